@@ -18,6 +18,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
+	estore "github.com/cortezaproject/corteza-server/pkg/envoy/store"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
 	"github.com/cortezaproject/corteza-server/pkg/http"
@@ -30,6 +31,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/provision"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/pkg/scheduler"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
 	"github.com/cortezaproject/corteza-server/pkg/seeder"
 	"github.com/cortezaproject/corteza-server/pkg/sentry"
 	"github.com/cortezaproject/corteza-server/pkg/websocket"
@@ -112,6 +114,12 @@ func (app *CortezaApp) Setup() (err error) {
 
 	auth.SetupDefault(app.Opt.Auth.Secret, app.Opt.Auth.Expiry)
 
+	if app.Opt.Auth.SigningKeys != "" {
+		if err = auth.SetupDefaultKeySet(strings.Split(app.Opt.Auth.SigningKeys, ",")...); err != nil {
+			return fmt.Errorf("failed to load signing keys: %w", err)
+		}
+	}
+
 	mail.SetupDialer(
 		app.Opt.SMTP.Host,
 		app.Opt.SMTP.Port,
@@ -352,6 +360,13 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 	}
 
 	if app.Opt.Messagebus.Enabled {
+		// consumers resolve broker credentials from the secure storage
+		if c, err := secure.NewCipher(app.Opt.Apigw.SecretKey, app.Opt.Apigw.SecretKeyPrevious); err != nil {
+			app.Log.Warn("could not initialize secure storage, messagebus secrets will not be resolved", zap.Error(err))
+		} else {
+			messagebus.Service().SetSecureStorage(secure.NewStorage(app.Store, c))
+		}
+
 		// initialize all the queue handlers
 		messagebus.Service().Init(ctx, app.Store)
 	}
@@ -361,17 +376,23 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 	// Note: this is a legacy approach, all services from all 3 apps
 	// will most likely be merged in the future
 	err = sysService.Initialize(ctx, app.Log, app.Store, app.WsServer, sysService.Config{
-		ActionLog: app.Opt.ActionLog,
-		Storage:   app.Opt.ObjStore,
-		Template:  app.Opt.Template,
-		Auth:      app.Opt.Auth,
-		RBAC:      app.Opt.RBAC,
+		ActionLog:   app.Opt.ActionLog,
+		Storage:     app.Opt.ObjStore,
+		Template:    app.Opt.Template,
+		Auth:        app.Opt.Auth,
+		RBAC:        app.Opt.RBAC,
+		Apigw:       app.Opt.Apigw,
+		Environment: app.Opt.Environment,
+		Report:      app.Opt.Report,
 	})
 
 	if err != nil {
 		return
 	}
 
+	// personal access tokens are verified by the auth service
+	auth.DefaultPersonalAccessTokenAuthenticator = sysService.DefaultAuth
+
 	// Initializes automation services
 	//
 	// Note: this is a legacy approach, all services from all 3 apps
@@ -386,6 +407,8 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		return
 	}
 
+	sysService.DefaultReport.RegisterReporter("automationSessions", autService.DefaultSession)
+
 	// Initializes compose services
 	//
 	// Note: this is a legacy approach, all services from all 3 apps
@@ -399,6 +422,9 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		return
 	}
 
+	cmpService.DefaultNamespace.SetCloneStore(estore.NamespaceCloneStore())
+	sysService.DefaultResourceTransfer.SetTransferStore(estore.ResourceTransferStore())
+
 	corredor.Service().SetUserFinder(sysService.DefaultUser)
 	corredor.Service().SetRoleFinder(sysService.DefaultRole)
 
@@ -547,6 +573,9 @@ func updateAuthSettings(svc authServicer, current *types.AppSettings) {
 		PasswordCreateEnabled:     current.Auth.Internal.PasswordCreate.Enabled,
 		SplitCredentialsCheck:     current.Auth.Internal.SplitCredentialsCheck,
 		ExternalEnabled:           current.Auth.External.Enabled,
+		LDAP: authSettings.LDAP{
+			Enabled: current.Auth.LDAP.Enabled,
+		},
 		MultiFactor: authSettings.MultiFactor{
 			TOTP: authSettings.TOTP{
 				Enabled:  current.Auth.MultiFactor.TOTP.Enabled,
@@ -557,6 +586,17 @@ func updateAuthSettings(svc authServicer, current *types.AppSettings) {
 				Enabled:  current.Auth.MultiFactor.EmailOTP.Enabled,
 				Enforced: current.Auth.MultiFactor.EmailOTP.Enforced,
 			},
+			WebAuthn: authSettings.WebAuthn{
+				Enabled:          current.Auth.MultiFactor.WebAuthn.Enabled,
+				Enforced:         current.Auth.MultiFactor.WebAuthn.Enforced,
+				Passwordless:     current.Auth.MultiFactor.WebAuthn.Passwordless,
+				RelyingPartyID:   current.Auth.MultiFactor.WebAuthn.RelyingPartyID,
+				RelyingPartyName: current.Auth.MultiFactor.WebAuthn.RelyingPartyName,
+			},
+		},
+		PersonalAccessTokens: authSettings.PersonalAccessTokens{
+			Enabled:     current.Auth.PersonalAccessTokens.Enabled,
+			MaxLifetime: current.Auth.PersonalAccessTokens.MaxLifetime,
 		},
 	}
 
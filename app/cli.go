@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	authCommands "github.com/cortezaproject/corteza-server/auth/commands"
+	composeCommands "github.com/cortezaproject/corteza-server/compose/commands"
 	federationCommands "github.com/cortezaproject/corteza-server/federation/commands"
 	"github.com/cortezaproject/corteza-server/pkg/cli"
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -83,6 +84,7 @@ func (app *CortezaApp) InitCLI() {
 		systemCommands.Settings(ctx, app),
 		systemCommands.Import(ctx, storeInit),
 		systemCommands.Export(ctx, storeInit),
+		composeCommands.Records(ctx, storeInit),
 		serveCmd,
 		upgradeCmd,
 		provisionCmd,
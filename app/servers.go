@@ -108,8 +108,17 @@ func (app *CortezaApp) mountHttpRoutes(r chi.Router) {
 
 			// API Gateway
 			{
-				apigw.Setup(options.Apigw(), service.DefaultLogger, service.DefaultStore)
+				apigw.Setup(&app.Opt.Apigw, service.DefaultLogger, service.DefaultStore)
 				r.Route("/", apigw.Service().Router)
+
+				// routes with CORS policy answer preflight requests on their own
+				server.PreflightPassthrough = func(req *http.Request) bool {
+					if !strings.HasPrefix(req.URL.Path, fullpathAPI) {
+						return false
+					}
+
+					return apigw.Service().HandlesPreflight("/" + strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, fullpathAPI), "/"))
+				}
 			}
 
 			var fullpathDocs = options.CleanBase(ho.BaseUrl, ho.ApiBaseUrl, "docs")
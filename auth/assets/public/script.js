@@ -11,4 +11,64 @@ $(function () {
   })
 
   $('input.mfa-code-mask').mask('000 000')
+
+  // Security key (WebAuthn) ceremonies
+  //
+  // Options are prepared by the server; binary values are exchanged
+  // as base64url strings and converted to/from ArrayBuffers here
+  function b64ToBuf (s) {
+    s = s.replace(/-/g, '+').replace(/_/g, '/')
+    return Uint8Array.from(atob(s + '==='.slice((s.length + 3) % 4)), function (c) {
+      return c.charCodeAt(0)
+    }).buffer
+  }
+
+  function bufToB64 (buf) {
+    return btoa(String.fromCharCode.apply(null, new Uint8Array(buf)))
+      .replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+  }
+
+  $('form[data-webauthn]').on('submit', function (e) {
+    let form = this
+
+    e.preventDefault()
+
+    let fail = function (err) {
+      $('.webauthn-error', form).text(err.message || err).removeClass('d-none')
+      setTimeout(function () {
+        $('button, input[type=submit]', form).attr('disabled', false)
+      }, 100)
+    }
+
+    if (!window.PublicKeyCredential) {
+      fail('Security keys are not supported by this browser')
+      return
+    }
+
+    let o = JSON.parse(form.dataset.webauthnOptions)
+    let ceremony
+
+    o.challenge = b64ToBuf(o.challenge)
+    if (form.dataset.webauthn === 'create') {
+      o.user.id = b64ToBuf(o.user.id)
+      o.excludeCredentials.forEach(function (c) { c.id = b64ToBuf(c.id) })
+      ceremony = navigator.credentials.create({ publicKey: o })
+    } else {
+      o.allowCredentials.forEach(function (c) { c.id = b64ToBuf(c.id) })
+      ceremony = navigator.credentials.get({ publicKey: o })
+    }
+
+    ceremony.then(function (c) {
+      let r = { id: bufToB64(c.rawId), type: c.type, response: {} }
+
+      for (let k of ['clientDataJSON', 'attestationObject', 'authenticatorData', 'signature', 'userHandle']) {
+        if (c.response[k]) {
+          r.response[k] = bufToB64(c.response[k])
+        }
+      }
+
+      form.elements.credential.value = JSON.stringify(r)
+      form.submit()
+    }).catch(fail)
+  })
 })
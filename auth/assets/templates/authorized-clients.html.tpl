@@ -35,6 +35,50 @@
 			<i>{{ tr "authorized-clients.template.list.empty" }}</i>
 		</div>
 	{{ end }}
+
+	{{ if .serviceClients }}
+		<h2 class="h5 p-3 border-top border-bottom mb-0">{{ tr "authorized-clients.template.service.title" }}</h2>
+		{{ range .serviceClients }}
+		<div class="p-3">
+			<div class="text-primary font-weight-bold">{{ .Name }}</div>
+			{{ range .Tokens }}
+			<div class="d-flex justify-content-between align-items-center mt-1">
+				<div>
+					{{ tr "authorized-clients.template.service.issued-on" }}
+					<time datetime="{{ .CreatedAt | date "2006-01-02T15:04:05Z07:00" }}">
+						{{ .CreatedAt | date "Mon, 02 Jan 2006 15:04 MST" }}
+					</time>
+					{{ if .RemoteAddr }}({{ .RemoteAddr }}){{ end }},
+					{{ tr "authorized-clients.template.service.expires-on" }}
+					<time datetime="{{ .ExpiresAt | date "2006-01-02T15:04:05Z07:00" }}">
+						{{ .ExpiresAt | date "Mon, 02 Jan 2006 15:04 MST" }}
+					</time>
+				</div>
+				<button
+					type="submit"
+					name="revoke-token"
+					value="{{ .ID }}"
+					class="btn btn-sm btn-outline-danger"
+				>
+					{{ tr "authorized-clients.template.service.buttons.revoke-token" }}
+				</button>
+			</div>
+			{{ else }}
+			<div><i>{{ tr "authorized-clients.template.service.no-tokens" }}</i></div>
+			{{ end }}
+			{{ if .Tokens }}
+			<button
+				type="submit"
+				name="revoke-tokens"
+				value="{{ .ID }}"
+				class="btn btn-sm btn-danger mt-2"
+			>
+				{{ tr "authorized-clients.template.service.buttons.revoke-all" }}
+			</button>
+			{{ end }}
+		</div>
+		{{ end }}
+	{{ end }}
 	</form>
 </div>
 {{ template "inc_footer.html.tpl" . }}
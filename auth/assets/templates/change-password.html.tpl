@@ -1,6 +1,13 @@
 {{ template "inc_header.html.tpl" set . "activeNav" "security" }}
 <div class="card-body p-0">
 	<h1 class="h4 card-title p-3 border-bottom">{{ tr "change-password.template.title" }}</h1>
+
+	{{ if .passwordChangeRequired }}
+	<p class="px-3 pt-3 text-danger mb-0 font-weight-bold">
+		{{ tr "change-password.template.change-required" }}
+	</p>
+	{{ end }}
+
 	<form
 		method="POST"
 		action="{{ links.ChangePassword }}"
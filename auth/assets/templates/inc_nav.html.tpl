@@ -29,6 +29,11 @@
 		<li class="nav-item {{ if eq $activeNav "clients" }}active{{ end  }}">
 			<a class="nav-link" href="{{ links.AuthorizedClients }}">{{ tr "inc_nav.template.class.authorized-clients" }}</a>
 		</li>
+		{{ if .settings.PersonalAccessTokens.Enabled }}
+		<li class="nav-item {{ if eq $activeNav "tokens" }}active{{ end  }}">
+			<a class="nav-link" href="{{ links.PersonalAccessTokens }}">{{ tr "inc_nav.template.class.personal-access-tokens" }}</a>
+		</li>
+		{{ end }}
 	</ul>
 	{{ end }}
 {{ end }}
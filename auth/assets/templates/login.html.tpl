@@ -1,7 +1,7 @@
 {{ template "inc_header.html.tpl" . }}
 <div class="card-body p-0">
 	<h1 class="h4 card-title p-3 border-bottom">{{ tr "login.template.title" }}</h1>
-	{{ if .settings.LocalEnabled }}
+	{{ if or .settings.LocalEnabled .settings.LDAP.Enabled }}
 	<form
 		method="POST"
 		action="{{ links.Login }}"
@@ -18,7 +18,7 @@
                 {{ tr "login.template.form.email.label" }} *
             </label>
 			<input
-				type="email"
+				type="{{ if .settings.LDAP.Enabled }}text{{ else }}email{{ end }}"
 				class="form-control"
 				name="email"
 				required
@@ -87,6 +87,28 @@
         {{ end }}
 	</div>
 	{{ end }}
+	{{ if .webAuthnOptions }}
+	<form
+		method="POST"
+		action="{{ links.LoginWebAuthn }}"
+		class="px-3"
+		data-webauthn="get"
+		data-webauthn-options="{{ .webAuthnOptions }}"
+	>
+		<div class="alert alert-danger d-none webauthn-error" role="alert"></div>
+
+		{{ .csrfField }}
+		<input type="hidden" name="credential">
+
+		<button
+			class="btn btn-light btn-block btn-lg mb-2 text-dark"
+			type="submit"
+		>
+			<i class="bi bi-key mr-1"></i>
+			{{ tr "login.template.webauthn.login" }}
+		</button>
+	</form>
+	{{ end }}
 	{{ if .settings.ExternalEnabled }}
 	<div class="p-3">
 	{{ range .providers }}
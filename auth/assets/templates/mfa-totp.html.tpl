@@ -2,6 +2,22 @@
 <div class="card-body p-0">
 	<h1 class="h4 card-title p-3 border-bottom">{{ tr "mfa-totp.template.title" }}</h1>
 
+	{{ if .recoveryCodes }}
+	<div class="p-3">
+		<p>{{ tr "mfa-totp.template.recovery-codes.instructions" }}</p>
+		<p class="text-danger font-weight-bold">{{ tr "mfa-totp.template.recovery-codes.warning" }}</p>
+
+		<ul class="list-unstyled text-center h5">
+			{{ range .recoveryCodes }}
+			<li><pre class="mb-1">{{ . }}</pre></li>
+			{{ end }}
+		</ul>
+
+		<a href="{{ links.Security }}" class="btn btn-primary btn-block btn-lg">
+			{{ tr "mfa-totp.template.recovery-codes.continue" }}
+		</a>
+	</div>
+	{{ else }}
 	{{ if .enforced }}
 	<p class="p-3 text-danger mb-0 font-weight-bold">
 		{{ tr "mfa-totp.template.enforced" }}
@@ -72,5 +88,6 @@
 			</div>
 		</div>
 	</div>
+	{{ end }}
 </div>
 {{ template "inc_footer.html.tpl" . }}
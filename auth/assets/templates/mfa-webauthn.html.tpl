@@ -0,0 +1,54 @@
+{{ template "inc_header.html.tpl" set . "hideNav" true }}
+<div class="card-body p-0">
+	<h1 class="h4 card-title p-3 border-bottom">{{ tr "mfa-webauthn.template.title" }}</h1>
+
+	{{ if .enforced }}
+	<p class="p-3 text-danger mb-0 font-weight-bold">
+		{{ tr "mfa-webauthn.template.enforced" }}
+	</p>
+	{{ end }}
+
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.MfaWebAuthnSetup }}"
+		data-webauthn="create"
+		data-webauthn-options="{{ .options }}"
+	>
+		<p>{{ tr "mfa-webauthn.template.instructions" }}</p>
+
+		{{ if .form.error }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.error }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger d-none webauthn-error" role="alert"></div>
+
+		{{ .csrfField }}
+		<input type="hidden" name="credential">
+
+		<div class="mb-3">
+			<label>
+				{{ tr "mfa-webauthn.template.form.label" }}
+			</label>
+			<input
+				type="text"
+				class="form-control"
+				name="label"
+				maxlength="64"
+				placeholder="{{ tr "mfa-webauthn.template.form.label-placeholder" }}"
+				autocomplete="off"
+				aria-label="{{ tr "mfa-webauthn.template.form.label" }}">
+		</div>
+
+		<button
+			class="btn btn-primary btn-block btn-lg"
+			type="submit"
+		>
+			<i class="bi bi-key mr-1"></i>
+			{{ tr "mfa-webauthn.template.form.button" }}
+		</button>
+	</form>
+</div>
+{{ template "inc_footer.html.tpl" . }}
@@ -97,10 +97,77 @@
 			{{ tr "mfa.template.email.verify" }}
 		</button>
 	</form>
+
+	<form
+		class="px-3 pb-3"
+		method="POST"
+		action="{{ links.Mfa }}"
+	>
+		<h6>{{ tr "mfa.template.recovery-code.instructions" }}</h6>
+
+		{{ .csrfField }}
+		<div class="input-group my-3">
+			<input
+				type="text"
+				required
+				class="form-control text-center"
+				name="code"
+				maxlength="11"
+				aria-required="true"
+				placeholder="xxxxx-xxxxx"
+				autocomplete="off"
+				aria-label="{{ tr "mfa.template.recovery-code.code" }}">
+		</div>
+
+		<button
+			class="btn btn-light btn-block"
+			type="submit"
+			name="action"
+			value="verifyRecoveryCode"
+		>
+			{{ tr "mfa.template.recovery-code.verify" }}
+		</button>
+	</form>
 	{{ else if not .totpDisabled }}
 		<p class="p-3 mb-0">
 			<i class="bi bi-check-circle text-success h5 mr-1"></i> {{ tr "mfa.template.totp.confirmed" }}
 		</p>
 	{{ end }}
+
+	{{ if .webAuthnPending }}
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.Mfa }}"
+		data-webauthn="get"
+		data-webauthn-options="{{ .webAuthnOptions }}"
+	>
+		<h5>{{ tr "mfa.template.webauthn.instructions" }}</h5>
+
+		{{ if .form.webAuthnError }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.webAuthnError }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger d-none webauthn-error" role="alert"></div>
+
+		{{ .csrfField }}
+		<input type="hidden" name="action" value="verifyWebAuthn">
+		<input type="hidden" name="credential">
+
+		<button
+			class="btn btn-primary btn-block btn-lg"
+			type="submit"
+		>
+			<i class="bi bi-key mr-1"></i>
+			{{ tr "mfa.template.webauthn.verify" }}
+		</button>
+	</form>
+	{{ else if not .webAuthnDisabled }}
+		<p class="p-3 mb-0">
+			<i class="bi bi-check-circle text-success h5 mr-1"></i> {{ tr "mfa.template.webauthn.confirmed" }}
+		</p>
+	{{ end }}
 </div>
 {{ template "inc_footer.html.tpl" . }}
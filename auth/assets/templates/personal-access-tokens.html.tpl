@@ -0,0 +1,129 @@
+{{ template "inc_header.html.tpl" set . "activeNav" "tokens" }}
+<div class="card-body p-0">
+	<h1 class="h4 card-title p-3 border-bottom">{{ tr "personal-access-tokens.template.title" }}</h1>
+
+	{{ if .newToken }}
+	<div class="p-3 border-bottom">
+		<p class="font-weight-bold mb-2">{{ tr "personal-access-tokens.template.new-token.title" }}</p>
+		<input
+			type="text"
+			class="form-control text-monospace"
+			readonly
+			value="{{ .newToken }}"
+			aria-label="{{ tr "personal-access-tokens.template.new-token.title" }}">
+		<small class="text-danger">{{ tr "personal-access-tokens.template.new-token.warning" }}</small>
+	</div>
+	{{ end }}
+
+	<form
+		method="POST"
+		action="{{ links.PersonalAccessTokens }}"
+		class="p-3 border-bottom"
+	>
+		{{ .csrfField }}
+		{{ if .form.error }}
+		<div class="text-danger font-weight-bold mb-3" role="alert">
+			{{ .form.error }}
+		</div>
+		{{ end }}
+		<div class="mb-3">
+			<label>{{ tr "personal-access-tokens.template.form.name.label" }}</label>
+			<input
+				type="text"
+				required
+				class="form-control"
+				name="name"
+				value="{{ .form.name }}"
+				placeholder="{{ tr "personal-access-tokens.template.form.name.placeholder" }}"
+				aria-label="{{ tr "personal-access-tokens.template.form.name.label" }}">
+		</div>
+		<div class="mb-3">
+			<label class="d-block">{{ tr "personal-access-tokens.template.form.scope.label" }}</label>
+			{{ range $scope := list "profile" "api" "api:system" "api:compose" "api:automation" "api:federation" }}
+			<div class="form-check form-check-inline">
+				<input
+					type="checkbox"
+					class="form-check-input"
+					name="scope"
+					id="scope-{{ $scope }}"
+					value="{{ $scope }}">
+				<label class="form-check-label" for="scope-{{ $scope }}">{{ $scope }}</label>
+			</div>
+			{{ end }}
+		</div>
+		<div class="mb-3">
+			<label>{{ tr "personal-access-tokens.template.form.expires-at.label" }}</label>
+			<input
+				type="date"
+				class="form-control"
+				name="expiresAt"
+				{{ if .settings.PersonalAccessTokens.MaxLifetime }}required{{ end }}
+				value="{{ .form.expiresAt }}"
+				aria-label="{{ tr "personal-access-tokens.template.form.expires-at.label" }}">
+			{{ if .settings.PersonalAccessTokens.MaxLifetime }}
+			<small class="text-muted">{{ tr "personal-access-tokens.template.form.expires-at.max-lifetime" "days" (print .settings.PersonalAccessTokens.MaxLifetime) }}</small>
+			{{ end }}
+		</div>
+		<button
+			type="submit"
+			name="action"
+			value="create"
+			class="btn btn-primary btn-block btn-lg"
+		>
+			{{ tr "personal-access-tokens.template.form.buttons.create" }}
+		</button>
+	</form>
+
+	<form
+		method="POST"
+		action="{{ links.PersonalAccessTokens }}"
+	>
+		{{ .csrfField }}
+		<input type="hidden" name="action" value="revoke">
+	{{ range .tokens }}
+		<div class="p-3 d-flex justify-content-between align-items-center">
+			<div>
+				<div class="text-primary font-weight-bold">{{ .Name }}</div>
+				<div class="small">{{ join " " .Scope }}</div>
+				<div class="small text-muted">
+					{{ tr "personal-access-tokens.template.list.created-on" }}
+					<time datetime="{{ .CreatedAt | date "2006-01-02T15:04:05Z07:00" }}">
+						{{ .CreatedAt | date "Mon, 02 Jan 2006 15:04 MST" }}
+					</time>
+					{{ if .LastUsedAt }},
+					{{ tr "personal-access-tokens.template.list.last-used" }}
+					<time datetime="{{ .LastUsedAt | date "2006-01-02T15:04:05Z07:00" }}">
+						{{ .LastUsedAt | date "Mon, 02 Jan 2006 15:04 MST" }}
+					</time>
+					{{ end }}
+				</div>
+				<div class="small text-muted">
+					{{ if .Expired }}
+					<span class="badge badge-warning">{{ tr "personal-access-tokens.template.list.expired" }}</span>
+					{{ else if .ExpiresAt }}
+					{{ tr "personal-access-tokens.template.list.expires-on" }}
+					<time datetime="{{ .ExpiresAt | date "2006-01-02T15:04:05Z07:00" }}">
+						{{ .ExpiresAt | date "Mon, 02 Jan 2006" }}
+					</time>
+					{{ else }}
+					{{ tr "personal-access-tokens.template.list.no-expiration" }}
+					{{ end }}
+				</div>
+			</div>
+			<button
+				type="submit"
+				name="tokenID"
+				value="{{ .ID }}"
+				class="btn btn-sm btn-danger"
+			>
+				{{ tr "personal-access-tokens.template.list.buttons.revoke" }}
+			</button>
+		</div>
+	{{ else }}
+		<div class="text-center m-3 mb-5">
+			<i>{{ tr "personal-access-tokens.template.list.empty" }}</i>
+		</div>
+	{{ end }}
+	</form>
+</div>
+{{ template "inc_footer.html.tpl" . }}
@@ -17,7 +17,7 @@
 	<div>
 		{{ .csrfField }}
 		<h5>{{ tr "security.template.mfa.title" }}</h5>
-		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled }}
+		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled .settings.MultiFactor.WebAuthn.Enabled }}
 			{{ if .settings.MultiFactor.TOTP.Enabled }}
 			<div class="py-4">
 				<h6>{{ tr "security.template.mfa.totp.title" }}</h6>
@@ -44,6 +44,21 @@
 						{{ end }}
 					</div>
 				</div>
+				{{ if .totpEnforced }}
+				<div class="row mt-3">
+					<div class="col-10 pt-2">
+						{{ if .recoveryCodesLeft }}
+						<i class="bi bi-check-circle text-success h5 mr-1"></i>
+						{{ else }}
+						<i class="bi bi-exclamation-circle-fill text-danger h5 mr-1"></i>
+						{{ end }}
+						{{ tr "security.template.mfa.totp.recovery-codes.left" "count" .recoveryCodesLeft }}
+					</div>
+					<div class="col-md-2 col-sm-12">
+						<button name="action" value="regenerateRecoveryCodes" class="btn btn-light float-right">{{ tr "security.template.mfa.totp.recovery-codes.regenerate" }}</button>
+					</div>
+				</div>
+				{{ end }}
 			</div>
 			{{ end }}
 
@@ -76,6 +91,42 @@
 				</div>
 			</div>
 			{{ end }}
+
+			{{ if .settings.MultiFactor.WebAuthn.Enabled }}
+			<div class="py-4">
+				<h6>{{ tr "security.template.mfa.webauthn.title" }}</h6>
+				<div class="row">
+					<div class="col-10 pt-2">
+					{{ if .webAuthnEnforced }}
+						<i class="bi bi-check-circle text-success h5 mr-1"></i>
+						{{ tr "security.template.mfa.webauthn.enforced" }}
+					{{ else }}
+						<i class="bi bi-exclamation-circle-fill text-danger h5 mr-1"></i>
+						{{ tr "security.template.mfa.webauthn.disabled" }}
+					{{ end }}
+					</div>
+					<div class="col-md-2 col-sm-12">
+						<button name="action" value="configureWebAuthn" class="btn btn-primary float-right">{{ tr "security.template.mfa.webauthn.register" }}</button>
+					</div>
+				</div>
+				{{ range .webAuthnKeys }}
+				<div class="row mt-2">
+					<div class="col-10 pt-2">
+						<i class="bi bi-key mr-1"></i>
+						{{ .Label }}
+						{{ if .LastUsedAt }}
+						<small class="text-muted ml-2">{{ tr "security.template.mfa.webauthn.last-used" "at" (.LastUsedAt.Format "2006-01-02 15:04") }}</small>
+						{{ end }}
+					</div>
+					<div class="col-md-2 col-sm-12">
+						{{ if not (and $.settings.MultiFactor.WebAuthn.Enforced (eq (len $.webAuthnKeys) 1)) }}
+						<button name="action" value="removeWebAuthn" formaction="{{ links.Security }}?credentialsID={{ .ID }}" class="btn btn-danger float-right">{{ tr "security.template.mfa.webauthn.remove" }}</button>
+						{{ end }}
+					</div>
+				</div>
+				{{ end }}
+			</div>
+			{{ end }}
 		{{ else }}
 			<div class="mb-4 font-italic" role="alert">
 				{{ tr "security.template.mfa.all-disabled" }}
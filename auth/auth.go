@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -67,6 +68,16 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 		log = zap.NewNop()
 	}
 
+	if auth.DefaultKeySet == nil {
+		// no signing keys configured (see AUTH_SIGNING_KEYS)
+		if err = auth.SetupDefaultKeySet(); err != nil {
+			return nil, err
+		}
+
+		svc.log.Warn("using generated key for signing ID tokens, " +
+			"tokens can not be verified after restart or on other nodes (see AUTH_SIGNING_KEYS)")
+	}
+
 	sesManager := request.NewSessionManager(s, opt, log)
 
 	oauth2Manager := oauth2.NewManager(
@@ -132,7 +143,7 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 
 		// ensure all requested scopes are allowed on a client
 		for _, scope := range strings.Split(tgr.Scope, " ") {
-			if !auth.CheckScope(client.Scope, scope) {
+			if !auth.ScopeAllowed(client.Scope, scope) {
 				return false, fmt.Errorf("client does not allow use of '%s' scope", scope)
 			}
 		}
@@ -211,6 +222,21 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 		}
 	}
 
+	cs := &clientService{store: s, impersonateUsers: opt.ClientCredentialsImpersonateUsers}
+	if cc, err := cs.UserImpersonatingClients(ctx); err != nil {
+		log.Warn("could not check auth clients with client credentials grant", zap.Error(err))
+	} else {
+		for _, c := range cc {
+			log.Warn(
+				"auth client with client credentials grant impersonates user that is not a bot, "+
+					"change impersonated user to a bot",
+				zap.Uint64("clientID", c.ID),
+				zap.String("handle", c.Handle),
+				zap.Bool("AUTH_CLIENT_CREDENTIALS_IMPERSONATE_USERS", opt.ClientCredentialsImpersonateUsers),
+			)
+		}
+	}
+
 	svc.handlers = &handlers.AuthHandlers{
 		Locale:         locale.Global(),
 		Log:            log,
@@ -219,7 +245,7 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 		OAuth2:         oauth2Server,
 		AuthService:    systemService.DefaultAuth,
 		UserService:    systemService.DefaultUser,
-		ClientService:  &clientService{s},
+		ClientService:  cs,
 		TokenService:   &tokenService{s},
 		DefaultClient:  defClient,
 		Opt:            svc.opt,
@@ -240,8 +266,10 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 // LoadSamlService takes care of certificate preloading, fetching of the
 // IDP metadata once the auth settings are loaded and registers the
 // SAML middleware
-func (svc *service) LoadSamlService(ctx context.Context, s *settings.Settings) (srvc *saml.SamlSPService, err error) {
-	links := handlers.GetLinks()
+//
+// Each identity provider gets its own SAML service (with its own links)
+func (svc *service) LoadSamlService(ctx context.Context, s *settings.Settings, idp settings.SamlIDP) (srvc *saml.SamlSPService, err error) {
+	links := handlers.GetSamlLinks(idp.Handle)
 
 	certManager := saml.NewCertManager(&saml.CertStoreLoader{Storer: svc.store})
 
@@ -250,7 +278,7 @@ func (svc *service) LoadSamlService(ctx context.Context, s *settings.Settings) (
 		return
 	}
 
-	idpUrl, err := url.Parse(s.Saml.IDP.URL)
+	idpUrl, err := url.Parse(idp.URL)
 	if err != nil {
 		return
 	}
@@ -271,6 +299,8 @@ func (svc *service) LoadSamlService(ctx context.Context, s *settings.Settings) (
 	}
 
 	srvc, err = saml.NewSamlSPService(saml.SamlSPArgs{
+		Handle: idp.Handle,
+
 		AcsURL:  links.SamlCallback,
 		MetaURL: links.SamlMetadata,
 		SloURL:  links.SamlLogout,
@@ -282,10 +312,13 @@ func (svc *service) LoadSamlService(ctx context.Context, s *settings.Settings) (
 		IdpMeta: md,
 
 		IdentityPayload: saml.IdpIdentityPayload{
-			Name:       s.Saml.IDP.IdentName,
-			Handle:     s.Saml.IDP.IdentHandle,
-			Identifier: s.Saml.IDP.IdentIdentifier,
+			Name:       idp.IdentName,
+			Handle:     idp.IdentHandle,
+			Identifier: idp.IdentIdentifier,
 		},
+
+		RoleAttribute: idp.RoleAttribute,
+		RoleMapping:   samlRoleMapping(idp.RoleMapping),
 	})
 
 	return
@@ -320,33 +353,53 @@ func (svc *service) UpdateSettings(s *settings.Settings) {
 		svc.log.Debug("setting changed", zap.Any("mfa", s.MultiFactor))
 	}
 
-	if svc.settings.Saml != s.Saml {
+	if svc.settings.PersonalAccessTokens != s.PersonalAccessTokens {
+		svc.log.Debug("setting changed", zap.Any("personalAccessTokens", s.PersonalAccessTokens))
+	}
+
+	if !reflect.DeepEqual(svc.settings.Saml, s.Saml) {
 		var (
 			log = svc.log.Named("saml")
 			ss  *saml.SamlSPService
 			err error
+
+			// default identity provider (with an empty handle)
+			// and additional identity providers
+			idps = s.Saml.IDPs
+
+			// services by identity provider handle
+			services = make(map[string]*saml.SamlSPService)
 		)
 
+		if s.Saml.IDP.URL != "" {
+			idps = append([]settings.SamlIDP{s.Saml.IDP}, idps...)
+		}
+
 		switch true {
 		case s.Saml.Cert == "", s.Saml.Key == "":
 			log.Warn("certificate private/public keys empty (see 'auth.external.saml' settings)")
 			break
 
-		case s.Saml.IDP.URL == "":
+		case len(idps) == 0:
 			log.Warn("could not get IDP url (see 'auth.external.saml.idp')")
 			break
 
 		default:
-			ss, err = svc.LoadSamlService(context.Background(), s)
+			for _, idp := range idps {
+				ss, err = svc.LoadSamlService(context.Background(), s, idp)
 
-			if err != nil {
-				log.Warn("could not reload service", zap.Error(err))
+				if err != nil {
+					log.Warn("could not reload service", zap.String("idp", idp.Handle), zap.Error(err))
+					continue
+				}
+
+				services[idp.Handle] = ss
 			}
 		}
 
-		if ss != nil {
+		if len(services) > 0 {
 			log.Debug("settings changed, reloading")
-			svc.handlers.SamlSPService = *ss
+			svc.handlers.SamlSPServices = services
 		}
 	}
 
@@ -438,18 +491,10 @@ func dirCheck(path string) (err error) {
 	return
 }
 
-//func (svc service) WellKnownOpenIDConfiguration() http.HandlerFunc {
-//	return func(w http.ResponseWriter, r *http.Request) {
-//		json.NewEncoder(w).Encode(map[string]interface{}{
-//			"issuer":                                svc.opt.BaseURL,
-//			"authorization_endpoint":                svc.opt.BaseURL + "/oauth2/authorize",
-//			"token_endpoint":                        svc.opt.BaseURL + "/oauth2/token",
-//			"jwks_uri":                              svc.opt.BaseURL + "/oauth2/public-keys", // @todo
-//			"subject_types_supported":               []string{"public"},
-//			"response_types_supported":              []string{"public"},
-//			"id_token_signing_alg_values_supported": []string{"RS256", "HS512"},
-//		})
-//
-//		w.Header().Set("Content-Type", "application/json")
-//	}
-//}
+func samlRoleMapping(mm []settings.SamlRoleMapping) (out []saml.RoleMapping) {
+	for _, m := range mm {
+		out = append(out, saml.RoleMapping{Value: m.Value, Role: m.Role})
+	}
+
+	return
+}
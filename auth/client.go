@@ -2,8 +2,13 @@ package auth
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
+	oauth2def "github.com/go-oauth2/oauth2/v4"
 )
 
 type (
@@ -11,7 +16,13 @@ type (
 		store interface {
 			store.AuthClients
 			store.AuthConfirmedClients
+			store.Users
+			store.Roles
 		}
+
+		// allows client credentials grant for users that are not bots;
+		// kept for compatibility with clients created before bot users
+		impersonateUsers bool
 	}
 )
 
@@ -27,3 +38,81 @@ func (svc clientService) Confirmed(ctx context.Context, userID uint64) (types.Au
 func (svc clientService) Revoke(ctx context.Context, userID, clientID uint64) error {
 	return store.DeleteAuthConfirmedClientByUserIDClientID(ctx, svc.store, userID, clientID)
 }
+
+// ServiceClients returns all enabled clients with client credentials grant
+func (svc clientService) ServiceClients(ctx context.Context) (types.AuthClientSet, error) {
+	set, _, err := store.SearchAuthClients(ctx, svc.store, types.AuthClientFilter{
+		Deleted: filter.StateExcluded,
+		Check: func(c *types.AuthClient) (bool, error) {
+			return c.Enabled && c.ValidGrant == oauth2def.ClientCredentials.String(), nil
+		},
+	})
+
+	return set, err
+}
+
+// ImpersonatedUser loads bot user (with roles) that is impersonated by the client
+// when client credentials grant is used
+//
+// Ordinary users can be impersonated only when
+// AUTH_CLIENT_CREDENTIALS_IMPERSONATE_USERS is enabled
+func (svc clientService) ImpersonatedUser(ctx context.Context, c *types.AuthClient) (*types.User, error) {
+	if c.Security == nil || c.Security.ImpersonateUser == 0 {
+		return nil, fmt.Errorf("client does not impersonate any user")
+	}
+
+	u, err := store.LookupUserByID(ctx, svc.store, c.Security.ImpersonateUser)
+	if err != nil {
+		return nil, fmt.Errorf("could not load impersonated user: %w", err)
+	}
+
+	switch {
+	case u.Kind == types.NormalUser && svc.impersonateUsers:
+		// allowed for compatibility
+	case u.Kind != types.BotUser:
+		return nil, fmt.Errorf("impersonated user is not a bot")
+	}
+
+	switch {
+	case u.DeletedAt != nil, u.SuspendedAt != nil:
+		return nil, fmt.Errorf("impersonated user is deleted or suspended")
+	}
+
+	rr, _, err := store.SearchRoles(ctx, svc.store, types.RoleFilter{MemberID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	u.SetRoles(rr.IDs()...)
+	return u, nil
+}
+
+// UserImpersonatingClients returns enabled clients with client credentials grant
+// that impersonate users that are not bots
+//
+// These clients are refused unless AUTH_CLIENT_CREDENTIALS_IMPERSONATE_USERS is enabled
+func (svc clientService) UserImpersonatingClients(ctx context.Context) (out types.AuthClientSet, err error) {
+	cc, err := svc.ServiceClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cc {
+		if c.Security == nil || c.Security.ImpersonateUser == 0 {
+			continue
+		}
+
+		u, err := store.LookupUserByID(ctx, svc.store, c.Security.ImpersonateUser)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if u.Kind != types.BotUser {
+			out = append(out, c)
+		}
+	}
+
+	return out, nil
+}
@@ -17,6 +17,12 @@ type (
 
 	externalSamlAuthHandler struct {
 		service saml.SamlSPService
+
+		// NameID, session index and attributes
+		// from the assertion of the completed authentication
+		nameID       string
+		sessionIndex string
+		attributes   samlsp.Attributes
 	}
 
 	externalDefaultAuthHandler struct{}
@@ -58,7 +64,7 @@ func (eh *externalSamlAuthHandler) CompleteUserAuth(w http.ResponseWriter, r *ht
 		}
 
 		u = &types.ExternalAuthUser{}
-		u.Provider = "saml"
+		u.Provider = eh.service.Provider()
 
 		// get identifier for use with Corteza (email)
 		u.Email = eh.service.GuessIdentifier(sess.Attributes)
@@ -73,12 +79,38 @@ func (eh *externalSamlAuthHandler) CompleteUserAuth(w http.ResponseWriter, r *ht
 			u.NickName = sess.Attributes.Get(eh.service.IDPUserMeta.Handle)
 		}
 
-		u.UserID = sess.Attributes.Get("SessionIndex")
+		// NameID identifies the user on the identity provider
+		u.UserID = sess.StandardClaims.Subject
+		if u.UserID == "" {
+			u.UserID = sess.Attributes.Get("SessionIndex")
+		}
+
+		eh.nameID = sess.StandardClaims.Subject
+		eh.sessionIndex = sess.Attributes.Get("SessionIndex")
+		eh.attributes = sess.Attributes
 	}
 
 	return
 }
 
+// Attributes returns attributes from the assertion
+// (available after the authentication is completed)
+func (eh *externalSamlAuthHandler) Attributes() map[string][]string {
+	return eh.attributes
+}
+
+// NameID returns user's NameID on the identity provider
+// (available after the authentication is completed)
+func (eh *externalSamlAuthHandler) NameID() string {
+	return eh.nameID
+}
+
+// SessionIndex returns index of the session on the identity provider
+// (available after the authentication is completed)
+func (eh *externalSamlAuthHandler) SessionIndex() string {
+	return eh.sessionIndex
+}
+
 func NewSamlExternalHandler(s saml.SamlSPService) *externalSamlAuthHandler {
 	return &externalSamlAuthHandler{
 		service: s,
@@ -19,6 +19,13 @@ type (
 		Description string
 		ConfirmedAt time.Time
 	}
+
+	// client with client credentials grant and its tokens
+	serviceClient struct {
+		ID     uint64
+		Name   string
+		Tokens types.AuthOa2tokenSet
+	}
 )
 
 // Will sort clients - in order of creation
@@ -41,6 +48,10 @@ func (h *AuthHandlers) clientsView(req *request.AuthReq) error {
 	sort.Sort(ss)
 	req.Data["authorizedClients"] = ss
 
+	if req.Data["serviceClients"], err = h.getServiceClients(req); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -65,6 +76,37 @@ func (h *AuthHandlers) clientsProc(req *request.AuthReq) error {
 			Type: "primary",
 			Text: t("authorized-clients.alerts.removed"),
 		})
+
+	case len(req.Request.PostFormValue("revoke-token")) > 0,
+		len(req.Request.PostFormValue("revoke-tokens")) > 0:
+		var (
+			tokenID, _  = strconv.ParseUint(req.Request.PostFormValue("revoke-token"), 10, 64)
+			clientID, _ = strconv.ParseUint(req.Request.PostFormValue("revoke-tokens"), 10, 64)
+		)
+
+		// only tokens of the service clients that user can manage can be revoked
+		cc, err := h.getServiceClients(req)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range cc {
+			for _, t := range c.Tokens {
+				if t.ID != tokenID && c.ID != clientID {
+					continue
+				}
+
+				if err = h.TokenService.DeleteByID(req.Context(), t.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		t := translator(req, "auth")
+		req.NewAlerts = append(req.NewAlerts, request.Alert{
+			Type: "primary",
+			Text: t("authorized-clients.alerts.tokens-revoked"),
+		})
 	}
 
 	req.RedirectTo = GetLinks().AuthorizedClients
@@ -107,3 +149,40 @@ func (h *AuthHandlers) getAuthorizedClients(req *request.AuthReq) (ss authClient
 
 	return
 }
+
+// returns service clients (with client credentials grant) that user can manage
+// with all tokens issued to them
+func (h *AuthHandlers) getServiceClients(req *request.AuthReq) (ss []serviceClient, err error) {
+	var (
+		ctx = req.Context()
+		cc  types.AuthClientSet
+		tt  types.AuthOa2tokenSet
+	)
+
+	if cc, err = h.ClientService.ServiceClients(ctx); err != nil {
+		return
+	}
+
+	ss = make([]serviceClient, 0, len(cc))
+	for _, c := range cc {
+		if c.Security == nil || c.Security.ImpersonateUser == 0 || !h.canManageClient(ctx, c) {
+			continue
+		}
+
+		// tokens are issued to the impersonated user
+		if tt, err = h.TokenService.SearchByUserID(ctx, c.Security.ImpersonateUser); err != nil {
+			return
+		}
+
+		sc := serviceClient{ID: c.ID, Name: c.String()}
+		for _, t := range tt {
+			if t.ClientID == c.ID {
+				sc.Tokens = append(sc.Tokens, t)
+			}
+		}
+
+		ss = append(ss, sc)
+	}
+
+	return
+}
@@ -11,6 +11,7 @@ func (h *AuthHandlers) changePasswordForm(req *request.AuthReq) error {
 	h.Log.Debug("showing password change form")
 	req.Template = TmplChangePassword
 	req.Data["form"] = req.PopKV()
+	req.Data["passwordChangeRequired"] = req.AuthUser.PasswordChangeRequired
 	return nil
 }
 
@@ -31,12 +32,22 @@ func (h *AuthHandlers) changePasswordProc(req *request.AuthReq) (err error) {
 		})
 
 		req.RedirectTo = GetLinks().Profile
+
+		if req.AuthUser.PasswordChangeRequired {
+			// password changed, continue where user was before
+			req.AuthUser.PasswordChangeRequired = false
+			req.AuthUser.Save(req.Session)
+			handleSuccessfulAuth(req)
+		}
+
 		return nil
 	}
 
 	switch {
 	case service.AuthErrInternalLoginDisabledByConfig().Is(err),
 		service.AuthErrPasswordNotSecure().Is(err),
+		service.AuthErrPasswordReused().Is(err),
+		service.AuthErrPasswordBreached().Is(err),
 		service.AuthErrPasswordChangeFailedForUnknownUser().Is(err),
 		service.AuthErrPasswodResetFailedOldPasswordCheckFailed().Is(err):
 		req.SetKV(map[string]string{
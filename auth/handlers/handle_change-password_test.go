@@ -107,6 +107,18 @@ func Test_changePasswordProc(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "password was used recently",
+			payload: map[string]string{"error": "provided password was used recently; choose a different password"},
+			link:    GetLinks().ChangePassword,
+			fn: func(_ *settings.Settings) {
+				authService = &authServiceMocked{
+					changePassword: func(ctx context.Context, userID uint64, oldPassword, newPassword string) (err error) {
+						return service.AuthErrPasswordReused()
+					},
+				}
+			},
+		},
 		{
 			name:    "password change failed for unknown user",
 			payload: map[string]string{"error": "failed to change password for the unknown user"},
@@ -147,3 +159,34 @@ func Test_changePasswordProc(t *testing.T) {
 		})
 	}
 }
+
+func Test_changePasswordProcRequired(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{PostForm: url.Values{}}
+
+		authService = &authServiceMocked{
+			changePassword: func(ctx context.Context, userID uint64, oldPassword, newPassword string) (err error) {
+				return nil
+			},
+		}
+
+		rq = require.New(t)
+	)
+
+	service.CurrentSettings = &types.AppSettings{}
+
+	authHandlers := prepareClientAuthHandlers(authService, &settings.Settings{})
+	authReq := prepareClientAuthReq(authHandlers, req, user)
+	authReq.AuthUser.CompleteEmailOTP()
+	authReq.AuthUser.PasswordChangeRequired = true
+
+	// everything else is off limits until password is changed
+	rq.NoError(authOnly(authHandlers.profileForm)(authReq))
+	rq.Equal(GetLinks().ChangePassword, authReq.RedirectTo)
+
+	rq.NoError(authHandlers.changePasswordProc(authReq))
+	rq.False(authReq.AuthUser.PasswordChangeRequired)
+	rq.Equal(GetLinks().Profile, authReq.RedirectTo)
+}
@@ -81,6 +81,15 @@ func (h *AuthHandlers) createPasswordProc(req *request.AuthReq) (err error) {
 		h.Log.Warn("handled error", zap.Error(err))
 		return nil
 
+	case service.AuthErrPasswordNotSecure().Is(err),
+		service.AuthErrPasswordReused().Is(err),
+		service.AuthErrPasswordBreached().Is(err):
+		req.SetKV(map[string]string{"error": err.Error()})
+		req.RedirectTo = createPasswordLink
+
+		h.Log.Warn("handled error", zap.Error(err))
+		return nil
+
 	default:
 		h.Log.Error("unhandled error", zap.Error(err))
 		return err
@@ -33,14 +33,17 @@ func (h AuthHandlers) externalCallback(w http.ResponseWriter, r *http.Request) {
 		h.Log.Error("failed to complete user auth", zap.Error(err))
 		h.handleFailedExternalAuth(w, r, err)
 	} else {
-		h.handleSuccessfulExternalAuth(w, r, *user)
+		h.handleSuccessfulExternalAuth(w, r, *user, nil)
 	}
 }
 
 // Handles authentication via external auth providers of
 // unknown an user + appending authentication on external providers
 // to a current user
-func (h AuthHandlers) handleSuccessfulExternalAuth(w http.ResponseWriter, r *http.Request, cred types.ExternalAuthUser) {
+//
+// Optional onLogin fn is called after the user is logged-in
+// and before the session is saved
+func (h AuthHandlers) handleSuccessfulExternalAuth(w http.ResponseWriter, r *http.Request, cred types.ExternalAuthUser, onLogin func(req *request.AuthReq, user *types.User) error) {
 	var (
 		user *types.User
 		err  error
@@ -56,6 +59,12 @@ func (h AuthHandlers) handleSuccessfulExternalAuth(w http.ResponseWriter, r *htt
 	}
 
 	h.handle(func(req *request.AuthReq) error {
+		if onLogin != nil {
+			if err = onLogin(req, user); err != nil {
+				return err
+			}
+		}
+
 		req.AuthUser = request.NewAuthUser(
 			h.Settings,
 			user,
@@ -32,6 +32,19 @@ func (h *AuthHandlers) loginForm(req *request.AuthReq) error {
 	}
 
 	req.Data["form"] = kv
+
+	if h.Settings.MultiFactor.WebAuthn.Enabled && h.Settings.MultiFactor.WebAuthn.Passwordless {
+		var err error
+		req.Data["webAuthnOptions"], err = h.webAuthnOptions(req, func(challenge []byte) (interface{}, error) {
+			o, err := h.AuthService.WebAuthnAssertionOptions(req.Context(), h.passwordlessRelyingParty(), challenge)
+			return o, err
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -73,7 +86,7 @@ func (h *AuthHandlers) loginProc(req *request.AuthReq) (err error) {
 			return
 		}
 
-		user, err = h.AuthService.InternalLogin(req.Context(), email, password)
+		user, err = h.login(req, email, password)
 
 		if err != nil {
 			return
@@ -90,6 +103,12 @@ func (h *AuthHandlers) loginProc(req *request.AuthReq) (err error) {
 
 		req.AuthUser = request.NewAuthUser(h.Settings, user, isPerm, lifetime)
 
+		// password expired or change was requested
+		req.AuthUser.PasswordChangeRequired, err = h.AuthService.PasswordChangeRequired(req.Context(), user)
+		if err != nil {
+			return
+		}
+
 		req.AuthUser.Save(req.Session)
 
 		h.Log.Info(
@@ -122,7 +141,11 @@ func (h *AuthHandlers) loginProc(req *request.AuthReq) (err error) {
 		return nil
 	case service.AuthErrInvalidEmailFormat().Is(err),
 		service.AuthErrInvalidCredentials().Is(err),
-		service.AuthErrCredentialsLinkedToInvalidUser().Is(err):
+		service.AuthErrCredentialsLinkedToInvalidUser().Is(err),
+		service.AuthErrLoginThrottled().Is(err),
+		service.AuthErrLdapUnavailable().Is(err),
+		service.AuthErrLdapEntryWithoutValidEmail().Is(err),
+		service.AuthErrLdapExistingUser().Is(err):
 		req.SetKV(map[string]string{
 			"error": err.Error(),
 			"email": email,
@@ -137,6 +160,51 @@ func (h *AuthHandlers) loginProc(req *request.AuthReq) (err error) {
 	}
 }
 
+// login with LDAP (when enabled) and internal credentials
+//
+// LDAP is tried first; when user can not be authenticated with LDAP
+// or LDAP server is not available, internal login is used as a fallback (local accounts, admins)
+func (h *AuthHandlers) login(req *request.AuthReq, username, password string) (user *types.User, err error) {
+	var (
+		ldapErr error
+	)
+
+	if h.Settings.LDAP.Enabled {
+		if user, ldapErr = h.AuthService.LDAPLogin(req.Context(), username, password); ldapErr == nil {
+			return
+		}
+
+		fallback := service.AuthErrInvalidCredentials().Is(ldapErr) ||
+			service.AuthErrLdapUnavailable().Is(ldapErr) ||
+			service.AuthErrLdapExistingUser().Is(ldapErr)
+
+		if !h.Settings.LocalEnabled || !fallback {
+			// no fallback when local login is disabled
+			// or when LDAP failed for some other reason
+			return nil, ldapErr
+		}
+	}
+
+	user, err = h.AuthService.InternalLogin(req.Context(), username, password)
+	if ldapErr != nil && (service.AuthErrInvalidEmailFormat().Is(err) || service.AuthErrInvalidCredentials().Is(err)) {
+		// username is not an email or not a local user, report LDAP error
+		return nil, ldapErr
+	}
+
+	return
+}
+
+func (h *AuthHandlers) onlyIfLocalOrLDAPEnabled(fn handlerFn) handlerFn {
+	return func(req *request.AuthReq) error {
+		if !h.Settings.LocalEnabled && !h.Settings.LDAP.Enabled {
+			h.localDisabledAlert(req)
+			return nil
+		}
+
+		return fn(req)
+	}
+}
+
 func (h *AuthHandlers) onlyIfLocalEnabled(fn handlerFn) handlerFn {
 	return func(req *request.AuthReq) error {
 		if !h.Settings.LocalEnabled {
@@ -85,6 +85,25 @@ func Test_loginProc(t *testing.T) {
 						err = nil
 						return
 					},
+					passwordChangeRequired: func(ctx context.Context, u *types.User) (bool, error) {
+						return false, nil
+					},
+				}
+			},
+		},
+		{
+			name:    "successful login with expired password",
+			payload: map[string]string(nil),
+			alerts:  []request.Alert{{Type: "primary", Text: "login.alerts.logged-in", Html: ""}},
+			link:    GetLinks().ChangePassword,
+			fn: func(_ *settings.Settings) {
+				authService = &authServiceMocked{
+					internalLogin: func(ctx context.Context, email, password string) (u *types.User, err error) {
+						return &types.User{Meta: &types.UserMeta{}}, nil
+					},
+					passwordChangeRequired: func(ctx context.Context, u *types.User) (bool, error) {
+						return true, nil
+					},
 				}
 			},
 		},
@@ -150,6 +169,107 @@ func Test_loginProc(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "throttled login",
+			payload: map[string]string{"email": "mockuser@example.tld", "error": "too many failed login attempts, try again later"},
+			alerts:  []request.Alert(nil),
+			link:    GetLinks().Login,
+			fn: func(*settings.Settings) {
+				req.PostForm.Add("email", "mockuser@example.tld")
+
+				authService = &authServiceMocked{
+					internalLogin: func(ctx context.Context, email, password string) (u *types.User, err error) {
+						err = service.AuthErrLoginThrottled()
+						return
+					},
+				}
+			},
+		},
+		{
+			name:    "successful LDAP login",
+			payload: map[string]string(nil),
+			alerts:  []request.Alert{{Type: "primary", Text: "login.alerts.logged-in", Html: ""}},
+			link:    GetLinks().Profile,
+			fn: func(authSettings *settings.Settings) {
+				authSettings.LDAP.Enabled = true
+
+				authService = &authServiceMocked{
+					ldapLogin: func(ctx context.Context, username, password string) (u *types.User, err error) {
+						return &types.User{Meta: &types.UserMeta{}}, nil
+					},
+					passwordChangeRequired: func(ctx context.Context, u *types.User) (bool, error) {
+						return false, nil
+					},
+				}
+			},
+		},
+		{
+			name:    "invalid LDAP credentials with internal login fallback",
+			payload: map[string]string{"email": "mockuser", "error": "invalid username and password combination"},
+			alerts:  []request.Alert(nil),
+			link:    GetLinks().Login,
+			fn: func(authSettings *settings.Settings) {
+				req.PostForm.Add("email", "mockuser")
+				authSettings.LDAP.Enabled = true
+				authSettings.LocalEnabled = true
+
+				authService = &authServiceMocked{
+					ldapLogin: func(ctx context.Context, username, password string) (u *types.User, err error) {
+						err = service.AuthErrInvalidCredentials()
+						return
+					},
+					internalLogin: func(ctx context.Context, email, password string) (u *types.User, err error) {
+						err = service.AuthErrInvalidEmailFormat()
+						return
+					},
+				}
+			},
+		},
+		{
+			name:    "LDAP server unavailable",
+			payload: map[string]string{"email": "mockuser", "error": "LDAP server is not available"},
+			alerts:  []request.Alert(nil),
+			link:    GetLinks().Login,
+			fn: func(authSettings *settings.Settings) {
+				req.PostForm.Add("email", "mockuser")
+				authSettings.LDAP.Enabled = true
+				authSettings.LocalEnabled = true
+
+				authService = &authServiceMocked{
+					ldapLogin: func(ctx context.Context, username, password string) (u *types.User, err error) {
+						err = service.AuthErrLdapUnavailable()
+						return
+					},
+					internalLogin: func(ctx context.Context, email, password string) (u *types.User, err error) {
+						err = service.AuthErrInvalidCredentials()
+						return
+					},
+				}
+			},
+		},
+		{
+			name:    "LDAP server unavailable with internal login fallback",
+			payload: map[string]string(nil),
+			alerts:  []request.Alert{{Type: "primary", Text: "login.alerts.logged-in", Html: ""}},
+			link:    GetLinks().Profile,
+			fn: func(authSettings *settings.Settings) {
+				authSettings.LDAP.Enabled = true
+				authSettings.LocalEnabled = true
+
+				authService = &authServiceMocked{
+					ldapLogin: func(ctx context.Context, username, password string) (u *types.User, err error) {
+						err = service.AuthErrLdapUnavailable()
+						return
+					},
+					internalLogin: func(ctx context.Context, email, password string) (u *types.User, err error) {
+						return &types.User{Meta: &types.UserMeta{}}, nil
+					},
+					passwordChangeRequired: func(ctx context.Context, u *types.User) (bool, error) {
+						return false, nil
+					},
+				}
+			},
+		},
 		{
 			name:    "split credentials check",
 			payload: map[string]string{"email": "mockuser@example.tld"},
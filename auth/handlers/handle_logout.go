@@ -2,10 +2,22 @@ package handlers
 
 import (
 	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/saml"
 	"github.com/markbates/goth/gothic"
+	"go.uber.org/zap"
 )
 
 func (h *AuthHandlers) logoutProc(req *request.AuthReq) (err error) {
+	var (
+		// session on the SAML identity provider that this session is linked with
+		samlSession = request.GetSamlSession(req.Session)
+		ss          *saml.SamlSPService
+	)
+
+	if samlSession != nil {
+		ss = h.SamlSPServices[samlSession.IdP]
+	}
+
 	req.Session.Options.MaxAge = -1
 	if err = req.Session.Save(req.Request, req.Response); err != nil {
 		return
@@ -20,6 +32,18 @@ func (h *AuthHandlers) logoutProc(req *request.AuthReq) (err error) {
 	req.Client = nil
 	h.Log.Info("logout successful")
 
+	if ss != nil {
+		// SP-initiated single logout,
+		// identity provider responds to the SLO link
+		rURL, err := ss.LogoutRequestURL(samlSession.NameID, samlSession.SessionIndex, "")
+		if err != nil {
+			h.Log.Warn("could not create SAML logout request", zap.Error(err))
+		} else if rURL != nil {
+			req.RedirectTo = rURL.String()
+			return nil
+		}
+	}
+
 	req.Template = TmplLogout
 
 	if req.Request.FormValue("back") != "" {
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
+
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/system/service"
 )
 
 // Handles MFA TOTP configuration form
@@ -37,6 +41,26 @@ func (h AuthHandlers) mfaForm(req *request.AuthReq) (err error) {
 	req.Data["emailOtpPending"] = req.AuthUser.PendingEmailOTP()
 	req.Data["totpDisabled"] = req.AuthUser.DisabledTOTP()
 	req.Data["totpPending"] = req.AuthUser.PendingTOTP()
+	req.Data["webAuthnDisabled"] = req.AuthUser.DisabledWebAuthn()
+	req.Data["webAuthnPending"] = req.AuthUser.PendingWebAuthn()
+
+	if req.AuthUser.PendingWebAuthn() {
+		ctx := auth.SetIdentityToContext(req.Context(), req.AuthUser.User)
+
+		req.Data["webAuthnOptions"], err = h.webAuthnOptions(req, func(challenge []byte) (interface{}, error) {
+			o, err := h.AuthService.WebAuthnAssertionOptions(ctx, h.relyingParty(), challenge)
+			return o, err
+		})
+
+		if service.AuthErrUnconfiguredWebAuthn().Is(err) {
+			// security keys enforced on the user but none registered yet
+			req.RedirectTo = GetLinks().MfaWebAuthnSetup
+			return nil
+		}
+
+		return err
+	}
+
 	return nil
 }
 
@@ -77,6 +101,50 @@ func (h AuthHandlers) mfaProc(req *request.AuthReq) (err error) {
 
 		req.PushAlert(t("mfa.topt.valid"))
 		req.AuthUser.CompleteTOTP()
+
+	case "verifyRecoveryCode":
+		// recovery code can be used instead of TOTP
+		err = h.AuthService.ValidateRecoveryCode(
+			auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+			req.Request.PostFormValue("code"),
+		)
+
+		if err != nil {
+			req.SetKV(map[string]string{"totpError": err.Error()})
+			return nil
+		}
+
+		t := translator(req, "auth")
+
+		req.PushAlert(t("mfa.alerts.recovery-code-used"))
+		req.AuthUser.CompleteTOTP()
+
+	case "verifyWebAuthn":
+		var (
+			challenge []byte
+			r         = &webauthn.AssertionResponse{}
+		)
+
+		if challenge, err = popWebAuthnChallenge(req); err == nil {
+			if err = json.Unmarshal([]byte(req.Request.PostFormValue("credential")), r); err == nil {
+				err = h.AuthService.ValidateWebAuthn(
+					auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+					h.relyingParty(),
+					challenge,
+					r,
+				)
+			}
+		}
+
+		if err != nil {
+			req.SetKV(map[string]string{"webAuthnError": err.Error()})
+			return nil
+		}
+
+		t := translator(req, "auth")
+
+		req.PushAlert(t("mfa.webauthn.valid"))
+		req.AuthUser.CompleteWebAuthn()
 	}
 
 	// All required MFA's confirmed, proceed to profile
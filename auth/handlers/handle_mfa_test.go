@@ -8,6 +8,7 @@ import (
 
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/stretchr/testify/require"
@@ -59,6 +60,38 @@ func Test_mfaProc(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "TOTP: successful login with recovery code",
+			payload: map[string]string(nil),
+			alerts:  []request.Alert{{Type: "primary", Text: "mfa.alerts.recovery-code-used"}},
+			link:    GetLinks().Mfa,
+			fn: func(_ *settings.Settings) {
+				req.Form.Set("action", "verifyRecoveryCode")
+				req.PostForm.Add("code", "abcde-fghjk")
+
+				authService = &authServiceMocked{
+					validateRecoveryCode: func(ctx context.Context, code string) (err error) {
+						return nil
+					},
+				}
+			},
+		},
+		{
+			name:    "TOTP: invalid recovery code",
+			payload: map[string]string{"totpError": "invalid recovery code"},
+			alerts:  []request.Alert(nil),
+			link:    GetLinks().Mfa,
+			fn: func(_ *settings.Settings) {
+				req.Form.Set("action", "verifyRecoveryCode")
+				req.PostForm.Add("code", "abcde-fghjk")
+
+				authService = &authServiceMocked{
+					validateRecoveryCode: func(ctx context.Context, code string) (err error) {
+						return service.AuthErrInvalidRecoveryCode()
+					},
+				}
+			},
+		},
 		{
 			name:    "Email: disabled",
 			payload: map[string]string{"emailOtpError": "multi factor authentication with email OTP is disabled"},
@@ -210,3 +243,68 @@ func Test_mfaProc(t *testing.T) {
 		})
 	}
 }
+
+func Test_mfaProcWebAuthn(t *testing.T) {
+	var (
+		user = makeMockUser()
+	)
+
+	tcc := []struct {
+		name      string
+		challenge bool
+		payload   map[string]string
+		alerts    []request.Alert
+		validate  func(context.Context, webauthn.RelyingParty, []byte, *webauthn.AssertionResponse) error
+	}{
+		{
+			name:      "successful login",
+			challenge: true,
+			payload:   map[string]string(nil),
+			alerts:    []request.Alert{{Type: "primary", Text: "mfa.webauthn.valid"}},
+			validate: func(_ context.Context, _ webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) error {
+				if string(challenge) != "challenge" || string(r.ID) != "key" {
+					return service.AuthErrInvalidWebAuthn()
+				}
+
+				return nil
+			},
+		},
+		{
+			name:    "no challenge in session",
+			payload: map[string]string{"webAuthnError": "no security key challenge in session"},
+		},
+		{
+			name:      "invalid assertion",
+			challenge: true,
+			payload:   map[string]string{"webAuthnError": "security key verification failed"},
+			validate: func(context.Context, webauthn.RelyingParty, []byte, *webauthn.AssertionResponse) error {
+				return service.AuthErrInvalidWebAuthn()
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq  = require.New(t)
+				req = &http.Request{Form: url.Values{}, PostForm: url.Values{}}
+			)
+
+			req.Form.Set("action", "verifyWebAuthn")
+			req.PostForm.Set("credential", `{"id":"`+webauthn.Encode([]byte("key"))+`"}`)
+			user.Meta = &types.UserMeta{}
+
+			authHandlers := prepareClientAuthHandlers(&authServiceMocked{validateWebAuthn: tc.validate}, &settings.Settings{})
+			authReq := prepareClientAuthReq(authHandlers, req, user)
+
+			if tc.challenge {
+				authReq.Session.Values[webAuthnChallengeKey] = webauthn.Encode([]byte("challenge"))
+			}
+
+			rq.NoError(authHandlers.mfaProc(authReq))
+			rq.Equal(tc.payload, authReq.GetKV())
+			rq.Equal(tc.alerts, authReq.NewAlerts)
+			rq.NotContains(authReq.Session.Values, webAuthnChallengeKey)
+		})
+	}
+}
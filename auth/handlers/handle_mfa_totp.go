@@ -17,6 +17,10 @@ import (
 const (
 	// session key where the secret is kept between requests
 	totpSecretKey = "totpSecret"
+
+	// session key where freshly generated recovery codes
+	// are kept until they are shown to the user
+	recoveryCodesKey = "recoveryCodes"
 )
 
 // Handles MFA TOTP configuration form
@@ -32,6 +36,15 @@ func (h AuthHandlers) mfaTotpConfigForm(req *request.AuthReq) (err error) {
 		_, fresh = req.Request.URL.Query()["fresh"]
 	)
 
+	if codes, has := req.Session.Values[recoveryCodesKey]; has {
+		// recovery codes were just generated, show them (only once)
+		// instead of the configuration form
+		delete(req.Session.Values, recoveryCodesKey)
+		req.Data["recoveryCodes"] = codes
+		req.Template = TmplMfaTotp
+		return nil
+	}
+
 	if s, has := req.Session.Values[totpSecretKey]; has && !fresh {
 		// secret is already in the session and
 		// there's no explicit request to change it
@@ -83,8 +96,16 @@ func (h AuthHandlers) mfaTotpConfigProc(req *request.AuthReq) (err error) {
 		req.AuthUser.Save(req.Session)
 
 		h.Log.Info("TOTP code verified")
-		req.RedirectTo = GetLinks().Security
 		delete(req.Session.Values, totpSecretKey)
+
+		// TOTP is configured, recovery codes are shown on the next page load
+		codes, err := h.AuthService.GenerateRecoveryCodes(auth.SetIdentityToContext(req.Context(), user))
+		if err != nil {
+			return err
+		}
+
+		req.Session.Values[recoveryCodesKey] = codes
+		req.RedirectTo = GetLinks().MfaTotpNewSecret
 		return nil
 	}
 
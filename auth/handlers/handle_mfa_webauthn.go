@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// session key where the ceremony challenge is kept between requests
+	webAuthnChallengeKey = "webAuthnChallenge"
+)
+
+// Handles security key registration form
+//
+// Registration options are passed to the page where
+// browser asks the authenticator to create a new credential
+func (h AuthHandlers) mfaWebAuthnConfigForm(req *request.AuthReq) (err error) {
+	ctx := auth.SetIdentityToContext(req.Context(), req.AuthUser.User)
+
+	options, err := h.webAuthnOptions(req, func(challenge []byte) (interface{}, error) {
+		o, err := h.AuthService.WebAuthnRegistrationOptions(ctx, h.relyingParty(), challenge)
+		return o, err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	req.Data["options"] = options
+	req.Data["enforced"] = h.Settings.MultiFactor.WebAuthn.Enforced
+	req.Data["form"] = req.PopKV()
+	req.Template = TmplMfaWebAuthn
+	return nil
+}
+
+// Handles security key registration form processing
+func (h AuthHandlers) mfaWebAuthnConfigProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().MfaWebAuthnSetup
+	req.SetKV(nil)
+
+	var (
+		user *types.User
+		r    = &webauthn.AttestationResponse{}
+	)
+
+	challenge, err := popWebAuthnChallenge(req)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal([]byte(req.Request.PostFormValue("credential")), r); err != nil {
+		req.SetKV(map[string]string{"error": "Invalid security key response"})
+		return nil
+	}
+
+	user, err = h.AuthService.ConfigureWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		h.relyingParty(),
+		challenge,
+		r,
+		req.Request.PostFormValue("label"),
+	)
+
+	if err == nil {
+		t := translator(req, "auth")
+		req.NewAlerts = append(req.NewAlerts, request.Alert{
+			Type: "primary",
+			Text: t("mfa-webauthn.alerts.registered"),
+		})
+
+		// Make sure we update User's data in the session
+		req.AuthUser.User = user
+		req.AuthUser.CompleteWebAuthn()
+		req.AuthUser.Save(req.Session)
+
+		h.Log.Info("security key registered")
+		req.RedirectTo = GetLinks().Security
+		return nil
+	}
+
+	switch {
+	case service.AuthErrInvalidWebAuthn().Is(err):
+		req.SetKV(map[string]string{"error": err.Error()})
+		h.Log.Warn("handled error", zap.Error(err))
+		return nil
+
+	default:
+		h.Log.Error("unhandled error", zap.Error(err))
+		return err
+	}
+}
+
+// Handles passwordless login with security key
+//
+// Authentication options are prepared by the login form
+func (h *AuthHandlers) loginWebAuthnProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().Login
+	req.SetKV(nil)
+
+	var (
+		user *types.User
+		r    = &webauthn.AssertionResponse{}
+	)
+
+	err = func() (err error) {
+		challenge, err := popWebAuthnChallenge(req)
+		if err != nil {
+			return service.AuthErrInvalidWebAuthn().Wrap(err)
+		}
+
+		if err = json.Unmarshal([]byte(req.Request.PostFormValue("credential")), r); err != nil {
+			return service.AuthErrInvalidWebAuthn().Wrap(err)
+		}
+
+		if user, err = h.AuthService.WebAuthnLogin(req.Context(), h.passwordlessRelyingParty(), challenge, r); err != nil {
+			return
+		}
+
+		req.AuthUser = request.NewAuthUser(h.Settings, user, false, h.Opt.SessionLifetime)
+		req.AuthUser.CompleteWebAuthn()
+		req.AuthUser.Save(req.Session)
+
+		h.Log.Info(
+			"login with security key successful",
+			zap.Any("mfa", req.AuthUser.MFAStatus),
+		)
+
+		t := translator(req, "auth")
+		req.PushAlert(t("login.alerts.logged-in"))
+
+		if req.AuthUser.PendingEmailOTP() {
+			if err = h.AuthService.SendEmailOTP(auth.SetIdentityToContext(req.Context(), req.AuthUser.User)); err != nil {
+				return errors.Internal("could not send OTP via email, contact your administrator").Wrap(err)
+			}
+		}
+
+		handleSuccessfulAuth(req)
+		return
+	}()
+
+	switch {
+	case err == nil:
+		return nil
+
+	case service.AuthErrInvalidWebAuthn().Is(err),
+		service.AuthErrDisabledPasswordlessWebAuthn().Is(err),
+		service.AuthErrInvalidCredentials().Is(err):
+		req.SetKV(map[string]string{"error": err.Error()})
+		h.Log.Warn("handled error", zap.Error(err))
+		return nil
+
+	default:
+		h.Log.Error("unhandled error", zap.Error(err))
+		return err
+	}
+}
+
+// Generates new challenge, keeps it in the session and
+// returns JSON encoded ceremony options
+func (h AuthHandlers) webAuthnOptions(req *request.AuthReq, fn func([]byte) (interface{}, error)) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	o, err := fn(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	enc, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+
+	req.Session.Values[webAuthnChallengeKey] = webauthn.Encode(challenge)
+	return string(enc), nil
+}
+
+// Removes the challenge from the session; challenge can be used only once
+func popWebAuthnChallenge(req *request.AuthReq) ([]byte, error) {
+	c, has := req.Session.Values[webAuthnChallengeKey]
+	if !has {
+		return nil, fmt.Errorf("no security key challenge in session")
+	}
+
+	delete(req.Session.Values, webAuthnChallengeKey)
+	return webauthn.Decode(c.(string))
+}
+
+// Relying party (this server) as seen by the authenticator
+//
+// ID defaults to the host of the auth base URL
+func (h AuthHandlers) relyingParty() webauthn.RelyingParty {
+	var (
+		s  = h.Settings.MultiFactor.WebAuthn
+		rp = webauthn.RelyingParty{ID: s.RelyingPartyID, Name: s.RelyingPartyName}
+	)
+
+	if u, err := url.Parse(h.Opt.BaseURL); err == nil {
+		rp.Origin = u.Scheme + "://" + u.Host
+
+		if rp.ID == "" {
+			rp.ID = u.Hostname()
+		}
+	}
+
+	if rp.Name == "" {
+		rp.Name = "Corteza"
+	}
+
+	return rp
+}
+
+// Relying party for the passwordless login
+//
+// Security key replaces the password so the user must be verified (PIN, biometrics)
+func (h AuthHandlers) passwordlessRelyingParty() webauthn.RelyingParty {
+	rp := h.relyingParty()
+	rp.UserVerification = true
+	return rp
+}
@@ -50,6 +50,11 @@ func (h AuthHandlers) oauth2Authorize(req *request.AuthReq) (err error) {
 	// this way we work around the limitations we have with the oauth2 lib.
 	ctx = context.WithValue(req.Context(), &oauth2.ContextClientStore{}, client)
 
+	// nonce from OpenID Connect authentication request is stored with the authorization code
+	eti := request.GetExtraReqInfoFromContext(ctx)
+	eti.Nonce = req.Request.Form.Get("nonce")
+	ctx = context.WithValue(ctx, request.ExtraReqInfo{}, eti)
+
 	if client != nil {
 		// No client validation is done at this point;
 		// first, see if user is able to authenticate.
@@ -150,6 +155,11 @@ func (h AuthHandlers) canAuthorizeClient(ctx context.Context, c *types.AuthClien
 	return systemService.DefaultAccessControl.CanAuthorizeAuthClient(ctx, c)
 }
 
+// Verifies weather current user can manage (revoke tokens of) this client or not
+func (h AuthHandlers) canManageClient(ctx context.Context, c *types.AuthClient) bool {
+	return systemService.DefaultAccessControl.CanUpdateAuthClient(ctx, c)
+}
+
 func (h AuthHandlers) oauth2Token(req *request.AuthReq) (err error) {
 	// Cleanup
 	request.SetOauth2ClientAuthorized(req.Session, false)
@@ -372,15 +382,27 @@ func (h AuthHandlers) handleTokenRequest(req *request.AuthReq, client *types.Aut
 	if gt == oauth2def.ClientCredentials {
 		// Authenticated with client credentials!
 		//
-		// We'll use info from client security
-		if client.Security == nil || client.Security.ImpersonateUser == 0 {
-			return h.tokenError(w, errors.Internal("auth client security configuration invalid"))
+		// Token is issued for the bot user that client impersonates,
+		// user's roles are processed with client's security settings
+		u, err := h.ClientService.ImpersonatedUser(ctx, client)
+		if err != nil {
+			h.Log.Warn("client credentials grant refused", zap.Uint64("clientID", client.ID), zap.Error(err))
+			return h.tokenError(w, oauth2errors.ErrUnauthorizedClient)
+		}
+
+		tgr.UserID = oauth2.UserIDSerializer(u.ID, client.Security.ProcessRoles(u.Roles()...)...)
+
+		if tgr.Scope == "" {
+			// default to all scopes allowed on the client
+			tgr.Scope = client.Scope
 		}
+	}
 
-		tgr.UserID = strings.Join(append(
-			[]string{fmt.Sprintf("%d", client.Security.ImpersonateUser)},
-			client.Security.ForcedRoles...,
-		), " ")
+	var nonce string
+	if gt == oauth2def.AuthorizationCode {
+		// code is removed when exchanged for the access token
+		// so nonce needs to be fetched before that
+		nonce, _ = h.TokenService.NonceByCode(ctx, tgr.Code)
 	}
 
 	ti, err := h.OAuth2.GetAccessToken(ctx, gt, tgr)
@@ -388,7 +410,15 @@ func (h AuthHandlers) handleTokenRequest(req *request.AuthReq, client *types.Aut
 		return h.tokenError(w, err)
 	}
 
-	return token(w, h.OAuth2.GetTokenData(ti), nil)
+	data := h.OAuth2.GetTokenData(ti)
+
+	if gt != oauth2def.ClientCredentials && auth.CheckScope(ti.GetScope(), "openid") {
+		if data["id_token"], err = h.idToken(ctx, ti, nonce); err != nil {
+			return h.tokenError(w, err)
+		}
+	}
+
+	return token(w, data, nil)
 }
 
 func (h AuthHandlers) tokenError(w http.ResponseWriter, err error) error {
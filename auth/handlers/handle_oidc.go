@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/auth/oauth2"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	systemService "github.com/cortezaproject/corteza-server/system/service"
+	oauth2def "github.com/go-oauth2/oauth2/v4"
+	"go.uber.org/zap"
+)
+
+// OpenID Connect discovery document
+//
+// See https://openid.net/specs/openid-connect-discovery-1_0.html
+func (h AuthHandlers) oidcConfiguration(w http.ResponseWriter, r *http.Request) {
+	var (
+		iss = h.issuer()
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                iss,
+		"authorization_endpoint":                iss + "/oauth2/authorize",
+		"token_endpoint":                        iss + "/oauth2/token",
+		"userinfo_endpoint":                     iss + "/oauth2/userinfo",
+		"jwks_uri":                              iss + "/oauth2/public-keys",
+		"scopes_supported":                      []string{"openid", "profile", "email", "api"},
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "client_credentials"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": auth.DefaultKeySet.Algorithms(),
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+		"claims_supported": []string{
+			"iss", "sub", "aud", "exp", "iat", "nonce",
+			"name", "preferred_username", "locale", "email", "email_verified",
+		},
+	})
+}
+
+// Public keys for verification of ID tokens
+//
+// Access tokens are signed with the shared secret (HS512) and can not be verified with these keys
+func (h AuthHandlers) oidcPublicKeys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(auth.DefaultKeySet.JWKS())
+}
+
+// OpenID Connect userinfo endpoint
+//
+// Returns claims about the user that owns the access token
+func (h AuthHandlers) oidcUserInfo(w http.ResponseWriter, r *http.Request) {
+	ti, err := h.OAuth2.ValidationBearerToken(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if !auth.CheckScope(ti.GetScope(), "openid") {
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	claims, err := h.userClaims(r.Context(), ti)
+	if err != nil {
+		h.Log.Error("failed to load user claims", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(claims)
+}
+
+// creates ID token for the issued access token
+func (h AuthHandlers) idToken(ctx context.Context, ti oauth2def.TokenInfo, nonce string) (string, error) {
+	claims, err := h.userClaims(ctx, ti)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2.IDToken(auth.DefaultKeySet.Signing(), h.issuer(), nonce, ti, claims)
+}
+
+// userClaims returns standard OpenID Connect claims
+// for the token owner, according to token's scope
+func (h AuthHandlers) userClaims(ctx context.Context, ti oauth2def.TokenInfo) (map[string]interface{}, error) {
+	userID, roles := auth.ExtractFromSubClaim(ti.GetUserID())
+	if userID == 0 {
+		return nil, fmt.Errorf("invalid user ID in 'sub' claim")
+	}
+
+	claims := map[string]interface{}{
+		"sub": fmt.Sprintf("%d", userID),
+	}
+
+	var (
+		scope      = ti.GetScope()
+		hasProfile = auth.CheckScope(scope, "profile")
+		hasEmail   = auth.CheckScope(scope, "email")
+	)
+
+	if !hasProfile && !hasEmail {
+		return claims, nil
+	}
+
+	user, err := systemService.DefaultUser.FindByID(
+		// inject ad-hoc identity into context so that user service is aware who is
+		// doing the lookup
+		auth.SetIdentityToContext(ctx, auth.Authenticated(userID, roles...)),
+		userID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if hasProfile {
+		claims["name"] = user.Name
+		claims["preferred_username"] = user.Handle
+
+		if user.Meta != nil && user.Meta.PreferredLanguage != "" {
+			claims["locale"] = user.Meta.PreferredLanguage
+		}
+	}
+
+	if hasEmail {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailConfirmed
+	}
+
+	return claims, nil
+}
+
+// issuer identifier; base URL of the auth server
+func (h AuthHandlers) issuer() string {
+	return strings.TrimRight(h.Opt.BaseURL, "/")
+}
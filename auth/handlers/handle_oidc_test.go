@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/models"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_oidcConfiguration(t *testing.T) {
+	var (
+		req = require.New(t)
+		rec = httptest.NewRecorder()
+		doc = make(map[string]interface{})
+
+		h = AuthHandlers{Opt: options.AuthOpt{BaseURL: "https://corteza.tld/auth/"}}
+	)
+
+	h.oidcConfiguration(rec, httptest.NewRequest(http.MethodGet, "/auth/.well-known/openid-configuration", nil))
+
+	req.Equal(http.StatusOK, rec.Code)
+	req.NoError(json.NewDecoder(rec.Body).Decode(&doc))
+	req.Equal("https://corteza.tld/auth", doc["issuer"])
+	req.Equal("https://corteza.tld/auth/oauth2/public-keys", doc["jwks_uri"])
+	req.Equal("https://corteza.tld/auth/oauth2/userinfo", doc["userinfo_endpoint"])
+}
+
+func Test_oauth2TokenWithIDToken(t *testing.T) {
+	var (
+		req = require.New(t)
+		rec = httptest.NewRecorder()
+
+		ti = &models.Token{
+			ClientID:        "123",
+			UserID:          "42 1 2",
+			Scope:           "openid api",
+			Access:          "access-token",
+			AccessCreateAt:  time.Now(),
+			AccessExpiresIn: time.Hour,
+		}
+
+		h = AuthHandlers{
+			Log: zap.NewNop(),
+			Opt: options.AuthOpt{BaseURL: "https://corteza.tld/auth"},
+			OAuth2: &oauth2ServiceMocked{
+				validationTokenRequest: func(r *http.Request) (oauth2.GrantType, *oauth2.TokenGenerateRequest, error) {
+					return oauth2.AuthorizationCode, &oauth2.TokenGenerateRequest{Code: "code"}, nil
+				},
+				getAccessToken: func(ctx context.Context, gt oauth2.GrantType, tgr *oauth2.TokenGenerateRequest) (oauth2.TokenInfo, error) {
+					return ti, nil
+				},
+				getTokenData: func(ti oauth2.TokenInfo) map[string]interface{} {
+					return map[string]interface{}{"access_token": ti.GetAccess()}
+				},
+			},
+			TokenService: tokenServiceMocked{
+				nonceByCode: func(ctx context.Context, code string) (string, error) {
+					return "nonce-" + code, nil
+				},
+			},
+		}
+
+		authReq = &request.AuthReq{
+			Response: rec,
+			Request:  &http.Request{Form: url.Values{}},
+		}
+
+		data = make(map[string]interface{})
+	)
+
+	defer func(ks *auth.KeySet) { auth.DefaultKeySet = ks }(auth.DefaultKeySet)
+	req.NoError(auth.SetupDefaultKeySet())
+
+	req.NoError(h.handleTokenRequest(authReq, &types.AuthClient{ID: 123, Enabled: true}))
+	req.Equal(http.StatusOK, rec.Code)
+	req.NoError(json.NewDecoder(rec.Body).Decode(&data))
+	req.Contains(data, "id_token")
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(data["id_token"].(string), claims, func(tkn *jwt.Token) (interface{}, error) {
+		return auth.DefaultKeySet.Lookup(tkn.Header["kid"].(string)).Key.Public(), nil
+	})
+
+	req.NoError(err)
+	req.Equal("https://corteza.tld/auth", claims["iss"])
+	req.Equal("42", claims["sub"])
+	req.Equal("123", claims["aud"])
+	req.Equal("nonce-code", claims["nonce"])
+	req.NotEmpty(claims["at_hash"])
+
+	// no ID token without openid scope
+	rec = httptest.NewRecorder()
+	authReq.Response = rec
+	ti.Scope = "api"
+	data = make(map[string]interface{})
+
+	req.NoError(h.handleTokenRequest(authReq, &types.AuthClient{ID: 123, Enabled: true}))
+	req.NoError(json.NewDecoder(rec.Body).Decode(&data))
+	req.NotContains(data, "id_token")
+}
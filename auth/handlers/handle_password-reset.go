@@ -90,6 +90,11 @@ func (h *AuthHandlers) resetPasswordProc(req *request.AuthReq) (err error) {
 			Text: t("password-reset-requested.alerts.password-reset-success"),
 		})
 
+		if req.AuthUser.PasswordChangeRequired {
+			req.AuthUser.PasswordChangeRequired = false
+			req.AuthUser.Save(req.Session)
+		}
+
 		req.RedirectTo = GetLinks().Profile
 		return nil
 	}
@@ -99,6 +104,15 @@ func (h *AuthHandlers) resetPasswordProc(req *request.AuthReq) (err error) {
 		h.passwordResetDisabledAlert(req)
 		return nil
 
+	case service.AuthErrPasswordNotSecure().Is(err),
+		service.AuthErrPasswordReused().Is(err),
+		service.AuthErrPasswordBreached().Is(err):
+		req.SetKV(map[string]string{"error": err.Error()})
+		req.RedirectTo = GetLinks().ResetPassword
+
+		h.Log.Warn("handled error", zap.Error(err))
+		return nil
+
 	default:
 		h.Log.Error("unhandled error", zap.Error(err))
 		return err
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"go.uber.org/zap"
+)
+
+const (
+	// session key where freshly created token
+	// is stored until it is shown to the user
+	personalAccessTokenKey = "personalAccessToken"
+
+	// format of the expiration date in the form
+	personalAccessTokenExpiresFormat = "2006-01-02"
+)
+
+func (h *AuthHandlers) personalAccessTokensView(req *request.AuthReq) error {
+	req.Template = TmplPersonalAccessTokens
+	req.Data["form"] = req.PopKV()
+
+	if token, has := req.Session.Values[personalAccessTokenKey]; has {
+		// token was just created, show it (only once)
+		delete(req.Session.Values, personalAccessTokenKey)
+		req.Data["newToken"] = token
+	}
+
+	tt, err := h.AuthService.PersonalAccessTokens(req.Context(), req.AuthUser.User.ID)
+	if err != nil {
+		return err
+	}
+
+	req.Data["tokens"] = tt
+	return nil
+}
+
+func (h *AuthHandlers) personalAccessTokensProc(req *request.AuthReq) error {
+	req.RedirectTo = GetLinks().PersonalAccessTokens
+	t := translator(req, "auth")
+
+	switch req.Request.PostFormValue("action") {
+	case "create":
+		var (
+			name      = req.Request.PostFormValue("name")
+			scope     = req.Request.PostForm["scope"]
+			expiresAt *time.Time
+		)
+
+		if exp := req.Request.PostFormValue("expiresAt"); exp != "" {
+			// token expires at the end of the day
+			if d, err := time.ParseInLocation(personalAccessTokenExpiresFormat, exp, time.Local); err == nil {
+				d = d.Add(time.Hour*24 - time.Second)
+				expiresAt = &d
+			}
+		}
+
+		token, _, err := h.AuthService.CreatePersonalAccessToken(req.Context(), name, scope, expiresAt)
+		switch {
+		case err == nil:
+			req.Session.Values[personalAccessTokenKey] = token
+			req.NewAlerts = append(req.NewAlerts, request.Alert{
+				Type: "primary",
+				Text: t("personal-access-tokens.alerts.created"),
+			})
+
+		case service.AuthErrDisabledPersonalAccessTokens().Is(err),
+			service.AuthErrInvalidPersonalAccessTokenName().Is(err),
+			service.AuthErrInvalidPersonalAccessTokenScope().Is(err),
+			service.AuthErrInvalidPersonalAccessTokenExpiry().Is(err):
+			req.SetKV(map[string]string{
+				"error":     err.Error(),
+				"name":      name,
+				"expiresAt": req.Request.PostFormValue("expiresAt"),
+			})
+
+			h.Log.Warn("handled error", zap.Error(err))
+
+		default:
+			h.Log.Error("unhandled error", zap.Error(err))
+			return err
+		}
+
+	case "revoke":
+		tokenID, _ := strconv.ParseUint(req.Request.PostFormValue("tokenID"), 10, 64)
+		if err := h.AuthService.RevokePersonalAccessToken(req.Context(), req.AuthUser.User.ID, tokenID); err != nil {
+			return err
+		}
+
+		req.NewAlerts = append(req.NewAlerts, request.Alert{
+			Type: "primary",
+			Text: t("personal-access-tokens.alerts.revoked"),
+		})
+
+		h.Log.Info("personal access token revoked", zap.Uint64("tokenID", tokenID))
+	}
+
+	return nil
+}
+
+func (h *AuthHandlers) onlyIfPersonalAccessTokensEnabled(fn handlerFn) handlerFn {
+	return func(req *request.AuthReq) error {
+		if !h.Settings.PersonalAccessTokens.Enabled {
+			t := translator(req, "auth")
+			req.RedirectTo = GetLinks().Profile
+			req.NewAlerts = append(req.NewAlerts, request.Alert{
+				Type: "danger",
+				Text: t("personal-access-tokens.alerts.disabled"),
+			})
+
+			return nil
+		}
+
+		return fn(req)
+	}
+}
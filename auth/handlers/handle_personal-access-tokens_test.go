@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_personalAccessTokensView(t *testing.T) {
+	var (
+		user = makeMockUser()
+		tt   = []*types.PersonalAccessToken{{ID: 42, Name: "script"}}
+
+		authService = &authServiceMocked{
+			personalAccessTokens: func(_ context.Context, userID uint64) ([]*types.PersonalAccessToken, error) {
+				return tt, nil
+			},
+		}
+
+		authSettings = &settings.Settings{}
+
+		rq = require.New(t)
+	)
+
+	authHandlers := prepareClientAuthHandlers(authService, authSettings)
+	authReq := prepareClientAuthReq(authHandlers, &http.Request{URL: &url.URL{}}, user)
+	authReq.Session.Values = map[interface{}]interface{}{personalAccessTokenKey: "cpat_token"}
+
+	rq.NoError(authHandlers.personalAccessTokensView(authReq))
+	rq.Equal(TmplPersonalAccessTokens, authReq.Template)
+	rq.Equal(tt, authReq.Data["tokens"])
+	rq.Equal("cpat_token", authReq.Data["newToken"])
+
+	// new token is shown only once
+	rq.Empty(authReq.Session.Values)
+}
+
+func Test_personalAccessTokensProc(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{
+			PostForm: url.Values{},
+		}
+
+		authService authService
+	)
+
+	tcc := []testingExpect{
+		{
+			name:    "create",
+			link:    GetLinks().PersonalAccessTokens,
+			payload: map[interface{}]interface{}{personalAccessTokenKey: "cpat_token"},
+			alerts:  []request.Alert{{Type: "primary", Text: "personal-access-tokens.alerts.created"}},
+			fn: func(_ *settings.Settings) {
+				req.PostForm.Set("action", "create")
+				req.PostForm.Set("name", "script")
+				req.PostForm["scope"] = []string{"profile", "api:compose"}
+				req.PostForm.Set("expiresAt", "2030-01-02")
+
+				authService = &authServiceMocked{
+					createPersonalAccessToken: func(_ context.Context, name string, scope []string, expiresAt *time.Time) (string, *types.PersonalAccessToken, error) {
+						if name != "script" || len(scope) != 2 || expiresAt == nil || expiresAt.Day() != 2 {
+							return "", nil, service.AuthErrInvalidPersonalAccessTokenName()
+						}
+
+						return "cpat_token", &types.PersonalAccessToken{}, nil
+					},
+				}
+			},
+		},
+		{
+			name:    "create with invalid scope",
+			link:    GetLinks().PersonalAccessTokens,
+			payload: map[interface{}]interface{}{"KV:": map[string]string{"error": "invalid personal access token scope", "name": "script", "expiresAt": ""}},
+			alerts:  []request.Alert(nil),
+			fn: func(_ *settings.Settings) {
+				req.PostForm.Set("action", "create")
+				req.PostForm.Set("name", "script")
+
+				authService = &authServiceMocked{
+					createPersonalAccessToken: func(_ context.Context, _ string, _ []string, _ *time.Time) (string, *types.PersonalAccessToken, error) {
+						return "", nil, service.AuthErrInvalidPersonalAccessTokenScope()
+					},
+				}
+			},
+		},
+		{
+			name:    "revoke",
+			link:    GetLinks().PersonalAccessTokens,
+			payload: map[interface{}]interface{}{},
+			alerts:  []request.Alert{{Type: "primary", Text: "personal-access-tokens.alerts.revoked"}},
+			fn: func(_ *settings.Settings) {
+				req.PostForm.Set("action", "revoke")
+				req.PostForm.Set("tokenID", "42")
+
+				authService = &authServiceMocked{
+					revokePersonalAccessToken: func(_ context.Context, userID, tokenID uint64) error {
+						if tokenID != 42 {
+							return service.AuthErrInvalidToken()
+						}
+
+						return nil
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			rq := require.New(t)
+
+			// reset from previous
+			req.Form = url.Values{}
+			req.PostForm = url.Values{}
+
+			authSettings := &settings.Settings{}
+
+			tc.fn(authSettings)
+
+			authHandlers := prepareClientAuthHandlers(authService, authSettings)
+			authReq := prepareClientAuthReq(authHandlers, req, user)
+			authReq.Session.Values = map[interface{}]interface{}{}
+
+			rq.NoError(authHandlers.personalAccessTokensProc(authReq))
+			rq.Equal(tc.payload, authReq.Session.Values)
+			rq.Equal(tc.link, authReq.RedirectTo)
+			rq.Equal(tc.alerts, authReq.NewAlerts)
+		})
+	}
+}
@@ -4,20 +4,133 @@ import (
 	"net/http"
 
 	"github.com/cortezaproject/corteza-server/auth/external"
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/saml"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/go-chi/chi"
 	"go.uber.org/zap"
 )
 
-func (h AuthHandlers) samlInit(w http.ResponseWriter, r *http.Request) {
+// returns SAML service of the identity provider from the URL
+//
+// Links without identity provider handle belong to the default identity provider
+func (h *AuthHandlers) samlService(r *http.Request) *saml.SamlSPService {
+	return h.SamlSPServices[chi.URLParam(r, "idp")]
+}
+
+// serves metadata and assertion consumer service of the identity provider
+func (h *AuthHandlers) samlServe(w http.ResponseWriter, r *http.Request) {
+	ss := h.samlService(r)
+	if ss == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	ss.ServeHTTP(w, r)
+}
+
+func (h *AuthHandlers) samlInit(w http.ResponseWriter, r *http.Request) {
+	ss := h.samlService(r)
+	if ss == nil {
+		http.NotFound(w, r)
+		return
+	}
+
 	r = copyProviderToContext(r)
-	h.Log.Info("starting saml authentication flow")
+	h.Log.Info("starting saml authentication flow", zap.String("idp", ss.Handle))
 
-	ex := external.NewSamlExternalHandler(h.SamlSPService)
+	ex := external.NewSamlExternalHandler(*ss)
 	beginUserAuth(w, r, ex)
 
-	if user, err := completeUserAuth(w, r, ex); err != nil {
+	user, err := completeUserAuth(w, r, ex)
+	if err != nil {
 		h.Log.Error("failed to complete user auth", zap.Error(err))
 		h.handleFailedExternalAuth(w, r, err)
-	} else {
-		h.handleSuccessfulExternalAuth(w, r, *user)
+		return
+	}
+
+	h.handleSuccessfulExternalAuth(w, r, *user, func(req *request.AuthReq, u *types.User) error {
+		if roles := ss.MappedRoles(ex.Attributes()); roles != nil {
+			if err := h.AuthService.ExternalRoles(req.Context(), user.Provider, u.ID, roles); err != nil {
+				return err
+			}
+		}
+
+		// link auth session with the session on the identity provider
+		// so that it can be ended with single logout
+		request.SetSamlSession(req.Session, &request.SamlSession{
+			IdP:          ss.Handle,
+			NameID:       ex.NameID(),
+			SessionIndex: ex.SessionIndex(),
+		})
+
+		// auth session takes over from here on
+		return ss.DeleteSession(req.Response, req.Request)
+	})
+}
+
+// handles single logout messages from the identity provider
+//
+// Logout request (IdP-initiated logout) ends all auth sessions
+// linked with the session on the identity provider;
+// logout response completes SP-initiated logout
+func (h *AuthHandlers) samlLogout(req *request.AuthReq) (err error) {
+	ss := h.samlService(req.Request)
+	if ss == nil {
+		req.Status = http.StatusNotFound
+		return nil
+	}
+
+	if !saml.IsLogoutRequest(req.Request) {
+		if err = ss.ValidateLogoutResponse(req.Request); err != nil {
+			h.Log.Warn("invalid SAML logout response", zap.String("idp", ss.Handle), zap.Error(err))
+		}
+
+		req.Template = TmplLogout
+		req.Data["link"] = GetLinks().Login
+		return nil
+	}
+
+	lr, err := ss.ParseLogoutRequest(req.Request)
+	if err != nil {
+		h.Log.Warn("invalid SAML logout request", zap.String("idp", ss.Handle), zap.Error(err))
+		req.Status = http.StatusBadRequest
+		return nil
+	}
+
+	var (
+		ctx   = req.Context()
+		ended = request.SamlSession{IdP: ss.Handle, NameID: lr.NameID.Value}
+	)
+
+	if lr.SessionIndex != nil {
+		ended.SessionIndex = lr.SessionIndex.Value
+	}
+
+	userIDs, err := h.AuthService.ExternalCredentialsOwners(ctx, ss.Provider(), ended.NameID)
+	if err != nil {
+		return
 	}
+
+	for _, userID := range userIDs {
+		if err = h.SessionManager.DeleteBySamlSession(ctx, userID, ended); err != nil {
+			return
+		}
+	}
+
+	if ended.Ends(request.GetSamlSession(req.Session)) {
+		// prevent saving the (already removed) session
+		// that was sent with this request
+		req.Session.Options.MaxAge = -1
+	}
+
+	h.Log.Info("saml single logout", zap.String("idp", ss.Handle), zap.Int("users", len(userIDs)))
+
+	rsp, err := ss.LogoutResponseURL(lr.ID, req.Request.FormValue("RelayState"))
+	if err != nil {
+		return
+	}
+
+	req.RedirectTo = rsp.String()
+	return nil
 }
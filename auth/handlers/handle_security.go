@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"strconv"
+
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"go.uber.org/zap"
 )
@@ -13,6 +15,25 @@ func (h *AuthHandlers) securityForm(req *request.AuthReq) error {
 
 	req.Data["emailOtpEnforced"] = umsp.EnforcedEmailOTP
 	req.Data["totpEnforced"] = umsp.EnforcedTOTP
+	req.Data["webAuthnEnforced"] = umsp.EnforcedWebAuthn
+
+	if umsp.EnforcedTOTP {
+		codes, err := h.AuthService.RecoveryCodes(req.Context(), req.AuthUser.User.ID)
+		if err != nil {
+			return err
+		}
+
+		req.Data["recoveryCodesLeft"] = len(codes)
+	}
+
+	if h.Settings.MultiFactor.WebAuthn.Enabled {
+		keys, err := h.AuthService.WebAuthnCredentials(req.Context(), req.AuthUser.User.ID)
+		if err != nil {
+			return err
+		}
+
+		req.Data["webAuthnKeys"] = keys
+	}
 
 	return nil
 }
@@ -30,6 +51,37 @@ func (h *AuthHandlers) securityProc(req *request.AuthReq) error {
 	case "disableTOTP":
 		req.RedirectTo = GetLinks().MfaTotpDisable
 
+	case "regenerateRecoveryCodes":
+		codes, err := h.AuthService.GenerateRecoveryCodes(req.Context())
+		if err != nil {
+			return err
+		}
+
+		// codes are shown (once) on the TOTP page
+		req.Session.Values[recoveryCodesKey] = codes
+		req.RedirectTo = GetLinks().MfaTotpNewSecret
+
+	case "configureWebAuthn":
+		req.RedirectTo = GetLinks().MfaWebAuthnSetup
+
+	case "removeWebAuthn":
+		credentialsID, _ := strconv.ParseUint(req.Request.Form.Get("credentialsID"), 10, 64)
+		if user, err := h.AuthService.RemoveWebAuthn(req.Context(), req.AuthUser.User.ID, credentialsID); err != nil {
+			return err
+		} else {
+			t := translator(req, "auth")
+			req.NewAlerts = append(req.NewAlerts, request.Alert{
+				Type: "primary",
+				Text: t("security.alerts.webauthn-removed"),
+			})
+
+			// Make sure we update User's data in the session
+			req.AuthUser.User = user
+			req.AuthUser.Save(req.Session)
+
+			h.Log.Info("security key removed", zap.Uint64("credentialsID", credentialsID))
+		}
+
 	case "disableEmailOTP", "enableEmailOTP":
 		enable := action == "enableEmailOTP"
 		if user, err := h.AuthService.ConfigureEmailOTP(req.Context(), req.AuthUser.User.ID, enable); err != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/stretchr/testify/require"
 )
@@ -71,6 +72,30 @@ func Test_securityProc(t *testing.T) {
 				req.Form.Set("action", "disableTOTP")
 			},
 		},
+		{
+			name:    "configureWebAuthn",
+			link:    GetLinks().MfaWebAuthnSetup,
+			payload: map[interface{}]interface{}{"totpSecret": "SECRET_VALUE"},
+			fn: func(_ *settings.Settings) {
+				req.Form.Set("action", "configureWebAuthn")
+			},
+		},
+		{
+			name:    "removeWebAuthn failure",
+			err:     "security keys are enforced and the last key cannot be removed",
+			link:    GetLinks().Security,
+			payload: map[interface{}]interface{}{"totpSecret": "SECRET_VALUE"},
+			fn: func(_ *settings.Settings) {
+				req.Form.Set("action", "removeWebAuthn")
+				req.Form.Set("credentialsID", "42")
+
+				authService = &authServiceMocked{
+					removeWebAuthn: func(_ context.Context, _, credentialsID uint64) (*types.User, error) {
+						return nil, service.AuthErrEnforcedMFAWithWebAuthn()
+					},
+				}
+			},
+		},
 		{
 			name:    "disableEmailOTP failure",
 			err:     "custom error",
@@ -157,3 +182,99 @@ func Test_securityProcDisableEmailOTPSuccess(t *testing.T) {
 	rq.NoError(err)
 	rq.Equal([]request.Alert{{Type: "primary", Text: "security.totp-disabled", Html: ""}}, authReq.NewAlerts)
 }
+
+func Test_securityFormWebAuthn(t *testing.T) {
+	var (
+		user = makeMockUser()
+		keys = types.CredentialsSet{{ID: 42, Label: "YubiKey"}}
+
+		authService = &authServiceMocked{
+			webAuthnCredentials: func(_ context.Context, userID uint64) (types.CredentialsSet, error) {
+				return keys, nil
+			},
+		}
+
+		authSettings = &settings.Settings{}
+
+		rq = require.New(t)
+	)
+
+	authSettings.MultiFactor.WebAuthn.Enabled = true
+	user.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+
+	authHandlers := prepareClientAuthHandlers(authService, authSettings)
+	authReq := prepareClientAuthReq(authHandlers, &http.Request{URL: &url.URL{}}, user)
+
+	rq.NoError(authHandlers.securityForm(authReq))
+	rq.Equal(true, authReq.Data["webAuthnEnforced"])
+	rq.Equal(keys, authReq.Data["webAuthnKeys"])
+}
+
+func Test_securityProcRemoveWebAuthnSuccess(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{
+			Form:     url.Values{},
+			PostForm: url.Values{},
+		}
+
+		removed uint64
+
+		authService = &authServiceMocked{
+			removeWebAuthn: func(_ context.Context, _, credentialsID uint64) (*types.User, error) {
+				removed = credentialsID
+				return user, nil
+			},
+		}
+
+		rq = require.New(t)
+	)
+
+	req.Form.Set("action", "removeWebAuthn")
+	req.Form.Set("credentialsID", "42")
+
+	authHandlers := prepareClientAuthHandlers(authService, &settings.Settings{})
+	authReq := prepareClientAuthReq(authHandlers, req, user)
+
+	rq.NoError(authHandlers.securityProc(authReq))
+	rq.Equal(uint64(42), removed)
+	rq.Equal(GetLinks().Security, authReq.RedirectTo)
+	rq.Equal([]request.Alert{{Type: "primary", Text: "security.alerts.webauthn-removed", Html: ""}}, authReq.NewAlerts)
+}
+
+func Test_securityProcRegenerateRecoveryCodes(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{
+			Form:     url.Values{},
+			PostForm: url.Values{},
+		}
+
+		codes = []string{"abcde-fghjk", "mnpqr-stuvw"}
+
+		authService = &authServiceMocked{
+			generateRecoveryCodes: func(context.Context) ([]string, error) {
+				return codes, nil
+			},
+		}
+
+		rq = require.New(t)
+	)
+
+	req.Form.Set("action", "regenerateRecoveryCodes")
+
+	authHandlers := prepareClientAuthHandlers(authService, &settings.Settings{})
+	authReq := prepareClientAuthReq(authHandlers, req, user)
+
+	rq.NoError(authHandlers.securityProc(authReq))
+	rq.Equal(GetLinks().MfaTotpNewSecret, authReq.RedirectTo)
+	rq.Equal(codes, authReq.Session.Values[recoveryCodesKey])
+
+	// codes are shown only once
+	authReq.Request = &http.Request{URL: &url.URL{}}
+	rq.NoError(authHandlers.mfaTotpConfigForm(authReq))
+	rq.Equal(codes, authReq.Data["recoveryCodes"])
+	rq.NotContains(authReq.Session.Values, recoveryCodesKey)
+}
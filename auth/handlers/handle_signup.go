@@ -65,6 +65,7 @@ func (h *AuthHandlers) signupProc(req *request.AuthReq) error {
 	case service.AuthErrInvalidEmailFormat().Is(err),
 		service.AuthErrInvalidHandle().Is(err),
 		service.AuthErrPasswordNotSecure().Is(err),
+		service.AuthErrPasswordBreached().Is(err),
 		service.AuthErrInvalidCredentials().Is(err):
 		req.SetKV(map[string]string{
 			"error":  err.Error(),
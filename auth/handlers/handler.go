@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/auth/external"
 	"github.com/cortezaproject/corteza-server/auth/request"
@@ -16,6 +17,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/server"
@@ -29,10 +31,14 @@ import (
 type (
 	authService interface {
 		External(ctx context.Context, profile types.ExternalAuthUser) (u *types.User, err error)
+		ExternalRoles(ctx context.Context, provider string, userID uint64, roles map[string]bool) (err error)
+		ExternalCredentialsOwners(ctx context.Context, provider, credentials string) (ids []uint64, err error)
 		InternalSignUp(ctx context.Context, input *types.User, password string) (u *types.User, err error)
 		InternalLogin(ctx context.Context, email string, password string) (u *types.User, err error)
+		LDAPLogin(ctx context.Context, username string, password string) (u *types.User, err error)
 		SetPassword(ctx context.Context, userID uint64, password string) (err error)
 		ChangePassword(ctx context.Context, userID uint64, oldPassword, newPassword string) (err error)
+		PasswordChangeRequired(ctx context.Context, u *types.User) (bool, error)
 		ValidateEmailConfirmationToken(ctx context.Context, token string) (user *types.User, err error)
 		ValidatePasswordResetToken(ctx context.Context, token string) (user *types.User, err error)
 		ValidatePasswordCreateToken(ctx context.Context, token string) (user *types.User, err error)
@@ -44,9 +50,25 @@ type (
 		ConfigureTOTP(ctx context.Context, secret string, code string) (u *types.User, err error)
 		RemoveTOTP(ctx context.Context, userID uint64, code string) (u *types.User, err error)
 
+		GenerateRecoveryCodes(ctx context.Context) (codes []string, err error)
+		ValidateRecoveryCode(ctx context.Context, code string) (err error)
+		RecoveryCodes(ctx context.Context, userID uint64) (types.CredentialsSet, error)
+
 		SendEmailOTP(ctx context.Context) (err error)
 		ConfigureEmailOTP(ctx context.Context, userID uint64, enable bool) (u *types.User, err error)
 		ValidateEmailOTP(ctx context.Context, code string) (err error)
+
+		WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error)
+		WebAuthnRegistrationOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (*webauthn.CreationOptions, error)
+		ConfigureWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AttestationResponse, label string) (*types.User, error)
+		WebAuthnAssertionOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (*webauthn.RequestOptions, error)
+		ValidateWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) error
+		WebAuthnLogin(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) (*types.User, error)
+		RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (*types.User, error)
+
+		PersonalAccessTokens(ctx context.Context, userID uint64) ([]*types.PersonalAccessToken, error)
+		CreatePersonalAccessToken(ctx context.Context, name string, scope []string, expiresAt *time.Time) (string, *types.PersonalAccessToken, error)
+		RevokePersonalAccessToken(ctx context.Context, userID, tokenID uint64) error
 	}
 
 	userService interface {
@@ -57,6 +79,8 @@ type (
 		LookupByID(context.Context, uint64) (*types.AuthClient, error)
 		Confirmed(context.Context, uint64) (types.AuthConfirmedClientSet, error)
 		Revoke(ctx context.Context, userID, clientID uint64) error
+		ServiceClients(ctx context.Context) (types.AuthClientSet, error)
+		ImpersonatedUser(ctx context.Context, c *types.AuthClient) (*types.User, error)
 	}
 
 	// @todo this should probably be a little more decoupled from the store and nicely named
@@ -64,6 +88,7 @@ type (
 		SearchByUserID(ctx context.Context, userID uint64) (types.AuthOa2tokenSet, error)
 		DeleteByID(ctx context.Context, ID uint64) error
 		DeleteByUserID(ctx context.Context, userID uint64) error
+		NonceByCode(ctx context.Context, code string) (string, error)
 	}
 
 	templateExecutor interface {
@@ -108,7 +133,10 @@ type (
 		DefaultClient  *types.AuthClient
 		Opt            options.AuthOpt
 		Settings       *settings.Settings
-		SamlSPService  saml.SamlSPService
+
+		// SAML services by identity provider handle
+		// (default identity provider has an empty handle)
+		SamlSPServices map[string]*saml.SamlSPService
 	}
 
 	handlerFn func(req *request.AuthReq) error
@@ -127,11 +155,13 @@ const (
 	TmplSecurity                 = "security.html.tpl"
 	TmplProfile                  = "profile.html.tpl"
 	TmplSessions                 = "sessions.html.tpl"
+	TmplPersonalAccessTokens     = "personal-access-tokens.html.tpl"
 	TmplSignup                   = "signup.html.tpl"
 	TmplPendingEmailConfirmation = "pending-email-confirmation.html.tpl"
 	TmplMfa                      = "mfa.html.tpl"
 	TmplMfaTotp                  = "mfa-totp.html.tpl"
 	TmplMfaTotpDisable           = "mfa-totp-disable.html.tpl"
+	TmplMfaWebAuthn              = "mfa-webauthn.html.tpl"
 	TmplInternalError            = "error-internal.html.tpl"
 )
 
@@ -314,7 +344,13 @@ func (h *AuthHandlers) enrichTmplData(req *request.AuthReq) interface{} {
 	var pp = make([]provider, 0, len(dSettings.Providers))
 
 	if h.Settings.Saml.Enabled {
-		pp = append(pp, provider(saml.TemplateProvider(h.Settings.Saml.IDP.URL, h.Settings.Saml.IDP.Name)))
+		if h.Settings.Saml.IDP.URL != "" {
+			pp = append(pp, provider(saml.TemplateProvider("", h.Settings.Saml.IDP.URL, h.Settings.Saml.IDP.Name)))
+		}
+
+		for _, idp := range h.Settings.Saml.IDPs {
+			pp = append(pp, provider(saml.TemplateProvider(idp.Handle, idp.URL, idp.Name)))
+		}
 	}
 
 	for _, p := range dSettings.Providers {
@@ -349,6 +385,9 @@ func handleSuccessfulAuth(req *request.AuthReq) {
 	case req.AuthUser.PendingMFA():
 		req.RedirectTo = GetLinks().Mfa
 
+	case req.AuthUser.PasswordChangeRequired:
+		req.RedirectTo = GetLinks().ChangePassword
+
 	case request.GetOAuth2AuthParams(req.Session) != nil:
 		// client authorization flow was paused, continue.
 		req.RedirectTo = GetLinks().OAuth2AuthorizeClient
@@ -360,7 +399,22 @@ func handleSuccessfulAuth(req *request.AuthReq) {
 }
 
 // redirects anonymous users to login
+// and users that need to change their password to password change form
 func authOnly(fn handlerFn) handlerFn {
+	return authOnlyAllowPasswordChange(func(req *request.AuthReq) error {
+		if req.AuthUser.PasswordChangeRequired {
+			req.RedirectTo = GetLinks().ChangePassword
+			return nil
+		}
+
+		return fn(req)
+	})
+}
+
+// redirects anonymous users to login
+//
+// Users that need to change their password are let through
+func authOnlyAllowPasswordChange(fn handlerFn) handlerFn {
 	return func(req *request.AuthReq) error {
 		// these next few lines keep users away from the pages they should not see
 		// and redirect them to where they need to be
@@ -373,6 +427,10 @@ func authOnly(fn handlerFn) handlerFn {
 			// authenticated but need to configure MFA
 			req.RedirectTo = GetLinks().MfaTotpNewSecret
 
+		case req.AuthUser.UnconfiguredWebAuthn():
+			// authenticated but need to register security key
+			req.RedirectTo = GetLinks().MfaWebAuthnSetup
+
 		case req.AuthUser.PendingMFA():
 			// authenticated but MFA pending
 			req.RedirectTo = GetLinks().Mfa
@@ -9,6 +9,7 @@ type (
 		ConfirmEmail,
 		PendingEmailConfirmation,
 		Login,
+		LoginWebAuthn,
 
 		Security,
 		ChangePassword,
@@ -19,6 +20,7 @@ type (
 		ResetPassword,
 		Sessions,
 		AuthorizedClients,
+		PersonalAccessTokens,
 		Logout,
 
 		OAuth2Authorize,
@@ -26,6 +28,10 @@ type (
 		OAuth2Token,
 		OAuth2Info,
 		OAuth2DefaultClient,
+		OAuth2UserInfo,
+		OAuth2PublicKeys,
+
+		OpenIDConfiguration,
 
 		Mfa,
 
@@ -33,6 +39,8 @@ type (
 		MfaTotpQRImage,
 		MfaTotpDisable,
 
+		MfaWebAuthnSetup,
+
 		External,
 
 		SamlInit,
@@ -57,6 +65,7 @@ func GetLinks() Links {
 		ConfirmEmail:             b + "auth/confirm-email",
 		PendingEmailConfirmation: b + "auth/pending-email-confirmation",
 		Login:                    b + "auth/login",
+		LoginWebAuthn:            b + "auth/login/webauthn",
 		Security:                 b + "auth/security",
 		ChangePassword:           b + "auth/change-password",
 		CreatePassword:           b + "auth/create-password",
@@ -65,6 +74,7 @@ func GetLinks() Links {
 		ResetPassword:            b + "auth/reset-password",
 		Sessions:                 b + "auth/sessions",
 		AuthorizedClients:        b + "auth/authorized-clients",
+		PersonalAccessTokens:     b + "auth/personal-access-tokens",
 		Logout:                   b + "auth/logout",
 
 		OAuth2Authorize:       b + "auth/oauth2/authorize",
@@ -72,11 +82,16 @@ func GetLinks() Links {
 		OAuth2Token:           b + "auth/oauth2/token",
 		OAuth2Info:            b + "auth/oauth2/info",
 		OAuth2DefaultClient:   b + "auth/oauth2/default-client",
+		OAuth2UserInfo:        b + "auth/oauth2/userinfo",
+		OAuth2PublicKeys:      b + "auth/oauth2/public-keys",
+
+		OpenIDConfiguration: b + "auth/.well-known/openid-configuration",
 
 		Mfa:              b + "auth/mfa",
 		MfaTotpNewSecret: b + "auth/mfa/totp/setup",
 		MfaTotpQRImage:   b + "auth/mfa/totp/qr.png",
 		MfaTotpDisable:   b + "auth/mfa/totp/disable",
+		MfaWebAuthnSetup: b + "auth/mfa/webauthn/setup",
 
 		External: b + "auth/external",
 
@@ -90,6 +105,22 @@ func GetLinks() Links {
 	}
 }
 
+// GetSamlLinks returns SAML links for the identity provider
+//
+// Default identity provider (with an empty handle) uses the base SAML links
+func GetSamlLinks(handle string) Links {
+	var l = GetLinks()
+
+	if handle != "" {
+		l.SamlInit = l.External + "/saml/" + handle + "/init"
+		l.SamlCallback = l.External + "/saml/" + handle + "/callback"
+		l.SamlMetadata = l.External + "/saml/" + handle + "/metadata"
+		l.SamlLogout = l.External + "/saml/" + handle + "/slo"
+	}
+
+	return l
+}
+
 // trim base path
 func tbp(s string) string {
 	s = strings.TrimPrefix(s, BasePath)
@@ -10,6 +10,7 @@ import (
 	"github.com/cortezaproject/corteza-server/auth/settings"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -60,6 +61,13 @@ type (
 		validationBearerToken      func(r *http.Request) (oauth2.TokenInfo, error)
 	}
 
+	tokenServiceMocked struct {
+		searchByUserID func(context.Context, uint64) (types.AuthOa2tokenSet, error)
+		deleteByID     func(context.Context, uint64) error
+		deleteByUserID func(context.Context, uint64) error
+		nonceByCode    func(context.Context, string) (string, error)
+	}
+
 	testingExpect struct {
 		name        string
 		payload     interface{}
@@ -77,10 +85,14 @@ type (
 
 	authServiceMocked struct {
 		external                          func(context.Context, types.ExternalAuthUser) (u *types.User, err error)
+		externalRoles                     func(context.Context, string, uint64, map[string]bool) error
+		externalCredentialsOwners         func(context.Context, string, string) ([]uint64, error)
 		internalSignUp                    func(context.Context, *types.User, string) (u *types.User, err error)
 		internalLogin                     func(context.Context, string, string) (u *types.User, err error)
+		ldapLogin                         func(context.Context, string, string) (u *types.User, err error)
 		setPassword                       func(context.Context, uint64, string) (err error)
 		changePassword                    func(context.Context, uint64, string, string) (err error)
+		passwordChangeRequired            func(context.Context, *types.User) (bool, error)
 		createPassword                    func(context.Context, uint64, string) (err error)
 		validateEmailConfirmationToken    func(context.Context, string) (user *types.User, err error)
 		validatePasswordResetToken        func(context.Context, string) (user *types.User, err error)
@@ -92,9 +104,22 @@ type (
 		validateTOTP                      func(context.Context, string) (err error)
 		configureTOTP                     func(context.Context, string, string) (u *types.User, err error)
 		removeTOTP                        func(context.Context, uint64, string) (u *types.User, err error)
+		generateRecoveryCodes             func(context.Context) ([]string, error)
+		validateRecoveryCode              func(context.Context, string) error
+		recoveryCodes                     func(context.Context, uint64) (types.CredentialsSet, error)
 		sendEmailOTP                      func(context.Context) (err error)
 		configureEmailOTP                 func(context.Context, uint64, bool) (u *types.User, err error)
 		validateEmailOTP                  func(context.Context, string) (err error)
+		webAuthnCredentials               func(context.Context, uint64) (types.CredentialsSet, error)
+		webAuthnRegistrationOptions       func(context.Context, webauthn.RelyingParty, []byte) (*webauthn.CreationOptions, error)
+		configureWebAuthn                 func(context.Context, webauthn.RelyingParty, []byte, *webauthn.AttestationResponse, string) (*types.User, error)
+		webAuthnAssertionOptions          func(context.Context, webauthn.RelyingParty, []byte) (*webauthn.RequestOptions, error)
+		validateWebAuthn                  func(context.Context, webauthn.RelyingParty, []byte, *webauthn.AssertionResponse) error
+		webAuthnLogin                     func(context.Context, webauthn.RelyingParty, []byte, *webauthn.AssertionResponse) (*types.User, error)
+		removeWebAuthn                    func(context.Context, uint64, uint64) (*types.User, error)
+		personalAccessTokens              func(context.Context, uint64) ([]*types.PersonalAccessToken, error)
+		createPersonalAccessToken         func(context.Context, string, []string, *time.Time) (string, *types.PersonalAccessToken, error)
+		revokePersonalAccessToken         func(context.Context, uint64, uint64) error
 	}
 )
 
@@ -112,6 +137,14 @@ func (s authServiceMocked) External(ctx context.Context, profile types.ExternalA
 	return s.external(ctx, profile)
 }
 
+func (s authServiceMocked) ExternalRoles(ctx context.Context, provider string, userID uint64, roles map[string]bool) error {
+	return s.externalRoles(ctx, provider, userID, roles)
+}
+
+func (s authServiceMocked) ExternalCredentialsOwners(ctx context.Context, provider, credentials string) ([]uint64, error) {
+	return s.externalCredentialsOwners(ctx, provider, credentials)
+}
+
 func (s authServiceMocked) InternalSignUp(ctx context.Context, input *types.User, password string) (u *types.User, err error) {
 	return s.internalSignUp(ctx, input, password)
 }
@@ -120,6 +153,10 @@ func (s authServiceMocked) InternalLogin(ctx context.Context, email string, pass
 	return s.internalLogin(ctx, email, password)
 }
 
+func (s authServiceMocked) LDAPLogin(ctx context.Context, username string, password string) (u *types.User, err error) {
+	return s.ldapLogin(ctx, username, password)
+}
+
 func (s authServiceMocked) SetPassword(ctx context.Context, userID uint64, password string) (err error) {
 	return s.setPassword(ctx, userID, password)
 }
@@ -128,6 +165,10 @@ func (s authServiceMocked) ChangePassword(ctx context.Context, userID uint64, ol
 	return s.changePassword(ctx, userID, oldPassword, newPassword)
 }
 
+func (s authServiceMocked) PasswordChangeRequired(ctx context.Context, u *types.User) (bool, error) {
+	return s.passwordChangeRequired(ctx, u)
+}
+
 func (s authServiceMocked) ValidateEmailConfirmationToken(ctx context.Context, token string) (user *types.User, err error) {
 	return s.validateEmailConfirmationToken(ctx, token)
 }
@@ -168,6 +209,18 @@ func (s authServiceMocked) RemoveTOTP(ctx context.Context, userID uint64, code s
 	return s.removeTOTP(ctx, userID, code)
 }
 
+func (s authServiceMocked) GenerateRecoveryCodes(ctx context.Context) ([]string, error) {
+	return s.generateRecoveryCodes(ctx)
+}
+
+func (s authServiceMocked) ValidateRecoveryCode(ctx context.Context, code string) error {
+	return s.validateRecoveryCode(ctx, code)
+}
+
+func (s authServiceMocked) RecoveryCodes(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return s.recoveryCodes(ctx, userID)
+}
+
 func (s authServiceMocked) SendEmailOTP(ctx context.Context) (err error) {
 	return s.sendEmailOTP(ctx)
 }
@@ -180,6 +233,46 @@ func (s authServiceMocked) ValidateEmailOTP(ctx context.Context, code string) (e
 	return s.validateEmailOTP(ctx, code)
 }
 
+func (s authServiceMocked) WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return s.webAuthnCredentials(ctx, userID)
+}
+
+func (s authServiceMocked) WebAuthnRegistrationOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (*webauthn.CreationOptions, error) {
+	return s.webAuthnRegistrationOptions(ctx, rp, challenge)
+}
+
+func (s authServiceMocked) ConfigureWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AttestationResponse, label string) (*types.User, error) {
+	return s.configureWebAuthn(ctx, rp, challenge, r, label)
+}
+
+func (s authServiceMocked) WebAuthnAssertionOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (*webauthn.RequestOptions, error) {
+	return s.webAuthnAssertionOptions(ctx, rp, challenge)
+}
+
+func (s authServiceMocked) ValidateWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) error {
+	return s.validateWebAuthn(ctx, rp, challenge, r)
+}
+
+func (s authServiceMocked) WebAuthnLogin(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) (*types.User, error) {
+	return s.webAuthnLogin(ctx, rp, challenge, r)
+}
+
+func (s authServiceMocked) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (*types.User, error) {
+	return s.removeWebAuthn(ctx, userID, credentialsID)
+}
+
+func (s authServiceMocked) PersonalAccessTokens(ctx context.Context, userID uint64) ([]*types.PersonalAccessToken, error) {
+	return s.personalAccessTokens(ctx, userID)
+}
+
+func (s authServiceMocked) CreatePersonalAccessToken(ctx context.Context, name string, scope []string, expiresAt *time.Time) (string, *types.PersonalAccessToken, error) {
+	return s.createPersonalAccessToken(ctx, name, scope, expiresAt)
+}
+
+func (s authServiceMocked) RevokePersonalAccessToken(ctx context.Context, userID, tokenID uint64) error {
+	return s.revokePersonalAccessToken(ctx, userID, tokenID)
+}
+
 //
 // Mocking oauth2Service
 //
@@ -243,6 +336,25 @@ func (s *oauth2ServiceMocked) ValidationBearerToken(r *http.Request) (oauth2.Tok
 	return s.validationBearerToken(r)
 }
 
+//
+// Mocking tokenService
+//
+func (s tokenServiceMocked) SearchByUserID(ctx context.Context, userID uint64) (types.AuthOa2tokenSet, error) {
+	return s.searchByUserID(ctx, userID)
+}
+
+func (s tokenServiceMocked) DeleteByID(ctx context.Context, ID uint64) error {
+	return s.deleteByID(ctx, ID)
+}
+
+func (s tokenServiceMocked) DeleteByUserID(ctx context.Context, userID uint64) error {
+	return s.deleteByUserID(ctx, userID)
+}
+
+func (s tokenServiceMocked) NonceByCode(ctx context.Context, code string) (string, error) {
+	return s.nonceByCode(ctx, code)
+}
+
 //
 // Mocking authService
 //
@@ -285,6 +397,10 @@ func (m mockNotificationService) EmailOTP(ctx context.Context, emailAddress stri
 	return nil
 }
 
+func (m mockNotificationService) AccountLocked(ctx context.Context, emailAddress string, until time.Time) error {
+	return nil
+}
+
 //
 // Mocking gorilla session
 //
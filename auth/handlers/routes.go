@@ -59,13 +59,17 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 			r.Get(tbp(l.AuthorizedClients), h.handle(authOnly(h.clientsView)))
 			r.Post(tbp(l.AuthorizedClients), h.handle(authOnly(h.clientsProc)))
 
+			r.Get(tbp(l.PersonalAccessTokens), h.handle(h.onlyIfPersonalAccessTokensEnabled(authOnly(h.personalAccessTokensView))))
+			r.Post(tbp(l.PersonalAccessTokens), h.handle(h.onlyIfPersonalAccessTokensEnabled(authOnly(h.personalAccessTokensProc))))
+
 			r.Get(tbp(l.Signup), h.handle(h.onlyIfSignupEnabled(anonyOnly(h.signupForm))))
 			r.Post(tbp(l.Signup), h.handle(h.onlyIfSignupEnabled(anonyOnly(h.signupProc))))
 			r.Get(tbp(l.PendingEmailConfirmation), h.handle(h.pendingEmailConfirmation))
 			r.Get(tbp(l.ConfirmEmail), h.handle(h.confirmEmail))
 
 			r.Get(tbp(l.Login), h.handle(anonyOnly(h.loginForm)))
-			r.Post(tbp(l.Login), h.handle(h.onlyIfLocalEnabled(anonyOnly(h.loginProc))))
+			r.Post(tbp(l.Login), h.handle(h.onlyIfLocalOrLDAPEnabled(anonyOnly(h.loginProc))))
+			r.Post(tbp(l.LoginWebAuthn), h.handle(anonyOnly(h.loginWebAuthnProc)))
 
 			r.Get(tbp(l.Mfa), h.handle(h.mfaForm))
 			r.Post(tbp(l.Mfa), h.handle(h.mfaProc))
@@ -74,12 +78,12 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 			r.Post(tbp(l.RequestPasswordReset), h.handle(h.onlyIfPasswordResetEnabled(anonyOnly(h.requestPasswordResetProc))))
 			r.Get(tbp(l.PasswordResetRequested), h.handle(h.onlyIfPasswordResetEnabled(anonyOnly(h.passwordResetRequested))))
 			r.Get(tbp(l.ResetPassword), h.handle(h.onlyIfPasswordResetEnabled(h.resetPasswordForm)))
-			r.Post(tbp(l.ResetPassword), h.handle(h.onlyIfPasswordResetEnabled(authOnly(h.resetPasswordProc))))
+			r.Post(tbp(l.ResetPassword), h.handle(h.onlyIfPasswordResetEnabled(authOnlyAllowPasswordChange(h.resetPasswordProc))))
 
 			r.Get(tbp(l.Security), h.handle(authOnly(h.securityForm)))
 			r.Post(tbp(l.Security), h.handle(authOnly(h.securityProc)))
-			r.Get(tbp(l.ChangePassword), h.handle(h.onlyIfLocalEnabled(authOnly(h.changePasswordForm))))
-			r.Post(tbp(l.ChangePassword), h.handle(h.onlyIfLocalEnabled(authOnly(h.changePasswordProc))))
+			r.Get(tbp(l.ChangePassword), h.handle(h.onlyIfLocalEnabled(authOnlyAllowPasswordChange(h.changePasswordForm))))
+			r.Post(tbp(l.ChangePassword), h.handle(h.onlyIfLocalEnabled(authOnlyAllowPasswordChange(h.changePasswordProc))))
 			r.Get(tbp(l.CreatePassword), h.handle(h.onlyIfPasswordCreateEnabled(h.createPasswordForm)))
 			r.Post(tbp(l.CreatePassword), h.handle(h.onlyIfPasswordCreateEnabled(h.createPasswordProc)))
 
@@ -89,6 +93,9 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 			r.Get(tbp(l.MfaTotpDisable), h.handle(authOnly(h.mfaTotpDisableForm)))
 			r.Post(tbp(l.MfaTotpDisable), h.handle(authOnly(h.mfaTotpDisableProc)))
 
+			r.Get(tbp(l.MfaWebAuthnSetup), h.handle(partAuthOnly(h.mfaWebAuthnConfigForm)))
+			r.Post(tbp(l.MfaWebAuthnSetup), h.handle(partAuthOnly(h.mfaWebAuthnConfigProc)))
+
 		})
 
 		r.Group(func(r chi.Router) {
@@ -101,9 +108,19 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 		})
 
 		r.Group(func(r chi.Router) {
-			r.Handle(tbp(l.SamlMetadata), h.SamlSPService)
-			r.Handle(tbp(l.SamlCallback), h.SamlSPService)
+			// default identity provider
+			r.Handle(tbp(l.SamlMetadata), http.HandlerFunc(h.samlServe))
+			r.Handle(tbp(l.SamlCallback), http.HandlerFunc(h.samlServe))
 			r.HandleFunc(tbp(l.SamlInit), h.samlInit)
+			r.HandleFunc(tbp(l.SamlLogout), h.handle(h.samlLogout))
+
+			// additional identity providers
+			r.Route(tbp(l.External)+"/saml/{idp}", func(r chi.Router) {
+				r.Handle("/metadata", http.HandlerFunc(h.samlServe))
+				r.Handle("/callback", http.HandlerFunc(h.samlServe))
+				r.HandleFunc("/init", h.samlInit)
+				r.HandleFunc("/slo", h.handle(h.samlLogout))
+			})
 		})
 
 		r.Route(tbp(l.External)+"/{provider}", func(r chi.Router) {
@@ -114,5 +131,10 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 
 		r.HandleFunc("/auth/oauth2/token", h.handle(h.oauth2Token))
 		r.HandleFunc("/auth/oauth2/info", h.oauth2Info)
+
+		// OpenID Connect
+		r.Get(tbp(l.OpenIDConfiguration), h.oidcConfiguration)
+		r.Get(tbp(l.OAuth2PublicKeys), h.oidcPublicKeys)
+		r.HandleFunc(tbp(l.OAuth2UserInfo), h.oidcUserInfo)
 	})
 }
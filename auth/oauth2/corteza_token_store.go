@@ -74,6 +74,20 @@ func (c CortezaTokenStore) Create(ctx context.Context, info oauth2.TokenInfo) (e
 		return
 	}
 
+	if eti.Nonce != "" && oa2t.Code != "" {
+		// keep nonce with the code so that it can be added to the ID token
+		// when code is exchanged for the access token
+		var aux map[string]interface{}
+		if err = json.Unmarshal(oa2t.Data, &aux); err != nil {
+			return
+		}
+
+		aux["nonce"] = eti.Nonce
+		if oa2t.Data, err = json.Marshal(aux); err != nil {
+			return
+		}
+	}
+
 	if oa2t.ClientID, err = strconv.ParseUint(info.GetClientID(), 10, 64); err != nil {
 		return fmt.Errorf("could not parse client ID from token info: %w", err)
 	}
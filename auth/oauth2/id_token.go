@@ -0,0 +1,61 @@
+package oauth2
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-oauth2/oauth2/v4"
+)
+
+// IDToken creates OpenID Connect ID token for the issued access token
+//
+// Claims (user's profile) are added to the token
+// and standard claims (iss, sub, aud, exp, iat, nonce, at_hash) are set
+func IDToken(key *auth.SigningKey, issuer, nonce string, ti oauth2.TokenInfo, claims map[string]interface{}) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("signing key missing")
+	}
+
+	userID, _ := auth.ExtractFromSubClaim(ti.GetUserID())
+	if userID == 0 {
+		return "", fmt.Errorf("invalid user ID in token info")
+	}
+
+	mc := jwt.MapClaims{}
+	for k, v := range claims {
+		mc[k] = v
+	}
+
+	mc["iss"] = issuer
+	mc["sub"] = fmt.Sprintf("%d", userID)
+	mc["aud"] = ti.GetClientID()
+	mc["iat"] = ti.GetAccessCreateAt().Unix()
+	mc["exp"] = ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).Unix()
+
+	if nonce != "" {
+		mc["nonce"] = nonce
+	}
+
+	if access := ti.GetAccess(); access != "" {
+		// left-most half of the access token hash
+		// (both supported algorithms use SHA-256)
+		sum := sha256.Sum256([]byte(access))
+		mc["at_hash"] = base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+	}
+
+	return key.Sign(mc)
+}
+
+// CodeNonce extracts nonce from the data stored with authorization code
+func CodeNonce(data []byte) string {
+	aux := struct {
+		Nonce string `json:"nonce"`
+	}{}
+
+	_ = json.Unmarshal(data, &aux)
+	return aux.Nonce
+}
@@ -5,12 +5,13 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/rand"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-oauth2/oauth2/v4"
-	"github.com/go-oauth2/oauth2/v4/errors"
 	"strings"
 )
 
 // NewJWTAccessGenerate create to generate the jwt access token instance
-func NewJWTAccessGenerate(kid string, key []byte, method jwt.SigningMethod) *JWTAccessGenerate {
+//
+// Key is a secret ([]byte) for HMAC or a private key for RSA and ECDSA signing methods
+func NewJWTAccessGenerate(kid string, key interface{}, method jwt.SigningMethod) *JWTAccessGenerate {
 	return &JWTAccessGenerate{
 		SignedKeyID:  kid,
 		SignedKey:    key,
@@ -21,7 +22,7 @@ func NewJWTAccessGenerate(kid string, key []byte, method jwt.SigningMethod) *JWT
 // JWTAccessGenerate generate the jwt access token
 type JWTAccessGenerate struct {
 	SignedKeyID  string
-	SignedKey    []byte
+	SignedKey    interface{}
 	SignedMethod jwt.SigningMethod
 }
 
@@ -46,26 +47,8 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 	if a.SignedKeyID != "" {
 		token.Header["kid"] = a.SignedKeyID
 	}
-	var key interface{}
-	if a.isEs() {
-		v, err := jwt.ParseECPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isRsOrPS() {
-		v, err := jwt.ParseRSAPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isHs() {
-		key = a.SignedKey
-	} else {
-		return "", "", errors.New("unsupported sign method")
-	}
 
-	access, err := token.SignedString(key)
+	access, err := token.SignedString(a.SignedKey)
 	if err != nil {
 		return "", "", err
 	}
@@ -77,17 +60,3 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 
 	return access, refresh, nil
 }
-
-func (a *JWTAccessGenerate) isEs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "ES")
-}
-
-func (a *JWTAccessGenerate) isRsOrPS() bool {
-	isRs := strings.HasPrefix(a.SignedMethod.Alg(), "RS")
-	isPs := strings.HasPrefix(a.SignedMethod.Alg(), "PS")
-	return isRs || isPs
-}
-
-func (a *JWTAccessGenerate) isHs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "HS")
-}
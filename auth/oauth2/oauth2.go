@@ -25,6 +25,9 @@ func NewManager(opt options.AuthOpt, log *zap.Logger, cs oauth2.ClientStore, ts
 	manager.MapTokenStorage(ts)
 
 	// generate jwt access token
+	//
+	// access tokens are always signed with JWT secret; signing keys
+	// are used only for ID tokens that are verified by the clients
 	manager.MapAccessGenerate(NewJWTAccessGenerate("", []byte(opt.Secret), jwt.SigningMethodHS512))
 	manager.MapClientStorage(cs)
 
@@ -70,8 +73,6 @@ func NewServer(manager *manage.Manager) *server.Server {
 		AllowedGrantTypes: []oauth2.GrantType{
 			oauth2.AuthorizationCode,
 			oauth2.Refreshing,
-			// before enabling ClientCredentials grant type, we need to know how to modify released token
-			// using client's security info; how to enforce impersonated user and his roles.
 			oauth2.ClientCredentials,
 		},
 		AllowedCodeChallengeMethods: []oauth2.CodeChallengeMethod{
@@ -19,6 +19,10 @@ type (
 		PermLifetime time.Duration
 
 		MFAStatus map[authType]authStatus
+
+		// password expired or change was requested;
+		// user needs to change it before continuing
+		PasswordChangeRequired bool
 	}
 
 	authStatus uint
@@ -46,6 +50,7 @@ const (
 	authByPassword = "password"
 	authByEmailOTP = "email-otp"
 	authByTOTP     = "totp"
+	authByWebAuthn = "webauthn"
 )
 
 func init() {
@@ -87,6 +92,7 @@ func (au *authUser) set(s *settings.Settings, u *types.User) {
 		authByPassword: authStatusOK,
 		authByEmailOTP: authStatusDisabled,
 		authByTOTP:     authStatusDisabled,
+		authByWebAuthn: authStatusDisabled,
 	}
 
 	// determinate mfa status for email OTP
@@ -106,6 +112,16 @@ func (au *authUser) set(s *settings.Settings, u *types.User) {
 		mfaStatus[authByTOTP] = authStatusPending
 	}
 
+	// determinate mfa status for security keys
+	if !gmsp.WebAuthn.Enabled {
+		mfaStatus[authByWebAuthn] = authStatusDisabled
+	} else if !umsp.EnforcedWebAuthn && gmsp.WebAuthn.Enforced {
+		// security key not registered but enforced globally
+		mfaStatus[authByWebAuthn] = authStatusUnconfigured
+	} else if umsp.EnforcedWebAuthn {
+		mfaStatus[authByWebAuthn] = authStatusPending
+	}
+
 	au.MFAStatus = mfaStatus
 }
 
@@ -129,6 +145,18 @@ func (au authUser) PendingTOTP() bool {
 	return au.MFAStatus[authByTOTP] == authStatusPending
 }
 
+func (au authUser) DisabledWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusDisabled
+}
+
+func (au authUser) UnconfiguredWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusUnconfigured
+}
+
+func (au authUser) PendingWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusPending
+}
+
 // PendingMFA Returns true if any of MFAs are pending
 func (au authUser) PendingMFA() bool {
 	for _, st := range au.MFAStatus {
@@ -152,6 +180,10 @@ func (au *authUser) ResetTOTP() {
 	au.MFAStatus[authByTOTP] = authStatusUnconfigured
 }
 
+func (au *authUser) CompleteWebAuthn() {
+	au.MFAStatus[authByWebAuthn] = authStatusOK
+}
+
 func (au *authUser) Forget(ses *sessions.Session) {
 	delete(ses.Values, keyAuthUser)
 	delete(ses.Values, keyPermanent)
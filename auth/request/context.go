@@ -68,6 +68,10 @@ type (
 	ExtraReqInfo struct {
 		RemoteAddr string
 		UserAgent  string
+
+		// Nonce from OpenID Connect authentication request,
+		// stored with authorization code and copied into ID token
+		Nonce string
 	}
 )
 
@@ -1,7 +1,9 @@
 package request
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
 	"net/http"
 
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -58,3 +60,31 @@ func (m *SessionManager) DeleteByUserID(ctx context.Context, userID uint64) (err
 func (m *SessionManager) DeleteByID(ctx context.Context, sessionID string) (err error) {
 	return m.cstore.DeleteAuthSessionByID(ctx, sessionID)
 }
+
+// DeleteBySamlSession removes user's sessions linked with the SAML session
+//
+// When session index is empty, all sessions linked with
+// the identity provider and NameID are removed
+func (m *SessionManager) DeleteBySamlSession(ctx context.Context, userID uint64, ss SamlSession) (err error) {
+	set, _, err := m.cstore.SearchAuthSessions(ctx, types.AuthSessionFilter{UserID: userID})
+	if err != nil {
+		return
+	}
+
+	for _, s := range set {
+		var values = make(map[interface{}]interface{})
+		if gob.NewDecoder(bytes.NewReader(s.Data)).Decode(&values) != nil {
+			continue
+		}
+
+		if linked, is := values[keySamlSession].(*SamlSession); !is || !ss.Ends(linked) {
+			continue
+		}
+
+		if err = m.cstore.DeleteAuthSessionByID(ctx, s.ID); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
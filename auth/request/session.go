@@ -1,11 +1,23 @@
 package request
 
 import (
+	"encoding/gob"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/gorilla/sessions"
 	"net/url"
 )
 
+type (
+	// SamlSession links session with the session on SAML identity provider
+	SamlSession struct {
+		// Identity provider handle, empty for the default one
+		IdP string
+
+		NameID       string
+		SessionIndex string
+	}
+)
+
 const (
 	keyPermanent              = "permanent"
 	keyOriginalSession        = "originalSession"
@@ -14,8 +26,13 @@ const (
 	keyOAuth2AuthParams       = "oauth2AuthParams"
 	keyOAuth2Client           = "oauth2ClientID"
 	keyOAuth2ClientAuthorized = "oauth2ClientAuthorized"
+	keySamlSession            = "samlSession"
 )
 
+func init() {
+	gob.Register(&SamlSession{})
+}
+
 // GetUser is wrapper to get value from session
 func GetAuthUser(ses *sessions.Session) *authUser {
 	val, has := ses.Values[keyAuthUser]
@@ -101,3 +118,35 @@ func SetOauth2ClientAuthorized(ses *sessions.Session, val bool) {
 		delete(ses.Values, keyOAuth2ClientAuthorized)
 	}
 }
+
+// Ends checks if ending of the SAML session (by identity provider)
+// ends the linked session as well
+//
+// Empty session index ends all sessions of the NameID
+func (s SamlSession) Ends(linked *SamlSession) bool {
+	switch {
+	case linked == nil, linked.IdP != s.IdP, linked.NameID != s.NameID:
+		return false
+	default:
+		return s.SessionIndex == "" || s.SessionIndex == linked.SessionIndex
+	}
+}
+
+// GetSamlSession is wrapper to get value from session
+func GetSamlSession(ses *sessions.Session) *SamlSession {
+	val, has := ses.Values[keySamlSession]
+	if !has {
+		return nil
+	}
+
+	return val.(*SamlSession)
+}
+
+// SetSamlSession is a session value setting wrapper for SamlSession
+func SetSamlSession(ses *sessions.Session, val *SamlSession) {
+	if val != nil {
+		ses.Values[keySamlSession] = val
+	} else {
+		delete(ses.Values, keySamlSession)
+	}
+}
@@ -6,4 +6,10 @@ type (
 		Handle     string
 		Identifier string
 	}
+
+	// RoleMapping maps assertion attribute value to role (handle)
+	RoleMapping struct {
+		Value string
+		Role  string
+	}
 )
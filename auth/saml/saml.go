@@ -25,16 +25,24 @@ func FetchIDPMetadata(ctx context.Context, u url.URL) (*saml.EntityDescriptor, e
 
 // TemplateProvider adds a wrapper to the button
 // data that is displayed on the login form
-func TemplateProvider(url, name string) templateProvider {
+//
+// Default identity provider has an empty handle
+func TemplateProvider(handle, url, name string) templateProvider {
 	if name == "" {
 		name = url
 	}
 
-	return templateProvider{
+	tp := templateProvider{
 		Label:  name,
 		Handle: "saml/init",
 		Icon:   "key",
 	}
+
+	if handle != "" {
+		tp.Handle = "saml/" + handle + "/init"
+	}
+
+	return tp
 }
 
 // UpdateSettings applies the app settings to the
@@ -51,4 +59,32 @@ func UpdateSettings(source *types.AppSettings, dest *settings.Settings) {
 	dest.Saml.IDP.IdentName = cas.External.Saml.IDP.IdentName
 	dest.Saml.IDP.IdentHandle = cas.External.Saml.IDP.IdentHandle
 	dest.Saml.IDP.IdentIdentifier = cas.External.Saml.IDP.IdentIdentifier
+	dest.Saml.IDP.RoleAttribute = cas.External.Saml.IDP.RoleAttribute
+	dest.Saml.IDP.RoleMapping = roleMapping(cas.External.Saml.IDP.RoleMapping)
+
+	dest.Saml.IDPs = nil
+	for _, idp := range cas.External.Saml.IDPs {
+		if idp.Handle == "" || idp.URL == "" {
+			continue
+		}
+
+		dest.Saml.IDPs = append(dest.Saml.IDPs, settings.SamlIDP{
+			Handle:          idp.Handle,
+			URL:             idp.URL,
+			Name:            idp.Name,
+			IdentName:       idp.IdentName,
+			IdentHandle:     idp.IdentHandle,
+			IdentIdentifier: idp.IdentIdentifier,
+			RoleAttribute:   idp.RoleAttribute,
+			RoleMapping:     roleMapping(idp.RoleMapping),
+		})
+	}
+}
+
+func roleMapping(mm []types.SamlRoleMapping) (out []settings.SamlRoleMapping) {
+	for _, m := range mm {
+		out = append(out, settings.SamlRoleMapping{Value: m.Value, Role: m.Role})
+	}
+
+	return
 }
@@ -10,6 +10,7 @@ func Test_TemplateProvider(t *testing.T) {
 	var (
 		tcc = []struct {
 			name   string
+			handle string
 			url    string
 			expect templateProvider
 		}{
@@ -31,6 +32,16 @@ func Test_TemplateProvider(t *testing.T) {
 					Icon:   "key",
 				},
 			},
+			{
+				name:   "Additional SAML provider",
+				handle: "partner",
+				url:    "http://partner.tld",
+				expect: templateProvider{
+					Label:  "Additional SAML provider",
+					Handle: "saml/partner/init",
+					Icon:   "key",
+				},
+			},
 		}
 	)
 
@@ -40,7 +51,7 @@ func Test_TemplateProvider(t *testing.T) {
 				req = require.New(t)
 			)
 
-			req.Equal(tc.expect, TemplateProvider(tc.url, tc.name))
+			req.Equal(tc.expect, TemplateProvider(tc.handle, tc.url, tc.name))
 		})
 	}
 }
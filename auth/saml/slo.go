@@ -0,0 +1,363 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// Single logout (SLO)
+//
+// Logout requests and responses from the identity provider are accepted
+// with HTTP-Redirect and HTTP-POST bindings and must be signed; either
+// with signature in the query string (HTTP-Redirect binding)
+// or with enveloped XML signature.
+
+const (
+	sigAlgRsaSha1   = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	sigAlgRsaSha256 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	sigAlgRsaSha512 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+
+	paramRequest  = "SAMLRequest"
+	paramResponse = "SAMLResponse"
+
+	// upper limit for the inflated logout message;
+	// protects the (unauthenticated) endpoint from decompression bombs
+	logoutMessageMaxSize = 256 << 10
+)
+
+// LogoutRequestURL creates URL for SP-initiated logout (HTTP-Redirect binding)
+//
+// NameID format is the same as the one requested in the authentication request.
+// Returns nil when identity provider does not support single logout
+func (ssp *SamlSPService) LogoutRequestURL(nameID, sessionIndex, relayState string) (*url.URL, error) {
+	var (
+		sp  = &ssp.handler.ServiceProvider
+		loc = sp.GetSLOBindingLocation(saml.HTTPRedirectBinding)
+	)
+
+	if loc == "" {
+		return nil, nil
+	}
+
+	req, err := sp.MakeLogoutRequest(loc, nameID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionIndex != "" {
+		req.SessionIndex = &saml.SessionIndex{Value: sessionIndex}
+	}
+
+	return req.Redirect(relayState), nil
+}
+
+// LogoutResponseURL creates URL with response to IdP-initiated logout (HTTP-Redirect binding)
+func (ssp *SamlSPService) LogoutResponseURL(requestID, relayState string) (*url.URL, error) {
+	var (
+		sp = &ssp.handler.ServiceProvider
+	)
+
+	if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) == "" {
+		return nil, fmt.Errorf("identity provider does not support single logout")
+	}
+
+	return sp.MakeRedirectLogoutResponse(requestID, relayState)
+}
+
+// IsLogoutRequest checks if request holds logout request (IdP-initiated logout)
+func IsLogoutRequest(r *http.Request) bool {
+	return r.URL.Query().Get(paramRequest) != "" || r.PostFormValue(paramRequest) != ""
+}
+
+// ParseLogoutRequest parses and validates logout request from the identity provider
+func (ssp *SamlSPService) ParseLogoutRequest(r *http.Request) (*saml.LogoutRequest, error) {
+	var (
+		sp  = &ssp.handler.ServiceProvider
+		req = &saml.LogoutRequest{}
+	)
+
+	raw, err := ssp.readLogoutMessage(r, paramRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = xml.Unmarshal(raw, req); err != nil {
+		return nil, fmt.Errorf("could not parse logout request: %w", err)
+	}
+
+	if err = ssp.validateLogoutMessage(req.Issuer, req.Destination, req.IssueInstant); err != nil {
+		return nil, err
+	}
+
+	if req.NameID == nil || req.NameID.Value == "" {
+		return nil, fmt.Errorf("logout request without NameID")
+	}
+
+	if req.NameID.NameQualifier != "" && req.NameID.NameQualifier != sp.IDPMetadata.EntityID {
+		return nil, fmt.Errorf("NameID qualifier does not match the IdP")
+	}
+
+	return req, nil
+}
+
+// ValidateLogoutResponse validates logout response from the identity provider (SP-initiated logout)
+func (ssp *SamlSPService) ValidateLogoutResponse(r *http.Request) error {
+	var (
+		rsp = &saml.LogoutResponse{}
+	)
+
+	raw, err := ssp.readLogoutMessage(r, paramResponse)
+	if err != nil {
+		return err
+	}
+
+	if err = xml.Unmarshal(raw, rsp); err != nil {
+		return fmt.Errorf("could not parse logout response: %w", err)
+	}
+
+	if err = ssp.validateLogoutMessage(rsp.Issuer, rsp.Destination, rsp.IssueInstant); err != nil {
+		return err
+	}
+
+	if rsp.Status.StatusCode.Value != saml.StatusSuccess {
+		return fmt.Errorf("logout failed with status %s", rsp.Status.StatusCode.Value)
+	}
+
+	return nil
+}
+
+// DeleteSession removes SAML session (cookie) so that
+// the next login goes through the identity provider again
+func (ssp *SamlSPService) DeleteSession(w http.ResponseWriter, r *http.Request) error {
+	return ssp.handler.Session.DeleteSession(w, r)
+}
+
+// validates issuer, destination and issue instant of the logout message
+func (ssp *SamlSPService) validateLogoutMessage(issuer *saml.Issuer, destination string, issuedAt time.Time) error {
+	var (
+		sp = &ssp.handler.ServiceProvider
+	)
+
+	if issuer == nil || issuer.Value != sp.IDPMetadata.EntityID {
+		return fmt.Errorf("issuer does not match the IdP metadata (expected %q)", sp.IDPMetadata.EntityID)
+	}
+
+	if destination != "" && destination != sp.SloURL.String() {
+		return fmt.Errorf("destination does not match SLO URL (expected %q)", sp.SloURL.String())
+	}
+
+	if issuedAt.Add(saml.MaxIssueDelay).Before(saml.TimeNow()) {
+		return fmt.Errorf("logout message expired")
+	}
+
+	return nil
+}
+
+// reads logout message from the request and verifies its signature
+//
+// With enveloped XML signature, only the signed (validated) element is returned
+// so that unsigned content can not be wrapped around it
+func (ssp *SamlSPService) readLogoutMessage(r *http.Request, param string) (raw []byte, err error) {
+	var (
+		certs []*x509.Certificate
+		query = rawQuery(r.URL.RawQuery)
+		doc   = etree.NewDocument()
+	)
+
+	if certs, err = ssp.idpSigningCerts(); err != nil {
+		return
+	}
+
+	if encoded, has := query[param]; has {
+		// HTTP-Redirect binding, deflated message
+		var value string
+		if value, err = url.QueryUnescape(encoded); err != nil {
+			return
+		}
+
+		if raw, err = base64.StdEncoding.DecodeString(value); err != nil {
+			return nil, fmt.Errorf("could not decode %s: %w", param, err)
+		}
+
+		inflated := io.LimitReader(flate.NewReader(bytes.NewReader(raw)), logoutMessageMaxSize+1)
+		if raw, err = ioutil.ReadAll(inflated); err != nil {
+			return nil, fmt.Errorf("could not inflate %s: %w", param, err)
+		}
+
+		if len(raw) > logoutMessageMaxSize {
+			return nil, fmt.Errorf("could not inflate %s: message too large", param)
+		}
+
+		if _, signed := query["Signature"]; signed {
+			return raw, verifyQuerySignature(query, param, certs)
+		}
+	} else {
+		// HTTP-POST binding
+		if raw, err = base64.StdEncoding.DecodeString(r.PostFormValue(param)); err != nil || len(raw) == 0 {
+			return nil, fmt.Errorf("could not decode %s", param)
+		}
+	}
+
+	if err = doc.ReadFromBytes(raw); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", param, err)
+	}
+
+	validated, err := verifyXMLSignature(doc.Root(), certs)
+	if err != nil {
+		return nil, err
+	}
+
+	doc = etree.NewDocument()
+	doc.SetRoot(validated)
+	return doc.WriteToBytes()
+}
+
+// returns certificates that identity provider uses for signing
+func (ssp *SamlSPService) idpSigningCerts() (cc []*x509.Certificate, err error) {
+	var (
+		md = ssp.handler.ServiceProvider.IDPMetadata
+	)
+
+	for _, d := range md.IDPSSODescriptors {
+		for _, kd := range d.KeyDescriptors {
+			if kd.Use != "" && kd.Use != "signing" {
+				continue
+			}
+
+			der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(kd.KeyInfo.Certificate), ""))
+			if err != nil {
+				return nil, fmt.Errorf("could not decode IdP certificate: %w", err)
+			}
+
+			c, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse IdP certificate: %w", err)
+			}
+
+			cc = append(cc, c)
+		}
+	}
+
+	if len(cc) == 0 {
+		return nil, fmt.Errorf("IdP metadata without signing certificate")
+	}
+
+	return
+}
+
+// verifies signature of the HTTP-Redirect binding
+//
+// Signature is calculated from the URL-encoded query parameters as they were sent
+// (SAML bindings, 3.4.4.1)
+func verifyQuerySignature(query map[string]string, param string, certs []*x509.Certificate) error {
+	var (
+		hash   crypto.Hash
+		signed = param + "=" + query[param]
+	)
+
+	if rs, has := query["RelayState"]; has {
+		signed += "&RelayState=" + rs
+	}
+
+	signed += "&SigAlg=" + query["SigAlg"]
+
+	sigAlg, err := url.QueryUnescape(query["SigAlg"])
+	if err != nil {
+		return err
+	}
+
+	switch sigAlg {
+	case sigAlgRsaSha1:
+		hash = crypto.SHA1
+	case sigAlgRsaSha256:
+		hash = crypto.SHA256
+	case sigAlgRsaSha512:
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sigAlg)
+	}
+
+	sig, err := url.QueryUnescape(query["Signature"])
+	if err != nil {
+		return err
+	}
+
+	rawSig, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("could not decode signature: %w", err)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	for _, c := range certs {
+		if pub, ok := c.PublicKey.(*rsa.PublicKey); ok && rsa.VerifyPKCS1v15(pub, hash, digest, rawSig) == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid signature")
+}
+
+// verifies enveloped XML signature of the message and returns the signed element
+func verifyXMLSignature(el *etree.Element, certs []*x509.Certificate) (*etree.Element, error) {
+	if el == nil || el.FindElement("./Signature") == nil {
+		return nil, fmt.Errorf("logout message is not signed")
+	}
+
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	ctx.IdAttribute = "ID"
+	if saml.Clock != nil {
+		ctx.Clock = saml.Clock
+	}
+
+	return ctx.Validate(el)
+}
+
+// splits raw query without unescaping the values
+func rawQuery(q string) map[string]string {
+	var (
+		out = make(map[string]string)
+	)
+
+	for _, kv := range strings.Split(q, "&") {
+		if kv == "" {
+			continue
+		}
+
+		var (
+			parts = strings.SplitN(kv, "=", 2)
+			value string
+		)
+
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+
+		if _, has := out[parts[0]]; !has {
+			out[parts[0]] = value
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,280 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdpEntityID = "https://idp.example.tld/metadata"
+	testIdpSloURL   = "https://idp.example.tld/slo"
+	testSpSloURL    = "https://corteza.example.tld/auth/external/saml/slo"
+)
+
+func Test_logoutRequest(t *testing.T) {
+	var (
+		idpKey, idpCert = makeTestKeyPair(t)
+		otherKey, _     = makeTestKeyPair(t)
+
+		ssp = makeTestSamlSPService(t, idpCert)
+
+		tcc = []struct {
+			name  string
+			query string
+			err   string
+		}{
+			{
+				name:  "signed request",
+				query: signedLogoutQuery(t, idpKey, makeTestLogoutRequest(testIdpEntityID)),
+			},
+			{
+				name:  "signed with unknown key",
+				query: signedLogoutQuery(t, otherKey, makeTestLogoutRequest(testIdpEntityID)),
+				err:   "invalid signature",
+			},
+			{
+				name:  "unsigned request",
+				query: unsignedLogoutQuery(t, makeTestLogoutRequest(testIdpEntityID)),
+				err:   "logout message is not signed",
+			},
+			{
+				name:  "request from another issuer",
+				query: signedLogoutQuery(t, idpKey, makeTestLogoutRequest("https://other.example.tld")),
+				err:   "issuer does not match the IdP metadata",
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				r   = &http.Request{URL: &url.URL{Path: "/slo", RawQuery: tc.query}}
+			)
+
+			req.True(IsLogoutRequest(r))
+
+			lr, err := ssp.ParseLogoutRequest(r)
+			if tc.err != "" {
+				req.Error(err)
+				req.Contains(err.Error(), tc.err)
+				return
+			}
+
+			req.NoError(err)
+			req.Equal("user@example.tld", lr.NameID.Value)
+			req.Equal("session-index", lr.SessionIndex.Value)
+		})
+	}
+}
+
+func Test_logoutRequestTooLarge(t *testing.T) {
+	var (
+		req             = require.New(t)
+		idpKey, idpCert = makeTestKeyPair(t)
+		ssp             = makeTestSamlSPService(t, idpCert)
+
+		lr = makeTestLogoutRequest(testIdpEntityID)
+	)
+
+	// highly compressible payload that inflates over the limit
+	lr.NameID.Value = strings.Repeat("a", logoutMessageMaxSize)
+
+	r := &http.Request{URL: &url.URL{Path: "/slo", RawQuery: signedLogoutQuery(t, idpKey, lr)}}
+	_, err := ssp.ParseLogoutRequest(r)
+	req.Error(err)
+	req.Contains(err.Error(), "message too large")
+}
+
+func Test_logoutRequestXMLSignature(t *testing.T) {
+	var (
+		req             = require.New(t)
+		idpKey, idpCert = makeTestKeyPair(t)
+		ssp             = makeTestSamlSPService(t, idpCert)
+
+		post = func(doc *etree.Document) *http.Request {
+			raw, err := doc.WriteToBytes()
+			req.NoError(err)
+
+			r := &http.Request{
+				Method: http.MethodPost,
+				URL:    &url.URL{Path: "/slo"},
+				Form:   url.Values{paramRequest: {base64.StdEncoding.EncodeToString(raw)}},
+			}
+			r.PostForm = r.Form
+			return r
+		}
+	)
+
+	signed, err := dsig.NewDefaultSigningContext(&testKeyStore{key: idpKey, cert: idpCert}).
+		SignEnveloped(makeTestLogoutRequest(testIdpEntityID).Element())
+	req.NoError(err)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(signed)
+
+	lr, err := ssp.ParseLogoutRequest(post(doc))
+	req.NoError(err)
+	req.Equal("user@example.tld", lr.NameID.Value)
+
+	// modified content is not covered by the signature
+	doc.Root().FindElement("./NameID").SetText("admin@example.tld")
+	_, err = ssp.ParseLogoutRequest(post(doc))
+	req.Error(err)
+}
+
+func Test_logoutURLs(t *testing.T) {
+	var (
+		req        = require.New(t)
+		_, idpCert = makeTestKeyPair(t)
+		ssp        = makeTestSamlSPService(t, idpCert)
+	)
+
+	u, err := ssp.LogoutRequestURL("user@example.tld", "session-index", "")
+	req.NoError(err)
+	req.NotNil(u)
+	req.Equal("idp.example.tld", u.Host)
+	req.NotEmpty(u.Query().Get(paramRequest))
+
+	u, err = ssp.LogoutResponseURL("request-id", "relay")
+	req.NoError(err)
+	req.NotEmpty(u.Query().Get(paramResponse))
+	req.Equal("relay", u.Query().Get("RelayState"))
+
+	// identity provider without single logout support
+	ssp.handler.ServiceProvider.IDPMetadata.IDPSSODescriptors[0].SingleLogoutServices = nil
+
+	u, err = ssp.LogoutRequestURL("user@example.tld", "session-index", "")
+	req.NoError(err)
+	req.Nil(u)
+}
+
+func makeTestKeyPair(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.tld"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return key, cert
+}
+
+func makeTestSamlSPService(t *testing.T, idpCert *x509.Certificate) *SamlSPService {
+	var (
+		spKey, spCert = makeTestKeyPair(t)
+		sloURL, _     = url.Parse(testSpSloURL)
+		mdURL, _      = url.Parse("https://corteza.example.tld/auth/external/saml/metadata")
+	)
+
+	md := &saml.EntityDescriptor{
+		EntityID: testIdpEntityID,
+		IDPSSODescriptors: []saml.IDPSSODescriptor{{
+			SSODescriptor: saml.SSODescriptor{
+				RoleDescriptor: saml.RoleDescriptor{
+					KeyDescriptors: []saml.KeyDescriptor{{
+						Use: "signing",
+						KeyInfo: saml.KeyInfo{
+							Certificate: base64.StdEncoding.EncodeToString(idpCert.Raw),
+						},
+					}},
+				},
+				SingleLogoutServices: []saml.Endpoint{{
+					Binding:  saml.HTTPRedirectBinding,
+					Location: testIdpSloURL,
+				}},
+			},
+		}},
+	}
+
+	return &SamlSPService{
+		handler: &samlsp.Middleware{
+			ServiceProvider: saml.ServiceProvider{
+				Key:         spKey,
+				Certificate: spCert,
+				IDPMetadata: md,
+				MetadataURL: *mdURL,
+				SloURL:      *sloURL,
+			},
+		},
+	}
+}
+
+func makeTestLogoutRequest(issuer string) *saml.LogoutRequest {
+	return &saml.LogoutRequest{
+		ID:           "id-logout-request",
+		Version:      "2.0",
+		IssueInstant: saml.TimeNow(),
+		Destination:  testSpSloURL,
+		Issuer:       &saml.Issuer{Value: issuer},
+		NameID:       &saml.NameID{Value: "user@example.tld"},
+		SessionIndex: &saml.SessionIndex{Value: "session-index"},
+	}
+}
+
+func unsignedLogoutQuery(t *testing.T, lr *saml.LogoutRequest) string {
+	var (
+		buf = &bytes.Buffer{}
+		doc = etree.NewDocument()
+	)
+
+	doc.SetRoot(lr.Element())
+
+	w, err := flate.NewWriter(buf, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = doc.WriteTo(w)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return paramRequest + "=" + url.QueryEscape(base64.StdEncoding.EncodeToString(buf.Bytes())) +
+		"&RelayState=" + url.QueryEscape("relay state")
+}
+
+func signedLogoutQuery(t *testing.T, key *rsa.PrivateKey, lr *saml.LogoutRequest) string {
+	q := unsignedLogoutQuery(t, lr) + "&SigAlg=" + url.QueryEscape(sigAlgRsaSha256)
+
+	digest := sha256.Sum256([]byte(q))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return q + "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(sig))
+}
+
+type (
+	testKeyStore struct {
+		key  *rsa.PrivateKey
+		cert *x509.Certificate
+	}
+)
+
+func (ks *testKeyStore) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
+	return ks.key, ks.cert.Raw, nil
+}
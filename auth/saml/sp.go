@@ -13,20 +13,38 @@ import (
 	"github.com/pkg/errors"
 )
 
-const defaultNameIdentifier = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+const (
+	defaultNameIdentifier = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+
+	// provider (credentials kind) of the default identity provider,
+	// additional identity providers use "saml:<handle>"
+	defaultProvider = "saml"
+
+	sessionCookieName = "token"
+)
 
 type (
 	SamlSPService struct {
+		// Identity provider handle, empty for the default one
+		Handle string
+
 		IdpURL      url.URL
 		Host        url.URL
 		IDPUserMeta *IdpIdentityPayload
 		IDPMeta     *saml.EntityDescriptor
 
+		// assertion attribute and mapping of its values to roles
+		roleAttribute string
+		roleMapping   []RoleMapping
+
 		sp      saml.ServiceProvider
 		handler *samlsp.Middleware
 	}
 
 	SamlSPArgs struct {
+		// Identity provider handle, empty for the default one
+		Handle string
+
 		AcsURL  string
 		MetaURL string
 		SloURL  string
@@ -34,6 +52,10 @@ type (
 		// user meta from idp
 		IdentityPayload IdpIdentityPayload
 
+		// assertion attribute with values that are mapped to roles
+		RoleAttribute string
+		RoleMapping   []RoleMapping
+
 		IdpURL  url.URL
 		Host    url.URL
 		Cert    tls.Certificate
@@ -86,10 +108,28 @@ func NewSamlSPService(args SamlSPArgs) (s *SamlSPService, err error) {
 	handler.RequestTracker = samlsp.DefaultRequestTracker(opts, &handler.ServiceProvider)
 	handler.ServiceProvider = sp
 
+	// each additional identity provider has its own session cookie
+	// and sessions issued for one are not accepted by the others
+	if args.Handle != "" {
+		opts.CookieName = sessionCookieName + "-" + args.Handle
+
+		codec := samlsp.DefaultSessionCodec(opts)
+		codec.Audience = sp.MetadataURL.String()
+
+		session := samlsp.DefaultSessionProvider(opts)
+		session.Codec = codec
+		handler.Session = session
+	}
+
 	s = &SamlSPService{
+		Handle: args.Handle,
+
 		sp:      sp,
 		handler: handler,
 
+		roleAttribute: args.RoleAttribute,
+		roleMapping:   args.RoleMapping,
+
 		IdpURL:      args.IdpURL,
 		Host:        args.Host,
 		IDPUserMeta: &args.IdentityPayload,
@@ -98,6 +138,36 @@ func NewSamlSPService(args SamlSPArgs) (s *SamlSPService, err error) {
 	return
 }
 
+// Provider returns provider name that is used for external credentials
+func (ssp *SamlSPService) Provider() string {
+	if ssp.Handle == "" {
+		return defaultProvider
+	}
+
+	return defaultProvider + ":" + ssp.Handle
+}
+
+// MappedRoles resolves memberships of the mapped roles from the assertion attributes
+//
+// Returns role handles with flag if user should be a member;
+// nil when there is no role mapping configured
+func (ssp *SamlSPService) MappedRoles(payload map[string][]string) map[string]bool {
+	if ssp.roleAttribute == "" || len(ssp.roleMapping) == 0 {
+		return nil
+	}
+
+	var (
+		rr = make(map[string]bool)
+		vv = payload[ssp.roleAttribute]
+	)
+
+	for _, m := range ssp.roleMapping {
+		rr[m.Role] = rr[m.Role] || hasValue(vv, m.Value)
+	}
+
+	return rr
+}
+
 func (ssp *SamlSPService) NameIdentifier() string {
 	return strings.TrimPrefix(defaultNameIdentifier, "urn:oasis:names:tc:SAML:1.1:nameid-format:")
 }
@@ -132,3 +202,13 @@ func (ssp *SamlSPService) Handler() *samlsp.Middleware {
 func (ssp SamlSPService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ssp.handler.ServeHTTP(w, r)
 }
+
+func hasValue(vv []string, v string) bool {
+	for _, c := range vv {
+		if strings.EqualFold(c, v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -69,3 +69,48 @@ func Test_guessIdentifier(t *testing.T) {
 		})
 	}
 }
+
+func Test_mappedRoles(t *testing.T) {
+	var (
+		sp = SamlSPService{
+			roleAttribute: "groups",
+			roleMapping: []RoleMapping{
+				{Value: "admins", Role: "admins"},
+				{Value: "developers", Role: "developers"},
+				{Value: "ops", Role: "developers"},
+			},
+		}
+
+		tcc = []struct {
+			name    string
+			payload map[string][]string
+			expect  map[string]bool
+		}{
+			{
+				name:    "no values",
+				payload: map[string][]string{},
+				expect:  map[string]bool{"admins": false, "developers": false},
+			},
+			{
+				name:    "case-insensitive values",
+				payload: map[string][]string{"groups": {"Admins"}},
+				expect:  map[string]bool{"admins": true, "developers": false},
+			},
+			{
+				name:    "multiple values mapped to the same role",
+				payload: map[string][]string{"groups": {"ops", "other"}},
+				expect:  map[string]bool{"admins": false, "developers": true},
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, sp.MappedRoles(tc.payload))
+		})
+	}
+
+	t.Run("without mapping", func(t *testing.T) {
+		require.Nil(t, (&SamlSPService{}).MappedRoles(map[string][]string{"groups": {"admins"}}))
+	})
+}
@@ -11,7 +11,9 @@ type (
 		SplitCredentialsCheck     bool
 		Providers                 []Provider
 		Saml                      SAML
+		LDAP                      LDAP
 		MultiFactor               MultiFactor
+		PersonalAccessTokens      PersonalAccessTokens
 	}
 
 	SAML struct {
@@ -23,21 +25,45 @@ type (
 		// SAML certificate private key
 		Key string
 
+		// Default identity provider
+		IDP SamlIDP
+
+		// Additional identity providers
+		IDPs []SamlIDP
+	}
+
+	SamlIDP struct {
+		// Empty for the default identity provider
+		Handle string
+
 		// Identity provider hostname
-		IDP struct {
-			URL  string
-			Name string
-
-			// identifier payload from idp
-			IdentName       string
-			IdentHandle     string
-			IdentIdentifier string
-		}
+		URL  string
+		Name string
+
+		// identifier payload from idp
+		IdentName       string
+		IdentHandle     string
+		IdentIdentifier string
+
+		// Assertion attribute with values that are mapped to roles
+		RoleAttribute string
+		RoleMapping   []SamlRoleMapping
+	}
+
+	SamlRoleMapping struct {
+		Value string
+		Role  string
+	}
+
+	LDAP struct {
+		// Can users login with LDAP credentials?
+		Enabled bool
 	}
 
 	MultiFactor struct {
 		EmailOTP EmailOTP
 		TOTP     TOTP
+		WebAuthn WebAuthn
 	}
 
 	EmailOTP struct {
@@ -59,6 +85,29 @@ type (
 		Issuer string
 	}
 
+	WebAuthn struct {
+		// Can users use security keys for MFA?
+		Enabled bool
+
+		// Is MFA with security keys enforced?
+		Enforced bool
+
+		// Can users login with security key without password?
+		Passwordless bool
+
+		// Relying party ID and name
+		RelyingPartyID   string
+		RelyingPartyName string
+	}
+
+	PersonalAccessTokens struct {
+		// Can users create personal access tokens?
+		Enabled bool
+
+		// Max number of days until token expires
+		MaxLifetime uint
+	}
+
 	Provider struct {
 		Handle      string
 		Label       string
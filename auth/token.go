@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"github.com/cortezaproject/corteza-server/auth/oauth2"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
 )
@@ -24,3 +25,13 @@ func (svc tokenService) DeleteByID(ctx context.Context, ID uint64) error {
 func (svc tokenService) DeleteByUserID(ctx context.Context, userID uint64) error {
 	return svc.store.DeleteAuthOA2TokenByUserID(ctx, userID)
 }
+
+// NonceByCode returns nonce stored with the authorization code
+func (svc tokenService) NonceByCode(ctx context.Context, code string) (string, error) {
+	t, err := svc.store.LookupAuthOa2tokenByCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2.CodeNonce(t.Data), nil
+}
@@ -19,6 +19,12 @@ type (
 	}
 )
 
+// Resolve returns the value as-is; workflow oauth2 params
+// do not support secret references yet
+func (secureStoragerTodo) Resolve(_ context.Context, v string) (string, error) {
+	return v, nil
+}
+
 func Oauth2Handler(reg oauth2HandlerRegistry) *oauth2Handler {
 	h := &oauth2Handler{
 		reg: reg,
@@ -10,7 +10,7 @@ import (
 func MountRoutes(r chi.Router) {
 	// Protect all _private_ routes
 	r.Group(func(r chi.Router) {
-		r.Use(auth.MiddlewareValidOnly)
+		r.Use(auth.ComponentAccessTokenCheck("automation"))
 
 		handlers.NewWorkflow(Workflow{}.New()).MountRoutes(r)
 		handlers.NewTrigger(Trigger{}.New()).MountRoutes(r)
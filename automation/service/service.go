@@ -96,6 +96,7 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 	DefaultTrigger = Trigger(DefaultLogger.Named("trigger"), c.Workflow)
 
 	DefaultWorkflow.triggers = DefaultTrigger
+	DefaultSession.workflow = DefaultWorkflow
 
 	Registry().AddTypes(
 		&expr.Any{},
@@ -133,6 +134,10 @@ func Activate(ctx context.Context) (err error) {
 		return
 	}
 
+	if err = DefaultSession.resumeAll(ctx); err != nil {
+		return
+	}
+
 	return
 }
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/sentry"
 	"github.com/cortezaproject/corteza-server/pkg/wfexec"
@@ -32,6 +34,9 @@ type (
 		pool         map[uint64]*types.Session
 		spawnQueue   chan *spawn
 		promptSender promptSender
+
+		// resolves graphs of workflows when suspended sessions are restored
+		workflow workflowGraphResolver
 	}
 
 	spawn struct {
@@ -39,6 +44,13 @@ type (
 		session    chan *wfexec.Session
 		graph      *wfexec.Graph
 		trace      bool
+
+		// when set, spawned session reuses the ID of the session that is restored
+		sessionID uint64
+	}
+
+	workflowGraphResolver interface {
+		graph(ctx context.Context, workflowID uint64) (*wfexec.Graph, error)
 	}
 
 	sessionAccessController interface {
@@ -111,25 +123,112 @@ func (svc *session) LookupByID(ctx context.Context, sessionID uint64) (res *type
 	return res, svc.recordAction(ctx, sap, SessionActionLookup, err)
 }
 
+// resumeAll loads all suspended (delayed or prompted) sessions from the store
+// and restores them into the pool so that they can survive server restarts
+//
+// Sessions that can not be restored (workflow removed or changed) are marked as failed
 func (svc *session) resumeAll(ctx context.Context) error {
-	// In theory we could resume active/pending/prompt sessions from persistent store
-	// so that they can survive server termination
+	if svc.workflow == nil {
+		return nil
+	}
+
+	ss, _, err := store.SearchAutomationSessions(ctx, svc.store, types.SessionFilter{
+		Status:    []uint{uint(types.SessionPrompted), uint(types.SessionSuspended)},
+		Completed: filter.StateExcluded,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, ses := range ss {
+		log := svc.log.With(zap.Uint64("sessionID", ses.ID), zap.Uint64("workflowID", ses.WorkflowID))
+
+		if err = svc.restore(ctx, ses); err == nil {
+			log.Debug("session resumed", zap.Int("states", len(ses.SuspendedStates)))
+			continue
+		}
+
+		log.Warn("could not resume session", zap.Error(err))
+
+		ses.SuspendedStates = nil
+		ses.SuspendedAt = nil
+		ses.CompletedAt = now()
+		ses.Error = fmt.Sprintf("could not resume session: %v", err)
+		ses.Status = types.SessionFailed
+
+		if err = store.UpsertAutomationSession(ctx, svc.store, ses); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restore spawns new workflow session with the same ID as the stored one and restores its suspended states
+func (svc *session) restore(ctx context.Context, stored *types.Session) (err error) {
+	if len(stored.SuspendedStates) == 0 {
+		return fmt.Errorf("no suspended states")
+	}
+
+	g, err := svc.workflow.graph(ctx, stored.WorkflowID)
+	if err != nil {
+		return err
+	}
+
+	if err = stored.SuspendedStates.ResolveTypes(Registry().Type); err != nil {
+		return err
+	}
+
+	s := &spawn{
+		workflowID: stored.WorkflowID,
+		sessionID:  stored.ID,
+		session:    make(chan *wfexec.Session, 1),
+		graph:      g,
+	}
+
+	svc.spawnQueue <- s
+	wfs := <-s.session
+
+	ses, err := types.RestoreSession(stored, wfs)
+	if err != nil {
+		wfs.Stop()
+		return err
+	}
 
-	// @todo resume active sessions from storage
-	//       load all active sessions from store and load them into the pool
-	//
+	svc.mux.Lock()
+	svc.pool[ses.ID] = ses
+	svc.mux.Unlock()
 	return nil
 }
 
+// suspendAll flushes all sessions from the pool to the store
+//
+// Delayed and prompted states are serialized so that the sessions
+// can be resumed (see resumeAll) when server is started again.
+// Active states (steps being executed at the moment) can not be preserved.
 func (svc *session) suspendAll(ctx context.Context) error {
-	// In theory we could suspend active/pending/prompt sessions to persistent store
-	// so that they can survive server termination
-
-	// @todo suspend active sessions to storage:
-	//       stop watcher queue
-	//       run gc
-	//       stop worker on each session
-	//       flush session to store (like we're doing in the status handler
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+
+	for _, ses := range svc.pool {
+		if ses.GC() {
+			continue
+		}
+
+		log := svc.log.With(zap.Uint64("sessionID", ses.ID))
+
+		if err := ses.Suspend(); err != nil {
+			log.Warn("could not serialize suspended states", zap.Error(err))
+		}
+
+		if err := svc.store.UpsertAutomationSession(ctx, ses); err != nil {
+			return err
+		}
+
+		log.Debug("session suspended", zap.Int("states", len(ses.SuspendedStates)))
+	}
+
 	return nil
 }
 
@@ -262,6 +361,13 @@ func (svc *session) Watch(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
+				// serialize sessions & suspended states
+				//
+				// using new context since the one we got is already canceled
+				if err := svc.suspendAll(context.Background()); err != nil {
+					svc.log.Error("failed to suspend sessions", zap.Error(err))
+				}
+
 				return
 			case s := <-svc.spawnQueue:
 				opts := []wfexec.SessionOpt{
@@ -269,6 +375,10 @@ func (svc *session) Watch(ctx context.Context) {
 					wfexec.SetHandler(svc.stateChangeHandler(ctx)),
 				}
 
+				if s.sessionID > 0 {
+					opts = append(opts, wfexec.SetSessionID(s.sessionID))
+				}
+
 				if svc.opt.ExecDebug {
 					opts = append(
 						opts,
@@ -288,9 +398,6 @@ func (svc *session) Watch(ctx context.Context) {
 				svc.logPending()
 			}
 		}
-
-		// @todo serialize sessions & suspended states
-		//svc.suspendAll(ctx)
 	}()
 
 	svc.log.Debug("watcher initialized")
@@ -418,6 +525,12 @@ func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHa
 
 		ses.CopyRuntimeStacktrace()
 
+		if i == wfexec.SessionCompleted || i == wfexec.SessionFailed {
+			ses.ClearSuspended()
+		} else if err := ses.Suspend(); err != nil {
+			log.Warn("could not serialize suspended states", zap.Error(err))
+		}
+
 		if err := svc.store.UpsertAutomationSession(ctx, ses); err != nil {
 			log.Error("failed to update session", zap.Error(err))
 		} else {
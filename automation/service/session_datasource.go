@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/spf13/cast"
+)
+
+// Datasource provides workflow sessions to the report
+//
+// Definition supports filtering by workflowID, eventType, resourceType
+// and the completed state; completed sessions are included by default.
+func (svc *session) Datasource(ctx context.Context, ld *report.LoadStepDefinition) (report.Datasource, error) {
+	var (
+		f = types.SessionFilter{
+			Completed: filter.StateInclusive,
+		}
+		def = ld.Definition
+	)
+
+	if v, ok := def["workflowID"]; ok {
+		id, err := cast.ToUint64E(v)
+		if err != nil {
+			return nil, err
+		}
+		f.WorkflowID = []uint64{id}
+	}
+	if v, ok := def["eventType"]; ok {
+		f.EventType = cast.ToString(v)
+	}
+	if v, ok := def["resourceType"]; ok {
+		f.ResourceType = cast.ToString(v)
+	}
+	if v, ok := def["completed"]; ok {
+		s, err := cast.ToUintE(v)
+		if err != nil {
+			return nil, err
+		}
+		f.Completed = filter.State(s)
+	}
+
+	cols := report.FrameColumnSet{
+		sessionColumn("ID", "id", "Session ID"),
+		sessionColumn("ID", "workflowID", "Workflow ID"),
+		sessionColumn("String", "status", "Status"),
+		sessionColumn("String", "eventType", "Event type"),
+		sessionColumn("String", "resourceType", "Resource type"),
+		sessionColumn("String", "error", "Error"),
+		sessionColumn("DateTime", "createdAt", "Created at"),
+		sessionColumn("User", "createdBy", "Created by"),
+		sessionColumn("DateTime", "suspendedAt", "Suspended at"),
+		sessionColumn("DateTime", "completedAt", "Completed at"),
+		sessionColumn("DateTime", "purgeAt", "Purge at"),
+	}
+	cols[0].System = true
+	cols[0].Primary = true
+	cols[0].Unique = true
+
+	return report.MemoryDatasource(ld, cols, func(ctx context.Context) ([]map[string]interface{}, error) {
+		ss, _, err := svc.Search(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]map[string]interface{}, len(ss))
+		for i, s := range ss {
+			out[i] = map[string]interface{}{
+				"id":           s.ID,
+				"workflowID":   s.WorkflowID,
+				"status":       s.Status.String(),
+				"eventType":    s.EventType,
+				"resourceType": s.ResourceType,
+				"error":        s.Error,
+				"createdAt":    s.CreatedAt,
+				"createdBy":    s.CreatedBy,
+				"suspendedAt":  s.SuspendedAt,
+				"completedAt":  s.CompletedAt,
+				"purgeAt":      s.PurgeAt,
+			}
+		}
+
+		return out, nil
+	})
+}
+
+func sessionColumn(kind, name, label string) *report.FrameColumn {
+	c := report.MakeColumnOfKind(kind)
+	c.Name = name
+	c.Label = label
+	return c
+}
@@ -479,6 +479,27 @@ func (svc *workflow) Load(ctx context.Context) error {
 	return svc.triggers.registerWorkflows(ctx, wwf...)
 }
 
+// graph loads workflow and converts it to graph
+//
+// Used when restoring suspended sessions
+func (svc *workflow) graph(ctx context.Context, workflowID uint64) (*wfexec.Graph, error) {
+	wf, err := loadWorkflow(ctx, svc.store, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wf.DeletedAt != nil {
+		return nil, WorkflowErrNotFound()
+	}
+
+	g, issues := Convert(svc, wf)
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	return g, nil
+}
+
 func (svc *workflow) Exec(ctx context.Context, workflowID uint64, p types.WorkflowExecParams) (*expr.Vars, types.Stacktrace, error) {
 	var (
 		runAs   intAuth.Identifiable
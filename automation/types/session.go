@@ -49,6 +49,11 @@ type (
 		// the whole stacktrace
 		RuntimeStacktrace Stacktrace `json:"-"`
 
+		// Delayed and prompted states, serialized when session is suspended
+		//
+		// Used to restore the session after server restart
+		SuspendedStates SuspendedStateSet `json:"-"`
+
 		l sync.RWMutex
 	}
 
@@ -85,6 +90,8 @@ type (
 
 	Stacktrace []*wfexec.Frame
 
+	SuspendedStateSet []*wfexec.SuspendedState
+
 	SessionStatus uint
 )
 
@@ -103,6 +110,18 @@ func NewSession(s *wfexec.Session) *Session {
 	}
 }
 
+// RestoreSession links stored session with a new workflow session
+// and restores all suspended states on it
+func RestoreSession(stored *Session, s *wfexec.Session) (*Session, error) {
+	if err := s.RestoreSuspended(stored.SuspendedStates...); err != nil {
+		return nil, err
+	}
+
+	stored.session = s
+	stored.RuntimeStacktrace = stored.Stacktrace
+	return stored, nil
+}
+
 func (s *Session) Exec(ctx context.Context, step wfexec.Step, input *expr.Vars) error {
 	return s.session.Exec(ctx, step, input)
 }
@@ -115,6 +134,30 @@ func (s *Session) PendingPrompts(ownerId uint64) []*wfexec.PendingPrompt {
 	return s.session.UserPendingPrompts(ownerId)
 }
 
+// Suspend serializes all delayed and prompted states of the session
+//
+// Previously serialized states are kept when serialization fails
+func (s *Session) Suspend() error {
+	ss, err := s.session.SuspendedStates()
+	if err != nil {
+		return err
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.SuspendedStates = ss
+	return nil
+}
+
+// ClearSuspended removes serialized states when session is completed or failed
+func (s *Session) ClearSuspended() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.SuspendedStates = nil
+}
+
 func (s *Session) GC() bool {
 	s.l.RLock()
 	defer s.l.RUnlock()
@@ -123,11 +166,15 @@ func (s *Session) GC() bool {
 }
 
 // WaitResults wait blocks until workflow session is completed or fails (or context is canceled) and returns resuts
+//
+// Session is not locked while waiting so that the state changes can be recorded
 func (s *Session) WaitResults(ctx context.Context) (*expr.Vars, wfexec.SessionStatus, Stacktrace, error) {
+	err := s.session.WaitUntil(ctx, wfexec.SessionFailed, wfexec.SessionCompleted)
+
 	s.l.RLock()
 	defer s.l.RUnlock()
 
-	if err := s.session.WaitUntil(ctx, wfexec.SessionFailed, wfexec.SessionCompleted); err != nil {
+	if err != nil {
 		return nil, -1, s.Stacktrace, err
 	}
 
@@ -192,6 +239,44 @@ func (set Stacktrace) Value() (driver.Value, error) {
 	return json.Marshal(set)
 }
 
+func (set *SuspendedStateSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*set = SuspendedStateSet{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, set); err != nil {
+			return fmt.Errorf("cannot scan '%v' into SuspendedStateSet: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (set SuspendedStateSet) Value() (driver.Value, error) {
+	return json.Marshal(set)
+}
+
+// ResolveTypes resolves types of all variables in scopes & payloads of suspended states
+func (set SuspendedStateSet) ResolveTypes(res func(typ string) expr.Type) (err error) {
+	for _, s := range set {
+		if err = s.Scope.ResolveTypes(res); err != nil {
+			return
+		}
+
+		if s.Prompt == nil {
+			continue
+		}
+
+		if err = s.Prompt.Payload.ResolveTypes(res); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
 func (s SessionStatus) String() string {
 	switch s {
 	case SessionStarted:
@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/cli"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/logger"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/spf13/cobra"
+)
+
+func Records(ctx context.Context, storeInit func(ctx context.Context) (store.Storer, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "records",
+		Aliases: []string{"record"},
+		Short:   "Compose record tools",
+	}
+
+	cmd.AddCommand(
+		recordsPartition(ctx, storeInit),
+		recordsRecalculate(ctx, storeInit),
+	)
+
+	return cmd
+}
+
+func recordsPartition(ctx context.Context, storeInit func(ctx context.Context) (store.Storer, error)) *cobra.Command {
+	var (
+		table    string
+		physical []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "partition [namespace-ID-or-slug] [module-ID-or-handle]",
+		Short: "Move module records into a dedicated table",
+		Long: "Enables record partitioning on the module and moves all existing records\n" +
+			"(with values) from the shared record tables into a dedicated table.",
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				s, err = storeInit(ctx)
+				m      *types.Module
+				n      int
+			)
+
+			cli.HandleError(err)
+
+			m, err = findModule(ctx, s, args[0], args[1])
+			cli.HandleError(err)
+
+			if m.Config.RecordPartition.Enabled {
+				cli.HandleError(fmt.Errorf("records of module %q are already partitioned", m.Handle))
+			}
+
+			p, err := partitionedModule(m, table, physical)
+			cli.HandleError(err)
+
+			// DDL statements are committed implicitly by some databases;
+			// table is created before records are moved
+			cli.HandleError(store.UpgradeComposeRecordPartition(ctx, s, p))
+
+			err = store.Tx(ctx, s, func(ctx context.Context, s store.Storer) (err error) {
+				n, err = partitionRecords(ctx, s, m, p)
+				return
+			})
+
+			cli.HandleError(err)
+
+			cmd.Printf("Moved %d records of module [%d] %q to %s\n", n, m.ID, m.Handle, m.RecordPartitionTable())
+		},
+	}
+
+	cmd.Flags().StringVar(&table, "table", "", "Name of the dedicated table (default compose_record_<moduleID>)")
+	cmd.Flags().StringSliceVar(&physical, "physical-column", nil, "Store values of the (single-value) field in a physical column. Can be used multiple times")
+
+	return cmd
+}
+
+func recordsRecalculate(ctx context.Context, storeInit func(ctx context.Context) (store.Storer, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recalculate [namespace-ID-or-slug] [module-ID-or-handle]",
+		Short: "Recalculate computed values on existing records",
+		Long: "Recalculates lookup, rollup and expression values on all records of the module\n" +
+			"(or all modules in the namespace with computed fields when module is omitted)\n" +
+			"and propagates changes to the records that depend on them.",
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				s, err = storeInit(ctx)
+				mm     types.ModuleSet
+				m      *types.Module
+				rr     types.RecordSet
+				n      int
+			)
+
+			cli.HandleError(err)
+
+			if len(args) == 2 {
+				m, err = findModule(ctx, s, args[0], args[1])
+				cli.HandleError(err)
+				mm = types.ModuleSet{m}
+			} else {
+				mm, err = findModules(ctx, s, args[0])
+				cli.HandleError(err)
+			}
+
+			rc := service.RecordComputed(s, logger.Default())
+
+			for _, m = range mm {
+				if !hasComputedFields(m) {
+					continue
+				}
+
+				rr, _, err = store.SearchComposeRecords(ctx, s, m, types.RecordFilter{})
+				cli.HandleError(err)
+
+				n, err = rc.Recalculate(ctx, m, rr...)
+				cli.HandleError(err)
+
+				cmd.Printf("Recalculated %d of %d records of module [%d] %q\n", n, len(rr), m.ID, m.Handle)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// partitionedModule returns copy of the module with enabled record partitioning
+// and values of the given fields stored in physical columns
+func partitionedModule(m *types.Module, table string, physical []string) (*types.Module, error) {
+	var (
+		p = m.Clone()
+	)
+
+	p.Config.RecordPartition = types.ModuleConfigRecordPartition{Enabled: true, Table: table}
+
+	for _, name := range physical {
+		f := p.Fields.FindByName(name)
+		if f == nil {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+
+		if f.Multi {
+			return nil, fmt.Errorf("multi-value field %q can not be stored in a physical column", name)
+		}
+
+		if f.Options == nil {
+			f.Options = types.ModuleFieldOptions{}
+		}
+
+		f.Options.SetIsPhysicalColumn(true)
+	}
+
+	return p, nil
+}
+
+// partitionRecords moves all records of the module into the dedicated table
+// of the partitioned module and updates the module and its fields
+func partitionRecords(ctx context.Context, s store.Storer, m, p *types.Module) (int, error) {
+	var (
+		rr  types.RecordSet
+		err error
+	)
+
+	// Load all records, including deleted ones, from the shared tables
+	if rr, _, err = store.SearchComposeRecords(ctx, s, m, types.RecordFilter{Deleted: filter.StateInclusive}); err != nil {
+		return 0, err
+	}
+
+	if err = store.UpdateComposeModuleField(ctx, s, p.Fields...); err != nil {
+		return 0, err
+	}
+
+	if err = store.CreateComposeRecord(ctx, s, p, rr...); err != nil {
+		return 0, err
+	}
+
+	if err = store.DeleteComposeRecord(ctx, s, m, rr...); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	p.UpdatedAt = &now
+	if err = store.UpdateComposeModule(ctx, s, p); err != nil {
+		return 0, err
+	}
+
+	*m = *p
+	return len(rr), nil
+}
+
+// hasComputedFields checks if any of the module fields has computed value
+func hasComputedFields(m *types.Module) bool {
+	for _, f := range m.Fields {
+		if f.Expressions.IsComputed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findNamespace finds namespace by ID or slug
+func findNamespace(ctx context.Context, s store.Storer, nsIdent string) (ns *types.Namespace, err error) {
+	if ID, _ := strconv.ParseUint(nsIdent, 10, 64); ID > 0 {
+		ns, err = store.LookupComposeNamespaceByID(ctx, s, ID)
+	} else {
+		ns, err = store.LookupComposeNamespaceBySlug(ctx, s, nsIdent)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not find namespace %q: %w", nsIdent, err)
+	}
+
+	return
+}
+
+// findModules finds all modules (with fields) on namespace
+// found by ID or slug
+func findModules(ctx context.Context, s store.Storer, nsIdent string) (mm types.ModuleSet, err error) {
+	var (
+		ns *types.Namespace
+		ff types.ModuleFieldSet
+	)
+
+	if ns, err = findNamespace(ctx, s, nsIdent); err != nil {
+		return
+	}
+
+	if mm, _, err = store.SearchComposeModules(ctx, s, types.ModuleFilter{NamespaceID: ns.ID}); err != nil || len(mm) == 0 {
+		return
+	}
+
+	if ff, _, err = store.SearchComposeModuleFields(ctx, s, types.ModuleFieldFilter{ModuleID: mm.IDs()}); err != nil {
+		return
+	}
+
+	for _, m := range mm {
+		m.Fields = ff.FilterByModule(m.ID)
+	}
+
+	return
+}
+
+// findModule finds module (with fields) by ID or handle on namespace
+// found by ID or slug
+func findModule(ctx context.Context, s store.Storer, nsIdent, modIdent string) (m *types.Module, err error) {
+	var (
+		ns *types.Namespace
+		ID uint64
+	)
+
+	if ns, err = findNamespace(ctx, s, nsIdent); err != nil {
+		return
+	}
+
+	if ID, _ = strconv.ParseUint(modIdent, 10, 64); ID > 0 {
+		m, err = store.LookupComposeModuleByID(ctx, s, ID)
+	} else {
+		m, err = store.LookupComposeModuleByNamespaceIDHandle(ctx, s, ns.ID, modIdent)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not find module %q: %w", modIdent, err)
+	}
+
+	if m.NamespaceID != ns.ID {
+		return nil, fmt.Errorf("module %q does not belong to namespace %q", modIdent, nsIdent)
+	}
+
+	m.Fields, _, err = store.SearchComposeModuleFields(ctx, s, types.ModuleFieldFilter{ModuleID: []uint64{m.ID}})
+	return m, err
+}
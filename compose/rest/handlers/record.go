@@ -24,11 +24,14 @@ type (
 		ImportInit(context.Context, *request.RecordImportInit) (interface{}, error)
 		ImportRun(context.Context, *request.RecordImportRun) (interface{}, error)
 		ImportProgress(context.Context, *request.RecordImportProgress) (interface{}, error)
+		ImportErrors(context.Context, *request.RecordImportErrors) (interface{}, error)
 		Export(context.Context, *request.RecordExport) (interface{}, error)
 		Exec(context.Context, *request.RecordExec) (interface{}, error)
 		Create(context.Context, *request.RecordCreate) (interface{}, error)
 		Read(context.Context, *request.RecordRead) (interface{}, error)
 		Update(context.Context, *request.RecordUpdate) (interface{}, error)
+		Revisions(context.Context, *request.RecordRevisions) (interface{}, error)
+		RestoreRevision(context.Context, *request.RecordRestoreRevision) (interface{}, error)
 		BulkDelete(context.Context, *request.RecordBulkDelete) (interface{}, error)
 		Delete(context.Context, *request.RecordDelete) (interface{}, error)
 		Upload(context.Context, *request.RecordUpload) (interface{}, error)
@@ -43,11 +46,14 @@ type (
 		ImportInit          func(http.ResponseWriter, *http.Request)
 		ImportRun           func(http.ResponseWriter, *http.Request)
 		ImportProgress      func(http.ResponseWriter, *http.Request)
+		ImportErrors        func(http.ResponseWriter, *http.Request)
 		Export              func(http.ResponseWriter, *http.Request)
 		Exec                func(http.ResponseWriter, *http.Request)
 		Create              func(http.ResponseWriter, *http.Request)
 		Read                func(http.ResponseWriter, *http.Request)
 		Update              func(http.ResponseWriter, *http.Request)
+		Revisions           func(http.ResponseWriter, *http.Request)
+		RestoreRevision     func(http.ResponseWriter, *http.Request)
 		BulkDelete          func(http.ResponseWriter, *http.Request)
 		Delete              func(http.ResponseWriter, *http.Request)
 		Upload              func(http.ResponseWriter, *http.Request)
@@ -138,6 +144,22 @@ func NewRecord(h RecordAPI) *Record {
 
 			api.Send(w, r, value)
 		},
+		ImportErrors: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordImportErrors()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ImportErrors(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		Export: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewRecordExport()
@@ -218,6 +240,38 @@ func NewRecord(h RecordAPI) *Record {
 
 			api.Send(w, r, value)
 		},
+		Revisions: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRevisions()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Revisions(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RestoreRevision: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRestoreRevision()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RestoreRevision(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		BulkDelete: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewRecordBulkDelete()
@@ -309,11 +363,14 @@ func (h Record) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/import", h.ImportInit)
 		r.Patch("/namespace/{namespaceID}/module/{moduleID}/record/import/{sessionID}", h.ImportRun)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/import/{sessionID}", h.ImportProgress)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/import/{sessionID}/errors.{ext}", h.ImportErrors)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/export{filename}.{ext}", h.Export)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/exec/{procedure}", h.Exec)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/", h.Create)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}", h.Read)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}", h.Update)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions", h.Revisions)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions/{revisionID}/restore", h.RestoreRevision)
 		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/", h.BulkDelete)
 		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}", h.Delete)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/attachment", h.Upload)
@@ -25,6 +25,12 @@ type (
 		CanCreatePage      bool `json:"canCreatePage"`
 	}
 
+	namespaceClonePayload struct {
+		*namespacePayload
+
+		Progress *types.NamespaceCloneProgress `json:"progress"`
+	}
+
 	namespaceSetPayload struct {
 		Filter types.NamespaceFilter `json:"filter"`
 		Set    []*namespacePayload   `json:"set"`
@@ -133,6 +139,21 @@ func (ctrl Namespace) Delete(ctx context.Context, r *request.NamespaceDelete) (i
 	return api.OK(), ctrl.namespace.DeleteByID(ctx, r.NamespaceID)
 }
 
+func (ctrl Namespace) Clone(ctx context.Context, r *request.NamespaceClone) (interface{}, error) {
+	dup := &types.Namespace{
+		Name: r.Name,
+		Slug: r.Slug,
+	}
+
+	ns, progress, err := ctrl.namespace.Clone(ctx, r.NamespaceID, dup, r.HandlePrefix, r.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	nsp, err := ctrl.makePayload(ctx, ns, err)
+	return &namespaceClonePayload{namespacePayload: nsp, Progress: progress}, err
+}
+
 func (ctrl Namespace) Upload(ctx context.Context, r *request.NamespaceUpload) (interface{}, error) {
 	file, err := r.Upload.Open()
 	if err != nil {
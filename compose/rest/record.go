@@ -2,13 +2,14 @@ package rest
 
 import (
 	"context"
+	encsv "encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/rest/request"
 	"github.com/cortezaproject/corteza-server/compose/service"
@@ -18,7 +19,6 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/envoy"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/csv"
 	ejson "github.com/cortezaproject/corteza-server/pkg/envoy/json"
-	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
 	estore "github.com/cortezaproject/corteza-server/pkg/envoy/store"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/payload"
@@ -42,6 +42,17 @@ type (
 		Set    []*recordPayload    `json:"set"`
 	}
 
+	recordImportSessionPayload struct {
+		*types.RecordImportSession
+
+		Rejected types.RecordImportRowSet `json:"rejected,omitempty"`
+	}
+
+	recordRevisionSetPayload struct {
+		Filter types.RecordRevisionFilter `json:"filter"`
+		Set    types.RecordRevisionSet    `json:"set"`
+	}
+
 	Record struct {
 		importSession service.ImportSessionService
 		record        service.RecordService
@@ -256,6 +267,51 @@ func (ctrl *Record) BulkDelete(ctx context.Context, r *request.RecordBulkDelete)
 	)
 }
 
+func (ctrl *Record) Revisions(ctx context.Context, r *request.RecordRevisions) (interface{}, error) {
+	var (
+		err error
+		f   = types.RecordRevisionFilter{
+			NamespaceID: r.NamespaceID,
+			ModuleID:    r.ModuleID,
+			RecordID:    r.RecordID,
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	rr, f, err := ctrl.record.SearchRevisions(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordRevisionSetPayload{Filter: f, Set: rr}, nil
+}
+
+func (ctrl *Record) RestoreRevision(ctx context.Context, r *request.RecordRestoreRevision) (interface{}, error) {
+	var (
+		m   *types.Module
+		err error
+	)
+
+	if m, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	record, err := ctrl.record.RestoreRevision(ctx, r.NamespaceID, r.ModuleID, r.RecordID, r.RevisionID)
+
+	if rve := types.IsRecordValueErrorSet(err); rve != nil {
+		return ctrl.handleValidationError(rve), nil
+	}
+
+	return ctrl.makePayload(ctx, m, record, err)
+}
+
 func (ctrl *Record) Upload(ctx context.Context, r *request.RecordUpload) (interface{}, error) {
 	file, err := r.Upload.Open()
 	if err != nil {
@@ -296,7 +352,8 @@ func (ctrl *Record) ImportInit(ctx context.Context, r *request.RecordImportInit)
 
 func (ctrl *Record) ImportRun(ctx context.Context, r *request.RecordImportRun) (interface{}, error) {
 	var (
-		err error
+		err    error
+		fields = make(types.RecordImportFields)
 	)
 
 	// Access control.
@@ -304,113 +361,111 @@ func (ctrl *Record) ImportRun(ctx context.Context, r *request.RecordImportRun) (
 		return nil, err
 	}
 
-	// Check if session ok
-	ses, err := ctrl.importSession.FindByID(ctx, r.SessionID)
-	if err != nil {
+	if err = json.Unmarshal(r.Fields, &fields); err != nil {
 		return nil, err
 	}
 
-	ses.Fields = make(map[string]string)
-	err = json.Unmarshal(r.Fields, &ses.Fields)
+	ses, err := ctrl.importSession.Run(ctx, r.SessionID, fields, r.OnError, types.RecordImportMode(r.Mode), r.Key, r.DryRun)
 	if err != nil {
 		return nil, err
 	}
 
-	ses.OnError = r.OnError
+	return ctrl.makeImportSessionPayload(ses), nil
+}
 
-	// Errors are presented in the session
-	err = func() (err error) {
-		if ses.Progress.StartedAt != nil {
-			return fmt.Errorf("unable to start import: import session already active")
-		}
+func (ctrl *Record) ImportProgress(ctx context.Context, r *request.RecordImportProgress) (interface{}, error) {
+	// Get session
+	ses, err := ctrl.importSession.FindByID(ctx, r.SessionID)
+	if err != nil {
+		return nil, err
+	}
 
-		sa := time.Now()
-		ses.Progress.StartedAt = &sa
-
-		// Prepare additional metadata
-		tpl := resource.NewComposeRecordTemplate(
-			strconv.FormatUint(ses.ModuleID, 10),
-			strconv.FormatUint(ses.NamespaceID, 10),
-			ses.Name,
-			false,
-			resource.MapToMappingTplSet(ses.Fields),
-			ses.Key,
-		)
+	return ctrl.makeImportSessionPayload(ses), nil
+}
 
-		// Shape the data
-		ses.Resources = append(ses.Resources, tpl)
-		rt := resource.ComposeRecordShaper()
-		ses.Resources, err = resource.Shape(ses.Resources, rt)
-
-		// Build
-		cfg := &estore.EncoderConfig{
-			// For now the identifier is ignored, so this will never occur
-			OnExisting: resource.Skip,
-			DeferOk: func() {
-				ses.Progress.Completed++
-			},
-		}
-		cfg.DeferNok = func(err error) error {
-			ses.Progress.Failed++
+// ImportErrors writes rejected rows with original values and errors
+func (ctrl *Record) ImportErrors(ctx context.Context, r *request.RecordImportErrors) (interface{}, error) {
+	ses, err := ctrl.importSession.FindByID(ctx, r.SessionID)
+	if err != nil {
+		return nil, err
+	}
 
-			if ses.Progress.FailLog == nil {
-				ses.Progress.FailLog = &service.FailLog{
-					Errors: make(service.ErrorIndex),
-				}
-			}
+	filename := fmt.Sprintf("; filename=%s-errors.%s", strings.TrimSuffix(ses.Name, filepath.Ext(ses.Name)), r.Ext)
 
-			if rve, is := err.(*types.RecordValueErrorSet); is {
-				for _, ve := range rve.Set {
-					for k, v := range ve.Meta {
-						ses.Progress.FailLog.Errors.Add(fmt.Sprintf("%s %s %v", ve.Kind, k, v))
-					}
-				}
-			} else {
-				ses.Progress.FailLog.Errors.Add(err.Error())
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch strings.ToLower(r.Ext) {
+		case "json":
+			w.Header().Add("Content-Type", "application/json")
+			w.Header().Add("Content-Disposition", "attachment"+filename)
+			if err = json.NewEncoder(w).Encode(ses.Rejected); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
 
-			if len(ses.Progress.FailLog.Records) < service.IMPORT_ERROR_MAX_INDEX_COUNT {
-				// +1 because we indexed them with 1 before
-				ses.Progress.FailLog.Records = append(ses.Progress.FailLog.Records, int(ses.Progress.Completed)+1)
-			} else {
-				ses.Progress.FailLog.RecordsTruncated = true
+		case "csv":
+			w.Header().Add("Content-Type", "text/csv")
+			w.Header().Add("Content-Disposition", "attachment"+filename)
+			if err = writeImportErrorsCsv(w, ses); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
 			}
 
-			if ses.OnError == service.IMPORT_ON_ERROR_SKIP {
-				return nil
-			}
-			return err
-		}
-		se := estore.NewStoreEncoder(service.DefaultStore, cfg)
-		bld := envoy.NewBuilder(se)
-		g, err := bld.Build(ctx, ses.Resources...)
-		if err != nil {
-			return err
+		default:
+			http.Error(w, "unsupported format ("+r.Ext+")", http.StatusBadRequest)
 		}
+	}, nil
+}
 
-		// Encode
-		err = envoy.Encode(ctx, g, se)
-		now := time.Now()
-		ses.Progress.FinishedAt = &now
-		if err != nil {
-			ses.Progress.FailReason = err.Error()
-			return err
-		}
+func (ctrl *Record) makeImportSessionPayload(ses *types.RecordImportSession) *recordImportSessionPayload {
+	p := &recordImportSessionPayload{RecordImportSession: ses}
 
-		return
-	}()
+	// rejected rows are included only in dry-run results;
+	// for regular imports they can be downloaded as a report
+	if ses.DryRun {
+		p.Rejected = ses.Rejected
+	}
 
-	return ses, ctrl.record.RecordImport(ctx, err)
+	return p
 }
 
-func (ctrl *Record) ImportProgress(ctx context.Context, r *request.RecordImportProgress) (interface{}, error) {
-	// Get session
-	ses, err := ctrl.importSession.FindByID(ctx, r.SessionID)
-	if err != nil {
-		return nil, err
+// writes rejected rows as CSV
+//
+// Columns from the import file are followed by the row index and error columns
+func writeImportErrorsCsv(w io.Writer, ses *types.RecordImportSession) error {
+	var (
+		cw   = encsv.NewWriter(w)
+		cols = ses.Fields.Columns()
+	)
+
+	if err := cw.Write(append([]string{"row"}, append(cols, "errors")...)); err != nil {
+		return err
+	}
+
+	for _, rej := range ses.Rejected {
+		var (
+			rr = []string{strconv.FormatUint(rej.Index, 10)}
+			ee []string
+		)
+
+		for _, c := range cols {
+			rr = append(rr, rej.Values[c])
+		}
+
+		if rej.Errors != nil {
+			for _, ve := range rej.Errors.Set {
+				for k, v := range ve.Meta {
+					ee = append(ee, fmt.Sprintf("%s %s %v", ve.Kind, k, v))
+				}
+			}
+		} else {
+			ee = append(ee, rej.Error)
+		}
+
+		if err := cw.Write(append(rr, strings.Join(ee, "; "))); err != nil {
+			return err
+		}
 	}
 
-	return ses, nil
+	cw.Flush()
+	return cw.Error()
 }
 
 func (ctrl *Record) Export(ctx context.Context, r *request.RecordExport) (interface{}, error) {
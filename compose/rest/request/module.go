@@ -105,6 +105,11 @@ type (
 		// Module meta data
 		Meta sqlxTypes.JSONText
 
+		// Config POST parameter
+		//
+		// Module configuration (record partition can not be changed after module is created)
+		Config types.ModuleConfig
+
 		// Labels POST parameter
 		//
 		// Module labels
@@ -154,6 +159,11 @@ type (
 		// Module meta data
 		Meta sqlxTypes.JSONText
 
+		// Config POST parameter
+		//
+		// Module configuration (only unique constraints can be changed)
+		Config types.ModuleConfig
+
 		// UpdatedAt POST parameter
 		//
 		// Last update (or creation) date
@@ -338,6 +348,7 @@ func (r ModuleCreate) Auditable() map[string]interface{} {
 		"handle":      r.Handle,
 		"fields":      r.Fields,
 		"meta":        r.Meta,
+		"config":      r.Config,
 		"labels":      r.Labels,
 	}
 }
@@ -367,6 +378,11 @@ func (r ModuleCreate) GetMeta() sqlxTypes.JSONText {
 	return r.Meta
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ModuleCreate) GetConfig() types.ModuleConfig {
+	return r.Config
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r ModuleCreate) GetLabels() map[string]string {
 	return r.Labels
@@ -421,6 +437,18 @@ func (r *ModuleCreate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["config[]"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["config"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["labels[]"]; ok {
 			r.Labels, err = label.ParseStrings(val)
 			if err != nil {
@@ -510,6 +538,7 @@ func (r ModuleUpdate) Auditable() map[string]interface{} {
 		"handle":      r.Handle,
 		"fields":      r.Fields,
 		"meta":        r.Meta,
+		"config":      r.Config,
 		"updatedAt":   r.UpdatedAt,
 		"labels":      r.Labels,
 	}
@@ -545,6 +574,11 @@ func (r ModuleUpdate) GetMeta() sqlxTypes.JSONText {
 	return r.Meta
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ModuleUpdate) GetConfig() types.ModuleConfig {
+	return r.Config
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r ModuleUpdate) GetUpdatedAt() *time.Time {
 	return r.UpdatedAt
@@ -604,6 +638,18 @@ func (r *ModuleUpdate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["config[]"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["config"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["updatedAt"]; ok && len(val) > 0 {
 			r.UpdatedAt, err = payload.ParseISODatePtrWithErr(val[0])
 			if err != nil {
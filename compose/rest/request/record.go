@@ -156,6 +156,21 @@ type (
 		//
 		// What happens if record fails to import
 		OnError string
+
+		// Mode POST parameter
+		//
+		// Import mode (create, upsert)
+		Mode string
+
+		// Key POST parameter
+		//
+		// Column that identifies existing records (upsert)
+		Key string
+
+		// DryRun POST parameter
+		//
+		// Validate records without storing them
+		DryRun bool
 	}
 
 	RecordImportProgress struct {
@@ -175,6 +190,28 @@ type (
 		SessionID uint64 `json:",string"`
 	}
 
+	RecordImportErrors struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// SessionID PATH parameter
+		//
+		// Import session
+		SessionID uint64 `json:",string"`
+
+		// Ext PATH parameter
+		//
+		// Report format (csv, json)
+		Ext string
+	}
+
 	RecordExport struct {
 		// NamespaceID PATH parameter
 		//
@@ -310,6 +347,60 @@ type (
 		Labels map[string]string
 	}
 
+	RecordRevisions struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	RecordRestoreRevision struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+
+		// RevisionID PATH parameter
+		//
+		// Revision ID
+		RevisionID uint64 `json:",string"`
+	}
+
 	RecordBulkDelete struct {
 		// NamespaceID PATH parameter
 		//
@@ -754,6 +845,9 @@ func (r RecordImportRun) Auditable() map[string]interface{} {
 		"sessionID":   r.SessionID,
 		"fields":      r.Fields,
 		"onError":     r.OnError,
+		"mode":        r.Mode,
+		"key":         r.Key,
+		"dryRun":      r.DryRun,
 	}
 }
 
@@ -782,6 +876,21 @@ func (r RecordImportRun) GetOnError() string {
 	return r.OnError
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportRun) GetMode() string {
+	return r.Mode
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportRun) GetKey() string {
+	return r.Key
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportRun) GetDryRun() bool {
+	return r.DryRun
+}
+
 // Fill processes request and fills internal variables
 func (r *RecordImportRun) Fill(req *http.Request) (err error) {
 
@@ -816,6 +925,27 @@ func (r *RecordImportRun) Fill(req *http.Request) (err error) {
 				return err
 			}
 		}
+
+		if val, ok := req.Form["mode"]; ok && len(val) > 0 {
+			r.Mode, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["key"]; ok && len(val) > 0 {
+			r.Key, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["dryRun"]; ok && len(val) > 0 {
+			r.DryRun, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	{
@@ -904,6 +1034,77 @@ func (r *RecordImportProgress) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewRecordImportErrors request
+func NewRecordImportErrors() *RecordImportErrors {
+	return &RecordImportErrors{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportErrors) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"sessionID":   r.SessionID,
+		"ext":         r.Ext,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportErrors) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportErrors) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportErrors) GetSessionID() uint64 {
+	return r.SessionID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordImportErrors) GetExt() string {
+	return r.Ext
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordImportErrors) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "sessionID")
+		r.SessionID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "ext")
+		r.Ext, err = val, nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewRecordExport request
 func NewRecordExport() *RecordExport {
 	return &RecordExport{}
@@ -1404,6 +1605,178 @@ func (r *RecordUpdate) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewRecordRevisions request
+func NewRecordRevisions() *RecordRevisions {
+	return &RecordRevisions{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+		"limit":       r.Limit,
+		"pageCursor":  r.PageCursor,
+		"sort":        r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordRevisions) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordRestoreRevision request
+func NewRecordRestoreRevision() *RecordRestoreRevision {
+	return &RecordRestoreRevision{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+		"revisionID":  r.RevisionID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetRevisionID() uint64 {
+	return r.RevisionID
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordRestoreRevision) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "revisionID")
+		r.RevisionID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewRecordBulkDelete request
 func NewRecordBulkDelete() *RecordBulkDelete {
 	return &RecordBulkDelete{}
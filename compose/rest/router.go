@@ -27,7 +27,7 @@ func MountRoutes(r chi.Router) {
 
 	// Protect all _private_ routes
 	r.Group(func(r chi.Router) {
-		r.Use(auth.MiddlewareValidOnly)
+		r.Use(auth.ComponentAccessTokenCheck("compose"))
 		handlers.NewPermissions(Permissions{}.New()).MountRoutes(r)
 		handlers.NewNamespace(namespace).MountRoutes(r)
 		handlers.NewPage(page).MountRoutes(r)
@@ -14,6 +14,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/report"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/disintegration/imaging"
 	"github.com/edwvee/exiffix"
@@ -56,6 +57,8 @@ type (
 		OpenOriginal(att *types.Attachment) (io.ReadSeeker, error)
 		OpenPreview(att *types.Attachment) (io.ReadSeeker, error)
 		DeleteByID(namespaceID, attachmentID uint64) error
+
+		Datasource(context.Context, *report.LoadStepDefinition) (report.Datasource, error)
 	}
 )
 
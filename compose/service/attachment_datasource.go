@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/spf13/cast"
+)
+
+// Datasource provides the contents of a CSV or JSON attachment to the report
+//
+// CSV files must define a header row; JSON files must contain an array of objects.
+// When load step columns are not defined, all of the file's columns are provided as strings.
+func (svc attachment) Datasource(ctx context.Context, ld *report.LoadStepDefinition) (report.Datasource, error) {
+	var (
+		def = ld.Definition
+		att *types.Attachment
+	)
+
+	attachmentID, err := cast.ToUint64E(def["attachmentID"])
+	if err != nil || attachmentID == 0 {
+		return nil, AttachmentErrInvalidID()
+	}
+
+	if att, err = store.LookupComposeAttachmentByID(ctx, svc.store, attachmentID); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, AttachmentErrNotFound()
+		}
+		return nil, err
+	}
+
+	// namespace attachments are not bound to a namespace
+	if att.NamespaceID > 0 {
+		ns, err := loadNamespace(ctx, svc.store, att.NamespaceID)
+		if err != nil {
+			return nil, err
+		}
+		if !svc.ac.CanReadNamespace(ctx, ns) {
+			return nil, AttachmentErrNotAllowedToReadNamespace()
+		}
+	}
+
+	// record attachments are readable only through the record they belong to
+	if att.Kind == types.RecordAttachment {
+		rec, err := loadAttachmentRecord(ctx, svc.store, att)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil || !svc.ac.CanReadRecord(ctx, rec) {
+			return nil, AttachmentErrNotAllowedToReadRecord()
+		}
+	}
+
+	format := cast.ToString(def["format"])
+	if format == "" {
+		format = att.Meta.Original.Extension
+	}
+
+	fh, err := svc.OpenOriginal(att)
+	if err != nil {
+		return nil, err
+	}
+	if fh == nil {
+		return nil, fmt.Errorf("attachment %d has no content", att.ID)
+	}
+
+	var (
+		cc report.FrameColumnSet
+		rr []map[string]interface{}
+	)
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "csv":
+		cc, rr, err = attachmentDatasourceCSV(fh, cast.ToString(def["delimiter"]))
+	case "json":
+		cc, rr, err = attachmentDatasourceJSON(fh)
+	default:
+		return nil, fmt.Errorf("unsupported attachment format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse attachment %d: %w", att.ID, err)
+	}
+
+	// explicitly defined columns determine the column kinds
+	if len(ld.Columns) > 0 {
+		cc = ld.Columns
+	}
+
+	return report.MemoryDatasource(ld, cc, func(ctx context.Context) ([]map[string]interface{}, error) {
+		return rr, nil
+	})
+}
+
+// loadAttachmentRecord loads record that references the attachment
+//
+// Record attachments do not hold a reference to the record so file fields
+// of all modules in attachment's namespace are checked.
+// Nil is returned when attachment is not (yet) referenced by any record.
+func loadAttachmentRecord(ctx context.Context, s store.Storer, att *types.Attachment) (*types.Record, error) {
+	mm, _, err := store.SearchComposeModules(ctx, s, types.ModuleFilter{NamespaceID: att.NamespaceID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = loadModuleFields(ctx, s, mm...); err != nil {
+		return nil, err
+	}
+
+	for _, m := range mm {
+		for _, f := range m.Fields {
+			if f.Kind != "File" {
+				continue
+			}
+
+			rf := types.RecordFilter{
+				ModuleID:    m.ID,
+				NamespaceID: m.NamespaceID,
+				Query:       fmt.Sprintf("%s = '%d'", f.Name, att.ID),
+			}
+			rf.Limit = 1
+
+			rr, _, err := store.SearchComposeRecords(ctx, s, m, rf)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(rr) > 0 {
+				return rr[0], nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func attachmentDatasourceCSV(r io.Reader, delimiter string) (cc report.FrameColumnSet, rr []map[string]interface{}, err error) {
+	cr := csv.NewReader(r)
+	if delimiter != "" {
+		cr.Comma = []rune(delimiter)[0]
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, h := range header {
+		cc = append(cc, attachmentDatasourceColumn(h))
+	}
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row := make(map[string]interface{}, len(header))
+		for i, v := range rec {
+			if i < len(header) {
+				row[header[i]] = v
+			}
+		}
+		rr = append(rr, row)
+	}
+
+	return
+}
+
+// attachmentDatasourceJSON parses an array of objects
+//
+// Columns are ordered by their first occurrence; nested values are provided as JSON strings.
+func attachmentDatasourceJSON(r io.Reader) (cc report.FrameColumnSet, rr []map[string]interface{}, err error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	expectDelim := func(d json.Delim) error {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if t != d {
+			return fmt.Errorf("expecting %s", d)
+		}
+		return nil
+	}
+
+	if err = expectDelim('['); err != nil {
+		return
+	}
+
+	for dec.More() {
+		if err = expectDelim('{'); err != nil {
+			return
+		}
+
+		row := make(map[string]interface{})
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			k := t.(string)
+
+			var v interface{}
+			if err = dec.Decode(&v); err != nil {
+				return nil, nil, err
+			}
+
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				aux, _ := json.Marshal(v)
+				v = string(aux)
+			}
+
+			if cc.Find(k) < 0 {
+				cc = append(cc, attachmentDatasourceColumn(k))
+			}
+			row[k] = v
+		}
+
+		if err = expectDelim('}'); err != nil {
+			return
+		}
+		rr = append(rr, row)
+	}
+
+	err = expectDelim(']')
+	return
+}
+
+func attachmentDatasourceColumn(name string) *report.FrameColumn {
+	c := report.MakeColumnOfKind("String")
+	c.Name = name
+	c.Label = name
+	return c
+}
@@ -1,84 +1,494 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/envoy"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/csv"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/json"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/store"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
 )
 
 type (
-	recordSet []*recordImportSession
-
 	importSession struct {
-		l       sync.Mutex
-		records recordSet
+		store  store.Storer
+		record *record
+		log    *zap.Logger
+
+		// serializes processing of the queued sessions
+		mux sync.Mutex
+
+		// signals the worker that there are queued sessions
+		queued chan struct{}
 	}
 
 	ImportSessionService interface {
-		Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*recordImportSession, error)
-		FindByID(ctx context.Context, sessionID uint64) (*recordImportSession, error)
+		Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*types.RecordImportSession, error)
+		FindByID(ctx context.Context, sessionID uint64) (*types.RecordImportSession, error)
 		DeleteByID(ctx context.Context, sessionID uint64) error
+
+		Run(ctx context.Context, sessionID uint64, fields types.RecordImportFields, onError string, mode types.RecordImportMode, key string, dryRun bool) (*types.RecordImportSession, error)
+		ProcessQueued(ctx context.Context) error
+		Watch(ctx context.Context)
 	}
+
+	// importCheckpointStore stores session progress in the same
+	// transaction as the imported record
+	//
+	// Interrupted import is resumed after the last stored record
+	// so rows are never imported twice
+	importCheckpointStore struct {
+		store.Storer
+		ses *types.RecordImportSession
+	}
+)
+
+var (
+	// Columns stored with each of the imported records
+	importProgressColumns = []string{"progress", "updated_at"}
+
+	// Columns stored periodically while import is running
+	importRejectedColumns = []string{"progress", "rejected", "updated_at"}
+)
+
+const (
+	// Progress of the running import is stored after every n rows
+	importCheckpointInterval = 100
+
+	// Max number of rejected rows kept for the error report
+	importRejectedMaxCount = 10000
+
+	// Running sessions without progress for this long are considered interrupted
+	// and are resumed by the worker
+	importStaleAfter = time.Minute * 5
+
+	// Running sessions are checkpointed at least this often
+	// so that they are not considered interrupted
+	importHeartbeatInterval = time.Minute
+
+	// Sessions are removed after they were not updated for this long
+	importSessionLifetime = time.Hour * 24 * 3
 )
 
 func ImportSession() *importSession {
 	return &importSession{
-		records: recordSet{},
+		store:  DefaultStore,
+		record: DefaultRecord.(*record),
+		log:    DefaultLogger.Named("import-session"),
+		queued: make(chan struct{}, 1),
 	}
 }
 
-func (svc *importSession) indexOf(userID, sessionID uint64) int {
-	for i, r := range svc.records {
-		if r.SessionID == sessionID && r.UserID == userID {
-			return i
+func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*types.RecordImportSession, error) {
+	// Prepare the session
+	ses := &types.RecordImportSession{
+		ID:          nextID(),
+		NamespaceID: namespaceID,
+		ModuleID:    moduleID,
+		OwnedBy:     auth.GetIdentityFromContext(ctx).Identity(),
+		Name:        name,
+		ContentType: contentType,
+
+		Status:  types.RecordImportPending,
+		Mode:    types.RecordImportModeCreate,
+		OnError: IMPORT_ON_ERROR_FAIL,
+		Fields:  make(types.RecordImportFields),
+
+		CreatedAt: *now(),
+	}
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	ses.Data = data
+
+	// Get some metadata
+	ds, err := svc.dataset(ctx, ses)
+	if err != nil {
+		return nil, err
+	}
+
+	prepKey := func(k string) string {
+		return strings.TrimSpace(strings.ToLower(k))
+	}
+
+	ses.Progress.EntryCount = ds.P.Count()
+	for _, f := range ds.P.Fields() {
+		ses.Fields[f] = ""
+
+		// @todo improve this bit
+		k := prepKey(f)
+		if k == "id" || k == "recordid" {
+			ses.Key = f
 		}
 	}
 
-	return -1
+	if err = store.CreateComposeRecordImportSession(ctx, svc.store, ses); err != nil {
+		return nil, err
+	}
+
+	return ses, nil
 }
 
-func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*recordImportSession, error) {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+func (svc *importSession) FindByID(ctx context.Context, sessionID uint64) (*types.RecordImportSession, error) {
+	ses, err := store.LookupComposeRecordImportSessionByID(ctx, svc.store, sessionID)
+	if errors.IsNotFound(err) || (err == nil && ses.OwnedBy != auth.GetIdentityFromContext(ctx).Identity()) {
+		return nil, fmt.Errorf("compose.service.RecordImportSessionNotFound")
+	}
 
-	// Prepare the session
-	sh := &recordImportSession{
-		Name:        name,
-		SessionID:   nextID(),
-		UserID:      auth.GetIdentityFromContext(ctx).Identity(),
-		NamespaceID: namespaceID,
-		ModuleID:    moduleID,
+	return ses, err
+}
+
+func (svc *importSession) DeleteByID(ctx context.Context, sessionID uint64) error {
+	ses, err := svc.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return store.DeleteComposeRecordImportSessionByID(ctx, svc.store, ses.ID)
+}
 
-		OnError:  IMPORT_ON_ERROR_FAIL,
-		Fields:   make(map[string]string),
-		Progress: &RecordImportProgress{},
+// Run configures and starts the import
+//
+// Import is queued and processed by the worker; with dry-run,
+// rows are validated right away and nothing is stored (except the session progress
+// and rejected rows). Session remains pending after the dry-run and can be started again.
+func (svc *importSession) Run(ctx context.Context, sessionID uint64, fields types.RecordImportFields, onError string, mode types.RecordImportMode, key string, dryRun bool) (ses *types.RecordImportSession, err error) {
+	if ses, err = svc.FindByID(ctx, sessionID); err != nil {
+		return
+	}
 
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	if ses.Status != types.RecordImportPending {
+		return nil, RecordErrImportSessionAlreadActive()
 	}
 
-	// Decoders; We only need to do csv & yaml here
-	cd := csv.Decoder()
-	jd := json.Decoder()
+	if mode == "" {
+		mode = types.RecordImportModeCreate
+	}
 
-	// This will really be at most 1
-	var err error
-	do := &envoy.DecoderOpts{
-		Name: name,
-		Path: "",
+	switch mode {
+	case types.RecordImportModeCreate:
+	case types.RecordImportModeUpsert:
+		if _, has := fields[key]; !has {
+			return nil, RecordErrImportInvalidKey()
+		}
+	default:
+		return nil, RecordErrImportInvalidMode()
+	}
+
+	ses.Fields = fields
+	ses.OnError = onError
+	ses.Mode = mode
+	ses.Key = key
+	ses.DryRun = dryRun
+
+	// results of the previous dry-run are discarded
+	ses.Progress = types.RecordImportProgress{EntryCount: ses.Progress.EntryCount}
+	ses.Rejected = nil
+	ses.UpdatedAt = now()
+
+	if dryRun {
+		err = svc.process(ctx, ses)
+		ses.Progress.FinishedAt = now()
+		if err != nil {
+			ses.Progress.FailReason = err.Error()
+		}
+
+		return ses, store.UpdateComposeRecordImportSession(ctx, svc.store, ses)
+	}
+
+	ses.Status = types.RecordImportQueued
+	if err = store.UpdateComposeRecordImportSession(ctx, svc.store, ses); err != nil {
+		return nil, err
+	}
+
+	// notify the worker
+	select {
+	case svc.queued <- struct{}{}:
+	default:
+	}
+
+	return ses, nil
+}
+
+// ProcessQueued processes all queued sessions and resumes interrupted ones
+//
+// Sessions are claimed before they are processed so that each
+// is processed by one worker only, even with multiple instances
+func (svc *importSession) ProcessQueued(ctx context.Context) error {
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+
+	var (
+		stale = now().Add(-importStaleAfter)
+
+		ff = []types.RecordImportSessionFilter{
+			{Status: []types.RecordImportStatus{types.RecordImportQueued}},
+			{Status: []types.RecordImportStatus{types.RecordImportRunning}, UpdatedBefore: &stale},
+		}
+	)
+
+	for _, f := range ff {
+		set, _, err := store.SearchComposeRecordImportSessions(ctx, svc.store, f)
+		if err != nil {
+			return err
+		}
+
+		for _, ses := range set {
+			if err = svc.runQueued(ctx, ses, f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Watch starts the worker that processes queued sessions and removes expired ones
+func (svc *importSession) Watch(ctx context.Context) {
+	ticker := time.NewTicker(time.Second * 30)
+
+	go func() {
+		defer sentry.Recover()
+		defer ticker.Stop()
+		defer svc.log.Info("stopped")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				svc.clean(ctx)
+			case <-svc.queued:
+			}
+
+			if err := svc.ProcessQueued(ctx); err != nil {
+				svc.log.Error("failed to process queued import sessions", zap.Error(err))
+			}
+		}
+	}()
+
+	svc.log.Debug("watcher initialized")
+}
+
+// runs the import of a single queued (or interrupted) session with the identity of the session owner
+//
+// Owner and their role memberships are loaded when session is claimed
+// so that import runs with the current permissions of the owner.
+//
+// Session is skipped when it no longer matches the filter it was found with (claimed by another worker).
+// Returned error is a store error; import errors are recorded in the session
+func (svc *importSession) runQueued(ctx context.Context, ses *types.RecordImportSession, f types.RecordImportSessionFilter) error {
+	ses.Status = types.RecordImportRunning
+	ses.UpdatedAt = now()
+	if claimed, err := store.ClaimComposeRecordImportSession(ctx, svc.store, ses, f); err != nil || !claimed {
+		return err
+	}
+
+	owner, err := svc.owner(ctx, ses)
+	if err == nil {
+		ctx = auth.SetIdentityToContext(ctx, owner)
+		err = svc.process(ctx, ses)
+	}
+
+	ses.Status = types.RecordImportCompleted
+	ses.Progress.FinishedAt = now()
+	if err != nil {
+		ses.Status = types.RecordImportFailed
+		ses.Progress.FailReason = err.Error()
+	}
+
+	_ = svc.record.RecordImport(ctx, err)
+	return svc.checkpoint(ctx, ses)
+}
+
+// loads the session owner with their role memberships
+func (svc *importSession) owner(ctx context.Context, ses *types.RecordImportSession) (*systemTypes.User, error) {
+	u, err := store.LookupUserByID(ctx, svc.store, ses.OwnedBy)
+	if err != nil {
+		return nil, fmt.Errorf("could not load import session owner: %w", err)
+	}
+
+	if u.DeletedAt != nil || u.SuspendedAt != nil {
+		return nil, fmt.Errorf("import session owner is deleted or suspended")
+	}
+
+	rr, _, err := store.SearchRoles(ctx, svc.store, systemTypes.RoleFilter{MemberID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	u.SetRoles(rr.IDs()...)
+	return u, nil
+}
+
+// stores the session progress
+func (svc *importSession) checkpoint(ctx context.Context, ses *types.RecordImportSession) error {
+	ses.UpdatedAt = now()
+	return store.UpdateComposeRecordImportSession(ctx, svc.store, ses)
+}
+
+// process imports rows from the session file
+//
+// Rows that were processed before (see Progress.Processed) are skipped
+func (svc *importSession) process(ctx context.Context, ses *types.RecordImportSession) (err error) {
+	var (
+		ds  *resource.ResourceDataset
+		m   *types.Module
+		row map[string]string
+		ri  uint64
+
+		imp = &recordImporter{
+			svc:   svc,
+			ses:   ses,
+			users: make(map[string]uint64),
+		}
+	)
+
+	imp.record = svc.record
+	if !ses.DryRun {
+		// records are stored with the session progress
+		rs := *svc.record
+		rs.store = importCheckpointStore{Storer: svc.store, ses: ses}
+		imp.record = &rs
+	}
+
+	if ds, err = svc.dataset(ctx, ses); err != nil {
+		return
+	}
+
+	if m, err = loadModule(ctx, svc.store, ses.ModuleID); err != nil {
+		return
+	}
+
+	imp.m = m
+
+	if ses.Progress.StartedAt == nil {
+		ses.Progress.StartedAt = now()
+	}
+
+	for {
+		if row, err = ds.P.Next(); err != nil || row == nil {
+			return
+		}
+
+		// row index, starting with 1
+		ri++
+		if ri <= ses.Progress.Processed {
+			continue
+		}
+
+		// progress is set before the row is imported
+		// so that it is stored with the record
+		ses.Progress.Processed = ri
+		ses.Progress.Completed++
+
+		if rowErr := imp.importRow(ctx, row); rowErr != nil {
+			ses.Progress.Completed--
+			svc.reject(ses, ri, row, rowErr)
+
+			// dry-run checks all rows
+			if !ses.DryRun && !strings.EqualFold(ses.OnError, IMPORT_ON_ERROR_SKIP) {
+				return rowErr
+			}
+		}
+
+		// progress of rejected rows is stored periodically
+		if !ses.DryRun && (ri%importCheckpointInterval == 0 || now().Sub(*ses.UpdatedAt) >= importHeartbeatInterval) {
+			ses.UpdatedAt = now()
+			if err = store.PartialComposeRecordImportSessionUpdate(ctx, svc.store, importRejectedColumns, ses); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// reject records the row and the error for the fail log and error report
+func (svc *importSession) reject(ses *types.RecordImportSession, ri uint64, row map[string]string, err error) {
+	var (
+		p   = &ses.Progress
+		rej = &types.RecordImportRow{Index: ri, Values: row}
+		rve *types.RecordValueErrorSet
+	)
+
+	p.Failed++
+
+	if p.FailLog == nil {
+		p.FailLog = &types.RecordImportFailLog{
+			Errors: make(types.RecordImportErrorIndex),
+		}
+	}
+
+	if errors.As(err, &rve) && !rve.IsValid() {
+		rej.Errors = rve
+		for _, ve := range rve.Set {
+			for k, v := range ve.Meta {
+				p.FailLog.Errors.Add(fmt.Sprintf("%s %s %v", ve.Kind, k, v))
+			}
+		}
+	} else {
+		rej.Error = err.Error()
+		p.FailLog.Errors.Add(err.Error())
 	}
 
-	sh.Resources, err = func() ([]resource.Interface, error) {
-		if cd.CanDecodeFile(f) || cd.CanDecodeMime(contentType) {
+	if len(p.FailLog.Records) < IMPORT_ERROR_MAX_INDEX_COUNT {
+		p.FailLog.Records = append(p.FailLog.Records, int(ri))
+	} else {
+		p.FailLog.RecordsTruncated = true
+	}
+
+	if len(ses.Rejected) < importRejectedMaxCount {
+		ses.Rejected = append(ses.Rejected, rej)
+	}
+}
+
+// Tx stores session progress at the end of the transaction
+func (s importCheckpointStore) Tx(ctx context.Context, fn func(context.Context, store.Storer) error) error {
+	return s.Storer.Tx(ctx, func(ctx context.Context, tx store.Storer) error {
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+
+		s.ses.UpdatedAt = now()
+		return store.PartialComposeRecordImportSessionUpdate(ctx, tx, importProgressColumns, s.ses)
+	})
+}
+
+// dataset decodes the session file
+func (svc *importSession) dataset(ctx context.Context, ses *types.RecordImportSession) (*resource.ResourceDataset, error) {
+	var (
+		// Decoders; We only need to do csv & json here
+		cd = csv.Decoder()
+		jd = json.Decoder()
+
+		f = bytes.NewReader(ses.Data)
+
+		do = &envoy.DecoderOpts{
+			Name: ses.Name,
+			Path: "",
+		}
+	)
+
+	// This will really be at most 1
+	rr, err := func() ([]resource.Interface, error) {
+		if cd.CanDecodeFile(f) || cd.CanDecodeMime(ses.ContentType) {
 			f.Seek(0, 0)
 			return cd.Decode(ctx, f, do)
 		}
@@ -96,73 +506,208 @@ func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, con
 		return nil, err
 	}
 
-	// Get some metadata
-	n, ok := (sh.Resources[0]).(*resource.ResourceDataset)
+	if len(rr) == 0 {
+		return nil, fmt.Errorf("compose.service.RecordImportFormatNotSupported")
+	}
+
+	ds, ok := rr[0].(*resource.ResourceDataset)
 	if !ok {
 		// @todo move this logic to service and use action/error pattern
 		return nil, fmt.Errorf("compose.service.RecordImportFormatNotSupported")
 	}
 
-	prepKey := func(k string) string {
-		return strings.TrimSpace(strings.ToLower(k))
+	return ds, nil
+}
+
+func (svc *importSession) clean(ctx context.Context) {
+	expired := now().Add(-importSessionLifetime)
+
+	set, _, err := store.SearchComposeRecordImportSessions(ctx, svc.store, types.RecordImportSessionFilter{
+		Status:        []types.RecordImportStatus{types.RecordImportPending, types.RecordImportCompleted, types.RecordImportFailed},
+		UpdatedBefore: &expired,
+	})
+
+	if err == nil && len(set) > 0 {
+		err = store.DeleteComposeRecordImportSession(ctx, svc.store, set...)
+	}
+
+	if err != nil {
+		svc.log.Error("failed to remove expired import sessions", zap.Error(err))
 	}
+}
 
-	sh.Progress.EntryCount = n.P.Count()
-	for _, f := range n.P.Fields() {
-		sh.Fields[f] = ""
+// recordImporter converts rows to records and creates or updates them
+type recordImporter struct {
+	svc    *importSession
+	ses    *types.RecordImportSession
+	m      *types.Module
+	record RecordService
 
-		// @todo improve this bit
-		k := prepKey(f)
-		if k == "id" || k == "recordid" {
-			sh.Key = f
+	// resolved user references
+	users map[string]uint64
+}
+
+func (imp *recordImporter) importRow(ctx context.Context, row map[string]string) (err error) {
+	var (
+		rec = &types.Record{
+			NamespaceID: imp.ses.NamespaceID,
+			ModuleID:    imp.ses.ModuleID,
+		}
+
+		old *types.Record
+	)
+
+	if rec.Values, err = imp.values(ctx, row); err != nil {
+		return
+	}
+
+	if imp.ses.Mode == types.RecordImportModeUpsert {
+		if old, err = imp.existing(ctx, row); err != nil {
+			return
 		}
 	}
 
-	// Create it
-	svc.records = append(svc.records, sh)
-	return sh, nil
-}
+	if old != nil {
+		rec.ID = old.ID
+		rec.OwnedBy = old.OwnedBy
+		rec.Labels = old.Labels
+		rec.UpdatedAt = old.UpdatedAt
+
+		// values of the fields that are not imported are kept
+		kept, _ := old.Values.Filter(func(v *types.RecordValue) (bool, error) {
+			return len(rec.Values.FilterByName(v.Name)) == 0, nil
+		})
 
-func (svc *importSession) FindByID(ctx context.Context, sessionID uint64) (*recordImportSession, error) {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+		rec.Values = append(kept.GetClean(), rec.Values...)
+	}
 
-	userID := auth.GetIdentityFromContext(ctx).Identity()
-	i := svc.indexOf(userID, sessionID)
-	if i >= 0 {
-		return svc.records[i], nil
+	switch {
+	case imp.ses.DryRun:
+		return imp.record.DryRun(ctx, rec)
+	case rec.ID > 0:
+		_, err = imp.record.Update(ctx, rec)
+	default:
+		_, err = imp.record.Create(ctx, rec)
 	}
-	return nil, fmt.Errorf("compose.service.RecordImportSessionNotFound")
+
+	return
 }
 
-// https://stackoverflow.com/a/37335777
-func remove(s recordSet, i int) recordSet {
-	s[len(s)-1], s[i] = s[i], s[len(s)-1]
-	return s[:len(s)-1]
+// values converts mapped columns of the row to record values
+//
+// User references are resolved by ID, email or handle
+func (imp *recordImporter) values(ctx context.Context, row map[string]string) (vv types.RecordValueSet, err error) {
+	for _, col := range imp.ses.Fields.Columns() {
+		var (
+			name = imp.ses.Fields[col]
+			v    = row[col]
+		)
+
+		if name == "" || v == "" {
+			continue
+		}
+
+		rv := &types.RecordValue{Name: name, Value: v}
+
+		if f := imp.m.Fields.FindByName(name); f != nil && f.Kind == "User" {
+			if rv.Ref, err = imp.user(ctx, v); err != nil {
+				return
+			}
+
+			rv.Value = strconv.FormatUint(rv.Ref, 10)
+		}
+
+		vv = append(vv, rv)
+	}
+
+	return
 }
 
-func (svc *importSession) DeleteByID(ctx context.Context, sessionID uint64) error {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+func (imp *recordImporter) user(ctx context.Context, ident string) (uint64, error) {
+	if userID, has := imp.users[ident]; has {
+		return userID, nil
+	}
 
-	userID := auth.GetIdentityFromContext(ctx).Identity()
-	i := svc.indexOf(userID, sessionID)
+	s := imp.svc.store
 
-	if i >= 0 {
-		svc.records = remove(svc.records, i)
+	userID, _ := strconv.ParseUint(ident, 10, 64)
+	if userID > 0 {
+		if _, err := store.LookupUserByID(ctx, s, userID); err == nil {
+			imp.users[ident] = userID
+			return userID, nil
+		}
 	}
-	return nil
+
+	if u, err := store.LookupUserByEmail(ctx, s, ident); err == nil {
+		imp.users[ident] = u.ID
+		return u.ID, nil
+	}
+
+	if u, err := store.LookupUserByHandle(ctx, s, ident); err == nil {
+		imp.users[ident] = u.ID
+		return u.ID, nil
+	}
+
+	return 0, fmt.Errorf("could not resolve user %q", ident)
 }
 
-// @todo run this in some interval
-func (svc *importSession) clean(ctx context.Context) {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+// existing finds the record that matches the key column
+//
+// When key column is mapped to a module field, record is matched by value of that field,
+// otherwise key column holds record ID
+func (imp *recordImporter) existing(ctx context.Context, row map[string]string) (*types.Record, error) {
+	var (
+		s     = imp.svc.store
+		value = strings.TrimSpace(row[imp.ses.Key])
+		name  = imp.ses.Fields[imp.ses.Key]
+	)
+
+	if value == "" {
+		return nil, nil
+	}
+
+	if name == "" {
+		recordID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, RecordErrInvalidID()
+		}
+
+		rec, err := store.LookupComposeRecordByID(ctx, s, imp.m, recordID)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return rec, err
+	}
 
-	for i := len(svc.records) - 1; i >= 0; i-- {
-		r := svc.records[i]
-		if time.Now().After(r.UpdatedAt.Add(time.Hour * 24 * 3)) {
-			svc.records = remove(svc.records, i)
+	f := imp.m.Fields.FindByName(name)
+	if f == nil {
+		return nil, RecordErrFieldNotFound(&recordActionProps{field: name})
+	}
+
+	if f.Kind == "User" {
+		userID, err := imp.user(ctx, value)
+		if err != nil {
+			return nil, err
 		}
+
+		value = strconv.FormatUint(userID, 10)
+	}
+
+	set, _, err := store.SearchComposeRecords(ctx, s, imp.m, types.RecordFilter{
+		NamespaceID: imp.ses.NamespaceID,
+		ModuleID:    imp.ses.ModuleID,
+		Query:       fmt.Sprintf("%s = '%s'", f.Name, strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)),
+	})
+
+	switch {
+	case err != nil:
+		return nil, err
+	case len(set) > 1:
+		return nil, fmt.Errorf("more than one record matches %s %q", f.Name, value)
+	case len(set) == 1:
+		return set[0], nil
+	default:
+		return nil, nil
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/pkg/label"
@@ -216,6 +217,10 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 			return err
 		}
 
+		if err = svc.uniqueConstraintsCheck(new); err != nil {
+			return err
+		}
+
 		new.ID = nextID()
 		new.CreatedAt = *now()
 		new.UpdatedAt = nil
@@ -251,9 +256,34 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 		return nil
 	})
 
+	if err == nil {
+		// DDL statements are committed implicitly by some databases
+		// so dedicated record table is created after the transaction;
+		// module is removed when table can not be created
+		if err = store.UpgradeComposeRecordPartition(ctx, svc.store, new); err != nil {
+			_ = svc.removeCreated(ctx, new)
+		}
+	}
+
 	return new, svc.recordAction(ctx, aProps, ModuleActionCreate, err)
 }
 
+// removeCreated removes module, its fields and labels
+// when it could not be completely created
+func (svc module) removeCreated(ctx context.Context, m *types.Module) error {
+	return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		if err = store.DeleteExtraLabels(ctx, s, m.LabelResourceKind(), m.ID); err != nil {
+			return
+		}
+
+		if err = store.DeleteComposeModuleField(ctx, s, m.Fields...); err != nil {
+			return
+		}
+
+		return store.DeleteComposeModule(ctx, s, m)
+	})
+}
+
 func (svc module) Update(ctx context.Context, upd *types.Module) (c *types.Module, err error) {
 	return svc.updater(ctx, upd.NamespaceID, upd.ID, ModuleActionUpdate, svc.handleUpdate(ctx, upd))
 }
@@ -343,6 +373,17 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 		return err
 	})
 
+	if err == nil && changes&moduleFieldsChanged > 0 {
+		// make sure all physical columns exist;
+		// DDL statements are committed implicitly by some databases
+		// so this runs after the transaction
+		err = store.UpgradeComposeRecordPartition(ctx, svc.store, m)
+	}
+
+	if err == nil && changes&moduleChanged > 0 {
+		values.ForgetUniqueFilters(moduleID)
+	}
+
 	return m, svc.recordAction(ctx, aProps, action, err)
 }
 
@@ -396,6 +437,44 @@ func (svc module) uniqueCheck(ctx context.Context, m *types.Module) (err error)
 	return nil
 }
 
+// uniqueConstraintsCheck verifies module's unique constraints against its fields
+//
+// Constraint must have at least one field; all fields must exist, hold a single
+// value and can not be computed from related records (lookup & rollup) as
+// those change without the record being written.
+func (svc module) uniqueConstraintsCheck(m *types.Module) error {
+	for _, c := range m.Config.UniqueConstraints {
+		if len(c.Fields) == 0 {
+			return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("constraint %q without fields", c.Label()))
+		}
+
+		seen := make(map[string]bool)
+		for _, name := range c.Fields {
+			f := m.Fields.FindByName(name)
+			switch {
+			case f == nil:
+				return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("unknown field %q", name))
+			case f.Multi:
+				return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("multi-value field %q", name))
+			case f.Expressions.IsRelated():
+				return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("related value field %q", name))
+			case seen[name]:
+				return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("duplicate field %q", name))
+			}
+
+			seen[name] = true
+		}
+
+		if c.Filter != "" {
+			if _, err := expr.Parser().NewEvaluable(c.Filter); err != nil {
+				return ModuleErrInvalidUniqueConstraint().Wrap(fmt.Errorf("invalid filter expression %q: %w", c.Filter, err))
+			}
+		}
+	}
+
+	return nil
+}
+
 func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpdateHandler {
 	return func(ctx context.Context, ns *types.Namespace, res *types.Module) (changes moduleChanges, err error) {
 		if isStale(upd.UpdatedAt, res.UpdatedAt, res.CreatedAt) {
@@ -410,6 +489,10 @@ func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpd
 			return moduleUnchanged, err
 		}
 
+		if err = svc.uniqueConstraintsCheck(upd); err != nil {
+			return moduleUnchanged, err
+		}
+
 		if !svc.ac.CanUpdateModule(ctx, res) {
 			return moduleUnchanged, ModuleErrNotAllowedToUpdate()
 		}
@@ -442,6 +525,14 @@ func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpd
 
 		}
 
+		// only unique constraints can be changed,
+		// record partition is configured when module is created
+		if len(res.Config.UniqueConstraints)+len(upd.Config.UniqueConstraints) > 0 &&
+			!reflect.DeepEqual(res.Config.UniqueConstraints, upd.Config.UniqueConstraints) {
+			changes |= moduleChanged
+			res.Config.UniqueConstraints = upd.Config.UniqueConstraints
+		}
+
 		// @todo make field-change detection more optimal
 		if !reflect.DeepEqual(res.Fields, upd.Fields) {
 			changes |= moduleFieldsChanged
@@ -678,6 +678,42 @@ func ModuleErrStaleData(mm ...*moduleActionProps) *errors.Error {
 	return e
 }
 
+// ModuleErrInvalidUniqueConstraint returns "compose:module.invalidUniqueConstraint" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrInvalidUniqueConstraint(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid unique constraint", nil),
+
+		errors.Meta("type", "invalidUniqueConstraint"),
+		errors.Meta("resource", "compose:module"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(moduleLogMetaKey{}, "invalid unique constraint on {{module}}"),
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "module.errors.invalidUniqueConstraint"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ModuleErrInvalidNamespaceID returns "compose:module.invalidNamespaceID" as *errors.Error
 //
 //
@@ -8,28 +8,36 @@ import (
 	"github.com/cortezaproject/corteza-server/compose/service/event"
 	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
 )
 
 type (
 	namespace struct {
-		actionlog actionlog.Recorder
-		ac        namespaceAccessController
-		eventbus  eventDispatcher
-		store     store.Storer
+		actionlog  actionlog.Recorder
+		ac         namespaceAccessController
+		eventbus   eventDispatcher
+		store      store.Storer
+		cloneStore NamespaceCloneStore
+		log        *zap.Logger
 	}
 
 	namespaceAccessController interface {
+		CanGrant(context.Context) bool
+
 		CanSearchNamespaces(context.Context) bool
 		CanCreateNamespace(context.Context) bool
 		CanReadNamespace(context.Context, *types.Namespace) bool
 		CanUpdateNamespace(context.Context, *types.Namespace) bool
 		CanDeleteNamespace(context.Context, *types.Namespace) bool
+		CanManageNamespace(context.Context, *types.Namespace) bool
 
 		Grant(ctx context.Context, rr ...*rbac.Rule) error
 	}
@@ -43,6 +51,19 @@ type (
 		Create(ctx context.Context, namespace *types.Namespace) (*types.Namespace, error)
 		Update(ctx context.Context, namespace *types.Namespace) (*types.Namespace, error)
 		DeleteByID(ctx context.Context, namespaceID uint64) error
+
+		Clone(ctx context.Context, namespaceID uint64, dup *types.Namespace, handlePrefix string, records bool) (*types.Namespace, *types.NamespaceCloneProgress, error)
+		SetCloneStore(NamespaceCloneStore)
+	}
+
+	// NamespaceCloneStore decodes resources of the cloned namespace from the store
+	// and prepares encoder that stores them as new resources
+	//
+	// Implemented by the envoy store package (pkg/envoy/store) that depends on
+	// compose services; it is set when services are initialized
+	NamespaceCloneStore interface {
+		Decode(ctx context.Context, s store.Storer, ns *types.Namespace, records bool) (resource.InterfaceSet, error)
+		Encoder(s store.Storer, onRecord func()) envoy.PrepareEncoder
 	}
 
 	namespaceUpdateHandler func(ctx context.Context, ns *types.Namespace) (namespaceChanges, error)
@@ -50,6 +71,9 @@ type (
 )
 
 const (
+	// progress of the namespace clone is logged after every n copied records
+	namespaceCloneProgressInterval = 1000
+
 	namespaceUnchanged     namespaceChanges = 0
 	namespaceChanged       namespaceChanges = 1
 	namespaceLabelsChanged namespaceChanges = 2
@@ -61,6 +85,7 @@ func Namespace() *namespace {
 		eventbus:  eventbus.Service(),
 		actionlog: DefaultActionlog,
 		store:     DefaultStore,
+		log:       DefaultLogger.Named("namespace"),
 	}
 }
 
@@ -222,6 +247,203 @@ func (svc namespace) UndeleteByID(ctx context.Context, namespaceID uint64) error
 	return trim1st(svc.updater(ctx, namespaceID, NamespaceActionUndelete, svc.handleUndelete))
 }
 
+// SetCloneStore sets store decoder and encoder used for cloning namespaces
+func (svc *namespace) SetCloneStore(cs NamespaceCloneStore) {
+	svc.cloneStore = cs
+}
+
+// Clone copies namespace with all of its resources
+//
+// Resources are loaded, rebuilt and stored with the envoy pipeline that assigns
+// new IDs and remaps the references between the copied resources.
+// Slug and name of the cloned namespace are taken from dup; handles of modules,
+// pages and charts are prefixed with handlePrefix. Records are copied when requested
+func (svc namespace) Clone(ctx context.Context, namespaceID uint64, dup *types.Namespace, handlePrefix string, records bool) (ns *types.Namespace, progress *types.NamespaceCloneProgress, err error) {
+	var (
+		rules  rbac.RuleSet
+		aProps = &namespaceActionProps{namespace: &types.Namespace{ID: namespaceID}, changed: dup}
+	)
+
+	progress = &types.NamespaceCloneProgress{StartedAt: *now()}
+
+	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		var (
+			orig *types.Namespace
+			rr   resource.InterfaceSet
+		)
+
+		if orig, err = loadNamespace(ctx, s, namespaceID); err != nil {
+			return
+		}
+
+		aProps.setNamespace(orig)
+
+		if !svc.ac.CanReadNamespace(ctx, orig) {
+			return NamespaceErrNotAllowedToRead()
+		}
+
+		// all resources of the namespace are copied, regardless
+		// of the (resource level) permissions to read them
+		if !svc.ac.CanManageNamespace(ctx, orig) {
+			return NamespaceErrNotAllowedToClone()
+		}
+
+		if !svc.ac.CanCreateNamespace(ctx) {
+			return NamespaceErrNotAllowedToCreate()
+		}
+
+		if dup.Slug == "" || !handle.IsValid(dup.Slug) {
+			return NamespaceErrInvalidHandle()
+		}
+
+		if err = svc.uniqueCheck(ctx, dup); err != nil {
+			return err
+		}
+
+		if svc.cloneStore == nil {
+			return errors.Internal("namespace clone store not set")
+		}
+
+		if rr, err = svc.cloneStore.Decode(ctx, s, orig, records); err != nil {
+			return err
+		}
+
+		if rr, rules, err = prepareNamespaceClone(rr, dup, handlePrefix, svc.ac.CanGrant(ctx), progress); err != nil {
+			return err
+		}
+
+		log := svc.log.With(zap.Uint64("namespaceID", orig.ID), zap.String("slug", dup.Slug))
+		log.Info("cloning namespace",
+			zap.Uint("modules", progress.Modules),
+			zap.Uint("pages", progress.Pages),
+			zap.Uint("charts", progress.Charts),
+			zap.Uint("rules", progress.Rules),
+		)
+
+		// records are stored one by one while the
+		// encoder walks them; report how far we got
+		se := svc.cloneStore.Encoder(s, func() {
+			progress.Records++
+			if progress.Records%namespaceCloneProgressInterval == 0 {
+				log.Info("cloning namespace records", zap.Uint("records", progress.Records))
+			}
+		})
+
+		g, err := envoy.NewBuilder(se).Build(ctx, rr...)
+		if err != nil {
+			return err
+		}
+
+		if err = envoy.Encode(ctx, g, se); err != nil {
+			return err
+		}
+
+		for _, r := range rr {
+			if res, ok := r.(*resource.ComposeNamespace); ok {
+				ns = res.Res
+			}
+		}
+
+		if ns == nil || ns.ID == 0 {
+			return NamespaceErrNotFound()
+		}
+
+		progress.FinishedAt = now()
+		log.Info("namespace cloned", zap.Uint64("cloneID", ns.ID), zap.Uint("records", progress.Records))
+
+		aProps.setChanged(ns)
+		return nil
+	})
+
+	if err == nil && len(rules) > 0 {
+		// Access rules were stored by the encoder;
+		// let RBAC service know about them
+		err = svc.ac.Grant(ctx, rules...)
+	}
+
+	return ns, progress, svc.recordAction(ctx, aProps, NamespaceActionClone, err)
+}
+
+// prepareNamespaceClone renames namespace & prefixes handles of decoded resources
+//
+// Resource identifiers are left as they are so that the references between
+// resources are still resolved by the encoder.
+// Access rules are copied only when the user is allowed to grant permissions;
+// rules that are not bound to any of the cloned resources are omitted.
+func prepareNamespaceClone(rr resource.InterfaceSet, dup *types.Namespace, handlePrefix string, canGrant bool, progress *types.NamespaceCloneProgress) (out resource.InterfaceSet, rules rbac.RuleSet, err error) {
+	var (
+		hasRecords bool
+
+		prefix = func(h string) (string, error) {
+			if h == "" || handlePrefix == "" {
+				return h, nil
+			}
+
+			if h = handlePrefix + h; !handle.IsValid(h) {
+				return "", NamespaceErrInvalidHandle()
+			}
+
+			return h, nil
+		}
+	)
+
+	for _, r := range rr {
+		if _, ok := r.(*resource.ComposeRecord); ok {
+			hasRecords = true
+		}
+	}
+
+	out = make(resource.InterfaceSet, 0, len(rr))
+	for _, r := range rr {
+		switch res := r.(type) {
+		case *resource.ComposeNamespace:
+			res.Res.Slug = dup.Slug
+			if dup.Name != "" {
+				res.Res.Name = dup.Name
+			}
+
+		case *resource.ComposeModule:
+			if res.Res.Handle, err = prefix(res.Res.Handle); err != nil {
+				return
+			}
+			progress.Modules++
+
+		case *resource.ComposePage:
+			if res.Res.Handle, err = prefix(res.Res.Handle); err != nil {
+				return
+			}
+			progress.Pages++
+
+		case *resource.ComposeChart:
+			if res.Res.Handle, err = prefix(res.Res.Handle); err != nil {
+				return
+			}
+			progress.Charts++
+
+		case *resource.RbacRule:
+			if !canGrant {
+				continue
+			}
+
+			if res.RefResource == nil && len(res.RefPath) == 0 {
+				// generic rule, not related to the namespace
+				continue
+			}
+
+			if !hasRecords && res.RefResource != nil && res.RefResource.ResourceType == types.RecordResourceType {
+				continue
+			}
+
+			rules = append(rules, res.Res)
+			progress.Rules++
+		}
+
+		out = append(out, r)
+	}
+
+	return
+}
+
 func (svc namespace) updater(ctx context.Context, namespaceID uint64, action func(...*namespaceActionProps) *namespaceAction, fn namespaceUpdateHandler) (*types.Namespace, error) {
 	var (
 		changes namespaceChanges
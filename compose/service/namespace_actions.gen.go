@@ -371,6 +371,26 @@ func NamespaceActionReorder(props ...*namespaceActionProps) *namespaceAction {
 	return a
 }
 
+// NamespaceActionClone returns "compose:namespace.clone" action
+//
+// This function is auto-generated.
+//
+func NamespaceActionClone(props ...*namespaceActionProps) *namespaceAction {
+	a := &namespaceAction{
+		timestamp: time.Now(),
+		resource:  "compose:namespace",
+		action:    "clone",
+		log:       "cloned {{namespace}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -799,6 +819,42 @@ func NamespaceErrNotAllowedToUndelete(mm ...*namespaceActionProps) *errors.Error
 	return e
 }
 
+// NamespaceErrNotAllowedToClone returns "compose:namespace.notAllowedToClone" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func NamespaceErrNotAllowedToClone(mm ...*namespaceActionProps) *errors.Error {
+	var p = &namespaceActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to clone this namespace", nil),
+
+		errors.Meta("type", "notAllowedToClone"),
+		errors.Meta("resource", "compose:namespace"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(namespaceLogMetaKey{}, "could not clone {{namespace}}; insufficient permissions"),
+		errors.Meta(namespacePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "namespace.errors.notAllowedToClone"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
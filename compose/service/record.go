@@ -2,12 +2,9 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"regexp"
-	"sort"
 	"strconv"
-	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service/event"
 	"github.com/cortezaproject/corteza-server/compose/service/values"
@@ -15,7 +12,6 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
-	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/label"
@@ -93,9 +89,13 @@ type (
 		Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (types.RecordSet, error)
 
 		Validate(ctx context.Context, rec *types.Record) error
+		DryRun(ctx context.Context, rec *types.Record) error
 
 		DeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
 
+		SearchRevisions(ctx context.Context, f types.RecordRevisionFilter) (types.RecordRevisionSet, types.RecordRevisionFilter, error)
+		RestoreRevision(ctx context.Context, namespaceID, moduleID, recordID, revisionID uint64) (*types.Record, error)
+
 		Organize(ctx context.Context, namespaceID, moduleID, recordID uint64, sortingField, sortingValue, sortingFilter, valueField, value string) error
 
 		Iterator(ctx context.Context, f types.RecordFilter, fn eventbus.HandlerFn, action string) (err error)
@@ -104,46 +104,6 @@ type (
 
 		EventEmitting(enable bool)
 	}
-
-	recordImportSession struct {
-		Name        string `json:"-"`
-		SessionID   uint64 `json:"sessionID,string"`
-		UserID      uint64 `json:"userID,string"`
-		NamespaceID uint64 `json:"namespaceID,string"`
-		ModuleID    uint64 `json:"moduleID,string"`
-
-		OnError  string                `json:"onError"`
-		Fields   map[string]string     `json:"fields"`
-		Key      string                `json:"key"`
-		Progress *RecordImportProgress `json:"progress"`
-
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-
-		Resources []resource.Interface `json:"-"`
-	}
-
-	RecordImportProgress struct {
-		StartedAt  *time.Time `json:"startedAt"`
-		FinishedAt *time.Time `json:"finishedAt"`
-		EntryCount uint64     `json:"entryCount"`
-		Completed  uint64     `json:"completed"`
-		Failed     uint64     `json:"failed"`
-		FailReason string     `json:"failReason,omitempty"`
-
-		FailLog *FailLog `json:"failLog,omitempty"`
-	}
-
-	FailLog struct {
-		// Records holds an array of record indexes
-		Records          RecordIndex `json:"records"`
-		RecordsTruncated bool        `json:"recordsTruncated"`
-		// Errors specifies a map of occurred errors & the number of
-		Errors ErrorIndex `json:"errors"`
-	}
-
-	RecordIndex []int
-	ErrorIndex  map[string]int
 )
 
 func Record() RecordService {
@@ -410,7 +370,7 @@ func (svc record) Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (r
 
 			case types.OperationTypeUpdate:
 				action = RecordActionUpdate
-				r, err = svc.update(ctx, r)
+				r, err = svc.update(ctx, r, types.RecordRevisionUpdate)
 
 			case types.OperationTypeDelete:
 				action = RecordActionDelete
@@ -519,7 +479,15 @@ func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Rec
 	}
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.CreateComposeRecord(ctx, s, m, new)
+		if err = RecordUniqueConstraintCheck(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		if err = store.CreateComposeRecord(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		return createRecordRevision(ctx, s, types.RecordRevisionCreate, invokerID, new, nil)
 	})
 
 	if err != nil {
@@ -654,6 +622,10 @@ func RecordPreparer(ctx context.Context, s store.Storer, ss recordValuesSanitize
 	new.Values = ss.Run(m, new.Values)
 
 	rve := &types.RecordValueErrorSet{}
+
+	// Lookup and rollup values are computed before
+	// expressions so that they can be used in formulas
+	values.Related(ctx, recordRelatedLoader{store: s}, m, new, rve)
 	values.Expression(ctx, m, new, nil, rve)
 
 	if !rve.IsValid() {
@@ -666,6 +638,14 @@ func RecordPreparer(ctx context.Context, s store.Storer, ss recordValuesSanitize
 		return rve
 	}
 
+	// Composite unique constraints are checked again,
+	// under lock, when record is stored
+	rve = &types.RecordValueErrorSet{}
+	values.UniqueConstraints(ctx, recordDuplicateFinder(s), m, new, rve)
+	if !rve.IsValid() {
+		return rve
+	}
+
 	// Cleanup the values
 	new.Values = new.Values.GetClean()
 
@@ -700,7 +680,9 @@ func RecordValueDefaults(m *types.Module, vv types.RecordValueSet) (out types.Re
 
 // Raw update function that is responsible for value validation, event dispatching
 // and update.
-func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Record, err error) {
+//
+// Changed values are stored as a new record revision with the given operation
+func (svc record) update(ctx context.Context, upd *types.Record, op types.RecordRevisionOperation) (rec *types.Record, err error) {
 	var (
 		aProps    = &recordActionProps{changed: upd}
 		invokerID = auth.GetIdentityFromContext(ctx).Identity()
@@ -768,13 +750,21 @@ func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Rec
 	}
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+		if err = RecordUniqueConstraintCheck(ctx, s, m, upd); err != nil {
+			return err
+		}
+
 		if label.Changed(old.Labels, upd.Labels) {
 			if err = label.Update(ctx, s, upd); err != nil {
 				return err
 			}
 		}
 
-		return store.UpdateComposeRecord(ctx, s, m, upd)
+		if err = store.UpdateComposeRecord(ctx, s, m, upd); err != nil {
+			return err
+		}
+
+		return createRecordRevision(ctx, s, op, invokerID, upd, old.Values)
 	})
 
 	if err != nil {
@@ -870,7 +860,7 @@ func (svc record) Update(ctx context.Context, upd *types.Record) (rec *types.Rec
 	)
 
 	err = func() error {
-		rec, err = svc.update(ctx, upd)
+		rec, err = svc.update(ctx, upd, types.RecordRevisionUpdate)
 		aProps.setRecord(rec)
 		return err
 	}()
@@ -964,7 +954,11 @@ func (svc record) delete(ctx context.Context, namespaceID, moduleID, recordID ui
 	del.DeletedBy = invokerID
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.UpdateComposeRecord(ctx, s, m, del)
+		if err = store.UpdateComposeRecord(ctx, s, m, del); err != nil {
+			return err
+		}
+
+		return createRecordRevision(ctx, s, types.RecordRevisionDelete, invokerID, del, del.Values)
 	})
 
 	if err != nil {
@@ -1207,6 +1201,60 @@ func (svc record) Validate(ctx context.Context, rec *types.Record) error {
 	}
 }
 
+// DryRun checks access, sanitizes and validates the record
+// in the same way create (for new records) or update would, without storing it
+//
+// Automation scripts are not executed
+func (svc record) DryRun(ctx context.Context, rec *types.Record) (err error) {
+	var (
+		invokerID = auth.GetIdentityFromContext(ctx).Identity()
+
+		m   *types.Module
+		old *types.Record
+		rve *types.RecordValueErrorSet
+	)
+
+	if rec.ID == 0 {
+		if _, m, err = loadModuleWithNamespace(ctx, svc.store, rec.NamespaceID, rec.ModuleID); err != nil {
+			return
+		}
+
+		if !svc.ac.CanCreateRecordOnModule(ctx, m) {
+			return RecordErrNotAllowedToCreate()
+		}
+	} else {
+		if _, m, old, err = loadRecordCombo(ctx, svc.store, rec.NamespaceID, rec.ModuleID, rec.ID); err != nil {
+			return
+		}
+
+		if !svc.ac.CanUpdateRecord(ctx, old) {
+			return RecordErrNotAllowedToUpdate()
+		}
+	}
+
+	if err = RecordValueSanitization(m, rec.Values); err != nil {
+		return
+	}
+
+	// work on a copy; procCreate and procUpdate modify the record
+	rec = rec.Clone()
+	rec.SetModule(m)
+
+	if old == nil {
+		rec.Values = RecordValueDefaults(m, rec.Values)
+		rve = svc.procCreate(ctx, invokerID, m, rec)
+	} else {
+		old.SetModule(m)
+		rve = svc.procUpdate(ctx, invokerID, m, rec, old)
+	}
+
+	if !rve.IsValid() {
+		return RecordErrValueInput().Wrap(rve)
+	}
+
+	return nil
+}
+
 // TriggerScript loads requested record sanitizes and validates values and passes all to the automation script
 //
 // For backward compatibility (of controllers), it returns module+record
@@ -1338,6 +1386,10 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+						if err := RecordUniqueConstraintCheck(ctx, s, m, rec); err != nil {
+							return err
+						}
+
 						return store.CreateComposeRecord(ctx, s, m, rec)
 					})
 				case "update":
@@ -1349,6 +1401,10 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+						if err := RecordUniqueConstraintCheck(ctx, s, m, rec); err != nil {
+							return err
+						}
+
 						return store.UpdateComposeRecord(ctx, s, m, rec)
 					})
 				case "delete":
@@ -1446,38 +1502,3 @@ func toLabeledRecords(set []*types.Record) []label.LabeledResource {
 
 	return ll
 }
-
-func (ei ErrorIndex) Add(err string) {
-	if _, has := ei[err]; has {
-		ei[err]++
-	} else {
-		ei[err] = 1
-	}
-}
-
-func (ri RecordIndex) MarshalJSON() ([]byte, error) {
-	sort.Ints(ri)
-
-	rr := make([][]int, 0, len(ri))
-	start := -1
-	crt := -1
-
-	for i := 0; i < len(ri); i++ {
-		if start == -1 {
-			start = ri[i]
-			crt = ri[i]
-			continue
-		}
-
-		// If the index increases for more then 1, the set is complete
-		if ri[i]-crt > 1 {
-			rr = append(rr, []int{start, crt})
-			start = ri[i]
-		}
-
-		crt = ri[i]
-	}
-
-	rr = append(rr, []int{start, crt})
-	return json.Marshal(rr)
-}
@@ -30,6 +30,7 @@ type (
 		field         string
 		value         string
 		valueErrors   *types.RecordValueErrorSet
+		revision      *types.RecordRevision
 	}
 
 	recordAction struct {
@@ -156,6 +157,17 @@ func (p *recordActionProps) setValueErrors(valueErrors *types.RecordValueErrorSe
 	return p
 }
 
+// setRevision updates recordActionProps's revision
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *recordActionProps) setRevision(revision *types.RecordRevision) *recordActionProps {
+	p.revision = revision
+	return p
+}
+
 // Serialize converts recordActionProps to actionlog.Meta
 //
 // This function is auto-generated.
@@ -202,6 +214,11 @@ func (p recordActionProps) Serialize() actionlog.Meta {
 	if p.valueErrors != nil {
 		m.Set("valueErrors.set", p.valueErrors.Set, true)
 	}
+	if p.revision != nil {
+		m.Set("revision.ID", p.revision.ID, true)
+		m.Set("revision.recordID", p.revision.RecordID, true)
+		m.Set("revision.operation", p.revision.Operation, true)
+	}
 
 	return m
 }
@@ -337,6 +354,22 @@ func (p recordActionProps) Format(in string, err error) string {
 		)
 		pairs = append(pairs, "{{valueErrors.set}}", fns(p.valueErrors.Set))
 	}
+
+	if p.revision != nil {
+		// replacement for "{{revision}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{revision}}",
+			fns(
+				p.revision.ID,
+				p.revision.RecordID,
+				p.revision.Operation,
+			),
+		)
+		pairs = append(pairs, "{{revision.ID}}", fns(p.revision.ID))
+		pairs = append(pairs, "{{revision.recordID}}", fns(p.revision.RecordID))
+		pairs = append(pairs, "{{revision.operation}}", fns(p.revision.Operation))
+	}
 	return strings.NewReplacer(pairs...).Replace(in)
 }
 
@@ -532,6 +565,46 @@ func RecordActionUndelete(props ...*recordActionProps) *recordAction {
 	return a
 }
 
+// RecordActionSearchRevisions returns "compose:record.searchRevisions" action
+//
+// This function is auto-generated.
+//
+func RecordActionSearchRevisions(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "searchRevisions",
+		log:       "searched for revisions of {{record}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// RecordActionRestoreRevision returns "compose:record.restoreRevision" action
+//
+// This function is auto-generated.
+//
+func RecordActionRestoreRevision(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "restoreRevision",
+		log:       "restored {{record}} to {{revision}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // RecordActionImport returns "compose:record.import" action
 //
 // This function is auto-generated.
@@ -970,6 +1043,40 @@ func RecordErrStaleData(mm ...*recordActionProps) *errors.Error {
 	return e
 }
 
+// RecordErrRevisionNotFound returns "compose:record.revisionNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrRevisionNotFound(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record revision not found", nil),
+
+		errors.Meta("type", "revisionNotFound"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.revisionNotFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // RecordErrNotAllowedToRead returns "compose:record.notAllowedToRead" as *errors.Error
 //
 //
@@ -1366,6 +1473,78 @@ func RecordErrImportSessionAlreadActive(mm ...*recordActionProps) *errors.Error
 	return e
 }
 
+// RecordErrImportInvalidMode returns "compose:record.importInvalidMode" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrImportInvalidMode(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid import mode", nil),
+
+		errors.Meta("type", "importInvalidMode"),
+		errors.Meta("resource", "compose:record"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(recordLogMetaKey{}, "failed to start import session; invalid import mode"),
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.importInvalidMode"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// RecordErrImportInvalidKey returns "compose:record.importInvalidKey" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrImportInvalidKey(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("import key must be one of the columns from the import file", nil),
+
+		errors.Meta("type", "importInvalidKey"),
+		errors.Meta("resource", "compose:record"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(recordLogMetaKey{}, "failed to start import session; invalid import key"),
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.importInvalidKey"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // RecordErrFieldNotFound returns "compose:record.fieldNotFound" as *errors.Error
 //
 //
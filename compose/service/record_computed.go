@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/service/values"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
+)
+
+type (
+	recordComputed struct {
+		store  store.Storer
+		loader values.RelatedLoader
+		log    *zap.Logger
+
+		// modules with computed values, cached per namespace
+		mux      sync.RWMutex
+		modCache map[uint64]recordComputedModules
+	}
+
+	recordComputedModules struct {
+		mm      types.ModuleSet
+		expires time.Time
+	}
+
+	recordComputedEventRegistry interface {
+		Register(h eventbus.HandlerFn, ops ...eventbus.HandlerRegOp) uintptr
+	}
+
+	// recordRelatedLoader loads related modules and records from the store
+	recordRelatedLoader struct {
+		store store.Storer
+	}
+)
+
+const (
+	// Modules are cached for this long; cache is invalidated on module changes
+	// but modules can also be changed directly in the store (ie. import)
+	recordComputedModulesTTL = time.Minute
+)
+
+// RecordComputed keeps values of lookup and rollup fields in sync
+// with the related records
+//
+// When record is created, updated or deleted, values are recalculated on
+// all records that depend on it; changes are propagated further to records
+// that depend on the recalculated ones.
+func RecordComputed(s store.Storer, log *zap.Logger) *recordComputed {
+	return &recordComputed{
+		store:    s,
+		loader:   recordRelatedLoader{store: s},
+		log:      log,
+		modCache: make(map[uint64]recordComputedModules),
+	}
+}
+
+// Register hooks recalculation to record's after-create, after-update and after-delete events
+//
+// Cached modules are invalidated on module's after-create, after-update and after-delete events
+func (svc *recordComputed) Register(eb recordComputedEventRegistry) {
+	eb.Register(
+		func(ctx context.Context, ev eventbus.Event) error {
+			if me, ok := ev.(interface{ Module() *types.Module }); ok && me.Module() != nil {
+				svc.invalidate(me.Module().NamespaceID)
+			}
+
+			return nil
+		},
+		eventbus.For("compose:module"),
+		eventbus.On("afterCreate", "afterUpdate", "afterDelete"),
+	)
+
+	eb.Register(
+		func(ctx context.Context, ev eventbus.Event) error {
+			re, ok := ev.(interface {
+				Record() *types.Record
+				OldRecord() *types.Record
+				Module() *types.Module
+			})
+
+			if !ok || re.Module() == nil {
+				return nil
+			}
+
+			if err := svc.Propagate(ctx, re.Module(), re.Record(), re.OldRecord()); err != nil {
+				// after-events are immutable and errors would be ignored anyway
+				svc.log.Error(
+					"failed to recalculate values on related records",
+					zap.Uint64("moduleID", re.Module().ID),
+					zap.Error(err),
+				)
+			}
+
+			return nil
+		},
+		eventbus.For("compose:record"),
+		eventbus.On("afterCreate", "afterUpdate", "afterDelete"),
+	)
+}
+
+// Propagate recalculates values on records that depend on the given records
+//
+// Records of the module m are the ones that were changed; when updated,
+// both new and old record should be passed so that records that are
+// no longer referenced are recalculated as well
+func (svc *recordComputed) Propagate(ctx context.Context, m *types.Module, rr ...*types.Record) error {
+	var (
+		visited = make(map[uint64]bool)
+		changed = make(types.RecordSet, 0, len(rr))
+	)
+
+	for _, r := range rr {
+		if r != nil {
+			visited[r.ID] = true
+			changed = append(changed, r)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	mm, err := svc.modules(ctx, m.NamespaceID)
+	if err != nil || len(mm) == 0 {
+		return err
+	}
+
+	return svc.propagate(ctx, mm, m, visited, changed...)
+}
+
+// Recalculate recalculates computed values on the given records of the module m
+// and propagates changes to the dependent records
+//
+// Returns number of records with changed values
+func (svc *recordComputed) Recalculate(ctx context.Context, m *types.Module, rr ...*types.Record) (n int, err error) {
+	var (
+		changed bool
+	)
+
+	for _, r := range rr {
+		if changed, err = svc.recalculate(ctx, m, r); err != nil {
+			return n, fmt.Errorf("could not recalculate record %d: %w", r.ID, err)
+		}
+
+		if !changed {
+			continue
+		}
+
+		n++
+		if err = svc.Propagate(ctx, m, r); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (svc *recordComputed) propagate(ctx context.Context, mm types.ModuleSet, m *types.Module, visited map[uint64]bool, rr ...*types.Record) error {
+	for _, dm := range mm {
+		ids, err := svc.dependants(ctx, dm, m, rr)
+		if err != nil {
+			return err
+		}
+
+		for _, ID := range ids {
+			if visited[ID] {
+				continue
+			}
+
+			visited[ID] = true
+
+			r, err := svc.loader.Record(ctx, dm, ID)
+			if err != nil {
+				return err
+			}
+
+			if r == nil {
+				// referenced record does not exist (anymore)
+				continue
+			}
+
+			changed, err := svc.recalculate(ctx, dm, r)
+			if err != nil {
+				return fmt.Errorf("could not recalculate record %d: %w", r.ID, err)
+			}
+
+			if !changed {
+				continue
+			}
+
+			if err = svc.propagate(ctx, mm, dm, visited, r); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dependants returns IDs of the records on module dm with values
+// computed from the records of module m
+func (svc *recordComputed) dependants(ctx context.Context, dm, m *types.Module, rr types.RecordSet) (ids []uint64, err error) {
+	var (
+		set types.RecordSet
+	)
+
+	for _, f := range dm.Fields {
+		switch {
+		case f.Expressions.Rollup != nil:
+			if f.Expressions.Rollup.ModuleID != m.ID {
+				continue
+			}
+
+			// records that are referenced by the changed records
+			for _, r := range rr {
+				for _, v := range r.Values.FilterByName(f.Expressions.Rollup.RefField) {
+					if ID, _ := strconv.ParseUint(v.Value, 10, 64); ID > 0 {
+						ids = append(ids, ID)
+					}
+				}
+			}
+
+		case f.Expressions.Lookup != nil:
+			ref := dm.Fields.FindByName(f.Expressions.Lookup.RefField)
+			if ref == nil || ref.Kind != "Record" || uint64(ref.Options.Int64("moduleID")) != m.ID {
+				continue
+			}
+
+			// records that reference the changed records
+			for _, r := range rr {
+				if set, err = svc.loader.Referencing(ctx, dm, ref.Name, r.ID); err != nil {
+					return
+				}
+
+				ids = append(ids, set.IDs()...)
+			}
+		}
+	}
+
+	return
+}
+
+// recalculate computes lookup, rollup and expression values on the record
+// and stores it when any of the computed values change
+//
+// Record is re-read in a transaction while records of the module are locked;
+// only computed values are changed so concurrent changes of other values are not overwritten.
+// On change, r is replaced with the stored record.
+func (svc *recordComputed) recalculate(ctx context.Context, m *types.Module, r *types.Record) (changed bool, err error) {
+	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		var (
+			rve = &types.RecordValueErrorSet{}
+			cur *types.Record
+			old types.RecordValueSet
+		)
+
+		if err = store.LockComposeRecords(ctx, s, m); err != nil {
+			return
+		}
+
+		if cur, err = (recordRelatedLoader{store: s}).Record(ctx, m, r.ID); err != nil || cur == nil {
+			// record was removed in the meantime
+			return
+		}
+
+		old = cur.Values.GetClean()
+
+		values.Related(ctx, recordRelatedLoader{store: s}, m, cur, rve)
+		values.Expression(ctx, m, cur, nil, rve)
+		if !rve.IsValid() {
+			return RecordErrValueInput().Wrap(rve)
+		}
+
+		cur.Values = cur.Values.GetClean()
+		if changed = computedValuesChanged(m, old, cur.Values); !changed {
+			return
+		}
+
+		invokerID := auth.GetIdentityFromContext(ctx).Identity()
+		if invokerID > 0 {
+			cur.UpdatedBy = invokerID
+		}
+
+		cur.UpdatedAt = now()
+		if err = store.UpdateComposeRecord(ctx, s, m, cur); err != nil {
+			return
+		}
+
+		*r = *cur
+		return createRecordRevision(ctx, s, types.RecordRevisionUpdate, invokerID, cur, old)
+	})
+
+	return
+}
+
+// modules returns all modules (with fields) from the namespace
+// that have values computed from related records
+//
+// Modules are loaded from the store when they are not cached (or cache expired)
+func (svc *recordComputed) modules(ctx context.Context, namespaceID uint64) (out types.ModuleSet, err error) {
+	svc.mux.RLock()
+	c, has := svc.modCache[namespaceID]
+	svc.mux.RUnlock()
+
+	if has && now().Before(c.expires) {
+		return c.mm, nil
+	}
+
+	mm, _, err := store.SearchComposeModules(ctx, svc.store, types.ModuleFilter{NamespaceID: namespaceID})
+	if err != nil {
+		return
+	}
+
+	if err = loadModuleFields(ctx, svc.store, mm...); err != nil {
+		return
+	}
+
+	for _, m := range mm {
+		for _, f := range m.Fields {
+			if f.Expressions.IsRelated() {
+				out = append(out, m)
+				break
+			}
+		}
+	}
+
+	svc.mux.Lock()
+	svc.modCache[namespaceID] = recordComputedModules{mm: out, expires: now().Add(recordComputedModulesTTL)}
+	svc.mux.Unlock()
+
+	return
+}
+
+// invalidate removes cached modules of the namespace
+func (svc *recordComputed) invalidate(namespaceID uint64) {
+	svc.mux.Lock()
+	delete(svc.modCache, namespaceID)
+	svc.mux.Unlock()
+}
+
+// computedValuesChanged compares values of computed fields
+func computedValuesChanged(m *types.Module, a, b types.RecordValueSet) bool {
+	for _, f := range m.Fields {
+		if !f.Expressions.IsComputed() {
+			continue
+		}
+
+		av, bv := a.FilterByName(f.Name), b.FilterByName(f.Name)
+		if len(av) != len(bv) {
+			return true
+		}
+
+		for _, v := range av {
+			if w := bv.Get(v.Name, v.Place); w == nil || w.Value != v.Value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (l recordRelatedLoader) Module(ctx context.Context, moduleID uint64) (*types.Module, error) {
+	return loadModule(ctx, l.store, moduleID)
+}
+
+func (l recordRelatedLoader) Record(ctx context.Context, m *types.Module, recordID uint64) (*types.Record, error) {
+	r, err := store.LookupComposeRecordByID(ctx, l.store, m, recordID)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil || r.DeletedAt != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (l recordRelatedLoader) Referencing(ctx context.Context, m *types.Module, field string, recordID uint64) (types.RecordSet, error) {
+	rr, _, err := store.SearchComposeRecords(ctx, l.store, m, types.RecordFilter{
+		NamespaceID: m.NamespaceID,
+		ModuleID:    m.ID,
+		Query:       fmt.Sprintf("%s = %d", field, recordID),
+	})
+
+	return rr, err
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// SearchRevisions returns revisions of a record, oldest first unless sorted otherwise
+//
+// Changes on fields that current user is not allowed to read are removed
+func (svc record) SearchRevisions(ctx context.Context, f types.RecordRevisionFilter) (rr types.RecordRevisionSet, _ types.RecordRevisionFilter, err error) {
+	var (
+		aProps = &recordActionProps{record: &types.Record{ID: f.RecordID, NamespaceID: f.NamespaceID, ModuleID: f.ModuleID}}
+
+		m *types.Module
+		r *types.Record
+	)
+
+	err = func() error {
+		if f.RecordID == 0 {
+			return RecordErrInvalidID()
+		}
+
+		if _, m, r, err = loadRecordCombo(ctx, svc.store, f.NamespaceID, f.ModuleID, f.RecordID); errors.IsNotFound(err) {
+			return RecordErrNotFound()
+		} else if err != nil {
+			return err
+		}
+
+		aProps.setRecord(r)
+
+		if !svc.ac.CanReadRecord(ctx, r) {
+			return RecordErrNotAllowedToRead()
+		}
+
+		if rr, f, err = store.SearchComposeRecordRevisions(ctx, svc.store, f); err != nil {
+			return err
+		}
+
+		for _, rev := range rr {
+			cc := make(types.RecordValueChangeSet, 0, len(rev.Changes))
+			for _, c := range rev.Changes {
+				if f := m.Fields.FindByName(c.Name); f != nil && svc.ac.CanReadRecordValue(ctx, f) {
+					cc = append(cc, c)
+				}
+			}
+
+			rev.Changes = cc
+		}
+
+		return nil
+	}()
+
+	return rr, f, svc.recordAction(ctx, aProps, RecordActionSearchRevisions, err)
+}
+
+// RestoreRevision restores record values to the state they were in right after the given revision
+//
+// All changes made after the revision are reverted and the resulting values are
+// passed through the regular update procedure (validation, access control, automation)
+func (svc record) RestoreRevision(ctx context.Context, namespaceID, moduleID, recordID, revisionID uint64) (rec *types.Record, err error) {
+	var (
+		aProps = &recordActionProps{record: &types.Record{ID: recordID, NamespaceID: namespaceID, ModuleID: moduleID}}
+
+		old *types.Record
+		rr  types.RecordRevisionSet
+	)
+
+	err = func() error {
+		if recordID == 0 {
+			return RecordErrInvalidID()
+		}
+
+		if _, _, old, err = loadRecordCombo(ctx, svc.store, namespaceID, moduleID, recordID); errors.IsNotFound(err) {
+			return RecordErrNotFound()
+		} else if err != nil {
+			return err
+		}
+
+		aProps.setRecord(old)
+
+		if rr, err = loadRecordRevisions(ctx, svc.store, recordID); err != nil {
+			return err
+		}
+
+		var (
+			values = old.Values.GetClean()
+			rev    *types.RecordRevision
+		)
+
+		// walk backwards and revert changes
+		// until we get to the requested revision
+		for i := len(rr) - 1; i >= 0; i-- {
+			if rr[i].ID == revisionID {
+				rev = rr[i]
+				break
+			}
+
+			values = rr[i].Changes.Revert(values)
+		}
+
+		if rev == nil {
+			return RecordErrRevisionNotFound()
+		}
+
+		aProps.setRevision(rev)
+
+		upd := &types.Record{
+			ID:          old.ID,
+			ModuleID:    old.ModuleID,
+			NamespaceID: old.NamespaceID,
+			OwnedBy:     old.OwnedBy,
+			Labels:      old.Labels,
+			Values:      values,
+			UpdatedAt:   old.UpdatedAt,
+		}
+
+		rec, err = svc.update(ctx, upd, types.RecordRevisionRestore)
+		return err
+	}()
+
+	return rec, svc.recordAction(ctx, aProps, RecordActionRestoreRevision, err)
+}
+
+// createRecordRevision stores changed record values as a new revision
+//
+// Updates w/o any value changes are not stored
+func createRecordRevision(ctx context.Context, s store.ComposeRecordRevisions, op types.RecordRevisionOperation, invokerID uint64, r *types.Record, old types.RecordValueSet) error {
+	rev := &types.RecordRevision{
+		ID:          nextID(),
+		RecordID:    r.ID,
+		ModuleID:    r.ModuleID,
+		NamespaceID: r.NamespaceID,
+		Operation:   op,
+		Changes:     types.RecordValueChanges(old, r.Values),
+		CreatedAt:   *now(),
+		CreatedBy:   invokerID,
+	}
+
+	if op == types.RecordRevisionUpdate && len(rev.Changes) == 0 {
+		return nil
+	}
+
+	return store.CreateComposeRecordRevision(ctx, s, rev)
+}
+
+// loads all revisions of a record and sorts them by ID (oldest first)
+func loadRecordRevisions(ctx context.Context, s store.ComposeRecordRevisions, recordID uint64) (rr types.RecordRevisionSet, err error) {
+	if rr, _, err = store.SearchComposeRecordRevisions(ctx, s, types.RecordRevisionFilter{RecordID: recordID}); err != nil {
+		return
+	}
+
+	sort.Slice(rr, func(i, j int) bool {
+		return rr[i].ID < rr[j].ID
+	})
+
+	return
+}
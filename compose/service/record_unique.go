@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/compose/service/values"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+var (
+	uniqueQueryValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+)
+
+// RecordUniqueConstraintCheck checks record against module's unique constraints
+// while records of the module are locked
+//
+// It must be called in the same transaction that stores the record and before
+// the record is stored; lock is held until the end of the transaction so that
+// concurrent writes can not store a duplicate between the check and the write.
+func RecordUniqueConstraintCheck(ctx context.Context, s store.Storer, m *types.Module, r *types.Record) error {
+	if len(m.Config.UniqueConstraints) == 0 {
+		return nil
+	}
+
+	if err := store.LockComposeRecords(ctx, s, m); err != nil {
+		return err
+	}
+
+	rve := &types.RecordValueErrorSet{}
+	values.UniqueConstraints(ctx, recordDuplicateFinder(s), m, r, rve)
+	if !rve.IsValid() {
+		return RecordErrValueInput().Wrap(rve)
+	}
+
+	return nil
+}
+
+// recordDuplicateFinder searches for records with the same values in the constrained fields
+//
+// Case-insensitive constraints use LIKE on string fields; wildcards in values
+// can yield additional records but those are filtered out by the check fn.
+// Search stops at the first duplicate.
+func recordDuplicateFinder(s store.Storer) values.DuplicateFinder {
+	return func(ctx context.Context, m *types.Module, c types.ModuleConfigUniqueConstraint, vv []string, check func(*types.Record) (bool, error)) (types.RecordSet, error) {
+		var (
+			cnd = make([]string, 0, len(c.Fields))
+		)
+
+		for i, name := range c.Fields {
+			f := m.Fields.FindByName(name)
+			if f == nil {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+
+			op := "="
+			if c.CaseInsensitive && !f.IsNumeric() && !f.IsBoolean() && !f.IsDateTime() && !f.IsRef() {
+				op = "LIKE"
+			}
+
+			cnd = append(cnd, fmt.Sprintf("%s %s '%s'", name, op, uniqueQueryValueEscaper.Replace(vv[i])))
+		}
+
+		f := types.RecordFilter{
+			NamespaceID: m.NamespaceID,
+			ModuleID:    m.ID,
+			Query:       strings.Join(cnd, " AND "),
+			Check:       check,
+		}
+		f.Limit = 1
+
+		rr, _, err := store.SearchComposeRecords(ctx, s, m, f)
+
+		return rr, err
+	}
+}
@@ -140,13 +140,16 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 	DefaultNamespace = Namespace()
 	DefaultModule = Module()
 
-	DefaultImportSession = ImportSession()
 	DefaultRecord = Record()
+	DefaultImportSession = ImportSession()
 	DefaultPage = Page()
 	DefaultChart = Chart()
 	DefaultNotification = Notification()
 	DefaultAttachment = Attachment(DefaultObjectStore)
 
+	// Recalculate lookup and rollup values when related records change
+	RecordComputed(DefaultStore, DefaultLogger.Named("record-computed")).Register(eventbus.Service())
+
 	RegisterIteratorProviders()
 
 	automationService.Registry().AddTypes(
@@ -175,8 +178,8 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 	)
 
 	// Register reporters
-	// @todo additional datasource providers; generate?
 	systemService.DefaultReport.RegisterReporter("composeRecords", DefaultRecord)
+	systemService.DefaultReport.RegisterReporter("composeAttachments", DefaultAttachment)
 
 	return nil
 }
@@ -186,7 +189,8 @@ func Activate(ctx context.Context) (err error) {
 }
 
 func Watchers(ctx context.Context) {
-	//
+	// processes queued record import sessions
+	DefaultImportSession.Watch(ctx)
 }
 
 func RegisterIteratorProviders() {
@@ -38,8 +38,8 @@ func (f formatter) Run(m *types.Module, vv types.RecordValueSet) types.RecordVal
 			continue
 		}
 
-		if fld.Expressions.ValueExpr != "" {
-			// do not do any validation if field has value expression!
+		if fld.Expressions.IsComputed() {
+			// do not do any validation if field value is computed!
 			continue
 		}
 
@@ -0,0 +1,231 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+type (
+	// RelatedLoader loads modules and records
+	// that lookup and rollup field values are computed from
+	RelatedLoader interface {
+		// Module loads module with fields
+		Module(ctx context.Context, moduleID uint64) (*types.Module, error)
+
+		// Record loads record (with values); nil is returned for missing and deleted records
+		Record(ctx context.Context, m *types.Module, recordID uint64) (*types.Record, error)
+
+		// Referencing loads all records of the module that reference the record through the field
+		Referencing(ctx context.Context, m *types.Module, field string, recordID uint64) (types.RecordSet, error)
+	}
+)
+
+func makeRelatedValueErr(field *types.ModuleField, err error) types.RecordValueError {
+	return types.RecordValueError{
+		Kind:    "relatedValue",
+		Message: fmt.Sprintf("failed to compute value from related records: %v", err.Error()),
+		Meta:    map[string]interface{}{"field": field.Name},
+	}
+}
+
+// Related computes values of lookup and rollup fields from
+// related records and assigns results to the record
+//
+// Lookups copy value from the record referenced through the record field
+// (first value, when record field is multi-value); rollups aggregate values
+// of all records (on the configured module) that reference this record.
+func Related(ctx context.Context, l RelatedLoader, m *types.Module, r *types.Record, rve *types.RecordValueErrorSet) {
+	for _, f := range m.Fields {
+		var (
+			vv  []string
+			err error
+		)
+
+		switch {
+		case f.Expressions.Lookup != nil:
+			vv, err = lookup(ctx, l, m, f, r)
+		case f.Expressions.Rollup != nil:
+			vv, err = rollup(ctx, l, f, r)
+		default:
+			continue
+		}
+
+		if err != nil {
+			rve.Push(makeRelatedValueErr(f, err))
+			continue
+		}
+
+		if !f.Multi && len(vv) > 1 {
+			vv = vv[:1]
+		}
+
+		r.Values = r.Values.Replace(f.Name, vv...)
+
+		if f.IsRef() {
+			for _, v := range r.Values.FilterByName(f.Name) {
+				v.Ref, _ = strconv.ParseUint(v.Value, 10, 64)
+			}
+		}
+	}
+}
+
+// lookup returns values of the lookup field's source field on the referenced record
+func lookup(ctx context.Context, l RelatedLoader, m *types.Module, f *types.ModuleField, r *types.Record) ([]string, error) {
+	var (
+		cfg = f.Expressions.Lookup
+		ref = m.Fields.FindByName(cfg.RefField)
+	)
+
+	if ref == nil || ref.Kind != "Record" {
+		return nil, fmt.Errorf("lookup field %q is not a record field", cfg.RefField)
+	}
+
+	v := r.Values.Get(ref.Name, 0)
+	if v == nil || v.IsDeleted() {
+		return nil, nil
+	}
+
+	recordID, _ := strconv.ParseUint(v.Value, 10, 64)
+	if recordID == 0 {
+		return nil, nil
+	}
+
+	rm, err := l.Module(ctx, uint64(ref.Options.Int64("moduleID")))
+	if err != nil {
+		return nil, err
+	}
+
+	if rm.Fields.FindByName(cfg.Field) == nil {
+		return nil, fmt.Errorf("lookup source field %q does not exist", cfg.Field)
+	}
+
+	src, err := l.Record(ctx, rm, recordID)
+	if err != nil || src == nil {
+		return nil, err
+	}
+
+	return rawValues(src.Values.FilterByName(cfg.Field)), nil
+}
+
+// rollup aggregates values of the records that reference the record
+func rollup(ctx context.Context, l RelatedLoader, f *types.ModuleField, r *types.Record) ([]string, error) {
+	var (
+		cfg = f.Expressions.Rollup
+		agg *types.ModuleField
+		rr  types.RecordSet
+	)
+
+	rm, err := l.Module(ctx, cfg.ModuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rm.Fields.FindByName(cfg.RefField) == nil {
+		return nil, fmt.Errorf("rollup reference field %q does not exist", cfg.RefField)
+	}
+
+	if cfg.Field != "" {
+		if agg = rm.Fields.FindByName(cfg.Field); agg == nil {
+			return nil, fmt.Errorf("rollup field %q does not exist", cfg.Field)
+		}
+	} else if strings.ToLower(cfg.Func) != types.RollupCount {
+		return nil, fmt.Errorf("rollup function %q requires a field", cfg.Func)
+	}
+
+	if r.ID > 0 {
+		// new records can not be referenced yet
+		if rr, err = l.Referencing(ctx, rm, cfg.RefField, r.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregate(f, agg, cfg.Func, rr)
+}
+
+// aggregate applies rollup function to the values of the agg field
+//
+// When agg field is not set, count returns number of records
+func aggregate(f, agg *types.ModuleField, fn string, rr types.RecordSet) ([]string, error) {
+	var (
+		vv []string
+	)
+
+	if agg != nil {
+		for _, r := range rr {
+			vv = append(vv, rawValues(r.Values.FilterByName(agg.Name))...)
+		}
+	}
+
+	switch strings.ToLower(fn) {
+	case types.RollupCount:
+		if agg == nil {
+			return []string{sanitize(f, len(rr))}, nil
+		}
+
+		return []string{sanitize(f, len(vv))}, nil
+
+	case types.RollupSum:
+		var sum float64
+		for _, v := range vv {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				sum += n
+			}
+		}
+
+		return []string{sanitize(f, sum)}, nil
+
+	case types.RollupMin, types.RollupMax:
+		var (
+			isMax = strings.ToLower(fn) == types.RollupMax
+			found bool
+			best  string
+			num   float64
+		)
+
+		for _, v := range vv {
+			if agg.IsNumeric() {
+				n, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+
+				if !found || (isMax && n > num) || (!isMax && n < num) {
+					best, num, found = v, n, true
+				}
+
+				continue
+			}
+
+			// dates are stored in ISO 8601 format
+			// and can be compared as strings
+			if !found || (isMax && v > best) || (!isMax && v < best) {
+				best, found = v, true
+			}
+		}
+
+		if !found {
+			return nil, nil
+		}
+
+		return []string{sanitize(f, best)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rollup function %q", fn)
+}
+
+// rawValues returns non-empty values that are not deleted
+func rawValues(vv types.RecordValueSet) (out []string) {
+	for _, v := range vv {
+		if v.IsDeleted() || v.Value == "" {
+			continue
+		}
+
+		out = append(out, v.Value)
+	}
+
+	return
+}
@@ -0,0 +1,162 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockRelatedLoader struct {
+		mm map[uint64]*types.Module
+		rr types.RecordSet
+	}
+)
+
+func (l mockRelatedLoader) Module(_ context.Context, moduleID uint64) (*types.Module, error) {
+	if m, ok := l.mm[moduleID]; ok {
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("module not found")
+}
+
+func (l mockRelatedLoader) Record(_ context.Context, m *types.Module, recordID uint64) (*types.Record, error) {
+	for _, r := range l.rr {
+		if r.ModuleID == m.ID && r.ID == recordID {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (l mockRelatedLoader) Referencing(_ context.Context, m *types.Module, field string, recordID uint64) (out types.RecordSet, err error) {
+	for _, r := range l.rr {
+		if r.ModuleID != m.ID {
+			continue
+		}
+
+		if v := r.Values.Get(field, 0); v != nil && v.Value == strconv.FormatUint(recordID, 10) {
+			out = append(out, r)
+		}
+	}
+
+	return
+}
+
+func TestRelated(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		child = &types.Module{ID: 2, Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "parent", Kind: "Record", Options: types.ModuleFieldOptions{"moduleID": "1"}},
+			&types.ModuleField{Name: "amount", Kind: "Number"},
+		}}
+
+		parent = &types.Module{ID: 1, Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "name", Kind: "String"},
+		}}
+
+		rollup = func(fn, field string) *types.Module {
+			return &types.Module{ID: 1, Fields: types.ModuleFieldSet{
+				&types.ModuleField{Name: "res", Kind: "Number", Options: types.ModuleFieldOptions{"precision": 2}, Expressions: types.ModuleFieldExpr{
+					Rollup: &types.ModuleFieldRollup{ModuleID: 2, RefField: "parent", Field: field, Func: fn},
+				}},
+			}}
+		}
+
+		loader = mockRelatedLoader{
+			mm: map[uint64]*types.Module{1: parent, 2: child},
+			rr: types.RecordSet{
+				&types.Record{ID: 10, ModuleID: 1, Values: types.RecordValueSet{{Name: "name", Value: "p"}}},
+				&types.Record{ID: 20, ModuleID: 2, Values: types.RecordValueSet{{Name: "parent", Value: "10"}, {Name: "amount", Value: "3"}}},
+				&types.Record{ID: 21, ModuleID: 2, Values: types.RecordValueSet{{Name: "parent", Value: "10"}, {Name: "amount", Value: "1.5"}}},
+				&types.Record{ID: 22, ModuleID: 2, Values: types.RecordValueSet{{Name: "parent", Value: "10"}}},
+				&types.Record{ID: 23, ModuleID: 2, Values: types.RecordValueSet{{Name: "parent", Value: "11"}, {Name: "amount", Value: "100"}}},
+			},
+		}
+	)
+
+	tcc := []struct {
+		name  string
+		m     *types.Module
+		value string
+	}{
+		{"sum", rollup("sum", "amount"), "4.5"},
+		{"count records", rollup("count", ""), "3"},
+		{"count values", rollup("count", "amount"), "2"},
+		{"min", rollup("min", "amount"), "1.5"},
+		{"max", rollup("MAX", "amount"), "3"},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				r   = &types.Record{ID: 10}
+				rve = &types.RecordValueErrorSet{}
+			)
+
+			Related(ctx, loader, tc.m, r, rve)
+			req.True(rve.IsValid())
+			req.Equal(tc.value, r.Values.Get("res", 0).Value)
+		})
+	}
+
+	t.Run("rollup on new record", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			r   = &types.Record{Values: types.RecordValueSet{{Name: "res", Value: "42"}}}
+			rve = &types.RecordValueErrorSet{}
+		)
+
+		Related(ctx, loader, rollup("max", "amount"), r, rve)
+		req.True(rve.IsValid())
+		req.Nil(r.Values.Get("res", 0))
+	})
+
+	t.Run("invalid rollup", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			rve = &types.RecordValueErrorSet{}
+		)
+
+		Related(ctx, loader, rollup("avg", "amount"), &types.Record{ID: 10}, rve)
+		req.False(rve.IsValid())
+		req.Equal("relatedValue", rve.Set[0].Kind)
+
+		rve = &types.RecordValueErrorSet{}
+		Related(ctx, loader, rollup("sum", ""), &types.Record{ID: 10}, rve)
+		req.False(rve.IsValid())
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			m   = &types.Module{ID: 2, Fields: types.ModuleFieldSet{
+				&types.ModuleField{Name: "parent", Kind: "Record", Options: types.ModuleFieldOptions{"moduleID": "1"}},
+				&types.ModuleField{Name: "parentName", Kind: "String", Expressions: types.ModuleFieldExpr{
+					Lookup: &types.ModuleFieldLookup{RefField: "parent", Field: "name"},
+				}},
+			}}
+
+			r   = &types.Record{Values: types.RecordValueSet{{Name: "parent", Value: "10"}}}
+			rve = &types.RecordValueErrorSet{}
+		)
+
+		Related(ctx, loader, m, r, rve)
+		req.True(rve.IsValid())
+		req.Equal("p", r.Values.Get("parentName", 0).Value)
+
+		// missing reference clears the value
+		r.Values = r.Values.Replace("parent", "99")
+		Related(ctx, loader, m, r, rve)
+		req.True(rve.IsValid())
+		req.Nil(r.Values.Get("parentName", 0))
+	})
+}
@@ -75,8 +75,8 @@ func (s sanitizer) Run(m *types.Module, vv types.RecordValueSet) (out types.Reco
 			continue
 		}
 
-		if f.Expressions.ValueExpr != "" {
-			// do not do any sanitization if field has value expression!
+		if f.Expressions.IsComputed() {
+			// do not do any sanitization if field value is computed!
 			continue
 		}
 
@@ -0,0 +1,182 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+)
+
+type (
+	// DuplicateFinder returns (first) record of the module that has
+	// the same values in the constrained fields
+	//
+	// Values are passed in the same order as fields on the constraint;
+	// finder can search for a superset of records and use check fn
+	// to tell which of them are actual duplicates.
+	DuplicateFinder func(ctx context.Context, m *types.Module, c types.ModuleConfigUniqueConstraint, vv []string, check func(*types.Record) (bool, error)) (types.RecordSet, error)
+)
+
+var (
+	// parsed constraint filter expressions, per module
+	uniqueFilters    = make(map[uint64]map[string]gval.Evaluable)
+	uniqueFiltersMux sync.RWMutex
+)
+
+func makeDuplicateCompositeValueErr(c types.ModuleConfigUniqueConstraint, recordID uint64) types.RecordValueError {
+	return types.RecordValueError{
+		Kind:    "duplicateCompositeValue",
+		Message: fmt.Sprintf("record with the same values already exists (%s)", c.Label()),
+		Meta: map[string]interface{}{
+			"constraint": c.Label(),
+			"fields":     c.Fields,
+			"recordID":   recordID,
+		},
+	}
+}
+
+func makeUniqueConstraintErr(c types.ModuleConfigUniqueConstraint, err error) types.RecordValueError {
+	return types.RecordValueError{
+		Kind:    "uniqueConstraint",
+		Message: fmt.Sprintf("failed to check unique constraint (%s): %v", c.Label(), err.Error()),
+		Meta:    map[string]interface{}{"constraint": c.Label()},
+	}
+}
+
+// UniqueConstraints checks record against module's composite unique constraints
+//
+// Constraint applies only to records with values in all constrained
+// fields that match constraint's filter (when set); same rules apply to
+// the records found by the finder.
+func UniqueConstraints(ctx context.Context, find DuplicateFinder, m *types.Module, r *types.Record, rve *types.RecordValueErrorSet) {
+	for _, c := range m.Config.UniqueConstraints {
+		vv := constrainedValues(c, r)
+		if vv == nil {
+			continue
+		}
+
+		if in, err := inConstraintScope(ctx, c, m, r); err != nil {
+			rve.Push(makeUniqueConstraintErr(c, err))
+			continue
+		} else if !in {
+			continue
+		}
+
+		rr, err := find(ctx, m, c, vv, func(dup *types.Record) (bool, error) {
+			if (r.ID > 0 && dup.ID == r.ID) || dup.DeletedAt != nil {
+				return false, nil
+			}
+
+			if !sameConstrainedValues(c, vv, constrainedValues(c, dup)) {
+				return false, nil
+			}
+
+			return inConstraintScope(ctx, c, m, dup)
+		})
+
+		if err != nil {
+			rve.Push(makeUniqueConstraintErr(c, err))
+			continue
+		}
+
+		if len(rr) > 0 {
+			rve.Push(makeDuplicateCompositeValueErr(c, rr[0].ID))
+		}
+	}
+}
+
+// constrainedValues returns (first) values of the constrained fields
+//
+// Nil is returned when any of the fields is without value
+func constrainedValues(c types.ModuleConfigUniqueConstraint, r *types.Record) []string {
+	if len(c.Fields) == 0 {
+		return nil
+	}
+
+	vv := make([]string, len(c.Fields))
+	for i, name := range c.Fields {
+		v := r.Values.Get(name, 0)
+		if v == nil || v.IsDeleted() || v.Value == "" {
+			return nil
+		}
+
+		vv[i] = v.Value
+	}
+
+	return vv
+}
+
+func sameConstrainedValues(c types.ModuleConfigUniqueConstraint, a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if c.CaseInsensitive && !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+
+		if !c.CaseInsensitive && a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inConstraintScope evaluates constraint's filter expression against record values
+func inConstraintScope(ctx context.Context, c types.ModuleConfigUniqueConstraint, m *types.Module, r *types.Record) (bool, error) {
+	if c.Filter == "" {
+		return true, nil
+	}
+
+	eval, err := uniqueFilter(m.ID, c.Filter)
+	if err != nil {
+		return false, err
+	}
+
+	return eval.EvalBool(ctx, r.Values.Dict(m.Fields))
+}
+
+// uniqueFilter returns parsed filter expression
+//
+// Expressions are parsed once and reused for all records of the module
+// until module is changed (see ForgetUniqueFilters)
+func uniqueFilter(moduleID uint64, filter string) (gval.Evaluable, error) {
+	uniqueFiltersMux.RLock()
+	eval, ok := uniqueFilters[moduleID][filter]
+	uniqueFiltersMux.RUnlock()
+
+	if ok {
+		return eval, nil
+	}
+
+	eval, err := expr.Parser().NewEvaluable(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression %q: %w", filter, err)
+	}
+
+	uniqueFiltersMux.Lock()
+	defer uniqueFiltersMux.Unlock()
+
+	if uniqueFilters[moduleID] == nil {
+		uniqueFilters[moduleID] = make(map[string]gval.Evaluable)
+	}
+
+	uniqueFilters[moduleID][filter] = eval
+	return eval, nil
+}
+
+// ForgetUniqueFilters removes parsed filter expressions of the module
+//
+// Called when module is updated or deleted
+func ForgetUniqueFilters(moduleID uint64) {
+	uniqueFiltersMux.Lock()
+	defer uniqueFiltersMux.Unlock()
+
+	delete(uniqueFilters, moduleID)
+}
@@ -0,0 +1,152 @@
+package values
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueConstraints(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		existing = types.RecordSet{
+			&types.Record{ID: 10, Values: types.RecordValueSet{{Name: "email", Value: "john@example.tld"}, {Name: "company", Value: "ACME"}, {Name: "status", Value: "active"}}},
+			&types.Record{ID: 11, Values: types.RecordValueSet{{Name: "email", Value: "jane@example.tld"}, {Name: "company", Value: "ACME"}, {Name: "status", Value: "archived"}}},
+		}
+
+		// finder goes through all records, constraint check should do the rest
+		find = func(_ context.Context, _ *types.Module, _ types.ModuleConfigUniqueConstraint, _ []string, check func(*types.Record) (bool, error)) (types.RecordSet, error) {
+			for _, r := range existing {
+				if ok, err := check(r); err != nil || ok {
+					return types.RecordSet{r}, err
+				}
+			}
+
+			return nil, nil
+		}
+
+		module = func(c types.ModuleConfigUniqueConstraint) *types.Module {
+			return &types.Module{
+				Fields: types.ModuleFieldSet{
+					&types.ModuleField{Name: "email", Kind: "String"},
+					&types.ModuleField{Name: "company", Kind: "String"},
+					&types.ModuleField{Name: "status", Kind: "String"},
+				},
+				Config: types.ModuleConfig{UniqueConstraints: []types.ModuleConfigUniqueConstraint{c}},
+			}
+		}
+
+		record = func(ID uint64, email, company, status string) *types.Record {
+			return &types.Record{ID: ID, Values: types.RecordValueSet{
+				{Name: "email", Value: email},
+				{Name: "company", Value: company},
+				{Name: "status", Value: status},
+			}}
+		}
+	)
+
+	tcc := []struct {
+		name string
+		c    types.ModuleConfigUniqueConstraint
+		r    *types.Record
+		dup  uint64
+	}{
+		{
+			name: "duplicate",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}},
+			r:    record(0, "john@example.tld", "ACME", ""),
+			dup:  10,
+		},
+		{
+			name: "different combination",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}},
+			r:    record(0, "john@example.tld", "Initech", ""),
+		},
+		{
+			name: "same record",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}},
+			r:    record(10, "john@example.tld", "ACME", ""),
+		},
+		{
+			name: "missing value",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}},
+			r:    record(0, "john@example.tld", "", ""),
+		},
+		{
+			name: "case sensitive",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}},
+			r:    record(0, "John@Example.tld", "acme", ""),
+		},
+		{
+			name: "case insensitive",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}, CaseInsensitive: true},
+			r:    record(0, "John@Example.tld", "acme", ""),
+			dup:  10,
+		},
+		{
+			name: "record out of filter scope",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email"}, Filter: `status == "active"`},
+			r:    record(0, "john@example.tld", "", "archived"),
+		},
+		{
+			name: "existing record out of filter scope",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email"}, Filter: `status == "active"`},
+			r:    record(0, "jane@example.tld", "", "active"),
+		},
+		{
+			name: "in filter scope",
+			c:    types.ModuleConfigUniqueConstraint{Fields: []string{"email"}, Filter: `status == "active"`},
+			r:    record(0, "john@example.tld", "", "active"),
+			dup:  10,
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				rve = &types.RecordValueErrorSet{}
+			)
+
+			UniqueConstraints(ctx, find, module(tc.c), tc.r, rve)
+
+			if tc.dup == 0 {
+				req.True(rve.IsValid())
+				return
+			}
+
+			req.False(rve.IsValid())
+			req.Len(rve.Set, 1)
+			req.Equal("duplicateCompositeValue", rve.Set[0].Kind)
+			req.Equal(tc.dup, rve.Set[0].Meta["recordID"])
+		})
+	}
+
+	t.Run("invalid filter", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			rve = &types.RecordValueErrorSet{}
+			c   = types.ModuleConfigUniqueConstraint{Fields: []string{"email"}, Filter: `status ==`}
+		)
+
+		UniqueConstraints(ctx, find, module(c), record(0, "john@example.tld", "", ""), rve)
+		req.False(rve.IsValid())
+		req.Equal("uniqueConstraint", rve.Set[0].Kind)
+	})
+}
+
+func TestForgetUniqueFilters(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	_, err := uniqueFilter(42, `status == "active"`)
+	req.NoError(err)
+	req.Len(uniqueFilters[42], 1)
+
+	ForgetUniqueFilters(42)
+	req.NotContains(uniqueFilters, uint64(42))
+}
@@ -146,8 +146,8 @@ fields:
 			continue
 		}
 
-		if f.Expressions.ValueExpr != "" {
-			// do not do any validation if field has value expression!
+		if f.Expressions.IsComputed() {
+			// do not do any validation if field value is computed!
 			continue
 		}
 
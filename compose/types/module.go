@@ -1,10 +1,15 @@
 package types
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/jmoiron/sqlx/types"
+	"github.com/pkg/errors"
 )
 
 type (
@@ -13,6 +18,7 @@ type (
 		Handle string         `json:"handle"`
 		Name   string         `json:"name"`
 		Meta   types.JSONText `json:"meta"`
+		Config ModuleConfig   `json:"config"`
 		Fields ModuleFieldSet `json:"fields"`
 
 		Labels map[string]string `json:"labels,omitempty"`
@@ -24,6 +30,43 @@ type (
 		DeletedAt *time.Time `json:"deletedAt,omitempty"`
 	}
 
+	ModuleConfig struct {
+		// RecordPartition configures dedicated record table for the module
+		//
+		// Can only be set when module is created;
+		// existing modules are migrated with "records partition" command
+		RecordPartition ModuleConfigRecordPartition `json:"recordPartition"`
+
+		// UniqueConstraints prevent records with the same
+		// combination of values in the constrained fields
+		UniqueConstraints []ModuleConfigUniqueConstraint `json:"uniqueConstraints,omitempty"`
+	}
+
+	ModuleConfigRecordPartition struct {
+		// Enabled records are stored in a dedicated table with values encoded as JSON
+		// instead of the shared compose_record & compose_record_value tables
+		Enabled bool `json:"enabled"`
+
+		// Table name; compose_record_<moduleID> is used when empty
+		Table string `json:"table,omitempty"`
+	}
+
+	ModuleConfigUniqueConstraint struct {
+		// Name is used in error messages; constrained fields are used when empty
+		Name string `json:"name,omitempty"`
+
+		// Fields (names) with values that must be unique in combination
+		Fields []string `json:"fields"`
+
+		// CaseInsensitive compares string values regardless of the letter case
+		CaseInsensitive bool `json:"caseInsensitive,omitempty"`
+
+		// Filter expression limits constraint to records that match it
+		//
+		// Record values are accessible by field names (as in value expressions)
+		Filter string `json:"filter,omitempty"`
+	}
+
 	ModuleFilter struct {
 		ModuleID    []uint64 `json:"moduleID"`
 		NamespaceID uint64   `json:"namespaceID,string"`
@@ -64,3 +107,54 @@ func (set ModuleSet) FindByHandle(handle string) *Module {
 
 	return nil
 }
+
+// RecordPartitionTable returns name of the dedicated record table
+//
+// Empty string is returned when module records are not partitioned
+func (m Module) RecordPartitionTable() string {
+	switch {
+	case !m.Config.RecordPartition.Enabled:
+		return ""
+	case m.Config.RecordPartition.Table != "":
+		return m.Config.RecordPartition.Table
+	default:
+		return fmt.Sprintf("compose_record_%d", m.ID)
+	}
+}
+
+// Label returns constraint name or names of the constrained fields when name is not set
+func (c ModuleConfigUniqueConstraint) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return strings.Join(c.Fields, ", ")
+}
+
+// ParseModuleConfig parses JSON encoded module config from the (first) string
+func ParseModuleConfig(ss []string) (mc ModuleConfig, err error) {
+	if len(ss) == 0 {
+		return
+	}
+
+	return mc, json.Unmarshal([]byte(ss[0]), &mc)
+}
+
+func (mc *ModuleConfig) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*mc = ModuleConfig{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, mc); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into ModuleConfig", string(b))
+		}
+	}
+
+	return nil
+}
+
+func (mc ModuleConfig) Value() (driver.Value, error) {
+	return json.Marshal(mc)
+}
@@ -17,6 +17,37 @@ type (
 
 		Formatters               []string `json:"formatters,omitempty"`
 		DisableDefaultFormatters bool     `json:"disableDefaultFormatters,omitempty"`
+
+		// Rollup aggregates values from records that reference this one
+		Rollup *ModuleFieldRollup `json:"rollup,omitempty"`
+
+		// Lookup copies value from the record this one references
+		Lookup *ModuleFieldLookup `json:"lookup,omitempty"`
+	}
+
+	ModuleFieldRollup struct {
+		// Module with the records that are aggregated
+		ModuleID uint64 `json:"moduleID,string,omitempty"`
+
+		// Module handle; used instead of ModuleID when ID is not yet known (on import)
+		Module string `json:"module,omitempty"`
+
+		// Record field on the aggregated module that references this module
+		RefField string `json:"refField"`
+
+		// Field with aggregated values; not needed when counting
+		Field string `json:"field,omitempty"`
+
+		// Aggregation function: sum, count, min or max
+		Func string `json:"func"`
+	}
+
+	ModuleFieldLookup struct {
+		// Record field on this module that references the record we copy value from
+		RefField string `json:"refField"`
+
+		// Field on the referenced record
+		Field string `json:"field"`
 	}
 
 	ModuleFieldValidator struct {
@@ -25,6 +56,24 @@ type (
 	}
 )
 
+const (
+	RollupSum   = "sum"
+	RollupCount = "count"
+	RollupMin   = "min"
+	RollupMax   = "max"
+)
+
+// IsComputed returns true if value of the field is computed
+// (from expression or related records) and not set directly
+func (opt ModuleFieldExpr) IsComputed() bool {
+	return opt.ValueExpr != "" || opt.Rollup != nil || opt.Lookup != nil
+}
+
+// IsRelated returns true if value of the field depends on related records
+func (opt ModuleFieldExpr) IsRelated() bool {
+	return opt.Rollup != nil || opt.Lookup != nil
+}
+
 func (opt *ModuleFieldExpr) Scan(value interface{}) error {
 	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
 	switch value.(type) {
@@ -17,6 +17,7 @@ const (
 	moduleFieldOptionExpression         = "expression"
 	moduleFieldOptionIsUnique           = "isUnique"
 	moduleFieldOptionIsUniqueMultiValue = "isUniqueMultiValue"
+	moduleFieldOptionPhysicalColumn     = "physicalColumn"
 
 	moduleFieldNumberOptionPrecision         = "precision"
 	moduleFieldNumberOptionPrecisionMin uint = 0
@@ -130,6 +131,18 @@ func (opt ModuleFieldOptions) SetIsUniqueMultiValue(value bool) {
 	opt[moduleFieldOptionIsUniqueMultiValue] = value
 }
 
+// IsPhysicalColumn - should value be (also) stored in a dedicated column?
+//
+// Used only on single-value fields of modules with partitioned records
+func (opt ModuleFieldOptions) IsPhysicalColumn() bool {
+	return opt.Bool(moduleFieldOptionPhysicalColumn)
+}
+
+// SetIsPhysicalColumn - should value be (also) stored in a dedicated column?
+func (opt ModuleFieldOptions) SetIsPhysicalColumn(value bool) {
+	opt[moduleFieldOptionPhysicalColumn] = value
+}
+
 func (opt ModuleFieldOptions) Precision() (p uint) {
 	p = uint(opt.Int64(moduleFieldNumberOptionPrecision))
 
@@ -58,6 +58,18 @@ type (
 		Logo   string `json:"logo,omitempty"`
 		LogoID uint64 `json:"logoID,string"`
 	}
+
+	// NamespaceCloneProgress counts resources that were copied to the cloned namespace
+	NamespaceCloneProgress struct {
+		StartedAt  time.Time  `json:"startedAt"`
+		FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+		Modules uint `json:"modules"`
+		Pages   uint `json:"pages"`
+		Charts  uint `json:"charts"`
+		Rules   uint `json:"rules"`
+		Records uint `json:"records"`
+	}
 )
 
 func (n Namespace) Clone() *Namespace {
@@ -0,0 +1,257 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/pkg/errors"
+)
+
+type (
+	// RecordImportSession holds uploaded import file, field mapping and progress of the record import
+	//
+	// Sessions are processed by the background worker; progress is stored
+	// after each batch of rows so that interrupted import can be resumed
+	RecordImportSession struct {
+		ID          uint64 `json:"sessionID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		OwnedBy     uint64 `json:"userID,string"`
+
+		// Name and content type of the uploaded file
+		Name        string `json:"name"`
+		ContentType string `json:"contentType"`
+
+		// Uploaded file
+		Data []byte `json:"-"`
+
+		Status  RecordImportStatus `json:"status"`
+		Mode    RecordImportMode   `json:"mode"`
+		OnError string             `json:"onError"`
+		DryRun  bool               `json:"dryRun"`
+
+		// Column (from the import file) to module field mapping
+		Fields RecordImportFields `json:"fields"`
+
+		// Column that identifies existing records
+		//
+		// When column is mapped to module field, records are matched by value of that field,
+		// otherwise column is expected to hold record IDs
+		Key string `json:"key"`
+
+		Progress RecordImportProgress `json:"progress"`
+
+		// Rows that were rejected (for error report)
+		Rejected RecordImportRowSet `json:"-"`
+
+		CreatedAt time.Time  `json:"createdAt,omitempty"`
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	}
+
+	RecordImportFields map[string]string
+
+	RecordImportProgress struct {
+		StartedAt  *time.Time `json:"startedAt"`
+		FinishedAt *time.Time `json:"finishedAt"`
+		EntryCount uint64     `json:"entryCount"`
+
+		// Number of processed rows (checkpoint)
+		Processed uint64 `json:"processed"`
+		Completed uint64 `json:"completed"`
+		Failed    uint64 `json:"failed"`
+
+		FailReason string `json:"failReason,omitempty"`
+
+		FailLog *RecordImportFailLog `json:"failLog,omitempty"`
+	}
+
+	RecordImportFailLog struct {
+		// Records holds an array of record indexes
+		Records          RecordImportIndex `json:"records"`
+		RecordsTruncated bool              `json:"recordsTruncated"`
+		// Errors specifies a map of occurred errors & the number of
+		Errors RecordImportErrorIndex `json:"errors"`
+	}
+
+	RecordImportIndex      []int
+	RecordImportErrorIndex map[string]int
+
+	// RecordImportRow is a row from the import file that was rejected
+	RecordImportRow struct {
+		// Row index, starting with 1
+		Index  uint64               `json:"index"`
+		Values map[string]string    `json:"values"`
+		Errors *RecordValueErrorSet `json:"errors,omitempty"`
+		Error  string               `json:"error,omitempty"`
+	}
+
+	RecordImportRowSet []*RecordImportRow
+
+	RecordImportStatus string
+	RecordImportMode   string
+
+	RecordImportSessionFilter struct {
+		NamespaceID uint64               `json:"namespaceID,string"`
+		ModuleID    uint64               `json:"moduleID,string"`
+		OwnedBy     uint64               `json:"userID,string"`
+		Status      []RecordImportStatus `json:"status"`
+
+		// Sessions that were not updated after this time
+		UpdatedBefore *time.Time `json:"updatedBefore"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*RecordImportSession) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+const (
+	// RecordImportPending session is created but import was not started yet
+	RecordImportPending RecordImportStatus = "pending"
+	// RecordImportQueued session waits for the worker
+	RecordImportQueued RecordImportStatus = "queued"
+	// RecordImportRunning session is being processed by the worker
+	RecordImportRunning   RecordImportStatus = "running"
+	RecordImportCompleted RecordImportStatus = "completed"
+	RecordImportFailed    RecordImportStatus = "failed"
+
+	// RecordImportModeCreate creates new record for each row
+	RecordImportModeCreate RecordImportMode = "create"
+	// RecordImportModeUpsert updates records that match the key and creates the rest
+	RecordImportModeUpsert RecordImportMode = "upsert"
+)
+
+// Done returns true when session is completed or failed
+func (s RecordImportSession) Done() bool {
+	return s.Status == RecordImportCompleted || s.Status == RecordImportFailed
+}
+
+// Columns returns columns of the import file in alphabetical order
+func (ff RecordImportFields) Columns() []string {
+	cc := make([]string, 0, len(ff))
+	for c := range ff {
+		cc = append(cc, c)
+	}
+
+	sort.Strings(cc)
+	return cc
+}
+
+func (ei RecordImportErrorIndex) Add(err string) {
+	if _, has := ei[err]; has {
+		ei[err]++
+	} else {
+		ei[err] = 1
+	}
+}
+
+func (ri RecordImportIndex) MarshalJSON() ([]byte, error) {
+	sort.Ints(ri)
+
+	rr := make([][]int, 0, len(ri))
+	start := -1
+	crt := -1
+
+	for i := 0; i < len(ri); i++ {
+		if start == -1 {
+			start = ri[i]
+			crt = ri[i]
+			continue
+		}
+
+		// If the index increases for more then 1, the set is complete
+		if ri[i]-crt > 1 {
+			rr = append(rr, []int{start, crt})
+			start = ri[i]
+		}
+
+		crt = ri[i]
+	}
+
+	rr = append(rr, []int{start, crt})
+	return json.Marshal(rr)
+}
+
+// UnmarshalJSON expands index ranges (see MarshalJSON)
+func (ri *RecordImportIndex) UnmarshalJSON(data []byte) error {
+	var rr [][]int
+	if err := json.Unmarshal(data, &rr); err != nil {
+		return err
+	}
+
+	*ri = RecordImportIndex{}
+	for _, r := range rr {
+		if len(r) != 2 || r[0] < 0 {
+			continue
+		}
+
+		for i := r[0]; i <= r[1]; i++ {
+			*ri = append(*ri, i)
+		}
+	}
+
+	return nil
+}
+
+func (ff *RecordImportFields) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*ff = RecordImportFields{}
+	case []uint8:
+		if err := json.Unmarshal(value.([]byte), ff); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into RecordImportFields", value)
+		}
+	}
+
+	return nil
+}
+
+func (ff RecordImportFields) Value() (driver.Value, error) {
+	return json.Marshal(ff)
+}
+
+func (p *RecordImportProgress) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*p = RecordImportProgress{}
+	case []uint8:
+		if err := json.Unmarshal(value.([]byte), p); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into RecordImportProgress", value)
+		}
+	}
+
+	return nil
+}
+
+func (p RecordImportProgress) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (set *RecordImportRowSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*set = RecordImportRowSet{}
+	case []uint8:
+		if err := json.Unmarshal(value.([]byte), set); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into RecordImportRowSet", value)
+		}
+	}
+
+	return nil
+}
+
+func (set RecordImportRowSet) Value() (driver.Value, error) {
+	return json.Marshal(set)
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/pkg/errors"
+)
+
+type (
+	// RecordRevision holds field-level changes made to record values in one operation
+	RecordRevision struct {
+		ID          uint64 `json:"revisionID,string"`
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		Operation RecordRevisionOperation `json:"operation"`
+		Changes   RecordValueChangeSet    `json:"changes"`
+
+		CreatedAt time.Time `json:"createdAt,omitempty"`
+		CreatedBy uint64    `json:"createdBy,string"`
+	}
+
+	// RecordValueChange holds old and new values (in place order) of a single field
+	RecordValueChange struct {
+		Name string   `json:"name"`
+		Old  []string `json:"old"`
+		New  []string `json:"new"`
+	}
+
+	RecordValueChangeSet []*RecordValueChange
+
+	RecordRevisionOperation string
+
+	RecordRevisionFilter struct {
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*RecordRevision) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+const (
+	RecordRevisionCreate  RecordRevisionOperation = "create"
+	RecordRevisionUpdate  RecordRevisionOperation = "update"
+	RecordRevisionDelete  RecordRevisionOperation = "delete"
+	RecordRevisionRestore RecordRevisionOperation = "restore"
+)
+
+// RecordValueChanges compares old and new value sets and returns changes for all fields
+// where values differ
+//
+// Deleted values are ignored
+func RecordValueChanges(old, new RecordValueSet) (cc RecordValueChangeSet) {
+	var (
+		oldClean, newClean = old.GetClean(), new.GetClean()
+		oldValues          = oldClean.byName()
+		newValues          = newClean.byName()
+	)
+
+	cc = RecordValueChangeSet{}
+
+	// walking through both sets to keep the order of fields
+	for _, v := range append(oldClean, newClean...) {
+		if cc.FindByName(v.Name) != nil || equalStrings(oldValues[v.Name], newValues[v.Name]) {
+			continue
+		}
+
+		cc = append(cc, &RecordValueChange{
+			Name: v.Name,
+			Old:  oldValues[v.Name],
+			New:  newValues[v.Name],
+		})
+	}
+
+	return cc
+}
+
+// Revert reverts changes on the given value set and returns a new value set
+func (cc RecordValueChangeSet) Revert(vv RecordValueSet) RecordValueSet {
+	for _, c := range cc {
+		vv = vv.Replace(c.Name, c.Old...)
+	}
+
+	return vv
+}
+
+func (cc RecordValueChangeSet) FindByName(name string) *RecordValueChange {
+	for _, c := range cc {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (cc *RecordValueChangeSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*cc = RecordValueChangeSet{}
+	case []uint8:
+		if err := json.Unmarshal(value.([]byte), cc); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into RecordValueChangeSet", value)
+		}
+	}
+
+	return nil
+}
+
+func (cc RecordValueChangeSet) Value() (driver.Value, error) {
+	return json.Marshal(cc)
+}
+
+// returns values grouped by field name and ordered by place
+func (set RecordValueSet) byName() map[string][]string {
+	var (
+		out = make(map[string][]string)
+	)
+
+	for _, v := range set {
+		vv := out[v.Name]
+		for uint(len(vv)) <= v.Place {
+			vv = append(vv, "")
+		}
+
+		vv[v.Place] = v.Value
+		out[v.Name] = vv
+	}
+
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordValueChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		old  RecordValueSet
+		new  RecordValueSet
+		want RecordValueChangeSet
+	}{
+		{
+			name: "no changes",
+			old:  RecordValueSet{{Name: "a", Value: "1"}},
+			new:  RecordValueSet{{Name: "a", Value: "1"}},
+			want: RecordValueChangeSet{},
+		},
+		{
+			name: "created",
+			new:  RecordValueSet{{Name: "a", Value: "1"}},
+			want: RecordValueChangeSet{{Name: "a", New: []string{"1"}}},
+		},
+		{
+			name: "changed and removed",
+			old:  RecordValueSet{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}},
+			new:  RecordValueSet{{Name: "a", Value: "3"}},
+			want: RecordValueChangeSet{{Name: "a", Old: []string{"1"}, New: []string{"3"}}, {Name: "b", Old: []string{"2"}}},
+		},
+		{
+			name: "multi-value",
+			old:  RecordValueSet{{Name: "a", Value: "1"}, {Name: "a", Value: "2", Place: 1}},
+			new:  RecordValueSet{{Name: "a", Value: "1"}},
+			want: RecordValueChangeSet{{Name: "a", Old: []string{"1", "2"}, New: []string{"1"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, RecordValueChanges(tt.old, tt.new))
+		})
+	}
+}
+
+func TestRecordValueChangeSet_Revert(t *testing.T) {
+	var (
+		req = require.New(t)
+		old = RecordValueSet{{Name: "a", Value: "1"}, {Name: "a", Value: "2", Place: 1}, {Name: "b", Value: "x"}}
+		new = RecordValueSet{{Name: "a", Value: "3"}, {Name: "c", Value: "y"}}
+		cc  = RecordValueChanges(old, new)
+	)
+
+	req.Equal(old.byName(), cc.Revert(new).byName())
+}
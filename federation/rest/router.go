@@ -14,7 +14,7 @@ func MountRoutes(r chi.Router) {
 
 	// Protect all _private_ routes
 	r.Group(func(r chi.Router) {
-		r.Use(auth.MiddlewareValidOnly)
+		r.Use(auth.ComponentAccessTokenCheck("federation"))
 		handlers.NewPermissions(Permissions{}.New()).MountRoutes(r)
 
 		handlers.NewNode(Node{}.New()).MountRoutes(r)
@@ -17,6 +17,7 @@ require (
 	github.com/PaesslerAG/jsonpath v0.1.1 // indirect
 	github.com/SentimensRG/ctx v0.0.0-20180729130232-0bfd988c655d
 	github.com/asaskevich/govalidator v0.0.0-20210307081110-f21760c49a8d
+	github.com/beevik/etree v1.1.0
 	github.com/brianvoe/gofakeit/v6 v6.5.0
 	github.com/cortezaproject/corteza-locale v0.0.0-20210902094343-6e40ca3a7d14
 	github.com/crewjam/saml v0.4.5
@@ -27,16 +28,19 @@ require (
 	github.com/dgryski/dgoogauth v0.0.0-20190221195224-5a805980a5f3
 	github.com/disintegration/imaging v1.6.0
 	github.com/dop251/goja v0.0.0-20210726224656-a55e4cfac4cf
+	github.com/duo-labs/webauthn v0.0.0-20210727191636-9f1b88ef44cc
 	github.com/edwvee/exiffix v0.0.0-20180602190213-b57537c92a6b
 	github.com/evanw/esbuild v0.12.16
 	github.com/fastly/go-utils v0.0.0-20180712184237-d95a45783239 // indirect
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/gabriel-vasile/mimetype v1.1.2
 	github.com/getsentry/sentry-go v0.1.1
+	github.com/go-asn1-ber/asn1-ber v1.5.1
 	github.com/go-chi/chi v3.3.4+incompatible
 	github.com/go-chi/cors v1.0.0
 	github.com/go-chi/httprate v0.4.0
 	github.com/go-chi/jwtauth v0.0.0-20190109153619-47840abb19b3
+	github.com/go-ldap/ldap/v3 v3.3.0
 	github.com/go-oauth2/oauth2/v4 v4.3.0
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/golang/mock v1.4.4
@@ -65,6 +69,8 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/pquerna/cachecontrol v0.0.0-20180517163645-1555304b9b35 // indirect
 	github.com/prometheus/client_golang v0.9.3
+	github.com/rabbitmq/amqp091-go v1.2.0
+	github.com/russellhaering/goxmldsig v1.1.0
 	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd // indirect
 	github.com/sony/sonyflake v0.0.0-20181109022403-6d5bd6181009
 	github.com/spf13/afero v1.2.2
@@ -86,7 +92,7 @@ require (
 	gopkg.in/alexcesaro/quotedprintable.v3 v3.0.0-20150716171945-2caba252f4dc // indirect
 	gopkg.in/ini.v1 v1.51.0 // indirect
 	gopkg.in/mail.v2 v2.3.1
-	gopkg.in/square/go-jose.v2 v2.3.1 // indirect
+	gopkg.in/square/go-jose.v2 v2.3.1
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 	moul.io/zapfilter v1.6.1
 	rsc.io/qr v0.2.0
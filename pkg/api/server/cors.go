@@ -6,8 +6,31 @@ import (
 	"github.com/go-chi/cors"
 )
 
+var (
+	// PreflightPassthrough reports if CORS preflight request is passed to the
+	// route instead of being answered with the default CORS rules
+	//
+	// Used for API Gateway routes with their own CORS policy
+	PreflightPassthrough = func(r *http.Request) bool { return false }
+)
+
 // Sets up default CORS rules to use as a middleware
 func handleCORS(next http.Handler) http.Handler {
+	var (
+		h = defaultCORS(next)
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions && PreflightPassthrough(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+func defaultCORS(next http.Handler) http.Handler {
 	return cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
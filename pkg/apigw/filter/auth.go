@@ -0,0 +1,475 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	pe "github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
+	"github.com/dgrijalva/jwt-go"
+)
+
+type (
+	// authJwt authenticates callers with Corteza access tokens
+	authJwt struct {
+		types.FilterMeta
+		d JwtAuthenticator
+
+		params struct {
+			Scope string `json:"scope"`
+		}
+	}
+
+	JwtAuthenticator interface {
+		Authenticate(token string) (jwt.MapClaims, error)
+	}
+
+	// authHmac authenticates requests signed with a shared secret
+	//
+	// Signature is calculated the same way as sink request signatures
+	// (see auth.HmacSigner) from user ID, request method, path, timestamp,
+	// nonce and body. Requests with timestamp outside of the window or with
+	// nonce that was already used are rejected.
+	authHmac struct {
+		types.FilterMeta
+		ss types.SecureStorager
+		ir types.IdentityResolver
+
+		signer auth.Signer
+		nonces *hmacNonces
+
+		params struct {
+			Secret string `json:"secret"`
+			User   uint64 `json:"user,string"`
+			Header string `json:"header"`
+
+			// Window (in seconds) in which signed request is valid
+			Window uint `json:"window"`
+		}
+	}
+
+	// hmacNonces keeps nonces of the signed requests until they expire
+	hmacNonces struct {
+		mux  sync.Mutex
+		seen map[string]time.Time
+	}
+
+	// authApiKey authenticates callers with a static API key
+	authApiKey struct {
+		types.FilterMeta
+		ss types.SecureStorager
+		ir types.IdentityResolver
+
+		key string
+
+		params struct {
+			Key    string `json:"key"`
+			User   uint64 `json:"user,string"`
+			Header string `json:"header"`
+		}
+	}
+)
+
+const (
+	authHmacDefaultHeader   = "X-Signature"
+	authHmacTimestampHeader = "X-Signature-Timestamp"
+	authHmacNonceHeader     = "X-Signature-Nonce"
+	authHmacDefaultWindow   = 300
+	authApiKeyDefaultHeader = "X-Api-Key"
+
+	authJwtDefaultScope = "api"
+)
+
+func NewAuthJwt(d JwtAuthenticator) (v *authJwt) {
+	v = &authJwt{}
+
+	v.d = d
+
+	v.Name = "authJwt"
+	v.Label = "Corteza access token"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "scope",
+			Example: "Required token scope (api when not set)",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h authJwt) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h authJwt) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *authJwt) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &authJwt{FilterMeta: h.FilterMeta, d: h.d}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.params.Scope == "" {
+		v.params.Scope = authJwtDefaultScope
+	}
+
+	return v, nil
+}
+
+func (h authJwt) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		tkn := bearerToken(r)
+
+		if tkn == "" {
+			return pe.Unauthorized("access token missing")
+		}
+
+		if h.d == nil {
+			return pe.Internal("access token authenticator not set")
+		}
+
+		claims, err := h.d.Authenticate(tkn)
+		if err != nil || claims == nil {
+			return pe.Unauthorized("invalid access token")
+		}
+
+		if !hasScope(claims["scope"], h.params.Scope) {
+			return pe.Unauthorized("access token scope not allowed")
+		}
+
+		i := auth.ClaimsToIdentity(claims)
+		if i == nil {
+			return pe.Unauthorized("invalid access token")
+		}
+
+		setIdentity(r, i)
+		return nil
+	}
+}
+
+func NewAuthHmac(ss types.SecureStorager, ir types.IdentityResolver) (v *authHmac) {
+	v = &authHmac{}
+
+	v.ss = ss
+	v.ir = ir
+
+	v.Name = "authHmac"
+	v.Label = "HMAC signature"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "secret",
+			Example: "secret://handle",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "user",
+			Label:   "user",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "header",
+			Example: authHmacDefaultHeader,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "window",
+			Example: "Request validity window in seconds, ie.: 300",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h authHmac) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h authHmac) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *authHmac) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &authHmac{FilterMeta: h.FilterMeta, ss: h.ss, ir: h.ir}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.params.User == 0 {
+		return nil, fmt.Errorf("could not validate parameters, user not set")
+	}
+
+	secret, err := resolveSecret(v.ss, v.params.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("could not validate parameters, secret not set")
+	}
+
+	if v.params.Header == "" {
+		v.params.Header = authHmacDefaultHeader
+	}
+
+	if v.params.Window == 0 {
+		v.params.Window = authHmacDefaultWindow
+	}
+
+	v.signer = auth.HmacSigner(secret)
+	v.nonces = &hmacNonces{seen: make(map[string]time.Time)}
+
+	return v, nil
+}
+
+func (h authHmac) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		var (
+			ctx        = r.Context()
+			signature  = r.Header.Get(h.params.Header)
+			timestamp  = r.Header.Get(authHmacTimestampHeader)
+			nonce      = r.Header.Get(authHmacNonceHeader)
+			payload, _ = agctx.ScopeFromContext(ctx).Get("payload")
+			window     = time.Duration(h.params.Window) * time.Second
+		)
+
+		if signature == "" {
+			return pe.Unauthorized("signature missing")
+		}
+
+		if timestamp == "" || nonce == "" {
+			return pe.Unauthorized("signature timestamp or nonce missing")
+		}
+
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return pe.Unauthorized("invalid signature timestamp")
+		}
+
+		signedAt := time.Unix(ts, 0)
+		if age := time.Since(signedAt); age > window || age < -window {
+			return pe.Unauthorized("signature expired")
+		}
+
+		if !h.signer.Verify(signature, h.params.User, r.Method, r.URL.Path, timestamp, nonce, payload) {
+			return pe.Unauthorized("invalid signature")
+		}
+
+		// nonce is only stored for valid signatures
+		// so that it can not be used to block legit requests
+		if !h.nonces.use(nonce, signedAt.Add(window)) {
+			return pe.Unauthorized("signature already used")
+		}
+
+		return resolveIdentity(ctx, r, h.ir, h.params.User)
+	}
+}
+
+// use stores nonce until it expires
+//
+// Returns false if nonce was already used
+func (n *hmacNonces) use(nonce string, expires time.Time) bool {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	now := time.Now()
+	for k, exp := range n.seen {
+		if exp.Before(now) {
+			delete(n.seen, k)
+		}
+	}
+
+	if _, used := n.seen[nonce]; used {
+		return false
+	}
+
+	n.seen[nonce] = expires
+	return true
+}
+
+func NewAuthApiKey(ss types.SecureStorager, ir types.IdentityResolver) (v *authApiKey) {
+	v = &authApiKey{}
+
+	v.ss = ss
+	v.ir = ir
+
+	v.Name = "authApiKey"
+	v.Label = "API key"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "key",
+			Example: "secret://handle",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "user",
+			Label:   "user",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "header",
+			Example: authApiKeyDefaultHeader,
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h authApiKey) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h authApiKey) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *authApiKey) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &authApiKey{FilterMeta: h.FilterMeta, ss: h.ss, ir: h.ir}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.params.User == 0 {
+		return nil, fmt.Errorf("could not validate parameters, user not set")
+	}
+
+	if v.key, err = resolveSecret(v.ss, v.params.Key); err != nil {
+		return nil, err
+	}
+
+	if v.key == "" {
+		return nil, fmt.Errorf("could not validate parameters, key not set")
+	}
+
+	if v.params.Header == "" {
+		v.params.Header = authApiKeyDefaultHeader
+	}
+
+	return v, nil
+}
+
+func (h authApiKey) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		key := r.Header.Get(h.params.Header)
+
+		if key == "" {
+			return pe.Unauthorized("API key missing")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(key), []byte(h.key)) != 1 {
+			return pe.Unauthorized("invalid API key")
+		}
+
+		return resolveIdentity(r.Context(), r, h.ir, h.params.User)
+	}
+}
+
+// bearerToken returns token from the Authorization header
+func bearerToken(r *http.Request) string {
+	var (
+		hdr = r.Header.Get("Authorization")
+	)
+
+	if len(hdr) > 7 && strings.EqualFold(hdr[:7], "bearer ") {
+		return strings.TrimSpace(hdr[7:])
+	}
+
+	return ""
+}
+
+// hasScope checks if space separated list of scopes contains the required one
+func hasScope(scope interface{}, required string) bool {
+	s, _ := scope.(string)
+
+	for _, sc := range strings.Fields(s) {
+		if sc == required {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveIdentity loads the user and sets its identity to the request scope
+func resolveIdentity(ctx context.Context, r *http.Request, ir types.IdentityResolver, userID uint64) error {
+	if ir == nil {
+		return pe.Internal("identity resolver not set")
+	}
+
+	i, err := ir.ResolveIdentity(ctx, userID)
+	if err != nil {
+		return pe.Unauthorized("could not resolve identity: %v", err)
+	}
+
+	setIdentity(r, i)
+	return nil
+}
+
+// setIdentity sets authenticated identity to the request scope;
+// processers use it instead of the service user
+func setIdentity(r *http.Request, i auth.Identifiable) {
+	agctx.ScopeFromContext(r.Context()).Set("identity", i)
+}
+
+// resolveSecret resolves value of the referenced secret
+//
+// Secret references can not be used when secure storage is not configured;
+// reference would otherwise be used as the secret value
+func resolveSecret(ss types.SecureStorager, v string) (string, error) {
+	if ss == nil {
+		if _, ok := secure.ParseReference(v); ok {
+			return "", fmt.Errorf("could not resolve secret: secure storage not configured")
+		}
+
+		return v, nil
+	}
+
+	out, err := ss.Resolve(context.Background(), v)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve secret: %w", err)
+	}
+
+	return out, nil
+}
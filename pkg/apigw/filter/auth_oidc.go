@@ -0,0 +1,221 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	pe "github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/crusttech/go-oidc"
+)
+
+type (
+	// authOidc authenticates callers with tokens issued
+	// by an external OpenID Connect provider
+	//
+	// Token signature is verified with the provider's keys (JWKS),
+	// token must be issued for the configured audience and
+	// with verified email (email_verified claim);
+	// the configured claim is used to find the matching user
+	authOidc struct {
+		types.FilterMeta
+		ir types.IdentityResolver
+
+		mux      sync.Mutex
+		verifier *oidc.IDTokenVerifier
+
+		params struct {
+			Issuer   string `json:"issuer"`
+			Jwks     string `json:"jwks"`
+			Audience string `json:"audience"`
+			Claim    string `json:"claim"`
+		}
+	}
+)
+
+const (
+	authOidcDefaultClaim = "email"
+)
+
+var (
+	authOidcSigningAlgs = []string{
+		oidc.RS256, oidc.RS384, oidc.RS512,
+		oidc.ES256, oidc.ES384, oidc.ES512,
+		oidc.PS256, oidc.PS384, oidc.PS512,
+	}
+)
+
+func NewAuthOidc(ir types.IdentityResolver) (v *authOidc) {
+	v = &authOidc{}
+
+	v.ir = ir
+
+	v.Name = "authOidc"
+	v.Label = "OpenID Connect token"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "issuer",
+			Example: "https://accounts.example.com",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "jwks",
+			Example: "Discovered from issuer when not set, ie.: https://accounts.example.com/keys",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "audience",
+			Example: "Client ID the tokens are issued for",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "claim",
+			Example: "Claim with user's email, defaults to " + authOidcDefaultClaim,
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h *authOidc) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h *authOidc) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *authOidc) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &authOidc{FilterMeta: h.FilterMeta, ir: h.ir}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.params.Issuer == "" {
+		return nil, fmt.Errorf("could not validate parameters, issuer not set")
+	}
+
+	if v.params.Audience == "" {
+		// tokens issued to any client of the issuer would be accepted
+		return nil, fmt.Errorf("could not validate parameters, audience not set")
+	}
+
+	if v.params.Claim == "" {
+		v.params.Claim = authOidcDefaultClaim
+	}
+
+	if v.params.Jwks != "" {
+		// keys are fetched on the first request
+		v.verifier = oidc.NewVerifier(v.params.Issuer, oidc.NewRemoteKeySet(context.Background(), v.params.Jwks), v.config())
+	}
+
+	return v, nil
+}
+
+func (h *authOidc) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		var (
+			ctx    = r.Context()
+			tkn    = bearerToken(r)
+			claims = map[string]interface{}{}
+		)
+
+		if tkn == "" {
+			return pe.Unauthorized("access token missing")
+		}
+
+		verifier, err := h.getVerifier()
+		if err != nil {
+			return pe.External("could not discover OpenID Connect provider: %v", err)
+		}
+
+		idt, err := verifier.Verify(ctx, tkn)
+		if err != nil {
+			return pe.Unauthorized("invalid access token: %v", err)
+		}
+
+		if err = idt.Claims(&claims); err != nil {
+			return pe.Unauthorized("invalid access token: %v", err)
+		}
+
+		email, _ := claims[h.params.Claim].(string)
+		if email == "" {
+			return pe.Unauthorized("access token claim %s missing", h.params.Claim)
+		}
+
+		if !emailVerified(claims["email_verified"]) {
+			return pe.Unauthorized("access token email not verified")
+		}
+
+		if h.ir == nil {
+			return pe.Internal("identity resolver not set")
+		}
+
+		i, err := h.ir.ResolveIdentityByEmail(ctx, email)
+		if err != nil {
+			return pe.Unauthorized("could not resolve identity: %v", err)
+		}
+
+		setIdentity(r, i)
+		return nil
+	}
+}
+
+// getVerifier returns token verifier
+//
+// When JWKS URL is not set, provider is discovered on the first request
+// and retried on the next one if discovery fails
+func (h *authOidc) getVerifier() (*oidc.IDTokenVerifier, error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.verifier != nil {
+		return h.verifier, nil
+	}
+
+	// provider's context is used for fetching keys
+	// so it should not be canceled with the request
+	p, err := oidc.NewProvider(context.Background(), h.params.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	h.verifier = p.Verifier(h.config())
+	return h.verifier, nil
+}
+
+func (h *authOidc) config() *oidc.Config {
+	return &oidc.Config{
+		ClientID:             h.params.Audience,
+		SupportedSigningAlgs: authOidcSigningAlgs,
+	}
+}
+
+// emailVerified checks email_verified claim;
+// some providers send it as a string
+func emailVerified(v interface{}) bool {
+	switch c := v.(type) {
+	case bool:
+		return c
+	case string:
+		return c == "true"
+	}
+
+	return false
+}
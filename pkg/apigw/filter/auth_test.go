@@ -0,0 +1,326 @@
+package filter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+type (
+	mockJwtAuthenticator func(string) (jwt.MapClaims, error)
+)
+
+func (m mockJwtAuthenticator) Authenticate(tkn string) (jwt.MapClaims, error) {
+	return m(tkn)
+}
+
+var (
+	mockIdentityResolver = types.MockIdentityResolver{
+		ID: func(ctx context.Context, ID uint64) (auth.Identifiable, error) {
+			if ID != 42 {
+				return nil, errors.New("user not found")
+			}
+
+			return auth.Authenticated(ID, 1, 2), nil
+		},
+		Email: func(ctx context.Context, email string) (auth.Identifiable, error) {
+			if email != "foo@example.com" {
+				return nil, errors.New("user not found")
+			}
+
+			return auth.Authenticated(42, 1), nil
+		},
+	}
+)
+
+func Test_authMerge(t *testing.T) {
+	t.Run("hmac without user", testMerge(NewAuthHmac(nil, nil), tf{
+		expr: `{"secret":"foo"}`,
+		err:  "could not validate parameters, user not set",
+	}))
+
+	t.Run("hmac without secret", testMerge(NewAuthHmac(nil, nil), tf{
+		expr: `{"user":"42"}`,
+		err:  "could not validate parameters, secret not set",
+	}))
+
+	t.Run("api key without key", testMerge(NewAuthApiKey(nil, nil), tf{
+		expr: `{"user":"42"}`,
+		err:  "could not validate parameters, key not set",
+	}))
+
+	t.Run("api key with secret reference", testMerge(NewAuthApiKey(types.MockSecureStorager(func(ctx context.Context, s string) (string, error) {
+		return "", errors.New("secret not found")
+	}), nil), tf{
+		expr: `{"user":"42","key":"secret://foo"}`,
+		err:  "could not resolve secret: secret not found",
+	}))
+
+	t.Run("api key with secret reference and no secure storage", testMerge(NewAuthApiKey(nil, nil), tf{
+		expr: `{"user":"42","key":"secret://foo"}`,
+		err:  "could not resolve secret: secure storage not configured",
+	}))
+
+	t.Run("hmac with secret reference and no secure storage", testMerge(NewAuthHmac(nil, nil), tf{
+		expr: `{"user":"42","secret":"secret://foo"}`,
+		err:  "could not resolve secret: secure storage not configured",
+	}))
+
+	t.Run("oidc without issuer", testMerge(NewAuthOidc(nil), tf{
+		expr: `{}`,
+		err:  "could not validate parameters, issuer not set",
+	}))
+
+	t.Run("oidc without audience", testMerge(NewAuthOidc(nil), tf{
+		expr: `{"issuer":"https://idp.example.com"}`,
+		err:  "could not validate parameters, audience not set",
+	}))
+}
+
+func Test_authJwt(t *testing.T) {
+	var (
+		d = mockJwtAuthenticator(func(tkn string) (jwt.MapClaims, error) {
+			switch tkn {
+			case "valid":
+			case "no-api":
+				return jwt.MapClaims{"sub": "42", "roles": "1 2", "scope": "profile"}, nil
+			default:
+				return nil, errors.New("invalid")
+			}
+
+			return jwt.MapClaims{"sub": "42", "roles": "1 2", "scope": "profile api"}, nil
+		})
+
+		tcc = []struct {
+			name   string
+			params string
+			header string
+			err    string
+		}{
+			{
+				name:   "missing token",
+				params: `{}`,
+				err:    "access token missing",
+			},
+			{
+				name:   "invalid token",
+				params: `{}`,
+				header: "Bearer invalid",
+				err:    "invalid access token",
+			},
+			{
+				name:   "scope not allowed",
+				params: `{"scope":"admin"}`,
+				header: "Bearer valid",
+				err:    "access token scope not allowed",
+			},
+			{
+				name:   "api scope required by default",
+				params: `{}`,
+				header: "Bearer no-api",
+				err:    "access token scope not allowed",
+			},
+			{
+				name:   "valid token",
+				params: `{"scope":"api"}`,
+				header: "Bearer valid",
+			},
+			{
+				name:   "valid token with default scope",
+				params: `{}`,
+				header: "Bearer valid",
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+			)
+
+			r.Header.Set("Authorization", tc.header)
+
+			h, err := NewAuthJwt(d).Merge([]byte(tc.params))
+			req.NoError(err)
+
+			testAuthIdentity(req, r, h, tc.err, 42)
+		})
+	}
+}
+
+func Test_authApiKey(t *testing.T) {
+	var (
+		req = require.New(t)
+		ss  = types.MockSecureStorager(func(ctx context.Context, s string) (string, error) {
+			if s == "secret://key" {
+				return "s3cr3t", nil
+			}
+
+			return s, nil
+		})
+	)
+
+	h, err := NewAuthApiKey(ss, mockIdentityResolver).Merge([]byte(`{"key":"secret://key","user":"42"}`))
+	req.NoError(err)
+
+	r := httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+	testAuthIdentity(req, r, h, "API key missing", 0)
+
+	r.Header.Set("X-Api-Key", "secret://key")
+	testAuthIdentity(req, r, h, "invalid API key", 0)
+
+	r.Header.Set("X-Api-Key", "s3cr3t")
+	testAuthIdentity(req, r, h, "", 42)
+
+	// user can not be resolved
+	h, err = NewAuthApiKey(ss, mockIdentityResolver).Merge([]byte(`{"key":"secret://key","user":"1","header":"X-Key"}`))
+	req.NoError(err)
+
+	r.Header.Set("X-Key", "s3cr3t")
+	testAuthIdentity(req, r, h, "could not resolve identity: user not found", 0)
+}
+
+func Test_authHmac(t *testing.T) {
+	var (
+		req     = require.New(t)
+		payload = `{"foo":"bar"}`
+		signer  = auth.HmacSigner("s3cr3t")
+		now     = strconv.FormatInt(time.Now().Unix(), 10)
+	)
+
+	h, err := NewAuthHmac(nil, mockIdentityResolver).Merge([]byte(`{"secret":"s3cr3t","user":"42"}`))
+	req.NoError(err)
+
+	r := httptest.NewRequest(http.MethodPost, "/foo", http.NoBody)
+	r = r.WithContext(agctx.ScopeToContext(r.Context(), &types.Scp{"payload": payload}))
+	testAuthIdentity(req, r, h, "signature missing", 0)
+
+	r.Header.Set("X-Signature", signer.Sign(42, http.MethodPost, "/foo", now, "n1", payload))
+	testAuthIdentity(req, r, h, "signature timestamp or nonce missing", 0)
+
+	r.Header.Set("X-Signature-Nonce", "n1")
+	r.Header.Set("X-Signature-Timestamp", now)
+
+	// signed by another user
+	r.Header.Set("X-Signature", signer.Sign(1, http.MethodPost, "/foo", now, "n1", payload))
+	testAuthIdentity(req, r, h, "invalid signature", 0)
+
+	// signed for another path
+	r.Header.Set("X-Signature", signer.Sign(42, http.MethodPost, "/bar", now, "n1", payload))
+	testAuthIdentity(req, r, h, "invalid signature", 0)
+
+	r.Header.Set("X-Signature", signer.Sign(42, http.MethodPost, "/foo", now, "n1", payload))
+	testAuthIdentity(req, r, h, "", 42)
+
+	// replayed request
+	testAuthIdentity(req, r, h, "signature already used", 0)
+
+	// request signed outside of the window
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	r.Header.Set("X-Signature-Nonce", "n2")
+	r.Header.Set("X-Signature-Timestamp", old)
+	r.Header.Set("X-Signature", signer.Sign(42, http.MethodPost, "/foo", old, "n2", payload))
+	testAuthIdentity(req, r, h, "signature expired", 0)
+}
+
+func Test_authOidc(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	req.NoError(err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "k1", Algorithm: "RS256", Use: "sig"},
+		}})
+	}))
+	defer srv.Close()
+
+	sign := func(k *rsa.PrivateKey, claims jwt.MapClaims) string {
+		tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		tkn.Header["kid"] = "k1"
+
+		s, err := tkn.SignedString(k)
+		req.NoError(err)
+		return s
+	}
+
+	claims := func(iss, aud, email string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   iss,
+			"aud":   aud,
+			"sub":   "ext-user",
+			"email": email,
+			"exp":   time.Now().Add(time.Minute).Unix(),
+
+			"email_verified": true,
+		}
+	}
+
+	unverified := claims("https://idp.example.com", "corteza", "foo@example.com")
+	unverified["email_verified"] = false
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	req.NoError(err)
+
+	h, err := NewAuthOidc(mockIdentityResolver).Merge([]byte(`{"issuer":"https://idp.example.com","jwks":"` + srv.URL + `","audience":"corteza"}`))
+	req.NoError(err)
+
+	exec := func(tkn, err string, ID uint64) {
+		r := httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		r.Header.Set("Authorization", "Bearer "+tkn)
+		testAuthIdentity(req, r, h, err, ID)
+	}
+
+	exec(sign(key, claims("https://idp.example.com", "corteza", "foo@example.com")), "", 42)
+	exec(sign(other, claims("https://idp.example.com", "corteza", "foo@example.com")), "invalid access token: failed to verify signature: failed to verify id token signature", 0)
+	exec(sign(key, claims("https://idp.example.com", "other", "foo@example.com")), `invalid access token: oidc: expected audience "corteza" got ["other"]`, 0)
+	exec(sign(key, claims("https://other.example.com", "corteza", "foo@example.com")), `invalid access token: oidc: id token issued by a different provider, expected "https://idp.example.com" got "https://other.example.com"`, 0)
+	exec(sign(key, claims("https://idp.example.com", "corteza", "bar@example.com")), "could not resolve identity: user not found", 0)
+	exec(sign(key, unverified), "access token email not verified", 0)
+
+	delete(unverified, "email_verified")
+	exec(sign(key, unverified), "access token email not verified", 0)
+}
+
+// testAuthIdentity runs the auth filter and checks the identity set to the scope
+func testAuthIdentity(req *require.Assertions, r *http.Request, h types.Handler, expErr string, expID uint64) {
+	var (
+		scope = &types.Scp{}
+	)
+
+	if s, err := agctx.ScopeFromContext(r.Context()).Get("payload"); err == nil {
+		scope.Set("payload", s)
+	}
+
+	r = r.WithContext(agctx.ScopeToContext(r.Context(), scope))
+	err := h.Handler()(httptest.NewRecorder(), r)
+
+	if expErr != "" {
+		req.EqualError(err, expErr)
+		req.Nil(scope.Identity())
+		return
+	}
+
+	req.NoError(err)
+	req.NotNil(scope.Identity())
+	req.Equal(expID, scope.Identity().Identity())
+}
@@ -0,0 +1,387 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/spf13/cast"
+)
+
+const (
+	responseCacheDefaultEntries = 1000
+
+	// responses with larger bodies are not cached
+	responseCacheMaxBodySize = 1 << 20
+
+	responseCacheHeader = "X-Apigw-Cache"
+)
+
+type (
+	// responseCache stores successful responses and serves them
+	// to the subsequent requests with the same cache key
+	//
+	// It wraps processers and postfilters (see types.Wrapper);
+	// prefilters (auth, rate limiting...) run on every request
+	//
+	// Only GET and HEAD requests are cached; responses are
+	// cached per identity and Authorization header so that
+	// they are never served to a different client.
+	responseCache struct {
+		types.FilterMeta
+
+		ttl   time.Duration
+		eval  expr.Evaluable
+		store *responseCacheStore
+
+		params struct {
+			TTL     string `json:"ttl"`
+			Key     string `json:"key"`
+			Entries int    `json:"entries,string,omitempty"`
+		}
+	}
+
+	responseCacheStore struct {
+		mux sync.Mutex
+		max int
+		now func() time.Time
+		ee  map[string]*cachedResponse
+	}
+
+	cachedResponse struct {
+		status  int
+		header  http.Header
+		body    []byte
+		expires time.Time
+	}
+
+	// responseRecorder passes the response to the client
+	// and keeps a copy of it
+	//
+	// Copy is discarded (and response not cached) when
+	// body exceeds responseCacheMaxBodySize
+	responseRecorder struct {
+		http.ResponseWriter
+
+		status   int
+		body     bytes.Buffer
+		tooLarge bool
+	}
+)
+
+func NewResponseCache() (v *responseCache) {
+	v = &responseCache{}
+
+	v.Name = "responseCache"
+	v.Label = "Response cache"
+	v.Kind = types.PostFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "ttl",
+			Example: "5m",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "expr",
+			Label:   "key",
+			Example: `Defaults to method and URL, ie.: method + path + query.id; available: method, path, query, headers, payload, ip`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "number",
+			Label:   "entries",
+			Example: "Maximum number of cached responses, defaults to 1000",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h responseCache) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h responseCache) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+// Merge returns new response cache so that each route
+// keeps its own cached responses
+func (h *responseCache) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &responseCache{FilterMeta: h.FilterMeta}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.ttl, err = time.ParseDuration(v.params.TTL); err != nil || v.ttl <= 0 {
+		return nil, fmt.Errorf("could not validate parameters, invalid ttl: %s", v.params.TTL)
+	}
+
+	if v.params.Key != "" {
+		if v.eval, err = expr.NewParser().Parse(v.params.Key); err != nil {
+			return nil, fmt.Errorf("could not validate parameters, invalid key expression: %s", err)
+		}
+	}
+
+	if v.params.Entries <= 0 {
+		v.params.Entries = responseCacheDefaultEntries
+	}
+
+	v.store = &responseCacheStore{
+		max: v.params.Entries,
+		now: time.Now,
+		ee:  make(map[string]*cachedResponse),
+	}
+
+	return v, nil
+}
+
+// Handler does nothing, caching is done in Wrap
+func (h responseCache) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		return nil
+	}
+}
+
+func (h responseCache) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		key, err := h.key(r)
+		if err != nil {
+			// request can not be cached
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		if c := h.store.get(key); c != nil {
+			rw.Header().Set(responseCacheHeader, "hit")
+			c.write(rw)
+			return
+		}
+
+		rw.Header().Set(responseCacheHeader, "miss")
+
+		rec := &responseRecorder{ResponseWriter: rw}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == http.StatusOK && !rec.tooLarge && cacheable(rw.Header()) {
+			h.store.set(key, &cachedResponse{
+				status: rec.status,
+				header: rw.Header().Clone(),
+				body:   rec.body.Bytes(),
+			}, h.ttl)
+		}
+	})
+}
+
+// key evaluates cache key expression
+//
+// Identity and Authorization header are always part of the key;
+// identity set to the scope by the auth prefilters takes precedence
+// over the one on the request context
+func (h responseCache) key(r *http.Request) (string, error) {
+	var (
+		ctx = r.Context()
+		i   = agctx.ScopeFromContext(ctx).Identity()
+	)
+
+	if i == nil {
+		i = auth.GetIdentityFromContext(ctx)
+	}
+
+	prefix := fmt.Sprintf("%d %s ", i.Identity(), r.Header.Get("Authorization"))
+
+	if h.eval == nil {
+		return prefix + r.Method + " " + r.URL.RequestURI(), nil
+	}
+
+	var (
+		query   = map[string]interface{}{}
+		headers = map[string]interface{}{}
+		payload interface{}
+	)
+
+	for k, v := range r.URL.Query() {
+		query[k] = v[0]
+	}
+
+	for k, v := range r.Header {
+		headers[k] = v[0]
+	}
+
+	payload, _ = agctx.ScopeFromContext(ctx).Get("payload")
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	vv, err := expr.NewVars(map[string]interface{}{
+		"method":  r.Method,
+		"path":    r.URL.Path,
+		"query":   query,
+		"headers": headers,
+		"payload": payload,
+		"ip":      ip,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	out, err := h.eval.Eval(ctx, vv)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := cast.ToStringE(out)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + key, nil
+}
+
+// cacheable checks response headers; responses that set cookies,
+// are private, not to be stored or vary on everything are not cached
+func cacheable(hdr http.Header) bool {
+	if len(hdr.Values("Set-Cookie")) > 0 {
+		return false
+	}
+
+	for _, v := range hdr.Values("Vary") {
+		if strings.Contains(v, "*") {
+			return false
+		}
+	}
+
+	for _, v := range hdr.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(d)) {
+			case "private", "no-store", "no-cache":
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (s *responseCacheStore) get(key string) *cachedResponse {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	c, ok := s.ee[key]
+	if !ok {
+		return nil
+	}
+
+	if !s.now().Before(c.expires) {
+		delete(s.ee, key)
+		return nil
+	}
+
+	return c
+}
+
+func (s *responseCacheStore) set(key string, c *cachedResponse, ttl time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var (
+		now = s.now()
+	)
+
+	if _, ok := s.ee[key]; !ok && len(s.ee) >= s.max {
+		s.evict(now)
+	}
+
+	c.expires = now.Add(ttl)
+	s.ee[key] = c
+}
+
+// evict removes expired responses or the one
+// that expires first when all are still valid
+func (s *responseCacheStore) evict(now time.Time) {
+	var (
+		first string
+	)
+
+	for k, c := range s.ee {
+		if !now.Before(c.expires) {
+			delete(s.ee, k)
+			continue
+		}
+
+		if first == "" || c.expires.Before(s.ee[first].expires) {
+			first = k
+		}
+	}
+
+	if len(s.ee) >= s.max {
+		delete(s.ee, first)
+	}
+}
+
+// write replays cached response; headers already set
+// on the response (ie. by prefilters) are kept
+func (c *cachedResponse) write(rw http.ResponseWriter) {
+	hdr := rw.Header()
+
+	for k, vv := range c.header {
+		if _, has := hdr[k]; has {
+			continue
+		}
+
+		hdr[k] = vv
+	}
+
+	rw.WriteHeader(c.status)
+	rw.Write(c.body)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	if !r.tooLarge {
+		if r.body.Len()+len(b) > responseCacheMaxBodySize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_responseCacheMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "missing ttl",
+				expr: `{}`,
+				err:  "could not validate parameters, invalid ttl: ",
+			},
+			{
+				name: "invalid key",
+				expr: `{"ttl":"1m","key":"method +"}`,
+				err:  "could not validate parameters, invalid key expression: parsing error: method +\t:1:9 - 1:9 unexpected EOF while scanning extensions",
+			},
+			{
+				name: "valid",
+				expr: `{"ttl":"1m","key":"method + query.id"}`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewResponseCache(), tc))
+	}
+}
+
+func Test_responseCacheWrap(t *testing.T) {
+	var (
+		req   = require.New(t)
+		now   = time.Now()
+		calls = 0
+
+		next = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			calls++
+
+			if r.URL.Query().Get("fail") != "" {
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+
+			rw.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(rw, "response %d", calls)
+		})
+	)
+
+	h, err := NewResponseCache().Merge([]byte(`{"ttl":"1m","key":"query.id"}`))
+	req.NoError(err)
+
+	rc := h.(*responseCache)
+	rc.store.now = func() time.Time { return now }
+
+	exec := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		rc.Wrap(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+		return rr
+	}
+
+	rr := exec("/foo?id=1")
+	req.Equal("miss", rr.Header().Get(responseCacheHeader))
+	req.Equal("response 1", rr.Body.String())
+
+	rr = exec("/foo?id=1&bar=baz")
+	req.Equal("hit", rr.Header().Get(responseCacheHeader))
+	req.Equal("text/plain", rr.Header().Get("Content-Type"))
+	req.Equal("response 1", rr.Body.String())
+
+	rr = exec("/foo?id=2")
+	req.Equal("response 2", rr.Body.String())
+
+	// key can not be evaluated, response is not cached
+	rr = exec("/foo")
+	req.Equal("response 3", rr.Body.String())
+	req.Empty(rr.Header().Get(responseCacheHeader))
+
+	// unsuccessful responses are not cached
+	exec("/foo?id=3&fail=1")
+	rr = exec("/foo?id=3&fail=1")
+	req.Equal("response 5", rr.Body.String())
+
+	now = now.Add(time.Minute)
+
+	rr = exec("/foo?id=1")
+	req.Equal("miss", rr.Header().Get(responseCacheHeader))
+	req.Equal("response 6", rr.Body.String())
+}
+
+func Test_responseCacheUncacheable(t *testing.T) {
+	var (
+		req   = require.New(t)
+		calls = 0
+
+		next = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			calls++
+
+			if v := r.URL.Query().Get("cookie"); v != "" {
+				rw.Header().Set("Set-Cookie", "session="+v)
+			}
+
+			if v := r.URL.Query().Get("cc"); v != "" {
+				rw.Header().Set("Cache-Control", v)
+			}
+
+			fmt.Fprintf(rw, "response %d", calls)
+		})
+	)
+
+	h, err := NewResponseCache().Merge([]byte(`{"ttl":"1m"}`))
+	req.NoError(err)
+
+	exec := func(method, url, authorization string) string {
+		var (
+			rr = httptest.NewRecorder()
+			r  = httptest.NewRequest(method, url, http.NoBody)
+		)
+
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+
+		h.(*responseCache).Wrap(next).ServeHTTP(rr, r)
+		return rr.Body.String()
+	}
+
+	// only GET and HEAD requests are cached
+	exec(http.MethodPost, "/foo", "")
+	req.Equal("response 2", exec(http.MethodPost, "/foo", ""))
+
+	// responses are not shared between clients
+	req.Equal("response 3", exec(http.MethodGet, "/foo", "Bearer foo"))
+	req.Equal("response 3", exec(http.MethodGet, "/foo", "Bearer foo"))
+	req.Equal("response 4", exec(http.MethodGet, "/foo", "Bearer bar"))
+	req.Equal("response 5", exec(http.MethodGet, "/foo", ""))
+
+	// responses that set cookies or are private are not cached
+	exec(http.MethodGet, "/foo?cookie=1", "")
+	req.Equal("response 7", exec(http.MethodGet, "/foo?cookie=1", ""))
+	exec(http.MethodGet, "/foo?cc=private", "")
+	req.Equal("response 9", exec(http.MethodGet, "/foo?cc=private", ""))
+	exec(http.MethodGet, "/foo?cc=max-age=0,+no-store", "")
+	req.Equal("response 11", exec(http.MethodGet, "/foo?cc=max-age=0,+no-store", ""))
+}
+
+func Test_responseCacheLargeBody(t *testing.T) {
+	var (
+		req = require.New(t)
+		rr  = httptest.NewRecorder()
+		rec = &responseRecorder{ResponseWriter: rr}
+		b   = make([]byte, responseCacheMaxBodySize/2+1)
+	)
+
+	rec.Write(b)
+	req.False(rec.tooLarge)
+	req.Equal(len(b), rec.body.Len())
+
+	// copy is dropped once the limit is exceeded,
+	// response is still passed to the client
+	rec.Write(b)
+	rec.Write(b)
+	req.True(rec.tooLarge)
+	req.Zero(rec.body.Len())
+	req.Equal(len(b)*3, rr.Body.Len())
+}
+
+func Test_responseCacheEvict(t *testing.T) {
+	var (
+		req = require.New(t)
+		now = time.Now()
+		s   = &responseCacheStore{max: 2, now: func() time.Time { return now }, ee: map[string]*cachedResponse{}}
+	)
+
+	s.set("a", &cachedResponse{}, time.Minute)
+	s.set("b", &cachedResponse{}, 2*time.Minute)
+	s.set("c", &cachedResponse{}, time.Minute)
+
+	req.Nil(s.get("a"))
+	req.NotNil(s.get("b"))
+	req.NotNil(s.get("c"))
+}
+
+func Test_responseCacheScopeIdentity(t *testing.T) {
+	var (
+		req   = require.New(t)
+		calls = 0
+
+		next = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			calls++
+			fmt.Fprintf(rw, "response %d", calls)
+		})
+	)
+
+	h, err := NewResponseCache().Merge([]byte(`{"ttl":"1m"}`))
+	req.NoError(err)
+
+	// identities set by the auth prefilters are on the scope,
+	// not on the request context
+	exec := func(userID uint64) *httptest.ResponseRecorder {
+		var (
+			rr = httptest.NewRecorder()
+			r  = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Authorization", "Basic c2hhcmVk")
+		r = r.WithContext(agctx.ScopeToContext(r.Context(), &types.Scp{"identity": auth.Authenticated(userID)}))
+		h.(*responseCache).Wrap(next).ServeHTTP(rr, r)
+		return rr
+	}
+
+	req.Equal("response 1", exec(1).Body.String())
+	req.Equal("response 1", exec(1).Body.String())
+	req.Equal("response 2", exec(2).Body.String())
+}
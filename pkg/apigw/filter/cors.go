@@ -0,0 +1,209 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	pe "github.com/cortezaproject/corteza-server/pkg/errors"
+)
+
+const (
+	// same as server's CORS handler
+	corsMaxAge = 300
+)
+
+type (
+	// cors enforces per-route CORS policy
+	//
+	// Preflight requests are answered by the route (see types.Preflighter)
+	// instead of the server's CORS handler; the policy is enforced on the
+	// actual request by rejecting disallowed origins and by setting
+	// the response headers the browser checks
+	cors struct {
+		types.FilterMeta
+
+		origins []string
+
+		params struct {
+			Origins       string `json:"origins"`
+			Credentials   bool   `json:"credentials"`
+			ExposeHeaders string `json:"exposeHeaders"`
+		}
+	}
+)
+
+func NewCors() (v *cors) {
+	v = &cors{}
+
+	v.Name = "cors"
+	v.Label = "CORS policy"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "origins",
+			Example: "Keep the origins separated with spaces ie.: 'https://example.com https://*.example.org', use '*' to allow any",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "bool",
+			Label:   "credentials",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "exposeHeaders",
+			Example: "X-RateLimit-Remaining",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h cors) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h cors) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *cors) Merge(params []byte) (types.Handler, error) {
+	var (
+		v = &cors{FilterMeta: h.FilterMeta}
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v.origins = strings.Fields(strings.ToLower(v.params.Origins))
+
+	if len(v.origins) == 0 {
+		return nil, fmt.Errorf("could not validate parameters, origins not set")
+	}
+
+	if v.params.Credentials && v.any() {
+		// any site could make credentialed requests
+		return nil, fmt.Errorf("could not validate parameters, credentials can not be allowed for any origin")
+	}
+
+	return v, nil
+}
+
+func (h cors) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		var (
+			origin = r.Header.Get("Origin")
+			hdr    = rw.Header()
+		)
+
+		hdr.Add("Vary", "Origin")
+
+		if origin == "" {
+			// not a cross-origin request
+			return nil
+		}
+
+		// remove permissive headers set by the server's CORS handler
+		hdr.Del("Access-Control-Allow-Origin")
+		hdr.Del("Access-Control-Allow-Credentials")
+
+		if !h.allowed(origin) {
+			return pe.Unauthenticated("origin not allowed")
+		}
+
+		h.allowOrigin(hdr, origin)
+
+		if h.params.ExposeHeaders != "" {
+			hdr.Set("Access-Control-Expose-Headers", strings.Join(strings.Fields(h.params.ExposeHeaders), ", "))
+		}
+
+		return nil
+	}
+}
+
+// Preflight answers preflight request
+//
+// Route checks the requested method before the preflight is handled;
+// requested headers are allowed for the allowed origins
+func (h cors) Preflight(rw http.ResponseWriter, r *http.Request) error {
+	var (
+		origin = r.Header.Get("Origin")
+		hdr    = rw.Header()
+	)
+
+	hdr.Add("Vary", "Origin")
+	hdr.Add("Vary", "Access-Control-Request-Method")
+	hdr.Add("Vary", "Access-Control-Request-Headers")
+
+	if !h.allowed(origin) {
+		return pe.Unauthenticated("origin not allowed")
+	}
+
+	h.allowOrigin(hdr, origin)
+
+	hdr.Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+
+	if v := r.Header.Get("Access-Control-Request-Headers"); v != "" {
+		hdr.Set("Access-Control-Allow-Headers", v)
+	}
+
+	hdr.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+	return nil
+}
+
+func (h cors) allowOrigin(hdr http.Header, origin string) {
+	if h.params.Credentials || !h.any() {
+		hdr.Set("Access-Control-Allow-Origin", origin)
+	} else {
+		hdr.Set("Access-Control-Allow-Origin", "*")
+	}
+
+	if h.params.Credentials {
+		hdr.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (h cors) any() bool {
+	for _, o := range h.origins {
+		if o == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowed checks origin against the list of allowed origins;
+// origins can contain one wildcard (ie. https://*.example.org)
+func (h cors) allowed(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, o := range h.origins {
+		if o == "*" || o == origin {
+			return true
+		}
+
+		if i := strings.Index(o, "*"); i >= 0 {
+			var (
+				prefix, suffix = o[:i], o[i+1:]
+			)
+
+			if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_corsMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "missing origins",
+				expr: `{"origins":" "}`,
+				err:  "could not validate parameters, origins not set",
+			},
+			{
+				name: "credentials for any origin",
+				expr: `{"origins":"https://example.com *","credentials":true}`,
+				err:  "could not validate parameters, credentials can not be allowed for any origin",
+			},
+			{
+				name: "valid",
+				expr: `{"origins":"https://example.com https://*.example.org"}`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewCors(), tc))
+	}
+}
+
+func Test_corsHandle(t *testing.T) {
+	type (
+		tcors struct {
+			name        string
+			params      string
+			origin      string
+			err         string
+			allowOrigin string
+			credentials string
+		}
+	)
+
+	var (
+		tcc = []tcors{
+			{
+				name:        "allowed origin",
+				params:      `{"origins":"https://example.com"}`,
+				origin:      "https://example.com",
+				allowOrigin: "https://example.com",
+			},
+			{
+				name:   "disallowed origin",
+				params: `{"origins":"https://example.com"}`,
+				origin: "https://example.org",
+				err:    "origin not allowed",
+			},
+			{
+				name:        "wildcard subdomain",
+				params:      `{"origins":"https://*.example.org"}`,
+				origin:      "https://api.Example.org",
+				allowOrigin: "https://api.Example.org",
+			},
+			{
+				name:   "wildcard subdomain mismatch",
+				params: `{"origins":"https://*.example.org"}`,
+				origin: "https://example.com",
+				err:    "origin not allowed",
+			},
+			{
+				name:        "any origin",
+				params:      `{"origins":"*"}`,
+				origin:      "https://example.com",
+				allowOrigin: "*",
+			},
+			{
+				name:        "credentials",
+				params:      `{"origins":"https://*.com","credentials":true}`,
+				origin:      "https://example.com",
+				allowOrigin: "https://example.com",
+				credentials: "true",
+			},
+			{
+				name:   "same origin",
+				params: `{"origins":"https://example.com"}`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				rr  = httptest.NewRecorder()
+				r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+			)
+
+			h, err := NewCors().Merge([]byte(tc.params))
+			req.NoError(err)
+
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			// set by the server's CORS handler
+			rr.Header().Set("Access-Control-Allow-Origin", "*")
+
+			err = h.Handler()(rr, r)
+
+			if tc.err != "" {
+				req.EqualError(err, tc.err)
+				req.Empty(rr.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+
+			req.NoError(err)
+			req.Equal("Origin", rr.Header().Get("Vary"))
+			req.Equal(tc.credentials, rr.Header().Get("Access-Control-Allow-Credentials"))
+
+			if tc.origin != "" {
+				req.Equal(tc.allowOrigin, rr.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
+
+func Test_corsPreflight(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	h, err := NewCors().Merge([]byte(`{"origins":"https://example.com","credentials":true}`))
+	req.NoError(err)
+
+	preflight := func(origin string) (*httptest.ResponseRecorder, error) {
+		var (
+			rr = httptest.NewRecorder()
+			r  = httptest.NewRequest(http.MethodOptions, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		r.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+
+		return rr, h.(*cors).Preflight(rr, r)
+	}
+
+	rr, err := preflight("https://example.com")
+	req.NoError(err)
+	req.Equal("https://example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	req.Equal("true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	req.Equal(http.MethodPost, rr.Header().Get("Access-Control-Allow-Methods"))
+	req.Equal("Authorization, Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+
+	rr, err = preflight("https://example.org")
+	req.EqualError(err, "origin not allowed")
+	req.Empty(rr.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -13,6 +13,7 @@ import (
 	atypes "github.com/cortezaproject/corteza-server/automation/types"
 	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	pe "github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/jsenv"
@@ -107,6 +108,12 @@ func (h workflow) Handler() types.HandlerFunc {
 			return pe.Internal("could not validate request data: (%v)", err)
 		}
 
+		// run workflow as the caller when authenticated
+		// by one of the auth prefilters
+		if i := scope.Identity(); i != nil {
+			ctx = auth.SetIdentityToContext(ctx, i)
+		}
+
 		wp := atypes.WorkflowExecParams{
 			Trace: true,
 			// todo depending on settings per-route
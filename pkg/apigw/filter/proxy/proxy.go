@@ -83,7 +83,7 @@ func (f *proxy) Merge(params []byte) (types.Handler, error) {
 	}
 
 	// get the auth mechanism
-	f.a, err = NewProxyAuthServicer(f.c, f.params.Auth, f.s)
+	f.a, err = NewProxyAuthServicer(context.Background(), f.c, f.params.Auth, f.s)
 
 	if err != nil {
 		return nil, fmt.Errorf("could not load auth servicer for proxying: %s", err)
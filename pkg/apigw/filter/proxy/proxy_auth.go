@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
 	"github.com/cortezaproject/corteza-server/pkg/http/auth"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
 )
 
 const (
@@ -77,7 +79,12 @@ type (
 	}
 )
 
-func NewProxyAuthServicer(c *http.Client, p ProxyAuthParams, s types.SecureStorager) (ProxyAuthServicer, error) {
+func NewProxyAuthServicer(ctx context.Context, c *http.Client, p ProxyAuthParams, s types.SecureStorager) (_ ProxyAuthServicer, err error) {
+	// replace secret references with values from the secure storage
+	if p.Params, err = resolveSecrets(ctx, s, p.Params); err != nil {
+		return nil, err
+	}
+
 	switch p.Type {
 	case proxyAuthTypeHeader:
 		return NewProxyAuthHeader(p)
@@ -94,6 +101,43 @@ func NewProxyAuthServicer(c *http.Client, p ProxyAuthParams, s types.SecureStora
 	}
 }
 
+// resolveSecrets returns copy of params with secret references
+// replaced by the secret values
+//
+// Without secure storage, secret references can not be resolved
+// and are never sent upstream as-is
+func resolveSecrets(ctx context.Context, s types.SecureStorager, pp map[string]interface{}) (out map[string]interface{}, err error) {
+	if len(pp) == 0 {
+		return pp, nil
+	}
+
+	if s == nil {
+		for k, v := range pp {
+			if str, is := v.(string); is {
+				if _, is = secure.ParseReference(str); is {
+					return nil, fmt.Errorf("could not resolve param %s: secure storage not configured", k)
+				}
+			}
+		}
+
+		return pp, nil
+	}
+
+	out = make(map[string]interface{}, len(pp))
+
+	for k, v := range pp {
+		if str, is := v.(string); is {
+			if v, err = s.Resolve(ctx, str); err != nil {
+				return nil, fmt.Errorf("could not resolve param %s: %w", k, err)
+			}
+		}
+
+		out[k] = v
+	}
+
+	return
+}
+
 func NewProxyAuthHeader(p ProxyAuthParams) (s proxyAuthServicerHeader, err error) {
 	s = proxyAuthServicerHeader{
 		params: p.Params,
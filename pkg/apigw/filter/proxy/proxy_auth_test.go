@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -81,6 +83,26 @@ func Test_authDo(t *testing.T) {
 				params: ProxyAuthParams{},
 				exp:    http.Header{},
 			},
+			{
+				name: "auth basic with secret reference",
+				params: ProxyAuthParams{
+					Type: proxyAuthTypeBasic,
+					Params: map[string]interface{}{
+						"username": "user",
+						"password": "secret://basic-pass",
+					},
+				},
+				exp: http.Header{"Authorization": []string{"Basic dXNlcjpwYXNzMTIzNA=="}},
+			},
+			{
+				name: "auth header with missing secret reference",
+				params: ProxyAuthParams{
+					Type:   proxyAuthTypeHeader,
+					Params: map[string]interface{}{"X-Token": "secret://missing"},
+				},
+				exp:  http.Header{},
+				errv: "could not resolve param X-Token: secret not found",
+			},
 			{
 				name: "auth JWT token",
 				params: ProxyAuthParams{
@@ -99,13 +121,24 @@ func Test_authDo(t *testing.T) {
 			var (
 				req = require.New(t)
 				c   = http.DefaultClient
+
+				ss = types.MockSecureStorager(func(_ context.Context, v string) (string, error) {
+					switch v {
+					case "secret://basic-pass":
+						return "pass1234", nil
+					case "secret://missing":
+						return "", fmt.Errorf("secret not found")
+					}
+
+					return v, nil
+				})
 			)
 
 			c.Transport = types.MockRoundTripper(func(r *http.Request) (rs *http.Response, err error) { return })
 
 			rq, _ := http.NewRequest("POST", "/foo", http.NoBody)
 
-			authServicer, err := NewProxyAuthServicer(c, tc.params, struct{}{})
+			authServicer, err := NewProxyAuthServicer(context.Background(), c, tc.params, ss)
 
 			if tc.errv != "" {
 				req.EqualError(err, tc.errv)
@@ -122,3 +155,22 @@ func Test_authDo(t *testing.T) {
 		})
 	}
 }
+
+func Test_proxyAuthWithoutSecureStorage(t *testing.T) {
+	var (
+		req = require.New(t)
+		c   = http.DefaultClient
+	)
+
+	_, err := NewProxyAuthServicer(context.Background(), c, ProxyAuthParams{
+		Type:   proxyAuthTypeHeader,
+		Params: map[string]interface{}{"X-Token": "secret://token"},
+	}, nil)
+	req.EqualError(err, "could not resolve param X-Token: secure storage not configured")
+
+	_, err = NewProxyAuthServicer(context.Background(), c, ProxyAuthParams{
+		Type:   proxyAuthTypeHeader,
+		Params: map[string]interface{}{"X-Token": "1234"},
+	}, nil)
+	req.NoError(err)
+}
@@ -174,7 +174,7 @@ func Test_proxy(t *testing.T) {
 				rq = httptest.NewRequest("POST", "/foo", strings.NewReader(`custom request body`))
 			}
 
-			proxy := New(zap.NewNop(), c, struct{}{})
+			proxy := New(zap.NewNop(), c, nil)
 			proxy.Merge([]byte(tc.params))
 
 			scope := &types.Scp{
@@ -0,0 +1,284 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	pe "github.com/cortezaproject/corteza-server/pkg/errors"
+)
+
+const (
+	rateLimitKeyIP   = "ip"
+	rateLimitKeyUser = "user"
+
+	// Header values are not verified; clients can bypass the limit
+	// by changing the value. Use it only with headers that are checked
+	// by the preceding filters or use user key instead
+	rateLimitKeyHeader = "header"
+
+	// max number of buckets; full (unused) buckets are removed first,
+	// then the ones that were not used for the longest time
+	rateLimitMaxBuckets = 10000
+)
+
+type (
+	rateLimit struct {
+		types.FilterMeta
+
+		buckets *tokenBuckets
+
+		params struct {
+			Requests int    `json:"requests,string"`
+			Period   string `json:"period"`
+			Burst    int    `json:"burst,string,omitempty"`
+			Key      string `json:"key"`
+			Header   string `json:"header"`
+		}
+	}
+
+	// tokenBuckets holds one token bucket per client
+	//
+	// Buckets are refilled with rate tokens per second
+	// up to the burst size
+	tokenBuckets struct {
+		mux   sync.Mutex
+		rate  float64
+		burst float64
+		now   func() time.Time
+		bb    map[string]*tokenBucket
+	}
+
+	tokenBucket struct {
+		tokens float64
+		last   time.Time
+
+		// last time token was taken from the bucket
+		seen time.Time
+	}
+)
+
+func NewRateLimit() (v *rateLimit) {
+	v = &rateLimit{}
+
+	v.Name = "rateLimit"
+	v.Label = "Rate limit"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "number",
+			Label:   "requests",
+			Example: "60",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "period",
+			Example: "1m",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "number",
+			Label:   "burst",
+			Example: "Maximum number of requests at once, defaults to number of requests",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:  "select",
+			Label: "key",
+			Options: map[string]interface{}{
+				"values": []string{rateLimitKeyIP, rateLimitKeyHeader, rateLimitKeyUser},
+			},
+		},
+		{
+			Type:    "text",
+			Label:   "header",
+			Example: "X-Api-Key; value is not verified, use only headers that are checked by other filters",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h rateLimit) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h rateLimit) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+// Merge returns new rate limiter so that each route
+// keeps its own set of buckets
+func (h *rateLimit) Merge(params []byte) (types.Handler, error) {
+	var (
+		v      = &rateLimit{FilterMeta: h.FilterMeta}
+		period = time.Second
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&v.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if v.params.Requests <= 0 {
+		return nil, fmt.Errorf("could not validate parameters, invalid number of requests: %d", v.params.Requests)
+	}
+
+	if v.params.Period != "" {
+		if period, err = time.ParseDuration(v.params.Period); err != nil || period <= 0 {
+			return nil, fmt.Errorf("could not validate parameters, invalid period: %s", v.params.Period)
+		}
+	}
+
+	switch v.params.Key {
+	case "":
+		v.params.Key = rateLimitKeyIP
+	case rateLimitKeyIP, rateLimitKeyUser:
+	case rateLimitKeyHeader:
+		if v.params.Header == "" {
+			return nil, fmt.Errorf("could not validate parameters, header not set")
+		}
+	default:
+		return nil, fmt.Errorf("could not validate parameters, invalid key: %s", v.params.Key)
+	}
+
+	if v.params.Burst <= 0 {
+		v.params.Burst = v.params.Requests
+	}
+
+	v.buckets = newTokenBuckets(float64(v.params.Requests)/period.Seconds(), float64(v.params.Burst))
+
+	return v, nil
+}
+
+func (h rateLimit) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		var (
+			key              = h.key(r)
+			remaining, retry = h.buckets.take(key)
+		)
+
+		rw.Header().Set("X-RateLimit-Limit", strconv.Itoa(h.params.Burst))
+		rw.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if retry > 0 {
+			rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			return pe.TooManyRequests("rate limit exceeded")
+		}
+
+		return nil
+	}
+}
+
+// key returns the identifier of the client's bucket
+//
+// Requests without the configured header or identity
+// fall back to the client's IP address; see rateLimitKeyHeader
+// on limitations of header based keys
+func (h rateLimit) key(r *http.Request) string {
+	switch h.params.Key {
+	case rateLimitKeyHeader:
+		if v := r.Header.Get(h.params.Header); v != "" {
+			return "header:" + v
+		}
+
+	case rateLimitKeyUser:
+		if i := agctx.ScopeFromContext(r.Context()).Identity(); i != nil {
+			return "user:" + strconv.FormatUint(i.Identity(), 10)
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+func newTokenBuckets(rate, burst float64) *tokenBuckets {
+	return &tokenBuckets{
+		rate:  rate,
+		burst: burst,
+		now:   time.Now,
+		bb:    make(map[string]*tokenBucket),
+	}
+}
+
+// take removes one token from the client's bucket
+//
+// Returns number of tokens left and, when bucket is empty,
+// how long the client needs to wait for the next token
+func (tb *tokenBuckets) take(key string) (remaining int, retry time.Duration) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+
+	var (
+		now = tb.now()
+		b   = tb.bb[key]
+	)
+
+	if b == nil {
+		if len(tb.bb) >= rateLimitMaxBuckets {
+			tb.cleanup(now)
+		}
+
+		b = &tokenBucket{tokens: tb.burst, last: now}
+		tb.bb[key] = b
+	}
+
+	b.refill(now, tb.rate, tb.burst)
+	b.seen = now
+
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / tb.rate * float64(time.Second))
+	}
+
+	b.tokens--
+	return int(b.tokens), 0
+}
+
+// cleanup removes buckets that are full again (they are the same as the new ones);
+// when there are still too many, least recently used tenth of the buckets is removed
+func (tb *tokenBuckets) cleanup(now time.Time) {
+	for k, b := range tb.bb {
+		if b.refill(now, tb.rate, tb.burst); b.tokens >= tb.burst {
+			delete(tb.bb, k)
+		}
+	}
+
+	if len(tb.bb) < rateLimitMaxBuckets {
+		return
+	}
+
+	kk := make([]string, 0, len(tb.bb))
+	for k := range tb.bb {
+		kk = append(kk, k)
+	}
+
+	sort.Slice(kk, func(i, j int) bool {
+		return tb.bb[kk[i]].seen.Before(tb.bb[kk[j]].seen)
+	})
+
+	for _, k := range kk[:len(kk)-rateLimitMaxBuckets*9/10] {
+		delete(tb.bb, k)
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+}
@@ -0,0 +1,136 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rateLimitMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "missing requests",
+				expr: `{"period":"1m"}`,
+				err:  "could not validate parameters, invalid number of requests: 0",
+			},
+			{
+				name: "invalid period",
+				expr: `{"requests":"10","period":"foo"}`,
+				err:  "could not validate parameters, invalid period: foo",
+			},
+			{
+				name: "header key without header",
+				expr: `{"requests":"10","key":"header"}`,
+				err:  "could not validate parameters, header not set",
+			},
+			{
+				name: "valid",
+				expr: `{"requests":"10","period":"1m","key":"user"}`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewRateLimit(), tc))
+	}
+}
+
+func Test_rateLimitHandle(t *testing.T) {
+	var (
+		req = require.New(t)
+		now = time.Now()
+	)
+
+	h, err := NewRateLimit().Merge([]byte(`{"requests":"2","period":"1m","key":"header","header":"X-Api-Key"}`))
+	req.NoError(err)
+
+	rl := h.(*rateLimit)
+	rl.buckets.now = func() time.Time { return now }
+
+	exec := func(key string) (*httptest.ResponseRecorder, error) {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		r.Header.Set("X-Api-Key", key)
+		return rr, rl.Handler()(rr, r)
+	}
+
+	rr, err := exec("a")
+	req.NoError(err)
+	req.Equal("1", rr.Header().Get("X-RateLimit-Remaining"))
+
+	_, err = exec("a")
+	req.NoError(err)
+
+	rr, err = exec("a")
+	req.EqualError(err, "rate limit exceeded")
+	req.Equal("30", rr.Header().Get("Retry-After"))
+
+	// other clients have their own bucket
+	_, err = exec("b")
+	req.NoError(err)
+
+	// one token is added every 30 seconds
+	now = now.Add(30 * time.Second)
+
+	_, err = exec("a")
+	req.NoError(err)
+}
+
+func Test_rateLimitKey(t *testing.T) {
+	var (
+		req = require.New(t)
+		r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+	)
+
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	h, err := NewRateLimit().Merge([]byte(`{"requests":"2","key":"user"}`))
+	req.NoError(err)
+
+	// no identity in scope
+	req.Equal("ip:10.0.0.1", h.(*rateLimit).key(r))
+
+	h, err = NewRateLimit().Merge([]byte(`{"requests":"2","key":"header","header":"X-Api-Key"}`))
+	req.NoError(err)
+
+	r.Header.Set("X-Api-Key", "foo")
+	req.Equal("header:foo", h.(*rateLimit).key(r))
+}
+
+func Test_rateLimitCleanup(t *testing.T) {
+	var (
+		req = require.New(t)
+		now = time.Now()
+		tb  = newTokenBuckets(0.01, 10)
+	)
+
+	tb.now = func() time.Time { return now }
+
+	for i := 0; i < rateLimitMaxBuckets; i++ {
+		now = now.Add(time.Millisecond)
+		tb.take(fmt.Sprintf("k%d", i))
+	}
+
+	req.Len(tb.bb, rateLimitMaxBuckets)
+
+	// buckets are not full yet; least recently used ones are removed
+	now = now.Add(time.Millisecond)
+	tb.take("new")
+	req.Len(tb.bb, rateLimitMaxBuckets*9/10+1)
+	req.NotContains(tb.bb, "k0")
+	req.Contains(tb.bb, fmt.Sprintf("k%d", rateLimitMaxBuckets-1))
+
+	// full buckets are removed
+	for i := len(tb.bb); i < rateLimitMaxBuckets; i++ {
+		tb.take(fmt.Sprintf("n%d", i))
+	}
+
+	now = now.Add(time.Hour)
+	tb.take("newer")
+	req.Len(tb.bb, 1)
+}
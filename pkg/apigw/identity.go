@@ -0,0 +1,51 @@
+package apigw
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// identityResolver loads users that API Gateway routes
+	// are executed as after caller is authenticated
+	identityResolver struct {
+		storer storer
+	}
+)
+
+func (ir identityResolver) ResolveIdentity(ctx context.Context, userID uint64) (auth.Identifiable, error) {
+	u, err := ir.storer.LookupUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load user %d: %w", userID, err)
+	}
+
+	return ir.identity(ctx, u)
+}
+
+func (ir identityResolver) ResolveIdentityByEmail(ctx context.Context, email string) (auth.Identifiable, error) {
+	u, err := ir.storer.LookupUserByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("could not load user %s: %w", email, err)
+	}
+
+	return ir.identity(ctx, u)
+}
+
+// identity checks if user is still active and loads user's roles
+func (ir identityResolver) identity(ctx context.Context, u *st.User) (auth.Identifiable, error) {
+	if !u.Valid() {
+		return nil, fmt.Errorf("user %d is suspended or deleted", u.ID)
+	}
+
+	rr, _, err := ir.storer.SearchRoles(ctx, st.RoleFilter{MemberID: u.ID})
+	if err != nil {
+		return nil, fmt.Errorf("could not load roles of user %d: %w", u.ID, err)
+	}
+
+	u.SetRoles(rr.IDs()...)
+
+	return u, nil
+}
@@ -0,0 +1,50 @@
+package apigw
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	st "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_identityResolver(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		now = time.Now()
+
+		ir = identityResolver{storer: types.MockStorer{
+			U: func(ctx context.Context, ID uint64) (*st.User, error) {
+				u := &st.User{ID: ID}
+
+				if ID == 2 {
+					u.SuspendedAt = &now
+				}
+
+				return u, nil
+			},
+			E: func(ctx context.Context, email string) (*st.User, error) {
+				return &st.User{ID: 3, Email: email}, nil
+			},
+			Roles: func(ctx context.Context, f st.RoleFilter) (st.RoleSet, st.RoleFilter, error) {
+				return st.RoleSet{{ID: f.MemberID * 10}}, f, nil
+			},
+		}}
+	)
+
+	i, err := ir.ResolveIdentity(ctx, 1)
+	req.NoError(err)
+	req.Equal(uint64(1), i.Identity())
+	req.Equal([]uint64{10}, i.Roles())
+
+	_, err = ir.ResolveIdentity(ctx, 2)
+	req.EqualError(err, "user 2 is suspended or deleted")
+
+	i, err = ir.ResolveIdentityByEmail(ctx, "foo@example.com")
+	req.NoError(err)
+	req.Equal(uint64(3), i.Identity())
+	req.Equal([]uint64{30}, i.Roles())
+}
@@ -19,6 +19,12 @@ type (
 		Name    string
 		Type    types.FilterKind
 		Handler func(rw http.ResponseWriter, r *http.Request) error
+
+		// Wrap is set for workers that wrap processers and postfilters
+		Wrap func(http.Handler) http.Handler
+
+		// Preflight is set for workers that answer CORS preflight requests
+		Preflight func(rw http.ResponseWriter, r *http.Request) error
 	}
 
 	workerSet []*Worker
@@ -74,11 +80,29 @@ func (pp *Pl) Handler() http.Handler {
 
 // makeMiddleware creates a list of handlers from workers
 // it is used in chaining
+//
+// Wrappers are chained right after the prefilters so they
+// wrap the processers and postfilters, regardless of their weight
 func (pp *Pl) makeMiddleware(hh ...*Worker) (middleware []func(http.Handler) http.Handler) {
+	var (
+		pre, wrap, rest []func(http.Handler) http.Handler
+	)
+
 	for _, wrker := range hh {
-		middleware = append(middleware, pp.makeHandler(*wrker))
+		switch {
+		case wrker.Wrap != nil:
+			wrap = append(wrap, wrker.Wrap)
+		case wrker.Type == types.PreFilter:
+			pre = append(pre, pp.makeHandler(*wrker))
+		default:
+			rest = append(rest, pp.makeHandler(*wrker))
+		}
 	}
 
+	middleware = append(middleware, pre...)
+	middleware = append(middleware, wrap...)
+	middleware = append(middleware, rest...)
+
 	return middleware
 }
 
@@ -123,3 +123,46 @@ func Test_pipelineExecErr(t *testing.T) {
 	}
 
 }
+
+func Test_pipelineWrap(t *testing.T) {
+	var (
+		req = require.New(t)
+		rr  = httptest.NewRecorder()
+		p   = NewPl()
+
+		write = func(s string) func(rw http.ResponseWriter, r *http.Request) error {
+			return func(rw http.ResponseWriter, r *http.Request) error {
+				rw.Write([]byte(s))
+				return nil
+			}
+		}
+	)
+
+	p.Add(&Worker{
+		Handler: write("pre"),
+		Type:    types.PreFilter,
+		Weight:  0,
+	})
+
+	p.Add(&Worker{
+		Handler: write("proc"),
+		Type:    types.Processer,
+		Weight:  100,
+	})
+
+	p.Add(&Worker{
+		Type:   types.PostFilter,
+		Weight: 200,
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte("["))
+				next.ServeHTTP(rw, r)
+				rw.Write([]byte("]"))
+			})
+		},
+	})
+
+	p.Handler().ServeHTTP(rr, &http.Request{})
+
+	req.Equal(`pre[proc]`, rr.Body.String())
+}
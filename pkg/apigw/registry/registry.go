@@ -8,6 +8,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter/proxy"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"go.uber.org/zap"
 )
@@ -16,8 +17,6 @@ type (
 	Registry struct {
 		h map[string]types.Handler
 	}
-
-	secureStorageTodo struct{}
 )
 
 func NewRegistry() *Registry {
@@ -57,19 +56,26 @@ func (r *Registry) All() (list types.FilterMetaList) {
 	return
 }
 
-func (r *Registry) Preload() {
+func (r *Registry) Preload(s types.SecureStorager, ir types.IdentityResolver) {
 	// prefilters
 	r.Add("queryParam", filter.NewQueryParam())
 	r.Add("header", filter.NewHeader())
+	r.Add("authJwt", filter.NewAuthJwt(auth.DefaultJwtHandler))
+	r.Add("authOidc", filter.NewAuthOidc(ir))
+	r.Add("authHmac", filter.NewAuthHmac(s, ir))
+	r.Add("authApiKey", filter.NewAuthApiKey(s, ir))
+	r.Add("rateLimit", filter.NewRateLimit())
+	r.Add("cors", filter.NewCors())
 
 	// processers
 	r.Add("workflow", filter.NewWorkflow(NewWorkflow()))
-	r.Add("proxy", proxy.New(service.DefaultLogger, http.DefaultClient, secureStorageTodo{}))
+	r.Add("proxy", proxy.New(service.DefaultLogger, http.DefaultClient, s))
 	r.Add("payload", filter.NewPayload(service.DefaultLogger))
 
 	// postfilters
 	r.Add("redirection", filter.NewRedirection())
 	r.Add("defaultJsonResponse", filter.NewDefaultJsonResponse())
+	r.Add("responseCache", filter.NewResponseCache())
 }
 
 func NewWorkflow() (wf filter.WfExecer) {
@@ -28,6 +28,9 @@ type (
 
 		handler    http.Handler
 		errHandler types.ErrorHandlerFunc
+
+		// set when one of the route's filters answers CORS preflight requests
+		preflight types.HandlerFunc
 	}
 
 	routeMeta struct {
@@ -45,6 +48,11 @@ func (r route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	r.log.Debug("started serving route")
 
+	if r.preflight != nil && isPreflight(req) {
+		r.servePreflight(w, req)
+		return
+	}
+
 	b, _ := io.ReadAll(req.Body)
 	body := string(b)
 
@@ -74,6 +82,21 @@ func (r route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	)
 }
 
+// servePreflight answers CORS preflight request using route's CORS policy
+func (r route) servePreflight(w http.ResponseWriter, req *http.Request) {
+	if m := req.Header.Get("Access-Control-Request-Method"); m != r.method {
+		r.errHandler(w, req, fmt.Errorf("invalid method %s", m))
+		return
+	}
+
+	if err := r.preflight(w, req); err != nil {
+		r.errHandler(w, req, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (r route) validate(req *http.Request) (err error) {
 	if req.Method != r.method {
 		err = fmt.Errorf("invalid method %s", req.Method)
@@ -85,3 +108,7 @@ func (r route) validate(req *http.Request) (err error) {
 func (r route) String() string {
 	return fmt.Sprintf("%s %s", r.method, r.endpoint)
 }
+
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+}
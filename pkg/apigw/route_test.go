@@ -116,3 +116,52 @@ func Test_pl(t *testing.T) {
 		})
 	}
 }
+
+func Test_routePreflight(t *testing.T) {
+	var (
+		req   = require.New(t)
+		pipe  = pipeline.NewPipeline(zap.NewNop())
+		calls = 0
+	)
+
+	pipe.Add(&pipeline.Worker{
+		Handler: func(rw http.ResponseWriter, r *http.Request) error {
+			calls++
+			return errors.New("unauthenticated")
+		},
+	})
+
+	route := &route{
+		method:     http.MethodPost,
+		log:        zap.NewNop(),
+		opts:       options.Apigw(),
+		handler:    pipe.Handler(),
+		errHandler: pipe.Error(),
+		preflight: func(rw http.ResponseWriter, r *http.Request) error {
+			rw.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+			return nil
+		},
+	}
+
+	preflight := func(method string) *httptest.ResponseRecorder {
+		var (
+			rr = httptest.NewRecorder()
+			r  = httptest.NewRequest(http.MethodOptions, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", "https://example.com")
+		r.Header.Set("Access-Control-Request-Method", method)
+		route.ServeHTTP(rr, r)
+		return rr
+	}
+
+	// preflight does not go through the route's pipeline
+	rr := preflight(http.MethodPost)
+	req.Equal(http.StatusNoContent, rr.Code)
+	req.Equal("https://example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	req.Equal(0, calls)
+
+	rr = preflight(http.MethodPut)
+	req.Empty(rr.Header().Get("Access-Control-Allow-Origin"))
+	req.Contains(rr.Body.String(), "invalid method PUT")
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
+	"sync"
 
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter/proxy"
@@ -13,6 +15,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
 	f "github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
 	st "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-chi/chi"
 	"go.uber.org/zap"
@@ -22,6 +25,10 @@ type (
 	storer interface {
 		SearchApigwRoutes(ctx context.Context, f st.ApigwRouteFilter) (st.ApigwRouteSet, st.ApigwRouteFilter, error)
 		SearchApigwFilters(ctx context.Context, f st.ApigwFilterFilter) (st.ApigwFilterSet, st.ApigwFilterFilter, error)
+		LookupApigwSecretByHandle(ctx context.Context, handle string) (*st.ApigwSecret, error)
+		LookupUserByID(ctx context.Context, id uint64) (*st.User, error)
+		LookupUserByEmail(ctx context.Context, email string) (*st.User, error)
+		SearchRoles(ctx context.Context, f st.RoleFilter) (st.RoleSet, st.RoleFilter, error)
 	}
 
 	apigw struct {
@@ -31,6 +38,10 @@ type (
 		routes []*route
 		storer storer
 		reload chan bool
+
+		// matches routes that answer CORS preflight requests
+		preflight *chi.Mux
+		mux       sync.RWMutex
 	}
 )
 
@@ -57,8 +68,18 @@ func Setup(opts *options.ApigwOpt, log *zap.Logger, storer storer) {
 }
 
 func New(opts *options.ApigwOpt, logger *zap.Logger, storer storer) *apigw {
-	reg := registry.NewRegistry()
-	reg.Preload()
+	var (
+		reg = registry.NewRegistry()
+		ss  types.SecureStorager
+	)
+
+	if c, err := secure.NewCipher(opts.SecretKey, opts.SecretKeyPrevious); err != nil {
+		logger.Error("could not initialize secure storage, secrets will not be resolved", zap.Error(err))
+	} else {
+		ss = secure.NewStorage(storer, c)
+	}
+
+	reg.Preload(ss, identityResolver{storer: storer})
 
 	return &apigw{
 		opts:   opts,
@@ -211,6 +232,10 @@ func (s *apigw) Init(ctx context.Context, route ...*route) {
 				continue
 			}
 
+			if ff.Preflight != nil {
+				r.preflight = ff.Preflight
+			}
+
 			pipe.Add(ff)
 
 			flog.Debug("registered filter")
@@ -234,6 +259,30 @@ func (s *apigw) Init(ctx context.Context, route ...*route) {
 
 		log.Debug("successfuly registered route")
 	}
+
+	preflight := chi.NewRouter()
+	for _, r := range s.routes {
+		if r.preflight != nil {
+			preflight.Handle(r.endpoint, r)
+		}
+	}
+
+	s.mux.Lock()
+	s.preflight = preflight
+	s.mux.Unlock()
+}
+
+// HandlesPreflight reports if CORS preflight request on the path
+// (relative to the API base URL) is answered by the route
+func (s *apigw) HandlesPreflight(path string) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	if s.preflight == nil {
+		return false
+	}
+
+	return s.preflight.Match(chi.NewRouteContext(), http.MethodOptions, path)
 }
 
 func (s *apigw) registerFilter(f *st.ApigwFilter, r *route) (ff *pipeline.Worker, err error) {
@@ -265,6 +314,14 @@ func (s *apigw) registerFilter(f *st.ApigwFilter, r *route) (ff *pipeline.Worker
 		Weight:  filter.FilterWeight(int(f.Weight), types.FilterKind(f.Kind)),
 	}
 
+	if w, is := handler.(types.Wrapper); is {
+		ff.Wrap = w.Wrap
+	}
+
+	if p, is := handler.(types.Preflighter); is {
+		ff.Preflight = p.Preflight
+	}
+
 	return
 }
 
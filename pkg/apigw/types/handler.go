@@ -22,6 +22,19 @@ type (
 		Meta() FilterMeta
 	}
 
+	// Wrapper is implemented by handlers that need to run around
+	// processers and postfilters, ie. to serve a stored response
+	// instead of processing the request
+	Wrapper interface {
+		Wrap(next http.Handler) http.Handler
+	}
+
+	// Preflighter is implemented by handlers that answer
+	// CORS preflight requests for the route
+	Preflighter interface {
+		Preflight(rw http.ResponseWriter, r *http.Request) error
+	}
+
 	HandlerFunc      func(rw http.ResponseWriter, r *http.Request) error
 	ErrorHandlerFunc func(rw http.ResponseWriter, r *http.Request, err error)
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 )
@@ -50,6 +51,18 @@ func (s Scp) Opts() *options.ApigwOpt {
 	return nil
 }
 
+// Identity returns caller's identity
+//
+// Identity is resolved and set to scope by one of the auth prefilters;
+// nil is returned when caller is not identified
+func (s Scp) Identity() auth.Identifiable {
+	if i, ok := s["identity"].(auth.Identifiable); ok && i.Valid() {
+		return i
+	}
+
+	return nil
+}
+
 func (s Scp) Set(k string, v interface{}) {
 	s[k] = v
 }
@@ -1,5 +1,25 @@
 package types
 
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+)
+
 type (
-	SecureStorager interface{}
+	SecureStorager interface {
+		// Resolve returns value of the referenced secret;
+		// values that do not reference a secret are returned as-is
+		Resolve(ctx context.Context, v string) (string, error)
+	}
+
+	IdentityResolver interface {
+		// ResolveIdentity returns identity of an active user
+		// with all of user's roles
+		ResolveIdentity(ctx context.Context, userID uint64) (auth.Identifiable, error)
+
+		// ResolveIdentityByEmail returns identity of an active user
+		// with the given email
+		ResolveIdentityByEmail(ctx context.Context, email string) (auth.Identifiable, error)
+	}
 )
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	st "github.com/cortezaproject/corteza-server/system/types"
 )
 
@@ -25,8 +26,19 @@ type (
 	}
 
 	MockStorer struct {
-		F func(context.Context, st.ApigwFilterFilter) (st.ApigwFilterSet, st.ApigwFilterFilter, error)
-		R func(context.Context, st.ApigwRouteFilter) (st.ApigwRouteSet, st.ApigwRouteFilter, error)
+		F     func(context.Context, st.ApigwFilterFilter) (st.ApigwFilterSet, st.ApigwFilterFilter, error)
+		R     func(context.Context, st.ApigwRouteFilter) (st.ApigwRouteSet, st.ApigwRouteFilter, error)
+		S     func(context.Context, string) (*st.ApigwSecret, error)
+		U     func(context.Context, uint64) (*st.User, error)
+		E     func(context.Context, string) (*st.User, error)
+		Roles func(context.Context, st.RoleFilter) (st.RoleSet, st.RoleFilter, error)
+	}
+
+	MockSecureStorager func(context.Context, string) (string, error)
+
+	MockIdentityResolver struct {
+		ID    func(context.Context, uint64) (auth.Identifiable, error)
+		Email func(context.Context, string) (auth.Identifiable, error)
 	}
 
 	MockRoundTripper func(*http.Request) (*http.Response, error)
@@ -59,6 +71,34 @@ func (td MockStorer) SearchApigwFilters(ctx context.Context, f st.ApigwFilterFil
 	return td.F(ctx, f)
 }
 
+func (td MockStorer) LookupApigwSecretByHandle(ctx context.Context, handle string) (*st.ApigwSecret, error) {
+	return td.S(ctx, handle)
+}
+
+func (td MockStorer) LookupUserByID(ctx context.Context, id uint64) (*st.User, error) {
+	return td.U(ctx, id)
+}
+
+func (td MockStorer) LookupUserByEmail(ctx context.Context, email string) (*st.User, error) {
+	return td.E(ctx, email)
+}
+
+func (td MockStorer) SearchRoles(ctx context.Context, f st.RoleFilter) (st.RoleSet, st.RoleFilter, error) {
+	return td.Roles(ctx, f)
+}
+
+func (ms MockSecureStorager) Resolve(ctx context.Context, v string) (string, error) {
+	return ms(ctx, v)
+}
+
+func (ir MockIdentityResolver) ResolveIdentity(ctx context.Context, userID uint64) (auth.Identifiable, error) {
+	return ir.ID(ctx, userID)
+}
+
+func (ir MockIdentityResolver) ResolveIdentityByEmail(ctx context.Context, email string) (auth.Identifiable, error) {
+	return ir.Email(ctx, email)
+}
+
 func (h MockErrorHandler) Handler() ErrorHandlerFunc {
 	return h.Handler_
 }
@@ -1,8 +1,10 @@
 package auth
 
 import (
-	"github.com/dgrijalva/jwt-go"
+	"context"
 	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
 )
 
 type (
@@ -24,6 +26,13 @@ type (
 		HttpAuthenticator() func(http.Handler) http.Handler
 	}
 
+	// PersonalAccessTokenAuthenticator verifies personal access tokens
+	//
+	// Returns identity of the token owner and scope of the token
+	PersonalAccessTokenAuthenticator interface {
+		AuthenticatePersonalAccessToken(ctx context.Context, token string) (Identifiable, string, error)
+	}
+
 	Signer interface {
 		Sign(userID uint64, pp ...interface{}) string
 		Verify(signature string, userID uint64, pp ...interface{}) bool
@@ -8,7 +8,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cortezaproject/corteza-server/pkg/api"
+	pe "github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/jwtauth"
 	"github.com/pkg/errors"
@@ -18,6 +18,7 @@ type (
 	token struct {
 		// Expiration time in minutes
 		expiry    time.Duration
+		secret    []byte
 		tokenAuth *jwtauth.JWTAuth
 	}
 )
@@ -40,6 +41,7 @@ func JWT(secret string, expiry time.Duration) (tkn *token, err error) {
 
 	tkn = &token{
 		expiry:    expiry,
+		secret:    []byte(secret),
 		tokenAuth: jwtauth.New(jwt.SigningMethodHS512.Alg(), []byte(secret), nil),
 	}
 
@@ -47,19 +49,11 @@ func JWT(secret string, expiry time.Duration) (tkn *token, err error) {
 }
 
 func (t *token) Authenticate(token string) (jwt.MapClaims, error) {
-	dt, err := t.tokenAuth.Decode(token)
+	dt, err := t.decode(context.Background(), token)
 	if err != nil {
 		return nil, err
 	}
 
-	if dt == nil || !dt.Valid {
-		return nil, jwtauth.ErrUnauthorized
-	}
-
-	if dt.Method != jwt.SigningMethodHS512 {
-		return nil, jwtauth.ErrAlgoInvalid
-	}
-
 	if mc, is := dt.Claims.(jwt.MapClaims); is {
 		return mc, nil
 	}
@@ -68,8 +62,74 @@ func (t *token) Authenticate(token string) (jwt.MapClaims, error) {
 }
 
 // HttpVerifier returns a HTTP handler that verifies JWT and stores it into context
+//
+// Same as jwtauth.Verifier but it also accepts personal access tokens
 func (t *token) HttpVerifier() func(http.Handler) http.Handler {
-	return jwtauth.Verifier(t.tokenAuth)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				tkn *jwt.Token
+				err = jwtauth.ErrNoTokenFound
+			)
+
+			for _, fn := range []func(r *http.Request) string{jwtauth.TokenFromQuery, jwtauth.TokenFromHeader, jwtauth.TokenFromCookie} {
+				if str := fn(r); str != "" {
+					tkn, err = t.decode(r.Context(), str)
+					break
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(jwtauth.NewContext(r.Context(), tkn, err)))
+		})
+	}
+}
+
+// decodes and verifies token
+//
+// Only tokens signed with HMAC (JWT secret) are accepted; ID tokens
+// signed with keys from DefaultKeySet are meant for clients and
+// can not be used for API authentication
+//
+// Personal access tokens are verified with DefaultPersonalAccessTokenAuthenticator.
+//
+// Token is returned together with the error (same as jwtauth.Verifier)
+// so that HttpAuthenticator can respond with an error instead of
+// handling the request as anonymous
+func (t *token) decode(ctx context.Context, str string) (*jwt.Token, error) {
+	if IsPersonalAccessToken(str) {
+		return decodePersonalAccessToken(ctx, str)
+	}
+
+	dt, err := jwt.Parse(str, func(dt *jwt.Token) (interface{}, error) {
+		if dt.Method != jwt.SigningMethodHS512 {
+			return nil, jwtauth.ErrAlgoInvalid
+		}
+
+		return t.secret, nil
+	})
+
+	if dt == nil {
+		dt = invalidToken(str)
+	}
+
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok && verr.Errors&jwt.ValidationErrorExpired > 0 {
+			return dt, jwtauth.ErrExpired
+		}
+
+		return dt, err
+	}
+
+	if !dt.Valid {
+		return dt, jwtauth.ErrUnauthorized
+	}
+
+	return dt, nil
+}
+
+// invalidToken wraps token string that could not be decoded
+func invalidToken(str string) *jwt.Token {
+	return &jwt.Token{Raw: str, Claims: jwt.MapClaims{}}
 }
 
 func (t *token) Encode(i Identifiable, scope ...string) string {
@@ -88,23 +148,27 @@ func (t *token) Encode(i Identifiable, scope ...string) string {
 }
 
 func (t *token) encode(i Identifiable, clientID uint64, scope ...string) string {
-
-	roles := ""
-	for _, r := range i.Roles() {
-		roles += fmt.Sprintf(" %d", r)
-	}
-
 	_, tkn, _ := t.tokenAuth.Encode(jwt.MapClaims{
 		"sub":   i.String(),
 		"exp":   time.Now().Add(t.expiry).Unix(),
 		"aud":   fmt.Sprintf("%d", clientID),
 		"scope": strings.Join(scope, " "),
-		"roles": strings.TrimSpace(roles),
+		"roles": encodeRoles(i.Roles()...),
 	})
 
 	return tkn
 }
 
+// encodes roles into space delimited list for the roles claim
+func encodeRoles(rr ...uint64) string {
+	roles := ""
+	for _, r := range rr {
+		roles += fmt.Sprintf(" %d", r)
+	}
+
+	return strings.TrimSpace(roles)
+}
+
 // HttpAuthenticator converts JWT claims into identity and stores it into context
 func (t *token) HttpAuthenticator() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -117,7 +181,11 @@ func (t *token) HttpAuthenticator() func(http.Handler) http.Handler {
 			if tkn != nil {
 				if err != nil {
 					// But if token is present, the shouldn't be an error
-					api.Send(w, r, err)
+					if !pe.IsKind(err, pe.KindUnauthorized) {
+						err = pe.Unauthorized("%s", err.Error())
+					}
+
+					pe.ProperlyServeHTTP(w, r, err, false)
 					return
 				}
 
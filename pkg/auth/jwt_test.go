@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpAuthenticator(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	defer func(a PersonalAccessTokenAuthenticator) { DefaultPersonalAccessTokenAuthenticator = a }(DefaultPersonalAccessTokenAuthenticator)
+	DefaultPersonalAccessTokenAuthenticator = nil
+
+	tkn, err := JWT("secret", time.Minute)
+	req.NoError(err)
+
+	exec := func(str string) (int, uint64) {
+		var (
+			rr       = httptest.NewRecorder()
+			r        = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			identity uint64
+		)
+
+		r.Header.Set("Authorization", "Bearer "+str)
+
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity = GetIdentityFromContext(r.Context()).Identity()
+		})
+
+		tkn.HttpVerifier()(tkn.HttpAuthenticator()(next)).ServeHTTP(rr, r)
+		return rr.Code, identity
+	}
+
+	code, identity := exec(tkn.Encode(Authenticated(42)))
+	req.Equal(http.StatusOK, code)
+	req.Equal(uint64(42), identity)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{"sub": "42", "exp": time.Now().Add(-time.Minute).Unix()}).SignedString([]byte("secret"))
+	req.NoError(err)
+
+	// expired, badly signed, malformed and personal access tokens that
+	// can not be verified are rejected instead of handled as anonymous
+	for _, str := range []string{expired, expired + "x", "not-a-token", PersonalAccessTokenPrefix + "foo"} {
+		code, _ = exec(str)
+		req.Equal(http.StatusUnauthorized, code, str)
+	}
+}
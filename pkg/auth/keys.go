@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type (
+	// SigningKey is an asymmetric key used for signing JWTs
+	SigningKey struct {
+		// Key ID (JWK thumbprint, RFC 7638)
+		ID     string
+		Method jwt.SigningMethod
+		Key    crypto.Signer
+	}
+
+	// KeySet holds all signing keys
+	//
+	// First key is used for signing new tokens, all keys are used for
+	// verification and published as JWKS. To rotate keys, add a new key
+	// in front and remove the old one after all tokens signed with it expire.
+	KeySet struct {
+		keys []*SigningKey
+
+		// keys were generated on startup and are not persisted
+		ephemeral bool
+	}
+
+	// JWK is a public key in JSON Web Key format (RFC 7517)
+	JWK struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+
+		// RSA keys
+		N string `json:"n,omitempty"`
+		E string `json:"e,omitempty"`
+
+		// EC keys
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	// JWKS is a set of public keys in JSON Web Key Set format
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+const (
+	generatedKeyBits = 2048
+)
+
+var (
+	DefaultKeySet *KeySet
+)
+
+// SetupDefaultKeySet loads signing keys from the given PEM files
+//
+// When no files are given, key is generated.
+func SetupDefaultKeySet(paths ...string) (err error) {
+	if len(paths) == 0 {
+		DefaultKeySet, err = GenerateKeySet()
+	} else {
+		DefaultKeySet, err = LoadKeySet(paths...)
+	}
+
+	return
+}
+
+// LoadKeySet loads PEM encoded RSA or EC (P-256) private keys from files
+func LoadKeySet(paths ...string) (ks *KeySet, err error) {
+	var (
+		raw []byte
+		k   *SigningKey
+	)
+
+	ks = &KeySet{}
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		if raw, err = ioutil.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("could not read signing key: %w", err)
+		}
+
+		if k, err = ParseSigningKey(raw); err != nil {
+			return nil, fmt.Errorf("could not parse signing key %s: %w", path, err)
+		}
+
+		ks.keys = append(ks.keys, k)
+	}
+
+	if len(ks.keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+
+	return ks, nil
+}
+
+// GenerateKeySet generates key set with one RSA key
+func GenerateKeySet() (*KeySet, error) {
+	pk, err := rsa.GenerateKey(rand.Reader, generatedKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate signing key: %w", err)
+	}
+
+	k, err := NewSigningKey(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeySet{keys: []*SigningKey{k}, ephemeral: true}, nil
+}
+
+// ParseSigningKey parses PEM encoded private key (PKCS #1, PKCS #8 or SEC 1)
+func ParseSigningKey(raw []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM data")
+	}
+
+	var (
+		pk  interface{}
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		pk, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		pk, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		pk, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSigningKey(pk)
+}
+
+// NewSigningKey wraps RSA or EC (P-256) private key
+func NewSigningKey(pk interface{}) (k *SigningKey, err error) {
+	k = &SigningKey{}
+
+	switch pk := pk.(type) {
+	case *rsa.PrivateKey:
+		if pk.N.BitLen() < generatedKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits long", generatedKeyBits)
+		}
+
+		k.Key, k.Method = pk, jwt.SigningMethodRS256
+
+	case *ecdsa.PrivateKey:
+		if pk.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("only P-256 curve is supported for EC keys")
+		}
+
+		k.Key, k.Method = pk, jwt.SigningMethodES256
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pk)
+	}
+
+	if k.ID, err = k.thumbprint(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Sign encodes and signs claims
+func (k *SigningKey) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.Method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.Key)
+}
+
+// JWK returns public key in JSON Web Key format
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Use: "sig", Alg: k.Method.Alg(), Kid: k.ID}
+
+	switch pub := k.Key.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = b64(pub.N.Bytes())
+		jwk.E = b64(big.NewInt(int64(pub.E)).Bytes())
+
+	case *ecdsa.PublicKey:
+		// coordinates are always padded to the curve size
+		size := (pub.Curve.Params().BitSize + 7) / 8
+
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = b64(pad(pub.X.Bytes(), size))
+		jwk.Y = b64(pad(pub.Y.Bytes(), size))
+	}
+
+	return jwk
+}
+
+// thumbprint calculates JWK thumbprint (RFC 7638)
+//
+// Only required members, ordered lexicographically, are used
+func (k *SigningKey) thumbprint() (string, error) {
+	var (
+		jwk = k.JWK()
+		m   interface{}
+	)
+
+	switch jwk.Kty {
+	case "RSA":
+		m = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		m = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	}
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return b64(sum[:]), nil
+}
+
+// Signing returns key that is used for signing new tokens
+func (ks *KeySet) Signing() *SigningKey {
+	if ks == nil || len(ks.keys) == 0 {
+		return nil
+	}
+
+	return ks.keys[0]
+}
+
+// Lookup returns key by its ID
+func (ks *KeySet) Lookup(kid string) *SigningKey {
+	if ks == nil {
+		return nil
+	}
+
+	for _, k := range ks.keys {
+		if k.ID == kid {
+			return k
+		}
+	}
+
+	return nil
+}
+
+// Ephemeral returns true when keys were generated on startup
+//
+// Such keys change with every restart and are not shared between nodes
+func (ks *KeySet) Ephemeral() bool {
+	return ks != nil && ks.ephemeral
+}
+
+// JWKS returns all public keys
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0)}
+	if ks == nil {
+		return set
+	}
+
+	for _, k := range ks.keys {
+		set.Keys = append(set.Keys, k.JWK())
+	}
+
+	return set
+}
+
+// Algorithms returns list of signing algorithms used by the keys
+func (ks *KeySet) Algorithms() (aa []string) {
+	if ks == nil {
+		return
+	}
+
+	for _, k := range ks.keys {
+		var has bool
+		for _, a := range aa {
+			has = has || a == k.Method.Alg()
+		}
+
+		if !has {
+			aa = append(aa, k.Method.Alg())
+		}
+	}
+
+	return
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func pad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	return append(make([]byte, size-len(b)), b...)
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	dir, err := ioutil.TempDir("", "keys")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	req.NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	req.NoError(err)
+
+	ecPath := filepath.Join(dir, "ec.pem")
+	req.NoError(ioutil.WriteFile(ecPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	gen, err := GenerateKeySet()
+	req.NoError(err)
+	req.True(gen.Ephemeral())
+
+	rsaPath := filepath.Join(dir, "rsa.pem")
+	req.NoError(ioutil.WriteFile(rsaPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(gen.Signing().Key.(*rsa.PrivateKey)),
+	}), 0600))
+
+	ks, err := LoadKeySet(ecPath, " "+rsaPath)
+	req.NoError(err)
+	req.False(ks.Ephemeral())
+	req.Equal(jwt.SigningMethodES256, ks.Signing().Method)
+	req.Equal([]string{"ES256", "RS256"}, ks.Algorithms())
+
+	// key IDs are stable
+	req.Equal(gen.Signing().ID, ks.Lookup(gen.Signing().ID).ID)
+
+	jwks := ks.JWKS()
+	req.Len(jwks.Keys, 2)
+	req.Equal("EC", jwks.Keys[0].Kty)
+	req.Equal("P-256", jwks.Keys[0].Crv)
+	req.Len(jwks.Keys[0].X, 43)
+	req.Equal("RSA", jwks.Keys[1].Kty)
+	req.Equal("AQAB", jwks.Keys[1].E)
+
+	_, err = LoadKeySet(filepath.Join(dir, "missing.pem"))
+	req.Error(err)
+}
+
+func TestTokenDecode(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	defer func(ks *KeySet) { DefaultKeySet = ks }(DefaultKeySet)
+
+	tkn, err := JWT("secret", time.Minute)
+	req.NoError(err)
+
+	// signed with JWT secret
+	claims, err := tkn.Authenticate(tkn.Encode(Authenticated(42)))
+	req.NoError(err)
+	req.Equal("42", claims["sub"])
+
+	var (
+		ks, _  = GenerateKeySet()
+		signed = func() string {
+			str, err := ks.Signing().Sign(jwt.MapClaims{"sub": "42", "exp": time.Now().Add(time.Minute).Unix()})
+			req.NoError(err)
+			return str
+		}
+	)
+
+	// ID tokens (signed with one of the signing keys)
+	// can not be used for authentication
+	DefaultKeySet = ks
+	_, err = tkn.Authenticate(signed())
+	req.Error(err)
+
+	// other HMAC algorithms are not accepted
+	str, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "42"}).SignedString([]byte("secret"))
+	req.NoError(err)
+	_, err = tkn.Authenticate(str)
+	req.Error(err)
+}
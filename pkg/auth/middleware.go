@@ -11,6 +11,26 @@ func MiddlewareValidOnly(next http.Handler) http.Handler {
 }
 
 func AccessTokenCheck(scope ...string) func(http.Handler) http.Handler {
+	return accessTokenCheck(func(claim interface{}) bool {
+		for _, s := range scope {
+			if !CheckScope(claim, s) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// ComponentAccessTokenCheck allows access with "api" scope (all components)
+// or with component specific scope ("api:system", "api:compose"...)
+func ComponentAccessTokenCheck(component string) func(http.Handler) http.Handler {
+	return accessTokenCheck(func(claim interface{}) bool {
+		return CheckComponentScope(claim, component)
+	})
+}
+
+func accessTokenCheck(check func(claim interface{}) bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var ctx = r.Context()
@@ -20,11 +40,9 @@ func AccessTokenCheck(scope ...string) func(http.Handler) http.Handler {
 				return
 			}
 
-			for _, s := range scope {
-				if !CheckScope(ctx.Value(scopeCtxKey{}), s) {
-					errors.ProperlyServeHTTP(w, r, ErrUnauthorizedScope(), false)
-					return
-				}
+			if !check(ctx.Value(scopeCtxKey{})) {
+				errors.ProperlyServeHTTP(w, r, ErrUnauthorizedScope(), false)
+				return
 			}
 
 			next.ServeHTTP(w, r)
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// PersonalAccessTokenPrefix helps distinguishing personal access tokens
+	// from JWTs (and makes them easier to find when leaked)
+	PersonalAccessTokenPrefix = "cpat_"
+)
+
+var (
+	DefaultPersonalAccessTokenAuthenticator PersonalAccessTokenAuthenticator
+)
+
+// IsPersonalAccessToken checks if token looks like personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// decodes personal access token into jwt.Token
+//
+// Token is not a JWT but it is wrapped into one so that the rest of the
+// auth middleware can handle it the same way as any other access token
+func decodePersonalAccessToken(ctx context.Context, str string) (*jwt.Token, error) {
+	if DefaultPersonalAccessTokenAuthenticator == nil {
+		return invalidToken(str), ErrUnauthorized()
+	}
+
+	i, scope, err := DefaultPersonalAccessTokenAuthenticator.AuthenticatePersonalAccessToken(ctx, str)
+	if err != nil {
+		return invalidToken(str), err
+	}
+
+	return &jwt.Token{
+		Raw:   str,
+		Valid: true,
+		Claims: jwt.MapClaims{
+			"sub":   i.String(),
+			"aud":   "0",
+			"scope": scope,
+			"roles": encodeRoles(i.Roles()...),
+		},
+	}, nil
+}
@@ -10,6 +10,9 @@ type (
 
 const (
 	scopeDelimiter = " "
+
+	apiScope          = "api"
+	apiComponentScope = apiScope + ":"
 )
 
 // Checks if required scope is in claim
@@ -26,3 +29,21 @@ func CheckScope(claim interface{}, req string) bool {
 		scopeDelimiter+strings.TrimSpace(req)+scopeDelimiter,
 	)
 }
+
+// CheckComponentScope checks if claim allows access to component's API
+//
+// Access is allowed with "api" or "api:<component>" scope
+func CheckComponentScope(claim interface{}, component string) bool {
+	return CheckScope(claim, apiScope) || CheckScope(claim, apiComponentScope+component)
+}
+
+// ScopeAllowed checks if requested scope is allowed
+//
+// Component API scopes ("api:<component>") are allowed when "api" scope is allowed
+func ScopeAllowed(allowed, requested string) bool {
+	if strings.HasPrefix(requested, apiComponentScope) {
+		return CheckComponentScope(allowed, strings.TrimPrefix(requested, apiComponentScope))
+	}
+
+	return CheckScope(allowed, requested)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckComponentScope(t *testing.T) {
+	req := require.New(t)
+
+	req.True(CheckComponentScope("profile api", "compose"))
+	req.True(CheckComponentScope("api:compose", "compose"))
+	req.False(CheckComponentScope("api:system", "compose"))
+	req.False(CheckComponentScope("profile", "compose"))
+	req.False(CheckComponentScope(nil, "compose"))
+}
+
+func TestScopeAllowed(t *testing.T) {
+	req := require.New(t)
+
+	req.True(ScopeAllowed("profile api", "api:compose"))
+	req.True(ScopeAllowed("api:compose", "api:compose"))
+	req.False(ScopeAllowed("api:compose", "api:system"))
+	req.False(ScopeAllowed("api:compose", "api"))
+	req.True(ScopeAllowed("profile api", "profile"))
+}
@@ -1,9 +1,12 @@
 package directory
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -50,42 +53,101 @@ func Decode(ctx context.Context, p string, decoders ...Decoder) ([]resource.Inte
 		}
 		defer f.Close()
 
-		for _, d := range decoders {
-			if !d.CanDecodeFile(f) {
-				// decoder cannot handle this file
-				// Make sure to reset it, as the above check consumes the reader
-				if _, err = f.Seek(0, 0); err != nil {
-					return err
-				}
-
-				continue
-			}
+		if dnn, err = decodeFile(ctx, f, p, decoders); err != nil {
+			return err
+		}
 
-			if !d.CanDecodeExt(info.Name()) {
-				// this decoder cannot handle this extension
-				continue
-			}
+		nn = append(nn, dnn...)
+		return nil
+	})
+}
 
-			if _, err = f.Seek(0, 0); err != nil {
-				return err
-			}
+// DecodeFS runs the decoding process over all files in the given file system
+//
+// Useful for decoding uploaded archives (see archive/zip.Reader)
+func DecodeFS(ctx context.Context, fsys fs.FS, decoders ...Decoder) ([]resource.Interface, error) {
+	var (
+		// decoded nodes
+		dnn []resource.Interface
 
-			dir, fn := path.Split(p)
-			do := &envoy.DecoderOpts{
-				Name: fn,
-				Path: dir,
-			}
+		// agregated resources
+		nn = make([]resource.Interface, 0, 100)
+	)
 
-			if dnn, err = d.Decode(ctx, f, do); err != nil {
-				return fmt.Errorf("failed to decode %s: %w", info.Name(), err)
-			}
+	if len(decoders) == 0 {
+		return nil, fmt.Errorf("no decoders provided")
+	}
 
-			nn = append(nn, dnn...)
+	return nn, fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 
-			// found compatible decoder
+		if d.IsDir() {
 			return nil
 		}
 
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// Files from the file system are not necessarily seekable
+		buf, err := ioutil.ReadAll(f)
+		if err != nil {
+			return err
+		}
+
+		if dnn, err = decodeFile(ctx, bytes.NewReader(buf), p, decoders); err != nil {
+			return err
+		}
+
+		nn = append(nn, dnn...)
 		return nil
 	})
 }
+
+// decodeFile decodes the file with the first compatible decoder
+func decodeFile(ctx context.Context, f io.ReadSeeker, p string, decoders []Decoder) ([]resource.Interface, error) {
+	var (
+		err     error
+		dir, fn = path.Split(p)
+	)
+
+	for _, d := range decoders {
+		if !d.CanDecodeFile(f) {
+			// decoder cannot handle this file
+			// Make sure to reset it, as the above check consumes the reader
+			if _, err = f.Seek(0, 0); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if !d.CanDecodeExt(fn) {
+			// this decoder cannot handle this extension
+			continue
+		}
+
+		if _, err = f.Seek(0, 0); err != nil {
+			return nil, err
+		}
+
+		do := &envoy.DecoderOpts{
+			Name: fn,
+			Path: dir,
+		}
+
+		nn, err := d.Decode(ctx, f, do)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", fn, err)
+		}
+
+		// found compatible decoder
+		return nn, nil
+	}
+
+	return nil, nil
+}
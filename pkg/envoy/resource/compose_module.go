@@ -42,6 +42,11 @@ func NewComposeModule(res *types.Module, nsRef string) *ComposeModule {
 				r.RefMods = append(r.RefMods, r.AddRef(types.ModuleResourceType, refMod).Constraint(r.RefNs))
 			}
 		}
+
+		// Rollup fields aggregate records of the related module
+		if refMod := ComposeModuleRollupRef(f); refMod != "" {
+			r.RefMods = append(r.RefMods, r.AddRef(types.ModuleResourceType, refMod).Constraint(r.RefNs))
+		}
 	}
 
 	// Initial timestamps
@@ -50,6 +55,24 @@ func NewComposeModule(res *types.Module, nsRef string) *ComposeModule {
 	return r
 }
 
+// ComposeModuleRollupRef returns reference to the module
+// that rollup field aggregates records from
+func ComposeModuleRollupRef(f *types.ModuleField) string {
+	if f.Expressions.Rollup == nil {
+		return ""
+	}
+
+	if f.Expressions.Rollup.Module != "" {
+		return f.Expressions.Rollup.Module
+	}
+
+	if f.Expressions.Rollup.ModuleID > 0 {
+		return strconv.FormatUint(f.Expressions.Rollup.ModuleID, 10)
+	}
+
+	return ""
+}
+
 func (r *ComposeModule) SysID() uint64 {
 	return r.Res.ID
 }
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	automationService "github.com/cortezaproject/corteza-server/automation/service"
+	automationTypes "github.com/cortezaproject/corteza-server/automation/types"
+	cmpService "github.com/cortezaproject/corteza-server/compose/service"
+	composeTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/store"
+	systemService "github.com/cortezaproject/corteza-server/system/service"
+)
+
+// CanExport checks if the current user can read the given resource
+//
+// Resources that do not exist in the store can not be exported
+func CanExport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error) {
+	return canAccess(ctx, s, r, false)
+}
+
+// CanImport checks if the current user can import the given resource
+//
+// Existing resources are checked for update and new ones
+// for create permission; RBAC rules require grant permission
+// on the component and settings require settings management
+func CanImport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error) {
+	return canAccess(ctx, s, r, true)
+}
+
+func canAccess(ctx context.Context, s store.Storer, r resource.Interface, write bool) (bool, error) {
+	var (
+		gf  = makeGenericFilter(r.Identifiers())
+		ns  *composeTypes.Namespace
+		mod *composeTypes.Module
+		err error
+
+		cac = cmpService.DefaultAccessControl
+		sac = systemService.DefaultAccessControl
+		aac = automationService.DefaultAccessControl
+	)
+
+	// namespace resolves the referenced namespace; when it does not
+	// exist (yet) permissions are checked on any namespace
+	namespace := func(ref *resource.Ref) (*composeTypes.Namespace, error) {
+		if ref != nil {
+			ns, err := findComposeNamespaceStore(ctx, s, makeGenericFilter(ref.Identifiers))
+			if err != nil || ns != nil {
+				return ns, err
+			}
+		}
+
+		return &composeTypes.Namespace{}, nil
+	}
+
+	// records are checked on the module they belong to
+	records := func(refNs, refMod *resource.Ref) (bool, error) {
+		if ns, err = namespace(refNs); err != nil {
+			return false, err
+		}
+
+		if refMod != nil {
+			if mod, err = findComposeModuleStore(ctx, s, ns.ID, makeGenericFilter(refMod.Identifiers)); err != nil {
+				return false, err
+			}
+		}
+
+		if mod == nil {
+			if !write {
+				return false, nil
+			}
+
+			mod = &composeTypes.Module{NamespaceID: ns.ID}
+		}
+
+		rec := &composeTypes.Record{NamespaceID: mod.NamespaceID, ModuleID: mod.ID}
+		if !write {
+			return cac.CanReadRecord(ctx, rec), nil
+		}
+
+		return cac.CanCreateRecordOnModule(ctx, mod) && cac.CanUpdateRecord(ctx, rec), nil
+	}
+
+	switch rt := r.(type) {
+	case *resource.ComposeNamespace:
+		if ns, err = findComposeNamespaceStore(ctx, s, gf); err != nil || ns == nil {
+			return write && err == nil && cac.CanCreateNamespace(ctx), err
+		}
+
+		if !write {
+			return cac.CanReadNamespace(ctx, ns), nil
+		}
+
+		return cac.CanUpdateNamespace(ctx, ns), nil
+
+	case *resource.ComposeModule:
+		if ns, err = namespace(rt.RefNs); err != nil {
+			return false, err
+		}
+
+		if mod, err = findComposeModuleStore(ctx, s, ns.ID, gf); err != nil || mod == nil {
+			return write && err == nil && cac.CanCreateModuleOnNamespace(ctx, ns), err
+		}
+
+		if !write {
+			return cac.CanReadModule(ctx, mod), nil
+		}
+
+		return cac.CanUpdateModule(ctx, mod), nil
+
+	case *resource.ComposePage:
+		if ns, err = namespace(rt.RefNs); err != nil {
+			return false, err
+		}
+
+		pg, err := findComposePageStore(ctx, s, ns.ID, gf)
+		if err != nil || pg == nil {
+			return write && err == nil && cac.CanCreatePageOnNamespace(ctx, ns), err
+		}
+
+		if !write {
+			return cac.CanReadPage(ctx, pg), nil
+		}
+
+		return cac.CanUpdatePage(ctx, pg), nil
+
+	case *resource.ComposeChart:
+		if ns, err = namespace(rt.RefNs); err != nil {
+			return false, err
+		}
+
+		ch, err := findComposeChartStore(ctx, s, ns.ID, gf)
+		if err != nil || ch == nil {
+			return write && err == nil && cac.CanCreateChartOnNamespace(ctx, ns), err
+		}
+
+		if !write {
+			return cac.CanReadChart(ctx, ch), nil
+		}
+
+		return cac.CanUpdateChart(ctx, ch), nil
+
+	case *resource.ComposeRecord:
+		return records(rt.RefNs, rt.RefMod)
+
+	case *resource.ComposeRecordTemplate:
+		return records(rt.NsRef, rt.ModRef)
+
+	case *resource.Role:
+		rl, err := findRoleStore(ctx, s, gf)
+		if err != nil || rl == nil {
+			return write && err == nil && sac.CanCreateRole(ctx), err
+		}
+
+		if !write {
+			return sac.CanReadRole(ctx, rl), nil
+		}
+
+		return sac.CanUpdateRole(ctx, rl), nil
+
+	case *resource.User:
+		u, err := findUserStore(ctx, s, gf)
+		if err != nil || u == nil {
+			return write && err == nil && sac.CanCreateUser(ctx), err
+		}
+
+		if !write {
+			return sac.CanReadUser(ctx, u), nil
+		}
+
+		return sac.CanUpdateUser(ctx, u), nil
+
+	case *resource.Template:
+		t, err := findTemplateStore(ctx, s, gf)
+		if err != nil || t == nil {
+			return write && err == nil && sac.CanCreateTemplate(ctx), err
+		}
+
+		if !write {
+			return sac.CanReadTemplate(ctx, t), nil
+		}
+
+		return sac.CanUpdateTemplate(ctx, t), nil
+
+	case *resource.Application:
+		ap, err := findApplicationStore(ctx, s, gf)
+		if err != nil || ap == nil {
+			return write && err == nil && sac.CanCreateApplication(ctx), err
+		}
+
+		if !write {
+			return sac.CanReadApplication(ctx, ap), nil
+		}
+
+		return sac.CanUpdateApplication(ctx, ap), nil
+
+	case *resource.AutomationWorkflow:
+		wf, err := findAutomationWorkflowStore(ctx, s, gf)
+		if err != nil || wf == nil {
+			return write && err == nil && aac.CanCreateWorkflow(ctx), err
+		}
+
+		if !write {
+			return aac.CanReadWorkflow(ctx, wf), nil
+		}
+
+		return aac.CanUpdateWorkflow(ctx, wf), nil
+
+	case *resource.RbacRule:
+		// rules are readable only by those that can grant them
+		switch {
+		case rt.Res == nil:
+			return false, nil
+		case strings.HasPrefix(rt.Res.Resource, composeTypes.ComponentResourceType):
+			return cac.CanGrant(ctx), nil
+		case strings.HasPrefix(rt.Res.Resource, automationTypes.ComponentResourceType):
+			return aac.CanGrant(ctx), nil
+		}
+
+		return sac.CanGrant(ctx), nil
+
+	case *resource.Settings, *resource.Setting:
+		if !write {
+			return sac.CanReadSettings(ctx), nil
+		}
+
+		return sac.CanManageSettings(ctx), nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// Changes checks if the given resource already exists in the store
+// and lists fields where the resource differs from the stored one
+//
+// Only fields that are set on the given resource are compared;
+// identifiers, timestamps and userstamps are ignored.
+//
+// Compose resources are looked up inside the namespace they reference;
+// when that namespace does not exist neither can the resource.
+// Resources that can not be matched against a single stored counterpart
+// (record sets, RBAC rules, settings) are reported as not existing.
+func Changes(ctx context.Context, s store.Storer, r resource.Interface) (bool, []string, error) {
+	var (
+		gf   = makeGenericFilter(r.Identifiers())
+		nsID uint64
+		err  error
+
+		// resource and its stored counterpart
+		res, ex interface{}
+	)
+
+	// namespaceID resolves the ID of the referenced namespace
+	namespaceID := func(ref *resource.Ref) (uint64, error) {
+		if ref == nil {
+			return 0, nil
+		}
+
+		ns, err := findComposeNamespaceStore(ctx, s, makeGenericFilter(ref.Identifiers))
+		if err != nil || ns == nil {
+			return 0, err
+		}
+
+		return ns.ID, nil
+	}
+
+	// in all cases below stored counterpart is
+	// assigned only when it is found to avoid typed nils
+	switch rt := r.(type) {
+	case *resource.ComposeNamespace:
+		ns, err := findComposeNamespaceStore(ctx, s, gf)
+		if err != nil || ns == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, ns
+
+	case *resource.ComposeModule:
+		if nsID, err = namespaceID(rt.RefNs); err != nil {
+			return false, nil, err
+		}
+
+		mod, err := findComposeModuleStore(ctx, s, nsID, gf)
+		if err != nil || mod == nil {
+			return false, nil, err
+		}
+
+		if mod.Fields, err = findComposeModuleFieldsStore(ctx, s, mod); err != nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, mod
+
+	case *resource.ComposePage:
+		if nsID, err = namespaceID(rt.RefNs); err != nil {
+			return false, nil, err
+		}
+
+		pg, err := findComposePageStore(ctx, s, nsID, gf)
+		if err != nil || pg == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, pg
+
+	case *resource.ComposeChart:
+		if nsID, err = namespaceID(rt.RefNs); err != nil {
+			return false, nil, err
+		}
+
+		ch, err := findComposeChartStore(ctx, s, nsID, gf)
+		if err != nil || ch == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, ch
+
+	case *resource.Role:
+		rl, err := findRoleStore(ctx, s, gf)
+		if err != nil || rl == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, rl
+
+	case *resource.User:
+		u, err := findUserStore(ctx, s, gf)
+		if err != nil || u == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, u
+
+	case *resource.Template:
+		t, err := findTemplateStore(ctx, s, gf)
+		if err != nil || t == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, t
+
+	case *resource.Application:
+		ap, err := findApplicationStore(ctx, s, gf)
+		if err != nil || ap == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, ap
+
+	case *resource.AutomationWorkflow:
+		wf, err := findAutomationWorkflowStore(ctx, s, gf)
+		if err != nil || wf == nil {
+			return false, nil, err
+		}
+
+		res, ex = rt.Res, wf
+
+	default:
+		return false, nil, nil
+	}
+
+	ff, err := changedFields(res, ex)
+	return true, ff, err
+}
+
+// changedFields compares JSON representation of both values
+//
+// Returns sorted list of (JSON) names of the top level fields
+func changedFields(res, ex interface{}) (ff []string, err error) {
+	var (
+		rm, em map[string]interface{}
+	)
+
+	if rm, err = comparableMap(res); err != nil {
+		return
+	}
+
+	if em, err = comparableMap(ex); err != nil {
+		return
+	}
+
+	for k, v := range rm {
+		if isEmptyValue(v) || reflect.DeepEqual(v, em[k]) {
+			continue
+		}
+
+		ff = append(ff, k)
+	}
+
+	sort.Strings(ff)
+	return
+}
+
+// comparableMap encodes value into a map without identifiers, timestamps and userstamps
+func comparableMap(v interface{}) (map[string]interface{}, error) {
+	var (
+		m   = make(map[string]interface{})
+		buf []byte
+		err error
+	)
+
+	if buf, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(buf, &m); err != nil {
+		return nil, err
+	}
+
+	stripIgnoredKeys(m)
+	return m, nil
+}
+
+func stripIgnoredKeys(v interface{}) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for k, v := range c {
+			if strings.HasSuffix(k, "ID") || strings.HasSuffix(k, "At") || strings.HasSuffix(k, "By") {
+				delete(c, k)
+				continue
+			}
+
+			stripIgnoredKeys(v)
+		}
+
+	case []interface{}:
+		for _, v := range c {
+			stripIgnoredKeys(v)
+		}
+	}
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch c := v.(type) {
+	case nil:
+		return true
+	case string:
+		return c == ""
+	case float64:
+		return c == 0
+	case bool:
+		return !c
+	case map[string]interface{}:
+		return len(c) == 0
+	case []interface{}:
+		return len(c) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	composeTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFields(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now()
+
+	t.Run("unset, identifiers and timestamps are ignored", func(t *testing.T) {
+		ff, err := changedFields(
+			&types.Role{Name: "name", Handle: "handle"},
+			&types.Role{ID: 42, Name: "name", Handle: "handle", CreatedAt: now, UpdatedAt: &now},
+		)
+
+		req.NoError(err)
+		req.Empty(ff)
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		ff, err := changedFields(
+			&types.Role{Name: "renamed", Handle: "handle"},
+			&types.Role{ID: 42, Name: "name", Handle: "handle"},
+		)
+
+		req.NoError(err)
+		req.Equal([]string{"name"}, ff)
+	})
+
+	t.Run("nested identifiers are ignored", func(t *testing.T) {
+		ff, err := changedFields(
+			&composeTypes.Module{Name: "mod", Fields: composeTypes.ModuleFieldSet{{Name: "f1", Kind: "String"}}},
+			&composeTypes.Module{ID: 42, Name: "mod", Fields: composeTypes.ModuleFieldSet{{ID: 43, ModuleID: 42, Name: "f1", Kind: "String"}}},
+		)
+
+		req.NoError(err)
+		req.Empty(ff)
+
+		ff, err = changedFields(
+			&composeTypes.Module{Name: "mod", Fields: composeTypes.ModuleFieldSet{{Name: "f1", Kind: "Number"}}},
+			&composeTypes.Module{ID: 42, Name: "mod", Fields: composeTypes.ModuleFieldSet{{ID: 43, ModuleID: 42, Name: "f1", Kind: "String"}}},
+		)
+
+		req.NoError(err)
+		req.Equal([]string{"fields"}, ff)
+	})
+}
@@ -164,6 +164,21 @@ func (n *composeModule) Encode(ctx context.Context, pl *payload) (err error) {
 			f.Options["moduleID"] = strconv.FormatUint(modID, 10)
 			delete(f.Options, "module")
 		}
+
+		if refMod := resource.ComposeModuleRollupRef(f); refMod != "" {
+			modID := n.recFields[refMod]
+			if modID <= 0 {
+				ii := resource.MakeIdentifiers(refMod)
+				mod := resource.FindComposeModule(pl.state.ParentResources, ii)
+				if mod == nil || mod.ID <= 0 {
+					return composeModuleErrUnresolvedRecordField(ii)
+				}
+				modID = mod.ID
+			}
+
+			f.Expressions.Rollup.ModuleID = modID
+			f.Expressions.Rollup.Module = ""
+		}
 	}
 
 	// Evaluate the resource skip expression
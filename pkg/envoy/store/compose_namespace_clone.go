@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/rbac"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+type (
+	namespaceCloneStore struct{}
+)
+
+// NamespaceCloneStore decodes resources of the cloned namespace
+// and stores them as new (see compose service's NamespaceCloneStore)
+func NamespaceCloneStore() *namespaceCloneStore {
+	return &namespaceCloneStore{}
+}
+
+// Decode loads namespace with its modules, pages, charts, access rules
+// and, when requested, records
+func (namespaceCloneStore) Decode(ctx context.Context, s store.Storer, ns *types.Namespace, records bool) (resource.InterfaceSet, error) {
+	f := NewDecodeFilter().
+		ComposeNamespace(&types.NamespaceFilter{NamespaceID: []uint64{ns.ID}}).
+		ComposeModule(&types.ModuleFilter{}).
+		ComposePage(&types.PageFilter{}).
+		ComposeChart(&types.ChartFilter{}).
+		Rbac(&rbac.RuleFilter{})
+
+	if records {
+		mm, _, err := store.SearchComposeModules(ctx, s, types.ModuleFilter{NamespaceID: ns.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range mm {
+			f = f.ComposeRecord(&types.RecordFilter{ModuleID: m.ID})
+		}
+	}
+
+	return Decoder().Decode(ctx, s, f)
+}
+
+// Encoder returns store encoder that creates all resources anew
+//
+// IDs are generated and references are resolved from the cloned resources.
+// Cloned pages keep references to the existing workflows.
+// onRecord is called after each of the records is stored
+func (namespaceCloneStore) Encoder(s store.Storer, onRecord func()) envoy.PrepareEncoder {
+	return NewStoreEncoder(s, &EncoderConfig{
+		IgnoreStore:    true,
+		StoreWorkflows: true,
+		DeferOk:        onRecord,
+	})
+}
@@ -71,7 +71,7 @@ func (n *composePage) Prepare(ctx context.Context, pl *payload) (err error) {
 	// Get related workflows
 	var wf *atypes.Workflow
 	for _, wfr := range n.res.WfRefs {
-		if !n.cfg.IgnoreStore {
+		if !n.cfg.IgnoreStore || n.cfg.StoreWorkflows {
 			wf, err = findAutomationWorkflow(ctx, pl.s, pl.state.ParentResources, wfr.Identifiers)
 			if err != nil {
 				return err
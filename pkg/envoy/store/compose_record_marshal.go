@@ -33,15 +33,14 @@ func NewComposeRecordFromResource(res *resource.ComposeRecord, cfg *EncoderConfi
 }
 
 func (n *composeRecord) Prepare(ctx context.Context, pl *payload) (err error) {
-	// @todo add this
-	if n.cfg.IgnoreStore {
-		return fmt.Errorf("IgnoreStore not supported for compose records")
-	}
-
 	// Get related namespace
-	n.relNS, err = findComposeNamespace(ctx, pl.s, pl.state.ParentResources, n.res.RefNs.Identifiers)
-	if err != nil {
-		return err
+	if !n.cfg.IgnoreStore {
+		n.relNS, err = findComposeNamespace(ctx, pl.s, pl.state.ParentResources, n.res.RefNs.Identifiers)
+		if err != nil {
+			return err
+		}
+	} else {
+		n.relNS = resource.FindComposeNamespace(pl.state.ParentResources, n.res.RefNs.Identifiers)
 	}
 	if n.relNS == nil {
 		return resource.ComposeNamespaceErrUnresolved(n.res.RefNs.Identifiers)
@@ -49,7 +48,7 @@ func (n *composeRecord) Prepare(ctx context.Context, pl *payload) (err error) {
 
 	n.missing = true
 	n.relMod = resource.FindComposeModule(pl.state.ParentResources, n.res.RefMod.Identifiers)
-	if n.relMod == nil && n.relNS.ID > 0 {
+	if n.relMod == nil && !n.cfg.IgnoreStore && n.relNS.ID > 0 {
 		n.relMod, err = findComposeModuleStore(ctx, pl.s, n.relNS.ID, makeGenericFilter(n.res.RefMod.Identifiers))
 		if err != nil {
 			return err
@@ -102,6 +101,17 @@ func (n *composeRecord) Prepare(ctx context.Context, pl *payload) (err error) {
 		}
 	}
 
+	// All of the records are new; assign the IDs upfront so that
+	// references between records can be resolved regardless of the order
+	if n.cfg.IgnoreStore {
+		return n.res.Walker(func(r *resource.ComposeRecordRaw) error {
+			if r.ID != "" {
+				n.res.IDMap[r.ID] = NextID()
+			}
+			return nil
+		})
+	}
+
 	// Can't do anything else, since the NS doesn't yet exist
 	if n.relNS.ID == 0 {
 		return nil
@@ -443,6 +453,10 @@ func (n *composeRecord) Encode(ctx context.Context, pl *payload) (err error) {
 				return rve
 			}
 
+			if err = service.RecordUniqueConstraintCheck(ctx, pl.s, mod, rec); err != nil {
+				return err
+			}
+
 			// Create a new record
 			if !exists {
 				err = store.CreateComposeRecord(ctx, pl.s, mod, rec)
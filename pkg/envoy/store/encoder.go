@@ -41,6 +41,9 @@ type (
 
 		// IgnoreStore prevents encoders from accessing the store for initial resources
 		IgnoreStore bool
+		// StoreWorkflows allows encoders to resolve workflows from the store
+		// even when IgnoreStore is set (workflows are not namespaced)
+		StoreWorkflows bool
 	}
 
 	accessControlRBACServicer interface {
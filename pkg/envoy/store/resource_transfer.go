@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+type (
+	resourceTransferStore struct{}
+)
+
+// ResourceTransferStore decodes exported and encodes imported resources
+// (see system service's ResourceTransferStore)
+func ResourceTransferStore() *resourceTransferStore {
+	return &resourceTransferStore{}
+}
+
+// Decode loads resources selected by resource strings
+func (resourceTransferStore) Decode(ctx context.Context, s store.Storer, rr ...string) (resource.InterfaceSet, error) {
+	return Decoder().Decode(ctx, s, NewDecodeFilter().FromResource(rr...))
+}
+
+func (resourceTransferStore) Changes(ctx context.Context, s store.Storer, r resource.Interface) (bool, []string, error) {
+	return Changes(ctx, s, r)
+}
+
+func (resourceTransferStore) CanExport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error) {
+	return CanExport(ctx, s, r)
+}
+
+func (resourceTransferStore) CanImport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error) {
+	return CanImport(ctx, s, r)
+}
+
+// Encoder returns store encoder that handles existing resources with onExisting
+func (resourceTransferStore) Encoder(s store.Storer, onExisting resource.MergeAlg) envoy.PrepareEncoder {
+	return NewStoreEncoder(s, &EncoderConfig{
+		OnExisting: onExisting,
+	})
+}
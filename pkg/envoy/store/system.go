@@ -310,6 +310,12 @@ func (d *systemDecoder) decodeRbac(ctx context.Context, s store.Storer, ff []*rb
 				r.refRes = ref
 				r.refPathRes = pp
 				if err != nil {
+					if f.resourceID != nil {
+						// decoding rules for specific resources;
+						// malformed rules can not refer to any of them
+						continue
+					}
+
 					return &auxRsp{
 						err: err,
 					}
@@ -49,6 +49,22 @@ func (n *composeModule) Prepare(ctx context.Context, state *envoy.ResourceState)
 			}
 		}
 
+		if refMod := resource.ComposeModuleRollupRef(f); refMod != "" {
+			relMod := resource.FindComposeModule(state.ParentResources, resource.MakeIdentifiers(refMod))
+			if relMod == nil {
+				return resource.ComposeModuleErrUnresolved(resource.MakeIdentifiers(refMod))
+			}
+
+			// Rollup module is referenced by handle
+			rollup := *f.Expressions.Rollup
+			rollup.Module = relMod.Handle
+			if rollup.Module == "" {
+				rollup.Module = relMod.Name
+			}
+			rollup.ModuleID = 0
+			cmf.expr.Rollup = &rollup
+		}
+
 		n.fields = append(n.fields, cmf)
 	}
 
@@ -180,6 +196,33 @@ func (c *composeModuleField) MarshalYAML() (interface{}, error) {
 }
 
 func (fe composeModuleFieldExpr) MarshalYAML() (interface{}, error) {
+	var (
+		rollup, lookup interface{}
+		err            error
+	)
+
+	if fe.Rollup != nil {
+		rollup, err = makeMap(
+			"module", fe.Rollup.Module,
+			"refField", fe.Rollup.RefField,
+			"field", fe.Rollup.Field,
+			"func", fe.Rollup.Func,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fe.Lookup != nil {
+		lookup, err = makeMap(
+			"refField", fe.Lookup.RefField,
+			"field", fe.Lookup.Field,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return makeMap(
 		"valueExpr", fe.ValueExpr,
 		"sanitizers", fe.Sanitizers,
@@ -187,5 +230,7 @@ func (fe composeModuleFieldExpr) MarshalYAML() (interface{}, error) {
 		"disableDefaultValidators", fe.DisableDefaultValidators,
 		"formatters", fe.Formatters,
 		"disableDefaultFormatters", fe.DisableDefaultFormatters,
+		"rollup", rollup,
+		"lookup", lookup,
 	)
 }
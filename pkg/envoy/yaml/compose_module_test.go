@@ -50,6 +50,28 @@ func TestComposeModule_UnmarshalYAML(t *testing.T) {
 		req.Equal("bar", w.res.Fields[1].DefaultValue[1].Value)
 	})
 
+	t.Run("field with rollup and lookup", func(t *testing.T) {
+		req := require.New(t)
+
+		w, err := parseString(`{ fields: {
+  total: { kind: Number, expressions: { rollup: { module: line, refField: order, field: amount, func: sum } } },
+  company: { kind: String, expressions: { lookup: { refField: account, field: name } } } } }`)
+		req.NoError(err)
+		req.NotNil(w.res)
+
+		total := w.res.Fields.FindByName("total")
+		req.NotNil(total.Expressions.Rollup)
+		req.Equal("line", total.Expressions.Rollup.Module)
+		req.Equal("order", total.Expressions.Rollup.RefField)
+		req.Equal("amount", total.Expressions.Rollup.Field)
+		req.Equal("sum", total.Expressions.Rollup.Func)
+
+		company := w.res.Fields.FindByName("company")
+		req.NotNil(company.Expressions.Lookup)
+		req.Equal("account", company.Expressions.Lookup.RefField)
+		req.Equal("name", company.Expressions.Lookup.Field)
+	})
+
 	t.Run("doc 1", func(t *testing.T) {
 		req := require.New(t)
 
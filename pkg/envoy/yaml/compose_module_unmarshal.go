@@ -356,6 +356,34 @@ func (aux *composeModuleFieldExpr) UnmarshalYAML(n *yaml.Node) (err error) {
 			})
 		case "disableDefaultFormatters":
 			return v.Decode(&aux.DisableDefaultFormatters)
+		case "rollup":
+			aux.Rollup = &types.ModuleFieldRollup{}
+			return y7s.EachMap(v, func(k *yaml.Node, v *yaml.Node) error {
+				switch k.Value {
+				case "module":
+					return y7s.DecodeScalar(v, "rollup module", &aux.Rollup.Module)
+				case "refField":
+					return y7s.DecodeScalar(v, "rollup reference field", &aux.Rollup.RefField)
+				case "field":
+					return y7s.DecodeScalar(v, "rollup field", &aux.Rollup.Field)
+				case "func":
+					return y7s.DecodeScalar(v, "rollup function", &aux.Rollup.Func)
+				}
+
+				return nil
+			})
+		case "lookup":
+			aux.Lookup = &types.ModuleFieldLookup{}
+			return y7s.EachMap(v, func(k *yaml.Node, v *yaml.Node) error {
+				switch k.Value {
+				case "refField":
+					return y7s.DecodeScalar(v, "lookup reference field", &aux.Lookup.RefField)
+				case "field":
+					return y7s.DecodeScalar(v, "lookup field", &aux.Lookup.Field)
+				}
+
+				return nil
+			})
 		}
 
 		return nil
@@ -45,6 +45,9 @@ const (
 
 	// automation error
 	KindAutomation
+
+	// Request rate limit exceeded
+	KindTooManyRequests
 )
 
 // translates error kind into http status
@@ -68,6 +71,9 @@ func (k kind) httpStatus() int {
 	case KindUnauthenticated:
 		return http.StatusForbidden
 
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+
 	default:
 		return http.StatusInternalServerError
 	}
@@ -117,6 +123,10 @@ func Automation(m string, aa ...interface{}) *Error {
 	return err(KindAutomation, fmt.Sprintf(m, aa...))
 }
 
+func TooManyRequests(m string, aa ...interface{}) *Error {
+	return err(KindTooManyRequests, fmt.Sprintf(m, aa...))
+}
+
 func IsKind(err error, k kind) bool {
 	t, ok := err.(*Error)
 	if !ok {
@@ -179,3 +189,7 @@ func IsExternal(err error) bool {
 func IsAutomation(err error) bool {
 	return IsKind(err, KindAutomation)
 }
+
+func IsTooManyRequests(err error) bool {
+	return IsKind(err, KindTooManyRequests)
+}
@@ -0,0 +1,228 @@
+package ldap
+
+// LDAP client used for authentication and directory synchronization
+//
+// Thin wrapper around github.com/go-ldap/ldap that exposes only
+// simple bind, StartTLS and (paged) search
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const (
+	ScopeBaseObject   = goldap.ScopeBaseObject
+	ScopeSingleLevel  = goldap.ScopeSingleLevel
+	ScopeWholeSubtree = goldap.ScopeWholeSubtree
+
+	ResultSuccess            = goldap.LDAPResultSuccess
+	ResultSizeLimitExceeded  = goldap.LDAPResultSizeLimitExceeded
+	ResultNoSuchObject       = goldap.LDAPResultNoSuchObject
+	ResultInvalidCredentials = goldap.LDAPResultInvalidCredentials
+
+	defaultTimeout = 10 * time.Second
+)
+
+type (
+	// Conn is a connection to LDAP server
+	Conn struct {
+		conn *goldap.Conn
+	}
+
+	DialOptions struct {
+		// Upgrade plain connection with StartTLS
+		StartTLS bool
+
+		// TLS configuration for ldaps:// and StartTLS
+		TLSConfig *tls.Config
+
+		// Timeout for connecting and for each operation
+		Timeout time.Duration
+	}
+
+	SearchRequest struct {
+		BaseDN     string
+		Scope      int
+		Filter     string
+		Attributes []string
+
+		// Max number of entries returned, 0 for no limit
+		SizeLimit int
+
+		// When set, paged results control is used
+		// and entries are fetched in pages of this size
+		PageSize int
+	}
+
+	Entry struct {
+		DN         string
+		Attributes map[string][]string
+	}
+
+	// Error is LDAP result with non-success result code
+	Error = goldap.Error
+)
+
+// Dial connects to the LDAP server
+//
+// Supported URL schemes are ldap:// (port 389) and ldaps:// (port 636)
+func Dial(ctx context.Context, rawURL string, opt DialOptions) (c *Conn, err error) {
+	var (
+		u    *url.URL
+		conn *goldap.Conn
+	)
+
+	if opt.Timeout == 0 {
+		opt.Timeout = defaultTimeout
+	}
+
+	if opt.TLSConfig == nil {
+		opt.TLSConfig = &tls.Config{}
+	}
+
+	if u, err = url.Parse(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid LDAP URL: %w", err)
+	}
+
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q", u.Scheme)
+	}
+
+	if opt.TLSConfig.ServerName == "" {
+		opt.TLSConfig = opt.TLSConfig.Clone()
+		opt.TLSConfig.ServerName = u.Hostname()
+	}
+
+	dialer := &net.Dialer{Timeout: opt.Timeout}
+	if dl, ok := ctx.Deadline(); ok {
+		dialer.Deadline = dl
+	}
+
+	conn, err = goldap.DialURL(rawURL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(opt.TLSConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(opt.Timeout)
+
+	if opt.StartTLS && u.Scheme == "ldap" {
+		if err = conn.StartTLS(opt.TLSConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("StartTLS failed: %w", err)
+		}
+	}
+
+	return &Conn{conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	c.conn.Close()
+	return nil
+}
+
+// Bind authenticates with DN and password (simple bind)
+//
+// Returns error for empty password to prevent unauthenticated binds (RFC 4513, 5.1.2)
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return goldap.NewError(ResultInvalidCredentials, fmt.Errorf("empty password"))
+	}
+
+	return c.conn.Bind(dn, password)
+}
+
+// Search returns all entries that match the search request
+func (c *Conn) Search(req SearchRequest) (ee []*Entry, err error) {
+	var (
+		rsp *goldap.SearchResult
+	)
+
+	if req.Filter == "" {
+		req.Filter = "(objectClass=*)"
+	}
+
+	sr := goldap.NewSearchRequest(
+		req.BaseDN,
+		req.Scope,
+		goldap.NeverDerefAliases,
+		req.SizeLimit,
+		0,
+		false,
+		req.Filter,
+		req.Attributes,
+		nil,
+	)
+
+	// malformed responses are reported as errors
+	// and must not take down the caller
+	defer func() {
+		if r := recover(); r != nil {
+			ee, err = nil, fmt.Errorf("invalid LDAP search response: %v", r)
+		}
+	}()
+
+	if req.PageSize > 0 {
+		rsp, err = c.conn.SearchWithPaging(sr, uint32(req.PageSize))
+	} else {
+		rsp, err = c.conn.Search(sr)
+	}
+
+	if rsp != nil {
+		for _, e := range rsp.Entries {
+			ee = append(ee, decodeEntry(e))
+		}
+	}
+
+	return ee, err
+}
+
+func decodeEntry(e *goldap.Entry) *Entry {
+	out := &Entry{DN: e.DN, Attributes: make(map[string][]string)}
+	for _, a := range e.Attributes {
+		out.Attributes[a.Name] = a.Values
+	}
+
+	return out
+}
+
+// IsInvalidCredentials checks if error is LDAP invalid credentials result
+func IsInvalidCredentials(err error) bool {
+	return goldap.IsErrorWithCode(err, ResultInvalidCredentials)
+}
+
+// EscapeFilter escapes special characters in the filter assertion value (RFC 4515)
+func EscapeFilter(in string) string {
+	return goldap.EscapeFilter(in)
+}
+
+// Values returns values of the attribute (attribute names are case-insensitive)
+func (e *Entry) Values(attr string) []string {
+	if strings.EqualFold(attr, "dn") || strings.EqualFold(attr, "distinguishedName") {
+		return []string{e.DN}
+	}
+
+	for name, vv := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return vv
+		}
+	}
+
+	return nil
+}
+
+// Value returns first value of the attribute
+func (e *Entry) Value(attr string) string {
+	if vv := e.Values(attr); len(vv) > 0 {
+		return vv[0]
+	}
+
+	return ""
+}
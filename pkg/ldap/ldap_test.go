@@ -0,0 +1,72 @@
+package ldap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/ldap"
+	"github.com/cortezaproject/corteza-server/pkg/ldap/ldaptest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+	)
+
+	srv, err := ldaptest.NewServer(
+		ldaptest.NewEntry("dc=example,dc=com", "objectClass", "domain"),
+		ldaptest.NewEntry("cn=admin,dc=example,dc=com", "objectClass", "person", "userPassword", "secret"),
+		ldaptest.NewEntry("uid=john,ou=people,dc=example,dc=com",
+			"objectClass", "person",
+			"uid", "john",
+			"mail", "john@example.com",
+			"userPassword", "john's password",
+		),
+		ldaptest.NewEntry("uid=jane,ou=people,dc=example,dc=com",
+			"objectClass", "person",
+			"uid", "jane",
+			"mail", "jane@example.com",
+		),
+	)
+	req.NoError(err)
+	defer srv.Close()
+
+	conn, err := ldap.Dial(ctx, srv.URL(), ldap.DialOptions{})
+	req.NoError(err)
+	defer conn.Close()
+
+	req.True(ldap.IsInvalidCredentials(conn.Bind("cn=admin,dc=example,dc=com", "wrong")))
+	req.True(ldap.IsInvalidCredentials(conn.Bind("cn=admin,dc=example,dc=com", "")))
+	req.NoError(conn.Bind("cn=admin,dc=example,dc=com", "secret"))
+
+	ee, err := conn.Search(ldap.SearchRequest{
+		BaseDN:     "ou=people,dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(&(objectClass=person)(uid=j*))",
+		Attributes: []string{"uid", "mail"},
+		PageSize:   100,
+	})
+
+	req.NoError(err)
+	req.Len(ee, 2)
+	req.Equal("uid=john,ou=people,dc=example,dc=com", ee[0].DN)
+	req.Equal("john@example.com", ee[0].Value("MAIL"))
+	req.Empty(ee[0].Values("userPassword"))
+
+	ee, err = conn.Search(ldap.SearchRequest{
+		BaseDN: "dc=example,dc=com",
+		Scope:  ldap.ScopeSingleLevel,
+	})
+
+	req.NoError(err)
+	req.Len(ee, 1)
+	req.Equal("cn=admin,dc=example,dc=com", ee[0].DN)
+
+	req.NoError(conn.Bind("uid=john,ou=people,dc=example,dc=com", "john's password"))
+}
+
+func TestEscapeFilter(t *testing.T) {
+	require.Equal(t, `john\2a\28\29\5c`, ldap.EscapeFilter(`john*()\`))
+}
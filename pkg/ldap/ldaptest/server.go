@@ -0,0 +1,337 @@
+package ldaptest
+
+// In-process LDAP server for tests
+//
+// Serves entries from memory; supports simple bind (against
+// userPassword attribute), search and unbind
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/cortezaproject/corteza-server/pkg/ldap"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+type (
+	Server struct {
+		mux     sync.RWMutex
+		entries []*ldap.Entry
+
+		listener net.Listener
+		wg       sync.WaitGroup
+	}
+)
+
+// NewServer starts server on a random local port
+func NewServer(ee ...*ldap.Entry) (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: l, entries: ee}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// NewEntry is a helper for constructing entries
+//
+// Attributes are given as name-value pairs; repeated names are multi-valued attributes
+func NewEntry(dn string, aa ...string) *ldap.Entry {
+	e := &ldap.Entry{DN: dn, Attributes: make(map[string][]string)}
+	for i := 0; i+1 < len(aa); i += 2 {
+		e.Attributes[aa[i]] = append(e.Attributes[aa[i]], aa[i+1])
+	}
+
+	return e
+}
+
+// URL returns ldap:// URL of the server
+func (s *Server) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// SetEntries replaces all entries
+func (s *Server) SetEntries(ee ...*ldap.Entry) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.entries = ee
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+
+		var (
+			id, _ = msg.Children[0].Value.(int64)
+			op    = msg.Children[1]
+			rr    []*ber.Packet
+		)
+
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			rr = append(rr, s.bind(op))
+
+		case goldap.ApplicationSearchRequest:
+			rr = s.search(op)
+
+		case goldap.ApplicationUnbindRequest:
+			return
+
+		default:
+			rr = append(rr, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultProtocolError, "operation not supported"))
+		}
+
+		for _, rsp := range rr {
+			out := ber.NewSequence("LDAP message")
+			out.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "message ID"))
+			out.AppendChild(rsp)
+
+			if _, err = conn.Write(out.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) *ber.Packet {
+	var (
+		dn       = str(op.Children[1])
+		password = str(op.Children[2])
+	)
+
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	for _, e := range s.entries {
+		if !strings.EqualFold(e.DN, dn) {
+			continue
+		}
+
+		for _, p := range e.Values("userPassword") {
+			if p == password && password != "" {
+				return result(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+			}
+		}
+	}
+
+	return result(goldap.ApplicationBindResponse, goldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *Server) search(op *ber.Packet) (rr []*ber.Packet) {
+	var (
+		base         = strings.ToLower(str(op.Children[0]))
+		scope, _     = op.Children[1].Value.(int64)
+		sizeLimit, _ = op.Children[3].Value.(int64)
+		filter       = op.Children[6]
+		aa           []string
+	)
+
+	for _, a := range op.Children[7].Children {
+		aa = append(aa, str(a))
+	}
+
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	for _, e := range s.entries {
+		if !inScope(strings.ToLower(e.DN), base, int(scope)) || !match(filter, e) {
+			continue
+		}
+
+		if sizeLimit > 0 && int64(len(rr)) >= sizeLimit {
+			return append(rr, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSizeLimitExceeded, ""))
+		}
+
+		rr = append(rr, entryPacket(e, aa))
+	}
+
+	return append(rr, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess, ""))
+}
+
+func inScope(dn, base string, scope int) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == base
+	case ldap.ScopeSingleLevel:
+		i := strings.IndexByte(dn, ',')
+		return i > -1 && dn[i+1:] == base
+	default:
+		return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+// match evaluates encoded search filter (RFC 4511, 4.5.1.7) on the entry
+//
+// Extensible match is not supported
+func match(f *ber.Packet, e *ldap.Entry) bool {
+	switch f.Tag {
+	case goldap.FilterAnd:
+		for _, c := range f.Children {
+			if !match(c, e) {
+				return false
+			}
+		}
+
+		return true
+
+	case goldap.FilterOr:
+		for _, c := range f.Children {
+			if match(c, e) {
+				return true
+			}
+		}
+
+		return false
+
+	case goldap.FilterNot:
+		return len(f.Children) == 1 && !match(f.Children[0], e)
+
+	case goldap.FilterPresent:
+		return len(e.Values(str(f))) > 0
+
+	case goldap.FilterEqualityMatch, goldap.FilterApproxMatch, goldap.FilterGreaterOrEqual, goldap.FilterLessOrEqual:
+		value := strings.ToLower(str(f.Children[1]))
+		for _, v := range e.Values(str(f.Children[0])) {
+			v = strings.ToLower(v)
+
+			switch {
+			case f.Tag == goldap.FilterGreaterOrEqual && v >= value,
+				f.Tag == goldap.FilterLessOrEqual && v <= value,
+				f.Tag != goldap.FilterGreaterOrEqual && f.Tag != goldap.FilterLessOrEqual && v == value:
+				return true
+			}
+		}
+
+		return false
+
+	case goldap.FilterSubstrings:
+		for _, v := range e.Values(str(f.Children[0])) {
+			if matchSubstrings(strings.ToLower(v), f.Children[1].Children) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+func matchSubstrings(v string, ss []*ber.Packet) bool {
+	for _, s := range ss {
+		sub := strings.ToLower(str(s))
+
+		switch s.Tag {
+		case goldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(v, sub) {
+				return false
+			}
+
+			v = v[len(sub):]
+
+		case goldap.FilterSubstringsAny:
+			i := strings.Index(v, sub)
+			if i < 0 {
+				return false
+			}
+
+			v = v[i+len(sub):]
+
+		case goldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(v, sub) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func entryPacket(e *ldap.Entry, aa []string) *ber.Packet {
+	var (
+		rsp   = ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "search result entry")
+		attrs = ber.NewSequence("attributes")
+	)
+
+	for name, vv := range e.Attributes {
+		if strings.EqualFold(name, "userPassword") || !requested(name, aa) {
+			continue
+		}
+
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "values")
+		for _, v := range vv {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+
+		attr := ber.NewSequence("attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+
+	rsp.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "DN"))
+	rsp.AppendChild(attrs)
+	return rsp
+}
+
+func requested(name string, aa []string) bool {
+	if len(aa) == 0 {
+		return true
+	}
+
+	for _, a := range aa {
+		if a == "*" || strings.EqualFold(a, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func result(tag ber.Tag, code int, msg string) *ber.Packet {
+	rsp := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "result")
+	rsp.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "result code"))
+	rsp.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matched DN"))
+	rsp.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, msg, "diagnostic message"))
+	return rsp
+}
+
+// str returns value of the string or (context specific) primitive packet
+func str(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+
+	return p.Data.String()
+}
@@ -10,6 +10,7 @@ const (
 	ConsumerRedis    ConsumerType = "redis"
 	ConsumerStore    ConsumerType = "store"
 	ConsumerEventbus ConsumerType = "eventbus"
+	ConsumerAmqp     ConsumerType = "amqp"
 )
 
 type (
@@ -27,6 +28,12 @@ type (
 	Storer interface {
 		SetStore(QueueStorer)
 	}
+
+	// Receiver is implemented by consumers that also
+	// receive messages from the external broker
+	Receiver interface {
+		Receive(ctx context.Context) error
+	}
 )
 
 func ConsumerTypes() []ConsumerType {
@@ -35,5 +42,6 @@ func ConsumerTypes() []ConsumerType {
 		ConsumerEventbus,
 		ConsumerRedis,
 		ConsumerStore,
+		ConsumerAmqp,
 	}
 }
@@ -13,12 +13,18 @@ type (
 		Payload   []byte     `json:"payload"`
 		Created   *time.Time `json:"created"`
 		Processed *time.Time `json:"processed"`
+
+		// Dead-letter queue info; set when message could not be delivered
+		Failed   *time.Time `json:"failed,omitempty"`
+		Attempts int        `json:"attempts,omitempty"`
+		Error    string     `json:"error,omitempty"`
 	}
 
 	QueueMessageFilter struct {
 		Queue string
 
 		Processed filter.State `json:"processed"`
+		Failed    filter.State `json:"failed"`
 
 		filter.Sorting
 		filter.Paging
@@ -3,6 +3,7 @@ package messagebus
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -20,6 +21,13 @@ type (
 		logger *zap.Logger
 		mutex  sync.Mutex
 		queues QueueSet
+		storer QueueStorer
+
+		// resolves secret references in consumer settings
+		secure SecureStorager
+
+		// stops receivers of the current set of queues
+		stopReceivers context.CancelFunc
 
 		in     chan message
 		quit   chan bool
@@ -90,6 +98,7 @@ func (mb *messageBus) Listen(ctx context.Context) {
 
 				if err != nil {
 					log.Warn("could not add message to queue", zap.Error(err))
+					q.retries.add(ctx, t.p, err)
 					break
 				}
 
@@ -153,12 +162,37 @@ func (mb *messageBus) Push(q string, p []byte) {
 	mb.in <- message{p: p, q: q}
 }
 
+// Replay writes the message from the dead-letter queue to the queue consumer
+func (mb *messageBus) Replay(ctx context.Context, m *QueueMessage) error {
+	if !mb.opts.Enabled {
+		return fmt.Errorf("messagebus disabled")
+	}
+
+	q := mb.queue(m.Queue)
+	if q == nil {
+		return fmt.Errorf("queue %s not found", m.Queue)
+	}
+
+	return q.consumer.Write(ctx, m.Payload)
+}
+
+// SetSecureStorage sets storage that consumers use to resolve secret references
+func (mb *messageBus) SetSecureStorage(ss SecureStorager) {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+
+	mb.secure = ss
+}
+
 func (mb *messageBus) Register(ctx context.Context, qs *QueueSettings, consumer Consumer) {
 	// associate consumer with the queue
-	mb.queues[qs.Queue] = &Queue{
+	q := &Queue{
 		settings: *qs,
 		consumer: consumer,
 	}
+
+	q.retries = newRetryScheduler(mb, q)
+	mb.queues[qs.Queue] = q
 }
 
 func (mb *messageBus) queue(q string) *Queue {
@@ -179,6 +213,22 @@ func (mb *messageBus) initQueues(ctx context.Context, storer QueueStorer) error
 	mb.mutex.Lock()
 	defer mb.mutex.Unlock()
 
+	mb.storer = storer
+
+	// stop receiving and release connections of the previous queues
+	if mb.stopReceivers != nil {
+		mb.stopReceivers()
+	}
+
+	for _, q := range mb.queues {
+		if c, is := q.consumer.(io.Closer); is {
+			_ = c.Close()
+		}
+	}
+
+	var rctx context.Context
+	rctx, mb.stopReceivers = context.WithCancel(ctx)
+
 	// empty first
 	mb.queues = make(QueueSet)
 
@@ -197,6 +247,11 @@ func (mb *messageBus) initQueues(ctx context.Context, storer QueueStorer) error
 		}
 
 		mb.Register(ctx, qs, c)
+		go mb.queues[qs.Queue].retries.run(rctx)
+
+		if r, is := c.(Receiver); is && qs.CanDispatch() {
+			go mb.receive(rctx, qs.Queue, r)
+		}
 	}
 
 	return nil
@@ -215,6 +270,10 @@ func (mb *messageBus) initConsumer(ctx context.Context, settings QueueSettings)
 		consumer = NewStoreConsumer(settings)
 		return
 
+	case string(ConsumerAmqp):
+		consumer, err = NewAmqpConsumer(settings, amqpDial, mb.secure)
+		return
+
 	default:
 		err = fmt.Errorf("message queue consumer %s not implemented", handle)
 		return
@@ -12,6 +12,9 @@ type (
 		// settings are used in messagebus for handling
 		// throttling, polling settings
 		settings QueueSettings
+
+		// retries failed writes to the consumer
+		retries *retryScheduler
 	}
 
 	QueueSet map[string]*Queue
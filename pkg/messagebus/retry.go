@@ -0,0 +1,279 @@
+package messagebus
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRetryDelay   = time.Second
+	defaultRetryBackoff = 2.0
+
+	// upper limits for the configured retries and the delay between them
+	maxRetries    = 100
+	maxRetryDelay = time.Hour * 24
+
+	// max number of messages waiting for retry per queue;
+	// messages over the limit are dead-lettered right away
+	maxPendingRetries = 1000
+
+	// receiver is restarted after this delay when connection is lost
+	receiverRestartDelay = time.Second * 5
+)
+
+type (
+	// retryScheduler retries failed writes to the queue consumer
+	//
+	// There is one scheduler (and goroutine) per queue that
+	// waits for the earliest due retry
+	retryScheduler struct {
+		mb *messageBus
+		q  *Queue
+
+		mux     sync.Mutex
+		pending retryHeap
+		wake    chan struct{}
+	}
+
+	pendingRetry struct {
+		p   []byte
+		err error
+
+		// number of the (next) retry and when it is due
+		n   int
+		due time.Time
+	}
+
+	// retryHeap orders pending retries by due time
+	retryHeap []*pendingRetry
+)
+
+// RetryLimit returns the number of retries, capped to maxRetries
+func (m QueueSettingsMeta) RetryLimit() int {
+	if m.MaxRetries > maxRetries {
+		return maxRetries
+	}
+
+	return m.MaxRetries
+}
+
+// RetryDelayFor returns the delay before the n-th retry
+//
+// Delays grow exponentially by the backoff factor
+// and are capped to maxRetryDelay
+func (m QueueSettingsMeta) RetryDelayFor(n int) time.Duration {
+	var (
+		d = defaultRetryDelay
+		b = m.RetryBackoff
+	)
+
+	if m.RetryDelay != nil {
+		d = *m.RetryDelay
+	}
+
+	if b < 1 {
+		b = defaultRetryBackoff
+	}
+
+	if n < 1 {
+		n = 1
+	}
+
+	// compare as floats; large exponents overflow the duration
+	if f := float64(d) * math.Pow(b, float64(n-1)); f < float64(maxRetryDelay) {
+		return time.Duration(f)
+	}
+
+	return maxRetryDelay
+}
+
+func newRetryScheduler(mb *messageBus, q *Queue) *retryScheduler {
+	return &retryScheduler{
+		mb:   mb,
+		q:    q,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// add schedules retry of the failed write
+//
+// Message is dead-lettered right away when retries are
+// disabled or too many messages are already waiting
+func (rs *retryScheduler) add(ctx context.Context, p []byte, err error) {
+	rs.mux.Lock()
+	if rs.q.settings.Meta.RetryLimit() < 1 || rs.pending.Len() >= maxPendingRetries {
+		rs.mux.Unlock()
+		rs.deadLetter(ctx, &pendingRetry{p: p, err: err})
+		return
+	}
+
+	heap.Push(&rs.pending, &pendingRetry{p: p, err: err, n: 1, due: now().Add(rs.q.settings.Meta.RetryDelayFor(1))})
+	rs.mux.Unlock()
+
+	select {
+	case rs.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run retries writes when they are due until context is done
+//
+// Messages still waiting for retry are then dead-lettered
+func (rs *retryScheduler) run(ctx context.Context) {
+	var (
+		timer = time.NewTimer(time.Hour)
+		log   = rs.mb.logger.With(zap.String("queue", rs.q.settings.Queue))
+	)
+
+	defer timer.Stop()
+
+	for {
+		rs.mux.Lock()
+		wait := time.Hour
+		if rs.pending.Len() > 0 {
+			wait = rs.pending[0].due.Sub(*now())
+		}
+		rs.mux.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		timer.Reset(wait)
+
+		select {
+		case <-rs.wake:
+			continue
+
+		case <-timer.C:
+
+		case <-ctx.Done():
+			rs.mux.Lock()
+			pending := rs.pending
+			rs.pending = nil
+			rs.mux.Unlock()
+
+			for _, r := range pending {
+				// n-th retry was not made
+				r.n--
+				rs.deadLetter(context.Background(), r)
+			}
+
+			return
+		}
+
+		for _, r := range rs.due() {
+			log.Debug("retrying message delivery", zap.Int("retry", r.n), zap.Error(r.err))
+
+			if r.err = rs.q.consumer.Write(ctx, r.p); r.err == nil {
+				continue
+			}
+
+			if r.n >= rs.q.settings.Meta.RetryLimit() {
+				rs.deadLetter(ctx, r)
+				continue
+			}
+
+			r.n++
+			r.due = now().Add(rs.q.settings.Meta.RetryDelayFor(r.n))
+
+			rs.mux.Lock()
+			heap.Push(&rs.pending, r)
+			rs.mux.Unlock()
+		}
+	}
+}
+
+// due removes and returns retries that are due
+func (rs *retryScheduler) due() (rr []*pendingRetry) {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	for rs.pending.Len() > 0 && !rs.pending[0].due.After(*now()) {
+		rr = append(rr, heap.Pop(&rs.pending).(*pendingRetry))
+	}
+
+	return
+}
+
+// deadLetter stores the message that could not be delivered
+func (rs *retryScheduler) deadLetter(ctx context.Context, r *pendingRetry) {
+	log := rs.mb.logger.With(zap.String("queue", rs.q.settings.Queue))
+	log.Warn("message delivery failed, moving message to dead-letter queue", zap.Error(r.err))
+
+	// first write and all the retries made
+	if err := rs.mb.deadLetter(ctx, rs.q.settings.Queue, r.p, r.n+1, r.err); err != nil {
+		log.Error("could not deliver message", zap.Error(err))
+	}
+}
+
+func (h retryHeap) Len() int            { return len(h) }
+func (h retryHeap) Less(i, j int) bool  { return h[i].due.Before(h[j].due) }
+func (h retryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *retryHeap) Push(x interface{}) { *h = append(*h, x.(*pendingRetry)) }
+
+func (h *retryHeap) Pop() interface{} {
+	var (
+		old = *h
+		n   = len(old)
+		r   = old[n-1]
+	)
+
+	old[n-1] = nil
+	*h = old[:n-1]
+	return r
+}
+
+// deadLetter stores the undelivered message
+func (mb *messageBus) deadLetter(ctx context.Context, queue string, p []byte, attempts int, cause error) error {
+	mb.mutex.Lock()
+	storer := mb.storer
+	mb.mutex.Unlock()
+
+	if storer == nil {
+		return fmt.Errorf("could not store undelivered message: %w", cause)
+	}
+
+	return storer.CreateMessagebusQueueMessage(ctx, &QueueMessage{
+		ID:       nextID(),
+		Queue:    queue,
+		Payload:  p,
+		Created:  now(),
+		Failed:   now(),
+		Attempts: attempts,
+		Error:    cause.Error(),
+	})
+}
+
+// receive runs the consumer receiver and restarts it when it fails
+func (mb *messageBus) receive(ctx context.Context, queue string, r Receiver) {
+	log := mb.logger.With(zap.String("queue", queue))
+
+	for {
+		err := r.Receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			return
+		}
+
+		log.Warn("could not receive messages", zap.Error(err))
+
+		select {
+		case <-time.After(receiverRestartDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
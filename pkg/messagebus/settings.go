@@ -15,6 +15,21 @@ type (
 	QueueSettingsMeta struct {
 		PollDelay      *time.Duration `json:"poll_delay"`
 		DispatchEvents bool           `json:"dispatch_events"`
+
+		// MaxRetries is the number of times the delivery is retried
+		// before the message is moved to the dead-letter queue
+		//
+		// Capped to 100 retries
+		MaxRetries int `json:"max_retries"`
+
+		// RetryDelay is the delay before the first retry; every
+		// next delay is multiplied with the RetryBackoff factor
+		// up to 24 hours
+		RetryDelay   *time.Duration `json:"retry_delay"`
+		RetryBackoff float64        `json:"retry_backoff"`
+
+		// Amqp holds connection settings for the amqp consumer
+		Amqp *AmqpSettings `json:"amqp,omitempty"`
 	}
 
 	QueueSettings struct {
@@ -52,7 +67,8 @@ func (h *QueueSettingsMeta) UnmarshalJSON(s []byte) error {
 	type Alias QueueSettingsMeta
 
 	aux := &struct {
-		PollDelay string `json:"poll_delay"`
+		PollDelay  string `json:"poll_delay"`
+		RetryDelay string `json:"retry_delay"`
 		*Alias
 	}{
 		Alias: (*Alias)(h),
@@ -69,6 +85,10 @@ func (h *QueueSettingsMeta) UnmarshalJSON(s []byte) error {
 		h.PollDelay = &d
 	}
 
+	if d, err := cast.ToDurationE(aux.RetryDelay); err == nil {
+		h.RetryDelay = &d
+	}
+
 	return nil
 }
 
@@ -83,12 +103,25 @@ func (m QueueSettingsMeta) MarshalJSON() ([]byte, error) {
 		pollDelay = m.PollDelay.String()
 	}
 
+	retryDelay := ""
+	if m.RetryDelay != nil {
+		retryDelay = m.RetryDelay.String()
+	}
+
 	return json.Marshal(struct {
-		PollDelay      string `json:"poll_delay"`
-		DispatchEvents bool   `json:"dispatch_events"`
+		PollDelay      string        `json:"poll_delay"`
+		DispatchEvents bool          `json:"dispatch_events"`
+		MaxRetries     int           `json:"max_retries"`
+		RetryDelay     string        `json:"retry_delay"`
+		RetryBackoff   float64       `json:"retry_backoff"`
+		Amqp           *AmqpSettings `json:"amqp,omitempty"`
 	}{
 		PollDelay:      pollDelay,
 		DispatchEvents: m.DispatchEvents,
+		MaxRetries:     m.MaxRetries,
+		RetryDelay:     retryDelay,
+		RetryBackoff:   m.RetryBackoff,
+		Amqp:           m.Amqp,
 	})
 }
 
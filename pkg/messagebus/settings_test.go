@@ -34,6 +34,17 @@ func Test_settingsUnmarshal(t *testing.T) {
 				err:     nil,
 				expect:  QueueSettingsMeta{PollDelay: makeDelay(time.Second * 7), DispatchEvents: false},
 			},
+			{
+				name:    "settings retry and amqp",
+				payload: `{"max_retries": 3, "retry_delay": "2s", "retry_backoff": 1.5, "amqp": {"url": "amqp://localhost", "consume": "in"}}`,
+				err:     nil,
+				expect: QueueSettingsMeta{
+					MaxRetries:   3,
+					RetryDelay:   makeDelay(time.Second * 2),
+					RetryBackoff: 1.5,
+					Amqp:         &AmqpSettings{URL: "amqp://localhost", Consume: "in"},
+				},
+			},
 			{
 				name:    "settings invalid poll delay",
 				payload: `{"poll_delay": "7seconds", "dispatch_events": false}`,
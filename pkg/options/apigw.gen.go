@@ -21,6 +21,8 @@ type (
 		ProxyEnableDebugLog  bool          `env:"APIGW_PROXY_ENABLE_DEBUG_LOG"`
 		ProxyFollowRedirects bool          `env:"APIGW_PROXY_FOLLOW_REDIRECTS"`
 		ProxyOutboundTimeout time.Duration `env:"APIGW_PROXY_OUTBOUND_TIMEOUT"`
+		SecretKey            string        `env:"APIGW_SECRET_KEY"`
+		SecretKeyPrevious    string        `env:"APIGW_SECRET_KEY_PREVIOUS"`
 	}
 )
 
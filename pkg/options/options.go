@@ -25,6 +25,8 @@ type (
 		Workflow    WorkflowOpt
 		RBAC        RBACOpt
 		Locale      LocaleOpt
+		Apigw       ApigwOpt
+		Report      ReportOpt
 	}
 )
 
@@ -53,5 +55,7 @@ func Init() *Options {
 		Workflow:    *Workflow(),
 		RBAC:        *RBAC(),
 		Locale:      *Locale(),
+		Apigw:       *Apigw(),
+		Report:      *Report(),
 	}
 }
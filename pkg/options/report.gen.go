@@ -0,0 +1,35 @@
+package options
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/options/report.yaml
+
+type (
+	ReportOpt struct {
+		ExportMaxRows int `env:"REPORT_EXPORT_MAX_ROWS"`
+	}
+)
+
+// Report initializes and returns a ReportOpt with default values
+func Report() (o *ReportOpt) {
+	o = &ReportOpt{
+		ExportMaxRows: 100000,
+	}
+
+	fill(o)
+
+	// Function that allows access to custom logic inside the parent function.
+	// The custom logic in the other file should be like:
+	// func (o *Report) Defaults() {...}
+	func(o interface{}) {
+		if def, ok := o.(interface{ Defaults() }); ok {
+			def.Defaults()
+		}
+	}(o)
+
+	return
+}
@@ -0,0 +1,168 @@
+// Package pwned checks passwords against a local list of breached password hashes
+//
+// List is a text file with one uppercase hex encoded SHA-1 hash per line,
+// optionally followed by a colon and number of occurrences
+// (format used by "Have I Been Pwned" downloads):
+//
+//	000000005AD76BD555C1D6D771DE417A4B87E4B4:10
+//	00000000A8DAE4228F821FB418F59826079BF368:4
+//
+// Lines must be ordered by hash. File is not loaded into memory, only an index of
+// byte offsets for each 5 character hash prefix is kept. Lookups follow the k-anonymity
+// model: all hash suffixes for the prefix are read and compared to the password hash.
+package pwned
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	List struct {
+		f    *os.File
+		size int64
+
+		// ordered prefixes and offsets where lines with that prefix start
+		prefixes []uint32
+		offsets  []int64
+	}
+)
+
+const (
+	prefixLength = 5
+	hashLength   = sha1.Size * 2
+)
+
+// Load opens hash list and builds the prefix index
+func Load(path string) (l *List, err error) {
+	l = &List{}
+
+	if l.f, err = os.Open(path); err != nil {
+		return nil, err
+	}
+
+	if err = l.index(); err != nil {
+		_ = l.f.Close()
+		return nil, fmt.Errorf("could not index breached password hashes: %w", err)
+	}
+
+	return l, nil
+}
+
+func (l *List) index() error {
+	var (
+		r = bufio.NewReaderSize(l.f, 1<<16)
+
+		offset int64
+		line   string
+		err    error
+	)
+
+	for err == nil {
+		line, err = r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if strings.TrimSpace(line) != "" {
+			p, perr := parsePrefix(line)
+			if perr != nil {
+				return fmt.Errorf("invalid line at offset %d: %w", offset, perr)
+			}
+
+			if n := len(l.prefixes); n == 0 || l.prefixes[n-1] < p {
+				l.prefixes = append(l.prefixes, p)
+				l.offsets = append(l.offsets, offset)
+			} else if l.prefixes[n-1] > p {
+				return fmt.Errorf("hashes are not ordered (offset %d)", offset)
+			}
+		}
+
+		offset += int64(len(line))
+	}
+
+	l.size = offset
+	return nil
+}
+
+// Range returns all hash suffixes that start with the given 5 character prefix
+func (l *List) Range(prefix string) (ss []string, err error) {
+	p, err := parsePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	i := sort.Search(len(l.prefixes), func(i int) bool { return l.prefixes[i] >= p })
+	if i == len(l.prefixes) || l.prefixes[i] != p {
+		return nil, nil
+	}
+
+	var (
+		start = l.offsets[i]
+		end   = l.size
+	)
+
+	if i+1 < len(l.offsets) {
+		end = l.offsets[i+1]
+	}
+
+	buf := make([]byte, end-start)
+	if _, err = l.f.ReadAt(buf, start); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line = strings.TrimSpace(line); len(line) < hashLength {
+			continue
+		}
+
+		ss = append(ss, strings.ToUpper(line[prefixLength:hashLength]))
+	}
+
+	return ss, nil
+}
+
+// Contains returns true if SHA-1 hash of the password is on the list
+func (l *List) Contains(password string) (bool, error) {
+	var (
+		sum  = sha1.Sum([]byte(password))
+		hash = strings.ToUpper(hex.EncodeToString(sum[:]))
+	)
+
+	ss, err := l.Range(hash[:prefixLength])
+	if err != nil {
+		return false, err
+	}
+
+	for _, s := range ss {
+		if s == hash[prefixLength:] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (l *List) Close() error {
+	return l.f.Close()
+}
+
+func parsePrefix(s string) (uint32, error) {
+	if len(s) < prefixLength {
+		return 0, fmt.Errorf("hash prefix too short")
+	}
+
+	p, err := strconv.ParseUint(s[:prefixLength], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hash prefix: %w", err)
+	}
+
+	return uint32(p), nil
+}
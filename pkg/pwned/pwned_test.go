@@ -0,0 +1,56 @@
+package pwned
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeList(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "pwned")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "hashes.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestList(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		// SHA-1 of "password" and "123456"
+		path = writeList(t, ""+
+			"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n"+
+			"7C4A8D09CA3762AF61E59520943DC26494F8941B:24230577\r\n"+
+			"7C4A8FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:1\r\n",
+		)
+	)
+
+	l, err := Load(path)
+	req.NoError(err)
+	defer l.Close()
+
+	ss, err := l.Range("7C4A8")
+	req.NoError(err)
+	req.Equal([]string{"D09CA3762AF61E59520943DC26494F8941B", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"}, ss)
+
+	ss, err = l.Range("00000")
+	req.NoError(err)
+	req.Empty(ss)
+
+	for pwd, exp := range map[string]bool{"password": true, "123456": true, "correct horse battery staple": false} {
+		has, err := l.Contains(pwd)
+		req.NoError(err)
+		req.Equal(exp, has, pwd)
+	}
+}
+
+func TestLoadUnordered(t *testing.T) {
+	_, err := Load(writeList(t, "7C4A8D09CA3762AF61E59520943DC26494F8941B\n5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\n"))
+	require.Error(t, err)
+}
@@ -1,8 +1,13 @@
 package report
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/qlng"
+	"github.com/spf13/cast"
 )
 
 type (
@@ -21,15 +26,30 @@ var (
 		// - bool
 		"and": andHandler,
 		"or":  orHandler,
+		"xor": xorHandler,
 
 		// - comp.
 		"eq": makeCmpHandler(0),
+		"ne": makeNegCmpHandler(0),
 		"lt": makeCmpHandler(-1),
+		"le": makeNegCmpHandler(1),
 		"gt": makeCmpHandler(1),
+		"ge": makeNegCmpHandler(-1),
 
 		"is": existenceHandler,
 
+		// - math
+		"add":  makeMathHandler(func(a, b float64) (float64, bool) { return a + b, true }),
+		"sub":  makeMathHandler(func(a, b float64) (float64, bool) { return a - b, true }),
+		"mult": makeMathHandler(func(a, b float64) (float64, bool) { return a * b, true }),
+		"div":  makeMathHandler(func(a, b float64) (float64, bool) { return a / b, b != 0 }),
+
+		// - strings
+		"like":  makeLikeHandler(false),
+		"nlike": makeLikeHandler(true),
+
 		// generic stuff
+		"group": groupHandler,
 		"null":  nullHandler,
 		"nnull": notNullHandler,
 	}
@@ -39,14 +59,24 @@ var (
 //
 // This is a simplified bool only implementation as nothing else is needed for now.
 func (d *joinedDataset) eval(n *qlng.ASTNode, row FrameRow, cc FrameColumnSet) bool {
-	if v, ok := d.evalRec(n, true, row, cc).(*expr.Boolean); !ok {
+	return evalBool(n, row, cc)
+}
+
+// evalBool evaluates the given AST over the provided frame row and returns the bool result
+//
+// Anything but a true boolean is considered as false.
+func evalBool(n *qlng.ASTNode, row FrameRow, cc FrameColumnSet) bool {
+	if v, ok := evalValue(n, row, cc).(*expr.Boolean); !ok {
 		return false
 	} else {
 		return v.GetValue()
 	}
 }
 
-func (d *joinedDataset) evalRec(n *qlng.ASTNode, isRoot bool, row FrameRow, cc FrameColumnSet) expr.TypedValue {
+// evalValue evaluates the given AST over the provided frame row
+//
+// The AST should be validated beforehand with validateEval.
+func evalValue(n *qlng.ASTNode, row FrameRow, cc FrameColumnSet) expr.TypedValue {
 	// Leaf edge-cases
 	switch {
 	case n.Symbol != "":
@@ -58,14 +88,53 @@ func (d *joinedDataset) evalRec(n *qlng.ASTNode, isRoot bool, row FrameRow, cc F
 	// Process arguments for the op.
 	args := make([]expr.TypedValue, len(n.Args))
 	for i, a := range n.Args {
-		s := d.evalRec(a, false, row, cc)
-		args[i] = s
+		args[i] = evalValue(a, row, cc)
 	}
 
 	// Default handlers
 	return handlers[n.Ref](args...)
 }
 
+// validateEval assures that the given AST can be evaluated over the provided columns
+func validateEval(n *qlng.ASTNode, cc FrameColumnSet) error {
+	return n.Traverse(func(n *qlng.ASTNode) (bool, *qlng.ASTNode, error) {
+		switch {
+		case n.Symbol != "":
+			if cc.Find(n.Symbol) < 0 {
+				return false, n, fmt.Errorf("column %s does not exist", n.Symbol)
+			}
+		case n.Value != nil:
+		default:
+			if _, ok := handlers[n.Ref]; !ok {
+				return false, n, fmt.Errorf("unsupported expression: %s", n.Ref)
+			}
+		}
+
+		return true, n, nil
+	})
+}
+
+// evalKind determines the kind of the value the given AST evaluates to
+func evalKind(n *qlng.ASTNode, cc FrameColumnSet) string {
+	switch {
+	case n.Symbol != "":
+		return cc[cc.Find(n.Symbol)].Kind
+	case n.Value != nil:
+		return n.Value.V.Type()
+	}
+
+	switch n.Ref {
+	case "group":
+		return evalKind(n.Args[0], cc)
+	case "add", "sub", "mult", "div":
+		return "Number"
+	case "null", "nnull":
+		return "Any"
+	default:
+		return "Boolean"
+	}
+}
+
 func andHandler(aa ...expr.TypedValue) expr.TypedValue {
 	for _, a := range aa {
 		if v, ok := a.(*expr.Boolean); !ok || !v.GetValue() {
@@ -84,16 +153,97 @@ func orHandler(aa ...expr.TypedValue) expr.TypedValue {
 	return expr.Must(expr.NewBoolean(false))
 }
 
+func xorHandler(aa ...expr.TypedValue) expr.TypedValue {
+	out := false
+	for _, a := range aa {
+		if v, ok := a.(*expr.Boolean); ok && v.GetValue() {
+			out = !out
+		}
+	}
+	return expr.Must(expr.NewBoolean(out))
+}
+
 func makeCmpHandler(val int) HandlerSig {
 	return func(aa ...expr.TypedValue) expr.TypedValue {
-		a := aa[0].(expr.Comparable)
-		b := aa[1]
+		c, ok := compareValues(aa[0], aa[1])
+		return expr.Must(expr.NewBoolean(ok && c == val))
+	}
+}
 
-		c, _ := a.Compare(b)
-		return expr.Must(expr.NewBoolean(c == val))
+// makeNegCmpHandler is the negated makeCmpHandler; ne, le and ge
+func makeNegCmpHandler(val int) HandlerSig {
+	return func(aa ...expr.TypedValue) expr.TypedValue {
+		c, ok := compareValues(aa[0], aa[1])
+		return expr.Must(expr.NewBoolean(ok && c != val))
 	}
 }
 
+// compareValues compares the two values; not ok when they can't be compared
+func compareValues(a, b expr.TypedValue) (int, bool) {
+	if isNil(a) || isNil(b) {
+		return 0, false
+	}
+
+	ca, ok := a.(expr.Comparable)
+	if !ok {
+		return 0, false
+	}
+
+	c, err := ca.Compare(b)
+	return c, err == nil
+}
+
+func makeMathHandler(op func(a, b float64) (float64, bool)) HandlerSig {
+	return func(aa ...expr.TypedValue) expr.TypedValue {
+		if isNil(aa[0]) || isNil(aa[1]) {
+			return nil
+		}
+
+		a, err := cast.ToFloat64E(aa[0].Get())
+		if err != nil {
+			return nil
+		}
+		b, err := cast.ToFloat64E(aa[1].Get())
+		if err != nil {
+			return nil
+		}
+
+		v, ok := op(a, b)
+		if !ok {
+			return nil
+		}
+		return expr.Must(expr.NewFloat(v))
+	}
+}
+
+// makeLikeHandler matches the value against the SQL like pattern
+func makeLikeHandler(neg bool) HandlerSig {
+	return func(aa ...expr.TypedValue) expr.TypedValue {
+		if isNil(aa[0]) || isNil(aa[1]) {
+			return expr.Must(expr.NewBoolean(false))
+		}
+
+		var (
+			v = cast.ToString(aa[0].Get())
+			p = cast.ToString(aa[1].Get())
+		)
+
+		p = regexp.QuoteMeta(p)
+		p = strings.ReplaceAll(p, "%", ".*")
+		p = strings.ReplaceAll(p, "_", ".")
+
+		m, err := regexp.MatchString("(?s)^"+p+"$", v)
+		if err != nil {
+			return expr.Must(expr.NewBoolean(false))
+		}
+		return expr.Must(expr.NewBoolean(m != neg))
+	}
+}
+
+func groupHandler(aa ...expr.TypedValue) expr.TypedValue {
+	return aa[0]
+}
+
 func nullHandler(_ ...expr.TypedValue) expr.TypedValue {
 	return nil
 }
@@ -37,7 +37,11 @@ type (
 		Partition(partitionSize uint, partitionCol string) (bool, error)
 	}
 
-	// @todo TransformableDatasource
+	// TransformableDatasource is able to provide transformed data
+	TransformableDatasource interface {
+		Datasource
+		Transform(TransformDefinition, string) (bool, error)
+	}
 )
 
 const (
@@ -0,0 +1,454 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/spf13/cast"
+)
+
+type (
+	// MemoryLoader returns all of the rows the datasource provides
+	//
+	// Each row maps the column name to the raw value; missing values are nil.
+	MemoryLoader func(ctx context.Context) ([]map[string]interface{}, error)
+
+	// memoryDatasource filters, sorts and pages the provided rows in memory
+	//
+	// It is used by the providers that can not query their data directly,
+	// such as system resources or attached files.
+	memoryDatasource struct {
+		name   string
+		cols   FrameColumnSet
+		filter *Filter
+		loader MemoryLoader
+
+		partitioned   bool
+		partitionSize uint
+		partitionCol  string
+	}
+)
+
+// MemoryDatasource initializes a datasource over the rows the loader provides
+//
+// The cc defines all of the available columns; the load step can select
+// a subset of them.
+func MemoryDatasource(ld *LoadStepDefinition, cc FrameColumnSet, l MemoryLoader) (Datasource, error) {
+	d := &memoryDatasource{
+		name:   ld.Name,
+		cols:   cc,
+		filter: ld.Filter,
+		loader: l,
+	}
+
+	if len(ld.Columns) > 0 {
+		d.cols = make(FrameColumnSet, len(ld.Columns))
+		for i, c := range ld.Columns {
+			ci := cc.Find(c.Name)
+			if ci < 0 {
+				return nil, fmt.Errorf("column not found: %s", c.Name)
+			}
+			d.cols[i] = cc[ci]
+		}
+	}
+
+	for i, c := range d.cols {
+		if c.Caster == nil {
+			aux := *c
+			aux.Caster = MakeColumnOfKind(c.Kind).Caster
+			d.cols[i] = &aux
+		}
+	}
+
+	if d.filter != nil && d.filter.ASTNode != nil {
+		if err := validateEval(d.filter.ASTNode, d.cols); err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	return d, nil
+}
+
+func (d *memoryDatasource) Name() string {
+	return d.name
+}
+
+func (d *memoryDatasource) Describe() FrameDescriptionSet {
+	return FrameDescriptionSet{
+		&FrameDescription{
+			Source:  d.Name(),
+			Ref:     d.Name(),
+			Columns: d.cols,
+		},
+	}
+}
+
+// Partition marks the DS to partition the response over the given column
+func (d *memoryDatasource) Partition(partitionSize uint, partitionCol string) (bool, error) {
+	if d.partitioned {
+		return true, fmt.Errorf("datasource already partitioned")
+	}
+	if partitionCol == "" {
+		return false, fmt.Errorf("unable to partition: partition column not defined")
+	}
+	if d.cols.Find(partitionCol) < 0 {
+		return false, fmt.Errorf("unable to partition: column not found: %s", partitionCol)
+	}
+
+	d.partitioned = true
+	d.partitionCol = partitionCol
+	d.partitionSize = partitionSize
+	return true, nil
+}
+
+func (d *memoryDatasource) Load(ctx context.Context, dd ...*FrameDefinition) (l Loader, c Closer, err error) {
+	def := dd[0]
+
+	rr, err := d.loader(ctx)
+	if err != nil {
+		return
+	}
+
+	f := &Frame{
+		Name:    def.Name,
+		Source:  d.Name(),
+		Ref:     d.Name(),
+		Columns: d.cols,
+		Rows:    make(FrameRowSet, 0, len(rr)),
+	}
+
+	for _, r := range rr {
+		row := make(FrameRow, len(d.cols))
+		for i, c := range d.cols {
+			v, ok := r[c.Name]
+			if !ok || isNilValue(v) {
+				continue
+			}
+
+			if row[i], err = c.Caster(v); err != nil {
+				return nil, nil, fmt.Errorf("unable to cast value of column %s: %w", c.Name, err)
+			}
+		}
+
+		if d.filter != nil && d.filter.ASTNode != nil && !evalBool(d.filter.ASTNode, row, d.cols) {
+			continue
+		}
+		f.Rows = append(f.Rows, row)
+	}
+
+	if d.partitioned {
+		return memoryFrameLoader(f, def, d.partitionSize, d.partitionCol)
+	}
+	return memoryFrameLoader(f, def, 0, "")
+}
+
+// memoryFrameLoader provides the requested frame from the given in-memory frame
+//
+// Outline:
+//  . filter and sort the rows
+//  . partition the rows over the partition column (when defined)
+//  . apply the paging cursor
+func memoryFrameLoader(f *Frame, def *FrameDefinition, partitionSize uint, partitionCol string) (l Loader, c Closer, err error) {
+	// . filter and sort the rows
+	if def.Filter != nil && def.Filter.ASTNode != nil {
+		if err = validateEval(def.Filter.ASTNode, f.Columns); err != nil {
+			return nil, nil, fmt.Errorf("invalid filter: %w", err)
+		}
+
+		aux := make(FrameRowSet, 0, len(f.Rows))
+		for _, r := range f.Rows {
+			if evalBool(def.Filter.ASTNode, r, f.Columns) {
+				aux = append(aux, r)
+			}
+		}
+		f.Rows = aux
+	}
+
+	var (
+		ss  = def.Sort
+		cur *filter.PagingCursor
+	)
+	if def.Paging != nil {
+		cur = def.Paging.PageCursor
+	}
+
+	if cur != nil {
+		if ss, err = cur.Sort(ss); err != nil {
+			return
+		}
+	}
+
+	if ss, err = uniqueSort(ss, f.Columns); err != nil {
+		return
+	}
+	f.Sort = ss
+	sortRows(f, ss)
+
+	// . partition the rows over the partition column
+	//
+	// partition size of 0 doesn't limit the partitions
+	if partitionCol != "" && partitionSize > 0 {
+		pci := f.Columns.Find(partitionCol)
+		if pci < 0 {
+			return nil, nil, fmt.Errorf("partition column not found: %s", partitionCol)
+		}
+
+		var (
+			aux    = make(FrameRowSet, 0, len(f.Rows))
+			counts = make(map[string]uint)
+		)
+		for _, r := range f.Rows {
+			k := cellToString(r[pci])
+			if counts[k] >= partitionSize {
+				continue
+			}
+			counts[k]++
+			aux = append(aux, r)
+		}
+		f.Rows = aux
+	}
+
+	// . apply the paging cursor
+	start, end := 0, len(f.Rows)
+	if cur != nil {
+		start, end = cursorBounds(f, cur)
+		if cur.ROrder && def.Paging.Limit > 0 && end-start > int(def.Paging.Limit) {
+			start = end - int(def.Paging.Limit)
+		}
+	}
+
+	// requested columns
+	cols, err := frameColumns(def.Columns, f.Columns)
+	if err != nil {
+		return
+	}
+
+	var (
+		pos  = start
+		done = false
+	)
+	return func(cap int, processed bool) ([]*Frame, error) {
+		// an empty frame is provided for an empty processed response
+		if done || pos >= end && !processed {
+			return nil, nil
+		}
+
+		n := end - pos
+		if cap > 0 && cap < n {
+			n = cap
+		}
+
+		out := &Frame{
+			Name:    f.Name,
+			Source:  f.Source,
+			Ref:     f.Ref,
+			Columns: make(FrameColumnSet, len(cols)),
+			Rows:    make(FrameRowSet, n),
+			Sort:    f.Sort,
+			Filter:  def.Filter,
+		}
+
+		for i, ci := range cols {
+			out.Columns[i] = f.Columns[ci]
+		}
+		for i, r := range f.Rows[pos : pos+n] {
+			out.Rows[i] = make(FrameRow, len(cols))
+			for j, ci := range cols {
+				out.Rows[i][j] = r[ci]
+			}
+		}
+
+		if processed && cap > 0 && n > 0 {
+			hasPrev := pos > 0
+			hasNext := pos+n < len(f.Rows)
+
+			if hasPrev {
+				out.Paging = &filter.Paging{}
+				out.Paging.PrevPage = f.CollectCursorValues(f.Rows[pos], ss...)
+				out.Paging.PrevPage.ROrder = true
+				out.Paging.PrevPage.LThen = !ss.Reversed()
+			}
+
+			if hasNext {
+				if out.Paging == nil {
+					out.Paging = &filter.Paging{}
+				}
+				out.Paging.NextPage = f.CollectCursorValues(f.Rows[pos+n-1], ss...)
+				out.Paging.NextPage.LThen = ss.Reversed()
+			}
+		}
+
+		pos += n
+		done = pos >= end
+
+		return []*Frame{out}, nil
+	}, func() {}, nil
+}
+
+// uniqueSort assures the sort defines a unique order so the paging can be applied
+func uniqueSort(ss filter.SortExprSet, cc FrameColumnSet) (filter.SortExprSet, error) {
+	ss = ss.Clone()
+
+	for _, s := range ss {
+		ci := cc.Find(s.Column)
+		if ci < 0 {
+			return nil, fmt.Errorf("sort column not found: %s", s.Column)
+		}
+
+		if cc[ci].Primary || cc[ci].Unique {
+			return ss, nil
+		}
+	}
+
+	desc := ss.LastDescending()
+	for _, c := range cc {
+		if c.Primary || c.Unique {
+			return append(ss, &filter.SortExpr{Column: c.Name, Descending: desc}), nil
+		}
+	}
+
+	// no unique column; the entire row is used
+	for _, c := range cc {
+		if ss.Get(c.Name) == nil {
+			ss = append(ss, &filter.SortExpr{Column: c.Name, Descending: desc})
+		}
+	}
+
+	return ss, nil
+}
+
+func sortRows(f *Frame, ss filter.SortExprSet) {
+	sci := make([]int, len(ss))
+	for i, s := range ss {
+		sci[i] = f.Columns.Find(s.Column)
+	}
+
+	sort.SliceStable(f.Rows, func(i, j int) bool {
+		for si, s := range ss {
+			c := compareCells(f.Rows[i][sci[si]], f.Rows[j][sci[si]])
+			if c == 0 {
+				continue
+			}
+
+			if s.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+
+		return false
+	})
+}
+
+// cursorBounds returns the bounds of the sorted rows the cursor permits
+func cursorBounds(f *Frame, cur *filter.PagingCursor) (start, end int) {
+	var (
+		kk = cur.Keys()
+		vv = cur.Values()
+		ds = cur.Desc()
+
+		cci = make([]int, len(kk))
+		cvv = make([]expr.TypedValue, len(kk))
+	)
+
+	for i, k := range kk {
+		cci[i] = f.Columns.Find(k)
+		if cci[i] < 0 || vv[i] == nil {
+			continue
+		}
+
+		cvv[i], _ = f.Columns[cci[i]].Caster(vv[i])
+	}
+
+	// cmp compares the row to the cursor in the sort order
+	cmp := func(r FrameRow) int {
+		for i := range kk {
+			if cci[i] < 0 {
+				continue
+			}
+
+			c := compareCells(r[cci[i]], cvv[i])
+			if ds[i] {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+
+	end = len(f.Rows)
+	if !cur.ROrder {
+		// rows after the cursor
+		start = sort.Search(len(f.Rows), func(i int) bool { return cmp(f.Rows[i]) > 0 })
+	} else {
+		// rows before the cursor
+		end = sort.Search(len(f.Rows), func(i int) bool { return cmp(f.Rows[i]) >= 0 })
+	}
+
+	return
+}
+
+// frameColumns returns the indexes of the requested columns
+func frameColumns(req, cc FrameColumnSet) (out []int, err error) {
+	if len(req) == 0 {
+		out = make([]int, len(cc))
+		for i := range cc {
+			out[i] = i
+		}
+		return
+	}
+
+	out = make([]int, len(req))
+	for i, c := range req {
+		out[i] = cc.Find(c.Name)
+		if out[i] < 0 {
+			return nil, fmt.Errorf("column not found: %s", c.Name)
+		}
+	}
+
+	return
+}
+
+// compareCells compares the two cells; nil values come first
+func compareCells(a, b expr.TypedValue) int {
+	switch {
+	case isNil(a) && isNil(b):
+		return 0
+	case isNil(a):
+		return -1
+	case isNil(b):
+		return 1
+	}
+
+	if c, ok := compareValues(a, b); ok {
+		return c
+	}
+
+	return strings.Compare(cellToString(a), cellToString(b))
+}
+
+func cellToString(v expr.TypedValue) string {
+	if isNil(v) {
+		return ""
+	}
+
+	return cast.ToString(v.Get())
+}
+
+// isNilValue checks if the raw value is nil or a nil pointer
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
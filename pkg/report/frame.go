@@ -104,7 +104,8 @@ func MakeColumnOfKind(k string) *FrameColumn {
 			case "DateTime":
 				return expr.NewDateTime(in)
 			case "User",
-				"Record":
+				"Record",
+				"ID":
 				return expr.NewID(in)
 			case "Checkbox":
 				return expr.NewBoolean(in)
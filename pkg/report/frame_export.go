@@ -0,0 +1,270 @@
+package report
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/spf13/cast"
+)
+
+const (
+	// xlsx sheet names are limited to 31 characters
+	xlsxSheetNameLength = 31
+
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`%s</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">%s</Relationships>`
+)
+
+// ExportCSV writes the frames as CSV
+//
+// Each frame starts with the header row; when there are multiple frames,
+// they are separated with an empty line and prefixed with the frame title.
+func ExportCSV(w io.Writer, ff ...*Frame) (err error) {
+	cw := csv.NewWriter(w)
+
+	for i, f := range ff {
+		if len(ff) > 1 {
+			if i > 0 {
+				if err = cw.Write([]string{}); err != nil {
+					return
+				}
+			}
+			if err = cw.Write([]string{f.ExportTitle()}); err != nil {
+				return
+			}
+		}
+
+		if err = cw.Write(exportHeader(f)); err != nil {
+			return
+		}
+
+		err = f.WalkRows(func(_ int, r FrameRow) error {
+			return cw.Write(ExportRow(r))
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportXLSX writes the frames as an XLSX workbook with a sheet for each frame
+func ExportXLSX(w io.Writer, ff ...*Frame) (err error) {
+	var (
+		zw = zip.NewWriter(w)
+
+		overrides, sheets, rels strings.Builder
+		names                   = make(map[string]bool)
+	)
+
+	for i, f := range ff {
+		n := i + 1
+
+		name := xlsxSheetName(f.ExportTitle(), n, names)
+
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+
+		sw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", n))
+		if err != nil {
+			return err
+		}
+		if err = xlsxSheet(sw, f); err != nil {
+			return err
+		}
+	}
+
+	parts := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, rels.String())},
+	}
+
+	for _, p := range parts {
+		pw, err := zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(pw, p.body); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ExportTitle returns the title used for the exported frame
+//
+// Frames of the joined datasources are identified by the reference value.
+func (f *Frame) ExportTitle() string {
+	t := f.Name
+	if f.Ref != "" && f.Ref != f.Name {
+		t += " " + f.Ref
+	}
+	if f.RefValue != "" {
+		t += " " + f.RefValue
+	}
+
+	return t
+}
+
+func xlsxSheet(w io.Writer, f *Frame) (err error) {
+	if _, err = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return
+	}
+
+	writeRow := func(n int, cc []string, numeric []bool) error {
+		var b strings.Builder
+		fmt.Fprintf(&b, `<row r="%d">`, n)
+		for i, c := range cc {
+			ref := xlsxColumnName(i) + fmt.Sprint(n)
+			if numeric != nil && numeric[i] {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, c)
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(c))
+		}
+		b.WriteString(`</row>`)
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if err = writeRow(1, exportHeader(f), nil); err != nil {
+		return
+	}
+
+	err = f.WalkRows(func(i int, r FrameRow) error {
+		var (
+			cc      = ExportRow(r)
+			numeric = make([]bool, len(r))
+		)
+
+		for j := range cc {
+			if j < len(f.Columns) && f.Columns[j].Kind == "Number" && cc[j] != "" {
+				_, err := cast.ToFloat64E(cc[j])
+				numeric[j] = err == nil
+			}
+		}
+
+		return writeRow(i+2, cc, numeric)
+	})
+	if err != nil {
+		return
+	}
+
+	_, err = io.WriteString(w, `</sheetData></worksheet>`)
+	return
+}
+
+// xlsxSheetName returns a valid and unique sheet name
+func xlsxSheetName(name string, n int, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", n)
+	}
+	if rr := []rune(name); len(rr) > xlsxSheetNameLength {
+		name = string(rr[:xlsxSheetNameLength])
+	}
+
+	if used[strings.ToLower(name)] {
+		sfx := fmt.Sprintf(" (%d)", n)
+		rr := []rune(name)
+		if len(rr)+len(sfx) > xlsxSheetNameLength {
+			rr = rr[:xlsxSheetNameLength-len(sfx)]
+		}
+		name = string(rr) + sfx
+	}
+
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// xlsxColumnName converts the 0-based column index to the column name (A, B, ..., AA, ...)
+func xlsxColumnName(i int) (out string) {
+	for i++; i > 0; i = (i - 1) / 26 {
+		out = string(rune('A'+(i-1)%26)) + out
+	}
+	return
+}
+
+// ExportRow returns the string representation of the row's cells
+func ExportRow(r FrameRow) []string {
+	out := make([]string, len(r))
+	for i, c := range r {
+		out[i] = exportValue(c)
+	}
+	return out
+}
+
+func exportHeader(f *Frame) []string {
+	hh := make([]string, len(f.Columns))
+	for i, c := range f.Columns {
+		hh[i] = c.Label
+		if hh[i] == "" {
+			hh[i] = c.Name
+		}
+	}
+	return hh
+}
+
+// exportValue returns the string representation of the cell
+func exportValue(v expr.TypedValue) string {
+	if isNil(v) {
+		return ""
+	}
+
+	switch c := v.Get().(type) {
+	case *time.Time:
+		if c == nil {
+			return ""
+		}
+		return c.Format(time.RFC3339)
+	case time.Time:
+		return c.Format(time.RFC3339)
+	}
+
+	return cellToString(v)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -37,10 +37,10 @@ type (
 	StepDefinition    struct {
 		Kind string `json:"kind,omitempty"`
 
-		Load  *LoadStepDefinition  `json:"load,omitempty"`
-		Join  *JoinStepDefinition  `json:"join,omitempty"`
-		Group *GroupStepDefinition `json:"group,omitempty"`
-		// @todo Transform
+		Load      *LoadStepDefinition      `json:"load,omitempty"`
+		Join      *JoinStepDefinition      `json:"join,omitempty"`
+		Group     *GroupStepDefinition     `json:"group,omitempty"`
+		Transform *TransformStepDefinition `json:"transform,omitempty"`
 	}
 )
 
@@ -67,7 +67,8 @@ func Model(ctx context.Context, sources map[string]DatasourceProvider, dd ...*St
 			case d.Group != nil:
 				steps = append(steps, &stepGroup{def: d.Group})
 
-			// @todo Transform
+			case d.Transform != nil:
+				steps = append(steps, &stepTransform{def: d.Transform})
 
 			default:
 				return errors.New("malformed step definition: unsupported step kind")
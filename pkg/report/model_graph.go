@@ -131,5 +131,22 @@ func (m *model) reduceBranch(ctx context.Context, n *modelGraphNode) (out Dataso
 		return gds, nil
 	}
 
+	// try to reduce the transform step
+	if n.step.Def().Transform != nil {
+		tds, ok := o.(TransformableDatasource)
+		if !ok {
+			return n.step.Run(ctx, auxO...)
+		}
+
+		ok, err = tds.Transform(n.step.Def().Transform.TransformDefinition, n.step.Name())
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			return n.step.Run(ctx, auxO...)
+		}
+
+		return tds, nil
+	}
+
 	return n.step.Run(ctx, auxO...)
 }
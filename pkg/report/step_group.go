@@ -59,8 +59,8 @@ func (j *stepGroup) Run(ctx context.Context, dd ...Datasource) (Datasource, erro
 		return nil, fmt.Errorf("unknown group dimension: %s", j.def.Source)
 	}
 
-	return nil, nil
-	// @todo
+	// @todo in-memory grouping for datasources that can't group the data themselves
+	return nil, fmt.Errorf("unable to group datasource %s: grouping not supported", dd[0].Name())
 	// return &groupedDataset{
 	// 	def: j.def,
 	// 	ds:  dd[0],
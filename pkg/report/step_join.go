@@ -32,6 +32,9 @@ type (
 		foreignFrames [][]*Frame
 		// foreignSourceIndex maps the ds to the ds index of foreignFrames slice
 		foreignSourceIndex map[string]int
+		// nestedFrames contains the pulled frames of a joined local datasource
+		// which are not yet a part of the response
+		nestedFrames []*Frame
 	}
 
 	JoinStepDefinition struct {
@@ -57,19 +60,28 @@ func (j *stepJoin) Run(ctx context.Context, dd ...Datasource) (Datasource, error
 		return nil, fmt.Errorf("foreign join datasources not defined: %s", j.def.LocalSource)
 	}
 
-	// @todo temporarily disabled
-	for _, d := range dd {
-		if _, ok := d.(*joinedDataset); ok {
-			return nil, fmt.Errorf("unable to join a joined source: %s", d.Name())
-		}
+	// Joined datasets may only be used as the local datasource; the join is then
+	// performed over the local datasource of the initial join.
+	//
+	// @todo allow joined foreign datasources
+	if _, ok := dd[1].(*joinedDataset); ok {
+		return nil, fmt.Errorf("unable to join a joined foreign source: %s", dd[1].Name())
 	}
 
-	// @todo multiple joins
-	return &joinedDataset{
+	out := &joinedDataset{
 		def:     j.def,
 		local:   dd[0],
 		foreign: dd[1],
-	}, nil
+	}
+
+	if _, ok := dd[0].(*joinedDataset); ok {
+		dscr := out.local.Describe().FilterByRef(out.localRef())
+		if len(dscr) == 0 || dscr[0].Columns.Find(j.def.LocalColumn) < 0 {
+			return nil, fmt.Errorf("unable to join a joined source: local column %s not defined by %s", j.def.LocalColumn, out.localRef())
+		}
+	}
+
+	return out, nil
 }
 
 func (j *stepJoin) Validate() error {
@@ -322,14 +334,14 @@ func (d *joinedDataset) prepareDefinitions(dd FrameDefinitionSet, dscr FrameDesc
 		return
 	}
 
-	localDef = FrameDefinitionSet(dd).FindBySourceRef(d.Name(), d.def.LocalSource)
+	localDef = FrameDefinitionSet(dd).FindBySourceRef(d.Name(), d.localRef())
 	foreignDef = FrameDefinitionSet(dd).FindBySourceRef(d.Name(), d.def.ForeignSource)
 
 	if localDef == nil {
 		localDef = &FrameDefinition{
 			Name:   dd[0].Name,
 			Source: d.Name(),
-			Ref:    d.def.LocalSource,
+			Ref:    d.localRef(),
 			Paging: dd[0].Paging,
 			Sort:   dd[0].Sort,
 			Filter: dd[0].Filter,
@@ -350,19 +362,67 @@ func (d *joinedDataset) prepareDefinitions(dd FrameDefinitionSet, dscr FrameDesc
 
 	if len(localDef.Columns) == 0 {
 		dscr = d.local.Describe()
-		sc := dscr.FilterBySource(localDef.Ref)[0]
+		sc := dscr.FilterByRef(localDef.Ref)[0]
 		localDef.Columns = sc.Columns
 	}
 
 	if len(foreignDef.Columns) == 0 {
 		dscr = d.foreign.Describe()
-		sc := dscr.FilterBySource(foreignDef.Ref)[0]
+		sc := dscr.FilterByRef(foreignDef.Ref)[0]
 		foreignDef.Columns = sc.Columns
 	}
 
 	return
 }
 
+// localRef returns the ref of the local frames
+//
+// When joining a joined datasource, the local frames are the ones of the
+// initial local datasource.
+func (d *joinedDataset) localRef() string {
+	if jd, ok := d.local.(*joinedDataset); ok {
+		return jd.localRef()
+	}
+
+	return d.def.LocalSource
+}
+
+// loadLocal initializes the local datasource loader
+//
+// When the local datasource is a joined datasource, the loader only provides
+// the local frames; the rest are buffered and included in the response
+// alongside the related local rows.
+func (d *joinedDataset) loadLocal(ctx context.Context, def *FrameDefinition) (Loader, Closer, error) {
+	l, c, err := d.local.Load(ctx, def)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := d.local.(*joinedDataset); !ok {
+		return l, c, nil
+	}
+
+	return func(cap int, processed bool) ([]*Frame, error) {
+		ff, err := l(cap, processed)
+		if err != nil || len(ff) == 0 {
+			return nil, err
+		}
+
+		// the joined datasource is never partitioned so the first frame
+		// is always the local one
+		if ff[0].Size() == 0 {
+			return nil, nil
+		}
+
+		for _, f := range ff[1:] {
+			f.Source = d.Name()
+			d.nestedFrames = append(d.nestedFrames, f)
+		}
+
+		return ff[:1], nil
+	}, c, nil
+}
+
 func (d *joinedDataset) sliceFrames(ff []*Frame, selfCol, relCol string) (out []*Frame, err error) {
 	outMap := make(map[string]int)
 
@@ -496,6 +556,11 @@ func (d *joinedDataset) prepareSorting(local, foreign *FrameDefinition) (inverte
 			} else {
 				foreignDS = spts[0]
 			}
+			if foreignDS != foreign.Ref {
+				// @todo allow this also
+				err = fmt.Errorf("cannot sort local datasource by a nested datasource: %s", foreignDS)
+				return
+			}
 
 			foreignSS = append(foreignSS, &filter.SortExpr{Column: spts[1], Descending: s.Descending})
 		} else {
@@ -769,6 +834,11 @@ func (d *joinedDataset) prepareResponse(localDef, foreignDef *FrameDefinition, d
 		a.RelSource = oo[0].Ref
 	}
 	oo = append(oo, aux...)
+	//
+	// -- followed by nested
+	if localDelimiter > 0 {
+		oo = append(oo, d.cutNestedFrameBuffer(oo[:localDelimiter])...)
+	}
 
 	// - Default for empty response
 	if len(oo) == 0 {
@@ -879,7 +949,7 @@ func (d *joinedDataset) cutLocalFrameBuffer(cap int) (oo []*Frame) {
 	// When partitioned, extract frames
 	if d.partitioned {
 		// We can use everything
-		if len(d.localFrames) <= cap {
+		if cap == 0 || len(d.localFrames) <= cap {
 			oo = d.localFrames
 			d.localFrames = nil
 			return
@@ -892,7 +962,7 @@ func (d *joinedDataset) cutLocalFrameBuffer(cap int) (oo []*Frame) {
 
 	// When not partitioned, extract rows
 	aux := d.localFrames[0].CloneMeta()
-	if d.localFrames[0].Size() <= cap {
+	if cap == 0 || d.localFrames[0].Size() <= cap {
 		aux.Rows = d.localFrames[0].Rows
 		d.localFrames = nil
 		return []*Frame{aux}
@@ -906,7 +976,7 @@ func (d *joinedDataset) cutLocalFrameBuffer(cap int) (oo []*Frame) {
 func (d *joinedDataset) cutForeignFrameBuffer(cap int) (oo []*Frame) {
 	for i, dsFrames := range d.foreignFrames {
 		// We can use everything
-		if len(dsFrames) <= cap {
+		if cap == 0 || len(dsFrames) <= cap {
 			oo = append(oo, dsFrames...)
 			d.foreignFrames[i] = nil
 			continue
@@ -919,6 +989,45 @@ func (d *joinedDataset) cutForeignFrameBuffer(cap int) (oo []*Frame) {
 	return
 }
 
+// cutNestedFrameBuffer extracts the nested frames related to the provided local frames
+func (d *joinedDataset) cutNestedFrameBuffer(local []*Frame) (oo []*Frame) {
+	var (
+		// related holds the values of the local frame columns the nested frames relate to
+		related = make(map[string]map[string]bool)
+		rest    = make([]*Frame, 0, len(d.nestedFrames))
+	)
+
+	for _, f := range d.nestedFrames {
+		kk, ok := related[f.RelColumn]
+		if !ok {
+			kk = make(map[string]bool)
+			for _, l := range local {
+				ci := l.Columns.Find(f.RelColumn)
+				if ci < 0 {
+					continue
+				}
+
+				l.WalkRows(func(i int, r FrameRow) error {
+					if r[ci] != nil {
+						kk[cast.ToString(r[ci].Get())] = true
+					}
+					return nil
+				})
+			}
+			related[f.RelColumn] = kk
+		}
+
+		if kk[f.RefValue] {
+			oo = append(oo, f)
+		} else {
+			rest = append(rest, f)
+		}
+	}
+
+	d.nestedFrames = rest
+	return
+}
+
 func (d *joinedDataset) bufferSize() int {
 	if len(d.localFrames) == 0 {
 		return 0
@@ -37,7 +37,7 @@ func (d *joinedDataset) stratLoadPrimary(ctx context.Context, inverted bool, loc
 	// @todo partitioned local when needed
 
 	// - local
-	ldr, clsr, err := d.local.Load(ctx, local)
+	ldr, clsr, err := d.loadLocal(ctx, local)
 	if err != nil {
 		return
 	}
@@ -134,7 +134,7 @@ func (d *joinedDataset) stratLoadInverted(ctx context.Context, inverted bool, lo
 		initLoader: func(cap int, f *Filter) (Loader, Closer, error) {
 			local.Filter = merger(lfilter.Clone(), f, "and")
 
-			return d.local.Load(ctx, local)
+			return d.loadLocal(ctx, local)
 		},
 
 		sorting:     local.Sort,
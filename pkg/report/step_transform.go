@@ -0,0 +1,548 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	stepTransform struct {
+		def *TransformStepDefinition
+	}
+
+	// transformedDataset performs the transformations in memory
+	//
+	// It is used when the underlying datasource is unable to transform the data.
+	transformedDataset struct {
+		def *TransformStepDefinition
+		ds  Datasource
+	}
+
+	// TransformDefinition defines the transformations over the source frame
+	//
+	// Transformations are applied in the following order:
+	//  . computed columns
+	//  . pivot or unpivot
+	//  . filter
+	TransformDefinition struct {
+		Columns []*TransformColumn `json:"columns,omitempty"`
+		Pivot   *TransformPivot    `json:"pivot,omitempty"`
+		Unpivot *TransformUnpivot  `json:"unpivot,omitempty"`
+		Filter  *Filter            `json:"filter,omitempty"`
+	}
+
+	TransformStepDefinition struct {
+		Name   string `json:"name"`
+		Source string `json:"source"`
+		TransformDefinition
+	}
+
+	// TransformColumn is a column computed from the source columns
+	TransformColumn struct {
+		Name  string `json:"name"`
+		Label string `json:"label"`
+		// Kind is determined from the expression when omitted
+		Kind string  `json:"kind,omitempty"`
+		Def  *colDef `json:"def"`
+	}
+
+	// TransformPivot turns values of a column into columns
+	//
+	// Rows should be aggregated beforehand (see the group step) so there is
+	// a single value for each key and pivoted column; the first one is used otherwise.
+	TransformPivot struct {
+		// Keys are the columns that identify the output rows
+		Keys []string `json:"keys"`
+		// Column is the column which values become the output columns
+		Column string `json:"column"`
+		// Value is the column that provides the values of the output columns
+		Value string `json:"value"`
+		// Values are the values of the Column that are turned into columns
+		Values []string `json:"values"`
+	}
+
+	// TransformUnpivot turns columns into rows
+	TransformUnpivot struct {
+		// Columns are the columns that are turned into rows
+		Columns []string `json:"columns"`
+		// Key is the output column with the name of the original column
+		Key string `json:"key"`
+		// Value is the output column with the value of the original column
+		Value string `json:"value"`
+	}
+)
+
+const (
+	// transformMaxSourceRows limits the number of source rows
+	// that are loaded into memory for the transformation
+	transformMaxSourceRows = 10000
+)
+
+func (j *stepTransform) Run(ctx context.Context, dd ...Datasource) (Datasource, error) {
+	if len(dd) == 0 || dd[0] == nil {
+		return nil, fmt.Errorf("unknown transform dimension: %s", j.def.Source)
+	}
+
+	dscr := dd[0].Describe()
+	if len(dscr) != 1 {
+		return nil, fmt.Errorf("unable to transform a joined source: %s", dd[0].Name())
+	}
+
+	// assure the transformation can be performed over the source
+	if _, err := j.def.columns(dscr[0].Columns); err != nil {
+		return nil, fmt.Errorf("invalid transform step: %w", err)
+	}
+
+	return &transformedDataset{
+		def: j.def,
+		ds:  dd[0],
+	}, nil
+}
+
+func (j *stepTransform) Validate() error {
+	pfx := "invalid transform step: "
+
+	// base things...
+	switch {
+	case j.def.Name == "":
+		return errors.New(pfx + "dimension name not defined")
+
+	case j.def.Source == "":
+		return errors.New(pfx + "transform dimension not defined")
+	case len(j.def.Columns) == 0 && j.def.Pivot == nil && j.def.Unpivot == nil && j.def.Filter == nil:
+		return errors.New(pfx + "no transformation defined")
+	case j.def.Pivot != nil && j.def.Unpivot != nil:
+		return errors.New(pfx + "pivot and unpivot can not be used together")
+	}
+
+	// columns...
+	for i, c := range j.def.Columns {
+		if c.Name == "" {
+			return fmt.Errorf("%scolumn alias missing for column: %d", pfx, i)
+		}
+		if c.Def == nil || c.Def.ASTNode == nil {
+			return fmt.Errorf("%scolumn expression missing for column: %s", pfx, c.Name)
+		}
+	}
+
+	// pivoting...
+	if p := j.def.Pivot; p != nil {
+		switch {
+		case len(p.Keys) == 0:
+			return errors.New(pfx + "pivot keys not defined")
+		case p.Column == "":
+			return errors.New(pfx + "pivot column not defined")
+		case p.Value == "":
+			return errors.New(pfx + "pivot value column not defined")
+		case len(p.Values) == 0:
+			return errors.New(pfx + "pivot values not defined")
+		}
+	}
+
+	if u := j.def.Unpivot; u != nil {
+		switch {
+		case len(u.Columns) == 0:
+			return errors.New(pfx + "unpivot columns not defined")
+		case u.Key == "":
+			return errors.New(pfx + "unpivot key column not defined")
+		case u.Value == "":
+			return errors.New(pfx + "unpivot value column not defined")
+		}
+	}
+
+	return nil
+}
+
+func (d *stepTransform) Name() string {
+	return d.def.Name
+}
+
+func (d *stepTransform) Source() []string {
+	return []string{d.def.Source}
+}
+
+func (d *stepTransform) Def() *StepDefinition {
+	return &StepDefinition{Transform: d.def}
+}
+
+// // // //
+
+func (d *transformedDataset) Name() string {
+	return d.def.Name
+}
+
+func (d *transformedDataset) Describe() FrameDescriptionSet {
+	// validated when the step was ran
+	cc, _ := d.def.columns(d.ds.Describe()[0].Columns)
+
+	return FrameDescriptionSet{
+		&FrameDescription{
+			Source:  d.Name(),
+			Ref:     d.Name(),
+			Columns: cc,
+		},
+	}
+}
+
+// Load transforms the entire source and provides the requested frame
+//
+// The transformed rows are filtered, sorted and paged in memory.
+func (d *transformedDataset) Load(ctx context.Context, dd ...*FrameDefinition) (l Loader, c Closer, err error) {
+	def := dd[0]
+
+	// . load and transform the source
+	src, err := d.loadSource(ctx)
+	if err != nil {
+		return
+	}
+
+	f, err := d.def.transform(src)
+	if err != nil {
+		return
+	}
+	f.Name = def.Name
+	f.Source = d.Name()
+	f.Ref = d.Name()
+
+	return memoryFrameLoader(f, def, 0, "")
+}
+
+// loadSource loads the entire source frame
+//
+// Sources with more than transformMaxSourceRows rows are rejected.
+func (d *transformedDataset) loadSource(ctx context.Context) (out *Frame, err error) {
+	l, c, err := d.ds.Load(ctx, &FrameDefinition{
+		Name:   d.Name(),
+		Source: d.ds.Name(),
+		Paging: &filter.Paging{Limit: transformMaxSourceRows + 1},
+		Sort:   filter.SortExprSet{},
+	})
+	if err != nil {
+		return
+	}
+	if c != nil {
+		defer c()
+	}
+
+	for {
+		ff, err := l(transformMaxSourceRows+1, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(ff) == 0 {
+			break
+		}
+
+		if out == nil {
+			out = ff[0]
+		} else {
+			out.Rows = append(out.Rows, ff[0].Rows...)
+		}
+
+		if len(out.Rows) > transformMaxSourceRows {
+			return nil, fmt.Errorf("unable to transform %s: source has more than %d rows", d.ds.Name(), transformMaxSourceRows)
+		}
+	}
+
+	if out == nil {
+		out = &Frame{
+			Columns: d.ds.Describe()[0].Columns,
+		}
+	}
+
+	return
+}
+
+// columns returns the columns of the transformed frame
+//
+// Transformation expressions are validated over the columns
+// available at the given stage.
+func (def TransformDefinition) columns(cc FrameColumnSet) (out FrameColumnSet, err error) {
+	// - computed columns
+	out = append(FrameColumnSet{}, cc...)
+	for _, c := range def.Columns {
+		if out.Find(c.Name) > -1 {
+			return nil, fmt.Errorf("column %s already exists", c.Name)
+		}
+
+		if err = validateEval(c.Def.ASTNode, cc); err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.Name, err)
+		}
+
+		kind := c.Kind
+		if kind == "" {
+			kind = evalKind(c.Def.ASTNode, cc)
+		}
+
+		col := MakeColumnOfKind(kind)
+		col.Name = c.Name
+		col.Label = c.Label
+		if col.Label == "" {
+			col.Label = col.Name
+		}
+		out = append(out, col)
+	}
+
+	// - pivot/unpivot
+	switch {
+	case def.Pivot != nil:
+		out, err = def.Pivot.columns(out)
+	case def.Unpivot != nil:
+		out, err = def.Unpivot.columns(out)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// - filter
+	if def.Filter != nil && def.Filter.ASTNode != nil {
+		if err = validateEval(def.Filter.ASTNode, out); err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+	}
+
+	return
+}
+
+// transform applies the transformations to the given frame
+func (def TransformDefinition) transform(f *Frame) (out *Frame, err error) {
+	cc, err := def.columns(f.Columns)
+	if err != nil {
+		return
+	}
+
+	out = &Frame{
+		Columns: cc,
+		Rows:    f.Rows,
+	}
+
+	// - computed columns
+	if len(def.Columns) > 0 {
+		rr := make(FrameRowSet, len(f.Rows))
+		for i, r := range f.Rows {
+			rr[i] = make(FrameRow, len(f.Columns), len(f.Columns)+len(def.Columns))
+			copy(rr[i], r)
+
+			for j, c := range def.Columns {
+				v := evalValue(c.Def.ASTNode, r, f.Columns)
+
+				// only cast when explicitly requested
+				if c.Kind != "" && !isNil(v) {
+					if v, err = cc[len(f.Columns)+j].Caster(v.Get()); err != nil {
+						return nil, fmt.Errorf("column %s: %w", c.Name, err)
+					}
+				}
+
+				rr[i] = append(rr[i], v)
+			}
+		}
+		out.Rows = rr
+	}
+
+	// - pivot/unpivot
+	srcCols := make(FrameColumnSet, 0, len(f.Columns)+len(def.Columns))
+	srcCols = append(srcCols, f.Columns...)
+	for _, c := range def.Columns {
+		srcCols = append(srcCols, &FrameColumn{Name: c.Name})
+	}
+	switch {
+	case def.Pivot != nil:
+		out.Rows = def.Pivot.rows(out.Rows, srcCols)
+	case def.Unpivot != nil:
+		out.Rows = def.Unpivot.rows(out.Rows, srcCols)
+	}
+
+	// - filter
+	if def.Filter != nil && def.Filter.ASTNode != nil {
+		aux := make(FrameRowSet, 0, len(out.Rows))
+		for _, r := range out.Rows {
+			if evalBool(def.Filter.ASTNode, r, out.Columns) {
+				aux = append(aux, r)
+			}
+		}
+		out.Rows = aux
+	}
+
+	return
+}
+
+func (p *TransformPivot) columns(cc FrameColumnSet) (out FrameColumnSet, err error) {
+	out = make(FrameColumnSet, 0, len(p.Keys)+len(p.Values))
+
+	for _, k := range p.Keys {
+		ci := cc.Find(k)
+		if ci < 0 {
+			return nil, fmt.Errorf("pivot key column not found: %s", k)
+		}
+
+		aux := *cc[ci]
+		// a single key identifies the row
+		aux.Unique = len(p.Keys) == 1
+		out = append(out, &aux)
+	}
+
+	if cc.Find(p.Column) < 0 {
+		return nil, fmt.Errorf("pivot column not found: %s", p.Column)
+	}
+
+	vi := cc.Find(p.Value)
+	if vi < 0 {
+		return nil, fmt.Errorf("pivot value column not found: %s", p.Value)
+	}
+
+	for _, v := range p.Values {
+		if out.Find(v) > -1 {
+			return nil, fmt.Errorf("column %s already exists", v)
+		}
+
+		aux := *cc[vi]
+		aux.Name = v
+		aux.Label = v
+		aux.Primary = false
+		aux.Unique = false
+		aux.System = false
+		out = append(out, &aux)
+	}
+
+	return
+}
+
+func (p *TransformPivot) rows(rr FrameRowSet, cc FrameColumnSet) (out FrameRowSet) {
+	var (
+		kci = make([]int, len(p.Keys))
+		pci = cc.Find(p.Column)
+		vci = cc.Find(p.Value)
+
+		// index of the output row for the given keys
+		index = make(map[string]int)
+		// index of the output column for the given value
+		values = make(map[string]int)
+	)
+
+	for i, k := range p.Keys {
+		kci[i] = cc.Find(k)
+	}
+	for i, v := range p.Values {
+		values[v] = len(p.Keys) + i
+	}
+
+	kk := make([]string, len(kci))
+	for _, r := range rr {
+		vi, ok := values[cellToString(r[pci])]
+		if !ok {
+			continue
+		}
+
+		for i, ci := range kci {
+			kk[i] = cellToString(r[ci])
+		}
+		k := strings.Join(kk, "\x00")
+
+		ri, ok := index[k]
+		if !ok {
+			ri = len(out)
+			index[k] = ri
+
+			row := make(FrameRow, len(p.Keys)+len(p.Values))
+			for i, ci := range kci {
+				row[i] = r[ci]
+			}
+			out = append(out, row)
+		}
+
+		if out[ri][vi] == nil {
+			out[ri][vi] = r[vci]
+		}
+	}
+
+	return
+}
+
+func (u *TransformUnpivot) columns(cc FrameColumnSet) (out FrameColumnSet, err error) {
+	var (
+		unpivot = make(map[string]bool)
+		kind    string
+	)
+
+	for _, c := range u.Columns {
+		ci := cc.Find(c)
+		if ci < 0 {
+			return nil, fmt.Errorf("unpivot column not found: %s", c)
+		}
+		unpivot[c] = true
+
+		// mixed kinds are provided as strings
+		switch kind {
+		case "":
+			kind = cc[ci].Kind
+		case cc[ci].Kind:
+		default:
+			kind = "String"
+		}
+	}
+
+	out = make(FrameColumnSet, 0, len(cc)-len(u.Columns)+2)
+	for _, c := range cc {
+		if !unpivot[c.Name] {
+			aux := *c
+			// a row is now split into multiple rows
+			aux.Primary = false
+			aux.Unique = false
+			out = append(out, &aux)
+		}
+	}
+
+	for _, n := range []string{u.Key, u.Value} {
+		if out.Find(n) > -1 {
+			return nil, fmt.Errorf("column %s already exists", n)
+		}
+	}
+
+	key := MakeColumnOfKind("String")
+	key.Name = u.Key
+	key.Label = u.Key
+
+	value := MakeColumnOfKind(kind)
+	value.Name = u.Value
+	value.Label = u.Value
+
+	return append(out, key, value), nil
+}
+
+func (u *TransformUnpivot) rows(rr FrameRowSet, cc FrameColumnSet) (out FrameRowSet) {
+	var (
+		unpivot = make(map[string]bool)
+		keep    = make([]int, 0, len(cc))
+		uci     = make([]int, len(u.Columns))
+	)
+
+	for i, c := range u.Columns {
+		unpivot[c] = true
+		uci[i] = cc.Find(c)
+	}
+	for i, c := range cc {
+		if !unpivot[c.Name] {
+			keep = append(keep, i)
+		}
+	}
+
+	out = make(FrameRowSet, 0, len(rr)*len(u.Columns))
+	for _, r := range rr {
+		for i, ci := range uci {
+			row := make(FrameRow, 0, len(keep)+2)
+			for _, ki := range keep {
+				row = append(row, r[ki])
+			}
+
+			row = append(row, expr.Must(expr.NewString(u.Columns[i])), r[ci])
+			out = append(out, row)
+		}
+	}
+
+	return
+}
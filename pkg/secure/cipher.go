@@ -0,0 +1,117 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+type (
+	// Cipher encrypts secret values with AES-GCM
+	//
+	// Values are always encrypted with the first (current) key;
+	// all keys are tried when decrypting so that values encrypted
+	// with the previous key can still be read and rotated
+	Cipher struct {
+		keys []cipher.AEAD
+	}
+)
+
+// NewCipher prepares cipher from the current and (optional) previous keys
+//
+// Keys can be of any length, 256bit AES key is derived from each of them
+func NewCipher(current string, previous ...string) (c *Cipher, err error) {
+	if current == "" {
+		return nil, fmt.Errorf("secret key not set")
+	}
+
+	c = &Cipher{}
+
+	for _, key := range append([]string{current}, previous...) {
+		if key == "" {
+			continue
+		}
+
+		var (
+			k     = sha256.Sum256([]byte(key))
+			block cipher.Block
+			aead  cipher.AEAD
+		)
+
+		if block, err = aes.NewCipher(k[:]); err != nil {
+			return nil, err
+		}
+
+		if aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+
+		c.keys = append(c.keys, aead)
+	}
+
+	return
+}
+
+// Encrypt encrypts value with the current key
+func (c *Cipher) Encrypt(value string) (string, error) {
+	var (
+		aead  = c.keys[0]
+		nonce = make([]byte, aead.NonceSize())
+	)
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// Decrypt decrypts value with any of the known keys
+func (c *Cipher) Decrypt(enc string) (value string, err error) {
+	value, _, err = c.decrypt(enc)
+	return
+}
+
+// Rotate re-encrypts value with the current key
+//
+// Returns false when value is already encrypted with the current key
+func (c *Cipher) Rotate(enc string) (_ string, rotated bool, err error) {
+	var (
+		value string
+		key   int
+	)
+
+	if value, key, err = c.decrypt(enc); err != nil || key == 0 {
+		return enc, false, err
+	}
+
+	if enc, err = c.Encrypt(value); err != nil {
+		return "", false, err
+	}
+
+	return enc, true, nil
+}
+
+func (c *Cipher) decrypt(enc string) (string, int, error) {
+	raw, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", -1, fmt.Errorf("could not decode secret: %w", err)
+	}
+
+	for k, aead := range c.keys {
+		if len(raw) < aead.NonceSize() {
+			break
+		}
+
+		value, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+		if err == nil {
+			return string(value), k, nil
+		}
+	}
+
+	return "", -1, fmt.Errorf("could not decrypt secret with any of the keys")
+}
@@ -0,0 +1,69 @@
+package secure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cipherEncryptDecrypt(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	c, err := NewCipher("key")
+	req.NoError(err)
+
+	enc, err := c.Encrypt("s3cr3t")
+	req.NoError(err)
+	req.NotContains(enc, "s3cr3t")
+
+	value, err := c.Decrypt(enc)
+	req.NoError(err)
+	req.Equal("s3cr3t", value)
+
+	c, err = NewCipher("other key")
+	req.NoError(err)
+
+	_, err = c.Decrypt(enc)
+	req.Error(err)
+}
+
+func Test_cipherRotate(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	prev, err := NewCipher("old key")
+	req.NoError(err)
+
+	enc, err := prev.Encrypt("s3cr3t")
+	req.NoError(err)
+
+	c, err := NewCipher("new key", "old key")
+	req.NoError(err)
+
+	value, err := c.Decrypt(enc)
+	req.NoError(err)
+	req.Equal("s3cr3t", value)
+
+	enc, rotated, err := c.Rotate(enc)
+	req.NoError(err)
+	req.True(rotated)
+
+	_, rotated, err = c.Rotate(enc)
+	req.NoError(err)
+	req.False(rotated)
+
+	c, err = NewCipher("new key")
+	req.NoError(err)
+
+	value, err = c.Decrypt(enc)
+	req.NoError(err)
+	req.Equal("s3cr3t", value)
+}
+
+func Test_cipherNoKey(t *testing.T) {
+	_, err := NewCipher("")
+	require.EqualError(t, err, "secret key not set")
+}
@@ -0,0 +1,71 @@
+package secure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+const (
+	// ReferencePrefix marks values (filter params, messagebus settings)
+	// that point to stored secrets
+	//
+	// Value "secret://my-token" is replaced with the (decrypted)
+	// value of the secret with handle "my-token"
+	ReferencePrefix = "secret://"
+)
+
+type (
+	storer interface {
+		LookupApigwSecretByHandle(ctx context.Context, handle string) (*types.ApigwSecret, error)
+	}
+
+	storage struct {
+		store  storer
+		cipher *Cipher
+	}
+)
+
+// NewStorage returns secure storage that resolves secret references
+// with secrets from the store
+func NewStorage(s storer, c *Cipher) *storage {
+	return &storage{store: s, cipher: c}
+}
+
+// Reference returns reference to the secret that can be used in place of the value
+func Reference(handle string) string {
+	return ReferencePrefix + handle
+}
+
+// ParseReference returns handle of the referenced secret
+func ParseReference(v string) (handle string, ok bool) {
+	if !strings.HasPrefix(v, ReferencePrefix) {
+		return "", false
+	}
+
+	handle = strings.TrimPrefix(v, ReferencePrefix)
+	return handle, handle != ""
+}
+
+// Resolve returns value of the referenced secret
+//
+// Values that are not secret references are returned as-is
+func (s *storage) Resolve(ctx context.Context, v string) (string, error) {
+	handle, ok := ParseReference(v)
+	if !ok {
+		return v, nil
+	}
+
+	sec, err := s.store.LookupApigwSecretByHandle(ctx, handle)
+	if err != nil {
+		return "", fmt.Errorf("could not load secret %q: %w", handle, err)
+	}
+
+	if v, err = s.cipher.Decrypt(sec.Value); err != nil {
+		return "", fmt.Errorf("could not read secret %q: %w", handle, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,48 @@
+package secure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockStorer map[string]*types.ApigwSecret
+)
+
+func (s mockStorer) LookupApigwSecretByHandle(_ context.Context, handle string) (*types.ApigwSecret, error) {
+	if sec, ok := s[handle]; ok {
+		return sec, nil
+	}
+
+	return nil, errors.New("not found")
+}
+
+func Test_storageResolve(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+	)
+
+	c, err := NewCipher("key")
+	req.NoError(err)
+
+	enc, err := c.Encrypt("s3cr3t")
+	req.NoError(err)
+
+	s := NewStorage(mockStorer{"token": {Handle: "token", Value: enc}}, c)
+
+	v, err := s.Resolve(ctx, "plain value")
+	req.NoError(err)
+	req.Equal("plain value", v)
+
+	v, err = s.Resolve(ctx, Reference("token"))
+	req.NoError(err)
+	req.Equal("s3cr3t", v)
+
+	_, err = s.Resolve(ctx, Reference("missing"))
+	req.EqualError(err, `could not load secret "missing": not found`)
+}
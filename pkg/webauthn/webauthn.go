@@ -0,0 +1,358 @@
+// Package webauthn implements relying party part of the
+// WebAuthn registration and authentication ceremonies
+//
+// https://www.w3.org/TR/webauthn-2/
+//
+// Decoding and verification of the authenticator responses (CBOR, COSE keys,
+// attestation statements) is done with github.com/duo-labs/webauthn; this package
+// only adapts it to the way ceremonies are handled by the auth handlers
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/protocol/webauthncose"
+)
+
+type (
+	// RelyingParty holds identification of the server
+	RelyingParty struct {
+		// ID is a valid domain string; (effective) domain of the origin
+		ID string
+
+		// Human-palatable name shown by the browser
+		Name string
+
+		// Origin of the pages (scheme://host[:port]) that invoke ceremonies
+		Origin string
+
+		// Require user verification (pin, biometrics) on the authenticator
+		UserVerification bool
+	}
+
+	// Credential holds the public part of the key that was registered
+	Credential struct {
+		ID        URLEncoded `json:"id"`
+		PublicKey URLEncoded `json:"publicKey"`
+		Algorithm int64      `json:"alg"`
+		SignCount uint32     `json:"signCount"`
+	}
+
+	// User the credential is created for
+	User struct {
+		ID          URLEncoded `json:"id"`
+		Name        string     `json:"name"`
+		DisplayName string     `json:"displayName"`
+	}
+
+	// CreationOptions are passed to navigator.credentials.create()
+	CreationOptions struct {
+		RP struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"rp"`
+
+		User                   User                   `json:"user"`
+		Challenge              URLEncoded             `json:"challenge"`
+		PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+		Timeout                int                    `json:"timeout"`
+		ExcludeCredentials     []credentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection struct {
+			ResidentKey      string `json:"residentKey"`
+			UserVerification string `json:"userVerification"`
+		} `json:"authenticatorSelection"`
+		Attestation string `json:"attestation"`
+	}
+
+	// RequestOptions are passed to navigator.credentials.get()
+	RequestOptions struct {
+		Challenge        URLEncoded             `json:"challenge"`
+		Timeout          int                    `json:"timeout"`
+		RPID             string                 `json:"rpId"`
+		AllowCredentials []credentialDescriptor `json:"allowCredentials"`
+		UserVerification string                 `json:"userVerification"`
+	}
+
+	// AttestationResponse is the credential created by the authenticator
+	AttestationResponse struct {
+		ID       URLEncoded `json:"id"`
+		Response struct {
+			ClientDataJSON    URLEncoded `json:"clientDataJSON"`
+			AttestationObject URLEncoded `json:"attestationObject"`
+		} `json:"response"`
+	}
+
+	// AssertionResponse is the credential assertion signed by the authenticator
+	AssertionResponse struct {
+		ID       URLEncoded `json:"id"`
+		Response struct {
+			ClientDataJSON    URLEncoded `json:"clientDataJSON"`
+			AuthenticatorData URLEncoded `json:"authenticatorData"`
+			Signature         URLEncoded `json:"signature"`
+			UserHandle        URLEncoded `json:"userHandle"`
+		} `json:"response"`
+	}
+
+	// URLEncoded is a byte slice encoded as unpadded base64url string in JSON
+	URLEncoded []byte
+
+	credentialParameter struct {
+		Type string `json:"type"`
+		Alg  int64  `json:"alg"`
+	}
+
+	credentialDescriptor struct {
+		Type string     `json:"type"`
+		ID   URLEncoded `json:"id"`
+	}
+)
+
+const (
+	ChallengeLength = 32
+
+	// ceremony timeout in milliseconds
+	timeout = 60000
+
+	credentialType = "public-key"
+)
+
+// supportedAlgorithms lists algorithms in the order of preference
+var supportedAlgorithms = []webauthncose.COSEAlgorithmIdentifier{
+	webauthncose.AlgES256,
+	webauthncose.AlgEdDSA,
+	webauthncose.AlgRS256,
+}
+
+// NewChallenge generates random challenge for a ceremony
+//
+// Challenge should be kept on the server (in the session)
+// until the ceremony is completed
+func NewChallenge() ([]byte, error) {
+	c := make([]byte, ChallengeLength)
+	if _, err := rand.Read(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// CreationOptions prepares options for registration ceremony
+//
+// Credentials that are already registered are excluded
+func (rp RelyingParty) CreationOptions(challenge []byte, u User, residentKey bool, exclude ...[]byte) *CreationOptions {
+	o := &CreationOptions{
+		User:               u,
+		Challenge:          challenge,
+		Timeout:            timeout,
+		ExcludeCredentials: descriptors(exclude),
+		Attestation:        "none",
+	}
+
+	o.RP.ID = rp.ID
+	o.RP.Name = rp.Name
+
+	for _, alg := range supportedAlgorithms {
+		o.PubKeyCredParams = append(o.PubKeyCredParams, credentialParameter{Type: credentialType, Alg: int64(alg)})
+	}
+
+	o.AuthenticatorSelection.ResidentKey = "discouraged"
+	if residentKey {
+		o.AuthenticatorSelection.ResidentKey = "preferred"
+	}
+
+	o.AuthenticatorSelection.UserVerification = rp.userVerification()
+	return o
+}
+
+// RequestOptions prepares options for authentication ceremony
+//
+// When no credentials are allowed, authenticator picks one of
+// the resident (discoverable) credentials
+func (rp RelyingParty) RequestOptions(challenge []byte, allow ...[]byte) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          timeout,
+		RPID:             rp.ID,
+		AllowCredentials: descriptors(allow),
+		UserVerification: rp.userVerification(),
+	}
+}
+
+// VerifyRegistration verifies response of the registration ceremony
+// and returns the credential that should be stored
+func (rp RelyingParty) VerifyRegistration(challenge []byte, r *AttestationResponse) (c *Credential, err error) {
+	if r == nil {
+		return nil, fmt.Errorf("missing attestation response")
+	}
+
+	defer recoverMalformed(&err)
+
+	var (
+		pcc = &protocol.ParsedCredentialCreationData{}
+		par *protocol.ParsedAttestationResponse
+	)
+
+	pcc.Raw.AttestationResponse.ClientDataJSON = protocol.URLEncodedBase64(r.Response.ClientDataJSON)
+	pcc.Raw.AttestationResponse.AttestationObject = protocol.URLEncodedBase64(r.Response.AttestationObject)
+
+	if par, err = pcc.Raw.AttestationResponse.Parse(); err != nil {
+		return nil, fmt.Errorf("could not parse attestation response: %w", err)
+	}
+
+	pcc.Response = *par
+	if err = pcc.Verify(Encode(challenge), rp.UserVerification, rp.ID, rp.origin()); err != nil {
+		return nil, verificationError(err)
+	}
+
+	var (
+		ad  = par.AttestationObject.AuthData
+		key interface{}
+	)
+
+	if len(r.ID) > 0 && !bytes.Equal(r.ID, ad.AttData.CredentialID) {
+		return nil, fmt.Errorf("credential ID mismatch")
+	}
+
+	if key, err = webauthncose.ParsePublicKey(ad.AttData.CredentialPublicKey); err != nil {
+		return nil, fmt.Errorf("could not parse credential public key: %w", err)
+	}
+
+	c = &Credential{
+		ID:        ad.AttData.CredentialID,
+		PublicKey: ad.AttData.CredentialPublicKey,
+		SignCount: ad.Counter,
+	}
+
+	switch k := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		c.Algorithm = k.Algorithm
+	case webauthncose.OKPPublicKeyData:
+		c.Algorithm = k.Algorithm
+	case webauthncose.RSAPublicKeyData:
+		c.Algorithm = k.Algorithm
+	default:
+		return nil, fmt.Errorf("unsupported credential public key")
+	}
+
+	return c, nil
+}
+
+// VerifyAssertion verifies response of the authentication ceremony
+// with the stored credential
+//
+// Returns the new signature counter that should be stored
+func (rp RelyingParty) VerifyAssertion(challenge []byte, r *AssertionResponse, c *Credential) (signCount uint32, err error) {
+	if r == nil {
+		return 0, fmt.Errorf("missing assertion response")
+	}
+
+	if c == nil || !bytes.Equal(r.ID, c.ID) {
+		return 0, fmt.Errorf("credential ID mismatch")
+	}
+
+	defer recoverMalformed(&err)
+
+	var (
+		pca = &protocol.ParsedCredentialAssertionData{}
+	)
+
+	pca.Raw.AssertionResponse.ClientDataJSON = protocol.URLEncodedBase64(r.Response.ClientDataJSON)
+	pca.Raw.AssertionResponse.AuthenticatorData = protocol.URLEncodedBase64(r.Response.AuthenticatorData)
+	pca.Raw.AssertionResponse.Signature = protocol.URLEncodedBase64(r.Response.Signature)
+	pca.Raw.AssertionResponse.UserHandle = protocol.URLEncodedBase64(r.Response.UserHandle)
+
+	pca.Response.Signature = r.Response.Signature
+	pca.Response.UserHandle = r.Response.UserHandle
+
+	if err = json.Unmarshal(r.Response.ClientDataJSON, &pca.Response.CollectedClientData); err != nil {
+		return 0, fmt.Errorf("could not decode client data: %w", err)
+	}
+
+	if err = pca.Response.AuthenticatorData.Unmarshal(r.Response.AuthenticatorData); err != nil {
+		return 0, fmt.Errorf("could not decode authenticator data: %w", err)
+	}
+
+	if err = pca.Verify(Encode(challenge), rp.ID, rp.origin(), rp.UserVerification, c.PublicKey); err != nil {
+		return 0, verificationError(err)
+	}
+
+	signCount = pca.Response.AuthenticatorData.Counter
+
+	// authenticators that do not implement
+	// signature counter always report zero
+	if (signCount > 0 || c.SignCount > 0) && signCount <= c.SignCount {
+		return 0, fmt.Errorf("signature counter did not increase, authenticator might be cloned")
+	}
+
+	return signCount, nil
+}
+
+func (rp RelyingParty) userVerification() string {
+	if rp.UserVerification {
+		return "required"
+	}
+
+	return "preferred"
+}
+
+// origin as compared with the (fully qualified) origin from the client data
+func (rp RelyingParty) origin() string {
+	return strings.TrimSuffix(rp.Origin, "/")
+}
+
+// verificationError adds debug info to the error; protocol errors only
+// report the general reason (challenge, origin...) in the Error()
+func verificationError(err error) error {
+	if perr, ok := err.(*protocol.Error); ok && perr.DevInfo != "" {
+		return fmt.Errorf("%s: %s", perr.Details, strings.TrimSpace(perr.DevInfo))
+	}
+
+	return err
+}
+
+// recoverMalformed converts panics on malformed authenticator
+// data (not all lengths are checked when decoding) into an error
+func recoverMalformed(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("malformed authenticator data")
+	}
+}
+
+func descriptors(ids [][]byte) []credentialDescriptor {
+	dd := make([]credentialDescriptor, 0, len(ids))
+	for _, id := range ids {
+		dd = append(dd, credentialDescriptor{Type: credentialType, ID: id})
+	}
+
+	return dd
+}
+
+// Encode encodes bytes as unpadded base64url
+func Encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode decodes base64url string, padding is optional
+func Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func (e URLEncoded) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Encode(e))
+}
+
+func (e *URLEncoded) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return
+	}
+
+	*e, err = Decode(s)
+	return
+}
@@ -0,0 +1,324 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// authenticator mimics a security key with a single credential
+	authenticator struct {
+		key       *ecdsa.PrivateKey
+		id        []byte
+		signCount uint32
+	}
+
+	coseKey map[int64]interface{}
+)
+
+const (
+	flagUserPresent      = byte(protocol.FlagUserPresent)
+	flagAttestedCredData = byte(protocol.FlagAttestedCredentialData)
+
+	algES256 = int64(webauthncose.AlgES256)
+)
+
+// cborEncode encodes the subset of the values used in the tests
+func cborEncode(v interface{}) []byte {
+	head := func(major byte, n int) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+		}
+	}
+
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, int(-1-v))
+		}
+		return head(0, int(v))
+	case []byte:
+		return append(head(2, len(v)), v...)
+	case string:
+		return append(head(3, len(v)), v...)
+	case []interface{}:
+		out := head(4, len(v))
+		for _, i := range v {
+			out = append(out, cborEncode(i)...)
+		}
+		return out
+	case coseKey:
+		kk := make([]int64, 0, len(v))
+		for k := range v {
+			kk = append(kk, k)
+		}
+		sort.Slice(kk, func(i, j int) bool { return kk[i] < kk[j] })
+
+		out := head(5, len(v))
+		for _, k := range kk {
+			out = append(out, cborEncode(k)...)
+			out = append(out, cborEncode(v[k])...)
+		}
+		return out
+	case map[string]interface{}:
+		kk := make([]string, 0, len(v))
+		for k := range v {
+			kk = append(kk, k)
+		}
+		sort.Strings(kk)
+
+		out := head(5, len(v))
+		for _, k := range kk {
+			out = append(out, cborEncode(k)...)
+			out = append(out, cborEncode(v[k])...)
+		}
+		return out
+	}
+
+	panic("unsupported type")
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &authenticator{key: key, id: []byte("credential-id")}
+}
+
+func (a *authenticator) publicKey() []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	return cborEncode(coseKey{1: int64(2), 3: algES256, -1: int64(1), -2: x, -3: y})
+}
+
+func (a *authenticator) authData(rpID string, flags byte, attested bool) []byte {
+	var (
+		h   = sha256.Sum256([]byte(rpID))
+		out = append(h[:], flags)
+	)
+
+	out = append(out, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[len(out)-4:], a.signCount)
+
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = append(out, 0, 0)
+		binary.BigEndian.PutUint16(out[len(out)-2:], uint16(len(a.id)))
+		out = append(out, a.id...)
+		out = append(out, a.publicKey()...)
+	}
+
+	return out
+}
+
+func (a *authenticator) sign(authData, clientDataJSON []byte) []byte {
+	var (
+		ch   = sha256.Sum256(clientDataJSON)
+		hash = sha256.Sum256(append(append([]byte{}, authData...), ch[:]...))
+	)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, hash[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return sig
+}
+
+func clientDataJSON(typ string, challenge []byte, origin string) []byte {
+	b, _ := json.Marshal(map[string]string{"type": typ, "challenge": Encode(challenge), "origin": origin})
+	return b
+}
+
+func (a *authenticator) create(rpID, origin string, challenge []byte, format string) *AttestationResponse {
+	var (
+		r    = &AttestationResponse{ID: a.id}
+		ad   = a.authData(rpID, flagUserPresent|flagAttestedCredData, true)
+		cd   = clientDataJSON("webauthn.create", challenge, origin)
+		stmt = map[string]interface{}{}
+	)
+
+	if format == "packed" {
+		stmt["alg"] = algES256
+		stmt["sig"] = a.sign(ad, cd)
+	}
+
+	r.Response.ClientDataJSON = cd
+	r.Response.AttestationObject = cborEncode(map[string]interface{}{
+		"fmt":      format,
+		"attStmt":  stmt,
+		"authData": ad,
+	})
+
+	return r
+}
+
+func (a *authenticator) get(rpID, origin string, challenge []byte) *AssertionResponse {
+	var (
+		r  = &AssertionResponse{ID: a.id}
+		ad = a.authData(rpID, flagUserPresent, false)
+		cd = clientDataJSON("webauthn.get", challenge, origin)
+	)
+
+	r.Response.ClientDataJSON = cd
+	r.Response.AuthenticatorData = ad
+	r.Response.Signature = a.sign(ad, cd)
+	return r
+}
+
+func TestRegistration(t *testing.T) {
+	var (
+		rp = RelyingParty{ID: "example.tld", Name: "Example", Origin: "https://example.tld"}
+	)
+
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	t.Run("none attestation", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			a   = newAuthenticator(t)
+		)
+
+		c, err := rp.VerifyRegistration(challenge, a.create(rp.ID, rp.Origin, challenge, "none"))
+		req.NoError(err)
+		req.Equal(a.id, []byte(c.ID))
+		// key is re-encoded when authenticator data is decoded
+		expected, err := webauthncose.ParsePublicKey(a.publicKey())
+		req.NoError(err)
+		stored, err := webauthncose.ParsePublicKey(c.PublicKey)
+		req.NoError(err)
+		req.Equal(expected, stored)
+		req.Equal(algES256, c.Algorithm)
+	})
+
+	t.Run("packed self attestation", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			a   = newAuthenticator(t)
+		)
+
+		_, err := rp.VerifyRegistration(challenge, a.create(rp.ID, rp.Origin, challenge, "packed"))
+		req.NoError(err)
+	})
+
+	t.Run("invalid ceremonies", func(t *testing.T) {
+		var (
+			a     = newAuthenticator(t)
+			other = []byte("some-other-challenge")
+		)
+
+		tcc := []struct {
+			name string
+			r    *AttestationResponse
+			err  string
+		}{
+			{"challenge", a.create(rp.ID, rp.Origin, other, "none"), "Error validating challenge"},
+			{"origin", a.create(rp.ID, "https://evil.tld", challenge, "none"), "Error validating origin"},
+			{"relying party", a.create("evil.tld", rp.Origin, challenge, "none"), "RP Hash mismatch"},
+			{"format", a.create(rp.ID, rp.Origin, challenge, "unknown"), "Attestation format unknown is unsupported"},
+		}
+
+		for _, tc := range tcc {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := rp.VerifyRegistration(challenge, tc.r)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			})
+		}
+	})
+
+	t.Run("malformed attestation object", func(t *testing.T) {
+		var (
+			a = newAuthenticator(t)
+
+			// deeply nested arrays and tags
+			nested = append(bytes.Repeat([]byte{0x81, 0xc0}, 100000), 0x00)
+
+			// attested credential data flag without the data
+			truncated = cborEncode(map[string]interface{}{
+				"fmt":      "none",
+				"attStmt":  map[string]interface{}{},
+				"authData": append(a.authData(rp.ID, flagUserPresent|flagAttestedCredData, false), 0, 1),
+			})
+		)
+
+		for _, obj := range [][]byte{nested, truncated} {
+			r := a.create(rp.ID, rp.Origin, challenge, "none")
+			r.Response.AttestationObject = obj
+
+			_, err := rp.VerifyRegistration(challenge, r)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestAssertion(t *testing.T) {
+	var (
+		req = require.New(t)
+		rp  = RelyingParty{ID: "example.tld", Name: "Example", Origin: "https://example.tld"}
+		a   = newAuthenticator(t)
+	)
+
+	challenge, err := NewChallenge()
+	req.NoError(err)
+
+	c, err := rp.VerifyRegistration(challenge, a.create(rp.ID, rp.Origin, challenge, "none"))
+	req.NoError(err)
+
+	a.signCount = 5
+	signCount, err := rp.VerifyAssertion(challenge, a.get(rp.ID, rp.Origin, challenge), c)
+	req.NoError(err)
+	req.Equal(uint32(5), signCount)
+	c.SignCount = signCount
+
+	// replayed counter
+	_, err = rp.VerifyAssertion(challenge, a.get(rp.ID, rp.Origin, challenge), c)
+	req.Error(err)
+
+	// signed by different key
+	a.signCount = 6
+	r := a.get(rp.ID, rp.Origin, challenge)
+	r.Response.Signature = newAuthenticator(t).sign(r.Response.AuthenticatorData, r.Response.ClientDataJSON)
+	_, err = rp.VerifyAssertion(challenge, r, c)
+	req.Error(err)
+	req.Contains(err.Error(), "Error validating the assertion signature")
+
+	// user verification required
+	rp.UserVerification = true
+	_, err = rp.VerifyAssertion(challenge, a.get(rp.ID, rp.Origin, challenge), c)
+	req.Error(err)
+	req.Contains(err.Error(), "Error verifying the authenticator data")
+}
+
+func TestURLEncoded(t *testing.T) {
+	var (
+		req = require.New(t)
+		v   URLEncoded
+	)
+
+	b, err := json.Marshal(URLEncoded{0xfb, 0xff})
+	req.NoError(err)
+	req.Equal(`"-_8"`, string(b))
+
+	req.NoError(json.Unmarshal([]byte(`"-_8="`), &v))
+	req.Equal(URLEncoded{0xfb, 0xff}, v)
+}
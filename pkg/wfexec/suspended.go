@@ -0,0 +1,193 @@
+package wfexec
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+)
+
+type (
+	// SuspendedState is a serializable representation of delayed or prompted state
+	//
+	// It holds everything needed to reconstruct the state
+	// on a new session after it is loaded from the storage
+	SuspendedState struct {
+		StateID      uint64     `json:"stateID,string"`
+		StepID       uint64     `json:"stepID,string"`
+		ParentID     uint64     `json:"parentID,string,omitempty"`
+		ErrHandlerID uint64     `json:"errHandlerID,string,omitempty"`
+		OwnerID      uint64     `json:"ownerID,string,omitempty"`
+		OwnerRoles   []uint64   `json:"ownerRoles,omitempty"`
+		CreatedAt    time.Time  `json:"createdAt"`
+		Scope        *expr.Vars `json:"scope"`
+
+		// Set when state is delayed
+		ResumeAt *time.Time `json:"resumeAt,omitempty"`
+
+		// Set when state is waiting for user input
+		Prompt *SuspendedPrompt `json:"prompt,omitempty"`
+	}
+
+	SuspendedPrompt struct {
+		Ref     string     `json:"ref"`
+		OwnerID uint64     `json:"ownerID,string"`
+		Payload *expr.Vars `json:"payload"`
+	}
+)
+
+// SetSessionID overrides generated session ID
+//
+// Used when session is restored from the storage
+func SetSessionID(sessionID uint64) SessionOpt {
+	return func(s *Session) {
+		s.id = sessionID
+	}
+}
+
+// SuspendedStates returns serializable copies of all delayed and prompted states
+//
+// States that are suspended inside a loop can not be serialized
+// (iterators are not serializable) and cause an error
+func (s *Session) SuspendedStates() (out []*SuspendedState, err error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	out = make([]*SuspendedState, 0, len(s.delayed)+len(s.prompted))
+
+	for _, d := range s.delayed {
+		ss, err := suspendState(d.state)
+		if err != nil {
+			return nil, err
+		}
+
+		at := d.resumeAt
+		ss.ResumeAt = &at
+		out = append(out, ss)
+	}
+
+	for _, p := range s.prompted {
+		ss, err := suspendState(p.state)
+		if err != nil {
+			return nil, err
+		}
+
+		ss.Prompt = &SuspendedPrompt{
+			Ref:     p.ref,
+			OwnerID: p.ownerId,
+			Payload: p.payload,
+		}
+
+		out = append(out, ss)
+	}
+
+	return out, nil
+}
+
+// RestoreSuspended reconstructs delayed and prompted states on the session
+//
+// Steps are resolved from session's graph; all referenced steps must exist
+func (s *Session) RestoreSuspended(ss ...*SuspendedState) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var (
+		lookup = func(stepID uint64) (Step, error) {
+			if stepID == 0 {
+				return nil, nil
+			}
+
+			if step := s.g.StepByID(stepID); step != nil {
+				return step, nil
+			}
+
+			return nil, fmt.Errorf("step %d not found", stepID)
+		}
+	)
+
+	for _, sus := range ss {
+		if sus == nil {
+			continue
+		}
+
+		st := &State{
+			stateId:   sus.StateID,
+			sessionId: s.id,
+			created:   sus.CreatedAt,
+			scope:     sus.Scope,
+			loops:     make([]Iterator, 0, 4),
+		}
+
+		if sus.OwnerID > 0 {
+			st.owner = auth.Authenticated(sus.OwnerID, sus.OwnerRoles...)
+		}
+
+		var err error
+		if st.step, err = lookup(sus.StepID); err != nil {
+			return fmt.Errorf("could not restore state %d: %w", sus.StateID, err)
+		} else if st.step == nil {
+			return fmt.Errorf("could not restore state %d: step is nil", sus.StateID)
+		}
+
+		if st.parent, err = lookup(sus.ParentID); err != nil {
+			return fmt.Errorf("could not restore state %d: %w", sus.StateID, err)
+		}
+
+		if st.errHandler, err = lookup(sus.ErrHandlerID); err != nil {
+			return fmt.Errorf("could not restore state %d: %w", sus.StateID, err)
+		}
+
+		switch {
+		case sus.Prompt != nil:
+			s.prompted[st.stateId] = &prompted{
+				payload: sus.Prompt.Payload,
+				ownerId: sus.Prompt.OwnerID,
+				state:   st,
+				ref:     sus.Prompt.Ref,
+			}
+
+		case sus.ResumeAt != nil:
+			s.delayed[st.stateId] = &delayed{
+				resumeAt: *sus.ResumeAt,
+				state:    st,
+			}
+
+		default:
+			return fmt.Errorf("could not restore state %d: neither delayed nor prompted", sus.StateID)
+		}
+	}
+
+	return nil
+}
+
+func suspendState(st *State) (*SuspendedState, error) {
+	if len(st.loops) > 0 {
+		return nil, fmt.Errorf("state %d is suspended inside a loop and can not be serialized", st.stateId)
+	}
+
+	ss := &SuspendedState{
+		StateID:   st.stateId,
+		CreatedAt: st.created,
+		Scope:     st.scope,
+	}
+
+	if st.step != nil {
+		ss.StepID = st.step.ID()
+	}
+
+	if st.parent != nil {
+		ss.ParentID = st.parent.ID()
+	}
+
+	if st.errHandler != nil {
+		ss.ErrHandlerID = st.errHandler.ID()
+	}
+
+	if st.owner != nil {
+		ss.OwnerID = st.owner.Identity()
+		ss.OwnerRoles = st.owner.Roles()
+	}
+
+	return ss, nil
+}
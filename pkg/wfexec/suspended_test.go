@@ -0,0 +1,93 @@
+package wfexec
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_SuspendAndRestore(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		start  = &sesTestStep{name: "start"}
+		prompt = &sesTestStep{name: "prompt", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if !r.Input.Has("answer") {
+				return Prompt(42, "question", nil), nil
+			}
+
+			out := &expr.Vars{}
+			r.Input.Copy(out, "answer")
+			return out, nil
+		}}
+
+		suspended []*SuspendedState
+	)
+
+	ctx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+	defer cancelFn()
+
+	start.SetID(1)
+	prompt.SetID(2)
+	wf.AddStep(start, prompt)
+	wf.AddStep(prompt)
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Exec(auth.SetIdentityToContext(ctx, auth.Authenticated(42)), start, nil))
+		req.NoError(ses.WaitUntil(ctx, SessionPrompted))
+
+		ss, err := ses.SuspendedStates()
+		req.NoError(err)
+		req.Len(ss, 1)
+		req.NotNil(ss[0].Prompt)
+		req.Equal(uint64(2), ss[0].StepID)
+		req.Equal(uint64(1), ss[0].ParentID)
+
+		// make sure it survives serialization
+		enc, err := json.Marshal(ss)
+		req.NoError(err)
+		req.NoError(json.Unmarshal(enc, &suspended))
+		ses.Stop()
+	}
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetSessionID(4242))
+		req.NoError(ses.RestoreSuspended(suspended...))
+		req.Equal(SessionPrompted, ses.Status())
+
+		pp := ses.UserPendingPrompts(42)
+		req.Len(pp, 1)
+		req.Equal(uint64(4242), pp[0].SessionID)
+		req.Equal("question", pp[0].Ref)
+
+		input := &expr.Vars{}
+		_ = input.Set("answer", "yes")
+		_, err := ses.Resume(auth.SetIdentityToContext(ctx, auth.Authenticated(42)), pp[0].StateID, input)
+		req.NoError(err)
+
+		req.NoError(ses.WaitUntil(ctx, SessionCompleted, SessionFailed))
+		req.NoError(ses.Error())
+		req.Equal("yes", expr.Must(expr.Select(ses.Result(), "answer")).Get())
+		req.Equal("/start", expr.Must(expr.Select(ses.Result(), "path")).Get())
+	}
+}
+
+func TestSession_RestoreUnknownStep(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		ses = NewSession(ctx, NewGraph())
+	)
+
+	defer ses.Stop()
+
+	req.Error(ses.RestoreSuspended(&SuspendedState{StateID: 1, StepID: 2, ResumeAt: now()}))
+}
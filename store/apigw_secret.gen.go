@@ -0,0 +1,78 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/apigw_secret.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	ApigwSecrets interface {
+		SearchApigwSecrets(ctx context.Context, f types.ApigwSecretFilter) (types.ApigwSecretSet, types.ApigwSecretFilter, error)
+		LookupApigwSecretByID(ctx context.Context, id uint64) (*types.ApigwSecret, error)
+		LookupApigwSecretByHandle(ctx context.Context, handle string) (*types.ApigwSecret, error)
+
+		CreateApigwSecret(ctx context.Context, rr ...*types.ApigwSecret) error
+
+		UpdateApigwSecret(ctx context.Context, rr ...*types.ApigwSecret) error
+
+		DeleteApigwSecret(ctx context.Context, rr ...*types.ApigwSecret) error
+		DeleteApigwSecretByID(ctx context.Context, ID uint64) error
+
+		TruncateApigwSecrets(ctx context.Context) error
+	}
+)
+
+var _ *types.ApigwSecret
+var _ context.Context
+
+// SearchApigwSecrets returns all matching ApigwSecrets from store
+func SearchApigwSecrets(ctx context.Context, s ApigwSecrets, f types.ApigwSecretFilter) (types.ApigwSecretSet, types.ApigwSecretFilter, error) {
+	return s.SearchApigwSecrets(ctx, f)
+}
+
+// LookupApigwSecretByID searches for secret by ID
+//
+// It also returns deleted secrets.
+func LookupApigwSecretByID(ctx context.Context, s ApigwSecrets, id uint64) (*types.ApigwSecret, error) {
+	return s.LookupApigwSecretByID(ctx, id)
+}
+
+// LookupApigwSecretByHandle searches for secret by handle
+//
+// It returns only valid secrets (not deleted)
+func LookupApigwSecretByHandle(ctx context.Context, s ApigwSecrets, handle string) (*types.ApigwSecret, error) {
+	return s.LookupApigwSecretByHandle(ctx, handle)
+}
+
+// CreateApigwSecret creates one or more ApigwSecrets in store
+func CreateApigwSecret(ctx context.Context, s ApigwSecrets, rr ...*types.ApigwSecret) error {
+	return s.CreateApigwSecret(ctx, rr...)
+}
+
+// UpdateApigwSecret updates one or more (existing) ApigwSecrets in store
+func UpdateApigwSecret(ctx context.Context, s ApigwSecrets, rr ...*types.ApigwSecret) error {
+	return s.UpdateApigwSecret(ctx, rr...)
+}
+
+// DeleteApigwSecret Deletes one or more ApigwSecrets from store
+func DeleteApigwSecret(ctx context.Context, s ApigwSecrets, rr ...*types.ApigwSecret) error {
+	return s.DeleteApigwSecret(ctx, rr...)
+}
+
+// DeleteApigwSecretByID Deletes ApigwSecret from store
+func DeleteApigwSecretByID(ctx context.Context, s ApigwSecrets, ID uint64) error {
+	return s.DeleteApigwSecretByID(ctx, ID)
+}
+
+// TruncateApigwSecrets Deletes all ApigwSecrets from store
+func TruncateApigwSecrets(ctx context.Context, s ApigwSecrets) error {
+	return s.TruncateApigwSecrets(ctx)
+}
@@ -0,0 +1,84 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/compose_record_import_sessions.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+type (
+	ComposeRecordImportSessions interface {
+		SearchComposeRecordImportSessions(ctx context.Context, f types.RecordImportSessionFilter) (types.RecordImportSessionSet, types.RecordImportSessionFilter, error)
+		LookupComposeRecordImportSessionByID(ctx context.Context, id uint64) (*types.RecordImportSession, error)
+
+		CreateComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) error
+
+		UpdateComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) error
+
+		DeleteComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) error
+		DeleteComposeRecordImportSessionByID(ctx context.Context, ID uint64) error
+
+		TruncateComposeRecordImportSessions(ctx context.Context) error
+
+		// Additional custom functions
+
+		// ClaimComposeRecordImportSession (custom function)
+		ClaimComposeRecordImportSession(ctx context.Context, _ses *types.RecordImportSession, _f types.RecordImportSessionFilter) (bool, error)
+
+		// PartialComposeRecordImportSessionUpdate (custom function)
+		PartialComposeRecordImportSessionUpdate(ctx context.Context, _onlyColumns []string, _rr ...*types.RecordImportSession) error
+	}
+)
+
+var _ *types.RecordImportSession
+var _ context.Context
+
+// SearchComposeRecordImportSessions returns all matching ComposeRecordImportSessions from store
+func SearchComposeRecordImportSessions(ctx context.Context, s ComposeRecordImportSessions, f types.RecordImportSessionFilter) (types.RecordImportSessionSet, types.RecordImportSessionFilter, error) {
+	return s.SearchComposeRecordImportSessions(ctx, f)
+}
+
+// LookupComposeRecordImportSessionByID searches for compose record import session by ID
+func LookupComposeRecordImportSessionByID(ctx context.Context, s ComposeRecordImportSessions, id uint64) (*types.RecordImportSession, error) {
+	return s.LookupComposeRecordImportSessionByID(ctx, id)
+}
+
+// CreateComposeRecordImportSession creates one or more ComposeRecordImportSessions in store
+func CreateComposeRecordImportSession(ctx context.Context, s ComposeRecordImportSessions, rr ...*types.RecordImportSession) error {
+	return s.CreateComposeRecordImportSession(ctx, rr...)
+}
+
+// UpdateComposeRecordImportSession updates one or more (existing) ComposeRecordImportSessions in store
+func UpdateComposeRecordImportSession(ctx context.Context, s ComposeRecordImportSessions, rr ...*types.RecordImportSession) error {
+	return s.UpdateComposeRecordImportSession(ctx, rr...)
+}
+
+// DeleteComposeRecordImportSession Deletes one or more ComposeRecordImportSessions from store
+func DeleteComposeRecordImportSession(ctx context.Context, s ComposeRecordImportSessions, rr ...*types.RecordImportSession) error {
+	return s.DeleteComposeRecordImportSession(ctx, rr...)
+}
+
+// DeleteComposeRecordImportSessionByID Deletes ComposeRecordImportSession from store
+func DeleteComposeRecordImportSessionByID(ctx context.Context, s ComposeRecordImportSessions, ID uint64) error {
+	return s.DeleteComposeRecordImportSessionByID(ctx, ID)
+}
+
+// TruncateComposeRecordImportSessions Deletes all ComposeRecordImportSessions from store
+func TruncateComposeRecordImportSessions(ctx context.Context, s ComposeRecordImportSessions) error {
+	return s.TruncateComposeRecordImportSessions(ctx)
+}
+
+func ClaimComposeRecordImportSession(ctx context.Context, s ComposeRecordImportSessions, _ses *types.RecordImportSession, _f types.RecordImportSessionFilter) (bool, error) {
+	return s.ClaimComposeRecordImportSession(ctx, _ses, _f)
+}
+
+func PartialComposeRecordImportSessionUpdate(ctx context.Context, s ComposeRecordImportSessions, _onlyColumns []string, _rr ...*types.RecordImportSession) error {
+	return s.PartialComposeRecordImportSessionUpdate(ctx, _onlyColumns, _rr...)
+}
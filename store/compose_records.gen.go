@@ -38,8 +38,14 @@ type (
 		// ComposeRecordDatasource (custom function)
 		ComposeRecordDatasource(ctx context.Context, _mod *types.Module, _ld *report.LoadStepDefinition) (report.Datasource, error)
 
+		// UpgradeComposeRecordPartition (custom function)
+		UpgradeComposeRecordPartition(ctx context.Context, _mod *types.Module) error
+
 		// PartialComposeRecordValueUpdate (custom function)
 		PartialComposeRecordValueUpdate(ctx context.Context, _mod *types.Module, _values ...*types.RecordValue) error
+
+		// LockComposeRecords (custom function)
+		LockComposeRecords(ctx context.Context, _mod *types.Module) error
 	}
 )
 
@@ -95,6 +101,14 @@ func ComposeRecordDatasource(ctx context.Context, s ComposeRecords, _mod *types.
 	return s.ComposeRecordDatasource(ctx, _mod, _ld)
 }
 
+func UpgradeComposeRecordPartition(ctx context.Context, s ComposeRecords, _mod *types.Module) error {
+	return s.UpgradeComposeRecordPartition(ctx, _mod)
+}
+
 func PartialComposeRecordValueUpdate(ctx context.Context, s ComposeRecords, _mod *types.Module, _values ...*types.RecordValue) error {
 	return s.PartialComposeRecordValueUpdate(ctx, _mod, _values...)
 }
+
+func LockComposeRecords(ctx context.Context, s ComposeRecords, _mod *types.Module) error {
+	return s.LockComposeRecords(ctx, _mod)
+}
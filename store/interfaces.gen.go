@@ -7,6 +7,7 @@ package store
 //  - store/actionlog.yaml
 //  - store/apigw_filter.yaml
 //  - store/apigw_route.yaml
+//  - store/apigw_secret.yaml
 //  - store/applications.yaml
 //  - store/attachments.yaml
 //  - store/auth_clients.yaml
@@ -22,6 +23,8 @@ package store
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_import_sessions.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/compose_record_values.yaml
 //  - store/compose_records.yaml
 //  - store/credentials.yaml
@@ -53,6 +56,7 @@ type (
 		Actionlogs
 		ApigwFilters
 		ApigwRoutes
+		ApigwSecrets
 		Applications
 		Attachments
 		AuthClients
@@ -68,6 +72,8 @@ type (
 		ComposeModules
 		ComposeNamespaces
 		ComposePages
+		ComposeRecordImportSessions
+		ComposeRecordRevisions
 		ComposeRecordValues
 		ComposeRecords
 		Credentials
@@ -16,6 +16,7 @@ import (
 type (
 	MessagebusQueueMessages interface {
 		SearchMessagebusQueueMessages(ctx context.Context, f messagebus.QueueMessageFilter) (messagebus.QueueMessageSet, messagebus.QueueMessageFilter, error)
+		LookupMessagebusQueueMessageByID(ctx context.Context, id uint64) (*messagebus.QueueMessage, error)
 
 		CreateMessagebusQueueMessage(ctx context.Context, rr ...*messagebus.QueueMessage) error
 
@@ -36,6 +37,11 @@ func SearchMessagebusQueueMessages(ctx context.Context, s MessagebusQueueMessage
 	return s.SearchMessagebusQueueMessages(ctx, f)
 }
 
+// LookupMessagebusQueueMessageByID searches for queue message by ID
+func LookupMessagebusQueueMessageByID(ctx context.Context, s MessagebusQueueMessages, id uint64) (*messagebus.QueueMessage, error) {
+	return s.LookupMessagebusQueueMessageByID(ctx, id)
+}
+
 // CreateMessagebusQueueMessage creates one or more MessagebusQueueMessages in store
 func CreateMessagebusQueueMessage(ctx context.Context, s MessagebusQueueMessages, rr ...*messagebus.QueueMessage) error {
 	return s.CreateMessagebusQueueMessage(ctx, rr...)
@@ -44,6 +44,8 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.ErrorHandler = errorHandler
 	cfg.UpsertBuilder = UpsertBuilder
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.SqlJsonValueExtractor = sqlJsonValueExtractor
+	cfg.TableUpgrader = tableUpgrader
 	cfg.SqlSortHandler = SqlSortHandler
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
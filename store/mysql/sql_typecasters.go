@@ -39,3 +39,8 @@ func fieldToColumnTypeCaster(field rdbms.ModuleFieldTypeDetector, ident string)
 	fc := fmt.Sprintf(fcp, ident)
 	return fmt.Sprintf(tcp, fc), fcp, tcp, nil
 }
+
+// sqlJsonValueExtractor extracts a single value from JSON encoded record values
+func sqlJsonValueExtractor(column, name string, place int) string {
+	return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s[%d]'))", column, name, place)
+}
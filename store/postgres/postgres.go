@@ -43,6 +43,8 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.ErrorHandler = errorHandler
 	cfg.SqlFunctionHandler = sqlFunctionHandler
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.SqlJsonValueExtractor = sqlJsonValueExtractor
+	cfg.TableUpgrader = tableUpgrader
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
 		return nil, err
@@ -451,6 +451,7 @@ func (s Store) internalAutomationSessionRowScanner(row rowScanner) (res *types.S
 			&res.CompletedAt,
 			&res.SuspendedAt,
 			&res.Error,
+			&res.SuspendedStates,
 		)
 	}
 
@@ -504,6 +505,7 @@ func (Store) automationSessionColumns(aa ...string) []string {
 		alias + "completed_at",
 		alias + "suspended_at",
 		alias + "error",
+		alias + "suspended_states",
 	}
 }
 
@@ -533,20 +535,21 @@ func (Store) sortableAutomationSessionColumns() map[string]string {
 // func when rdbms.customEncoder=true
 func (s Store) internalAutomationSessionEncoder(res *types.Session) store.Payload {
 	return store.Payload{
-		"id":            res.ID,
-		"rel_workflow":  res.WorkflowID,
-		"event_type":    res.EventType,
-		"resource_type": res.ResourceType,
-		"status":        res.Status,
-		"input":         res.Input,
-		"output":        res.Output,
-		"stacktrace":    res.Stacktrace,
-		"created_by":    res.CreatedBy,
-		"created_at":    res.CreatedAt,
-		"purge_at":      res.PurgeAt,
-		"completed_at":  res.CompletedAt,
-		"suspended_at":  res.SuspendedAt,
-		"error":         res.Error,
+		"id":               res.ID,
+		"rel_workflow":     res.WorkflowID,
+		"event_type":       res.EventType,
+		"resource_type":    res.ResourceType,
+		"status":           res.Status,
+		"input":            res.Input,
+		"output":           res.Output,
+		"stacktrace":       res.Stacktrace,
+		"created_by":       res.CreatedBy,
+		"created_at":       res.CreatedAt,
+		"purge_at":         res.PurgeAt,
+		"completed_at":     res.CompletedAt,
+		"suspended_at":     res.SuspendedAt,
+		"error":            res.Error,
+		"suspended_states": res.SuspendedStates,
 	}
 }
 
@@ -219,7 +219,108 @@ func (r *recordDatasource) Group(d report.GroupDefinition, name string) (bool, e
 	return true, nil
 }
 
-// @todo add Transform
+// Transform instructs the datasource to provide transformed output
+//
+// Computed columns and filtering are pushed down to the database;
+// pivoting is left to the report engine.
+func (r *recordDatasource) Transform(d report.TransformDefinition, name string) (bool, error) {
+	if d.Pivot != nil || d.Unpivot != nil {
+		return false, nil
+	}
+
+	defer func() {
+		r.nestLevel++
+		r.nestLabel = "transform"
+		r.name = name
+	}()
+
+	var (
+		q   = squirrel.Select("*")
+		err error
+	)
+
+	auxLevelColumns := r.levelColumns
+	r.levelColumns = make(map[string]string)
+	for k, v := range auxLevelColumns {
+		r.levelColumns[k] = v
+	}
+	transformCols := append(report.FrameColumnSet{}, r.cols...)
+
+	// computed columns
+	for _, k := range d.Columns {
+		if _, ok := auxLevelColumns[k.Name]; ok {
+			return false, fmt.Errorf("column %s already exists on level %d", k.Name, r.nestLevel)
+		}
+
+		// - validate columns & functions
+		err = k.Def.Traverse(func(n *qlng.ASTNode) (bool, *qlng.ASTNode, error) {
+			if n.Symbol != "" {
+				if _, ok := auxLevelColumns[n.Symbol]; !ok {
+					return false, nil, fmt.Errorf("column %s does not exist on level %d", n.Symbol, r.nestLevel)
+				}
+			}
+
+			return true, n, nil
+		})
+		if err != nil {
+			return false, err
+		}
+
+		// - aggregation is done by the group step
+		if r.isAggregated(k.Def.ASTNode) {
+			return false, fmt.Errorf("transform column %s aggregates data", k.Name)
+		}
+
+		// AST transformation tasks
+		tr := r.store.ASTTransformer(k.Def.ASTNode)
+		outType, err := tr.Analyze(auxLevelColumns)
+		if err != nil {
+			return false, err
+		}
+		if k.Kind != "" {
+			outType = k.Kind
+		}
+
+		// - prepare frame col. definition
+		c := report.MakeColumnOfKind(outType)
+		c.Name = k.Name
+		c.Label = k.Label
+		if c.Label == "" {
+			c.Label = c.Name
+		}
+		transformCols = append(transformCols, c)
+		r.levelColumns[k.Name] = outType
+
+		// - SQL things
+		q = q.Column(squirrel.Alias(tr, c.Name))
+	}
+
+	r.cols = transformCols
+	r.q = q.FromSelect(r.q, fmt.Sprintf("l%d", r.nestLevel))
+
+	// filtering over the computed columns needs an additional level
+	if d.Filter != nil && d.Filter.ASTNode != nil {
+		err = d.Filter.Traverse(func(n *qlng.ASTNode) (bool, *qlng.ASTNode, error) {
+			if n.Symbol != "" {
+				if _, ok := r.levelColumns[n.Symbol]; !ok {
+					return false, nil, fmt.Errorf("column %s does not exist on level %d", n.Symbol, r.nestLevel)
+				}
+			}
+
+			return true, n, nil
+		})
+		if err != nil {
+			return false, err
+		}
+
+		r.nestLevel++
+		r.q = squirrel.Select("*").
+			FromSelect(r.q, fmt.Sprintf("l%d", r.nestLevel)).
+			Where(r.store.ASTTransformer(d.Filter.ASTNode))
+	}
+
+	return true, nil
+}
 
 func (r *recordDatasource) Partition(partitionSize uint, partitionCol string) (bool, error) {
 	if r.partitioned {
@@ -266,8 +367,12 @@ func (r *recordDatasource) partition(ctx context.Context, partitionSize uint, pa
 
 	// the sort is already defined when partitioning so it's unneeded here
 	q = squirrel.Select("*").
-		FromSelect(prt, "partition_wrap").
-		Where(fmt.Sprintf("pp_rank <= %d", partitionSize))
+		FromSelect(prt, "partition_wrap")
+
+	// partition size of 0 doesn't limit the partitions
+	if partitionSize > 0 {
+		q = q.Where(fmt.Sprintf("pp_rank <= %d", partitionSize))
+	}
 
 	return r.load(ctx, def, q)
 }
@@ -0,0 +1,587 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/compose_record_import_sessions.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchComposeRecordImportSessions returns all matching rows
+//
+// This function calls convertComposeRecordImportSessionFilter with the given
+// types.RecordImportSessionFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchComposeRecordImportSessions(ctx context.Context, f types.RecordImportSessionFilter) (types.RecordImportSessionSet, types.RecordImportSessionFilter, error) {
+	var (
+		err error
+		set []*types.RecordImportSession
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertComposeRecordImportSessionFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableComposeRecordImportSessionColumns()); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfComposeRecordImportSessions(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			f.Check,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfComposeRecordImportSessions collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfComposeRecordImportSessions(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.RecordImportSession) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.RecordImportSession, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.RecordImportSession
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.RecordImportSession, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryComposeRecordImportSessions(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectComposeRecordImportSessionCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectComposeRecordImportSessionCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectComposeRecordImportSessionCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryComposeRecordImportSessions queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryComposeRecordImportSessions(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.RecordImportSession) (bool, error),
+) ([]*types.RecordImportSession, error) {
+	var (
+		tmp = make([]*types.RecordImportSession, 0, DefaultSliceCapacity)
+		set = make([]*types.RecordImportSession, 0, DefaultSliceCapacity)
+		res *types.RecordImportSession
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalComposeRecordImportSessionRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupComposeRecordImportSessionByID searches for compose record import session by ID
+func (s Store) LookupComposeRecordImportSessionByID(ctx context.Context, id uint64) (*types.RecordImportSession, error) {
+	return s.execLookupComposeRecordImportSession(ctx, squirrel.Eq{
+		s.preprocessColumn("cris.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateComposeRecordImportSession creates one or more rows in compose_record_import_session table
+func (s Store) CreateComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) (err error) {
+	for _, res := range rr {
+		err = s.checkComposeRecordImportSessionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateComposeRecordImportSessions(ctx, s.internalComposeRecordImportSessionEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateComposeRecordImportSession updates one or more existing rows in compose_record_import_session
+func (s Store) UpdateComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) error {
+	return s.partialComposeRecordImportSessionUpdate(ctx, nil, rr...)
+}
+
+// partialComposeRecordImportSessionUpdate updates one or more existing rows in compose_record_import_session
+func (s Store) partialComposeRecordImportSessionUpdate(ctx context.Context, onlyColumns []string, rr ...*types.RecordImportSession) (err error) {
+	for _, res := range rr {
+		err = s.checkComposeRecordImportSessionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateComposeRecordImportSessions(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("cris.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalComposeRecordImportSessionEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// DeleteComposeRecordImportSession Deletes one or more rows from compose_record_import_session table
+func (s Store) DeleteComposeRecordImportSession(ctx context.Context, rr ...*types.RecordImportSession) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteComposeRecordImportSessions(ctx, squirrel.Eq{
+			s.preprocessColumn("cris.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteComposeRecordImportSessionByID Deletes row from the compose_record_import_session table
+func (s Store) DeleteComposeRecordImportSessionByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteComposeRecordImportSessions(ctx, squirrel.Eq{
+		s.preprocessColumn("cris.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateComposeRecordImportSessions Deletes all rows from the compose_record_import_session table
+func (s Store) TruncateComposeRecordImportSessions(ctx context.Context) error {
+	return s.Truncate(ctx, s.composeRecordImportSessionTable())
+}
+
+// execLookupComposeRecordImportSession prepares ComposeRecordImportSession query and executes it,
+// returning types.RecordImportSession (or error)
+func (s Store) execLookupComposeRecordImportSession(ctx context.Context, cnd squirrel.Sqlizer) (res *types.RecordImportSession, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.composeRecordImportSessionsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalComposeRecordImportSessionRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateComposeRecordImportSessions updates all matched (by cnd) rows in compose_record_import_session with given data
+func (s Store) execCreateComposeRecordImportSessions(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.composeRecordImportSessionTable()).SetMap(payload))
+}
+
+// execUpdateComposeRecordImportSessions updates all matched (by cnd) rows in compose_record_import_session with given data
+func (s Store) execUpdateComposeRecordImportSessions(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.composeRecordImportSessionTable("cris")).Where(cnd).SetMap(set))
+}
+
+// execDeleteComposeRecordImportSessions Deletes all matched (by cnd) rows in compose_record_import_session with given data
+func (s Store) execDeleteComposeRecordImportSessions(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.composeRecordImportSessionTable("cris")).Where(cnd))
+}
+
+func (s Store) internalComposeRecordImportSessionRowScanner(row rowScanner) (res *types.RecordImportSession, err error) {
+	res = &types.RecordImportSession{}
+
+	if _, has := s.config.RowScanners["composeRecordImportSession"]; has {
+		scanner := s.config.RowScanners["composeRecordImportSession"].(func(_ rowScanner, _ *types.RecordImportSession) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.NamespaceID,
+			&res.ModuleID,
+			&res.OwnedBy,
+			&res.Name,
+			&res.ContentType,
+			&res.Data,
+			&res.Status,
+			&res.Mode,
+			&res.OnError,
+			&res.DryRun,
+			&res.Fields,
+			&res.Key,
+			&res.Progress,
+			&res.Rejected,
+			&res.CreatedAt,
+			&res.UpdatedAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan composeRecordImportSession db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryComposeRecordImportSessions returns squirrel.SelectBuilder with set table and all columns
+func (s Store) composeRecordImportSessionsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.composeRecordImportSessionTable("cris"), s.composeRecordImportSessionColumns("cris")...)
+}
+
+// composeRecordImportSessionTable name of the db table
+func (Store) composeRecordImportSessionTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "compose_record_import_session" + alias
+}
+
+// ComposeRecordImportSessionColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) composeRecordImportSessionColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_namespace",
+		alias + "rel_module",
+		alias + "owned_by",
+		alias + "name",
+		alias + "content_type",
+		alias + "data",
+		alias + "status",
+		alias + "mode",
+		alias + "on_error",
+		alias + "dry_run",
+		alias + "fields",
+		alias + "key_column",
+		alias + "progress",
+		alias + "rejected",
+		alias + "created_at",
+		alias + "updated_at",
+	}
+}
+
+// {true true false true true true}
+
+// sortableComposeRecordImportSessionColumns returns all ComposeRecordImportSession columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableComposeRecordImportSessionColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "status": "status", "created_at": "created_at",
+		"createdat":  "created_at",
+		"updated_at": "updated_at",
+		"updatedat":  "updated_at",
+	}
+}
+
+// internalComposeRecordImportSessionEncoder encodes fields from types.RecordImportSession to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeComposeRecordImportSession
+// func when rdbms.customEncoder=true
+func (s Store) internalComposeRecordImportSessionEncoder(res *types.RecordImportSession) store.Payload {
+	return store.Payload{
+		"id":            res.ID,
+		"rel_namespace": res.NamespaceID,
+		"rel_module":    res.ModuleID,
+		"owned_by":      res.OwnedBy,
+		"name":          res.Name,
+		"content_type":  res.ContentType,
+		"data":          res.Data,
+		"status":        res.Status,
+		"mode":          res.Mode,
+		"on_error":      res.OnError,
+		"dry_run":       res.DryRun,
+		"fields":        res.Fields,
+		"key_column":    res.Key,
+		"progress":      res.Progress,
+		"rejected":      res.Rejected,
+		"created_at":    res.CreatedAt,
+		"updated_at":    res.UpdatedAt,
+	}
+}
+
+// collectComposeRecordImportSessionCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectComposeRecordImportSessionCursorValues(res *types.RecordImportSession, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "status":
+					cursor.Set(c.Column, res.Status, c.Descending)
+
+				case "created_at":
+					cursor.Set(c.Column, res.CreatedAt, c.Descending)
+
+				case "updated_at":
+					cursor.Set(c.Column, res.UpdatedAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkComposeRecordImportSessionConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkComposeRecordImportSessionConstraints(ctx context.Context, res *types.RecordImportSession) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	return nil
+}
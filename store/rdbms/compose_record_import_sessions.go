@@ -0,0 +1,75 @@
+package rdbms
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+func (s Store) convertComposeRecordImportSessionFilter(f types.RecordImportSessionFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.composeRecordImportSessionsSelectBuilder()
+
+	if f.NamespaceID > 0 {
+		query = query.Where("cris.rel_namespace = ?", f.NamespaceID)
+	}
+
+	if f.ModuleID > 0 {
+		query = query.Where("cris.rel_module = ?", f.ModuleID)
+	}
+
+	if f.OwnedBy > 0 {
+		query = query.Where("cris.owned_by = ?", f.OwnedBy)
+	}
+
+	if len(f.Status) > 0 {
+		query = query.Where(squirrel.Eq{"cris.status": f.Status})
+	}
+
+	if f.UpdatedBefore != nil {
+		query = query.Where(squirrel.Lt{"COALESCE(cris.updated_at, cris.created_at)": f.UpdatedBefore})
+	}
+
+	return
+}
+
+// ClaimComposeRecordImportSession updates status and update time of the session
+// only if it still matches the status and update time conditions of the filter
+//
+// Returns false when session was claimed by someone else in the meantime
+func (s Store) ClaimComposeRecordImportSession(ctx context.Context, ses *types.RecordImportSession, f types.RecordImportSessionFilter) (bool, error) {
+	upd := s.UpdateBuilder(s.composeRecordImportSessionTable()).
+		Set("status", ses.Status).
+		Set("updated_at", ses.UpdatedAt).
+		Where(squirrel.Eq{"id": ses.ID})
+
+	if len(f.Status) > 0 {
+		upd = upd.Where(squirrel.Eq{"status": f.Status})
+	}
+
+	if f.UpdatedBefore != nil {
+		upd = upd.Where(squirrel.Lt{"COALESCE(updated_at, created_at)": f.UpdatedBefore})
+	}
+
+	query, args, err := upd.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, store.HandleError(err, s.config.ErrorHandler)
+	}
+
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// PartialComposeRecordImportSessionUpdate updates only the given columns of the sessions
+//
+// Running import stores its progress often and should not
+// rewrite the uploaded file (data column) every time
+func (s Store) PartialComposeRecordImportSessionUpdate(ctx context.Context, onlyColumns []string, rr ...*types.RecordImportSession) error {
+	return s.partialComposeRecordImportSessionUpdate(ctx, onlyColumns, rr...)
+}
@@ -0,0 +1,604 @@
+package rdbms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/ddl"
+	"go.uber.org/zap"
+)
+
+// Partitioned records
+//
+// Records of modules with enabled record partitioning are stored in a dedicated table
+// (see Schema.ComposeRecordPartition). Values are not separated into the record_value (key-value)
+// table but encoded as JSON ({"field": ["value", ...]}) and stored in the record_values column.
+//
+// Values of single-value fields with physicalColumn option are also stored in a dedicated
+// column (rv_<field name>) that can be used for efficient filtering and sorting.
+
+const (
+	composeRecordPartitionValuesColumn = "record_values"
+)
+
+var (
+	composeRecordPartitionIdent = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// default partition table names (compose_record_<moduleID>)
+	composeRecordPartitionDefaultTable = regexp.MustCompile(`^compose_record_[0-9]+$`)
+)
+
+// UpgradeComposeRecordPartition creates dedicated table for module's records
+//
+// For existing tables, missing physical columns (and their indexes) are added and
+// filled with values from JSON encoded record values.
+//
+// DDL statements are committed implicitly by some databases (MySQL) so this
+// should not run inside a transaction. Newly created table is removed
+// when any of the following steps fails
+func (s Store) UpgradeComposeRecordPartition(ctx context.Context, m *types.Module) (err error) {
+	if !isPartitioned(m) {
+		return nil
+	}
+
+	if err = validateComposeRecordPartition(m); err != nil {
+		return
+	}
+
+	if err = s.checkComposeRecordPartitionTable(ctx, m); err != nil {
+		return
+	}
+
+	if s.config.TableUpgrader == nil {
+		return fmt.Errorf("store does not support partitioned records")
+	}
+
+	var (
+		u      = s.config.TableUpgrader(s.log(ctx), &s)
+		t      = Schema{}.ComposeRecordPartition(m)
+		added  bool
+		exists bool
+	)
+
+	if exists, err = u.TableExists(ctx, t.Name); err != nil {
+		return
+	}
+
+	if err = u.CreateTable(ctx, t); err != nil {
+		return fmt.Errorf("could not create record partition table %s: %w", t.Name, err)
+	}
+
+	if !exists {
+		defer func() {
+			if err == nil {
+				return
+			}
+
+			if _, dErr := u.DropTable(ctx, t.Name); dErr != nil {
+				s.log(ctx).Error("could not remove record partition table", zap.String("table", t.Name), zap.Error(dErr))
+			}
+		}()
+	}
+
+	for _, f := range composeRecordPhysicalFields(m) {
+		var a bool
+		if a, err = u.AddColumn(ctx, t.Name, ddl.Columns(t.Columns).Get(composeRecordPhysicalColumn(f))); err != nil {
+			return fmt.Errorf("could not add physical column for field %q: %w", f.Name, err)
+		}
+
+		added = added || a
+	}
+
+	for _, i := range t.Indexes {
+		if _, err = u.CreateIndex(ctx, i); err != nil {
+			return fmt.Errorf("could not create index %s on record partition table %s: %w", i.Name, t.Name, err)
+		}
+	}
+
+	if added {
+		return s.refreshComposeRecordPhysicalColumns(ctx, m)
+	}
+
+	return nil
+}
+
+// refreshComposeRecordPhysicalColumns copies values from JSON encoded values
+// to all physical columns
+func (s Store) refreshComposeRecordPhysicalColumns(ctx context.Context, m *types.Module) error {
+	rr, err := s.QueryComposeRecords(ctx, m, s.partitionedComposeRecordsSelectBuilder(m), nil)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rr {
+		err = s.Exec(ctx, s.UpdateBuilder(s.composeRecordPartitionTable(m)).
+			Where(squirrel.Eq{"id": r.ID, "module_id": m.ID}).
+			SetMap(composeRecordPhysicalPayload(m, r.Values)))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Store) createPartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	var payload store.Payload
+	for _, res := range rr {
+		if payload, err = s.internalPartitionedComposeRecordEncoder(m, res); err != nil {
+			return
+		}
+
+		if err = s.Exec(ctx, s.InsertBuilder(s.composeRecordPartitionTable(m)).SetMap(payload)); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s Store) updatePartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	var payload store.Payload
+	for _, res := range rr {
+		if payload, err = s.internalPartitionedComposeRecordEncoder(m, res); err != nil {
+			return
+		}
+
+		err = s.Exec(ctx, s.UpdateBuilder(s.composeRecordPartitionTable(m)).
+			Where(squirrel.Eq{"id": res.ID, "module_id": m.ID}).
+			SetMap(payload.Skip("id")))
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s Store) upsertPartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	var (
+		payload store.Payload
+		upsert  squirrel.InsertBuilder
+	)
+
+	for _, res := range rr {
+		if payload, err = s.internalPartitionedComposeRecordEncoder(m, res); err != nil {
+			return
+		}
+
+		if upsert, err = s.config.UpsertBuilder(s.config, s.composeRecordPartitionTable(m), payload, "id"); err != nil {
+			return
+		}
+
+		if err = s.Exec(ctx, upsert); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s Store) lookupPartitionedComposeRecordByID(ctx context.Context, m *types.Module, id uint64) (res *types.Record, err error) {
+	row, err := s.QueryRow(ctx, s.partitionedComposeRecordsSelectBuilder(m).Where(squirrel.Eq{"crd.id": id}))
+	if err != nil {
+		return
+	}
+
+	if res, err = s.internalComposeRecordRowScanner(m, row); err != nil {
+		return
+	}
+
+	if err = s.partitionedComposeRecordPostLoadProcessor(ctx, m, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s Store) deletePartitionedComposeRecordByID(ctx context.Context, m *types.Module, ID uint64) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.composeRecordPartitionTable(m)).Where(squirrel.Eq{"id": ID, "module_id": m.ID}))
+}
+
+func (s Store) truncatePartitionedComposeRecords(ctx context.Context, m *types.Module) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.composeRecordPartitionTable(m)).Where(squirrel.Eq{"module_id": m.ID}))
+}
+
+// loads JSON encoded values for all records in the set
+func (s Store) partitionedComposeRecordPostLoadProcessor(ctx context.Context, m *types.Module, set ...*types.Record) (err error) {
+	if len(set) == 0 {
+		return nil
+	}
+
+	var (
+		q = s.SelectBuilder(s.composeRecordPartitionTable(m), "id", composeRecordPartitionValuesColumn).
+			Where(squirrel.Eq{"id": types.RecordSet(set).IDs(), "module_id": m.ID})
+
+		values = make(map[uint64]types.RecordValueSet, len(set))
+	)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id  uint64
+			enc []byte
+		)
+
+		if err = rows.Scan(&id, &enc); err != nil {
+			return
+		}
+
+		if values[id], err = decodeComposeRecordValues(m, id, enc); err != nil {
+			return fmt.Errorf("could not decode values of record %d: %w", id, err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for r := range set {
+		set[r].SetModule(m)
+		set[r].Values = values[set[r].ID]
+	}
+
+	return nil
+}
+
+// partitionedComposeRecordValueRefLookup searches for the first record that references the given value
+//
+// Physical columns are queried directly, JSON encoded values
+// are decoded and checked one by one
+func (s Store) partitionedComposeRecordValueRefLookup(ctx context.Context, m *types.Module, field string, ref uint64) (uint64, error) {
+	f := m.Fields.FindByName(field)
+	if f == nil {
+		return 0, fmt.Errorf("unknown field %q", field)
+	}
+
+	q := s.partitionedComposeRecordsSelectBuilder(m).
+		Where(squirrel.Eq{"crd.deleted_at": nil})
+
+	if isComposeRecordPhysicalField(f) {
+		q = q.Where(squirrel.Eq{"crd." + composeRecordPhysicalColumn(f): ref}).Limit(1)
+	}
+
+	rr, err := s.QueryComposeRecords(ctx, m, q, func(r *types.Record) (bool, error) {
+		for _, v := range r.Values.FilterByName(field) {
+			if v.Ref == ref {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	if err != nil || len(rr) == 0 {
+		return 0, err
+	}
+
+	return rr[0].ID, nil
+}
+
+// partialPartitionedComposeRecordValueUpdate sets values on records
+//
+// Each record is loaded, modified and updated
+func (s Store) partialPartitionedComposeRecordValueUpdate(ctx context.Context, m *types.Module, vv ...*types.RecordValue) (err error) {
+	var (
+		r *types.Record
+
+		// keeping record order as values were given
+		order   = make([]uint64, 0, len(vv))
+		byRecID = make(map[uint64]types.RecordValueSet)
+	)
+
+	for _, v := range vv {
+		if _, has := byRecID[v.RecordID]; !has {
+			order = append(order, v.RecordID)
+		}
+
+		byRecID[v.RecordID] = append(byRecID[v.RecordID], v)
+	}
+
+	for _, recordID := range order {
+		if r, err = s.lookupPartitionedComposeRecordByID(ctx, m, recordID); err != nil {
+			return
+		}
+
+		for _, v := range byRecID[recordID] {
+			r.Values = r.Values.Set(v)
+		}
+
+		if err = s.updatePartitionedComposeRecord(ctx, m, r); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+// castComposeRecordField returns query column (with and without type-casting) and type-cast template
+// for the given module field
+func (s Store) castComposeRecordField(m *types.Module, f *types.ModuleField, ident string) (full, col, tcp string, err error) {
+	if !isPartitioned(m) {
+		var fcp string
+		if full, fcp, tcp, err = s.config.CastModuleFieldToColumnType(f, ident); err != nil {
+			return
+		}
+
+		return full, fmt.Sprintf(fcp, ident), tcp, nil
+	}
+
+	var (
+		// type detector used for type-cast template
+		td ModuleFieldTypeDetector = f
+	)
+
+	switch {
+	case isComposeRecordPhysicalField(f):
+		col = "crd." + composeRecordPhysicalColumn(f)
+
+	case s.config.SqlJsonValueExtractor == nil:
+		return "", "", "", fmt.Errorf("field %q is not stored in a physical column and can not be used in filters or sorting", f.Name)
+
+	case !composeRecordPartitionIdent.MatchString(f.Name):
+		return "", "", "", fmt.Errorf("invalid field name %q", f.Name)
+
+	default:
+		col = s.config.SqlJsonValueExtractor("crd."+composeRecordPartitionValuesColumn, f.Name, 0)
+
+		if f.IsRef() {
+			// JSON encoded references are strings
+			td = mftd{numeric: true}
+		}
+	}
+
+	if _, _, tcp, err = s.config.CastModuleFieldToColumnType(td, ident); err != nil {
+		return
+	}
+
+	return fmt.Sprintf(tcp, col), col, tcp, nil
+}
+
+func (s Store) partitionedComposeRecordsSelectBuilder(m *types.Module) squirrel.SelectBuilder {
+	return s.SelectBuilder(s.composeRecordPartitionTable(m, "crd"), s.composeRecordColumns("crd")...).
+		Where(squirrel.Eq{"crd.module_id": m.ID})
+}
+
+func (s Store) composeRecordPartitionTable(m *types.Module, aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return m.RecordPartitionTable() + alias
+}
+
+// internalPartitionedComposeRecordEncoder encodes record, it's values and
+// values for all physical columns
+func (s Store) internalPartitionedComposeRecordEncoder(m *types.Module, res *types.Record) (store.Payload, error) {
+	enc, err := encodeComposeRecordValues(res.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := s.internalComposeRecordEncoder(res)
+	payload[composeRecordPartitionValuesColumn] = enc
+
+	for col, val := range composeRecordPhysicalPayload(m, res.Values) {
+		payload[col] = val
+	}
+
+	return payload, nil
+}
+
+// encodes record values into JSON object; values of each field are ordered by place
+//
+// Deleted values are omitted
+func encodeComposeRecordValues(vv types.RecordValueSet) ([]byte, error) {
+	var (
+		out = make(map[string][]string)
+	)
+
+	for _, v := range vv.GetClean() {
+		fv := out[v.Name]
+		for uint(len(fv)) <= v.Place {
+			fv = append(fv, "")
+		}
+
+		fv[v.Place] = v.Value
+		out[v.Name] = fv
+	}
+
+	return json.Marshal(out)
+}
+
+// decodes JSON encoded record values
+//
+// Values are ordered as fields on module; values of unknown fields are appended at the end
+func decodeComposeRecordValues(m *types.Module, recordID uint64, enc []byte) (vv types.RecordValueSet, err error) {
+	var (
+		aux   = make(map[string][]string)
+		names = make([]string, 0, len(m.Fields))
+	)
+
+	if len(enc) > 0 {
+		if err = json.Unmarshal(enc, &aux); err != nil {
+			return
+		}
+	}
+
+	for _, f := range m.Fields {
+		if _, has := aux[f.Name]; has {
+			names = append(names, f.Name)
+		}
+	}
+
+	if len(names) < len(aux) {
+		unknown := make([]string, 0, len(aux)-len(names))
+		for name := range aux {
+			if m.Fields.FindByName(name) == nil {
+				unknown = append(unknown, name)
+			}
+		}
+
+		sort.Strings(unknown)
+		names = append(names, unknown...)
+	}
+
+	vv = make(types.RecordValueSet, 0, len(aux))
+	for _, name := range names {
+		f := m.Fields.FindByName(name)
+		for place, value := range aux[name] {
+			v := &types.RecordValue{
+				RecordID: recordID,
+				Name:     name,
+				Value:    value,
+				Place:    uint(place),
+			}
+
+			if f != nil && f.IsRef() {
+				v.Ref, _ = strconv.ParseUint(value, 10, 64)
+			}
+
+			vv = append(vv, v)
+		}
+	}
+
+	return
+}
+
+// composeRecordPhysicalPayload returns values for all physical columns
+//
+// Empty values are stored as NULL
+func composeRecordPhysicalPayload(m *types.Module, vv types.RecordValueSet) store.Payload {
+	var (
+		payload = store.Payload{}
+	)
+
+	for _, f := range composeRecordPhysicalFields(m) {
+		var (
+			col = composeRecordPhysicalColumn(f)
+			v   = vv.GetClean().Get(f.Name, 0)
+		)
+
+		switch {
+		case v == nil:
+			payload[col] = nil
+		case f.IsRef():
+			payload[col] = v.Ref
+		default:
+			payload[col] = v.Value
+		}
+	}
+
+	return payload
+}
+
+// returns all fields that have values stored in physical columns
+func composeRecordPhysicalFields(m *types.Module) (ff types.ModuleFieldSet) {
+	for _, f := range m.Fields {
+		if isComposeRecordPhysicalField(f) {
+			ff = append(ff, f)
+		}
+	}
+
+	return
+}
+
+// only single-value fields can be stored in physical columns
+func isComposeRecordPhysicalField(f *types.ModuleField) bool {
+	return !f.Multi && f.Options.IsPhysicalColumn() && composeRecordPartitionIdent.MatchString(f.Name)
+}
+
+func composeRecordPhysicalColumn(f *types.ModuleField) string {
+	return composeRecordValueAliasPfx + f.Name
+}
+
+// indexes physical column; text values are indexed by prefix (MySQL can not index whole TEXT columns)
+func composeRecordPhysicalColumnIndex(f *types.ModuleField) func(*ddl.Table) {
+	var (
+		col = composeRecordPhysicalColumn(f)
+	)
+
+	if f.IsRef() {
+		return ddl.AddIndex(col, ddl.IColumn(col))
+	}
+
+	return ddl.AddIndex(col, ddl.IFieldFull(&ddl.IField{Field: col, Length: handleLength}))
+}
+
+func composeRecordPhysicalColumnDef(f *types.ModuleField) func(*ddl.Table) {
+	if f.IsRef() {
+		return ddl.ColumnDef(composeRecordPhysicalColumn(f), ddl.ColumnTypeIdentifier, ddl.Null)
+	}
+
+	return ddl.ColumnDef(composeRecordPhysicalColumn(f), ddl.ColumnTypeText, ddl.Null)
+}
+
+// checks if records of the module are stored in a dedicated table
+func isPartitioned(m *types.Module) bool {
+	return m != nil && m.Config.RecordPartition.Enabled
+}
+
+// validates partition table name
+//
+// Must be a valid identifier and must not be one of the tables from the schema;
+// names are compared case-insensitively as some databases ignore the case
+func validateComposeRecordPartition(m *types.Module) error {
+	var (
+		table = m.RecordPartitionTable()
+	)
+
+	if !composeRecordPartitionIdent.MatchString(table) {
+		return fmt.Errorf("invalid record partition table name %q", table)
+	}
+
+	for _, t := range (Schema{}).Tables() {
+		if strings.EqualFold(t.Name, table) {
+			return fmt.Errorf("record partition table name %q is reserved", table)
+		}
+	}
+
+	return nil
+}
+
+// checkComposeRecordPartitionTable makes sure partition table is not used by another module
+//
+// Default table names (compose_record_<moduleID>) are reserved for modules with that ID
+func (s Store) checkComposeRecordPartitionTable(ctx context.Context, m *types.Module) error {
+	var (
+		table = m.RecordPartitionTable()
+	)
+
+	if composeRecordPartitionDefaultTable.MatchString(strings.ToLower(table)) && !strings.EqualFold(table, fmt.Sprintf("compose_record_%d", m.ID)) {
+		return fmt.Errorf("record partition table name %q is reserved", table)
+	}
+
+	mm, _, err := s.SearchComposeModules(ctx, types.ModuleFilter{Deleted: filter.StateInclusive})
+	if err != nil {
+		return err
+	}
+
+	for _, o := range mm {
+		if o.ID != m.ID && strings.EqualFold(o.RecordPartitionTable(), table) {
+			return fmt.Errorf("record partition table %q is used by another module", table)
+		}
+	}
+
+	return nil
+}
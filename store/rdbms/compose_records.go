@@ -29,11 +29,11 @@ type (
 	}
 )
 
-func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.PagingCursor) squirrel.Sqlizer {
+func (s Store) buildComposeRecordsCursor(m *types.Module) func(cur *filter.PagingCursor) squirrel.Sqlizer {
 	return func(cur *filter.PagingCursor) squirrel.Sqlizer {
 		return builders.CursorCondition(cur, func(key string) (builders.KeyMap, error) {
 			if col, fd, is := isRealRecordCol(key); is {
-				_, _, tcp, _ := cfg.CastModuleFieldToColumnType(fd, key)
+				_, _, tcp, _ := s.config.CastModuleFieldToColumnType(fd, key)
 				// These values here won't be casted
 				return builders.KeyMap{
 					FieldCast:    col,
@@ -47,12 +47,11 @@ func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.Pa
 				return builders.KeyMap{}, fmt.Errorf("unknown module field %q used in a cursor", key)
 			}
 
-			_, fcp, tcp, err := cfg.CastModuleFieldToColumnType(f, f.Name)
+			_, fc, tcp, err := s.castComposeRecordField(m, f, key)
 			if err != nil {
 				return builders.KeyMap{}, err
 			}
 
-			fc := fmt.Sprintf(fcp, key)
 			tc := fmt.Sprintf(tcp, fc)
 			rr := builders.KeyMap{
 				FieldCast:    fc,
@@ -65,11 +64,6 @@ func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.Pa
 	}
 }
 
-// @todo support for partitioned records (records are partitioned into multiple record tables
-//       in this case, values are no longer separated into record_value (key-value) table but encoded as JSON
-// @todo support for partitioned record with (optional) physical columns for record values
-//       physical columns are part of module-field configuration
-
 // SearchComposeRecords returns all matching ComposeRecords from store
 func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f types.RecordFilter) (types.RecordSet, types.RecordFilter, error) {
 	var (
@@ -109,11 +103,11 @@ func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f type
 
 		// Prevent sorting over multi-value fields
 		//
-		// Due to how values are currently stored, this causes duplication.
-		// For now, we'll prevent this and address in future releases.
+		// Due to how values are stored in the record_value table, this causes duplication.
+		// Partitioned records are sorted by the first value.
 		for _, s := range f.Sort {
 			f := m.Fields.FindByName(s.Column)
-			if f != nil && f.Multi {
+			if f != nil && f.Multi && !isPartitioned(m) {
 				return fmt.Errorf("not allowed to sort by multi-value fields: %s", s.Column)
 			}
 		}
@@ -138,7 +132,7 @@ func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f type
 			ctx, m, q,
 			f.Sort, f.PageCursor, f.Limit,
 			f.Check,
-			buildComposeRecordsCursor(s.config, m),
+			s.buildComposeRecordsCursor(m),
 		)
 
 		if err != nil {
@@ -154,7 +148,7 @@ func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f type
 			if f.Limit > 0 && uint(len(set)) == f.Limit {
 				// Build page navigation ONLY when limit is set and
 				// there are less items fetched then requested limit
-				if nav, err := s.composeRecordsPageNavigation(ctx, m, q, f, f.Sort, buildComposeRecordsCursor(s.config, m)); err != nil {
+				if nav, err := s.composeRecordsPageNavigation(ctx, m, q, f, f.Sort, s.buildComposeRecordsCursor(m)); err != nil {
 					return err
 				} else {
 					f.Total = nav.Total
@@ -306,6 +300,10 @@ func (s Store) composeRecordsPageNavigation(
 // LookupComposeRecordByID searches for compose record by ID
 // It returns compose record even if deleted
 func (s Store) LookupComposeRecordByID(ctx context.Context, m *types.Module, id uint64) (res *types.Record, err error) {
+	if isPartitioned(m) {
+		return s.lookupPartitionedComposeRecordByID(ctx, m, id)
+	}
+
 	res, err = s.lookupComposeRecordByID(ctx, m, id)
 	if err != nil {
 		return
@@ -320,6 +318,10 @@ func (s Store) CreateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		return
 	}
 
+	if isPartitioned(m) {
+		return s.createPartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 
 		err = s.createComposeRecord(ctx, m, res)
@@ -345,6 +347,10 @@ func (s Store) UpdateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		return
 	}
 
+	if isPartitioned(m) {
+		return s.updatePartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 		err = s.updateComposeRecord(ctx, m, res)
 		if err != nil {
@@ -408,6 +414,10 @@ func (s Store) UpsertComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		return
 	}
 
+	if isPartitioned(m) {
+		return s.upsertPartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 		err = s.upsertComposeRecord(ctx, m, res)
 		if err != nil {
@@ -420,6 +430,10 @@ func (s Store) UpsertComposeRecord(ctx context.Context, m *types.Module, rr ...*
 
 // DeleteComposeRecordByID Deletes ComposeRecord from store
 func (s Store) DeleteComposeRecordByID(ctx context.Context, m *types.Module, ID uint64) (err error) {
+	if isPartitioned(m) {
+		return s.deletePartitionedComposeRecordByID(ctx, m, ID)
+	}
+
 	err = s.deleteComposeRecordByID(ctx, m, ID)
 	if err != nil {
 		return
@@ -435,6 +449,10 @@ func (s Store) DeleteComposeRecordByID(ctx context.Context, m *types.Module, ID
 
 // TruncateComposeRecords Deletes all ComposeRecords from store
 func (s Store) TruncateComposeRecords(ctx context.Context, m *types.Module) (err error) {
+	if isPartitioned(m) {
+		return s.truncatePartitionedComposeRecords(ctx, m)
+	}
+
 	err = s.truncateComposeRecords(ctx, m)
 	if err != nil {
 		return
@@ -448,11 +466,32 @@ func (s Store) TruncateComposeRecords(ctx context.Context, m *types.Module) (err
 	return
 }
 
+// LockComposeRecords serializes record writes on the module
+// until the end of the current transaction
+//
+// No-op update of the module row acquires a row lock (or a database write
+// lock on SQLite) that is held by the transaction; concurrent writers
+// wait for it before they can check for duplicates and store records.
+func (s Store) LockComposeRecords(ctx context.Context, m *types.Module) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.composeModuleTable()).
+		Set("id", squirrel.Expr("id")).
+		Where(squirrel.Eq{"id": m.ID}),
+	)
+}
+
 func (s Store) ComposeRecordReport(ctx context.Context, m *types.Module, metrics, dimensions, filter string) ([]map[string]interface{}, error) {
+	if isPartitioned(m) {
+		return nil, fmt.Errorf("reports are not supported on modules with partitioned records")
+	}
+
 	return ComposeRecordReportBuilder(&s, m, metrics, dimensions, filter).Run(ctx)
 }
 
 func (s Store) ComposeRecordDatasource(ctx context.Context, m *types.Module, ld *report.LoadStepDefinition) (report.Datasource, error) {
+	if isPartitioned(m) {
+		return nil, fmt.Errorf("report datasources are not supported on modules with partitioned records")
+	}
+
 	return ComposeRecordDatasourceBuilder(&s, m, ld)
 }
 
@@ -502,6 +541,12 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 				return i, fmt.Errorf("unknown field %q", i.Value)
 			}
 
+			if isPartitioned(m) {
+				// values of partitioned records are in the same table, no need to join
+				i.Value, _, _, err = s.castComposeRecordField(m, m.Fields.FindByName(i.Value), i.Value)
+				return i, err
+			}
+
 			if !alreadyJoined(i.Value) {
 				join := composeRecordValueJoinTpl
 				join = strings.ReplaceAll(join, "{alias}", composeRecordValueAliasPfx+i.Value)
@@ -514,7 +559,13 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 	)
 
 	// Create query for fetching and counting records.
-	query = s.composeRecordsSelectBuilder().
+	if isPartitioned(m) {
+		query = s.partitionedComposeRecordsSelectBuilder(m)
+	} else {
+		query = s.composeRecordsSelectBuilder()
+	}
+
+	query = query.
 		Where("crd.module_id = ?", m.ID).
 		Where("crd.rel_namespace = ?", m.NamespaceID)
 
@@ -603,6 +654,10 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 //}
 
 func (s Store) composeRecordPostLoadProcessor(ctx context.Context, m *types.Module, set ...*types.Record) (err error) {
+	if isPartitioned(m) {
+		return s.partitionedComposeRecordPostLoadProcessor(ctx, m, set...)
+	}
+
 	if len(set) > 0 {
 		// Load all related record values and append them to each record
 		var (
@@ -649,7 +704,7 @@ func (s Store) composeRecordsSorter(m *types.Module, q squirrel.SelectBuilder, s
 			sqlSort[i] = col
 		} else if f := m.Fields.FindByName(c.Column); f != nil {
 
-			sqlSort[i], _, _, err = s.config.CastModuleFieldToColumnType(f, c.Column)
+			sqlSort[i], _, _, err = s.castComposeRecordField(m, f, c.Column)
 		} else {
 			err = fmt.Errorf("could not sort by unknown column: %s", c.Column)
 		}
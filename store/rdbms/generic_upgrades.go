@@ -77,6 +77,7 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 	case "compose_module":
 		return g.all(ctx,
 			g.AlterComposeModuleRenameJsonToMeta,
+			g.AlterComposeModuleAddConfig,
 		)
 	case "compose_module_field":
 		return g.all(ctx,
@@ -85,6 +86,11 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 	case "automation_sessions":
 		return g.all(ctx,
 			g.CreateAutomationSessionIndexes,
+			g.AlterAutomationSessionsAddSuspendedStates,
+		)
+	case "queue_messages":
+		return g.all(ctx,
+			g.AlterQueueMessagesAddDeadLetterFields,
 		)
 		//case "compose_attachment_binds":
 		//	return g.all(ctx,
@@ -384,6 +390,20 @@ func (g genericUpgrades) AlterComposeModuleRenameJsonToMeta(ctx context.Context)
 	return err
 }
 
+func (g genericUpgrades) AlterComposeModuleAddConfig(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "config",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'{}'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "compose_module", col)
+	return
+}
+
 func (g genericUpgrades) AlterComposeModuleFieldAddExpresions(ctx context.Context) (err error) {
 	var (
 		col = &ddl.Column{
@@ -419,3 +439,49 @@ func (g genericUpgrades) CreateAutomationSessionIndexes(ctx context.Context) (er
 
 	return
 }
+
+func (g genericUpgrades) AlterAutomationSessionsAddSuspendedStates(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "suspended_states",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'[]'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_sessions", col)
+	return
+}
+
+func (g genericUpgrades) AlterQueueMessagesAddDeadLetterFields(ctx context.Context) (err error) {
+	var (
+		cc = []*ddl.Column{
+			{
+				Name:   "failed",
+				Type:   ddl.ColumnType{Type: ddl.ColumnTypeTimestamp},
+				IsNull: true,
+			},
+			{
+				Name:         "attempts",
+				Type:         ddl.ColumnType{Type: ddl.ColumnTypeInteger},
+				IsNull:       false,
+				DefaultValue: "0",
+			},
+			{
+				Name:         "error",
+				Type:         ddl.ColumnType{Type: ddl.ColumnTypeText},
+				IsNull:       false,
+				DefaultValue: "''",
+			},
+		}
+	)
+
+	for _, col := range cc {
+		if _, err = g.u.AddColumn(ctx, "queue_messages", col); err != nil {
+			return
+		}
+	}
+
+	return
+}
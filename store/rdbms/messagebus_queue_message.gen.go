@@ -271,6 +271,13 @@ func (s Store) QueryMessagebusQueueMessages(
 	return set, nil
 }
 
+// LookupMessagebusQueueMessageByID searches for queue message by ID
+func (s Store) LookupMessagebusQueueMessageByID(ctx context.Context, id uint64) (*messagebus.QueueMessage, error) {
+	return s.execLookupMessagebusQueueMessage(ctx, squirrel.Eq{
+		s.preprocessColumn("mqm.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
 // CreateMessagebusQueueMessage creates one or more rows in queue_messages table
 func (s Store) CreateMessagebusQueueMessage(ctx context.Context, rr ...*messagebus.QueueMessage) (err error) {
 	for _, res := range rr {
@@ -390,6 +397,9 @@ func (s Store) internalMessagebusQueueMessageRowScanner(row rowScanner) (res *me
 			&res.Payload,
 			&res.Processed,
 			&res.Created,
+			&res.Failed,
+			&res.Attempts,
+			&res.Error,
 		)
 	}
 
@@ -434,6 +444,9 @@ func (Store) messagebusQueueMessageColumns(aa ...string) []string {
 		alias + "payload",
 		alias + "processed",
 		alias + "created",
+		alias + "failed",
+		alias + "attempts",
+		alias + "error",
 	}
 }
 
@@ -459,6 +472,9 @@ func (s Store) internalMessagebusQueueMessageEncoder(res *messagebus.QueueMessag
 		"payload":   res.Payload,
 		"processed": res.Processed,
 		"created":   res.Created,
+		"failed":    res.Failed,
+		"attempts":  res.Attempts,
+		"error":     res.Error,
 	}
 }
 
@@ -14,5 +14,6 @@ func (s Store) convertMessagebusQueueMessageFilter(f messagebus.QueueMessageFilt
 	}
 
 	query = filter.StateCondition(query, "mqm.processed", f.Processed)
+	query = filter.StateCondition(query, "mqm.failed", f.Failed)
 	return
 }
@@ -28,6 +28,15 @@ type (
 		CreateIndexes(ii ...*ddl.Index) []string
 	}
 
+	// TableUpgrader creates tables and adds columns and indexes at runtime
+	TableUpgrader interface {
+		TableExists(context.Context, string) (bool, error)
+		CreateTable(context.Context, *ddl.Table) error
+		DropTable(context.Context, string) (bool, error)
+		AddColumn(context.Context, string, *ddl.Column) (bool, error)
+		CreateIndex(context.Context, *ddl.Index) (bool, error)
+	}
+
 	// Store - Corteza RDBMS persistence layer
 	Store struct {
 		config *Config
@@ -11,6 +11,7 @@ import (
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/pkg/ql"
 	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
 )
 
 // persistance layer
@@ -116,6 +117,16 @@ type (
 
 		CastModuleFieldToColumnType func(ModuleFieldTypeDetector, string) (string, string, string, error)
 
+		// SqlJsonValueExtractor returns expression that extracts single value (as string)
+		// from JSON encoded record values of partitioned records
+		//
+		// When not set, only values stored in physical columns can be used in filters and sorting
+		SqlJsonValueExtractor func(column, name string, place int) string
+
+		// TableUpgrader creates and upgrades tables that are not part of the static schema
+		// (dedicated tables of partitioned records)
+		TableUpgrader func(*zap.Logger, *Store) TableUpgrader
+
 		// ASTFormatter allows different SQL flavours to alater the default AST formatting flow.
 		ASTFormatter ASTFormatterFn
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cortezaproject/corteza-server/compose/types"
 	. "github.com/cortezaproject/corteza-server/store/rdbms/ddl"
 )
 
@@ -76,6 +77,8 @@ func (s Schema) Tables() []*Table {
 		s.ComposePage(),
 		s.ComposeRecord(),
 		s.ComposeRecordValue(),
+		s.ComposeRecordRevision(),
+		s.ComposeRecordImportSession(),
 		s.FederationModuleShared(),
 		s.FederationModuleExposed(),
 		s.FederationModuleMapping(),
@@ -89,6 +92,7 @@ func (s Schema) Tables() []*Table {
 		s.MessagebusQueueSettings(),
 		s.ApigwRoute(),
 		s.ApigwFilter(),
+		s.ApigwSecret(),
 	}
 }
 
@@ -435,6 +439,7 @@ func (Schema) ComposeModule() *Table {
 		ColumnDef("handle", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
 		ColumnDef("name", ColumnTypeText),
 		ColumnDef("meta", ColumnTypeJson),
+		ColumnDef("config", ColumnTypeJson),
 		CUDTimestamps,
 
 		AddIndex("namespace", IColumn("rel_namespace")),
@@ -515,6 +520,71 @@ func (Schema) ComposeRecord() *Table {
 	)
 }
 
+// ComposeRecordPartition returns definition for a dedicated table for records of the module
+//
+// This table is not part of the static schema and
+// is created when module with partitioned records is stored
+func (Schema) ComposeRecordPartition(m *types.Module) *Table {
+	t := TableDef(m.RecordPartitionTable(),
+		ID,
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("module_id", ColumnTypeIdentifier),
+		ColumnDef("owned_by", ColumnTypeIdentifier),
+		ColumnDef(composeRecordPartitionValuesColumn, ColumnTypeJson),
+		CUDTimestamps,
+		CUDUsers,
+
+		AddIndex("module", IColumn("module_id")),
+		AddIndex("owner", IColumn("owned_by")),
+	)
+
+	for _, f := range composeRecordPhysicalFields(m) {
+		t.Apply(composeRecordPhysicalColumnDef(f), composeRecordPhysicalColumnIndex(f))
+	}
+
+	return t
+}
+
+func (Schema) ComposeRecordRevision() *Table {
+	return TableDef("compose_record_revision",
+		ID,
+		ColumnDef("rel_record", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("operation", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("changes", ColumnTypeJson),
+		ColumnDef("created_by", ColumnTypeIdentifier),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+
+		AddIndex("record", IColumn("rel_record")),
+		AddIndex("module", IColumn("rel_module")),
+	)
+}
+
+func (Schema) ComposeRecordImportSession() *Table {
+	return TableDef("compose_record_import_session",
+		ID,
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("owned_by", ColumnTypeIdentifier),
+		ColumnDef("name", ColumnTypeText),
+		ColumnDef("content_type", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("data", ColumnTypeBinary),
+		ColumnDef("status", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("mode", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("on_error", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("dry_run", ColumnTypeBoolean),
+		ColumnDef("fields", ColumnTypeJson),
+		ColumnDef("key_column", ColumnTypeText),
+		ColumnDef("progress", ColumnTypeJson),
+		ColumnDef("rejected", ColumnTypeJson),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("updated_at", ColumnTypeTimestamp, Null),
+
+		AddIndex("status", IColumn("status")),
+	)
+}
+
 func (Schema) ComposeRecordValue() *Table {
 	return TableDef("compose_record_value",
 		ColumnDef("record_id", ColumnTypeIdentifier),
@@ -632,6 +702,7 @@ func (Schema) AutomationSessions() *Table {
 		ColumnDef("suspended_at", ColumnTypeTimestamp, Null),
 		ColumnDef("completed_at", ColumnTypeTimestamp, Null),
 		ColumnDef("error", ColumnTypeText),
+		ColumnDef("suspended_states", ColumnTypeJson),
 
 		AddIndex("workflow", IColumn("rel_workflow")),
 		AddIndex("event_type", IFieldFull(&IField{Field: "event_type", Length: handleLength})),
@@ -669,6 +740,9 @@ func (Schema) MessagebusQueuemessage() *Table {
 		ColumnDef("payload", ColumnTypeBinary),
 		ColumnDef("created", ColumnTypeTimestamp),
 		ColumnDef("processed", ColumnTypeTimestamp, Null),
+		ColumnDef("failed", ColumnTypeTimestamp, Null),
+		ColumnDef("attempts", ColumnTypeInteger),
+		ColumnDef("error", ColumnTypeText),
 
 		// AddIndex("processed", IColumn("processed")),
 	)
@@ -710,3 +784,15 @@ func (Schema) ApigwFilter() *Table {
 		CUDUsers,
 	)
 }
+
+func (Schema) ApigwSecret() *Table {
+	return TableDef("apigw_secrets",
+		ID,
+		ColumnDef("handle", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("value", ColumnTypeText),
+		CUDTimestamps,
+		CUDUsers,
+
+		AddIndex("unique_handle", IExpr("LOWER(handle)"), IWhere("LENGTH(handle) > 0 AND deleted_at IS NULL")),
+	)
+}
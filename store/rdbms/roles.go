@@ -2,6 +2,8 @@ package rdbms
 
 import (
 	"context"
+	"fmt"
+
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -44,6 +46,14 @@ func (s Store) convertRoleFilter(f types.RoleFilter) (query squirrel.SelectBuild
 	return
 }
 
+func (s Store) CountRoles(ctx context.Context, f types.RoleFilter) (uint, error) {
+	if q, err := s.convertRoleFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count roles: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
+
 func (s Store) RoleMetrics(ctx context.Context) (*types.RoleMetrics, error) {
 	var (
 		counters = squirrel.
@@ -76,6 +76,16 @@ func (s Store) CountUsers(ctx context.Context, f types.UserFilter) (uint, error)
 	}
 }
 
+// LockUser locks user's row until the end of the transaction
+//
+// Used to serialize changes of user's related resources (credentials)
+func (s Store) LockUser(ctx context.Context, u *types.User) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.userTable()).
+		Set("id", squirrel.Expr("id")).
+		Where(squirrel.Eq{"id": u.ID}),
+	)
+}
+
 func (s Store) UserMetrics(ctx context.Context) (*types.UserMetrics, error) {
 	var (
 		counters = squirrel.
@@ -49,6 +49,7 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.SqlFunctionHandler = sqlFunctionHandler
 	cfg.ASTFormatter = sqlASTFormatter
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.TableUpgrader = tableUpgrader
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
 		return nil, err
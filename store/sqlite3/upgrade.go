@@ -42,6 +42,11 @@ func NewUpgrader(log *zap.Logger, store *Store) *upgrader {
 	return g
 }
 
+// tableUpgrader returns upgrader for tables that are created at runtime
+func tableUpgrader(log *zap.Logger, s *rdbms.Store) rdbms.TableUpgrader {
+	return NewUpgrader(log, &Store{Store: s})
+}
+
 // Before runs before all tables are upgraded
 func (u upgrader) Before(ctx context.Context) error {
 	return rdbms.GenericUpgrades(u.log, u).Before(ctx)
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/rand"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testApigwSecret(t *testing.T, s store.ApigwSecrets) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(handle string) *types.ApigwSecret {
+			return &types.ApigwSecret{
+				ID:        id.Next(),
+				Handle:    handle,
+				Value:     "encrypted",
+				CreatedAt: time.Now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.ApigwSecret) {
+			req := require.New(t)
+			req.NoError(s.TruncateApigwSecrets(ctx))
+			res := makeNew(string(rand.Bytes(10)))
+			req.NoError(s.CreateApigwSecret(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateApigwSecret(ctx, makeNew("ApigwSecretCRUD")))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, secret := truncAndCreate(t)
+		fetched, err := s.LookupApigwSecretByID(ctx, secret.ID)
+		req.NoError(err)
+		req.Equal(secret.Handle, fetched.Handle)
+		req.Equal(secret.Value, fetched.Value)
+		req.Nil(fetched.DeletedAt)
+	})
+
+	t.Run("lookup by handle", func(t *testing.T) {
+		req, secret := truncAndCreate(t)
+		fetched, err := s.LookupApigwSecretByHandle(ctx, secret.Handle)
+		req.NoError(err)
+		req.Equal(secret.ID, fetched.ID)
+
+		secret.DeletedAt = &secret.CreatedAt
+		req.NoError(s.UpdateApigwSecret(ctx, secret))
+
+		_, err = s.LookupApigwSecretByHandle(ctx, secret.Handle)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, secret := truncAndCreate(t)
+		secret.Value = "rotated"
+		req.NoError(s.UpdateApigwSecret(ctx, secret))
+
+		updated, err := s.LookupApigwSecretByID(ctx, secret.ID)
+		req.NoError(err)
+		req.Equal("rotated", updated.Value)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, secret := truncAndCreate(t)
+		req.NoError(s.DeleteApigwSecretByID(ctx, secret.ID))
+		_, err := s.LookupApigwSecretByID(ctx, secret.ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateApigwSecrets(ctx))
+		req.NoError(s.CreateApigwSecret(ctx, makeNew("one"), makeNew("two")))
+
+		set, _, err := s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{Handle: "two"})
+		req.NoError(err)
+		req.Len(set, 1)
+	})
+}
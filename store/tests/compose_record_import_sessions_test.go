@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testComposeRecordImportSessions(t *testing.T, s store.ComposeRecordImportSessions) {
+	var (
+		ctx = context.Background()
+
+		ownerID = id.Next()
+
+		makeNew = func(status types.RecordImportStatus) *types.RecordImportSession {
+			return &types.RecordImportSession{
+				ID:          id.Next(),
+				NamespaceID: id.Next(),
+				ModuleID:    id.Next(),
+				OwnedBy:     ownerID,
+				Name:        "import.csv",
+				ContentType: "text/csv",
+				Data:        []byte("name,email\nfoo,foo@example.tld\n"),
+				Status:      status,
+				Mode:        types.RecordImportModeCreate,
+				Fields:      types.RecordImportFields{"name": "name", "email": ""},
+				Progress:    types.RecordImportProgress{EntryCount: 1},
+				CreatedAt:   time.Now(),
+			}
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+		req.NoError(s.CreateComposeRecordImportSession(ctx, makeNew(types.RecordImportPending)))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+
+		ses := makeNew(types.RecordImportPending)
+		ses.Key = "email"
+		ses.Rejected = types.RecordImportRowSet{{Index: 1, Values: map[string]string{"name": ""}, Error: "empty field name"}}
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		fetched, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		req.Equal(ses.Data, fetched.Data)
+		req.Equal("email", fetched.Key)
+		req.Equal(types.RecordImportFields{"name": "name", "email": ""}, fetched.Fields)
+		req.Equal(uint64(1), fetched.Progress.EntryCount)
+		req.Len(fetched.Rejected, 1)
+		req.Equal("empty field name", fetched.Rejected[0].Error)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+
+		ses := makeNew(types.RecordImportQueued)
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		ses.Status = types.RecordImportRunning
+		ses.Progress.Processed = 1
+		ses.UpdatedAt = &ses.CreatedAt
+		req.NoError(s.UpdateComposeRecordImportSession(ctx, ses))
+
+		fetched, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		req.Equal(types.RecordImportRunning, fetched.Status)
+		req.Equal(uint64(1), fetched.Progress.Processed)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+
+		stale := makeNew(types.RecordImportRunning)
+		stale.CreatedAt = time.Now().Add(-time.Hour)
+
+		req.NoError(s.CreateComposeRecordImportSession(ctx,
+			makeNew(types.RecordImportPending),
+			makeNew(types.RecordImportQueued),
+			stale,
+		))
+
+		set, _, err := s.SearchComposeRecordImportSessions(ctx, types.RecordImportSessionFilter{
+			Status: []types.RecordImportStatus{types.RecordImportQueued, types.RecordImportRunning},
+		})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		before := time.Now().Add(-time.Minute)
+		set, _, err = s.SearchComposeRecordImportSessions(ctx, types.RecordImportSessionFilter{UpdatedBefore: &before})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(stale.ID, set[0].ID)
+	})
+
+	t.Run("partial update", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			ses = makeNew(types.RecordImportRunning)
+			now = time.Now()
+		)
+
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		ses.Data = []byte("changed")
+		ses.Status = types.RecordImportFailed
+		ses.Progress.Processed = 1
+		ses.UpdatedAt = &now
+
+		req.NoError(s.PartialComposeRecordImportSessionUpdate(ctx, []string{"progress", "updated_at"}, ses))
+
+		fetched, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		req.Equal(uint64(1), fetched.Progress.Processed)
+		req.NotNil(fetched.UpdatedAt)
+
+		// other columns are not updated
+		req.Equal(makeNew(types.RecordImportRunning).Data, fetched.Data)
+		req.Equal(types.RecordImportRunning, fetched.Status)
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		var (
+			req    = require.New(t)
+			ses    = makeNew(types.RecordImportQueued)
+			now    = time.Now()
+			before = now.Add(-time.Minute)
+			f      = types.RecordImportSessionFilter{Status: []types.RecordImportStatus{types.RecordImportQueued}}
+		)
+
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		ses.Status = types.RecordImportRunning
+		ses.UpdatedAt = &now
+
+		claimed, err := s.ClaimComposeRecordImportSession(ctx, ses, f)
+		req.NoError(err)
+		req.True(claimed)
+
+		// already claimed
+		claimed, err = s.ClaimComposeRecordImportSession(ctx, ses, f)
+		req.NoError(err)
+		req.False(claimed)
+
+		// running but not stale
+		f = types.RecordImportSessionFilter{Status: []types.RecordImportStatus{types.RecordImportRunning}, UpdatedBefore: &before}
+		claimed, err = s.ClaimComposeRecordImportSession(ctx, ses, f)
+		req.NoError(err)
+		req.False(claimed)
+
+		fetched, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		req.Equal(types.RecordImportRunning, fetched.Status)
+	})
+}
@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testComposeRecordPartitions(t *testing.T, s store.ComposeRecords) {
+	var (
+		ctx = context.Background()
+
+		physical = func() types.ModuleFieldOptions {
+			opt := types.ModuleFieldOptions{}
+			opt.SetIsPhysicalColumn(true)
+			return opt
+		}
+
+		mod = &types.Module{
+			ID:          id.Next(),
+			NamespaceID: id.Next(),
+			Name:        "testComposeRecordPartitions",
+			CreatedAt:   time.Now(),
+			Config: types.ModuleConfig{
+				RecordPartition: types.ModuleConfigRecordPartition{Enabled: true},
+			},
+			Fields: types.ModuleFieldSet{
+				&types.ModuleField{Kind: "String", Name: "str1", Options: physical()},
+				&types.ModuleField{Kind: "Number", Name: "num1", Options: physical()},
+				&types.ModuleField{Kind: "Record", Name: "ref1", Options: physical()},
+				&types.ModuleField{Kind: "String", Name: "note"},
+				&types.ModuleField{Kind: "String", Name: "tags", Multi: true},
+			},
+		}
+
+		makeNew = func(vv ...*types.RecordValue) *types.Record {
+			var recordID = id.Next()
+
+			for _, v := range vv {
+				v.RecordID = recordID
+			}
+
+			return &types.Record{
+				ID:          recordID,
+				NamespaceID: mod.NamespaceID,
+				ModuleID:    mod.ID,
+				CreatedAt:   time.Now().Round(time.Second),
+				Values:      vv,
+			}
+		}
+
+		truncAndCreate = func(t *testing.T, rr ...*types.Record) (*require.Assertions, types.RecordSet) {
+			req := require.New(t)
+			req.NoError(s.TruncateComposeRecords(ctx, mod))
+			req.NoError(s.CreateComposeRecord(ctx, mod, rr...))
+			return req, rr
+		}
+
+		stringify = func(set types.RecordSet, field string) (out []string) {
+			for _, r := range set {
+				if v := r.Values.Get(field, 0); v != nil {
+					out = append(out, v.Value)
+				} else {
+					out = append(out, "")
+				}
+			}
+
+			return
+		}
+	)
+
+	require.NoError(t, s.UpgradeComposeRecordPartition(ctx, mod))
+
+	t.Run("create and lookup", func(t *testing.T) {
+		req, rr := truncAndCreate(t, makeNew(
+			&types.RecordValue{Name: "str1", Value: "v1"},
+			&types.RecordValue{Name: "ref1", Value: "42", Ref: 42},
+			&types.RecordValue{Name: "tags", Value: "a", Place: 0},
+			&types.RecordValue{Name: "tags", Value: "b", Place: 1},
+		))
+
+		fetched, err := s.LookupComposeRecordByID(ctx, mod, rr[0].ID)
+		req.NoError(err)
+		req.Equal(rr[0].ID, fetched.ID)
+		req.Len(fetched.Values, 4)
+		req.Equal("v1", fetched.Values.Get("str1", 0).Value)
+		req.Equal(uint64(42), fetched.Values.Get("ref1", 0).Ref)
+		req.Equal("b", fetched.Values.Get("tags", 1).Value)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, rr := truncAndCreate(t, makeNew(&types.RecordValue{Name: "str1", Value: "v1"}))
+
+		rr[0].Values = types.RecordValueSet{
+			&types.RecordValue{RecordID: rr[0].ID, Name: "str1", Value: "v2"},
+			&types.RecordValue{RecordID: rr[0].ID, Name: "note", Value: "n"},
+		}
+		req.NoError(s.UpdateComposeRecord(ctx, mod, rr[0]))
+
+		fetched, err := s.LookupComposeRecordByID(ctx, mod, rr[0].ID)
+		req.NoError(err)
+		req.Equal("v2", fetched.Values.Get("str1", 0).Value)
+		req.Equal("n", fetched.Values.Get("note", 0).Value)
+	})
+
+	t.Run("search by physical columns", func(t *testing.T) {
+		var (
+			req, _ = truncAndCreate(t,
+				makeNew(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "num1", Value: "1"}),
+				makeNew(&types.RecordValue{Name: "str1", Value: "b"}, &types.RecordValue{Name: "num1", Value: "10"}),
+				makeNew(&types.RecordValue{Name: "str1", Value: "c"}, &types.RecordValue{Name: "num1", Value: "2"}),
+			)
+
+			f = types.RecordFilter{Query: "num1 > 1"}
+		)
+
+		req.NoError(f.Sort.Set("num1 DESC"))
+
+		set, _, err := s.SearchComposeRecords(ctx, mod, f)
+		req.NoError(err)
+		req.Equal([]string{"b", "c"}, stringify(set, "str1"))
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		var (
+			req, _ = truncAndCreate(t,
+				makeNew(&types.RecordValue{Name: "str1", Value: "a"}),
+				makeNew(&types.RecordValue{Name: "str1", Value: "b"}),
+				makeNew(&types.RecordValue{Name: "str1", Value: "c"}),
+			)
+
+			f   = types.RecordFilter{Paging: filter.Paging{Limit: 2}}
+			set types.RecordSet
+			err error
+		)
+
+		req.NoError(f.Sort.Set("str1"))
+
+		set, f, err = s.SearchComposeRecords(ctx, mod, f)
+		req.NoError(err)
+		req.Equal([]string{"a", "b"}, stringify(set, "str1"))
+		req.NotNil(f.NextPage)
+
+		f.PageCursor = f.NextPage
+		set, _, err = s.SearchComposeRecords(ctx, mod, f)
+		req.NoError(err)
+		req.Equal([]string{"c"}, stringify(set, "str1"))
+	})
+
+	t.Run("partial value update", func(t *testing.T) {
+		req, rr := truncAndCreate(t,
+			makeNew(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "num1", Value: "1"}),
+		)
+
+		req.NoError(s.PartialComposeRecordValueUpdate(ctx, mod, &types.RecordValue{RecordID: rr[0].ID, Name: "num1", Value: "2"}))
+
+		fetched, err := s.LookupComposeRecordByID(ctx, mod, rr[0].ID)
+		req.NoError(err)
+		req.Equal("a", fetched.Values.Get("str1", 0).Value)
+		req.Equal("2", fetched.Values.Get("num1", 0).Value)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, rr := truncAndCreate(t, makeNew())
+		req.NoError(s.DeleteComposeRecordByID(ctx, mod, rr[0].ID))
+
+		_, err := s.LookupComposeRecordByID(ctx, mod, rr[0].ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("add physical column", func(t *testing.T) {
+		req, _ := truncAndCreate(t,
+			makeNew(&types.RecordValue{Name: "note", Value: "x"}),
+			makeNew(&types.RecordValue{Name: "note", Value: "y"}),
+		)
+
+		mod.Fields.FindByName("note").Options = physical()
+		req.NoError(s.UpgradeComposeRecordPartition(ctx, mod))
+
+		set, _, err := s.SearchComposeRecords(ctx, mod, types.RecordFilter{Query: "note = 'y'"})
+		req.NoError(err)
+		req.Equal([]string{"y"}, stringify(set, "note"))
+	})
+
+	t.Run("table of another module", func(t *testing.T) {
+		var (
+			req = require.New(t)
+
+			ms, ok = s.(store.ComposeModules)
+
+			other = &types.Module{
+				ID:          id.Next(),
+				NamespaceID: mod.NamespaceID,
+				Name:        "testComposeRecordPartitionsOther",
+				CreatedAt:   time.Now(),
+				Config: types.ModuleConfig{
+					RecordPartition: types.ModuleConfigRecordPartition{Enabled: true, Table: "test_partition_other"},
+				},
+			}
+
+			taken = &types.Module{
+				ID:          id.Next(),
+				NamespaceID: mod.NamespaceID,
+				Config: types.ModuleConfig{
+					RecordPartition: types.ModuleConfigRecordPartition{Enabled: true},
+				},
+			}
+		)
+
+		if !ok {
+			t.Skip("store does not support compose modules")
+		}
+
+		req.NoError(ms.CreateComposeModule(ctx, other))
+		defer ms.DeleteComposeModule(ctx, other)
+
+		taken.Config.RecordPartition.Table = other.RecordPartitionTable()
+		req.EqualError(s.UpgradeComposeRecordPartition(ctx, taken), `record partition table "test_partition_other" is used by another module`)
+
+		// default table name of another module
+		taken.Config.RecordPartition.Table = "compose_record_" + strconv.FormatUint(other.ID, 10)
+		req.Error(s.UpgradeComposeRecordPartition(ctx, taken))
+
+		// table names are case-insensitive on some databases
+		taken.Config.RecordPartition.Table = "Compose_Module"
+		req.EqualError(s.UpgradeComposeRecordPartition(ctx, taken), `record partition table name "Compose_Module" is reserved`)
+	})
+}
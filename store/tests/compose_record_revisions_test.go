@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testComposeRecordRevisions(t *testing.T, s store.ComposeRecordRevisions) {
+	var (
+		ctx = context.Background()
+
+		recordID = id.Next()
+
+		makeNew = func(op types.RecordRevisionOperation, cc ...*types.RecordValueChange) *types.RecordRevision {
+			return &types.RecordRevision{
+				ID:          id.Next(),
+				RecordID:    recordID,
+				ModuleID:    id.Next(),
+				NamespaceID: id.Next(),
+				Operation:   op,
+				Changes:     cc,
+				CreatedAt:   time.Now(),
+				CreatedBy:   id.Next(),
+			}
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordRevisions(ctx))
+		req.NoError(s.CreateComposeRecordRevision(ctx, makeNew(types.RecordRevisionCreate)))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordRevisions(ctx))
+
+		rev := makeNew(types.RecordRevisionUpdate, &types.RecordValueChange{Name: "foo", Old: []string{"a"}, New: []string{"b", "c"}})
+		req.NoError(s.CreateComposeRecordRevision(ctx, rev))
+
+		fetched, err := s.LookupComposeRecordRevisionByID(ctx, rev.ID)
+		req.NoError(err)
+		req.Equal(rev.RecordID, fetched.RecordID)
+		req.Equal(types.RecordRevisionUpdate, fetched.Operation)
+		req.Len(fetched.Changes, 1)
+		req.Equal([]string{"b", "c"}, fetched.Changes[0].New)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateComposeRecordRevisions(ctx))
+		req.NoError(s.CreateComposeRecordRevision(ctx,
+			makeNew(types.RecordRevisionCreate),
+			makeNew(types.RecordRevisionUpdate),
+			&types.RecordRevision{ID: id.Next(), RecordID: id.Next(), Operation: types.RecordRevisionCreate},
+		))
+
+		set, _, err := s.SearchComposeRecordRevisions(ctx, types.RecordRevisionFilter{RecordID: recordID})
+		req.NoError(err)
+		req.Len(set, 2)
+	})
+}
@@ -269,6 +269,16 @@ func testComposeRecords(t *testing.T, s store.ComposeRecords) {
 		})
 	})
 
+	t.Run("lock", func(t *testing.T) {
+		req, rr := truncAndCreate(t)
+		req.NoError(s.LockComposeRecords(ctx, mod))
+
+		// records are not modified
+		fetched, err := s.LookupComposeRecordByID(ctx, mod, rr[0].ID)
+		req.NoError(err)
+		req.Nil(fetched.UpdatedAt)
+	})
+
 	t.Run("search", func(t *testing.T) {
 		t.Run("by record attributes", func(t *testing.T) {
 			prefill := []*types.Record{
@@ -1622,4 +1632,8 @@ func testComposeRecords(t *testing.T, s store.ComposeRecords) {
 		req.Equal("1st,1;2nd,22;3rd,3", stringifyValues(set, "str1", "num1"))
 
 	})
+
+	t.Run("partitioned", func(t *testing.T) {
+		testComposeRecordPartitions(t, s)
+	})
 }
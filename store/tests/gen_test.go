@@ -7,6 +7,7 @@ package tests
 //  - store/actionlog.yaml
 //  - store/apigw_filter.yaml
 //  - store/apigw_route.yaml
+//  - store/apigw_secret.yaml
 //  - store/applications.yaml
 //  - store/attachments.yaml
 //  - store/auth_clients.yaml
@@ -22,6 +23,8 @@ package tests
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_import_sessions.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/credentials.yaml
 //  - store/federation_exposed_modules.yaml
 //  - store/federation_module_mappings.yaml
@@ -67,6 +70,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testApigwRoute(t, s)
 	})
 
+	// Run generated tests for ApigwSecret
+	t.Run("ApigwSecret", func(t *testing.T) {
+		testApigwSecret(t, s)
+	})
+
 	// Run generated tests for Applications
 	t.Run("Applications", func(t *testing.T) {
 		testApplications(t, s)
@@ -142,6 +150,16 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testComposePages(t, s)
 	})
 
+	// Run generated tests for ComposeRecordImportSessions
+	t.Run("ComposeRecordImportSessions", func(t *testing.T) {
+		testComposeRecordImportSessions(t, s)
+	})
+
+	// Run generated tests for ComposeRecordRevisions
+	t.Run("ComposeRecordRevisions", func(t *testing.T) {
+		testComposeRecordRevisions(t, s)
+	})
+
 	// Run generated tests for ComposeRecordValues
 	t.Run("ComposeRecordValues", func(t *testing.T) {
 		testComposeRecordValues(t, s)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/messagebus"
 	"github.com/cortezaproject/corteza-server/store"
 	_ "github.com/joho/godotenv/autoload"
@@ -40,4 +41,37 @@ func testMessagebusQueueMessage(t *testing.T, s store.MessagebusQueueMessages) {
 		req.NoError(err)
 		req.Len(set, 3)
 	})
+
+	t.Run("search failed", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateMessagebusQueueMessages(ctx))
+		req.NoError(s.CreateMessagebusQueueMessage(ctx,
+			&messagebus.QueueMessage{ID: 1, Queue: "test", Payload: []byte{}, Created: now()},
+			&messagebus.QueueMessage{ID: 2, Queue: "test", Payload: []byte{}, Created: now(), Failed: now(), Attempts: 3, Error: "failed"},
+		))
+
+		set, _, err := s.SearchMessagebusQueueMessages(ctx, messagebus.QueueMessageFilter{})
+		req.NoError(err)
+		req.Len(set, 1)
+
+		set, _, err = s.SearchMessagebusQueueMessages(ctx, messagebus.QueueMessageFilter{Failed: filter.StateExclusive})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(uint64(2), set[0].ID)
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateMessagebusQueueMessages(ctx))
+		req.NoError(s.CreateMessagebusQueueMessage(ctx,
+			&messagebus.QueueMessage{ID: 1, Queue: "test", Payload: []byte("foo"), Created: now(), Failed: now(), Attempts: 3, Error: "failed"},
+		))
+
+		m, err := s.LookupMessagebusQueueMessageByID(ctx, 1)
+		req.NoError(err)
+		req.Equal("foo", string(m.Payload))
+		req.Equal(3, m.Attempts)
+		req.Equal("failed", m.Error)
+		req.NotNil(m.Failed)
+	})
 }
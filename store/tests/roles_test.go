@@ -156,6 +156,37 @@ func testRoles(t *testing.T, s store.Roles) {
 		t.Skip("not implemented")
 	})
 
+	t.Run("count", func(t *testing.T) {
+		var (
+			req = require.New(t)
+
+			f      = types.RoleFilter{}
+			c1, c2 uint
+			err    error
+			role   = &types.Role{ID: id.Next(), CreatedAt: time.Now(), Name: "counted"}
+		)
+
+		c1, err = store.CountRoles(ctx, s, f)
+		req.NoError(err)
+
+		req.NoError(s.CreateRole(ctx, role))
+
+		c2, err = store.CountRoles(ctx, s, f)
+		req.NoError(err)
+		req.Equal(c1+1, c2)
+
+		f.Name = role.Name
+		c2, err = store.CountRoles(ctx, s, f)
+		req.NoError(err)
+		req.Equal(uint(1), c2)
+
+		req.NoError(s.DeleteRoleByID(ctx, role.ID))
+
+		c2, err = store.CountRoles(ctx, s, types.RoleFilter{})
+		req.NoError(err)
+		req.Equal(c1, c2)
+	})
+
 	t.Run("metrics", func(t *testing.T) {
 		var (
 			req = require.New(t)
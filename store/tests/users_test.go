@@ -419,6 +419,21 @@ func testUsers(t *testing.T, s store.Users) {
 		req.Equal(c1, c2)
 	})
 
+	t.Run("lock", func(t *testing.T) {
+		var (
+			req  = require.New(t)
+			user = &types.User{ID: id.Next(), CreatedAt: time.Now(), Email: fmt.Sprintf("user-crud+%s@crust.test", rand.Bytes(10))}
+		)
+
+		req.NoError(s.CreateUser(ctx, user))
+		req.NoError(s.LockUser(ctx, user))
+
+		// user is not modified
+		fetched, err := s.LookupUserByID(ctx, user.ID)
+		req.NoError(err)
+		req.Nil(fetched.UpdatedAt)
+	})
+
 	t.Run("metrics", func(t *testing.T) {
 		var (
 			req = require.New(t)
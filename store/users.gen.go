@@ -39,6 +39,9 @@ type (
 
 		// UserMetrics (custom function)
 		UserMetrics(ctx context.Context) (*types.UserMetrics, error)
+
+		// LockUser (custom function)
+		LockUser(ctx context.Context, _u *types.User) error
 	}
 )
 
@@ -115,3 +118,7 @@ func CountUsers(ctx context.Context, s Users, _f types.UserFilter) (uint, error)
 func UserMetrics(ctx context.Context, s Users) (*types.UserMetrics, error) {
 	return s.UserMetrics(ctx)
 }
+
+func LockUser(ctx context.Context, s Users, _u *types.User) error {
+	return s.LockUser(ctx, _u)
+}
@@ -0,0 +1,175 @@
+package automation
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// system/automation/reports_handler.yaml
+
+import (
+	"context"
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+)
+
+var _ wfexec.ExecResponse
+
+type (
+	reportsHandlerRegistry interface {
+		AddFunctions(ff ...*atypes.Function)
+		Type(ref string) expr.Type
+	}
+)
+
+func (h reportsHandler) register() {
+	h.reg.AddFunctions(
+		h.Export(),
+	)
+}
+
+type (
+	reportsExportArgs struct {
+		hasLookup    bool
+		Lookup       interface{}
+		lookupID     uint64
+		lookupHandle string
+
+		hasFormat bool
+		Format    string
+
+		hasTemplate    bool
+		Template       interface{}
+		templateID     uint64
+		templateHandle string
+
+		hasDocumentName bool
+		DocumentName    string
+	}
+
+	reportsExportResults struct {
+		Document *renderedDocument
+	}
+)
+
+func (a reportsExportArgs) GetLookup() (bool, uint64, string) {
+	return a.hasLookup, a.lookupID, a.lookupHandle
+}
+
+func (a reportsExportArgs) GetTemplate() (bool, uint64, string) {
+	return a.hasTemplate, a.templateID, a.templateHandle
+}
+
+// Export function Export report
+//
+// expects implementation of export function:
+// func (h reportsHandler) export(ctx context.Context, args *reportsExportArgs) (results *reportsExportResults, err error) {
+//    return
+// }
+func (h reportsHandler) Export() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "reportsExport",
+		Kind:   "function",
+		Labels: map[string]string{"export": "step", "reports": "step,workflow"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Export report",
+			Description: "Runs the report and exports all of its frames as a CSV, XLSX, HTML or PDF document",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "lookup",
+				Types: []string{"ID", "Handle"}, Required: true,
+			},
+			{
+				Name:  "format",
+				Types: []string{"String"}, Required: true,
+				Meta: &atypes.ParamMeta{
+					Label:       "Format",
+					Description: "One of csv, xlsx, html or pdf",
+				},
+			},
+			{
+				Name:  "template",
+				Types: []string{"ID", "Handle"},
+				Meta: &atypes.ParamMeta{
+					Label:       "Template",
+					Description: "Template used to render html and pdf documents",
+				},
+			},
+			{
+				Name:  "documentName",
+				Types: []string{"String"},
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "document",
+				Types: []string{"RenderedDocument"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &reportsExportArgs{
+					hasLookup:       in.Has("lookup"),
+					hasFormat:       in.Has("format"),
+					hasTemplate:     in.Has("template"),
+					hasDocumentName: in.Has("documentName"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			// Converting Lookup argument
+			if args.hasLookup {
+				aux := expr.Must(expr.Select(in, "lookup"))
+				switch aux.Type() {
+				case h.reg.Type("ID").Type():
+					args.lookupID = aux.Get().(uint64)
+				case h.reg.Type("Handle").Type():
+					args.lookupHandle = aux.Get().(string)
+				}
+			}
+
+			// Converting Template argument
+			if args.hasTemplate {
+				aux := expr.Must(expr.Select(in, "template"))
+				switch aux.Type() {
+				case h.reg.Type("ID").Type():
+					args.templateID = aux.Get().(uint64)
+				case h.reg.Type("Handle").Type():
+					args.templateHandle = aux.Get().(string)
+				}
+			}
+
+			var results *reportsExportResults
+			if results, err = h.export(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Document (*renderedDocument) to RenderedDocument
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("RenderedDocument").Cast(results.Document); err != nil {
+					return
+				} else if err = expr.Assign(out, "document", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
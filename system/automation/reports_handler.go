@@ -0,0 +1,95 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	reportService interface {
+		LookupByID(ctx context.Context, ID uint64) (*types.Report, error)
+		Search(ctx context.Context, rf types.ReportFilter) (types.ReportSet, types.ReportFilter, error)
+
+		Export(ctx context.Context, ID uint64, format string, templateID uint64, dd report.FrameDefinitionSet) (io.ReadSeeker, error)
+		ExportContentType(format string) string
+	}
+
+	reportTemplateService interface {
+		FindByHandle(ct context.Context, handle string) (*types.Template, error)
+	}
+
+	reportsHandler struct {
+		reg  reportsHandlerRegistry
+		rSvc reportService
+		tSvc reportTemplateService
+	}
+)
+
+func ReportsHandler(reg reportsHandlerRegistry, rSvc reportService, tSvc reportTemplateService) *reportsHandler {
+	h := &reportsHandler{
+		reg:  reg,
+		rSvc: rSvc,
+		tSvc: tSvc,
+	}
+
+	h.register()
+	return h
+}
+
+func (h reportsHandler) export(ctx context.Context, args *reportsExportArgs) (*reportsExportResults, error) {
+	reportID, err := h.reportID(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var templateID = args.templateID
+	if templateID == 0 && len(args.templateHandle) > 0 {
+		tpl, err := h.tSvc.FindByHandle(ctx, args.templateHandle)
+		if err != nil {
+			return nil, err
+		}
+		templateID = tpl.ID
+	}
+
+	doc, err := h.rSvc.Export(ctx, reportID, args.Format, templateID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name := args.DocumentName
+	if name == "" {
+		name = fmt.Sprintf("report.%s", args.Format)
+	}
+
+	return &reportsExportResults{
+		Document: &renderedDocument{
+			Document: doc,
+			Name:     name,
+			Type:     h.rSvc.ExportContentType(args.Format),
+		},
+	}, nil
+}
+
+func (h reportsHandler) reportID(ctx context.Context, args *reportsExportArgs) (uint64, error) {
+	_, ID, handle := args.GetLookup()
+
+	switch {
+	case ID > 0:
+		return ID, nil
+	case len(handle) > 0:
+		rr, _, err := h.rSvc.Search(ctx, types.ReportFilter{Handle: handle})
+		if err != nil {
+			return 0, err
+		}
+		if len(rr) == 0 {
+			return 0, fmt.Errorf("report not found")
+		}
+		return rr[0].ID, nil
+	}
+
+	return 0, fmt.Errorf("empty lookup params")
+}
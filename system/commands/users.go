@@ -229,10 +229,36 @@ func Users(ctx context.Context, app serviceInitializer) *cobra.Command {
 		},
 	}
 
+	unlockCmd := &cobra.Command{
+		Use:     "unlock [email]",
+		Short:   "Unlock user locked after too many failed login attempts",
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx = auth.SetIdentityToContext(ctx, auth.ServiceUser())
+
+			var (
+				user *types.User
+				err  error
+			)
+
+			if user, err = service.DefaultUser.FindByEmail(ctx, args[0]); err != nil {
+				cli.HandleError(err)
+			}
+
+			if err = service.DefaultAuth.ResetFailedLogins(ctx, user.ID); err != nil {
+				cli.HandleError(err)
+			}
+
+			cmd.Printf("User %s unlocked\n", user.Email)
+		},
+	}
+
 	cmd.AddCommand(
 		listCmd,
 		addCmd,
 		pwdCmd,
+		unlockCmd,
 	)
 
 	return cmd
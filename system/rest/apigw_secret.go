@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/api"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
+	"github.com/cortezaproject/corteza-server/system/rest/request"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	ApigwSecret struct {
+		svc secretService
+	}
+
+	secretPayload struct {
+		*types.ApigwSecret
+
+		// Reference to be used in filter params
+		Reference string `json:"reference"`
+	}
+
+	secretSetPayload struct {
+		Filter types.ApigwSecretFilter `json:"filter"`
+		Set    []*secretPayload        `json:"set"`
+	}
+
+	secretRotatePayload struct {
+		Rotated uint `json:"rotated"`
+	}
+
+	secretService interface {
+		FindByID(ctx context.Context, ID uint64) (*types.ApigwSecret, error)
+		Search(ctx context.Context, filter types.ApigwSecretFilter) (types.ApigwSecretSet, types.ApigwSecretFilter, error)
+		Create(ctx context.Context, new *types.ApigwSecret) (*types.ApigwSecret, error)
+		Update(ctx context.Context, upd *types.ApigwSecret) (*types.ApigwSecret, error)
+		DeleteByID(ctx context.Context, ID uint64) error
+		UndeleteByID(ctx context.Context, ID uint64) error
+		Rotate(ctx context.Context) (uint, error)
+	}
+)
+
+func (ApigwSecret) New() *ApigwSecret {
+	return &ApigwSecret{
+		svc: service.DefaultApigwSecret,
+	}
+}
+
+func (ctrl *ApigwSecret) List(ctx context.Context, r *request.ApigwSecretList) (interface{}, error) {
+	var (
+		err error
+		f   = types.ApigwSecretFilter{
+			Handle:  r.Handle,
+			Deleted: filter.State(r.Deleted),
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, filter, err := ctrl.svc.Search(ctx, f)
+
+	return ctrl.makeFilterPayload(ctx, set, filter, err)
+}
+
+func (ctrl *ApigwSecret) Create(ctx context.Context, r *request.ApigwSecretCreate) (interface{}, error) {
+	q, err := ctrl.svc.Create(ctx, &types.ApigwSecret{
+		Handle: r.Handle,
+		Value:  r.Value,
+	})
+
+	return ctrl.makePayload(ctx, q, err)
+}
+
+func (ctrl *ApigwSecret) Read(ctx context.Context, r *request.ApigwSecretRead) (interface{}, error) {
+	q, err := ctrl.svc.FindByID(ctx, r.SecretID)
+	return ctrl.makePayload(ctx, q, err)
+}
+
+func (ctrl *ApigwSecret) Update(ctx context.Context, r *request.ApigwSecretUpdate) (interface{}, error) {
+	q, err := ctrl.svc.Update(ctx, &types.ApigwSecret{
+		ID:     r.SecretID,
+		Handle: r.Handle,
+		Value:  r.Value,
+	})
+
+	return ctrl.makePayload(ctx, q, err)
+}
+
+func (ctrl *ApigwSecret) Delete(ctx context.Context, r *request.ApigwSecretDelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.DeleteByID(ctx, r.SecretID)
+}
+
+func (ctrl *ApigwSecret) Undelete(ctx context.Context, r *request.ApigwSecretUndelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.SecretID)
+}
+
+func (ctrl *ApigwSecret) Rotate(ctx context.Context, r *request.ApigwSecretRotate) (interface{}, error) {
+	rotated, err := ctrl.svc.Rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretRotatePayload{Rotated: rotated}, nil
+}
+
+func (ctrl *ApigwSecret) makePayload(ctx context.Context, q *types.ApigwSecret, err error) (*secretPayload, error) {
+	if err != nil || q == nil {
+		return nil, err
+	}
+
+	return &secretPayload{
+		ApigwSecret: q,
+		Reference:   secure.Reference(q.Handle),
+	}, nil
+}
+
+func (ctrl *ApigwSecret) makeFilterPayload(ctx context.Context, nn types.ApigwSecretSet, f types.ApigwSecretFilter, err error) (*secretSetPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	msp := &secretSetPayload{Filter: f, Set: make([]*secretPayload, len(nn))}
+
+	for i := range nn {
+		msp.Set[i], _ = ctrl.makePayload(ctx, nn[i], nil)
+	}
+
+	return msp, nil
+}
@@ -25,6 +25,8 @@ type (
 		Update(context.Context, *request.QueuesUpdate) (interface{}, error)
 		Delete(context.Context, *request.QueuesDelete) (interface{}, error)
 		Undelete(context.Context, *request.QueuesUndelete) (interface{}, error)
+		Failed(context.Context, *request.QueuesFailed) (interface{}, error)
+		Replay(context.Context, *request.QueuesReplay) (interface{}, error)
 	}
 
 	// HTTP API interface
@@ -35,6 +37,8 @@ type (
 		Update   func(http.ResponseWriter, *http.Request)
 		Delete   func(http.ResponseWriter, *http.Request)
 		Undelete func(http.ResponseWriter, *http.Request)
+		Failed   func(http.ResponseWriter, *http.Request)
+		Replay   func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -134,6 +138,38 @@ func NewQueues(h QueuesAPI) *Queues {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Failed: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewQueuesFailed()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Failed(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Replay: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewQueuesReplay()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Replay(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -148,5 +184,7 @@ func (h Queues) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Put("/queues/{queueID}", h.Update)
 		r.Delete("/queues/{queueID}", h.Delete)
 		r.Post("/queues/{queueID}/undelete", h.Undelete)
+		r.Get("/queues/{queueID}/failed", h.Failed)
+		r.Post("/queues/{queueID}/failed/{messageID}/replay", h.Replay)
 	})
 }
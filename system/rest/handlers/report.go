@@ -28,6 +28,7 @@ type (
 		Describe(context.Context, *request.ReportDescribe) (interface{}, error)
 		RunFresh(context.Context, *request.ReportRunFresh) (interface{}, error)
 		Run(context.Context, *request.ReportRun) (interface{}, error)
+		Export(context.Context, *request.ReportExport) (interface{}, error)
 	}
 
 	// HTTP API interface
@@ -41,6 +42,7 @@ type (
 		Describe func(http.ResponseWriter, *http.Request)
 		RunFresh func(http.ResponseWriter, *http.Request)
 		Run      func(http.ResponseWriter, *http.Request)
+		Export   func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -188,6 +190,22 @@ func NewReport(h ReportAPI) *Report {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Export: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewReportExport()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Export(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -205,5 +223,6 @@ func (h Report) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Post("/reports/describe", h.Describe)
 		r.Post("/reports/run", h.RunFresh)
 		r.Post("/reports/{reportID}/run", h.Run)
+		r.Post("/reports/{reportID}/export/{filename}.{ext}", h.Export)
 	})
 }
@@ -0,0 +1,76 @@
+package handlers
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/api"
+	"github.com/cortezaproject/corteza-server/system/rest/request"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+type (
+	// Internal API interface
+	ResourceTransferAPI interface {
+		Export(context.Context, *request.ResourceTransferExport) (interface{}, error)
+		Import(context.Context, *request.ResourceTransferImport) (interface{}, error)
+	}
+
+	// HTTP API interface
+	ResourceTransfer struct {
+		Export func(http.ResponseWriter, *http.Request)
+		Import func(http.ResponseWriter, *http.Request)
+	}
+)
+
+func NewResourceTransfer(h ResourceTransferAPI) *ResourceTransfer {
+	return &ResourceTransfer{
+		Export: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewResourceTransferExport()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Export(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Import: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewResourceTransferImport()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Import(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+	}
+}
+
+func (h ResourceTransfer) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http.Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares...)
+		r.Get("/resources/export", h.Export)
+		r.Post("/resources/import", h.Import)
+	})
+}
@@ -27,6 +27,7 @@ type (
 		Delete(context.Context, *request.UserDelete) (interface{}, error)
 		Suspend(context.Context, *request.UserSuspend) (interface{}, error)
 		Unsuspend(context.Context, *request.UserUnsuspend) (interface{}, error)
+		Unlock(context.Context, *request.UserUnlock) (interface{}, error)
 		Undelete(context.Context, *request.UserUndelete) (interface{}, error)
 		SetPassword(context.Context, *request.UserSetPassword) (interface{}, error)
 		MembershipList(context.Context, *request.UserMembershipList) (interface{}, error)
@@ -34,25 +35,30 @@ type (
 		MembershipRemove(context.Context, *request.UserMembershipRemove) (interface{}, error)
 		TriggerScript(context.Context, *request.UserTriggerScript) (interface{}, error)
 		SessionsRemove(context.Context, *request.UserSessionsRemove) (interface{}, error)
+		PersonalAccessTokenList(context.Context, *request.UserPersonalAccessTokenList) (interface{}, error)
+		PersonalAccessTokenRevoke(context.Context, *request.UserPersonalAccessTokenRevoke) (interface{}, error)
 	}
 
 	// HTTP API interface
 	User struct {
-		List             func(http.ResponseWriter, *http.Request)
-		Create           func(http.ResponseWriter, *http.Request)
-		Update           func(http.ResponseWriter, *http.Request)
-		PartialUpdate    func(http.ResponseWriter, *http.Request)
-		Read             func(http.ResponseWriter, *http.Request)
-		Delete           func(http.ResponseWriter, *http.Request)
-		Suspend          func(http.ResponseWriter, *http.Request)
-		Unsuspend        func(http.ResponseWriter, *http.Request)
-		Undelete         func(http.ResponseWriter, *http.Request)
-		SetPassword      func(http.ResponseWriter, *http.Request)
-		MembershipList   func(http.ResponseWriter, *http.Request)
-		MembershipAdd    func(http.ResponseWriter, *http.Request)
-		MembershipRemove func(http.ResponseWriter, *http.Request)
-		TriggerScript    func(http.ResponseWriter, *http.Request)
-		SessionsRemove   func(http.ResponseWriter, *http.Request)
+		List                      func(http.ResponseWriter, *http.Request)
+		Create                    func(http.ResponseWriter, *http.Request)
+		Update                    func(http.ResponseWriter, *http.Request)
+		PartialUpdate             func(http.ResponseWriter, *http.Request)
+		Read                      func(http.ResponseWriter, *http.Request)
+		Delete                    func(http.ResponseWriter, *http.Request)
+		Suspend                   func(http.ResponseWriter, *http.Request)
+		Unsuspend                 func(http.ResponseWriter, *http.Request)
+		Unlock                    func(http.ResponseWriter, *http.Request)
+		Undelete                  func(http.ResponseWriter, *http.Request)
+		SetPassword               func(http.ResponseWriter, *http.Request)
+		MembershipList            func(http.ResponseWriter, *http.Request)
+		MembershipAdd             func(http.ResponseWriter, *http.Request)
+		MembershipRemove          func(http.ResponseWriter, *http.Request)
+		TriggerScript             func(http.ResponseWriter, *http.Request)
+		SessionsRemove            func(http.ResponseWriter, *http.Request)
+		PersonalAccessTokenList   func(http.ResponseWriter, *http.Request)
+		PersonalAccessTokenRevoke func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -186,6 +192,22 @@ func NewUser(h UserAPI) *User {
 
 			api.Send(w, r, value)
 		},
+		Unlock: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewUserUnlock()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Unlock(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		Undelete: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewUserUndelete()
@@ -296,6 +318,38 @@ func NewUser(h UserAPI) *User {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		PersonalAccessTokenList: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewUserPersonalAccessTokenList()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.PersonalAccessTokenList(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		PersonalAccessTokenRevoke: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewUserPersonalAccessTokenRevoke()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.PersonalAccessTokenRevoke(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -312,6 +366,7 @@ func (h User) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http.H
 		r.Delete("/users/{userID}", h.Delete)
 		r.Post("/users/{userID}/suspend", h.Suspend)
 		r.Post("/users/{userID}/unsuspend", h.Unsuspend)
+		r.Post("/users/{userID}/unlock", h.Unlock)
 		r.Post("/users/{userID}/undelete", h.Undelete)
 		r.Post("/users/{userID}/password", h.SetPassword)
 		r.Get("/users/{userID}/membership", h.MembershipList)
@@ -319,5 +374,7 @@ func (h User) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http.H
 		r.Delete("/users/{userID}/membership/{roleID}", h.MembershipRemove)
 		r.Post("/users/{userID}/trigger", h.TriggerScript)
 		r.Delete("/users/{userID}/sessions", h.SessionsRemove)
+		r.Get("/users/{userID}/personal-access-tokens", h.PersonalAccessTokenList)
+		r.Delete("/users/{userID}/personal-access-tokens/{tokenID}", h.PersonalAccessTokenRevoke)
 	})
 }
@@ -25,6 +25,17 @@ type (
 		Set    []*queuePayload                `json:"set"`
 	}
 
+	queueMessagePayload struct {
+		*messagebus.QueueMessage
+
+		Payload string `json:"payload"`
+	}
+
+	queueMessageSetPayload struct {
+		Filter messagebus.QueueMessageFilter `json:"filter"`
+		Set    []*queueMessagePayload        `json:"set"`
+	}
+
 	queueService interface {
 		FindByID(ctx context.Context, ID uint64) (q *messagebus.QueueSettings, err error)
 		Create(ctx context.Context, new *messagebus.QueueSettings) (q *messagebus.QueueSettings, err error)
@@ -32,6 +43,8 @@ type (
 		DeleteByID(ctx context.Context, ID uint64) (err error)
 		UndeleteByID(ctx context.Context, ID uint64) (err error)
 		Search(ctx context.Context, filter messagebus.QueueSettingsFilter) (q messagebus.QueueSettingsSet, f messagebus.QueueSettingsFilter, err error)
+		SearchFailed(ctx context.Context, queueID uint64, filter messagebus.QueueMessageFilter) (mm messagebus.QueueMessageSet, f messagebus.QueueMessageFilter, err error)
+		Replay(ctx context.Context, queueID, messageID uint64) (err error)
 	}
 )
 
@@ -106,6 +119,29 @@ func (ctrl *Queue) Undelete(ctx context.Context, r *request.QueuesUndelete) (int
 	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.QueueID)
 }
 
+func (ctrl *Queue) Failed(ctx context.Context, r *request.QueuesFailed) (interface{}, error) {
+	var (
+		err error
+		f   = messagebus.QueueMessageFilter{}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, filter, err := ctrl.svc.SearchFailed(ctx, r.QueueID, f)
+
+	return ctrl.makeMessageFilterPayload(ctx, set, filter, err)
+}
+
+func (ctrl *Queue) Replay(ctx context.Context, r *request.QueuesReplay) (interface{}, error) {
+	return api.OK(), ctrl.svc.Replay(ctx, r.QueueID, r.MessageID)
+}
+
 func (ctrl *Queue) makePayload(ctx context.Context, q *messagebus.QueueSettings, err error) (*queuePayload, error) {
 	if err != nil || q == nil {
 		return nil, err
@@ -131,3 +167,20 @@ func (ctrl *Queue) makeFilterPayload(ctx context.Context, nn messagebus.QueueSet
 
 	return msp, nil
 }
+
+func (ctrl *Queue) makeMessageFilterPayload(ctx context.Context, mm messagebus.QueueMessageSet, f messagebus.QueueMessageFilter, err error) (*queueMessageSetPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	msp := &queueMessageSetPayload{Filter: f, Set: make([]*queueMessagePayload, len(mm))}
+
+	for i := range mm {
+		msp.Set[i] = &queueMessagePayload{
+			QueueMessage: mm[i],
+			Payload:      string(mm[i].Payload),
+		}
+	}
+
+	return msp, nil
+}
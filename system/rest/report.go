@@ -2,6 +2,11 @@ package rest
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/api"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
@@ -27,8 +32,8 @@ type (
 		Update(ctx context.Context, upd *types.Report) (app *types.Report, err error)
 		Delete(ctx context.Context, ID uint64) (err error)
 		Undelete(ctx context.Context, ID uint64) (err error)
-		// @todo
-		// Run(ctx context.Context, ID uint64, dd report.DatasetDefinitionSet) (rr *report.Matrix, err error)
+		Run(ctx context.Context, ID uint64, dd report.FrameDefinitionSet) (rr []*report.Frame, err error)
+		Export(ctx context.Context, ID uint64, format string, templateID uint64, dd report.FrameDefinitionSet) (io.ReadSeeker, error)
 		RunFresh(ctx context.Context, src types.ReportDataSourceSet, st report.StepDefinitionSet, dd report.FrameDefinitionSet) (rr []*report.Frame, err error)
 		DescribeFresh(ctx context.Context, src types.ReportDataSourceSet, st report.StepDefinitionSet, sources ...string) (out report.FrameDescriptionSet, err error)
 	}
@@ -136,8 +141,30 @@ func (ctrl *Report) Undelete(ctx context.Context, r *request.ReportUndelete) (in
 }
 
 func (ctrl *Report) Run(ctx context.Context, r *request.ReportRun) (interface{}, error) {
-	// @todo...
-	return nil, nil
+	rr, err := ctrl.report.Run(ctx, r.ReportID, r.Frames)
+	return ctrl.makeReportFramePayload(ctx, rr, err)
+}
+
+func (ctrl *Report) Export(ctx context.Context, r *request.ReportExport) (interface{}, error) {
+	format := strings.ToLower(strings.TrimSpace(r.Ext))
+
+	doc, err := ctrl.report.Export(ctx, r.ReportID, format, r.TemplateID, r.Frames)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := service.ReportExportContentType(format)
+	if strings.HasPrefix(ct, "text/") {
+		ct += "; charset=utf-8"
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := url.QueryEscape(strings.TrimSpace(r.Filename) + "." + format)
+		w.Header().Add("Content-Disposition", "attachment; filename="+name)
+		w.Header().Add("Content-Type", ct)
+
+		http.ServeContent(w, req, name, time.Now(), doc)
+	}, nil
 }
 
 func (ctrl *Report) Describe(ctx context.Context, r *request.ReportDescribe) (interface{}, error) {
@@ -120,6 +120,40 @@ type (
 		// Queue ID
 		QueueID uint64 `json:",string"`
 	}
+
+	QueuesFailed struct {
+		// QueueID PATH parameter
+		//
+		// Queue ID
+		QueueID uint64 `json:",string"`
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	QueuesReplay struct {
+		// QueueID PATH parameter
+		//
+		// Queue ID
+		QueueID uint64 `json:",string"`
+
+		// MessageID PATH parameter
+		//
+		// Message ID
+		MessageID uint64 `json:",string"`
+	}
 )
 
 // NewQueuesList request
@@ -487,3 +521,127 @@ func (r *QueuesUndelete) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewQueuesFailed request
+func NewQueuesFailed() *QueuesFailed {
+	return &QueuesFailed{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesFailed) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"queueID":    r.QueueID,
+		"limit":      r.Limit,
+		"pageCursor": r.PageCursor,
+		"sort":       r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesFailed) GetQueueID() uint64 {
+	return r.QueueID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesFailed) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesFailed) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesFailed) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *QueuesFailed) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "queueID")
+		r.QueueID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewQueuesReplay request
+func NewQueuesReplay() *QueuesReplay {
+	return &QueuesReplay{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesReplay) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"queueID":   r.QueueID,
+		"messageID": r.MessageID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesReplay) GetQueueID() uint64 {
+	return r.QueueID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r QueuesReplay) GetMessageID() uint64 {
+	return r.MessageID
+}
+
+// Fill processes request and fills internal variables
+func (r *QueuesReplay) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "queueID")
+		r.QueueID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "messageID")
+		r.MessageID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
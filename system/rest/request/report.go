@@ -193,6 +193,33 @@ type (
 		// Report data frame definitions
 		Frames report.FrameDefinitionSet
 	}
+
+	ReportExport struct {
+		// ReportID PATH parameter
+		//
+		// Report ID
+		ReportID uint64 `json:",string"`
+
+		// Filename PATH parameter
+		//
+		// Filename to use
+		Filename string
+
+		// Ext PATH parameter
+		//
+		// Export format (csv, xlsx, html or pdf)
+		Ext string
+
+		// Frames POST parameter
+		//
+		// Report data frame definitions; outermost steps are exported when omitted
+		Frames report.FrameDefinitionSet
+
+		// TemplateID POST parameter
+		//
+		// Template used to render the html and pdf documents
+		TemplateID uint64 `json:",string"`
+	}
 )
 
 // NewReportList request
@@ -856,3 +883,107 @@ func (r *ReportRun) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewReportExport request
+func NewReportExport() *ReportExport {
+	return &ReportExport{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"reportID":   r.ReportID,
+		"filename":   r.Filename,
+		"ext":        r.Ext,
+		"frames":     r.Frames,
+		"templateID": r.TemplateID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) GetReportID() uint64 {
+	return r.ReportID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) GetFilename() string {
+	return r.Filename
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) GetExt() string {
+	return r.Ext
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) GetFrames() report.FrameDefinitionSet {
+	return r.Frames
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ReportExport) GetTemplateID() uint64 {
+	return r.TemplateID
+}
+
+// Fill processes request and fills internal variables
+func (r *ReportExport) Fill(req *http.Request) (err error) {
+
+	if strings.ToLower(req.Header.Get("content-type")) == "application/json" {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		//if val, ok := req.Form["frames[]"]; ok && len(val) > 0  {
+		//    r.Frames, err = report.FrameDefinitionSet(val), nil
+		//    if err != nil {
+		//        return err
+		//    }
+		//}
+
+		if val, ok := req.Form["templateID"]; ok && len(val) > 0 {
+			r.TemplateID, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "reportID")
+		r.ReportID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "filename")
+		r.Filename, err = val, nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "ext")
+		r.Ext, err = val, nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
@@ -0,0 +1,188 @@
+package request
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/go-chi/chi"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// dummy vars to prevent
+// unused imports complain
+var (
+	_ = chi.URLParam
+	_ = multipart.ErrMessageTooLarge
+	_ = payload.ParseUint64s
+	_ = strings.ToLower
+	_ = io.EOF
+	_ = fmt.Errorf
+	_ = json.NewEncoder
+)
+
+type (
+	// Internal API interface
+	ResourceTransferExport struct {
+		// Resource GET parameter
+		//
+		// Resources to export
+		Resource []string
+
+		// Filename GET parameter
+		//
+		// Name of the archive (without extension)
+		Filename string
+	}
+
+	ResourceTransferImport struct {
+		// Upload POST parameter
+		//
+		// Zip archive with YAML files
+		Upload *multipart.FileHeader
+
+		// OnExisting POST parameter
+		//
+		// Existing resources: skip (default), replace, mergeLeft or mergeRight
+		OnExisting string
+
+		// Preview POST parameter
+		//
+		// Report what would be imported without changing anything
+		Preview bool
+	}
+)
+
+// NewResourceTransferExport request
+func NewResourceTransferExport() *ResourceTransferExport {
+	return &ResourceTransferExport{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferExport) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"resource": r.Resource,
+		"filename": r.Filename,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferExport) GetResource() []string {
+	return r.Resource
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferExport) GetFilename() string {
+	return r.Filename
+}
+
+// Fill processes request and fills internal variables
+func (r *ResourceTransferExport) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["resource[]"]; ok {
+			r.Resource, err = val, nil
+			if err != nil {
+				return err
+			}
+		} else if val, ok := tmp["resource"]; ok {
+			r.Resource, err = val, nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["filename"]; ok && len(val) > 0 {
+			r.Filename, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewResourceTransferImport request
+func NewResourceTransferImport() *ResourceTransferImport {
+	return &ResourceTransferImport{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferImport) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"upload":     r.Upload,
+		"onExisting": r.OnExisting,
+		"preview":    r.Preview,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferImport) GetUpload() *multipart.FileHeader {
+	return r.Upload
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferImport) GetOnExisting() string {
+	return r.OnExisting
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ResourceTransferImport) GetPreview() bool {
+	return r.Preview
+}
+
+// Fill processes request and fills internal variables
+func (r *ResourceTransferImport) Fill(req *http.Request) (err error) {
+
+	if strings.ToLower(req.Header.Get("content-type")) == "application/json" {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if _, r.Upload, err = req.FormFile("upload"); err != nil {
+			return fmt.Errorf("error processing uploaded file: %w", err)
+		}
+
+		if val, ok := req.Form["onExisting"]; ok && len(val) > 0 {
+			r.OnExisting, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["preview"]; ok && len(val) > 0 {
+			r.Preview, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
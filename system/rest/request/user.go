@@ -206,6 +206,13 @@ type (
 		UserID uint64 `json:",string"`
 	}
 
+	UserUnlock struct {
+		// UserID PATH parameter
+		//
+		// User ID
+		UserID uint64 `json:",string"`
+	}
+
 	UserUndelete struct {
 		// UserID PATH parameter
 		//
@@ -274,6 +281,25 @@ type (
 		// ID
 		UserID uint64 `json:",string"`
 	}
+
+	UserPersonalAccessTokenList struct {
+		// UserID PATH parameter
+		//
+		// ID
+		UserID uint64 `json:",string"`
+	}
+
+	UserPersonalAccessTokenRevoke struct {
+		// UserID PATH parameter
+		//
+		// ID
+		UserID uint64 `json:",string"`
+
+		// TokenID PATH parameter
+		//
+		// Token ID
+		TokenID uint64 `json:",string"`
+	}
 )
 
 // NewUserList request
@@ -899,6 +925,41 @@ func (r *UserUnsuspend) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewUserUnlock request
+func NewUserUnlock() *UserUnlock {
+	return &UserUnlock{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserUnlock) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"userID": r.UserID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserUnlock) GetUserID() uint64 {
+	return r.UserID
+}
+
+// Fill processes request and fills internal variables
+func (r *UserUnlock) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "userID")
+		r.UserID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewUserUndelete request
 func NewUserUndelete() *UserUndelete {
 	return &UserUndelete{}
@@ -1230,3 +1291,85 @@ func (r *UserSessionsRemove) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewUserPersonalAccessTokenList request
+func NewUserPersonalAccessTokenList() *UserPersonalAccessTokenList {
+	return &UserPersonalAccessTokenList{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserPersonalAccessTokenList) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"userID": r.UserID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserPersonalAccessTokenList) GetUserID() uint64 {
+	return r.UserID
+}
+
+// Fill processes request and fills internal variables
+func (r *UserPersonalAccessTokenList) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "userID")
+		r.UserID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewUserPersonalAccessTokenRevoke request
+func NewUserPersonalAccessTokenRevoke() *UserPersonalAccessTokenRevoke {
+	return &UserPersonalAccessTokenRevoke{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserPersonalAccessTokenRevoke) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"userID":  r.UserID,
+		"tokenID": r.TokenID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserPersonalAccessTokenRevoke) GetUserID() uint64 {
+	return r.UserID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserPersonalAccessTokenRevoke) GetTokenID() uint64 {
+	return r.TokenID
+}
+
+// Fill processes request and fills internal variables
+func (r *UserPersonalAccessTokenRevoke) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "userID")
+		r.UserID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "tokenID")
+		r.TokenID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
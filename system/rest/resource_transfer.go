@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/system/rest/request"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	ResourceTransfer struct {
+		svc resourceTransferService
+	}
+
+	resourceTransferService interface {
+		Export(ctx context.Context, w io.Writer, rr []string) error
+		Import(ctx context.Context, name string, archive []byte, onExisting string, preview bool) (*types.ResourceTransferReport, error)
+	}
+)
+
+func (ResourceTransfer) New() *ResourceTransfer {
+	return &ResourceTransfer{
+		svc: service.DefaultResourceTransfer,
+	}
+}
+
+func (ctrl *ResourceTransfer) Export(ctx context.Context, r *request.ResourceTransferExport) (interface{}, error) {
+	buf := &bytes.Buffer{}
+
+	if err := ctrl.svc.Export(ctx, buf, r.Resource); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(r.Filename)
+	if name == "" {
+		name = "export"
+	}
+
+	name = url.QueryEscape(name + ".zip")
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Content-Disposition", "attachment; filename="+name)
+		w.Header().Add("Content-Type", "application/zip")
+
+		http.ServeContent(w, req, name, time.Now(), bytes.NewReader(buf.Bytes()))
+	}, nil
+}
+
+func (ctrl *ResourceTransfer) Import(ctx context.Context, r *request.ResourceTransferImport) (interface{}, error) {
+	file, err := r.Upload.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	// service rejects archives over the limit
+	archive, err := ioutil.ReadAll(io.LimitReader(file, service.ResourceTransferMaxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	return ctrl.svc.Import(ctx, r.Upload.Filename, archive, r.OnExisting, r.Preview)
+}
+
+// resourceTransferBodyLimit limits size of the request body
+//
+// Besides the archive, body holds other form values and multipart boundaries
+func resourceTransferBodyLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, service.ResourceTransferMaxArchiveSize+(1<<20))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -25,7 +25,7 @@ func MountRoutes(r chi.Router) {
 
 	// Protect all _private_ routes
 	r.Group(func(r chi.Router) {
-		r.Use(auth.MiddlewareValidOnly)
+		r.Use(auth.ComponentAccessTokenCheck("system"))
 
 		handlers.NewAuthClient(AuthClient{}.New()).MountRoutes(r)
 		handlers.NewAutomation(Automation{}.New()).MountRoutes(r)
@@ -42,5 +42,7 @@ func MountRoutes(r chi.Router) {
 		handlers.NewQueues(Queue{}.New()).MountRoutes(r)
 		handlers.NewApigwRoute(ApigwRoute{}.New()).MountRoutes(r)
 		handlers.NewApigwFilter(ApigwFilter{}.New()).MountRoutes(r)
+		handlers.NewApigwSecret(ApigwSecret{}.New()).MountRoutes(r)
+		handlers.NewResourceTransfer(ResourceTransfer{}.New()).MountRoutes(r, resourceTransferBodyLimit)
 	})
 }
@@ -24,6 +24,12 @@ type (
 	User struct {
 		user service.UserService
 		role service.RoleService
+		auth userAuthService
+	}
+
+	userAuthService interface {
+		PersonalAccessTokens(ctx context.Context, userID uint64) ([]*types.PersonalAccessToken, error)
+		RevokePersonalAccessToken(ctx context.Context, userID, tokenID uint64) error
 	}
 
 	userSetPayload struct {
@@ -36,6 +42,7 @@ func (User) New() *User {
 	ctrl := &User{}
 	ctrl.user = service.DefaultUser
 	ctrl.role = service.DefaultRole
+	ctrl.auth = service.DefaultAuth
 	return ctrl
 }
 
@@ -193,6 +200,10 @@ func (ctrl User) Unsuspend(ctx context.Context, r *request.UserUnsuspend) (inter
 	return api.OK(), ctrl.user.Unsuspend(ctx, r.UserID)
 }
 
+func (ctrl User) Unlock(ctx context.Context, r *request.UserUnlock) (interface{}, error) {
+	return api.OK(), ctrl.user.Unlock(ctx, r.UserID)
+}
+
 func (ctrl User) Undelete(ctx context.Context, r *request.UserUndelete) (interface{}, error) {
 	return api.OK(), ctrl.user.Undelete(ctx, r.UserID)
 }
@@ -256,6 +267,14 @@ func (ctrl *User) SessionsRemove(ctx context.Context, r *request.UserSessionsRem
 	return
 }
 
+func (ctrl *User) PersonalAccessTokenList(ctx context.Context, r *request.UserPersonalAccessTokenList) (interface{}, error) {
+	return ctrl.auth.PersonalAccessTokens(ctx, r.UserID)
+}
+
+func (ctrl *User) PersonalAccessTokenRevoke(ctx context.Context, r *request.UserPersonalAccessTokenRevoke) (interface{}, error) {
+	return api.OK(), ctrl.auth.RevokePersonalAccessToken(ctx, r.UserID, r.TokenID)
+}
+
 func (ctrl User) makeFilterPayload(ctx context.Context, uu types.UserSet, f types.UserFilter, err error) (*userSetPayload, error) {
 	if err != nil {
 		return nil, err
@@ -0,0 +1,242 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// Bulk operations (RFC 7644, section 3.7)
+//
+// Each operation is dispatched to the same handlers that serve
+// individual requests. Operations are not executed in a transaction;
+// when operation fails, previous operations are not reverted.
+
+const (
+	urnBulkRequest  = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	urnBulkResponse = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+
+	bulkMaxOperations  = 1000
+	bulkMaxPayloadSize = 1 << 20
+)
+
+var (
+	// matches bulkId references in the path and data
+	bulkIdRef = regexp.MustCompile(`bulkId:([^"/\s]+)`)
+)
+
+type (
+	bulkRequest struct {
+		Schemas      []string                `json:"schemas"`
+		FailOnErrors int                     `json:"failOnErrors"`
+		Operations   []*bulkOperationRequest `json:"Operations"`
+	}
+
+	bulkOperationRequest struct {
+		Method string          `json:"method"`
+		BulkId string          `json:"bulkId,omitempty"`
+		Path   string          `json:"path"`
+		Data   json.RawMessage `json:"data,omitempty"`
+	}
+
+	bulkResponse struct {
+		Schemas    []string                 `json:"schemas"`
+		Operations []*bulkOperationResponse `json:"Operations"`
+	}
+
+	bulkOperationResponse struct {
+		Method   string          `json:"method"`
+		BulkId   string          `json:"bulkId,omitempty"`
+		Location string          `json:"location,omitempty"`
+		Status   string          `json:"status"`
+		Response json.RawMessage `json:"response,omitempty"`
+	}
+
+	bulkHandler struct {
+		externalIdAsPrimary bool
+
+		// serves resource endpoints (Users, Groups)
+		router http.Handler
+	}
+
+	// collects response of the dispatched operation
+	bulkResponseRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+func (h bulkHandler) handle(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var (
+		payload = &bulkRequest{}
+		base    = baseLocation(r, "/Bulk")
+		rsp     = &bulkResponse{Schemas: []string{urnBulkResponse}, Operations: make([]*bulkOperationResponse, 0)}
+
+		// resolved bulkId references
+		refs   = make(map[string]string)
+		failed int
+	)
+
+	raw, err := ioutil.ReadAll(io.LimitReader(r.Body, bulkMaxPayloadSize+1))
+	if err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if len(raw) > bulkMaxPayloadSize {
+		sendError(w, newErrorfResponse(http.StatusRequestEntityTooLarge, "payload exceeds max size of %d bytes", bulkMaxPayloadSize))
+		return
+	}
+
+	if err = json.Unmarshal(raw, payload); err != nil {
+		sendError(w, newScimErrorResponse(scimTypeInvalidSyntax, fmt.Errorf("could not decode bulk payload: %w", err)))
+		return
+	}
+
+	if len(payload.Operations) > bulkMaxOperations {
+		sendError(w, newErrorfResponse(http.StatusRequestEntityTooLarge, "number of operations exceeds max of %d", bulkMaxOperations))
+		return
+	}
+
+	for _, op := range payload.Operations {
+		res := h.dispatch(r.Context(), op, refs)
+		rsp.Operations = append(rsp.Operations, res)
+
+		if res.Response == nil {
+			if res.Location != "" {
+				res.Location = base + res.Location
+			}
+
+			continue
+		}
+
+		failed++
+		if payload.FailOnErrors > 0 && failed >= payload.FailOnErrors {
+			break
+		}
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
+// dispatch runs one operation and collects results
+func (h bulkHandler) dispatch(ctx context.Context, op *bulkOperationRequest, refs map[string]string) (res *bulkOperationResponse) {
+	var (
+		method = strings.ToUpper(op.Method)
+		path   string
+		data   string
+		err    error
+		rec    = &bulkResponseRecorder{header: http.Header{}, status: http.StatusOK}
+	)
+
+	res = &bulkOperationResponse{Method: method, BulkId: op.BulkId}
+
+	fail := func(err *errorResponse) *bulkOperationResponse {
+		res.Status = strconv.Itoa(err.Status)
+		res.Response, _ = json.Marshal(err)
+		return res
+	}
+
+	switch method {
+	case http.MethodPost:
+		if op.BulkId == "" {
+			return fail(newErrorfResponse(http.StatusBadRequest, "bulkId is required for POST operation"))
+		}
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fail(newErrorfResponse(http.StatusBadRequest, "unsupported method: %q", op.Method))
+	}
+
+	if path, err = resolveBulkIdRefs(op.Path, refs); err != nil {
+		return fail(newErrorResponse(http.StatusConflict, err))
+	}
+
+	if data, err = resolveBulkIdRefs(string(op.Data), refs); err != nil {
+		return fail(newErrorResponse(http.StatusConflict, err))
+	}
+
+	// remove chi's routing context from the parent request
+	// so that router can resolve the path of the operation
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, nil)
+
+	req, err := http.NewRequestWithContext(ctx, method, path, strings.NewReader(data))
+	if err != nil {
+		return fail(newErrorResponse(http.StatusBadRequest, err))
+	}
+
+	req.Header.Set("Content-Type", "application/scim+json")
+	h.router.ServeHTTP(rec, req)
+
+	res.Status = strconv.Itoa(rec.status)
+
+	if rec.status >= http.StatusBadRequest {
+		res.Response = rec.body.Bytes()
+		if !json.Valid(res.Response) {
+			res.Response, _ = json.Marshal(newErrorResponse(rec.status, fmt.Errorf("%s", strings.TrimSpace(rec.body.String()))))
+		}
+
+		return res
+	}
+
+	res.Location = strings.TrimRight(path, "/")
+
+	if method == http.MethodPost {
+		// resolve ID of the created resource
+		// and add it to the location
+		created := struct {
+			ID         string `json:"id"`
+			ExternalId string `json:"externalId"`
+		}{}
+
+		_ = json.Unmarshal(rec.body.Bytes(), &created)
+
+		id := created.ID
+		if h.externalIdAsPrimary {
+			id = created.ExternalId
+		}
+
+		refs[op.BulkId] = id
+		res.Location += "/" + id
+	}
+
+	return res
+}
+
+// replaces bulkId:<id> references with IDs of the resources
+// created by the previous operations
+func resolveBulkIdRefs(in string, refs map[string]string) (out string, err error) {
+	out = bulkIdRef.ReplaceAllStringFunc(in, func(m string) string {
+		id, ok := refs[m[len("bulkId:"):]]
+		if !ok {
+			err = fmt.Errorf("unresolved reference %q", m)
+		}
+
+		return id
+	})
+
+	return
+}
+
+func (rec *bulkResponseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *bulkResponseRecorder) Write(b []byte) (int, error) {
+	return rec.body.Write(b)
+}
+
+func (rec *bulkResponseRecorder) WriteHeader(status int) {
+	rec.status = status
+}
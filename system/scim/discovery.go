@@ -0,0 +1,266 @@
+package scim
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// Service provider configuration, resource types and schemas
+//
+// See RFC 7643, sections 5, 6 and 7
+
+const (
+	urnServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	urnResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	urnSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+)
+
+type (
+	supportedResponse struct {
+		Supported bool `json:"supported"`
+	}
+
+	bulkSupportResponse struct {
+		Supported      bool `json:"supported"`
+		MaxOperations  int  `json:"maxOperations"`
+		MaxPayloadSize int  `json:"maxPayloadSize"`
+	}
+
+	filterSupportResponse struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults"`
+	}
+
+	authSchemeResponse struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Primary     bool   `json:"primary,omitempty"`
+	}
+
+	serviceProviderConfigResponse struct {
+		Schemas               []string              `json:"schemas"`
+		Patch                 supportedResponse     `json:"patch"`
+		Bulk                  bulkSupportResponse   `json:"bulk"`
+		Filter                filterSupportResponse `json:"filter"`
+		ChangePassword        supportedResponse     `json:"changePassword"`
+		Sort                  supportedResponse     `json:"sort"`
+		Etag                  supportedResponse     `json:"etag"`
+		AuthenticationSchemes []authSchemeResponse  `json:"authenticationSchemes"`
+		Meta                  *discoveryMeta        `json:"meta"`
+	}
+
+	resourceTypeResponse struct {
+		Schemas     []string       `json:"schemas"`
+		ID          string         `json:"id"`
+		Name        string         `json:"name"`
+		Endpoint    string         `json:"endpoint"`
+		Description string         `json:"description"`
+		Schema      string         `json:"schema"`
+		Meta        *discoveryMeta `json:"meta"`
+	}
+
+	schemaResponse struct {
+		Schemas     []string             `json:"schemas,omitempty"`
+		ID          string               `json:"id"`
+		Name        string               `json:"name"`
+		Description string               `json:"description"`
+		Attributes  []*attributeResponse `json:"attributes"`
+		Meta        *discoveryMeta       `json:"meta,omitempty"`
+	}
+
+	attributeResponse struct {
+		Name          string               `json:"name"`
+		Type          string               `json:"type"`
+		MultiValued   bool                 `json:"multiValued"`
+		Required      bool                 `json:"required"`
+		CaseExact     bool                 `json:"caseExact"`
+		Mutability    string               `json:"mutability"`
+		Returned      string               `json:"returned"`
+		Uniqueness    string               `json:"uniqueness"`
+		SubAttributes []*attributeResponse `json:"subAttributes,omitempty"`
+	}
+
+	discoveryMeta struct {
+		ResourceType string `json:"resourceType"`
+		Location     string `json:"location,omitempty"`
+	}
+
+	discoveryHandler struct{}
+)
+
+func (h discoveryHandler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	send(w, http.StatusOK, &serviceProviderConfigResponse{
+		Schemas: []string{urnServiceProviderConfig},
+		Patch:   supportedResponse{true},
+		Bulk: bulkSupportResponse{
+			Supported:      true,
+			MaxOperations:  bulkMaxOperations,
+			MaxPayloadSize: bulkMaxPayloadSize,
+		},
+		Filter: filterSupportResponse{
+			Supported:  true,
+			MaxResults: listMaxResults,
+		},
+		ChangePassword: supportedResponse{true},
+		Sort:           supportedResponse{false},
+		Etag:           supportedResponse{false},
+		AuthenticationSchemes: []authSchemeResponse{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with the shared secret sent as bearer token",
+			Primary:     true,
+		}},
+		Meta: &discoveryMeta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     baseLocation(r, "/ServiceProviderConfig") + "/ServiceProviderConfig",
+		},
+	})
+}
+
+func (h discoveryHandler) resourceTypes(w http.ResponseWriter, r *http.Request) {
+	var (
+		base = baseLocation(r, "/ResourceTypes")
+		rr   = resourceTypes(base)
+	)
+
+	if id := chi.URLParam(r, "id"); id != "" {
+		for _, rt := range rr {
+			if rt.ID == id {
+				send(w, http.StatusOK, rt)
+				return
+			}
+		}
+
+		sendError(w, newErrorfResponse(http.StatusNotFound, "resource type not found"))
+		return
+	}
+
+	list := make([]interface{}, len(rr))
+	for i := range rr {
+		list[i] = rr[i]
+	}
+
+	rsp, _ := (&listRequest{startIndex: 1, count: listMaxResults}).apply(list, uint(len(list)), true)
+	send(w, http.StatusOK, rsp)
+}
+
+func (h discoveryHandler) schemas(w http.ResponseWriter, r *http.Request) {
+	var (
+		base = baseLocation(r, "/Schemas")
+		ss   = schemas(base)
+	)
+
+	if id := chi.URLParam(r, "id"); id != "" {
+		for _, s := range ss {
+			if s.ID == id {
+				send(w, http.StatusOK, s)
+				return
+			}
+		}
+
+		sendError(w, newErrorfResponse(http.StatusNotFound, "schema not found"))
+		return
+	}
+
+	list := make([]interface{}, len(ss))
+	for i := range ss {
+		list[i] = ss[i]
+	}
+
+	rsp, _ := (&listRequest{startIndex: 1, count: listMaxResults}).apply(list, uint(len(list)), true)
+	send(w, http.StatusOK, rsp)
+}
+
+func resourceTypes(base string) []*resourceTypeResponse {
+	return []*resourceTypeResponse{
+		{
+			Schemas:     []string{urnResourceType},
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "User Account",
+			Schema:      urnUser,
+			Meta:        &discoveryMeta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/User"},
+		},
+		{
+			Schemas:     []string{urnResourceType},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Group (role)",
+			Schema:      urnGroup,
+			Meta:        &discoveryMeta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/Group"},
+		},
+	}
+}
+
+// schemas describe attributes that are supported
+func schemas(base string) []*schemaResponse {
+	return []*schemaResponse{
+		{
+			Schemas:     []string{urnSchema},
+			ID:          urnUser,
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []*attributeResponse{
+				schemaAttr("userName", "string", false),
+				schemaAttr("nickName", "string", false),
+				schemaAttr("displayName", "string", false),
+				schemaAttr("name", "complex", false, schemaAttr("formatted", "string", false)),
+				schemaAttr("emails", "complex", true,
+					schemaAttr("value", "string", false),
+					schemaAttr("primary", "boolean", false),
+				),
+				schemaAttr("active", "boolean", false),
+				writeOnly(schemaAttr("password", "string", false)),
+			},
+			Meta: &discoveryMeta{ResourceType: "Schema", Location: base + "/Schemas/" + urnUser},
+		},
+		{
+			Schemas:     []string{urnSchema},
+			ID:          urnGroup,
+			Name:        "Group",
+			Description: "Group (role)",
+			Attributes: []*attributeResponse{
+				schemaAttr("displayName", "string", false),
+				writeOnly(schemaAttr("members", "complex", true, schemaAttr("value", "string", false))),
+			},
+			Meta: &discoveryMeta{ResourceType: "Schema", Location: base + "/Schemas/" + urnGroup},
+		},
+	}
+}
+
+func schemaAttr(name, typ string, multi bool, sub ...*attributeResponse) *attributeResponse {
+	return &attributeResponse{
+		Name:          name,
+		Type:          typ,
+		MultiValued:   multi,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: sub,
+	}
+}
+
+func writeOnly(a *attributeResponse) *attributeResponse {
+	a.Mutability = "writeOnly"
+	a.Returned = "never"
+	return a
+}
+
+// returns base location of SCIM endpoints
+// by removing endpoint (and everything after it) from the request path
+func baseLocation(r *http.Request, endpoint string) string {
+	var (
+		path = r.URL.Path
+	)
+
+	if i := strings.LastIndex(path, endpoint); i > -1 {
+		path = path[:i]
+	}
+
+	return strings.TrimRight(path, "/")
+}
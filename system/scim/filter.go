@@ -0,0 +1,526 @@
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SCIM filter support (RFC 7644, section 3.4.2.2)
+//
+// Filters are parsed into expression tree that is evaluated against
+// JSON representation of the resource (see resourceAttributes).
+//
+// Supported: eq, ne, co, sw, ew, pr, gt, ge, lt, le, and, or, not,
+// grouping with parentheses and complex attribute filters (emails[type eq "work"])
+
+const (
+	filterOpEq = "eq"
+	filterOpNe = "ne"
+	filterOpCo = "co"
+	filterOpSw = "sw"
+	filterOpEw = "ew"
+	filterOpPr = "pr"
+	filterOpGt = "gt"
+	filterOpGe = "ge"
+	filterOpLt = "lt"
+	filterOpLe = "le"
+
+	filterOpAnd = "and"
+	filterOpOr  = "or"
+	filterOpNot = "not"
+
+	// complex attribute filter
+	filterOpValuePath = "[]"
+)
+
+type (
+	filterExpr struct {
+		// logical operator (and, or, not) or comparison operator
+		Op string
+
+		// attribute path, lowercased and without schema URN prefix
+		Attr string
+
+		// value for comparison, string, float64, bool or nil
+		Value interface{}
+
+		// operands for logical operators and
+		// sub-filter for complex attribute filters
+		Left, Right *filterExpr
+	}
+
+	filterParser struct {
+		tokens []filterToken
+		pos    int
+	}
+
+	filterToken struct {
+		// quoted string value
+		str bool
+
+		val string
+	}
+)
+
+// parseFilter parses SCIM filter expression
+func parseFilter(in string) (*filterExpr, error) {
+	tt, err := tokenizeFilter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tt) == 0 {
+		return nil, nil
+	}
+
+	p := &filterParser{tokens: tt}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos].val)
+	}
+
+	return e, nil
+}
+
+func tokenizeFilter(in string) (tt []filterToken, err error) {
+	var (
+		rr = []rune(in)
+		i  int
+	)
+
+	for i < len(rr) {
+		switch r := rr[i]; {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(' || r == ')' || r == '[' || r == ']':
+			tt = append(tt, filterToken{val: string(r)})
+			i++
+
+		case r == '"':
+			// find closing quote, respecting escapes
+			j := i + 1
+			for ; j < len(rr) && rr[j] != '"'; j++ {
+				if rr[j] == '\\' {
+					j++
+				}
+			}
+
+			if j >= len(rr) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+
+			var s string
+			if s, err = strconv.Unquote(string(rr[i : j+1])); err != nil {
+				return nil, fmt.Errorf("invalid string in filter: %w", err)
+			}
+
+			tt = append(tt, filterToken{str: true, val: s})
+			i = j + 1
+
+		default:
+			j := i
+			for ; j < len(rr) && !unicode.IsSpace(rr[j]) && !strings.ContainsRune(`()[]"`, rr[j]); j++ {
+			}
+
+			tt = append(tt, filterToken{val: string(rr[i:j])})
+			i = j
+		}
+	}
+
+	return
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+
+	return &p.tokens[p.pos]
+}
+
+// checks if next token is (unquoted) keyword and consumes it
+func (p *filterParser) accept(kw string) bool {
+	if t := p.peek(); t != nil && !t.str && strings.EqualFold(t.val, kw) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterParser) expect(kw string) error {
+	if !p.accept(kw) {
+		return fmt.Errorf("expecting %q in filter", kw)
+	}
+
+	return nil
+}
+
+func (p *filterParser) parseOr() (*filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(filterOpOr) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &filterExpr{Op: filterOpOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (*filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(filterOpAnd) {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &filterExpr{Op: filterOpAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (*filterExpr, error) {
+	if !p.accept(filterOpNot) {
+		return p.parseAtom()
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return &filterExpr{Op: filterOpNot, Left: e}, nil
+}
+
+func (p *filterParser) parseAtom() (e *filterExpr, err error) {
+	if p.accept("(") {
+		if e, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+
+		return e, p.expect(")")
+	}
+
+	t := p.peek()
+	if t == nil || t.str {
+		return nil, fmt.Errorf("expecting attribute path in filter")
+	}
+
+	p.pos++
+	attr := normalizeAttrPath(t.val)
+
+	if p.accept("[") {
+		// complex attribute filter: emails[type eq "work" and value co "@example.com"]
+		var sub *filterExpr
+		if sub, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expect("]"); err != nil {
+			return nil, err
+		}
+
+		return &filterExpr{Op: filterOpValuePath, Attr: attr, Left: sub}, nil
+	}
+
+	t = p.peek()
+	if t == nil || t.str {
+		return nil, fmt.Errorf("expecting operator after %q in filter", attr)
+	}
+
+	e = &filterExpr{Op: strings.ToLower(t.val), Attr: attr}
+	p.pos++
+
+	switch e.Op {
+	case filterOpPr:
+		return e, nil
+	case filterOpEq, filterOpNe, filterOpCo, filterOpSw, filterOpEw, filterOpGt, filterOpGe, filterOpLt, filterOpLe:
+	default:
+		return nil, fmt.Errorf("unsupported operator %q in filter", t.val)
+	}
+
+	if t = p.peek(); t == nil {
+		return nil, fmt.Errorf("expecting value after %q in filter", e.Op)
+	}
+
+	p.pos++
+
+	switch {
+	case t.str:
+		e.Value = t.val
+	case t.val == "true" || t.val == "false":
+		e.Value = t.val == "true"
+	case t.val == "null":
+		e.Value = nil
+	default:
+		if e.Value, err = strconv.ParseFloat(t.val, 64); err != nil {
+			return nil, fmt.Errorf("invalid value %q in filter", t.val)
+		}
+	}
+
+	return e, nil
+}
+
+// removes schema URN prefix from the attribute path and lowercases it
+// (attribute names are case insensitive)
+func normalizeAttrPath(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		if i := strings.LastIndex(path, ":"); i > -1 {
+			path = path[i+1:]
+		}
+	}
+
+	return strings.ToLower(path)
+}
+
+// match evaluates filter against resource attributes
+func (e *filterExpr) match(attrs map[string]interface{}) bool {
+	if e == nil {
+		return true
+	}
+
+	switch e.Op {
+	case filterOpAnd:
+		return e.Left.match(attrs) && e.Right.match(attrs)
+	case filterOpOr:
+		return e.Left.match(attrs) || e.Right.match(attrs)
+	case filterOpNot:
+		return !e.Left.match(attrs)
+	case filterOpValuePath:
+		items, _ := attrItems(attrs, e.Attr)
+		for _, v := range items {
+			if sub, ok := v.(map[string]interface{}); ok && e.Left.match(sub) {
+				return true
+			}
+		}
+
+		return false
+	case filterOpPr:
+		for _, v := range lookupAttr(attrs, e.Attr) {
+			if v != nil && v != "" {
+				return true
+			}
+		}
+
+		return false
+	case filterOpNe:
+		for _, v := range lookupAttr(attrs, e.Attr) {
+			if compareFilterValues(filterOpEq, v, e.Value) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		vv := lookupAttr(attrs, e.Attr)
+		if len(vv) == 0 && e.Op == filterOpEq && e.Value == nil {
+			// attr eq null
+			return true
+		}
+
+		for _, v := range vv {
+			if compareFilterValues(e.Op, v, e.Value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// equalities returns attributes that are required by the filter
+// to be equal to a string value
+//
+// Used to narrow down the search before filter is evaluated
+func (e *filterExpr) equalities() map[string]string {
+	var (
+		out = make(map[string]string)
+	)
+
+	if e == nil {
+		return out
+	}
+
+	switch e.Op {
+	case filterOpAnd:
+		for k, v := range e.Left.equalities() {
+			out[k] = v
+		}
+		for k, v := range e.Right.equalities() {
+			out[k] = v
+		}
+	case filterOpEq:
+		if s, ok := e.Value.(string); ok {
+			out[e.Attr] = s
+		}
+	}
+
+	return out
+}
+
+// equalitiesOnly returns true when filter consists only of
+// equality conditions (joined with and) on distinct attributes
+//
+// Such filter is fully expressed with equalities()
+func (e *filterExpr) equalitiesOnly() bool {
+	var (
+		count func(*filterExpr) (int, bool)
+	)
+
+	count = func(e *filterExpr) (int, bool) {
+		if e == nil {
+			return 0, true
+		}
+
+		switch e.Op {
+		case filterOpAnd:
+			l, lok := count(e.Left)
+			r, rok := count(e.Right)
+			return l + r, lok && rok
+		case filterOpEq:
+			_, ok := e.Value.(string)
+			return 1, ok
+		}
+
+		return 0, false
+	}
+
+	n, ok := count(e)
+	return ok && n == len(e.equalities())
+}
+
+// resolves (dotted) attribute path to list of values
+//
+// Multi-valued attributes are flattened; when path points to
+// multi-valued complex attribute (emails) its values are used
+func lookupAttr(attrs map[string]interface{}, path string) (vv []interface{}) {
+	var (
+		name, rest = path, ""
+	)
+
+	if i := strings.Index(path, "."); i > -1 {
+		name, rest = path[:i], path[i+1:]
+	}
+
+	items, multi := attrItems(attrs, name)
+	for _, item := range items {
+		sub, complex := item.(map[string]interface{})
+		switch {
+		case rest != "" && complex:
+			vv = append(vv, lookupAttr(sub, rest)...)
+		case rest != "":
+			// sub attribute of simple value
+		case complex && multi:
+			vv = append(vv, lookupAttr(sub, "value")...)
+		default:
+			vv = append(vv, item)
+		}
+	}
+
+	return
+}
+
+// returns items of the (multi-valued) attribute
+func attrItems(attrs map[string]interface{}, name string) (items []interface{}, multi bool) {
+	for k, v := range attrs {
+		if !strings.EqualFold(k, name) {
+			continue
+		}
+
+		if items, multi = v.([]interface{}); multi {
+			return
+		}
+
+		return []interface{}{v}, false
+	}
+
+	return nil, false
+}
+
+func compareFilterValues(op string, a, b interface{}) bool {
+	switch bv := b.(type) {
+	case string:
+		av, ok := a.(string)
+		if !ok {
+			return false
+		}
+
+		// string comparison is case insensitive
+		av, bv = strings.ToLower(av), strings.ToLower(bv)
+
+		switch op {
+		case filterOpEq:
+			return av == bv
+		case filterOpCo:
+			return strings.Contains(av, bv)
+		case filterOpSw:
+			return strings.HasPrefix(av, bv)
+		case filterOpEw:
+			return strings.HasSuffix(av, bv)
+		case filterOpGt:
+			return av > bv
+		case filterOpGe:
+			return av >= bv
+		case filterOpLt:
+			return av < bv
+		case filterOpLe:
+			return av <= bv
+		}
+
+	case float64:
+		av, ok := a.(float64)
+		if !ok {
+			return false
+		}
+
+		switch op {
+		case filterOpEq:
+			return av == bv
+		case filterOpGt:
+			return av > bv
+		case filterOpGe:
+			return av >= bv
+		case filterOpLt:
+			return av < bv
+		case filterOpLe:
+			return av <= bv
+		}
+
+	case bool:
+		av, ok := a.(bool)
+		return ok && op == filterOpEq && av == bv
+
+	case nil:
+		return op == filterOpEq && a == nil
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	const user = `{
+		"id": "42",
+		"userName": "Foo",
+		"nickName": "foo",
+		"name": {"formatted": "Foo Bar"},
+		"active": true,
+		"meta": {"lastModified": "2021-03-01T10:00:00Z"},
+		"emails": [
+			{"value": "foo@example.com", "type": "work", "primary": true},
+			{"value": "foo@home.tld", "type": "home"}
+		]
+	}`
+
+	var (
+		attrs map[string]interface{}
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(user), &attrs))
+
+	tcc := []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "foo"`, true},
+		{`userName eq "bar"`, false},
+		{`USERNAME Eq "FOO"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "foo"`, true},
+		{`userName ne "bar"`, true},
+		{`userName co "o"`, true},
+		{`userName sw "fo"`, true},
+		{`userName ew "oo"`, true},
+		{`name.formatted eq "foo bar"`, true},
+		{`title pr`, false},
+		{`nickName pr`, true},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`emails eq "foo@home.tld"`, true},
+		{`emails.value co "@example.com"`, true},
+		{`emails[type eq "work" and value co "@example.com"]`, true},
+		{`emails[type eq "home" and value co "@example.com"]`, false},
+		{`meta.lastModified gt "2021-01-01T00:00:00Z"`, true},
+		{`meta.lastModified lt "2021-01-01T00:00:00Z"`, false},
+		{`userName eq "bar" or nickName eq "foo"`, true},
+		{`userName eq "foo" and nickName eq "bar"`, false},
+		{`not (userName eq "foo")`, false},
+		{`(userName eq "bar" or nickName eq "foo") and active eq true`, true},
+		{`userName eq "bar" or nickName eq "foo" and active eq false`, false},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := parseFilter(tc.filter)
+			require.NoError(t, err)
+			require.Equal(t, tc.match, f.match(attrs))
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tcc := []string{
+		`userName`,
+		`userName eq`,
+		`userName foo "bar"`,
+		`userName eq "bar`,
+		`(userName eq "bar"`,
+		`userName eq "bar" baz`,
+		`emails[type eq "work"`,
+		`userName eq bar`,
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc, func(t *testing.T) {
+			_, err := parseFilter(tc)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFilterEqualities(t *testing.T) {
+	f, err := parseFilter(`userName eq "foo" and (externalId eq "42" and active eq true) or nickName eq "bar"`)
+	require.NoError(t, err)
+	require.Empty(t, f.equalities())
+
+	f, err = parseFilter(`userName eq "foo" and (externalId eq "42" and active eq true)`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"username": "foo", "externalid": "42"}, f.equalities())
+	require.False(t, f.equalitiesOnly())
+
+	f, err = parseFilter(`userName eq "foo" and externalId eq "42"`)
+	require.NoError(t, err)
+	require.True(t, f.equalitiesOnly())
+
+	f, err = parseFilter(`userName eq "foo" and userName eq "bar"`)
+	require.NoError(t, err)
+	require.False(t, f.equalitiesOnly())
+}
@@ -24,6 +24,11 @@ type (
 
 const (
 	urnError = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// error types, see RFC 7644, section 3.12
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeTooMany       = "tooMany"
 )
 
 func newUserMetaResponse(u *types.User) *metaResponse {
@@ -67,6 +72,13 @@ func newErrorResponse(httpStatus int, err error) *errorResponse {
 	return er
 }
 
+// creates bad request error response with SCIM error type
+func newScimErrorResponse(scimType string, err error) *errorResponse {
+	er := newErrorResponse(http.StatusBadRequest, err)
+	er.SCIMType = scimType
+	return er
+}
+
 func (e *errorResponse) Error() string {
 	return e.Detail
 }
@@ -37,6 +37,58 @@ func (h groupsHandler) get(w http.ResponseWriter, r *http.Request) {
 	send(w, http.StatusOK, newGroupResourceResponse(res))
 }
 
+// lists groups
+//
+// supports filtering (displayName eq "...") and pagination (startIndex, count)
+func (h groupsHandler) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = h.sec(r)
+		f   = types.RoleFilter{}
+	)
+
+	req, err := newListRequest(r.URL.Query())
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	// narrow down the search with equality conditions;
+	// when filter has any other condition, whole filter
+	// is evaluated on the loaded groups
+	inStore := req.filter.equalitiesOnly()
+	for attr, v := range req.filter.equalities() {
+		switch attr {
+		case "displayname":
+			f.Name = v
+		case "externalid":
+			f.Labels = map[string]string{groupLabel_SCIM_externalId: v}
+		default:
+			inStore = false
+		}
+	}
+
+	f.Paging = req.paging(inStore)
+
+	gg, f, err := h.svc.Find(ctx, f)
+	if err != nil {
+		sendError(w, newErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rr := make([]interface{}, len(gg))
+	for i, g := range gg {
+		rr[i] = newGroupResourceResponse(g)
+	}
+
+	rsp, err := req.apply(rr, f.Total, inStore)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
 func (h groupsHandler) create(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -0,0 +1,167 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+const (
+	urnListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// max number of resources returned in one list response
+	listMaxResults = 1000
+
+	// max number of resources loaded from the store
+	// when filter needs to be evaluated on the loaded resources;
+	// filters that match more resources are rejected
+	listMaxLoaded = 10 * listMaxResults
+)
+
+type (
+	listRequest struct {
+		filter *filterExpr
+
+		// 1-based index of the first result
+		startIndex int
+
+		// max number of results
+		count int
+	}
+
+	listResponse struct {
+		Schemas      []string      `json:"schemas"`
+		TotalResults int           `json:"totalResults"`
+		StartIndex   int           `json:"startIndex"`
+		ItemsPerPage int           `json:"itemsPerPage"`
+		Resources    []interface{} `json:"Resources"`
+	}
+)
+
+// parses filter and pagination parameters
+func newListRequest(q url.Values) (req *listRequest, err error) {
+	req = &listRequest{startIndex: 1, count: listMaxResults}
+
+	if req.filter, err = parseFilter(q.Get("filter")); err != nil {
+		return nil, newScimErrorResponse(scimTypeInvalidFilter, err)
+	}
+
+	if v := q.Get("startIndex"); v != "" {
+		if req.startIndex, err = strconv.Atoi(v); err != nil {
+			return nil, newErrorfResponse(http.StatusBadRequest, "invalid startIndex: %q", v)
+		}
+
+		// values less than 1 are interpreted as 1
+		if req.startIndex < 1 {
+			req.startIndex = 1
+		}
+	}
+
+	if v := q.Get("count"); v != "" {
+		if req.count, err = strconv.Atoi(v); err != nil {
+			return nil, newErrorfResponse(http.StatusBadRequest, "invalid count: %q", v)
+		}
+
+		// negative values are interpreted as 0
+		if req.count < 0 {
+			req.count = 0
+		}
+
+		if req.count > listMaxResults {
+			req.count = listMaxResults
+		}
+	}
+
+	return req, nil
+}
+
+// paging returns paging parameters for the store
+//
+// When store can filter the resources (there is no filter or all of
+// its conditions are passed to the store), only resources up to the end
+// of the requested page are loaded and total is counted by the store.
+//
+// Otherwise, filter is evaluated on the loaded resources; one more than
+// listMaxLoaded is loaded to detect filters that match too many resources.
+func (req *listRequest) paging(inStore bool) (p filter.Paging) {
+	if !inStore {
+		p.Limit = listMaxLoaded + 1
+		return
+	}
+
+	p.IncTotal = true
+	p.Limit = uint(req.startIndex-1) + uint(req.count)
+	if p.Limit == 0 {
+		// zero would load all resources
+		p.Limit = 1
+	}
+
+	return
+}
+
+// apply filters and paginates resources
+//
+// Resources filtered by the store are only paginated
+// and total (counted by the store) is used
+func (req *listRequest) apply(rr []interface{}, total uint, inStore bool) (*listResponse, error) {
+	var (
+		matched = rr
+	)
+
+	if !inStore {
+		if len(rr) > listMaxLoaded {
+			return nil, newScimErrorResponse(scimTypeTooMany, fmt.Errorf("filter matches more than %d resources", listMaxLoaded))
+		}
+
+		matched = make([]interface{}, 0, len(rr))
+		for _, r := range rr {
+			attrs, err := resourceAttributes(r)
+			if err != nil {
+				return nil, newErrorResponse(http.StatusInternalServerError, err)
+			}
+
+			if req.filter.match(attrs) {
+				matched = append(matched, r)
+			}
+		}
+
+		total = uint(len(matched))
+	}
+
+	rsp := &listResponse{
+		Schemas:      []string{urnListResponse},
+		TotalResults: int(total),
+		StartIndex:   req.startIndex,
+		Resources:    make([]interface{}, 0),
+	}
+
+	if from := req.startIndex - 1; from < len(matched) {
+		to := from + req.count
+		if to > len(matched) {
+			to = len(matched)
+		}
+
+		rsp.Resources = append(rsp.Resources, matched[from:to]...)
+	}
+
+	rsp.ItemsPerPage = len(rsp.Resources)
+	return rsp, nil
+}
+
+// converts resource response to generic map of attributes
+// that filter is evaluated against
+func resourceAttributes(r interface{}) (attrs map[string]interface{}, err error) {
+	var (
+		raw []byte
+	)
+
+	if raw, err = json.Marshal(r); err != nil {
+		return nil, fmt.Errorf("could not encode resource: %w", err)
+	}
+
+	return attrs, json.Unmarshal(raw, &attrs)
+}
@@ -36,3 +36,34 @@ func TestOperationsRequestDecodeJSON(t *testing.T) {
 	req.NoError(err)
 	req.Equal(expectedPayload, payload)
 }
+
+func TestOperationsRequestDecodeJSONValues(t *testing.T) {
+	var (
+		req     = require.New(t)
+		payload operationsRequest
+
+		s = strings.NewReader(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:PatchOp"],"Operations":[
+			{"op":"Replace","path":"active","value":false},
+			{"op":"replace","value":{"userName":"foo","name":{"formatted":"Foo Bar"}}},
+			{"op":"add","path":"emails[type eq \"work\"].value","value":"foo@example.com"},
+			{"op":"Add","path":"members","value":[{"value":"1"},{"value":"2"}]},
+			{"op":"remove","path":"nickName"}
+		]}`)
+
+		expectedPayload = operationsRequest{
+			Schemas: []string{"urn:ietf:params:scim:schemas:core:2.0:PatchOp"},
+			Operations: []operationRequest{
+				{Operation: "replace", Path: "active", Value: map[string]string{"active": "false"}},
+				{Operation: "replace", Value: map[string]string{"username": "foo", "name.formatted": "Foo Bar"}},
+				{Operation: "add", Path: `emails[type eq "work"].value`, Value: map[string]string{"emails": "foo@example.com"}},
+				{Operation: "add", Path: "members", Value: map[string]string{"members": "1"}},
+				{Operation: "add", Path: "members", Value: map[string]string{"members": "2"}},
+				{Operation: "remove", Path: "nickName"},
+			},
+		}
+	)
+
+	err := payload.decodeJSON(s)
+	req.NoError(err)
+	req.Equal(expectedPayload, payload)
+}
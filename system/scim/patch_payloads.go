@@ -5,12 +5,23 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 const (
-	urnPatchOp    = "urn:ietf:params:scim:schemas:core:2.0:PatchOp"
-	patchOpAdd    = "add"
-	patchOpRemove = "remove"
+	urnPatchOp     = "urn:ietf:params:scim:schemas:core:2.0:PatchOp"
+	patchOpAdd     = "add"
+	patchOpRemove  = "remove"
+	patchOpReplace = "replace"
+)
+
+var (
+	// matches attribute with value filter: members[value eq "..."]
+	patchValueFilter = regexp.MustCompile(`^([a-zA-Z]\w*)\[value eq \"([^"]+)\"\]$`)
+
+	// matches attribute with any filter and optional sub-attribute: emails[type eq "work"].value
+	patchAnyFilter = regexp.MustCompile(`^([a-zA-Z]\w*)\[[^\]]*\](\.\w+)?$`)
 )
 
 type (
@@ -20,31 +31,160 @@ type (
 		Operations []operationRequest `json:"Operations"`
 	}
 
+	// operation with value, flattened to attribute path & (string) value pairs
+	//
+	// One operation from the payload can result in multiple operations when value
+	// holds multiple values for the same attribute (members: [{value: ...}, {value: ...}])
 	operationRequest struct {
 		Operation string `json:"op"`
 		Path      string `json:"path"`
 		Value     map[string]string
 	}
+
+	// auxiliary struct for decoding raw operations
+	rawOperationRequest struct {
+		Operation string          `json:"op"`
+		Path      string          `json:"path"`
+		Value     json.RawMessage `json:"value"`
+	}
+
+	patchValue struct {
+		path, value string
+	}
 )
 
 func (req *operationsRequest) decodeJSON(r io.Reader) error {
-	if err := json.NewDecoder(r).Decode(req); err != nil {
+	var (
+		aux = struct {
+			Schemas    []string              `json:"schemas"`
+			Operations []rawOperationRequest `json:"Operations"`
+		}{}
+	)
+
+	if err := json.NewDecoder(r).Decode(&aux); err != nil {
 		return fmt.Errorf("could not decode operations payload: %w", err)
 	}
 
-	// Compiles req.Path and updates req.Value
-	for i, op := range req.Operations {
-		r := regexp.MustCompile(`([a-z]\w+)\[value eq \"([\w]{8}-[\w]{4}-[\w]{4}-[\w]{4}-[\w]{12})\"\]`)
-		strings := r.FindStringSubmatch(op.Path)
-		if len(strings) == 3 {
-			key := strings[1]
-			if op.Value == nil {
-				op.Value = make(map[string]string)
-			}
-			op.Value[key] = strings[2]
-			req.Operations[i].Value = op.Value
+	req.Schemas = aux.Schemas
+	req.Operations = nil
+
+	for _, raw := range aux.Operations {
+		oo, err := raw.expand()
+		if err != nil {
+			return fmt.Errorf("could not decode operations payload: %w", err)
 		}
+
+		req.Operations = append(req.Operations, oo...)
 	}
 
 	return nil
 }
+
+// expand normalizes operation & path and flattens operation's value
+func (raw rawOperationRequest) expand() (oo []operationRequest, err error) {
+	var (
+		op    = strings.ToLower(raw.Operation)
+		path  = normalizePatchPath(raw.Path)
+		value interface{}
+		vv    []patchValue
+	)
+
+	if m := patchValueFilter.FindStringSubmatch(raw.Path); len(m) == 3 {
+		// filter in path holds the value:
+		// members[value eq "..."]
+		vv = append(vv, patchValue{strings.ToLower(m[1]), m[2]})
+	}
+
+	if len(raw.Value) > 0 {
+		if err = json.Unmarshal(raw.Value, &value); err != nil {
+			return nil, err
+		}
+
+		vv = append(vv, flattenPatchValue(path, value)...)
+	}
+
+	// distribute values over operations,
+	// each operation can hold one value per path
+	for _, v := range vv {
+		var placed bool
+		for i := range oo {
+			if _, has := oo[i].Value[v.path]; !has {
+				oo[i].Value[v.path] = v.value
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			oo = append(oo, operationRequest{
+				Operation: op,
+				Path:      raw.Path,
+				Value:     map[string]string{v.path: v.value},
+			})
+		}
+	}
+
+	if len(oo) == 0 {
+		oo = append(oo, operationRequest{Operation: op, Path: raw.Path})
+	}
+
+	return
+}
+
+// removes schema prefix and filters from the path
+//
+// emails[type eq "work"].value => emails
+func normalizePatchPath(path string) string {
+	path = normalizeAttrPath(path)
+
+	if m := patchAnyFilter.FindStringSubmatch(path); len(m) > 1 {
+		return m[1]
+	}
+
+	return path
+}
+
+// flattens (complex) value into list of path-value pairs
+//
+// Sub-attributes are joined with dot (name.formatted), multi-valued
+// attributes produce a pair for each value ({"value": "..."} objects are unwrapped)
+func flattenPatchValue(path string, value interface{}) (vv []patchValue) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if path != "" {
+			if _, has := v["value"]; has {
+				// complex value with value sub-attribute
+				// {"value": "...", "primary": true}
+				return flattenPatchValue(path, v["value"])
+			}
+		}
+
+		for k, sub := range v {
+			k = normalizeAttrPath(k)
+			if path != "" {
+				k = path + "." + k
+			}
+
+			vv = append(vv, flattenPatchValue(k, sub)...)
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			vv = append(vv, flattenPatchValue(path, item)...)
+		}
+
+	case string:
+		vv = append(vv, patchValue{path, v})
+
+	case bool:
+		vv = append(vv, patchValue{path, strconv.FormatBool(v)})
+
+	case float64:
+		vv = append(vv, patchValue{path, strconv.FormatFloat(v, 'f', -1, 64)})
+
+	case nil:
+		vv = append(vv, patchValue{path, ""})
+	}
+
+	return
+}
@@ -41,6 +41,34 @@ func Guard(opt options.SCIMOpt) func(next http.Handler) http.Handler {
 }
 
 func Routes(r chi.Router, cfg Config) {
+	mountResources(r, cfg)
+
+	{
+		dh := &discoveryHandler{}
+
+		r.Get("/ServiceProviderConfig", dh.serviceProviderConfig)
+		r.Get("/ResourceTypes", dh.resourceTypes)
+		r.Get("/ResourceTypes/{id}", dh.resourceTypes)
+		r.Get("/Schemas", dh.schemas)
+		r.Get("/Schemas/{id}", dh.schemas)
+	}
+
+	{
+		// bulk operations are dispatched to
+		// a separate router with resource endpoints
+		resources := chi.NewRouter()
+		mountResources(resources, cfg)
+
+		bh := &bulkHandler{
+			externalIdAsPrimary: cfg.ExternalIdAsPrimary,
+			router:              resources,
+		}
+
+		r.Post("/Bulk", bh.handle)
+	}
+}
+
+func mountResources(r chi.Router, cfg Config) {
 	r.Route("/Users", func(r chi.Router) {
 		uh := &usersHandler{
 			externalIdAsPrimary: cfg.ExternalIdAsPrimary,
@@ -51,9 +79,11 @@ func Routes(r chi.Router, cfg Config) {
 			sec:     getSecurityContext,
 		}
 
+		r.Get("/", uh.list)
 		r.Get("/{id}", uh.get)
 		r.Post("/", uh.create)
 		r.Put("/{id}", uh.replace)
+		r.Patch("/{id}", uh.patch)
 		r.Delete("/{id}", uh.delete)
 	})
 
@@ -67,6 +97,7 @@ func Routes(r chi.Router, cfg Config) {
 			sec:     getSecurityContext,
 		}
 
+		r.Get("/", gh.list)
 		r.Get("/{id}", gh.get)
 		r.Post("/", gh.create)
 		r.Put("/{id}", gh.replace)
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-chi/chi"
@@ -40,6 +43,60 @@ func (h usersHandler) get(w http.ResponseWriter, r *http.Request) {
 	send(w, http.StatusOK, newUserResourceResponse(res))
 }
 
+// lists users
+//
+// supports filtering (userName eq "...") and pagination (startIndex, count)
+func (h usersHandler) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = h.sec(r)
+		f   = types.UserFilter{Suspended: filter.StateInclusive}
+	)
+
+	req, err := newListRequest(r.URL.Query())
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	// narrow down the search with equality conditions;
+	// when filter has any other condition, whole filter
+	// is evaluated on the loaded users
+	inStore := req.filter.equalitiesOnly()
+	for attr, v := range req.filter.equalities() {
+		switch attr {
+		case "username":
+			f.Username = v
+		case "emails", "emails.value":
+			f.Email = v
+		case "externalid":
+			f.Labels = map[string]string{userLabel_SCIM_externalId: v}
+		default:
+			inStore = false
+		}
+	}
+
+	f.Paging = req.paging(inStore)
+
+	uu, f, err := h.svc.Find(ctx, f)
+	if err != nil {
+		sendError(w, newErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rr := make([]interface{}, len(uu))
+	for i, u := range uu {
+		rr[i] = newUserResourceResponse(u)
+	}
+
+	rsp, err := req.apply(rr, f.Total, inStore)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
 func (h usersHandler) create(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -147,9 +204,156 @@ func (h usersHandler) save(ctx context.Context, req *userResourceRequest, existi
 		}
 	}
 
+	if req.Active != nil {
+		if err = h.setActive(ctx, res, *req.Active); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
+// patches user
+//
+// supports add, replace and remove operations on userName, nickName, displayName (name.formatted),
+// emails, externalId, password and active attributes; other attributes are ignored
+func (h usersHandler) patch(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var (
+		ctx     = h.sec(r)
+		svc     = h.svc
+		res     = h.lookup(ctx, chi.URLParam(r, "id"), w)
+		payload = &operationsRequest{}
+
+		password string
+		active   *bool
+		err      error
+	)
+
+	if res == nil {
+		return
+	}
+
+	if err = payload.decodeJSON(r.Body); err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	for _, op := range payload.Operations {
+		switch op.Operation {
+		case patchOpAdd, patchOpReplace, patchOpRemove:
+		default:
+			sendError(w, newErrorfResponse(http.StatusBadRequest, "unsupported operation: %q", op.Operation))
+			return
+		}
+
+		if op.Operation == patchOpRemove && len(op.Value) == 0 {
+			// remove operation without value,
+			// attribute is in the path
+			op.Value = map[string]string{normalizePatchPath(op.Path): ""}
+		}
+
+		for path, value := range op.Value {
+			if op.Operation == patchOpRemove {
+				value = ""
+			}
+
+			switch path {
+			case "username":
+				res.Username = value
+
+			case "nickname":
+				if value != "" && !handle.IsValid(value) {
+					sendError(w, newErrorfResponse(http.StatusBadRequest, "invalid nickName: %q", value))
+					return
+				}
+
+				res.Handle = value
+
+			case "displayname", "name", "name.formatted":
+				res.Name = value
+
+			case "emails", "emails.value":
+				if value == "" {
+					sendError(w, newErrorfResponse(http.StatusBadRequest, "email can not be removed"))
+					return
+				}
+
+				res.Email = value
+
+			case "externalid":
+				if value != "" && h.externalIdValidator != nil && !h.externalIdValidator.MatchString(value) {
+					sendError(w, newErrorfResponse(http.StatusBadRequest, "invalid external ID"))
+					return
+				}
+
+				res.SetLabel(userLabel_SCIM_externalId, value)
+
+			case "password":
+				if value == "" {
+					sendError(w, newErrorfResponse(http.StatusBadRequest, "password can not be removed"))
+					return
+				}
+
+				password = value
+
+			case "active":
+				var b bool
+				if b, err = strconv.ParseBool(value); err != nil && value != "" {
+					sendError(w, newErrorfResponse(http.StatusBadRequest, "invalid value for active: %q", value))
+					return
+				}
+
+				active = &b
+			}
+		}
+	}
+
+	if res, err = svc.Update(ctx, res); err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if password != "" {
+		if err = h.passSvc.SetPassword(ctx, res.ID, password); err != nil {
+			sendError(w, newErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+	}
+
+	if active != nil {
+		if err = h.setActive(ctx, res, *active); err != nil {
+			sendError(w, newErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+	}
+
+	send(w, http.StatusOK, newUserResourceResponse(res))
+}
+
+// suspends or unsuspends user
+func (h usersHandler) setActive(ctx context.Context, u *types.User, active bool) (err error) {
+	switch {
+	case active && u.SuspendedAt != nil:
+		if err = h.svc.Unsuspend(ctx, u.ID); err != nil {
+			return
+		}
+
+		u.SuspendedAt = nil
+
+	case !active && u.SuspendedAt == nil:
+		if err = h.svc.Suspend(ctx, u.ID); err != nil {
+			return
+		}
+
+		suspendedAt := time.Now()
+		u.SuspendedAt = &suspendedAt
+	}
+
+	return nil
+}
+
 func (h usersHandler) delete(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx = h.sec(r)
@@ -37,8 +37,10 @@ type (
 		ExternalId string            `json:"externalId,omitempty"`
 		UserName   string            `json:"userName,omitempty"`
 		NickName   string            `json:"nickName,omitempty"`
-		Name       *userNameResponse `json:"displayName"`
+		Name       *userNameResponse `json:"name,omitempty"`
+		Display    string            `json:"displayName,omitempty"`
 		Emails     emailsResponse    `json:"emails,omitempty"`
+		Active     bool              `json:"active"`
 	}
 
 	userResourceRequest struct {
@@ -49,7 +51,9 @@ type (
 		NickName   *string           `json:"nickName,omitempty"`
 		Password   *string           `json:"password,omitempty"`
 		Name       *userNameResponse `json:"name"`
+		Display    *string           `json:"displayName,omitempty"`
 		Emails     emailsResponse    `json:"emails,omitempty"`
+		Active     *bool             `json:"active,omitempty"`
 
 		Groups []*userGroupMembershipRequest `json:"groups,omitempty"`
 	}
@@ -64,10 +68,12 @@ func newUserResourceResponse(u *types.User) *userResourceResponse {
 		UserName:   u.Username,
 		NickName:   u.Handle,
 		Emails:     emailsResponse{{u.Email, true}},
+		Active:     u.SuspendedAt == nil,
 	}
 
 	if u.Name != "" {
 		rsp.Name = &userNameResponse{Formatted: u.Name}
+		rsp.Display = u.Name
 	}
 
 	return rsp
@@ -105,6 +111,8 @@ func (req *userResourceRequest) applyTo(u *types.User) {
 
 	if req.Name != nil {
 		u.Name = req.Name.Formatted
+	} else if req.Display != nil {
+		u.Name = *req.Display
 	}
 
 	if req.UserName != nil {
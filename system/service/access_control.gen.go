@@ -356,6 +356,21 @@ func (svc accessControl) List() (out []map[string]string) {
 			"any":  types.ComponentRbacResource(),
 			"op":   "apigw-filters.search",
 		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "apigw-secrets.manage",
+		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "resources.export",
+		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "resources.import",
+		},
 	}
 
 	func(svc interface{}) {
@@ -824,6 +839,27 @@ func (svc accessControl) CanSearchApigwFilters(ctx context.Context) bool {
 	return svc.can(ctx, "apigw-filters.search", &types.Component{})
 }
 
+// CanManageApigwSecrets checks if current user can manage api gateway secrets
+//
+// This function is auto-generated
+func (svc accessControl) CanManageApigwSecrets(ctx context.Context) bool {
+	return svc.can(ctx, "apigw-secrets.manage", &types.Component{})
+}
+
+// CanExportResources checks if current user can export resources to downloadable archives
+//
+// This function is auto-generated
+func (svc accessControl) CanExportResources(ctx context.Context) bool {
+	return svc.can(ctx, "resources.export", &types.Component{})
+}
+
+// CanImportResources checks if current user can import resources from uploaded archives
+//
+// This function is auto-generated
+func (svc accessControl) CanImportResources(ctx context.Context) bool {
+	return svc.can(ctx, "resources.import", &types.Component{})
+}
+
 // rbacResourceValidator validates known component's resource by routing it to the appropriate validator
 //
 // This function is auto-generated
@@ -945,6 +981,9 @@ func rbacResourceOperations(r string) map[string]bool {
 			"apigw-routes.search":     true,
 			"apigw-filter.create":     true,
 			"apigw-filters.search":    true,
+			"apigw-secrets.manage":    true,
+			"resources.export":        true,
+			"resources.import":        true,
 		}
 	}
 
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	rep "github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/spf13/cast"
+)
+
+type (
+	// actionlogDatasource provides action log entries to the report
+	actionlogDatasource struct {
+		ac        actionlogAccessController
+		actionlog actionlog.Recorder
+	}
+
+	actionlogAccessController interface {
+		CanReadActionLog(ctx context.Context) bool
+	}
+)
+
+// ActionlogDatasource is a report datasource provider for the action log
+func ActionlogDatasource(ac actionlogAccessController, al actionlog.Recorder) *actionlogDatasource {
+	return &actionlogDatasource{ac: ac, actionlog: al}
+}
+
+// Datasource provides action log entries to the report
+//
+// Definition supports from & to timestamps, actorID, resource, action
+// and limit; the number of entries is limited by the store.
+func (svc actionlogDatasource) Datasource(ctx context.Context, ld *rep.LoadStepDefinition) (rep.Datasource, error) {
+	var (
+		err error
+		f   = actionlog.Filter{}
+		def = ld.Definition
+	)
+
+	if v, ok := def["from"]; ok {
+		t, err := cast.ToTimeE(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from timestamp: %w", err)
+		}
+		f.FromTimestamp = &t
+	}
+	if v, ok := def["to"]; ok {
+		t, err := cast.ToTimeE(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to timestamp: %w", err)
+		}
+		f.ToTimestamp = &t
+	}
+	if v, ok := def["actorID"]; ok {
+		f.ActorID = payload.ParseUint64s(cast.ToStringSlice(v))
+	}
+	if v, ok := def["resource"]; ok {
+		f.Resource = cast.ToString(v)
+	}
+	if v, ok := def["action"]; ok {
+		f.Action = cast.ToString(v)
+	}
+	if v, ok := def["limit"]; ok {
+		if f.Limit, err = cast.ToUintE(v); err != nil {
+			return nil, err
+		}
+	}
+
+	cols := rep.FrameColumnSet{
+		datasourceIDColumn("ID", "id", "Action ID"),
+		datasourceColumn("DateTime", "timestamp", "Timestamp"),
+		datasourceColumn("String", "requestOrigin", "Request origin"),
+		datasourceColumn("String", "requestID", "Request ID"),
+		datasourceColumn("String", "actorIPAddr", "Actor IP address"),
+		datasourceColumn("User", "actorID", "Actor"),
+		datasourceColumn("String", "resource", "Resource"),
+		datasourceColumn("String", "action", "Action"),
+		datasourceColumn("String", "error", "Error"),
+		datasourceColumn("Number", "severity", "Severity"),
+		datasourceColumn("String", "description", "Description"),
+	}
+
+	return rep.MemoryDatasource(ld, cols, func(ctx context.Context) ([]map[string]interface{}, error) {
+		if !svc.ac.CanReadActionLog(ctx) {
+			return nil, errors.Unauthorized("not allowed to read action log")
+		}
+
+		aa, _, err := svc.actionlog.Find(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]map[string]interface{}, len(aa))
+		for i, a := range aa {
+			out[i] = map[string]interface{}{
+				"id":            a.ID,
+				"timestamp":     a.Timestamp,
+				"requestOrigin": a.RequestOrigin,
+				"requestID":     a.RequestID,
+				"actorIPAddr":   a.ActorIPAddr,
+				"actorID":       a.ActorID,
+				"resource":      a.Resource,
+				"action":        a.Action,
+				"error":         a.Error,
+				"severity":      int(a.Severity),
+				"description":   a.Description,
+			}
+		}
+
+		return out, nil
+	})
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	a "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+type (
+	apigwSecret struct {
+		actionlog actionlog.Recorder
+		store     store.Storer
+		ac        apigwSecretAccessController
+
+		// cipher is nil when secret key is not configured
+		cipher *secure.Cipher
+	}
+
+	apigwSecretAccessController interface {
+		CanManageApigwSecrets(context.Context) bool
+	}
+)
+
+// ApigwSecret initializes secret service
+//
+// Secret values are encrypted with the API Gateway secret key before
+// they are stored; previous key is used only for reading and rotation
+func ApigwSecret(opt options.ApigwOpt) *apigwSecret {
+	svc := &apigwSecret{
+		ac:        DefaultAccessControl,
+		actionlog: DefaultActionlog,
+		store:     DefaultStore,
+	}
+
+	var err error
+	if svc.cipher, err = secure.NewCipher(opt.SecretKey, opt.SecretKeyPrevious); err != nil {
+		DefaultLogger.Warn("API Gateway secrets disabled", zap.Error(err))
+	}
+
+	return svc
+}
+
+func (svc *apigwSecret) FindByID(ctx context.Context, ID uint64) (q *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+	)
+
+	err = func() error {
+		if ID == 0 {
+			return ApigwSecretErrInvalidID()
+		}
+
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if q, err = store.LookupApigwSecretByID(ctx, svc.store, ID); err != nil {
+			return ApigwSecretErrNotFound(sProps).Wrap(err)
+		}
+
+		sProps.setSecret(q)
+
+		return nil
+	}()
+
+	return q, svc.recordAction(ctx, sProps, ApigwSecretActionLookup, err)
+}
+
+// Create encrypts and stores a new secret
+//
+// Value of the new secret is expected to be in plain text
+func (svc *apigwSecret) Create(ctx context.Context, new *types.ApigwSecret) (q *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{secret: new}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if err = svc.validate(ctx, new, sProps); err != nil {
+			return
+		}
+
+		if new.Value == "" {
+			return ApigwSecretErrInvalidValue(sProps)
+		}
+
+		if new.Value, err = svc.cipher.Encrypt(new.Value); err != nil {
+			return
+		}
+
+		new.ID = nextID()
+		new.CreatedAt = *now()
+		new.CreatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.CreateApigwSecret(ctx, svc.store, new); err != nil {
+			return
+		}
+
+		q = new
+
+		// secret might already be referenced by one of the filters
+		svc.reload(ctx)
+
+		return nil
+	}()
+
+	return q, svc.recordAction(ctx, sProps, ApigwSecretActionCreate, err)
+}
+
+// Update changes handle and/or rotates value of the secret
+//
+// Existing value is kept when new value is empty. Routes are
+// reloaded so filters pick up the new value without being changed.
+func (svc *apigwSecret) Update(ctx context.Context, upd *types.ApigwSecret) (q *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{secret: upd}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if q, err = store.LookupApigwSecretByID(ctx, svc.store, upd.ID); err != nil {
+			return ApigwSecretErrNotFound(sProps)
+		}
+
+		if err = svc.validate(ctx, upd, sProps); err != nil {
+			return
+		}
+
+		if upd.Value != "" {
+			if q.Value, err = svc.cipher.Encrypt(upd.Value); err != nil {
+				return
+			}
+		}
+
+		q.Handle = upd.Handle
+		q.UpdatedAt = now()
+		q.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, q); err != nil {
+			return
+		}
+
+		sProps.setSecret(q)
+		svc.reload(ctx)
+
+		return nil
+	}()
+
+	return q, svc.recordAction(ctx, sProps, ApigwSecretActionUpdate, err)
+}
+
+func (svc *apigwSecret) DeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+		q      *types.ApigwSecret
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if q, err = store.LookupApigwSecretByID(ctx, svc.store, ID); err != nil {
+			return ApigwSecretErrNotFound(sProps)
+		}
+
+		sProps.setSecret(q)
+
+		q.DeletedAt = now()
+		q.DeletedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, q); err != nil {
+			return
+		}
+
+		svc.reload(ctx)
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, sProps, ApigwSecretActionDelete, err)
+}
+
+func (svc *apigwSecret) UndeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+		q      *types.ApigwSecret
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if q, err = store.LookupApigwSecretByID(ctx, svc.store, ID); err != nil {
+			return ApigwSecretErrNotFound(sProps)
+		}
+
+		sProps.setSecret(q)
+
+		if err = svc.uniqueCheck(ctx, q, sProps); err != nil {
+			return
+		}
+
+		q.DeletedAt = nil
+		q.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, q); err != nil {
+			return
+		}
+
+		svc.reload(ctx)
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, sProps, ApigwSecretActionUndelete, err)
+}
+
+func (svc *apigwSecret) Search(ctx context.Context, filter types.ApigwSecretFilter) (r types.ApigwSecretSet, f types.ApigwSecretFilter, err error) {
+	var (
+		sProps = &apigwSecretActionProps{search: &filter}
+	)
+
+	err = func() error {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if r, f, err = store.SearchApigwSecrets(ctx, svc.store, filter); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return r, f, svc.recordAction(ctx, sProps, ApigwSecretActionSearch, err)
+}
+
+// Rotate re-encrypts all secrets that are still encrypted with the previous key
+//
+// Run it after APIGW_SECRET_KEY is changed and the old key is set to
+// APIGW_SECRET_KEY_PREVIOUS; previous key can be removed afterwards
+func (svc *apigwSecret) Rotate(ctx context.Context) (rotated uint, err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage(sProps)
+		}
+
+		if svc.cipher == nil {
+			return ApigwSecretErrStorageNotConfigured(sProps)
+		}
+
+		return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+			ss, _, err := store.SearchApigwSecrets(ctx, s, types.ApigwSecretFilter{Deleted: filter.StateInclusive})
+			if err != nil {
+				return err
+			}
+
+			return ss.Walk(func(q *types.ApigwSecret) (err error) {
+				var changed bool
+				if q.Value, changed, err = svc.cipher.Rotate(q.Value); err != nil || !changed {
+					return
+				}
+
+				rotated++
+				return store.UpdateApigwSecret(ctx, s, q)
+			})
+		})
+	}()
+
+	return rotated, svc.recordAction(ctx, sProps, ApigwSecretActionRotate, err)
+}
+
+func (svc *apigwSecret) validate(ctx context.Context, q *types.ApigwSecret, props *apigwSecretActionProps) error {
+	if svc.cipher == nil {
+		return ApigwSecretErrStorageNotConfigured(props)
+	}
+
+	if !handle.IsValid(q.Handle) {
+		return ApigwSecretErrInvalidHandle(props)
+	}
+
+	return svc.uniqueCheck(ctx, q, props)
+}
+
+func (svc *apigwSecret) uniqueCheck(ctx context.Context, q *types.ApigwSecret, props *apigwSecretActionProps) error {
+	e, err := store.LookupApigwSecretByHandle(ctx, svc.store, q.Handle)
+	if err == nil && e != nil && e.ID != q.ID {
+		return ApigwSecretErrHandleNotUnique(props)
+	}
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// reload signals API Gateway to reload routes
+// and resolve the secret references again
+func (svc *apigwSecret) reload(ctx context.Context) {
+	if gw := apigw.Service(); gw != nil {
+		gw.Reload(ctx)
+	}
+}
@@ -0,0 +1,641 @@
+package service
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// system/service/apigw_secret_actions.yaml
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/locale"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"strings"
+	"time"
+)
+
+type (
+	apigwSecretActionProps struct {
+		secret *types.ApigwSecret
+		search *types.ApigwSecretFilter
+	}
+
+	apigwSecretAction struct {
+		timestamp time.Time
+		resource  string
+		action    string
+		log       string
+		severity  actionlog.Severity
+
+		// prefix for error when action fails
+		errorMessage string
+
+		props *apigwSecretActionProps
+	}
+
+	apigwSecretLogMetaKey   struct{}
+	apigwSecretPropsMetaKey struct{}
+)
+
+var (
+	// just a placeholder to cover template cases w/o fmt package use
+	_ = fmt.Println
+)
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Props methods
+// setSecret updates apigwSecretActionProps's secret
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *apigwSecretActionProps) setSecret(secret *types.ApigwSecret) *apigwSecretActionProps {
+	p.secret = secret
+	return p
+}
+
+// setSearch updates apigwSecretActionProps's search
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *apigwSecretActionProps) setSearch(search *types.ApigwSecretFilter) *apigwSecretActionProps {
+	p.search = search
+	return p
+}
+
+// Serialize converts apigwSecretActionProps to actionlog.Meta
+//
+// This function is auto-generated.
+//
+func (p apigwSecretActionProps) Serialize() actionlog.Meta {
+	var (
+		m = make(actionlog.Meta)
+	)
+
+	if p.secret != nil {
+		m.Set("secret.ID", p.secret.ID, true)
+		m.Set("secret.handle", p.secret.Handle, true)
+	}
+	if p.search != nil {
+	}
+
+	return m
+}
+
+// tr translates string and replaces meta value placeholder with values
+//
+// This function is auto-generated.
+//
+func (p apigwSecretActionProps) Format(in string, err error) string {
+	var (
+		pairs = []string{"{{err}}"}
+		// first non-empty string
+		fns = func(ii ...interface{}) string {
+			for _, i := range ii {
+				if s := fmt.Sprintf("%v", i); len(s) > 0 {
+					return s
+				}
+			}
+
+			return ""
+		}
+	)
+
+	if err != nil {
+		pairs = append(pairs, err.Error())
+	} else {
+		pairs = append(pairs, "nil")
+	}
+
+	if p.secret != nil {
+		// replacement for "{{secret}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{secret}}",
+			fns(
+				p.secret.ID,
+				p.secret.Handle,
+			),
+		)
+		pairs = append(pairs, "{{secret.ID}}", fns(p.secret.ID))
+		pairs = append(pairs, "{{secret.handle}}", fns(p.secret.Handle))
+	}
+
+	if p.search != nil {
+		// replacement for "{{search}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{search}}",
+			fns(),
+		)
+	}
+	return strings.NewReplacer(pairs...).Replace(in)
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action methods
+
+// String returns loggable description as string
+//
+// This function is auto-generated.
+//
+func (a *apigwSecretAction) String() string {
+	var props = &apigwSecretActionProps{}
+
+	if a.props != nil {
+		props = a.props
+	}
+
+	return props.Format(a.log, nil)
+}
+
+func (e *apigwSecretAction) ToAction() *actionlog.Action {
+	return &actionlog.Action{
+		Resource:    e.resource,
+		Action:      e.action,
+		Severity:    e.severity,
+		Description: e.String(),
+		Meta:        e.props.Serialize(),
+	}
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action constructors
+
+// ApigwSecretActionSearch returns "system:apigw-secret.search" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionSearch(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "search",
+		log:       "searched for secrets",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionLookup returns "system:apigw-secret.lookup" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionLookup(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "lookup",
+		log:       "looked-up for a {{secret}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionCreate returns "system:apigw-secret.create" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionCreate(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "create",
+		log:       "created {{secret}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionUpdate returns "system:apigw-secret.update" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionUpdate(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "update",
+		log:       "updated {{secret}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionDelete returns "system:apigw-secret.delete" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionDelete(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "delete",
+		log:       "deleted {{secret}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionUndelete returns "system:apigw-secret.undelete" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionUndelete(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "undelete",
+		log:       "undeleted {{secret}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ApigwSecretActionRotate returns "system:apigw-secret.rotate" action
+//
+// This function is auto-generated.
+//
+func ApigwSecretActionRotate(props ...*apigwSecretActionProps) *apigwSecretAction {
+	a := &apigwSecretAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-secret",
+		action:    "rotate",
+		log:       "re-encrypted secrets with the current key",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Error constructors
+
+// ApigwSecretErrGeneric returns "system:apigw-secret.generic" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrGeneric(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("failed to complete request due to internal error", nil),
+
+		errors.Meta("type", "generic"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(apigwSecretLogMetaKey{}, "{err}"),
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.generic"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrNotFound returns "system:apigw-secret.notFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrNotFound(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("secret not found", nil),
+
+		errors.Meta("type", "notFound"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.notFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrInvalidID returns "system:apigw-secret.invalidID" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrInvalidID(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid ID", nil),
+
+		errors.Meta("type", "invalidID"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.invalidID"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrInvalidHandle returns "system:apigw-secret.invalidHandle" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrInvalidHandle(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid handle", nil),
+
+		errors.Meta("type", "invalidHandle"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.invalidHandle"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrHandleNotUnique returns "system:apigw-secret.handleNotUnique" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrHandleNotUnique(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("secret handle not unique", nil),
+
+		errors.Meta("type", "handleNotUnique"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(apigwSecretLogMetaKey{}, "used duplicate handle ({{secret.handle}}) for secret"),
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.handleNotUnique"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrInvalidValue returns "system:apigw-secret.invalidValue" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrInvalidValue(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("secret value can not be empty", nil),
+
+		errors.Meta("type", "invalidValue"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.invalidValue"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrStorageNotConfigured returns "system:apigw-secret.storageNotConfigured" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrStorageNotConfigured(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("secure storage is not configured", nil),
+
+		errors.Meta("type", "storageNotConfigured"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.storageNotConfigured"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ApigwSecretErrNotAllowedToManage returns "system:apigw-secret.notAllowedToManage" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwSecretErrNotAllowedToManage(mm ...*apigwSecretActionProps) *errors.Error {
+	var p = &apigwSecretActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to manage secrets", nil),
+
+		errors.Meta("type", "notAllowedToManage"),
+		errors.Meta("resource", "system:apigw-secret"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(apigwSecretLogMetaKey{}, "failed to manage secrets; insufficient permissions"),
+		errors.Meta(apigwSecretPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwSecret.errors.notAllowedToManage"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+
+// recordAction is a service helper function wraps function that can return error
+//
+// It will wrap unrecognized/internal errors with generic errors.
+//
+// This function is auto-generated.
+//
+func (svc apigwSecret) recordAction(ctx context.Context, props *apigwSecretActionProps, actionFn func(...*apigwSecretActionProps) *apigwSecretAction, err error) error {
+	if svc.actionlog == nil || actionFn == nil {
+		// action log disabled or no action fn passed, return error as-is
+		return err
+	} else if err == nil {
+		// action completed w/o error, record it
+		svc.actionlog.Record(ctx, actionFn(props).ToAction())
+		return nil
+	}
+
+	a := actionFn(props).ToAction()
+
+	// Extracting error information and recording it as action
+	a.Error = err.Error()
+
+	switch c := err.(type) {
+	case *errors.Error:
+		m := c.Meta()
+
+		a.Error = err.Error()
+		a.Severity = actionlog.Severity(m.AsInt("severity"))
+		a.Description = props.Format(m.AsString(apigwSecretLogMetaKey{}), err)
+
+		if p, has := m[apigwSecretPropsMetaKey{}]; has {
+			a.Meta = p.(*apigwSecretActionProps).Serialize()
+		}
+
+		svc.actionlog.Record(ctx, a)
+	default:
+		svc.actionlog.Record(ctx, a)
+	}
+
+	// Original error is passed on
+	return err
+}
@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	cryptoRand "crypto/rand"
+	"encoding/json"
 	"fmt"
 	rand2 "math/rand"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
@@ -15,6 +21,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/pkg/rand"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service/event"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -34,12 +41,26 @@ type (
 		notifications AuthNotificationService
 
 		providerValidator func(string) error
+
+		// list of breached passwords (optional)
+		breachedPasswords breachedPasswordsChecker
+
+		// failed login attempts per IP address
+		failedLoginsIP *ipFailedLogins
+
+		// LDAP server certificate verification can be skipped
+		// only in development environment
+		ldapAllowInsecure bool
 	}
 
 	authAccessController interface {
 		CanImpersonateUser(context.Context, *types.User) bool
 		CanUpdateUser(context.Context, *types.User) bool
 	}
+
+	breachedPasswordsChecker interface {
+		Contains(password string) (bool, error)
+	}
 )
 
 const (
@@ -51,17 +72,32 @@ const (
 	credentialsTypeCreatePasswordToken         = "password-create-token"
 	credentialsTypeMfaTotpSecret               = "mfa-totp-secret"
 	credentialsTypeMFAEmailOTP                 = "mfa-email-otp"
+	credentialsTypeMfaWebAuthn                 = "mfa-webauthn"
+	credentialsTypeMfaRecoveryCode             = "mfa-recovery-code"
+	credentialsTypeFailedLogins                = "failed-logins"
+	credentialsTypePersonalAccessToken         = "personal-access-token"
 
 	credentialsTokenLength = 32
+
+	// number of recovery codes generated and length of each code (w/o separator)
+	recoveryCodesCount = 10
+	recoveryCodeLength = 10
+
+	// passwords can not be shorter than this, regardless of the settings
+	passwordMinLength = 5
 )
 
 var (
 	reEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+	// separators and whitespace in recovery codes
+	reRecoveryCodeIgnored = regexp.MustCompile(`[^0-9a-zA-Z]`)
 )
 
 func defaultProviderValidator(provider string) error {
-	switch provider {
-	case "saml":
+	switch {
+	case provider == "saml", strings.HasPrefix(provider, "saml:"):
+		// default and additional SAML identity providers
 		return nil
 	default:
 		_, err := goth.GetProvider(provider)
@@ -80,6 +116,8 @@ func Auth() *auth {
 		store:     DefaultStore,
 
 		providerValidator: defaultProviderValidator,
+		breachedPasswords: breachedPasswords,
+		failedLoginsIP:    newIpFailedLogins(),
 	}
 }
 
@@ -283,6 +321,92 @@ func (svc auth) External(ctx context.Context, profile types.ExternalAuthUser) (u
 	return u, svc.recordAction(ctx, aam, AuthActionAuthenticate, err)
 }
 
+// ExternalRoles updates memberships of the roles mapped
+// from the attributes of the external identity provider
+//
+// Roles are given as role handles with flag if user should be a member;
+// memberships of roles that are not mapped are not modified
+func (svc auth) ExternalRoles(ctx context.Context, provider string, userID uint64, roles map[string]bool) (err error) {
+	var (
+		u   *types.User
+		aam = &authActionProps{provider: provider}
+	)
+
+	err = func() (err error) {
+		if u, err = store.LookupUserByID(ctx, svc.store, userID); err != nil {
+			return
+		}
+
+		aam.setUser(u)
+
+		return svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+			return syncMappedRoleMemberships(ctx, s, u.ID, roles)
+		})
+	}()
+
+	return svc.recordAction(ctx, aam, AuthActionExternalRolesSync, err)
+}
+
+// ExternalCredentialsOwners returns IDs of users with valid credentials
+// of the external identity provider
+func (svc auth) ExternalCredentialsOwners(ctx context.Context, provider, credentials string) (ids []uint64, err error) {
+	var (
+		cc types.CredentialsSet
+		f  = types.CredentialsFilter{Kind: provider, Credentials: credentials}
+	)
+
+	if cc, _, err = store.SearchCredentials(ctx, svc.store, f); err != nil {
+		return
+	}
+
+	for _, c := range cc {
+		if c.Valid() {
+			ids = append(ids, c.OwnerID)
+		}
+	}
+
+	return
+}
+
+// adds and removes user from the mapped roles
+//
+// Roles are given as role handles with flag if user should be a member;
+// roles that do not exist are ignored
+func syncMappedRoleMemberships(ctx context.Context, s store.Storer, userID uint64, roles map[string]bool) error {
+	mm, _, err := store.SearchRoleMembers(ctx, s, types.RoleMemberFilter{UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	for roleHandle, member := range roles {
+		r, err := store.LookupRoleByHandle(ctx, s, roleHandle)
+		if errors.IsNotFound(err) {
+			// mapped role does not exist
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		var isMember bool
+		for _, m := range mm {
+			isMember = isMember || m.RoleID == r.ID
+		}
+
+		switch {
+		case member && !isMember:
+			err = store.CreateRoleMember(ctx, s, &types.RoleMember{RoleID: r.ID, UserID: userID})
+		case !member && isMember:
+			err = store.DeleteRoleMemberByUserIDRoleID(ctx, s, userID, r.ID)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // InternalSignUp protocol
 //
 // Forgiving but strict: valid existing users get notified
@@ -341,6 +465,11 @@ func (svc auth) InternalSignUp(ctx context.Context, input *types.User, password
 			return err
 		}
 
+		// check password before user is created
+		if err = svc.checkPasswordStrength(password, aam); err != nil {
+			return err
+		}
+
 		// if !svc.settings.internalSignUpSendEmailOnExisting {
 		// 	return nil,errors.Wrap(err, "user with this email already exists")
 		// }
@@ -451,11 +580,25 @@ func (svc auth) InternalLogin(ctx context.Context, email string, password string
 
 		u, err = store.LookupUserByEmail(ctx, svc.store, email)
 		if errors.IsNotFound(err) {
+			if err = svc.checkLoginAttempts(ctx, nil); err != nil {
+				return err
+			}
+
+			if err = svc.registerFailedLogin(ctx, nil); err != nil {
+				return err
+			}
+
 			return AuthErrInvalidCredentials(aam)
 		} else if err != nil {
 			return err
 		}
 
+		aam.setUser(u)
+
+		if err = svc.checkLoginAttempts(ctx, u); err != nil {
+			return err
+		}
+
 		// Update audit meta with found user
 		ctx = internalAuth.SetIdentityToContext(ctx, u)
 		cc, _, err = store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{OwnerID: u.ID, Kind: credentialsTypePassword})
@@ -465,9 +608,17 @@ func (svc auth) InternalLogin(ctx context.Context, email string, password string
 
 		c := cc.CompareHashAndPassword(password)
 		if c == nil {
+			if err = svc.registerFailedLogin(ctx, u); err != nil {
+				return err
+			}
+
 			return AuthErrInvalidCredentials(aam)
 		}
 
+		if err = svc.clearFailedLogins(ctx, u.ID); err != nil {
+			return err
+		}
+
 		aam.setCredentials(c)
 		ctx = internalAuth.SetIdentityToContext(ctx, u)
 
@@ -500,8 +651,8 @@ func (svc auth) SetPassword(ctx context.Context, userID uint64, password string)
 			return AuthErrInternalLoginDisabledByConfig(aam)
 		}
 
-		if !svc.CheckPasswordStrength(password) {
-			return AuthErrPasswordNotSecure(aam)
+		if err = svc.checkPasswordStrength(password, aam); err != nil {
+			return err
 		}
 
 		u, err = store.LookupUserByID(ctx, svc.store, userID)
@@ -512,11 +663,11 @@ func (svc auth) SetPassword(ctx context.Context, userID uint64, password string)
 		aam.setUser(u)
 		ctx = internalAuth.SetIdentityToContext(ctx, u)
 
-		if err != svc.SetPasswordCredentials(ctx, userID, password) {
+		if err = svc.SetPasswordCredentials(ctx, userID, password); err != nil {
 			return err
 		}
 
-		return nil
+		return svc.clearPasswordChangeRequired(ctx, u)
 	}()
 
 	return svc.recordAction(ctx, aam, AuthActionChangePassword, err)
@@ -566,8 +717,8 @@ func (svc auth) ChangePassword(ctx context.Context, userID uint64, oldPassword,
 			return AuthErrPasswordNotSecure(aam)
 		}
 
-		if !svc.CheckPasswordStrength(newPassword) {
-			return AuthErrPasswordNotSecure(aam)
+		if err = svc.checkPasswordStrength(newPassword, aam); err != nil {
+			return err
 		}
 
 		u, err = store.LookupUserByID(ctx, svc.store, userID)
@@ -587,11 +738,11 @@ func (svc auth) ChangePassword(ctx context.Context, userID uint64, oldPassword,
 			return AuthErrPasswodResetFailedOldPasswordCheckFailed(aam)
 		}
 
-		if err != svc.SetPasswordCredentials(ctx, userID, newPassword) {
+		if err = svc.SetPasswordCredentials(ctx, userID, newPassword); err != nil {
 			return err
 		}
 
-		return nil
+		return svc.clearPasswordChangeRequired(ctx, u)
 	}()
 
 	return svc.recordAction(ctx, aam, AuthActionChangePassword, err)
@@ -602,11 +753,139 @@ func (svc auth) hashPassword(password string) (hash []byte, err error) {
 }
 
 func (svc auth) CheckPasswordStrength(password string) bool {
-	if len(password) <= 4 {
-		return false
+	return svc.checkPasswordStrength(password) == nil
+}
+
+// checkPasswordStrength checks password against password constraints
+// from the settings and against the list of breached passwords
+//
+// Action props, when given, are attached to the returned errors
+func (svc auth) checkPasswordStrength(password string, aam ...*authActionProps) error {
+	var (
+		pc = svc.settings.Auth.Internal.PasswordConstraints
+
+		minLength                  = pc.MinLength
+		upper, lower, num, special uint
+	)
+
+	if minLength < passwordMinLength {
+		minLength = passwordMinLength
+	}
+
+	if uint(utf8.RuneCountInString(password)) < minLength {
+		return AuthErrPasswordNotSecure(aam...)
+	}
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			num++
+		case !unicode.IsLetter(r):
+			special++
+		}
+	}
+
+	if upper < pc.MinUpperCase || lower < pc.MinLowerCase || num < pc.MinNumCount || special < pc.MinSpecial {
+		return AuthErrPasswordNotSecure(aam...)
+	}
+
+	if svc.breachedPasswords != nil {
+		if breached, err := svc.breachedPasswords.Contains(password); err != nil {
+			return err
+		} else if breached {
+			return AuthErrPasswordBreached(aam...)
+		}
 	}
 
-	return true
+	return nil
+}
+
+// checkPasswordHistory makes sure that password is not one of
+// the last N (as configured) user's passwords, current one included
+func (svc auth) checkPasswordHistory(ctx context.Context, userID uint64, password string) error {
+	n := int(svc.settings.Auth.Internal.PasswordConstraints.History)
+	if n == 0 {
+		return nil
+	}
+
+	// old passwords are kept as soft-deleted credentials
+	cc, _, err := store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypePassword,
+		Deleted: filter.StateInclusive,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// newest first
+	sort.Slice(cc, func(i, j int) bool {
+		if cc[i].CreatedAt.Equal(cc[j].CreatedAt) {
+			return cc[i].ID > cc[j].ID
+		}
+
+		return cc[i].CreatedAt.After(cc[j].CreatedAt)
+	})
+
+	if len(cc) > n {
+		cc = cc[:n]
+	}
+
+	for _, c := range cc {
+		if bcrypt.CompareHashAndPassword([]byte(c.Credentials), []byte(password)) == nil {
+			return AuthErrPasswordReused()
+		}
+	}
+
+	return nil
+}
+
+// PasswordChangeRequired checks if user needs to change the password
+//
+// Change is required when it was requested through user's security policy
+// or when password is older than allowed by password constraints
+func (svc auth) PasswordChangeRequired(ctx context.Context, u *types.User) (bool, error) {
+	if u.Meta != nil && u.Meta.SecurityPolicy.PasswordChangeRequired {
+		return true, nil
+	}
+
+	maxAge := svc.settings.Auth.Internal.PasswordConstraints.MaxAge
+	if maxAge == 0 {
+		return false, nil
+	}
+
+	cc, _, err := store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{
+		OwnerID: u.ID,
+		Kind:    credentialsTypePassword,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range cc {
+		if c.Valid() && now().Sub(c.CreatedAt) < time.Duration(maxAge)*24*time.Hour {
+			return false, nil
+		}
+	}
+
+	return len(cc) > 0, nil
+}
+
+// clears password change requirement after user changes the password
+func (svc auth) clearPasswordChangeRequired(ctx context.Context, u *types.User) error {
+	if u.Meta == nil || !u.Meta.SecurityPolicy.PasswordChangeRequired {
+		return nil
+	}
+
+	u.Meta.SecurityPolicy.PasswordChangeRequired = false
+	u.UpdatedAt = now()
+	return store.UpdateUser(ctx, svc.store, u)
 }
 
 // SetPasswordCredentials (soft) deletes old password entry and creates a new entry with new password on every change
@@ -620,6 +899,10 @@ func (svc auth) SetPasswordCredentials(ctx context.Context, userID uint64, passw
 		hash []byte
 	)
 
+	if err = svc.checkPasswordHistory(ctx, userID, password); err != nil {
+		return
+	}
+
 	if hash, err = svc.hashPassword(password); err != nil {
 		return
 	}
@@ -903,7 +1186,8 @@ func (svc auth) procLogin(ctx context.Context, s store.Storer, u *types.User, c
 		return AuthErrFailedForDeletedUser()
 	case u.Kind == types.SystemUser:
 		return AuthErrFailedForSystemUser()
-
+	case u.Kind == types.BotUser:
+		return AuthErrFailedForBotUser()
 	}
 
 	if err = svc.LoadRoleMemberships(ctx, u); err != nil {
@@ -1223,6 +1507,11 @@ func (svc auth) RemoveTOTP(ctx context.Context, userID uint64, code string) (u *
 			return err
 		}
 
+		// recovery codes are useless without TOTP
+		if err = svc.revokeRecoveryCodes(ctx, s, u.ID); err != nil {
+			return err
+		}
+
 		u.Meta.SecurityPolicy.MFA.EnforcedTOTP = false
 		return store.UpdateUser(ctx, s, u)
 
@@ -1291,6 +1580,204 @@ func (auth) revokeAllTOTP(ctx context.Context, s store.Credentials, userID uint6
 	})
 }
 
+// GenerateRecoveryCodes replaces user's MFA recovery codes with a new set
+//
+// Recovery codes can be used instead of TOTP, each of them only once.
+// Only hashes are stored; plain codes are returned and should be shown to the user only once
+func (svc auth) GenerateRecoveryCodes(ctx context.Context) (codes []string, err error) {
+	var (
+		u    *types.User
+		kind = credentialsTypeMfaRecoveryCode
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.TOTP.Enabled {
+			return AuthErrDisabledMFAWithTOTP()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if !u.Meta.SecurityPolicy.MFA.EnforcedTOTP {
+			return AuthErrUnconfiguredTOTP()
+		}
+
+		if err = svc.revokeRecoveryCodes(ctx, s, u.ID); err != nil {
+			return err
+		}
+
+		codes = make([]string, recoveryCodesCount)
+		cc := make(types.CredentialsSet, recoveryCodesCount)
+		for c := range codes {
+			if codes[c], err = generateRecoveryCode(); err != nil {
+				return err
+			}
+
+			hash, err := bcrypt.GenerateFromPassword([]byte(codes[c]), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+
+			cc[c] = &types.Credentials{
+				ID:          nextID(),
+				CreatedAt:   *now(),
+				OwnerID:     u.ID,
+				Kind:        kind,
+				Credentials: string(hash),
+			}
+		}
+
+		return store.CreateCredentials(ctx, s, cc...)
+	})
+
+	if err != nil {
+		codes = nil
+	}
+
+	return codes, svc.recordAction(ctx, aam, AuthActionRecoveryCodesGenerate, err)
+}
+
+// ValidateRecoveryCode checks the code against user's unused recovery codes
+//
+// Matched code is used up. Invalid codes are counted as failed login
+// attempts and the account is locked when lockout is enabled
+func (svc auth) ValidateRecoveryCode(ctx context.Context, code string) (err error) {
+	var (
+		u    *types.User
+		kind = credentialsTypeMfaRecoveryCode
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = func() error {
+		if !svc.settings.Auth.MultiFactor.TOTP.Enabled {
+			return AuthErrDisabledMFAWithTOTP()
+		}
+
+		u, err = store.LookupUserByID(ctx, svc.store, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if err = svc.checkLoginAttempts(ctx, u); err != nil {
+			if AuthErrInvalidCredentials().Is(err) {
+				return AuthErrInvalidRecoveryCode(aam)
+			}
+
+			return err
+		}
+
+		err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+			// codes are compared without separators and whitespace
+			code = strings.ToLower(reRecoveryCodeIgnored.ReplaceAllString(code, ""))
+			if len(code) != recoveryCodeLength {
+				return AuthErrInvalidRecoveryCode(aam)
+			}
+
+			cc, err := findRecoveryCodes(ctx, s, u.ID)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range cc {
+				if bcrypt.CompareHashAndPassword([]byte(c.Credentials), []byte(formatRecoveryCode(code))) != nil {
+					continue
+				}
+
+				c.LastUsedAt = now()
+				c.DeletedAt = now()
+				return store.UpdateCredentials(ctx, s, c)
+			}
+
+			return AuthErrInvalidRecoveryCode(aam)
+		})
+
+		if AuthErrInvalidRecoveryCode().Is(err) {
+			if ferr := svc.registerFailedLogin(ctx, u); ferr != nil {
+				return ferr
+			}
+
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		return svc.clearFailedLogins(ctx, u.ID)
+	}()
+
+	return svc.recordAction(ctx, aam, AuthActionRecoveryCodeUse, err)
+}
+
+// RecoveryCodes returns all unused recovery codes (hashes) of the user
+func (svc auth) RecoveryCodes(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return findRecoveryCodes(ctx, svc.store, userID)
+}
+
+func findRecoveryCodes(ctx context.Context, s store.Credentials, userID uint64) (cc types.CredentialsSet, err error) {
+	cc, _, err = store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeMfaRecoveryCode,
+		Deleted: filter.StateExcluded,
+	})
+
+	return
+}
+
+// Revokes all unused user's recovery codes
+func (auth) revokeRecoveryCodes(ctx context.Context, s store.Credentials, userID uint64) error {
+	cc, err := findRecoveryCodes(ctx, s, userID)
+	if err != nil {
+		return err
+	}
+
+	return cc.Walk(func(c *types.Credentials) error {
+		c.DeletedAt = now()
+		return store.UpdateCredentials(ctx, s, c)
+	})
+}
+
+// Generates random recovery code in "xxxxx-xxxxx" format
+func generateRecoveryCode() (string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	var (
+		b     = make([]byte, 0, recoveryCodeLength)
+		buf   = make([]byte, recoveryCodeLength)
+		limit = byte(256 / len(alphabet) * len(alphabet))
+	)
+
+	for len(b) < recoveryCodeLength {
+		if _, err := cryptoRand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, r := range buf {
+			// skip values that would make some characters more likely
+			if r < limit && len(b) < recoveryCodeLength {
+				b = append(b, alphabet[int(r)%len(alphabet)])
+			}
+		}
+	}
+
+	return formatRecoveryCode(string(b)), nil
+}
+
+func formatRecoveryCode(code string) string {
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+}
+
 func (svc auth) SendEmailOTP(ctx context.Context) (err error) {
 	var (
 		otp  string
@@ -1412,6 +1899,355 @@ func (svc auth) ValidateEmailOTP(ctx context.Context, code string) (err error) {
 	return svc.recordAction(ctx, aam, AuthActionEmailOtpVerify, err)
 }
 
+// WebAuthnCredentials returns all security keys registered by the user
+func (svc auth) WebAuthnCredentials(ctx context.Context, userID uint64) (cc types.CredentialsSet, err error) {
+	cc, _, err = store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeMfaWebAuthn,
+		Deleted: filter.StateExcluded,
+	})
+
+	return
+}
+
+// WebAuthnRegistrationOptions prepares options for the security key registration ceremony
+//
+// Keys that are already registered by the user are excluded
+func (svc auth) WebAuthnRegistrationOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (o *webauthn.CreationOptions, err error) {
+	var (
+		u  *types.User
+		cc types.CredentialsSet
+		i  = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+		return nil, AuthErrDisabledMFAWithWebAuthn()
+	}
+
+	if u, err = store.LookupUserByID(ctx, svc.store, i.Identity()); err != nil {
+		return nil, err
+	}
+
+	if cc, err = svc.WebAuthnCredentials(ctx, u.ID); err != nil {
+		return nil, err
+	}
+
+	user := webauthn.User{
+		ID:          []byte(strconv.FormatUint(u.ID, 10)),
+		Name:        u.Email,
+		DisplayName: u.Name,
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = u.Email
+	}
+
+	return rp.CreationOptions(challenge, user, svc.settings.Auth.MultiFactor.WebAuthn.Passwordless, webAuthnCredentialIDs(cc)...), nil
+}
+
+// ConfigureWebAuthn verifies the registration ceremony response and
+// stores the security key in user's credentials
+//
+// It returns the user with security policy changes
+func (svc auth) ConfigureWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AttestationResponse, label string) (u *types.User, err error) {
+	var (
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind, Label: label}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		wc, err := rp.VerifyRegistration(challenge, r)
+		if err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		meta, err := json.Marshal(wc)
+		if err != nil {
+			return err
+		}
+
+		if label == "" {
+			label = "Security key"
+		}
+
+		cred := &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			OwnerID:     u.ID,
+			Label:       label,
+			Kind:        kind,
+			Credentials: webauthn.Encode(wc.ID),
+			Meta:        meta,
+		}
+
+		aam.setCredentials(cred)
+
+		if err = store.CreateCredentials(ctx, s, cred); err != nil {
+			return err
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebAuthnConfigure, err)
+}
+
+// WebAuthnAssertionOptions prepares options for the security key authentication ceremony
+//
+// Without identity in the context (passwordless login) no keys are
+// listed and authenticator offers one of its discoverable credentials
+func (svc auth) WebAuthnAssertionOptions(ctx context.Context, rp webauthn.RelyingParty, challenge []byte) (o *webauthn.RequestOptions, err error) {
+	var (
+		cc types.CredentialsSet
+		i  = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+		return nil, AuthErrDisabledMFAWithWebAuthn()
+	}
+
+	if i == nil || i.Identity() == 0 {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Passwordless {
+			return nil, AuthErrDisabledPasswordlessWebAuthn()
+		}
+
+		return rp.RequestOptions(challenge), nil
+	}
+
+	if cc, err = svc.WebAuthnCredentials(ctx, i.Identity()); err != nil {
+		return nil, err
+	}
+
+	if len(cc) == 0 {
+		return nil, AuthErrUnconfiguredWebAuthn()
+	}
+
+	return rp.RequestOptions(challenge, webAuthnCredentialIDs(cc)...), nil
+}
+
+// ValidateWebAuthn verifies the authentication ceremony response
+// with one of the security keys registered by the user
+func (svc auth) ValidateWebAuthn(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) (err error) {
+	var (
+		u    *types.User
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		_, err = svc.verifyWebAuthn(ctx, s, rp, challenge, r, u.ID, aam)
+		return err
+	})
+
+	return svc.recordAction(ctx, aam, AuthActionWebAuthnValidate, err)
+}
+
+// WebAuthnLogin authenticates user with the security key, without password
+//
+// User is looked up by the credential that the authenticator offered
+func (svc auth) WebAuthnLogin(ctx context.Context, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse) (u *types.User, err error) {
+	var (
+		c            *types.Credentials
+		kind         = credentialsTypeMfaWebAuthn
+		authProvider = &types.AuthProvider{Provider: kind}
+		aam          = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled || !svc.settings.Auth.MultiFactor.WebAuthn.Passwordless {
+			return AuthErrDisabledPasswordlessWebAuthn()
+		}
+
+		if r == nil {
+			return AuthErrInvalidWebAuthn(aam)
+		}
+
+		cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+			Kind:        kind,
+			Credentials: webauthn.Encode(r.ID),
+			Deleted:     filter.StateExcluded,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(cc) != 1 {
+			return AuthErrInvalidWebAuthn(aam)
+		}
+
+		u, err = store.LookupUserByID(ctx, s, cc[0].OwnerID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if c, err = svc.verifyWebAuthn(ctx, s, rp, challenge, r, u.ID, aam); err != nil {
+			return err
+		}
+
+		ctx = internalAuth.SetIdentityToContext(ctx, u)
+		return svc.procLogin(ctx, s, u, c, authProvider)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionAuthenticate, err)
+}
+
+// RemoveWebAuthn removes security key from user's credentials
+//
+// Enforcement is lifted from the user when the last key is removed;
+// when keys are enforced globally the last key can not be removed
+//
+// It returns the user with security policy changes
+func (svc auth) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error) {
+	var (
+		c    *types.Credentials
+		cc   types.CredentialsSet
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+		self = i != nil && i.Identity() == userID
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, userID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if !self && !svc.ac.CanUpdateUser(ctx, u) {
+			return AuthErrNotAllowedToRemoveWebAuthn()
+		}
+
+		if cc, err = svc.WebAuthnCredentials(ctx, u.ID); err != nil {
+			return err
+		}
+
+		if c = cc.FindByID(credentialsID); c == nil {
+			return AuthErrUnconfiguredWebAuthn()
+		}
+
+		aam.setCredentials(c)
+
+		if len(cc) == 1 && svc.settings.Auth.MultiFactor.WebAuthn.Enforced {
+			return AuthErrEnforcedMFAWithWebAuthn()
+		}
+
+		c.DeletedAt = now()
+		if err = store.UpdateCredentials(ctx, s, c); err != nil {
+			return err
+		}
+
+		if len(cc) > 1 {
+			return nil
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = false
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebAuthnRemove, err)
+}
+
+// Verifies assertion with one of the user's security keys and updates the signature counter
+func (svc auth) verifyWebAuthn(ctx context.Context, s store.Credentials, rp webauthn.RelyingParty, challenge []byte, r *webauthn.AssertionResponse, userID uint64, aam *authActionProps) (*types.Credentials, error) {
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeMfaWebAuthn,
+		Deleted: filter.StateExcluded,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cc) == 0 {
+		return nil, AuthErrUnconfiguredWebAuthn()
+	}
+
+	if r == nil {
+		return nil, AuthErrInvalidWebAuthn(aam)
+	}
+
+	for _, c := range cc {
+		if c.Credentials != webauthn.Encode(r.ID) {
+			continue
+		}
+
+		aam.setCredentials(c)
+
+		wc := &webauthn.Credential{}
+		if err = json.Unmarshal(c.Meta, wc); err != nil {
+			return nil, err
+		}
+
+		if wc.SignCount, err = rp.VerifyAssertion(challenge, r, wc); err != nil {
+			return nil, AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		if c.Meta, err = json.Marshal(wc); err != nil {
+			return nil, err
+		}
+
+		c.LastUsedAt = now()
+		return c, store.UpdateCredentials(ctx, s, c)
+	}
+
+	return nil, AuthErrInvalidWebAuthn(aam)
+}
+
+func webAuthnCredentialIDs(cc types.CredentialsSet) [][]byte {
+	ids := make([][]byte, 0, len(cc))
+	for _, c := range cc {
+		wc := &webauthn.Credential{}
+		if json.Unmarshal(c.Meta, wc) == nil {
+			ids = append(ids, wc.ID)
+		}
+	}
+
+	return ids
+}
+
 // LoadRoleMemberships loads membership info
 //
 // @todo move this to role service
@@ -336,6 +336,26 @@ func AuthActionChangePassword(props ...*authActionProps) *authAction {
 	return a
 }
 
+// AuthActionLock returns "system:auth.lock" action
+//
+// This function is auto-generated.
+//
+func AuthActionLock(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "lock",
+		log:       "{{user.email}} locked after too many failed login attempts",
+		severity:  actionlog.Warning,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // AuthActionInternalSignup returns "system:auth.internalSignup" action
 //
 // This function is auto-generated.
@@ -636,6 +656,206 @@ func AuthActionEmailOtpVerify(props ...*authActionProps) *authAction {
 	return a
 }
 
+// AuthActionRecoveryCodesGenerate returns "system:auth.recoveryCodesGenerate" action
+//
+// This function is auto-generated.
+//
+func AuthActionRecoveryCodesGenerate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "recoveryCodesGenerate",
+		log:       "multi-factor recovery codes for {{user}} generated",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionRecoveryCodeUse returns "system:auth.recoveryCodeUse" action
+//
+// This function is auto-generated.
+//
+func AuthActionRecoveryCodeUse(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "recoveryCodeUse",
+		log:       "multi-factor recovery code used by {{user}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebAuthnConfigure returns "system:auth.webAuthnConfigure" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnConfigure(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnConfigure",
+		log:       "security key {{credentials.label}} for {{user}} registered",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebAuthnRemove returns "system:auth.webAuthnRemove" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnRemove(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnRemove",
+		log:       "security key {{credentials.label}} for {{user}} removed",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebAuthnValidate returns "system:auth.webAuthnValidate" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnValidate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnValidate",
+		log:       "security key {{credentials.label}} for {{user}} validated",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionPersonalAccessTokenCreate returns "system:auth.personalAccessTokenCreate" action
+//
+// This function is auto-generated.
+//
+func AuthActionPersonalAccessTokenCreate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "personalAccessTokenCreate",
+		log:       "personal access token {{credentials.label}} for {{user}} created",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionPersonalAccessTokenRevoke returns "system:auth.personalAccessTokenRevoke" action
+//
+// This function is auto-generated.
+//
+func AuthActionPersonalAccessTokenRevoke(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "personalAccessTokenRevoke",
+		log:       "personal access token {{credentials.label}} for {{user}} revoked",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionLdapSignup returns "system:auth.ldapSignup" action
+//
+// This function is auto-generated.
+//
+func AuthActionLdapSignup(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "ldapSignup",
+		log:       "{{user.email}} signed-up after successful LDAP authentication",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionLdapSync returns "system:auth.ldapSync" action
+//
+// This function is auto-generated.
+//
+func AuthActionLdapSync(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "ldapSync",
+		log:       "users and role memberships synchronized from LDAP",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionExternalRolesSync returns "system:auth.externalRolesSync" action
+//
+// This function is auto-generated.
+//
+func AuthActionExternalRolesSync(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "externalRolesSync",
+		log:       "role memberships of {{user}} synchronized from {{provider}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -940,6 +1160,46 @@ func AuthErrFailedForSystemUser(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
+// AuthErrFailedForBotUser returns "system:auth.failedForBotUser" as *errors.Error
+//
+// Note: This error will be wrapped with safe (system:auth.invalidCredentials) error!
+//
+// This function is auto-generated.
+//
+func AuthErrFailedForBotUser(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		"failedForBotUser",
+
+		errors.Meta("type", "failedForBotUser"),
+		errors.Meta("resource", "system:auth"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "bot user {{user}} tried to log-in with {{credentials.kind}}"),
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.failedForBotUser"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	// Wrap with safe error
+	e = AuthErrInvalidCredentials().Wrap(e)
+
+	return e
+}
+
 // AuthErrFailedUnconfirmedEmail returns "system:auth.failedUnconfirmedEmail" as *errors.Error
 //
 //
@@ -1248,12 +1508,12 @@ func AuthErrPasswordNotSecure(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrExternalDisabledByConfig returns "system:auth.externalDisabledByConfig" as *errors.Error
+// AuthErrLoginThrottled returns "system:auth.loginThrottled" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrExternalDisabledByConfig(mm ...*authActionProps) *errors.Error {
+func AuthErrLoginThrottled(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1262,18 +1522,16 @@ func AuthErrExternalDisabledByConfig(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("external authentication (using external authentication provider) is disabled", nil),
+		p.Format("too many failed login attempts, try again later", nil),
 
-		errors.Meta("type", "externalDisabledByConfig"),
+		errors.Meta("type", "loginThrottled"),
 		errors.Meta("resource", "system:auth"),
 
-		// action log entry; no formatting, it will be applied inside recordAction fn.
-		errors.Meta(authLogMetaKey{}, "external authentication is disabled"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.externalDisabledByConfig"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.loginThrottled"),
 
 		errors.StackSkip(1),
 	)
@@ -1284,12 +1542,12 @@ func AuthErrExternalDisabledByConfig(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrProfileWithoutValidEmail returns "system:auth.profileWithoutValidEmail" as *errors.Error
+// AuthErrPasswordReused returns "system:auth.passwordReused" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrProfileWithoutValidEmail(mm ...*authActionProps) *errors.Error {
+func AuthErrPasswordReused(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1298,18 +1556,16 @@ func AuthErrProfileWithoutValidEmail(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("external authentication provider returned profile without valid email", nil),
+		p.Format("provided password was used recently; choose a different password", nil),
 
-		errors.Meta("type", "profileWithoutValidEmail"),
+		errors.Meta("type", "passwordReused"),
 		errors.Meta("resource", "system:auth"),
 
-		// action log entry; no formatting, it will be applied inside recordAction fn.
-		errors.Meta(authLogMetaKey{}, "external authentication provider {{credentials.kind}} returned profile without valid email"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.profileWithoutValidEmail"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.passwordReused"),
 
 		errors.StackSkip(1),
 	)
@@ -1320,12 +1576,12 @@ func AuthErrProfileWithoutValidEmail(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrCredentialsLinkedToInvalidUser returns "system:auth.credentialsLinkedToInvalidUser" as *errors.Error
+// AuthErrPasswordBreached returns "system:auth.passwordBreached" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrCredentialsLinkedToInvalidUser(mm ...*authActionProps) *errors.Error {
+func AuthErrPasswordBreached(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1334,16 +1590,16 @@ func AuthErrCredentialsLinkedToInvalidUser(mm ...*authActionProps) *errors.Error
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("credentials {{credentials.kind}} linked to disabled or deleted user {{user}}", nil),
+		p.Format("provided password was found in a list of breached passwords; choose a different password", nil),
 
-		errors.Meta("type", "credentialsLinkedToInvalidUser"),
+		errors.Meta("type", "passwordBreached"),
 		errors.Meta("resource", "system:auth"),
 
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.credentialsLinkedToInvalidUser"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.passwordBreached"),
 
 		errors.StackSkip(1),
 	)
@@ -1354,12 +1610,12 @@ func AuthErrCredentialsLinkedToInvalidUser(mm ...*authActionProps) *errors.Error
 	return e
 }
 
-// AuthErrInvalidToken returns "system:auth.invalidToken" as *errors.Error
+// AuthErrExternalDisabledByConfig returns "system:auth.externalDisabledByConfig" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrInvalidToken(mm ...*authActionProps) *errors.Error {
+func AuthErrExternalDisabledByConfig(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1368,16 +1624,18 @@ func AuthErrInvalidToken(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("invalid token", nil),
+		p.Format("external authentication (using external authentication provider) is disabled", nil),
 
-		errors.Meta("type", "invalidToken"),
+		errors.Meta("type", "externalDisabledByConfig"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "external authentication is disabled"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidToken"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.externalDisabledByConfig"),
 
 		errors.StackSkip(1),
 	)
@@ -1388,12 +1646,12 @@ func AuthErrInvalidToken(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrNotAllowedToImpersonate returns "system:auth.notAllowedToImpersonate" as *errors.Error
+// AuthErrLdapDisabledByConfig returns "system:auth.ldapDisabledByConfig" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrNotAllowedToImpersonate(mm ...*authActionProps) *errors.Error {
+func AuthErrLdapDisabledByConfig(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1402,16 +1660,18 @@ func AuthErrNotAllowedToImpersonate(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("not allowed to impersonate this user", nil),
+		p.Format("LDAP authentication is disabled", nil),
 
-		errors.Meta("type", "notAllowedToImpersonate"),
+		errors.Meta("type", "ldapDisabledByConfig"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "LDAP authentication is disabled"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToImpersonate"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.ldapDisabledByConfig"),
 
 		errors.StackSkip(1),
 	)
@@ -1422,12 +1682,12 @@ func AuthErrNotAllowedToImpersonate(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrNotAllowedToRemoveTOTP returns "system:auth.notAllowedToRemoveTOTP" as *errors.Error
+// AuthErrLdapUnavailable returns "system:auth.ldapUnavailable" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrNotAllowedToRemoveTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrLdapUnavailable(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1436,16 +1696,18 @@ func AuthErrNotAllowedToRemoveTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("not allowed to remove TOTP", nil),
+		p.Format("LDAP server is not available", nil),
 
-		errors.Meta("type", "notAllowedToRemoveTOTP"),
+		errors.Meta("type", "ldapUnavailable"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "LDAP server is not available"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToRemoveTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.ldapUnavailable"),
 
 		errors.StackSkip(1),
 	)
@@ -1456,12 +1718,12 @@ func AuthErrNotAllowedToRemoveTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrUnconfiguredTOTP returns "system:auth.unconfiguredTOTP" as *errors.Error
+// AuthErrLdapEntryWithoutValidEmail returns "system:auth.ldapEntryWithoutValidEmail" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrUnconfiguredTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrLdapEntryWithoutValidEmail(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1470,16 +1732,18 @@ func AuthErrUnconfiguredTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("TOTP not configured", nil),
+		p.Format("LDAP entry without valid email", nil),
 
-		errors.Meta("type", "unconfiguredTOTP"),
+		errors.Meta("type", "ldapEntryWithoutValidEmail"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "LDAP entry {{credentials.label}} without valid email"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.unconfiguredTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.ldapEntryWithoutValidEmail"),
 
 		errors.StackSkip(1),
 	)
@@ -1490,12 +1754,12 @@ func AuthErrUnconfiguredTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrNotAllowedToConfigureTOTP returns "system:auth.notAllowedToConfigureTOTP" as *errors.Error
+// AuthErrLdapEntryWithoutID returns "system:auth.ldapEntryWithoutID" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrNotAllowedToConfigureTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrLdapEntryWithoutID(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1504,16 +1768,18 @@ func AuthErrNotAllowedToConfigureTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("not allowed to configure TOTP", nil),
+		p.Format("LDAP entry without unique identifier", nil),
 
-		errors.Meta("type", "notAllowedToConfigureTOTP"),
+		errors.Meta("type", "ldapEntryWithoutID"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "LDAP entry {{credentials.label}} without unique identifier"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToConfigureTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.ldapEntryWithoutID"),
 
 		errors.StackSkip(1),
 	)
@@ -1524,12 +1790,12 @@ func AuthErrNotAllowedToConfigureTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrEnforcedMFAWithTOTP returns "system:auth.enforcedMFAWithTOTP" as *errors.Error
+// AuthErrLdapExistingUser returns "system:auth.ldapExistingUser" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrEnforcedMFAWithTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrLdapExistingUser(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1538,16 +1804,18 @@ func AuthErrEnforcedMFAWithTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("TOTP is enforced and cannot be disabled", nil),
+		p.Format("user with this email already exists and can not be linked to LDAP entry", nil),
 
-		errors.Meta("type", "enforcedMFAWithTOTP"),
+		errors.Meta("type", "ldapExistingUser"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "LDAP entry {{credentials.label}} not linked to existing user {{user}} with other credentials"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.ldapExistingUser"),
 
 		errors.StackSkip(1),
 	)
@@ -1558,12 +1826,12 @@ func AuthErrEnforcedMFAWithTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrInvalidTOTP returns "system:auth.invalidTOTP" as *errors.Error
+// AuthErrProfileWithoutValidEmail returns "system:auth.profileWithoutValidEmail" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrInvalidTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrProfileWithoutValidEmail(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1572,16 +1840,18 @@ func AuthErrInvalidTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("invalid code", nil),
+		p.Format("external authentication provider returned profile without valid email", nil),
 
-		errors.Meta("type", "invalidTOTP"),
+		errors.Meta("type", "profileWithoutValidEmail"),
 		errors.Meta("resource", "system:auth"),
 
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "external authentication provider {{credentials.kind}} returned profile without valid email"),
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.profileWithoutValidEmail"),
 
 		errors.StackSkip(1),
 	)
@@ -1592,12 +1862,12 @@ func AuthErrInvalidTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrDisabledMFAWithTOTP returns "system:auth.disabledMFAWithTOTP" as *errors.Error
+// AuthErrCredentialsLinkedToInvalidUser returns "system:auth.credentialsLinkedToInvalidUser" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrDisabledMFAWithTOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrCredentialsLinkedToInvalidUser(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1606,16 +1876,16 @@ func AuthErrDisabledMFAWithTOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("multi factor authentication with TOTP is disabled", nil),
+		p.Format("credentials {{credentials.kind}} linked to disabled or deleted user {{user}}", nil),
 
-		errors.Meta("type", "disabledMFAWithTOTP"),
+		errors.Meta("type", "credentialsLinkedToInvalidUser"),
 		errors.Meta("resource", "system:auth"),
 
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithTOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.credentialsLinkedToInvalidUser"),
 
 		errors.StackSkip(1),
 	)
@@ -1626,12 +1896,12 @@ func AuthErrDisabledMFAWithTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrDisabledMFAWithEmailOTP returns "system:auth.disabledMFAWithEmailOTP" as *errors.Error
+// AuthErrInvalidToken returns "system:auth.invalidToken" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrDisabledMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrInvalidToken(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1640,16 +1910,16 @@ func AuthErrDisabledMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("multi factor authentication with email OTP is disabled", nil),
+		p.Format("invalid token", nil),
 
-		errors.Meta("type", "disabledMFAWithEmailOTP"),
+		errors.Meta("type", "invalidToken"),
 		errors.Meta("resource", "system:auth"),
 
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithEmailOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidToken"),
 
 		errors.StackSkip(1),
 	)
@@ -1660,12 +1930,12 @@ func AuthErrDisabledMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrEnforcedMFAWithEmailOTP returns "system:auth.enforcedMFAWithEmailOTP" as *errors.Error
+// AuthErrNotAllowedToImpersonate returns "system:auth.notAllowedToImpersonate" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrEnforcedMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrNotAllowedToImpersonate(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1674,16 +1944,16 @@ func AuthErrEnforcedMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
 	var e = errors.New(
 		errors.KindInternal,
 
-		p.Format("OTP over email is enforced and cannot be disabled", nil),
+		p.Format("not allowed to impersonate this user", nil),
 
-		errors.Meta("type", "enforcedMFAWithEmailOTP"),
+		errors.Meta("type", "notAllowedToImpersonate"),
 		errors.Meta("resource", "system:auth"),
 
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithEmailOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToImpersonate"),
 
 		errors.StackSkip(1),
 	)
@@ -1694,12 +1964,148 @@ func AuthErrEnforcedMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
-// AuthErrInvalidEmailOTP returns "system:auth.invalidEmailOTP" as *errors.Error
+// AuthErrNotAllowedToRemoveTOTP returns "system:auth.notAllowedToRemoveTOTP" as *errors.Error
 //
 //
 // This function is auto-generated.
 //
-func AuthErrInvalidEmailOTP(mm ...*authActionProps) *errors.Error {
+func AuthErrNotAllowedToRemoveTOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to remove TOTP", nil),
+
+		errors.Meta("type", "notAllowedToRemoveTOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToRemoveTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrUnconfiguredTOTP returns "system:auth.unconfiguredTOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrUnconfiguredTOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("TOTP not configured", nil),
+
+		errors.Meta("type", "unconfiguredTOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.unconfiguredTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrNotAllowedToConfigureTOTP returns "system:auth.notAllowedToConfigureTOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrNotAllowedToConfigureTOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to configure TOTP", nil),
+
+		errors.Meta("type", "notAllowedToConfigureTOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToConfigureTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrEnforcedMFAWithTOTP returns "system:auth.enforcedMFAWithTOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrEnforcedMFAWithTOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("TOTP is enforced and cannot be disabled", nil),
+
+		errors.Meta("type", "enforcedMFAWithTOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidTOTP returns "system:auth.invalidTOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidTOTP(mm ...*authActionProps) *errors.Error {
 	var p = &authActionProps{}
 	if len(mm) > 0 {
 		p = mm[0]
@@ -1710,14 +2116,558 @@ func AuthErrInvalidEmailOTP(mm ...*authActionProps) *errors.Error {
 
 		p.Format("invalid code", nil),
 
-		errors.Meta("type", "invalidEmailOTP"),
+		errors.Meta("type", "invalidTOTP"),
 		errors.Meta("resource", "system:auth"),
 
 		errors.Meta(authPropsMetaKey{}, p),
 
 		// translation namespace & key
 		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
-		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidEmailOTP"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledMFAWithTOTP returns "system:auth.disabledMFAWithTOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledMFAWithTOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("multi factor authentication with TOTP is disabled", nil),
+
+		errors.Meta("type", "disabledMFAWithTOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithTOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledMFAWithEmailOTP returns "system:auth.disabledMFAWithEmailOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("multi factor authentication with email OTP is disabled", nil),
+
+		errors.Meta("type", "disabledMFAWithEmailOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithEmailOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrEnforcedMFAWithEmailOTP returns "system:auth.enforcedMFAWithEmailOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrEnforcedMFAWithEmailOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("OTP over email is enforced and cannot be disabled", nil),
+
+		errors.Meta("type", "enforcedMFAWithEmailOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithEmailOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidEmailOTP returns "system:auth.invalidEmailOTP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidEmailOTP(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid code", nil),
+
+		errors.Meta("type", "invalidEmailOTP"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidEmailOTP"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledMFAWithWebAuthn returns "system:auth.disabledMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("multi factor authentication with security keys is disabled", nil),
+
+		errors.Meta("type", "disabledMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledPasswordlessWebAuthn returns "system:auth.disabledPasswordlessWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledPasswordlessWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("passwordless login with security keys is disabled", nil),
+
+		errors.Meta("type", "disabledPasswordlessWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledPasswordlessWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrUnconfiguredWebAuthn returns "system:auth.unconfiguredWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrUnconfiguredWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("security key not registered", nil),
+
+		errors.Meta("type", "unconfiguredWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.unconfiguredWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrEnforcedMFAWithWebAuthn returns "system:auth.enforcedMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrEnforcedMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("security keys are enforced and the last key cannot be removed", nil),
+
+		errors.Meta("type", "enforcedMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrNotAllowedToRemoveWebAuthn returns "system:auth.notAllowedToRemoveWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrNotAllowedToRemoveWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to remove security key", nil),
+
+		errors.Meta("type", "notAllowedToRemoveWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToRemoveWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidWebAuthn returns "system:auth.invalidWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("security key verification failed", nil),
+
+		errors.Meta("type", "invalidWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidRecoveryCode returns "system:auth.invalidRecoveryCode" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidRecoveryCode(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid recovery code", nil),
+
+		errors.Meta("type", "invalidRecoveryCode"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidRecoveryCode"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledPersonalAccessTokens returns "system:auth.disabledPersonalAccessTokens" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledPersonalAccessTokens(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("personal access tokens are disabled", nil),
+
+		errors.Meta("type", "disabledPersonalAccessTokens"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledPersonalAccessTokens"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidPersonalAccessTokenName returns "system:auth.invalidPersonalAccessTokenName" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidPersonalAccessTokenName(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("personal access token name is required", nil),
+
+		errors.Meta("type", "invalidPersonalAccessTokenName"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidPersonalAccessTokenName"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidPersonalAccessTokenScope returns "system:auth.invalidPersonalAccessTokenScope" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidPersonalAccessTokenScope(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid personal access token scope", nil),
+
+		errors.Meta("type", "invalidPersonalAccessTokenScope"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidPersonalAccessTokenScope"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidPersonalAccessTokenExpiry returns "system:auth.invalidPersonalAccessTokenExpiry" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidPersonalAccessTokenExpiry(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid personal access token expiration date", nil),
+
+		errors.Meta("type", "invalidPersonalAccessTokenExpiry"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidPersonalAccessTokenExpiry"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrNotAllowedToManagePersonalAccessTokens returns "system:auth.notAllowedToManagePersonalAccessTokens" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrNotAllowedToManagePersonalAccessTokens(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to manage personal access tokens", nil),
+
+		errors.Meta("type", "notAllowedToManagePersonalAccessTokens"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToManagePersonalAccessTokens"),
 
 		errors.StackSkip(1),
 	)
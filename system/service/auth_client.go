@@ -212,11 +212,8 @@ func (svc *authClient) Create(ctx context.Context, new *types.AuthClient) (app *
 			new.Meta = &types.AuthClientMeta{}
 		}
 
-		// Validate impersonated user
-		if new.ValidGrant == oauth2def.ClientCredentials.String() {
-			if new.Security == nil || new.Security.ImpersonateUser == 0 {
-				return errors.Internal("auth client security configuration invalid")
-			}
+		if err = svc.checkImpersonatedUser(ctx, new); err != nil {
+			return
 		}
 
 		if err = store.CreateAuthClient(ctx, svc.store, new); err != nil {
@@ -278,11 +275,8 @@ func (svc *authClient) Update(ctx context.Context, upd *types.AuthClient) (res *
 			return err
 		}
 
-		// Validate impersonated user
-		if upd.ValidGrant == oauth2def.ClientCredentials.String() {
-			if upd.Security == nil || upd.Security.ImpersonateUser == 0 {
-				return errors.Internal("auth client security configuration invalid")
-			}
+		if err = svc.checkImpersonatedUser(ctx, upd); err != nil {
+			return
 		}
 
 		if err = svc.eventbus.WaitFor(ctx, event.AuthClientBeforeUpdate(upd, res)); err != nil {
@@ -331,6 +325,41 @@ func (svc *authClient) Update(ctx context.Context, upd *types.AuthClient) (res *
 	return res, svc.recordAction(ctx, aaProps, AuthClientActionUpdate, err)
 }
 
+// checkImpersonatedUser verifies that client with client credentials grant
+// impersonates an active bot user
+//
+// Ordinary users are allowed only when
+// AUTH_CLIENT_CREDENTIALS_IMPERSONATE_USERS is enabled
+func (svc *authClient) checkImpersonatedUser(ctx context.Context, c *types.AuthClient) error {
+	if c.ValidGrant != oauth2def.ClientCredentials.String() {
+		return nil
+	}
+
+	if c.Security == nil || c.Security.ImpersonateUser == 0 {
+		return AuthClientErrInvalidImpersonatedUser()
+	}
+
+	u, err := store.LookupUserByID(ctx, svc.store, c.Security.ImpersonateUser)
+	if errors.IsNotFound(err) {
+		return AuthClientErrInvalidImpersonatedUser()
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case u.Kind == types.NormalUser && svc.opt.ClientCredentialsImpersonateUsers:
+		// allowed for compatibility
+	case u.Kind != types.BotUser:
+		return AuthClientErrInvalidImpersonatedUser()
+	}
+
+	if u.DeletedAt != nil || u.SuspendedAt != nil {
+		return AuthClientErrInvalidImpersonatedUser()
+	}
+
+	return nil
+}
+
 func (svc *authClient) Delete(ctx context.Context, ID uint64) (err error) {
 	var (
 		aaProps = &authClientActionProps{}
@@ -511,6 +511,42 @@ func AuthClientErrInvalidID(mm ...*authClientActionProps) *errors.Error {
 	return e
 }
 
+// AuthClientErrInvalidImpersonatedUser returns "system:auth-client.invalidImpersonatedUser" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthClientErrInvalidImpersonatedUser(mm ...*authClientActionProps) *errors.Error {
+	var p = &authClientActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("client credentials grant requires an active bot user to impersonate", nil),
+
+		errors.Meta("type", "invalidImpersonatedUser"),
+		errors.Meta("resource", "system:auth-client"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authClientLogMetaKey{}, "failed to save {{authClient}}; invalid impersonated user"),
+		errors.Meta(authClientPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "authClient.errors.invalidImpersonatedUser"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // AuthClientErrUnableToChangeDefaultClientHandle returns "system:auth-client.unableToChangeDefaultClientHandle" as *errors.Error
 //
 //
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuthClient_checkImpersonatedUser(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		user = &types.User{ID: nextID(), Email: "user@us.er", CreatedAt: *now()}
+		bot  = &types.User{ID: nextID(), Email: "bot@us.er", Kind: types.BotUser, CreatedAt: *now()}
+		sys  = &types.User{ID: nextID(), Email: "sys@us.er", Kind: types.SystemUser, CreatedAt: *now()}
+
+		client = func(userID uint64) *types.AuthClient {
+			return &types.AuthClient{
+				ValidGrant: "client_credentials",
+				Security:   &types.AuthClientSecurity{ImpersonateUser: userID},
+			}
+		}
+
+		s   store.Storer
+		err error
+	)
+
+	if s, err = sqlite3.ConnectInMemory(ctx); err != nil {
+		req.NoError(err)
+	} else if err = store.Upgrade(ctx, zap.NewNop(), s); err != nil {
+		req.NoError(err)
+	}
+
+	req.NoError(store.CreateUser(ctx, s, user, bot, sys))
+
+	svc := &authClient{store: s}
+
+	req.NoError(svc.checkImpersonatedUser(ctx, client(bot.ID)))
+	req.Error(svc.checkImpersonatedUser(ctx, client(user.ID)))
+	req.Error(svc.checkImpersonatedUser(ctx, client(sys.ID)))
+
+	// compatibility with clients that impersonate ordinary users
+	svc.opt.ClientCredentialsImpersonateUsers = true
+	req.NoError(svc.checkImpersonatedUser(ctx, client(user.ID)))
+	req.Error(svc.checkImpersonatedUser(ctx, client(sys.ID)))
+}
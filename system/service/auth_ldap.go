@@ -0,0 +1,652 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/ldap"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service/event"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+)
+
+type (
+	// LDAP credentials properties, stored as credentials meta
+	ldapCredentialsMeta struct {
+		// user was suspended by sync because it is no longer in the directory
+		Suspended bool `json:"suspended,omitempty"`
+	}
+)
+
+const (
+	credentialsTypeLDAP = "ldap"
+
+	ldapDefaultUserFilter  = "(&(objectClass=person)(|(uid={username})(sAMAccountName={username})(mail={username})))"
+	ldapDefaultGroupFilter = "(|(member={dn})(uniqueMember={dn})(memberUid={username}))"
+	ldapDefaultSyncFilter  = "(objectClass=person)"
+	ldapDefaultEmailAttr   = "mail"
+
+	ldapDefaultSyncInterval = 60
+
+	// entries are fetched in pages when syncing
+	ldapSyncPageSize = 500
+)
+
+var (
+	ldapDefaultNameAttrs   = []string{"displayName", "cn"}
+	ldapDefaultHandleAttrs = []string{"uid", "sAMAccountName"}
+)
+
+// LDAPLogin authenticates user with LDAP bind
+//
+// Flow
+// 1. user entry is found with the user filter (service account is used when configured)
+// 2. bind with user's DN and password verifies the credentials
+// 3. user is created or updated from the entry, memberships of the mapped roles are updated
+//
+// Failed attempts are throttled per IP address; account lockout is left to the directory
+func (svc auth) LDAPLogin(ctx context.Context, username, password string) (u *types.User, err error) {
+	var (
+		authProvider = &types.AuthProvider{Provider: credentialsTypeLDAP}
+
+		aam = &authActionProps{
+			email:       username,
+			credentials: &types.Credentials{Kind: credentialsTypeLDAP},
+		}
+
+		cfg = svc.settings.Auth.LDAP
+	)
+
+	err = func() (err error) {
+		if !cfg.Enabled {
+			return AuthErrLdapDisabledByConfig(aam)
+		}
+
+		if username = strings.TrimSpace(username); username == "" || len(password) == 0 {
+			return AuthErrInvalidCredentials(aam)
+		}
+
+		if err = svc.checkLoginAttempts(ctx, nil); err != nil {
+			return err
+		}
+
+		conn, err := svc.ldapConnect(ctx)
+		if err != nil {
+			return err
+		}
+
+		defer conn.Close()
+
+		e, err := svc.ldapFindUser(conn, username)
+		if err != nil {
+			return err
+		}
+
+		if e != nil {
+			err = conn.Bind(e.DN, password)
+		}
+
+		if e == nil || ldap.IsInvalidCredentials(err) {
+			if err = svc.registerFailedLogin(ctx, nil); err != nil {
+				return err
+			}
+
+			return AuthErrInvalidCredentials(aam)
+		} else if err != nil {
+			return AuthErrLdapUnavailable(aam).Wrap(err)
+		}
+
+		// switch back to service account for group lookup
+		if cfg.BindDN != "" {
+			if err = conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+				return AuthErrLdapUnavailable(aam).Wrap(err)
+			}
+		}
+
+		groups, err := svc.ldapUserGroups(conn, e)
+		if err != nil {
+			return err
+		}
+
+		return svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) (err error) {
+			var c *types.Credentials
+			if u, c, err = svc.ldapUpsertUser(ctx, s, e, aam); err != nil {
+				return err
+			}
+
+			if err = svc.ldapSyncMemberships(ctx, s, u, groups); err != nil {
+				return err
+			}
+
+			ctx = internalAuth.SetIdentityToContext(ctx, u)
+			return svc.procLogin(ctx, s, u, c, authProvider)
+		})
+	}()
+
+	return u, svc.recordAction(ctx, aam, AuthActionAuthenticate, err)
+}
+
+// LDAPSync creates and updates users and memberships of the mapped roles
+// from the directory entries
+//
+// When configured, users that are no longer in the directory are suspended
+func (svc auth) LDAPSync(ctx context.Context) (err error) {
+	var (
+		aam = &authActionProps{credentials: &types.Credentials{Kind: credentialsTypeLDAP}}
+		cfg = svc.settings.Auth.LDAP
+	)
+
+	err = func() (err error) {
+		if !cfg.Enabled {
+			return AuthErrLdapDisabledByConfig(aam)
+		}
+
+		conn, err := svc.ldapConnect(ctx)
+		if err != nil {
+			return err
+		}
+
+		defer conn.Close()
+
+		ee, err := conn.Search(ldap.SearchRequest{
+			BaseDN:   cfg.BaseDN,
+			Scope:    ldap.ScopeWholeSubtree,
+			Filter:   ldapDefault(cfg.Sync.Filter, ldapDefaultSyncFilter),
+			PageSize: ldapSyncPageSize,
+		})
+
+		if err != nil {
+			return AuthErrLdapUnavailable(aam).Wrap(err)
+		}
+
+		var (
+			// users found in the directory
+			found = make(map[uint64]bool)
+		)
+
+		for _, e := range ee {
+			groups, err := svc.ldapUserGroups(conn, e)
+			if err != nil {
+				return err
+			}
+
+			props := &authActionProps{credentials: &types.Credentials{Kind: credentialsTypeLDAP, Label: e.DN}}
+			err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) (err error) {
+				var u *types.User
+				if u, _, err = svc.ldapUpsertUser(ctx, s, e, props); err != nil {
+					return err
+				}
+
+				found[u.ID] = true
+				return svc.ldapSyncMemberships(ctx, s, u, groups)
+			})
+
+			if AuthErrLdapEntryWithoutValidEmail().Is(err) ||
+				AuthErrLdapEntryWithoutID().Is(err) ||
+				AuthErrLdapExistingUser().Is(err) ||
+				UserErrEmailNotUnique().Is(err) ||
+				UserErrHandleNotUnique().Is(err) {
+				// invalid entries are recorded and skipped
+				_ = svc.recordAction(ctx, props, AuthActionLdapSync, err)
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if cfg.Sync.SuspendMissing {
+			return svc.ldapSuspendMissing(ctx, found)
+		}
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, aam, AuthActionLdapSync, err)
+}
+
+// WatchLDAPSync periodically synchronizes users from LDAP
+//
+// Settings are checked on every tick so sync can be enabled,
+// disabled or reconfigured without restart
+func (svc auth) WatchLDAPSync(ctx context.Context) {
+	var (
+		ticker   = time.NewTicker(time.Minute)
+		lastSync time.Time
+		log      = DefaultLogger.Named("ldap")
+	)
+
+	go func() {
+		defer sentry.Recover()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cfg := svc.settings.Auth.LDAP
+				if !cfg.Enabled || !cfg.Sync.Enabled {
+					continue
+				}
+
+				interval := time.Duration(cfg.Sync.Interval) * time.Minute
+				if interval == 0 {
+					interval = ldapDefaultSyncInterval * time.Minute
+				}
+
+				if time.Since(lastSync) < interval {
+					continue
+				}
+
+				lastSync = time.Now()
+				if err := svc.LDAPSync(internalAuth.SetIdentityToContext(ctx, internalAuth.ServiceUser())); err != nil {
+					log.Error("LDAP sync failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// connects to LDAP server and binds with service account (when configured)
+func (svc auth) ldapConnect(ctx context.Context) (conn *ldap.Conn, err error) {
+	var (
+		cfg = svc.settings.Auth.LDAP
+		tc  = &tls.Config{}
+	)
+
+	if cfg.InsecureSkipVerify {
+		log := DefaultLogger.Named("ldap")
+		if svc.ldapAllowInsecure {
+			log.Warn("LDAP server certificate is not verified, do not use this setting in production")
+			tc.InsecureSkipVerify = true
+		} else {
+			log.Warn("ignoring LDAP insecure-skip-verify setting, allowed only in development environment")
+		}
+	}
+
+	conn, err = ldap.Dial(ctx, cfg.URL, ldap.DialOptions{
+		StartTLS:  cfg.StartTLS,
+		TLSConfig: tc,
+	})
+
+	if err != nil {
+		return nil, AuthErrLdapUnavailable().Wrap(err)
+	}
+
+	if cfg.BindDN != "" {
+		if err = conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			conn.Close()
+			return nil, AuthErrLdapUnavailable().Wrap(err)
+		}
+	}
+
+	return conn, nil
+}
+
+// finds user's entry, returns nil when user does not exist or is not unique
+func (svc auth) ldapFindUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	var (
+		cfg = svc.settings.Auth.LDAP
+	)
+
+	ee, err := conn.Search(ldap.SearchRequest{
+		BaseDN:    cfg.BaseDN,
+		Scope:     ldap.ScopeWholeSubtree,
+		Filter:    strings.ReplaceAll(ldapDefault(cfg.UserFilter, ldapDefaultUserFilter), "{username}", ldap.EscapeFilter(username)),
+		SizeLimit: 2,
+	})
+
+	if err != nil {
+		if e, ok := err.(*ldap.Error); ok && e.ResultCode == ldap.ResultSizeLimitExceeded {
+			return nil, nil
+		}
+
+		return nil, AuthErrLdapUnavailable().Wrap(err)
+	}
+
+	if len(ee) != 1 {
+		return nil, nil
+	}
+
+	return ee[0], nil
+}
+
+// finds groups of the user
+//
+// Groups are only needed when there are mapped roles
+func (svc auth) ldapUserGroups(conn *ldap.Conn, e *ldap.Entry) ([]*ldap.Entry, error) {
+	var (
+		cfg    = svc.settings.Auth.LDAP
+		filter = ldapDefault(cfg.Groups.Filter, ldapDefaultGroupFilter)
+	)
+
+	if len(cfg.Groups.Mapping) == 0 {
+		return nil, nil
+	}
+
+	filter = strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(e.DN),
+		"{username}", ldap.EscapeFilter(ldapFirstValue(e, cfg.Attributes.Handle, ldapDefaultHandleAttrs...)),
+	).Replace(filter)
+
+	gg, err := conn.Search(ldap.SearchRequest{
+		BaseDN:     ldapDefault(cfg.Groups.BaseDN, cfg.BaseDN),
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: []string{"cn"},
+		PageSize:   ldapSyncPageSize,
+	})
+
+	if err != nil {
+		return nil, AuthErrLdapUnavailable().Wrap(err)
+	}
+
+	return gg, nil
+}
+
+// creates or updates user from the LDAP entry
+//
+// Users are matched by LDAP credentials and then by email; name and email are
+// always updated from the entry, handle and username are only set on creation.
+func (svc auth) ldapUpsertUser(ctx context.Context, s store.Storer, e *ldap.Entry, aam *authActionProps) (u *types.User, c *types.Credentials, err error) {
+	var (
+		cfg = svc.settings.Auth.LDAP
+
+		id     = ldapEntryID(e, cfg.Attributes.ID)
+		email  = e.Value(ldapDefault(cfg.Attributes.Email, ldapDefaultEmailAttr))
+		name   = ldapFirstValue(e, cfg.Attributes.Name, ldapDefaultNameAttrs...)
+		handle = ldapFirstValue(e, cfg.Attributes.Handle, ldapDefaultHandleAttrs...)
+
+		cc types.CredentialsSet
+	)
+
+	aam.setEmail(email)
+	aam.setCredentials(&types.Credentials{Kind: credentialsTypeLDAP, Label: e.DN})
+
+	if !reEmail.MatchString(email) {
+		return nil, nil, AuthErrLdapEntryWithoutValidEmail(aam)
+	}
+
+	if id == "" {
+		// empty credentials would match credentials of all LDAP users
+		return nil, nil, AuthErrLdapEntryWithoutID(aam)
+	}
+
+	if cc, _, err = store.SearchCredentials(ctx, s, types.CredentialsFilter{Kind: credentialsTypeLDAP, Credentials: id}); err != nil {
+		return
+	}
+
+	for _, cand := range cc {
+		if !cand.Valid() {
+			continue
+		}
+
+		if u, err = store.LookupUserByID(ctx, s, cand.OwnerID); errors.IsNotFound(err) {
+			// orphaned credentials
+			if err = store.DeleteCredentialsByID(ctx, s, cand.ID); err != nil {
+				return
+			}
+
+			continue
+		} else if err != nil {
+			return
+		}
+
+		c = cand
+		break
+	}
+
+	if u == nil {
+		if u, err = store.LookupUserByEmail(ctx, s, email); errors.IsNotFound(err) {
+			if u, err = svc.ldapCreateUser(ctx, s, email, name, handle); err != nil {
+				return
+			}
+
+			aam.setUser(u)
+			_ = svc.recordAction(ctx, aam, AuthActionLdapSignup, nil)
+		} else if err != nil {
+			return
+		} else if !cfg.LinkExistingUsers {
+			aam.setUser(u)
+
+			// whoever controls the LDAP entry with the same email
+			// must not take over the account with other credentials
+			if cc, _, err = store.SearchCredentials(ctx, s, types.CredentialsFilter{OwnerID: u.ID}); err != nil {
+				return
+			}
+
+			for _, ec := range cc {
+				// failed login attempts are stored with credentials
+				// but can not be used to log in
+				if ec.Kind == credentialsTypeFailedLogins {
+					continue
+				}
+
+				if ec.Valid() {
+					return nil, nil, AuthErrLdapExistingUser(aam)
+				}
+			}
+		}
+
+		c = &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			Kind:        credentialsTypeLDAP,
+			Label:       e.DN,
+			OwnerID:     u.ID,
+			Credentials: id,
+		}
+
+		if err = store.CreateCredentials(ctx, s, c); err != nil {
+			return
+		}
+
+		aam.setCredentials(c)
+		_ = svc.recordAction(ctx, aam, AuthActionCreateCredentials, nil)
+	}
+
+	aam.setUser(u)
+	aam.setCredentials(c)
+
+	var (
+		meta    = ldapCredentialsMeta{}
+		changed = false
+	)
+
+	_ = json.Unmarshal(c.Meta, &meta)
+
+	if u.Email != email || (name != "" && u.Name != name) || !u.EmailConfirmed {
+		u.Email, u.EmailConfirmed = email, true
+		if name != "" {
+			u.Name = name
+		}
+
+		changed = true
+	}
+
+	if meta.Suspended && u.SuspendedAt != nil {
+		// user was suspended by sync and is back in the directory
+		u.SuspendedAt = nil
+		changed = true
+	}
+
+	if changed {
+		if err = uniqueUserCheck(ctx, s, u); err != nil {
+			return
+		}
+
+		u.UpdatedAt = now()
+		if err = store.UpdateUser(ctx, s, u); err != nil {
+			return
+		}
+	}
+
+	if meta.Suspended || c.Label != e.DN {
+		c.Label, c.Meta = e.DN, nil
+		if err = store.UpdateCredentials(ctx, s, c); err != nil {
+			return
+		}
+	}
+
+	return u, c, nil
+}
+
+// creates user from the entry; entry's handle attribute is used as user's handle when valid and not taken
+func (svc auth) ldapCreateUser(ctx context.Context, s store.Storer, email, name, userHandle string) (u *types.User, err error) {
+	u = &types.User{
+		Email:          email,
+		Name:           name,
+		EmailConfirmed: true,
+	}
+
+	if handle.IsValid(userHandle) {
+		if _, err = store.LookupUserByHandle(ctx, s, userHandle); errors.IsNotFound(err) {
+			u.Handle = userHandle
+		}
+	}
+
+	if err = svc.eventbus.WaitFor(ctx, event.AuthBeforeSignup(u, &types.AuthProvider{Provider: credentialsTypeLDAP})); err != nil {
+		return nil, err
+	}
+
+	createUserHandle(ctx, s, u)
+
+	if err = uniqueUserCheck(ctx, s, u); err != nil {
+		return nil, err
+	}
+
+	u.ID = nextID()
+	u.CreatedAt = *now()
+	if err = store.CreateUser(ctx, s, u); err != nil {
+		return nil, err
+	}
+
+	_ = svc.eventbus.WaitFor(ctx, event.AuthAfterSignup(u, &types.AuthProvider{Provider: credentialsTypeLDAP}))
+	return u, nil
+}
+
+// adds and removes user from the mapped roles according to the LDAP group membership
+//
+// Memberships of roles that are not mapped are not modified
+func (svc auth) ldapSyncMemberships(ctx context.Context, s store.Storer, u *types.User, groups []*ldap.Entry) error {
+	var (
+		mapping = svc.settings.Auth.LDAP.Groups.Mapping
+
+		// role handle => should user be a member
+		roles = make(map[string]bool)
+	)
+
+	if len(mapping) == 0 {
+		return nil
+	}
+
+	for _, m := range mapping {
+		roles[m.Role] = roles[m.Role] || ldapInGroup(groups, m.Group)
+	}
+
+	return syncMappedRoleMemberships(ctx, s, u.ID, roles)
+}
+
+// suspends users with LDAP credentials that were not found in the directory
+func (svc auth) ldapSuspendMissing(ctx context.Context, found map[uint64]bool) error {
+	cc, _, err := store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{Kind: credentialsTypeLDAP})
+	if err != nil {
+		return err
+	}
+
+	meta, _ := json.Marshal(ldapCredentialsMeta{Suspended: true})
+
+	for _, c := range cc {
+		if found[c.OwnerID] || !c.Valid() {
+			continue
+		}
+
+		err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+			u, err := store.LookupUserByID(ctx, s, c.OwnerID)
+			if errors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			if u.SuspendedAt != nil || u.DeletedAt != nil {
+				return nil
+			}
+
+			u.SuspendedAt = now()
+			if err = store.UpdateUser(ctx, s, u); err != nil {
+				return err
+			}
+
+			c.Meta = meta
+			return store.UpdateCredentials(ctx, s, c)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ldapInGroup(groups []*ldap.Entry, group string) bool {
+	for _, g := range groups {
+		if strings.EqualFold(g.DN, group) || strings.EqualFold(g.Value("cn"), group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unique identifier of the entry, DN (lowercased) by default
+//
+// Binary values (objectGUID) are hex encoded
+func ldapEntryID(e *ldap.Entry, attr string) string {
+	if attr == "" {
+		return strings.ToLower(e.DN)
+	}
+
+	v := e.Value(attr)
+	if !utf8.ValidString(v) {
+		return hex.EncodeToString([]byte(v))
+	}
+
+	return v
+}
+
+// returns value of the configured attribute or first non-empty value of the default attributes
+func ldapFirstValue(e *ldap.Entry, attr string, defaults ...string) string {
+	if attr != "" {
+		return e.Value(attr)
+	}
+
+	for _, a := range defaults {
+		if v := e.Value(a); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func ldapDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
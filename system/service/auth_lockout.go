@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/api"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// failed login attempts on one account or from one IP address
+	failedLogins struct {
+		Count       uint       `json:"count"`
+		LastAt      time.Time  `json:"lastAt"`
+		LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+	}
+
+	// in-memory registry of failed login attempts per IP address
+	//
+	// Per-account attempts are stored with user's credentials
+	// so that they are shared between all nodes
+	ipFailedLogins struct {
+		mux sync.Mutex
+		ips map[string]*failedLogins
+	}
+)
+
+const (
+	lockoutDefaultMaxAttempts      = 5
+	lockoutDefaultMaxAttemptsPerIP = 20
+	lockoutDefaultDuration         = 15 * time.Minute
+
+	// upper limit for the progressive delay between failed attempts
+	lockoutMaxDelay = 30 * time.Second
+)
+
+func newIpFailedLogins() *ipFailedLogins {
+	return &ipFailedLogins{ips: make(map[string]*failedLogins)}
+}
+
+// delay between attempts grows with every failed attempt (1s, 2s, 4s...);
+// first failed attempt is not penalized
+func (fl failedLogins) delay() time.Duration {
+	if fl.Count < 2 {
+		return 0
+	}
+
+	if fl.Count > 7 {
+		return lockoutMaxDelay
+	}
+
+	if d := time.Second << (fl.Count - 2); d < lockoutMaxDelay {
+		return d
+	}
+
+	return lockoutMaxDelay
+}
+
+func (fl failedLogins) locked(now time.Time) bool {
+	return fl.LockedUntil != nil && now.Before(*fl.LockedUntil)
+}
+
+// throttled returns true when another attempt is made
+// before the progressive delay passes
+func (fl failedLogins) throttled(now time.Time) bool {
+	return fl.LockedUntil == nil && now.Before(fl.LastAt.Add(fl.delay()))
+}
+
+// fail registers failed attempt and locks when the limit is reached
+//
+// Active lock is kept; counter is reset when lock expires
+// or after lockout duration without failed attempts.
+// Returns true only when attempt locks the account
+func (fl *failedLogins) fail(now time.Time, max uint, lockout time.Duration) (locked bool) {
+	if fl.locked(now) {
+		fl.Count++
+		fl.LastAt = now
+		return false
+	}
+
+	if fl.LockedUntil != nil || now.Sub(fl.LastAt) > lockout {
+		fl.Count = 0
+		fl.LockedUntil = nil
+	}
+
+	fl.Count++
+	fl.LastAt = now
+
+	if fl.Count >= max {
+		until := now.Add(lockout)
+		fl.LockedUntil = &until
+		return true
+	}
+
+	return false
+}
+
+func (r *ipFailedLogins) get(ip string) failedLogins {
+	if r == nil || ip == "" {
+		return failedLogins{}
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if fl, has := r.ips[ip]; has {
+		return *fl
+	}
+
+	return failedLogins{}
+}
+
+func (r *ipFailedLogins) fail(ip string, now time.Time, max uint, lockout time.Duration) {
+	if r == nil || ip == "" {
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	// forget addresses without recent failed attempts
+	for k, fl := range r.ips {
+		if !fl.locked(now) && now.Sub(fl.LastAt) > lockout {
+			delete(r.ips, k)
+		}
+	}
+
+	if _, has := r.ips[ip]; !has {
+		r.ips[ip] = &failedLogins{}
+	}
+
+	r.ips[ip].fail(now, max, lockout)
+}
+
+// lockout settings with defaults applied
+func (svc auth) lockoutPolicy() (maxAttempts, maxAttemptsPerIP uint, duration time.Duration) {
+	var s = svc.settings.Auth.Internal.Lockout
+
+	maxAttempts, maxAttemptsPerIP, duration = s.MaxAttempts, s.MaxAttemptsPerIP, time.Duration(s.Duration)*time.Minute
+
+	if maxAttempts == 0 {
+		maxAttempts = lockoutDefaultMaxAttempts
+	}
+
+	if maxAttemptsPerIP == 0 {
+		maxAttemptsPerIP = lockoutDefaultMaxAttemptsPerIP
+	}
+
+	if duration == 0 {
+		duration = lockoutDefaultDuration
+	}
+
+	return
+}
+
+// checkLoginAttempts verifies that login is allowed from the IP address and for the user (when known)
+func (svc auth) checkLoginAttempts(ctx context.Context, u *types.User) error {
+	if !svc.settings.Auth.Internal.Lockout.Enabled {
+		return nil
+	}
+
+	var (
+		ts = *now()
+		ip = svc.failedLoginsIP.get(remoteIP(ctx))
+	)
+
+	if ip.locked(ts) || ip.throttled(ts) {
+		return AuthErrLoginThrottled()
+	}
+
+	if u == nil {
+		return nil
+	}
+
+	_, fl, err := loadFailedLogins(ctx, svc.store, u.ID)
+	if err != nil {
+		return err
+	}
+
+	if fl.locked(ts) || fl.throttled(ts) {
+		// same error as for invalid credentials or unknown user
+		// so that lockout does not reveal that account exists
+		return AuthErrInvalidCredentials()
+	}
+
+	return nil
+}
+
+// registerFailedLogin counts failed login attempt from the IP address and for the user (when known)
+//
+// When user reaches the limit, account is locked and user is notified
+func (svc auth) registerFailedLogin(ctx context.Context, u *types.User) error {
+	if !svc.settings.Auth.Internal.Lockout.Enabled {
+		return nil
+	}
+
+	var (
+		ts = *now()
+
+		maxAttempts, maxAttemptsPerIP, duration = svc.lockoutPolicy()
+	)
+
+	svc.failedLoginsIP.fail(remoteIP(ctx), ts, maxAttemptsPerIP, duration)
+
+	if u == nil {
+		return nil
+	}
+
+	var (
+		fl     failedLogins
+		locked bool
+	)
+
+	// user is locked while failed logins are counted
+	// so that concurrent attempts are not lost
+	err := store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		var c *types.Credentials
+
+		if err = store.LockUser(ctx, s, u); err != nil {
+			return
+		}
+
+		if c, fl, err = loadFailedLogins(ctx, s, u.ID); err != nil {
+			return
+		}
+
+		locked = fl.fail(ts, maxAttempts, duration)
+
+		if c.Meta, err = json.Marshal(fl); err != nil {
+			return
+		}
+
+		c.LastUsedAt = &ts
+		c.ExpiresAt = fl.LockedUntil
+
+		if c.ID == 0 {
+			c.ID = nextID()
+			return store.CreateCredentials(ctx, s, c)
+		}
+
+		c.UpdatedAt = &ts
+		return store.UpdateCredentials(ctx, s, c)
+	})
+
+	if err != nil || !locked {
+		return err
+	}
+
+	aam := &authActionProps{user: u, credentials: &types.Credentials{Kind: credentialsTypePassword}}
+	_ = svc.recordAction(ctx, aam, AuthActionLock, nil)
+
+	if svc.notifications != nil {
+		// account is locked even if user could not be notified
+		_ = svc.notifications.AccountLocked(ctx, u.Email, *fl.LockedUntil)
+	}
+
+	return nil
+}
+
+// clearFailedLogins removes failed login attempts after successful login
+//
+// Nothing is done when lockout is disabled
+func (svc auth) clearFailedLogins(ctx context.Context, userID uint64) error {
+	if !svc.settings.Auth.Internal.Lockout.Enabled {
+		return nil
+	}
+
+	return svc.ResetFailedLogins(ctx, userID)
+}
+
+// ResetFailedLogins removes all failed login attempts and unlocks the account
+func (svc auth) ResetFailedLogins(ctx context.Context, userID uint64) error {
+	cc, _, err := store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeFailedLogins,
+	})
+
+	if err != nil || len(cc) == 0 {
+		return err
+	}
+
+	return store.DeleteCredentials(ctx, svc.store, cc...)
+}
+
+// LoginLocked returns time until account is locked or nil if it is not
+func (svc auth) LoginLocked(ctx context.Context, userID uint64) (*time.Time, error) {
+	_, fl, err := loadFailedLogins(ctx, svc.store, userID)
+	if err != nil || !fl.locked(*now()) {
+		return nil, err
+	}
+
+	return fl.LockedUntil, nil
+}
+
+func loadFailedLogins(ctx context.Context, s store.Credentials, userID uint64) (c *types.Credentials, fl failedLogins, err error) {
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeFailedLogins,
+	})
+
+	if err != nil {
+		return
+	}
+
+	if len(cc) == 0 {
+		c = &types.Credentials{OwnerID: userID, Kind: credentialsTypeFailedLogins, CreatedAt: *now()}
+		return
+	}
+
+	c = cc[0]
+	if len(c.Meta) > 0 {
+		if err = json.Unmarshal(c.Meta, &fl); err != nil {
+			return nil, fl, errors.Internal("could not decode failed login attempts").Wrap(err)
+		}
+	}
+
+	return
+}
+
+// extracts client's IP address from the request context
+func remoteIP(ctx context.Context) string {
+	addr := api.RemoteAddrFromContext(ctx)
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
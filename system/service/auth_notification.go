@@ -6,6 +6,7 @@ import (
 	htpl "html/template"
 	"io/ioutil"
 	"net/url"
+	"time"
 
 	intAuth "github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
@@ -30,6 +31,7 @@ type (
 		EmailConfirmation(ctx context.Context, emailAddress string, url string) error
 		PasswordReset(ctx context.Context, emailAddress string, url string) error
 		PasswordCreate(url string) (string, error)
+		AccountLocked(ctx context.Context, emailAddress string, until time.Time) error
 	}
 )
 
@@ -68,6 +70,13 @@ func (svc authNotification) PasswordCreate(token string) (string, error) {
 	return fmt.Sprintf("%s/create-password?token=%s", svc.opt.BaseURL, url.QueryEscape(token)), nil
 }
 
+func (svc authNotification) AccountLocked(ctx context.Context, emailAddress string, until time.Time) error {
+	return svc.send(ctx, "auth_email_account_locked", emailAddress, map[string]interface{}{
+		"Until": until.UTC().Format(time.RFC1123),
+		"URL":   fmt.Sprintf("%s/request-password-reset", svc.opt.BaseURL),
+	})
+}
+
 func (svc authNotification) newMail() *gomail.Message {
 	var (
 		m    = mail.New()
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// personal access token properties, stored as credentials meta
+	personalAccessTokenMeta struct {
+		Scope []string `json:"scope"`
+	}
+)
+
+const (
+	// length of the random part of the token (in bytes, hex encoded)
+	personalAccessTokenSecretLength = 20
+
+	// last-used timestamp is updated at most once per interval
+	// to avoid store writes on every API request
+	personalAccessTokenUsageInterval = time.Minute
+)
+
+var (
+	// scopes that can be assigned to personal access tokens
+	personalAccessTokenScopes = []string{
+		"profile",
+		"api",
+		"api:system",
+		"api:compose",
+		"api:automation",
+		"api:federation",
+	}
+)
+
+// PersonalAccessTokens returns all non-revoked personal access tokens of the user
+//
+// Users can always see their own tokens, tokens of others require permission to update the user
+func (svc auth) PersonalAccessTokens(ctx context.Context, userID uint64) (tt []*types.PersonalAccessToken, err error) {
+	var (
+		u  *types.User
+		cc types.CredentialsSet
+	)
+
+	if u, err = svc.personalAccessTokenOwner(ctx, svc.store, userID); err != nil {
+		return nil, err
+	}
+
+	if cc, err = findPersonalAccessTokens(ctx, svc.store, u.ID); err != nil {
+		return nil, err
+	}
+
+	tt = make([]*types.PersonalAccessToken, 0, len(cc))
+	for _, c := range cc {
+		tt = append(tt, personalAccessTokenFromCredentials(c))
+	}
+
+	return tt, nil
+}
+
+// CreatePersonalAccessToken creates new personal access token for the current user
+//
+// Only hash of the token is stored; plain token is returned and should be shown to the user only once
+func (svc auth) CreatePersonalAccessToken(ctx context.Context, name string, scope []string, expiresAt *time.Time) (token string, t *types.PersonalAccessToken, err error) {
+	var (
+		u   *types.User
+		c   *types.Credentials
+		aam = &authActionProps{credentials: &types.Credentials{Kind: credentialsTypePersonalAccessToken}}
+	)
+
+	err = func() (err error) {
+		if !svc.settings.Auth.PersonalAccessTokens.Enabled {
+			return AuthErrDisabledPersonalAccessTokens()
+		}
+
+		if u, err = svc.personalAccessTokenOwner(ctx, svc.store, internalAuth.GetIdentityFromContext(ctx).Identity()); err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if name = strings.TrimSpace(name); name == "" {
+			return AuthErrInvalidPersonalAccessTokenName(aam)
+		}
+
+		if scope, err = normalizePersonalAccessTokenScope(scope); err != nil {
+			return AuthErrInvalidPersonalAccessTokenScope(aam)
+		}
+
+		if expiresAt, err = svc.personalAccessTokenExpiry(expiresAt); err != nil {
+			return AuthErrInvalidPersonalAccessTokenExpiry(aam)
+		}
+
+		secret := make([]byte, personalAccessTokenSecretLength)
+		if _, err = cryptoRand.Read(secret); err != nil {
+			return err
+		}
+
+		meta, err := json.Marshal(personalAccessTokenMeta{Scope: scope})
+		if err != nil {
+			return err
+		}
+
+		c = &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			OwnerID:     u.ID,
+			Kind:        credentialsTypePersonalAccessToken,
+			Label:       name,
+			Credentials: hashPersonalAccessToken(hex.EncodeToString(secret)),
+			Meta:        meta,
+			ExpiresAt:   expiresAt,
+		}
+
+		aam.setCredentials(c)
+
+		if err = store.CreateCredentials(ctx, svc.store, c); err != nil {
+			return err
+		}
+
+		// suffixing tokens with credentials ID
+		// this will help us with token lookups
+		token = fmt.Sprintf("%s%x%d", internalAuth.PersonalAccessTokenPrefix, secret, c.ID)
+		t = personalAccessTokenFromCredentials(c)
+		return nil
+	}()
+
+	return token, t, svc.recordAction(ctx, aam, AuthActionPersonalAccessTokenCreate, err)
+}
+
+// RevokePersonalAccessToken revokes user's personal access token
+//
+// Users can always revoke their own tokens, tokens of others require permission to update the user
+func (svc auth) RevokePersonalAccessToken(ctx context.Context, userID, tokenID uint64) (err error) {
+	var (
+		u   *types.User
+		c   *types.Credentials
+		aam = &authActionProps{credentials: &types.Credentials{Kind: credentialsTypePersonalAccessToken, ID: tokenID}}
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) (err error) {
+		if u, err = svc.personalAccessTokenOwner(ctx, s, userID); err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		c, err = store.LookupCredentialsByID(ctx, s, tokenID)
+		if errors.IsNotFound(err) || (err == nil && (c.OwnerID != u.ID || c.Kind != credentialsTypePersonalAccessToken || c.DeletedAt != nil)) {
+			return AuthErrInvalidToken(aam)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		aam.setCredentials(c)
+
+		c.DeletedAt = now()
+		return store.UpdateCredentials(ctx, s, c)
+	})
+
+	return svc.recordAction(ctx, aam, AuthActionPersonalAccessTokenRevoke, err)
+}
+
+// AuthenticatePersonalAccessToken verifies personal access token
+// and returns token owner (with roles) and token's scope
+//
+// Satisfies auth.PersonalAccessTokenAuthenticator interface. Action is not recorded
+// to avoid flooding the action log with entries for every API request
+func (svc auth) AuthenticatePersonalAccessToken(ctx context.Context, token string) (_ internalAuth.Identifiable, scope string, err error) {
+	var (
+		u *types.User
+		c *types.Credentials
+
+		aam = &authActionProps{credentials: &types.Credentials{Kind: credentialsTypePersonalAccessToken}}
+	)
+
+	if !svc.settings.Auth.PersonalAccessTokens.Enabled {
+		return nil, "", AuthErrDisabledPersonalAccessTokens()
+	}
+
+	credentialsID, secret := parsePersonalAccessToken(token)
+	if credentialsID == 0 {
+		return nil, "", AuthErrInvalidToken(aam)
+	}
+
+	c, err = store.LookupCredentialsByID(ctx, svc.store, credentialsID)
+	if errors.IsNotFound(err) {
+		return nil, "", AuthErrInvalidToken(aam)
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c.Kind != credentialsTypePersonalAccessToken || c.DeletedAt != nil || (c.ExpiresAt != nil && now().After(*c.ExpiresAt)) {
+		return nil, "", AuthErrInvalidToken(aam)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Credentials), []byte(hashPersonalAccessToken(secret))) != 1 {
+		return nil, "", AuthErrInvalidToken(aam)
+	}
+
+	if u, err = store.LookupUserByID(ctx, svc.store, c.OwnerID); err != nil {
+		return nil, "", err
+	}
+
+	if !u.Valid() {
+		return nil, "", AuthErrInvalidToken(aam)
+	}
+
+	// roles are always loaded so that changes of
+	// user's memberships are reflected immediately
+	if err = svc.LoadRoleMemberships(ctx, u); err != nil {
+		return nil, "", err
+	}
+
+	if c.LastUsedAt == nil || now().Sub(*c.LastUsedAt) > personalAccessTokenUsageInterval {
+		c.LastUsedAt = now()
+		if err = store.UpdateCredentials(ctx, svc.store, c); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return u, strings.Join(personalAccessTokenFromCredentials(c).Scope, " "), nil
+}
+
+// loads owner of the personal access tokens and checks if tokens can be managed
+func (svc auth) personalAccessTokenOwner(ctx context.Context, s store.Users, userID uint64) (u *types.User, err error) {
+	if userID == 0 {
+		return nil, AuthErrNotAllowedToManagePersonalAccessTokens()
+	}
+
+	if u, err = store.LookupUserByID(ctx, s, userID); err != nil {
+		return nil, err
+	}
+
+	if internalAuth.GetIdentityFromContext(ctx).Identity() != u.ID && !svc.ac.CanUpdateUser(ctx, u) {
+		return nil, AuthErrNotAllowedToManagePersonalAccessTokens()
+	}
+
+	return u, nil
+}
+
+// checks token's expiration date against the settings
+//
+// When max lifetime is set, tokens without expiration date expire after max lifetime
+func (svc auth) personalAccessTokenExpiry(expiresAt *time.Time) (*time.Time, error) {
+	var (
+		maxLifetime = svc.settings.Auth.PersonalAccessTokens.MaxLifetime
+	)
+
+	if expiresAt != nil && !expiresAt.After(*now()) {
+		return nil, fmt.Errorf("expiration date in the past")
+	}
+
+	if maxLifetime == 0 {
+		return expiresAt, nil
+	}
+
+	max := now().Add(time.Duration(maxLifetime) * time.Hour * 24)
+	if expiresAt == nil {
+		return &max, nil
+	}
+
+	if expiresAt.After(max) {
+		return nil, fmt.Errorf("expiration date exceeds max lifetime")
+	}
+
+	return expiresAt, nil
+}
+
+func findPersonalAccessTokens(ctx context.Context, s store.Credentials, userID uint64) (cc types.CredentialsSet, err error) {
+	cc, _, err = store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypePersonalAccessToken,
+		Deleted: filter.StateExcluded,
+	})
+
+	return
+}
+
+func personalAccessTokenFromCredentials(c *types.Credentials) *types.PersonalAccessToken {
+	var (
+		meta = personalAccessTokenMeta{}
+	)
+
+	_ = json.Unmarshal(c.Meta, &meta)
+
+	return &types.PersonalAccessToken{
+		ID:         c.ID,
+		OwnerID:    c.OwnerID,
+		Name:       c.Label,
+		Scope:      meta.Scope,
+		LastUsedAt: c.LastUsedAt,
+		ExpiresAt:  c.ExpiresAt,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+// removes duplicates and checks if all scopes are allowed
+func normalizePersonalAccessTokenScope(scope []string) (out []string, err error) {
+	var (
+		has = make(map[string]bool)
+	)
+
+	for _, s := range scope {
+		for _, s = range strings.Fields(s) {
+			if has[s] {
+				continue
+			}
+
+			allowed := false
+			for _, a := range personalAccessTokenScopes {
+				allowed = allowed || a == s
+			}
+
+			if !allowed {
+				return nil, fmt.Errorf("scope %q not allowed", s)
+			}
+
+			has[s] = true
+			out = append(out, s)
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("scope is required")
+	}
+
+	return
+}
+
+// Token = <prefix><random hex chars><credentials-id>
+func parsePersonalAccessToken(token string) (ID uint64, secret string) {
+	const secretLength = personalAccessTokenSecretLength * 2
+
+	if !internalAuth.IsPersonalAccessToken(token) {
+		return
+	}
+
+	token = strings.TrimPrefix(token, internalAuth.PersonalAccessTokenPrefix)
+	if len(token) <= secretLength {
+		return
+	}
+
+	if ID, _ = strconv.ParseUint(token[secretLength:], 10, 64); ID == 0 {
+		return
+	}
+
+	return ID, token[:secretLength]
+}
+
+// tokens are long and random so there is no need for slow hashing
+func hashPersonalAccessToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,11 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/ldap/ldaptest"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/store/sqlite3"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -135,6 +140,63 @@ func TestAuth_External(t *testing.T) {
 	}
 }
 
+func TestAuth_ExternalRoles(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		admins = &types.Role{ID: nextID(), Handle: "admins", Name: "Admins"}
+		devs   = &types.Role{ID: nextID(), Handle: "developers", Name: "Developers"}
+		other  = &types.Role{ID: nextID(), Handle: "other", Name: "Other"}
+
+		user = &types.User{Email: "saml@test.cortezaproject.org", ID: nextID(), CreatedAt: *now()}
+
+		samlCredentials = &types.Credentials{
+			ID:          nextID(),
+			OwnerID:     user.ID,
+			Kind:        "saml:partner",
+			Credentials: "name-id",
+			CreatedAt:   *now(),
+		}
+
+		memberOf = func(r *types.Role) bool {
+			mm, _, err := store.SearchRoleMembers(ctx, svc.store, types.RoleMemberFilter{UserID: user.ID, RoleID: r.ID})
+			req.NoError(err)
+			return len(mm) > 0
+		}
+	)
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateRoles(ctx))
+	req.NoError(svc.store.TruncateRoleMembers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, user))
+	req.NoError(store.CreateRole(ctx, svc.store, admins, devs, other))
+	req.NoError(store.CreateRoleMember(ctx, svc.store, &types.RoleMember{UserID: user.ID, RoleID: devs.ID}))
+	req.NoError(store.CreateRoleMember(ctx, svc.store, &types.RoleMember{UserID: user.ID, RoleID: other.ID}))
+	req.NoError(store.CreateCredentials(ctx, svc.store, samlCredentials))
+
+	ids, err := svc.ExternalCredentialsOwners(ctx, "saml:partner", "name-id")
+	req.NoError(err)
+	req.Equal([]uint64{user.ID}, ids)
+
+	ids, err = svc.ExternalCredentialsOwners(ctx, "saml", "name-id")
+	req.NoError(err)
+	req.Empty(ids)
+
+	req.NoError(svc.ExternalRoles(ctx, "saml:partner", user.ID, map[string]bool{
+		admins.Handle: true,
+		devs.Handle:   false,
+		"missing":     true,
+	}))
+
+	req.True(memberOf(admins))
+	req.False(memberOf(devs))
+	// memberships of roles that are not mapped are not modified
+	req.True(memberOf(other))
+}
+
 func TestAuth_InternalSignUp(t *testing.T) {
 	var (
 		req = require.New(t)
@@ -347,6 +409,228 @@ func Test_auth_checkPassword(t *testing.T) {
 	}
 }
 
+type mockBreachedPasswords map[string]bool
+
+func (m mockBreachedPasswords) Contains(password string) (bool, error) {
+	return m[password], nil
+}
+
+func Test_auth_checkPasswordStrength(t *testing.T) {
+	svc := auth{
+		settings:          &types.AppSettings{},
+		breachedPasswords: mockBreachedPasswords{"Password1!": true},
+	}
+
+	pc := &svc.settings.Auth.Internal.PasswordConstraints
+	pc.MinLength = 8
+	pc.MinUpperCase = 1
+	pc.MinLowerCase = 1
+	pc.MinNumCount = 1
+	pc.MinSpecial = 1
+
+	tests := []struct {
+		password string
+		err      error
+	}{
+		{"Pa1!", AuthErrPasswordNotSecure()},
+		{"password1!", AuthErrPasswordNotSecure()},
+		{"PASSWORD1!", AuthErrPasswordNotSecure()},
+		{"Password!!", AuthErrPasswordNotSecure()},
+		{"Password11", AuthErrPasswordNotSecure()},
+		{"Password1!", AuthErrPasswordBreached()},
+		{"Pässwörd1!", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			err := svc.checkPasswordStrength(tt.password)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err.Error())
+			}
+		})
+	}
+
+	// defaults
+	svc = auth{settings: &types.AppSettings{}}
+	require.False(t, svc.CheckPasswordStrength("1234"))
+	require.True(t, svc.CheckPasswordStrength("12345"))
+}
+
+func TestAuth_PasswordPolicy(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		u = &types.User{Email: "policy@test.cortezaproject.org", ID: nextID(), CreatedAt: *now(), Meta: &types.UserMeta{}}
+	)
+
+	svc.settings.Auth.Internal.Enabled = true
+	svc.settings.Auth.Internal.PasswordConstraints.History = 2
+
+	u.Meta.SecurityPolicy.PasswordChangeRequired = true
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+
+	required, err := svc.PasswordChangeRequired(ctx, u)
+	req.NoError(err)
+	req.True(required)
+
+	req.NoError(svc.SetPassword(ctx, u.ID, "first password"))
+	req.NoError(svc.ChangePassword(ctx, u.ID, "first password", "second password"))
+
+	// current and previous password can not be reused
+	req.True(AuthErrPasswordReused().Is(svc.ChangePassword(ctx, u.ID, "second password", "second password")))
+	req.True(AuthErrPasswordReused().Is(svc.ChangePassword(ctx, u.ID, "second password", "first password")))
+
+	req.NoError(svc.ChangePassword(ctx, u.ID, "second password", "third password"))
+	req.NoError(svc.ChangePassword(ctx, u.ID, "third password", "first password"))
+
+	// requirement is cleared by password change
+	u, err = store.LookupUserByID(ctx, svc.store, u.ID)
+	req.NoError(err)
+	required, err = svc.PasswordChangeRequired(ctx, u)
+	req.NoError(err)
+	req.False(required)
+
+	// password expires
+	svc.settings.Auth.Internal.PasswordConstraints.MaxAge = 30
+	defer func(n func() *time.Time) { now = n }(now)
+	now = func() *time.Time {
+		c := time.Now().Add(31 * 24 * time.Hour)
+		return &c
+	}
+
+	required, err = svc.PasswordChangeRequired(ctx, u)
+	req.NoError(err)
+	req.True(required)
+}
+
+func TestAuth_Lockout(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		ts = time.Now()
+		u  = &types.User{Email: "lockout@test.cortezaproject.org", ID: nextID(), CreatedAt: ts, EmailConfirmed: true}
+
+		login = func(password string) error {
+			_, err := svc.InternalLogin(ctx, u.Email, password)
+			return err
+		}
+	)
+
+	defer func(n func() *time.Time) { now = n }(now)
+	now = func() *time.Time { c := ts; return &c }
+
+	svc.settings.Auth.Internal.Enabled = true
+	svc.settings.Auth.Internal.Lockout.Enabled = true
+	svc.settings.Auth.Internal.Lockout.MaxAttempts = 3
+	svc.settings.Auth.Internal.Lockout.Duration = 10
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+	req.NoError(svc.SetPasswordCredentials(ctx, u.ID, "valid password"))
+
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+
+	// second failed attempt delays the next one;
+	// valid password is not accepted
+	req.True(AuthErrInvalidCredentials().Is(login("valid password")))
+
+	ts = ts.Add(time.Second)
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+
+	// third failed attempt locks the account
+	until, err := svc.LoginLocked(ctx, u.ID)
+	req.NoError(err)
+	req.NotNil(until)
+	req.Equal(ts.Add(10*time.Minute).Unix(), until.Unix())
+
+	ts = ts.Add(5 * time.Minute)
+	req.True(AuthErrInvalidCredentials().Is(login("valid password")))
+
+	// lock expires
+	ts = ts.Add(5*time.Minute + time.Second)
+	req.NoError(login("valid password"))
+
+	// successful login resets the counter
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+
+	ts = ts.Add(2 * time.Second)
+	req.True(AuthErrInvalidCredentials().Is(login("invalid")))
+	req.True(AuthErrInvalidCredentials().Is(login("valid password")))
+
+	// unlock
+	req.NoError(svc.ResetFailedLogins(ctx, u.ID))
+	req.NoError(login("valid password"))
+}
+
+func Test_ipFailedLogins(t *testing.T) {
+	var (
+		req = require.New(t)
+		ts  = time.Now()
+		r   = newIpFailedLogins()
+	)
+
+	for i := 0; i < 3; i++ {
+		r.fail("10.0.0.1", ts, 3, time.Minute)
+		ts = ts.Add(2 * time.Minute)
+	}
+
+	// attempts older than lockout duration are forgotten
+	req.False(r.get("10.0.0.1").locked(ts))
+
+	for i := 0; i < 3; i++ {
+		r.fail("10.0.0.1", ts, 3, time.Minute)
+	}
+
+	req.True(r.get("10.0.0.1").locked(ts))
+	req.False(r.get("10.0.0.2").locked(ts))
+	req.False(r.get("10.0.0.1").locked(ts.Add(time.Minute + time.Second)))
+
+	// failed attempts while locked do not unlock
+	for i := 0; i < 3; i++ {
+		r.fail("10.0.0.1", ts, 3, time.Minute)
+	}
+
+	req.True(r.get("10.0.0.1").locked(ts))
+	req.True(r.get("10.0.0.1").locked(ts.Add(time.Minute - time.Second)))
+
+	// counter starts again after lock expires
+	ts = ts.Add(time.Minute + time.Second)
+	r.fail("10.0.0.1", ts, 3, time.Minute)
+	req.False(r.get("10.0.0.1").locked(ts))
+	req.Equal(uint(1), r.get("10.0.0.1").Count)
+}
+
+func Test_failedLogins_failWhileLocked(t *testing.T) {
+	var (
+		req = require.New(t)
+		ts  = time.Now()
+		fl  = failedLogins{}
+	)
+
+	for i := 0; i < 4; i++ {
+		req.False(fl.fail(ts, 5, time.Minute))
+	}
+
+	req.True(fl.fail(ts, 5, time.Minute))
+	req.True(fl.locked(ts))
+
+	// 6th failure, recorded while account is locked
+	req.False(fl.fail(ts.Add(time.Second), 5, time.Minute))
+	req.True(fl.locked(ts.Add(time.Second)))
+}
+
 func Test_auth_validateToken(t *testing.T) {
 	type args struct {
 		token string
@@ -392,3 +676,412 @@ func Test_auth_validateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuth_RemoveWebAuthn(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		u = &types.User{Email: "webauthn@test.cortezaproject.org", ID: nextID(), CreatedAt: *now(), Meta: &types.UserMeta{}}
+
+		k1 = &types.Credentials{ID: nextID(), OwnerID: u.ID, Kind: credentialsTypeMfaWebAuthn, Label: "key 1", Credentials: "a2V5MQ", CreatedAt: *now()}
+		k2 = &types.Credentials{ID: nextID(), OwnerID: u.ID, Kind: credentialsTypeMfaWebAuthn, Label: "key 2", Credentials: "a2V5Mg", CreatedAt: *now()}
+	)
+
+	u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+	svc.settings.Auth.MultiFactor.WebAuthn.Enabled = true
+	svc.settings.Auth.MultiFactor.WebAuthn.Enforced = true
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+	req.NoError(store.CreateCredentials(ctx, svc.store, k1, k2))
+
+	ctx = internalAuth.SetIdentityToContext(ctx, u)
+
+	u, err := svc.RemoveWebAuthn(ctx, u.ID, k1.ID)
+	req.NoError(err)
+	req.True(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	_, err = svc.RemoveWebAuthn(ctx, u.ID, k1.ID)
+	req.True(AuthErrUnconfiguredWebAuthn().Is(err))
+
+	// last key can not be removed while enforced globally
+	_, err = svc.RemoveWebAuthn(ctx, u.ID, k2.ID)
+	req.True(AuthErrEnforcedMFAWithWebAuthn().Is(err))
+
+	svc.settings.Auth.MultiFactor.WebAuthn.Enforced = false
+	u, err = svc.RemoveWebAuthn(ctx, u.ID, k2.ID)
+	req.NoError(err)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	cc, err := svc.WebAuthnCredentials(ctx, u.ID)
+	req.NoError(err)
+	req.Empty(cc)
+}
+
+func TestAuth_WebAuthnAssertionOptions(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+		rp  = webauthn.RelyingParty{ID: "example.tld", Origin: "https://example.tld"}
+	)
+
+	_, err := svc.WebAuthnAssertionOptions(ctx, rp, []byte("challenge"))
+	req.True(AuthErrDisabledMFAWithWebAuthn().Is(err))
+
+	svc.settings.Auth.MultiFactor.WebAuthn.Enabled = true
+	_, err = svc.WebAuthnAssertionOptions(ctx, rp, []byte("challenge"))
+	req.True(AuthErrDisabledPasswordlessWebAuthn().Is(err))
+
+	svc.settings.Auth.MultiFactor.WebAuthn.Passwordless = true
+	o, err := svc.WebAuthnAssertionOptions(ctx, rp, []byte("challenge"))
+	req.NoError(err)
+	req.Empty(o.AllowCredentials)
+	req.Equal("example.tld", o.RPID)
+}
+
+func TestAuth_RecoveryCodes(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		u = &types.User{Email: "recovery@test.cortezaproject.org", ID: nextID(), CreatedAt: *now(), Meta: &types.UserMeta{}}
+	)
+
+	svc.settings.Auth.MultiFactor.TOTP.Enabled = true
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+
+	ctx = internalAuth.SetIdentityToContext(ctx, u)
+
+	_, err := svc.GenerateRecoveryCodes(ctx)
+	req.True(AuthErrUnconfiguredTOTP().Is(err))
+
+	u.Meta.SecurityPolicy.MFA.EnforcedTOTP = true
+	req.NoError(store.UpdateUser(ctx, svc.store, u))
+
+	codes, err := svc.GenerateRecoveryCodes(ctx)
+	req.NoError(err)
+	req.Len(codes, recoveryCodesCount)
+	req.Regexp(`^[a-z2-9]{5}-[a-z2-9]{5}$`, codes[0])
+
+	// separators and case are ignored
+	req.NoError(svc.ValidateRecoveryCode(ctx, strings.ToUpper(strings.Replace(codes[0], "-", " ", 1))))
+
+	// codes can be used only once
+	req.True(AuthErrInvalidRecoveryCode().Is(svc.ValidateRecoveryCode(ctx, codes[0])))
+
+	cc, err := svc.RecoveryCodes(ctx, u.ID)
+	req.NoError(err)
+	req.Len(cc, recoveryCodesCount-1)
+
+	// regenerating revokes old codes
+	_, err = svc.GenerateRecoveryCodes(ctx)
+	req.NoError(err)
+	req.True(AuthErrInvalidRecoveryCode().Is(svc.ValidateRecoveryCode(ctx, codes[1])))
+}
+
+func TestAuth_RecoveryCodesLockout(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		ts = time.Now()
+		u  = &types.User{Email: "recovery-lockout@test.cortezaproject.org", ID: nextID(), CreatedAt: ts, Meta: &types.UserMeta{}}
+	)
+
+	defer func(n func() *time.Time) { now = n }(now)
+	now = func() *time.Time { c := ts; return &c }
+
+	svc.settings.Auth.MultiFactor.TOTP.Enabled = true
+	svc.settings.Auth.Internal.Lockout.Enabled = true
+	svc.settings.Auth.Internal.Lockout.MaxAttempts = 3
+	svc.settings.Auth.Internal.Lockout.Duration = 10
+
+	u.Meta.SecurityPolicy.MFA.EnforcedTOTP = true
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+
+	ctx = internalAuth.SetIdentityToContext(ctx, u)
+
+	codes, err := svc.GenerateRecoveryCodes(ctx)
+	req.NoError(err)
+
+	for i := 0; i < 3; i++ {
+		ts = ts.Add(time.Minute)
+		req.True(AuthErrInvalidRecoveryCode().Is(svc.ValidateRecoveryCode(ctx, "aaaaa-aaaaa")))
+	}
+
+	// account is locked, valid code is not accepted
+	until, err := svc.LoginLocked(ctx, u.ID)
+	req.NoError(err)
+	req.NotNil(until)
+	req.True(AuthErrInvalidRecoveryCode().Is(svc.ValidateRecoveryCode(ctx, codes[0])))
+
+	// lock expires
+	ts = ts.Add(10*time.Minute + time.Second)
+	req.NoError(svc.ValidateRecoveryCode(ctx, codes[0]))
+}
+
+func TestAuth_PersonalAccessTokens(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		ts = time.Now()
+		u  = &types.User{Email: "pat@test.cortezaproject.org", ID: nextID(), CreatedAt: ts}
+	)
+
+	defer func(n func() *time.Time) { now = n }(now)
+	now = func() *time.Time { c := ts; return &c }
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, u))
+
+	ctx = internalAuth.SetIdentityToContext(ctx, u)
+
+	_, _, err := svc.CreatePersonalAccessToken(ctx, "test", []string{"api"}, nil)
+	req.True(AuthErrDisabledPersonalAccessTokens().Is(err))
+
+	svc.settings.Auth.PersonalAccessTokens.Enabled = true
+	svc.settings.Auth.PersonalAccessTokens.MaxLifetime = 30
+
+	_, _, err = svc.CreatePersonalAccessToken(ctx, " ", []string{"api"}, nil)
+	req.True(AuthErrInvalidPersonalAccessTokenName().Is(err))
+
+	_, _, err = svc.CreatePersonalAccessToken(ctx, "test", []string{"openid"}, nil)
+	req.True(AuthErrInvalidPersonalAccessTokenScope().Is(err))
+
+	tooLate := ts.Add(time.Hour * 24 * 31)
+	_, _, err = svc.CreatePersonalAccessToken(ctx, "test", []string{"api"}, &tooLate)
+	req.True(AuthErrInvalidPersonalAccessTokenExpiry().Is(err))
+
+	// tokens without expiration date expire after max lifetime
+	token, pat, err := svc.CreatePersonalAccessToken(ctx, "test", []string{"profile api:compose", "profile"}, nil)
+	req.NoError(err)
+	req.True(internalAuth.IsPersonalAccessToken(token))
+	req.Equal([]string{"profile", "api:compose"}, pat.Scope)
+	req.NotNil(pat.ExpiresAt)
+	req.Equal(ts.Add(time.Hour*24*30).Unix(), pat.ExpiresAt.Unix())
+
+	i, scope, err := svc.AuthenticatePersonalAccessToken(context.Background(), token)
+	req.NoError(err)
+	req.Equal(u.ID, i.Identity())
+	req.Equal("profile api:compose", scope)
+
+	_, _, err = svc.AuthenticatePersonalAccessToken(context.Background(), token[:len(token)-1])
+	req.Error(err)
+
+	_, _, err = svc.AuthenticatePersonalAccessToken(context.Background(), strings.Replace(token, token[5:10], "00000", 1))
+	req.True(AuthErrInvalidToken().Is(err))
+
+	tt, err := svc.PersonalAccessTokens(ctx, u.ID)
+	req.NoError(err)
+	req.Len(tt, 1)
+	req.NotNil(tt[0].LastUsedAt)
+
+	// expired tokens are not accepted
+	ts = ts.Add(time.Hour * 24 * 31)
+	_, _, err = svc.AuthenticatePersonalAccessToken(context.Background(), token)
+	req.True(AuthErrInvalidToken().Is(err))
+
+	req.NoError(svc.RevokePersonalAccessToken(ctx, u.ID, pat.ID))
+	req.True(AuthErrInvalidToken().Is(svc.RevokePersonalAccessToken(ctx, u.ID, pat.ID)))
+
+	tt, err = svc.PersonalAccessTokens(ctx, u.ID)
+	req.NoError(err)
+	req.Empty(tt)
+}
+
+func TestAuth_LDAP(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		admins = &types.Role{ID: nextID(), Handle: "admins", Name: "Admins"}
+		devs   = &types.Role{ID: nextID(), Handle: "developers", Name: "Developers"}
+
+		bind = ldaptest.NewEntry("cn=corteza,ou=services,dc=example,dc=org", "userPassword", "service")
+		jane = ldaptest.NewEntry("uid=jane,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "jane",
+			"mail", "jane@example.org",
+			"displayName", "Jane Doe",
+			"userPassword", "secret",
+		)
+		john = ldaptest.NewEntry("uid=john,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "john",
+			"mail", "john@example.org",
+			"userPassword", "secret",
+		)
+		group = ldaptest.NewEntry("cn=developers,ou=groups,dc=example,dc=org",
+			"objectClass", "groupOfNames",
+			"cn", "developers",
+			"member", jane.DN,
+		)
+
+		memberOf = func(u *types.User, r *types.Role) bool {
+			mm, _, err := store.SearchRoleMembers(ctx, svc.store, types.RoleMemberFilter{UserID: u.ID, RoleID: r.ID})
+			req.NoError(err)
+			return len(mm) > 0
+		}
+	)
+
+	srv, err := ldaptest.NewServer(bind, jane, john, group)
+	req.NoError(err)
+	defer srv.Close()
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateRoles(ctx))
+	req.NoError(svc.store.TruncateRoleMembers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateRole(ctx, svc.store, admins, devs))
+
+	svc.settings.Auth.LDAP.URL = srv.URL()
+	svc.settings.Auth.LDAP.BindDN = bind.DN
+	svc.settings.Auth.LDAP.BindPassword = "service"
+	svc.settings.Auth.LDAP.BaseDN = "dc=example,dc=org"
+	svc.settings.Auth.LDAP.Groups.Mapping = []types.LDAPGroupMapping{
+		{Group: "developers", Role: devs.Handle},
+		{Group: "cn=admins,ou=groups,dc=example,dc=org", Role: admins.Handle},
+	}
+
+	_, err = svc.LDAPLogin(ctx, "jane", "secret")
+	req.True(AuthErrLdapDisabledByConfig().Is(err))
+
+	svc.settings.Auth.LDAP.Enabled = true
+
+	_, err = svc.LDAPLogin(ctx, "jane", "invalid")
+	req.True(AuthErrInvalidCredentials().Is(err))
+
+	_, err = svc.LDAPLogin(ctx, "nobody", "secret")
+	req.True(AuthErrInvalidCredentials().Is(err))
+
+	// filter injection
+	_, err = svc.LDAPLogin(ctx, "*", "secret")
+	req.True(AuthErrInvalidCredentials().Is(err))
+
+	t.Run("login creates user and memberships", func(t *testing.T) {
+		req := require.New(t)
+
+		u, err := svc.LDAPLogin(ctx, "jane", "secret")
+		req.NoError(err)
+		req.Equal("jane@example.org", u.Email)
+		req.Equal("Jane Doe", u.Name)
+		req.Equal("jane", u.Handle)
+		req.True(u.EmailConfirmed)
+		req.True(memberOf(u, devs))
+		req.False(memberOf(u, admins))
+
+		// same user on the next login, also when logging in with email
+		u2, err := svc.LDAPLogin(ctx, "jane@example.org", "secret")
+		req.NoError(err)
+		req.Equal(u.ID, u2.ID)
+	})
+
+	t.Run("login links existing user by email", func(t *testing.T) {
+		req := require.New(t)
+
+		existing := &types.User{ID: nextID(), Email: "john@example.org", Handle: "john", CreatedAt: time.Now()}
+		req.NoError(store.CreateUser(ctx, svc.store, existing))
+		req.NoError(store.CreateRoleMember(ctx, svc.store, &types.RoleMember{RoleID: admins.ID, UserID: existing.ID}))
+
+		u, err := svc.LDAPLogin(ctx, "john", "secret")
+		req.NoError(err)
+		req.Equal(existing.ID, u.ID)
+
+		// not in the mapped group
+		req.False(memberOf(u, admins))
+	})
+
+	t.Run("sync updates memberships and suspends missing users", func(t *testing.T) {
+		req := require.New(t)
+
+		svc.settings.Auth.LDAP.Sync.SuspendMissing = true
+
+		alice := ldaptest.NewEntry("uid=alice,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "alice",
+			"mail", "alice@example.org",
+		)
+
+		noMail := ldaptest.NewEntry("uid=bob,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "bob",
+		)
+
+		srv.SetEntries(bind, jane, alice, noMail,
+			ldaptest.NewEntry("cn=developers,ou=groups,dc=example,dc=org", "cn", "developers", "member", alice.DN),
+			ldaptest.NewEntry("cn=admins,ou=groups,dc=example,dc=org", "cn", "admins", "member", jane.DN),
+		)
+
+		req.NoError(svc.LDAPSync(ctx))
+
+		u, err := store.LookupUserByEmail(ctx, svc.store, "alice@example.org")
+		req.NoError(err)
+		req.True(memberOf(u, devs))
+
+		u, err = store.LookupUserByEmail(ctx, svc.store, "jane@example.org")
+		req.NoError(err)
+		req.Nil(u.SuspendedAt)
+		req.False(memberOf(u, devs))
+		req.True(memberOf(u, admins))
+
+		john, err := store.LookupUserByEmail(ctx, svc.store, "john@example.org")
+		req.NoError(err)
+		req.NotNil(john.SuspendedAt)
+
+		// user suspended by sync is restored when back in the directory
+		srv.SetEntries(bind, jane, alice, ldaptest.NewEntry("uid=john,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "john",
+			"mail", "john@example.org",
+		))
+
+		req.NoError(svc.LDAPSync(ctx))
+
+		john, err = store.LookupUserByID(ctx, svc.store, john.ID)
+		req.NoError(err)
+		req.Nil(john.SuspendedAt)
+	})
+
+	t.Run("entry without configured id attribute is rejected", func(t *testing.T) {
+		req := require.New(t)
+
+		svc.settings.Auth.LDAP.Attributes.ID = "entryUUID"
+		defer func() { svc.settings.Auth.LDAP.Attributes.ID = "" }()
+
+		mallory := ldaptest.NewEntry("uid=mallory,ou=people,dc=example,dc=org",
+			"objectClass", "person",
+			"uid", "mallory",
+			"mail", "mallory@example.org",
+			"userPassword", "secret",
+		)
+
+		srv.SetEntries(bind, jane, mallory)
+
+		_, err := svc.LDAPLogin(ctx, "mallory", "secret")
+		req.True(AuthErrLdapEntryWithoutID().Is(err))
+
+		// existing LDAP users are left intact
+		jane, err := store.LookupUserByEmail(ctx, svc.store, "jane@example.org")
+		req.NoError(err)
+		req.Equal("Jane Doe", jane.Name)
+
+		_, err = store.LookupUserByEmail(ctx, svc.store, "mallory@example.org")
+		req.True(errors.IsNotFound(err))
+	})
+}
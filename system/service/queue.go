@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/messagebus"
 	"github.com/cortezaproject/corteza-server/store"
 )
@@ -21,6 +22,8 @@ type (
 		CanReadQueue(ctx context.Context, c *messagebus.QueueSettings) bool
 		CanUpdateQueue(ctx context.Context, c *messagebus.QueueSettings) bool
 		CanDeleteQueue(ctx context.Context, c *messagebus.QueueSettings) bool
+		CanReadMessageOnQueue(ctx context.Context, c *messagebus.QueueSettings) bool
+		CanWriteMessageOnQueue(ctx context.Context, c *messagebus.QueueSettings) bool
 	}
 )
 
@@ -72,6 +75,10 @@ func (svc *queue) Create(ctx context.Context, new *messagebus.QueueSettings) (q
 			return QueueErrInvalidConsumer(qProps)
 		}
 
+		if err = svc.validateSettings(new); err != nil {
+			return QueueErrInvalidSettings(qProps).Wrap(err)
+		}
+
 		// Set new values after beforeCreate events are emitted
 		new.ID = nextID()
 		new.CreatedAt = *now()
@@ -115,6 +122,10 @@ func (svc *queue) Update(ctx context.Context, upd *messagebus.QueueSettings) (q
 			return QueueErrInvalidConsumer(qProps)
 		}
 
+		if err = svc.validateSettings(upd); err != nil {
+			return QueueErrInvalidSettings(qProps).Wrap(err)
+		}
+
 		// Set new values after beforeCreate events are emitted
 		upd.UpdatedAt = now()
 		upd.CreatedAt = qq.CreatedAt
@@ -233,6 +244,99 @@ func (svc *queue) Search(ctx context.Context, filter messagebus.QueueSettingsFil
 	return q, f, svc.recordAction(ctx, aProps, QueueActionSearch, err)
 }
 
+// SearchFailed returns messages from the dead-letter queue
+func (svc *queue) SearchFailed(ctx context.Context, queueID uint64, mf messagebus.QueueMessageFilter) (mm messagebus.QueueMessageSet, f messagebus.QueueMessageFilter, err error) {
+	var (
+		qProps = &queueActionProps{}
+		q      *messagebus.QueueSettings
+	)
+
+	err = func() (err error) {
+		if q, err = svc.lookupByID(ctx, queueID, qProps); err != nil {
+			return
+		}
+
+		if !svc.ac.CanReadMessageOnQueue(ctx, q) {
+			return QueueErrNotAllowedToReadFrom(qProps)
+		}
+
+		mf.Queue = q.Queue
+		mf.Failed = filter.StateExclusive
+
+		if mm, f, err = store.SearchMessagebusQueueMessages(ctx, svc.store, mf); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return mm, f, svc.recordAction(ctx, qProps, QueueActionSearchFailed, err)
+}
+
+// Replay writes the dead-lettered message to the queue once more
+//
+// Replayed message is marked as processed; when it fails
+// again, it stays in the dead-letter queue with the new
+// error and number of attempts
+func (svc *queue) Replay(ctx context.Context, queueID, messageID uint64) (err error) {
+	var (
+		qProps = &queueActionProps{}
+		q      *messagebus.QueueSettings
+		m      *messagebus.QueueMessage
+	)
+
+	err = func() (err error) {
+		if q, err = svc.lookupByID(ctx, queueID, qProps); err != nil {
+			return
+		}
+
+		if !svc.ac.CanWriteMessageOnQueue(ctx, q) {
+			return QueueErrNotAllowedToWriteTo(qProps)
+		}
+
+		if m, err = store.LookupMessagebusQueueMessageByID(ctx, svc.store, messageID); err != nil || m.Queue != q.Queue {
+			return QueueErrMessageNotFound(qProps)
+		}
+
+		qProps.setMessage(m)
+
+		if m.Failed == nil || m.Processed != nil {
+			return QueueErrMessageNotFailed(qProps)
+		}
+
+		m.Attempts++
+
+		if err = messagebus.Service().Replay(ctx, m); err != nil {
+			m.Failed = now()
+			m.Error = err.Error()
+
+			if uErr := store.UpdateMessagebusQueueMessage(ctx, svc.store, m); uErr != nil {
+				return uErr
+			}
+
+			return
+		}
+
+		m.Processed = now()
+		return store.UpdateMessagebusQueueMessage(ctx, svc.store, m)
+	}()
+
+	return svc.recordAction(ctx, qProps, QueueActionReplay, err)
+}
+
+func (svc *queue) lookupByID(ctx context.Context, ID uint64, qProps *queueActionProps) (q *messagebus.QueueSettings, err error) {
+	if ID == 0 {
+		return nil, QueueErrInvalidID()
+	}
+
+	if q, err = store.LookupMessagebusQueueSettingByID(ctx, svc.store, ID); err != nil {
+		return nil, QueueErrNotFound(qProps).Wrap(err)
+	}
+
+	qProps.setQueue(q)
+	return q, nil
+}
+
 func (svc *queue) isValidHandler(h messagebus.ConsumerType) bool {
 	for _, hh := range messagebus.ConsumerTypes() {
 		if h == hh {
@@ -241,3 +345,12 @@ func (svc *queue) isValidHandler(h messagebus.ConsumerType) bool {
 	}
 	return false
 }
+
+// validateSettings checks consumer specific queue settings
+func (svc *queue) validateSettings(qs *messagebus.QueueSettings) error {
+	if messagebus.ConsumerType(qs.Consumer) == messagebus.ConsumerAmqp && qs.Meta.Amqp != nil {
+		return qs.Meta.Amqp.Validate()
+	}
+
+	return nil
+}
@@ -21,10 +21,11 @@ import (
 
 type (
 	queueActionProps struct {
-		queue  *messagebus.QueueSettings
-		new    *messagebus.QueueSettings
-		update *messagebus.QueueSettings
-		search *messagebus.QueueSettingsFilter
+		queue   *messagebus.QueueSettings
+		new     *messagebus.QueueSettings
+		update  *messagebus.QueueSettings
+		search  *messagebus.QueueSettingsFilter
+		message *messagebus.QueueMessage
 	}
 
 	queueAction struct {
@@ -96,6 +97,17 @@ func (p *queueActionProps) setSearch(search *messagebus.QueueSettingsFilter) *qu
 	return p
 }
 
+// setMessage updates queueActionProps's message
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *queueActionProps) setMessage(message *messagebus.QueueMessage) *queueActionProps {
+	p.message = message
+	return p
+}
+
 // Serialize converts queueActionProps to actionlog.Meta
 //
 // This function is auto-generated.
@@ -121,6 +133,10 @@ func (p queueActionProps) Serialize() actionlog.Meta {
 	}
 	if p.search != nil {
 	}
+	if p.message != nil {
+		m.Set("message.ID", p.message.ID, true)
+		m.Set("message.queue", p.message.Queue, true)
+	}
 
 	return m
 }
@@ -204,6 +220,20 @@ func (p queueActionProps) Format(in string, err error) string {
 			fns(),
 		)
 	}
+
+	if p.message != nil {
+		// replacement for "{{message}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{message}}",
+			fns(
+				p.message.ID,
+				p.message.Queue,
+			),
+		)
+		pairs = append(pairs, "{{message.ID}}", fns(p.message.ID))
+		pairs = append(pairs, "{{message.queue}}", fns(p.message.Queue))
+	}
 	return strings.NewReplacer(pairs...).Replace(in)
 }
 
@@ -359,6 +389,46 @@ func QueueActionUndelete(props ...*queueActionProps) *queueAction {
 	return a
 }
 
+// QueueActionSearchFailed returns "system:queue.searchFailed" action
+//
+// This function is auto-generated.
+//
+func QueueActionSearchFailed(props ...*queueActionProps) *queueAction {
+	a := &queueAction{
+		timestamp: time.Now(),
+		resource:  "system:queue",
+		action:    "searchFailed",
+		log:       "searched for failed messages on {{queue}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// QueueActionReplay returns "system:queue.replay" action
+//
+// This function is auto-generated.
+//
+func QueueActionReplay(props ...*queueActionProps) *queueAction {
+	a := &queueAction{
+		timestamp: time.Now(),
+		resource:  "system:queue",
+		action:    "replay",
+		log:       "replayed {{message}} on {{queue}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -467,6 +537,74 @@ func QueueErrInvalidID(mm ...*queueActionProps) *errors.Error {
 	return e
 }
 
+// QueueErrMessageNotFound returns "system:queue.messageNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func QueueErrMessageNotFound(mm ...*queueActionProps) *errors.Error {
+	var p = &queueActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("message not found", nil),
+
+		errors.Meta("type", "messageNotFound"),
+		errors.Meta("resource", "system:queue"),
+
+		errors.Meta(queuePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "queue.errors.messageNotFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// QueueErrMessageNotFailed returns "system:queue.messageNotFailed" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func QueueErrMessageNotFailed(mm ...*queueActionProps) *errors.Error {
+	var p = &queueActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("message was delivered and can not be replayed", nil),
+
+		errors.Meta("type", "messageNotFailed"),
+		errors.Meta("resource", "system:queue"),
+
+		errors.Meta(queuePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "queue.errors.messageNotFailed"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // QueueErrInvalidConsumer returns "system:queue.invalidConsumer" as *errors.Error
 //
 //
@@ -501,6 +639,40 @@ func QueueErrInvalidConsumer(mm ...*queueActionProps) *errors.Error {
 	return e
 }
 
+// QueueErrInvalidSettings returns "system:queue.invalidSettings" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func QueueErrInvalidSettings(mm ...*queueActionProps) *errors.Error {
+	var p = &queueActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid queue settings", nil),
+
+		errors.Meta("type", "invalidSettings"),
+		errors.Meta("resource", "system:queue"),
+
+		errors.Meta(queuePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "queue.errors.invalidSettings"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // QueueErrAlreadyExists returns "system:queue.alreadyExists" as *errors.Error
 //
 //
@@ -1,11 +1,15 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/label"
+	"github.com/cortezaproject/corteza-server/pkg/options"
 	rep "github.com/cortezaproject/corteza-server/pkg/report"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -17,6 +21,14 @@ type (
 		eventbus  eventDispatcher
 		actionlog actionlog.Recorder
 		store     store.Storer
+		renderer  reportRenderer
+
+		// max number of exported rows; 0 for no limit
+		exportMaxRows int
+	}
+
+	reportRenderer interface {
+		Render(ctx context.Context, templateID uint64, dstType string, variables map[string]interface{}, options map[string]string) (io.ReadSeeker, error)
 	}
 
 	reportAccessController interface {
@@ -29,13 +41,40 @@ type (
 	}
 )
 
+const (
+	ReportExportCSV  = "csv"
+	ReportExportXLSX = "xlsx"
+	ReportExportHTML = "html"
+	ReportExportPDF  = "pdf"
+
+	// page size used when draining the frames for the export
+	reportExportPageSize = 1000
+)
+
 var (
 	reporters = make(map[string]rep.DatasourceProvider)
+
+	reportExportContentTypes = map[string]string{
+		ReportExportCSV:  "text/csv",
+		ReportExportXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		ReportExportHTML: string(types.DocumentTypeHTML),
+		ReportExportPDF:  string(types.DocumentTypePDF),
+	}
 )
 
 // Report is a default report service initializer
-func Report(s store.Storer, ac reportAccessController, al actionlog.Recorder, eb eventDispatcher) *report {
-	return &report{store: s, ac: ac, actionlog: al, eventbus: eb}
+func Report(s store.Storer, ac reportAccessController, al actionlog.Recorder, eb eventDispatcher, r reportRenderer, opt options.ReportOpt) *report {
+	return &report{store: s, ac: ac, actionlog: al, eventbus: eb, renderer: r, exportMaxRows: opt.ExportMaxRows}
+}
+
+// ReportExportContentType returns the content type of the export format; empty when not supported
+func ReportExportContentType(format string) string {
+	return reportExportContentTypes[format]
+}
+
+// ExportContentType returns the content type of the export format
+func (svc *report) ExportContentType(format string) string {
+	return ReportExportContentType(format)
 }
 
 func (svc *report) RegisterReporter(key string, r rep.DatasourceProvider) {
@@ -284,9 +323,89 @@ func (svc *report) Undelete(ctx context.Context, ID uint64) (err error) {
 	return svc.recordAction(ctx, aaProps, ReportActionUndelete, err)
 }
 
-func (svc *report) Run(ctx context.Context, ID uint64, dd rep.FrameDefinitionSet) (rr interface{}, err error) {
-	// @todo follow the RunFresh definition here
-	return nil, nil
+func (svc *report) Run(ctx context.Context, ID uint64, dd rep.FrameDefinitionSet) (out []*rep.Frame, err error) {
+	var (
+		aaProps = &reportActionProps{report: &types.Report{ID: ID}}
+		r       *types.Report
+	)
+
+	err = func() (err error) {
+		if r, err = svc.lookupRunnable(ctx, ID); err != nil {
+			return
+		}
+		aaProps.setReport(r)
+
+		model, err := svc.model(ctx, r.Sources, nil)
+		if err != nil {
+			return
+		}
+
+		out, err = svc.loadFrames(ctx, model, dd, false)
+		return
+	}()
+
+	return out, svc.recordAction(ctx, aaProps, ReportActionRun, err)
+}
+
+// Export runs the report and encodes all of the frames in the given format
+//
+// The paging of the frames is drained so the entire dataset is exported.
+// When no frame definitions are provided, the report's outermost steps are exported.
+// HTML and PDF documents are rendered with the given template; the frames are
+// provided to the template under the frames variable.
+func (svc *report) Export(ctx context.Context, ID uint64, format string, templateID uint64, dd rep.FrameDefinitionSet) (doc io.ReadSeeker, err error) {
+	var (
+		aaProps = &reportActionProps{report: &types.Report{ID: ID}}
+		r       *types.Report
+	)
+
+	err = func() (err error) {
+		if ReportExportContentType(format) == "" {
+			return ReportErrInvalidExportFormat()
+		}
+
+		if (format == ReportExportHTML || format == ReportExportPDF) && templateID == 0 {
+			return ReportErrExportTemplateRequired()
+		}
+
+		if r, err = svc.lookupRunnable(ctx, ID); err != nil {
+			return
+		}
+		aaProps.setReport(r)
+
+		model, err := svc.model(ctx, r.Sources, nil)
+		if err != nil {
+			return
+		}
+
+		if len(dd) == 0 {
+			dd = reportExportDefinitions(r.Sources.ModelSteps())
+		}
+
+		ff, err := svc.loadFrames(ctx, model, dd, true)
+		if err != nil {
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		switch format {
+		case ReportExportCSV:
+			err = rep.ExportCSV(buf, ff...)
+		case ReportExportXLSX:
+			err = rep.ExportXLSX(buf, ff...)
+		default:
+			doc, err = svc.renderer.Render(ctx, templateID, ReportExportContentType(format), reportTemplateVariables(r, ff), nil)
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		doc = bytes.NewReader(buf.Bytes())
+		return nil
+	}()
+
+	return doc, svc.recordAction(ctx, aaProps, ReportActionExport, err)
 }
 
 // actionlog?
@@ -332,73 +451,250 @@ func (svc *report) RunFresh(ctx context.Context, src types.ReportDataSourceSet,
 	var (
 		aaProps = &reportActionProps{}
 	)
-	out = make([]*rep.Frame, 0, 4)
 
 	err = func() (err error) {
 		// if err = svc.eventbus.WaitFor(ctx, event.ReportBeforeUpdate(upd, report)); err != nil {
 		// 	return
 		// }
 
-		ss := src.ModelSteps()
-		ss = append(ss, st...)
-
-		// Model the report
-		model, err := rep.Model(ctx, reporters, ss...)
+		model, err := svc.model(ctx, src, st)
 		if err != nil {
 			return
 		}
 
-		err = model.Run(ctx)
+		out, err = svc.loadFrames(ctx, model, dd, false)
+
+		// _ = svc.eventbus.WaitFor(ctx, event.ReportAfterUpdate(upd, report))
+		// return nil
+		return
+	}()
+
+	return out, svc.recordAction(ctx, aaProps, ReportActionRun, err)
+}
+
+// lookupRunnable returns the report if the current user can run it
+func (svc *report) lookupRunnable(ctx context.Context, ID uint64) (r *types.Report, err error) {
+	if ID == 0 {
+		return nil, ReportErrInvalidID()
+	}
+
+	if r, err = store.LookupReportByID(ctx, svc.store, ID); err != nil {
+		return nil, ReportErrInvalidID().Wrap(err)
+	}
+
+	if !svc.ac.CanRunReport(ctx, r) {
+		return nil, ReportErrNotAllowedToRun()
+	}
+
+	return r, nil
+}
+
+// model models and runs the report
+func (svc *report) model(ctx context.Context, src types.ReportDataSourceSet, st rep.StepDefinitionSet) (model rep.M, err error) {
+	ss := src.ModelSteps()
+	ss = append(ss, st...)
+
+	// Model the report
+	model, err = rep.Model(ctx, reporters, ss...)
+	if err != nil {
+		return
+	}
+
+	return model, model.Run(ctx)
+}
+
+// loadFrames loads the requested frames
+//
+// Frame definitions over the same joined datasource are loaded together.
+// When drain is set, all of the pages are loaded and merged.
+func (svc *report) loadFrames(ctx context.Context, model rep.M, dd rep.FrameDefinitionSet, drain bool) (out []*rep.Frame, err error) {
+	out = make([]*rep.Frame, 0, 4)
+
+	load := func(dd ...*rep.FrameDefinition) error {
+		var (
+			ff  []*rep.Frame
+			err error
+		)
+
+		if drain {
+			ff, err = svc.drainFrames(ctx, model, dd...)
+		} else {
+			ff, err = model.Load(ctx, dd...)
+		}
 		if err != nil {
-			return
+			return err
 		}
 
-		auxdd := make([]*rep.FrameDefinition, 0, len(dd))
-		for i, d := range dd {
-			// first one; nothing special needed
-			if i == 0 {
-				auxdd = append(auxdd, d)
-				continue
-			}
+		out = append(out, ff...)
+		return nil
+	}
+
+	auxdd := make([]*rep.FrameDefinition, 0, len(dd))
+	for i, d := range dd {
+		// first one; nothing special needed
+		if i == 0 {
+			auxdd = append(auxdd, d)
+			continue
+		}
+
+		stp := model.GetStep(d.Source)
+		if stp == nil {
+			return nil, fmt.Errorf("unknown source: %s", d.Source)
+		}
+
+		// if the current source matches the prev. source, and they both define references,
+		// they fall into the same chunk.
+		if stp.Def().Join != nil && (d.Source == dd[i-1].Source) && (d.Ref != "" && dd[i-1].Ref != "") && (d.Name == dd[i-1].Name) {
+			auxdd = append(auxdd, d)
+			continue
+		}
+
+		// if the current one doesn't fall into the current chunk, process
+		// the chunk and reset it
+		if err = load(auxdd...); err != nil {
+			return nil, err
+		}
+
+		auxdd = make([]*rep.FrameDefinition, 0, len(dd))
+		auxdd = append(auxdd, d)
+	}
+
+	if len(auxdd) > 0 {
+		if err = load(auxdd...); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// drainFrames loads all of the pages and merges the frames of the same origin
+//
+// Fails when frames hold more rows than allowed for the export
+func (svc *report) drainFrames(ctx context.Context, model rep.M, dd ...*rep.FrameDefinition) (out []*rep.Frame, err error) {
+	var (
+		ff    []*rep.Frame
+		index = make(map[string]*rep.Frame)
+		rows  int
+	)
+
+	aux := make([]*rep.FrameDefinition, len(dd))
+	for i, d := range dd {
+		aux[i] = d.Clone()
+		if aux[i].Paging == nil {
+			aux[i].Paging = &filter.Paging{}
+		}
+		if aux[i].Paging.Limit == 0 {
+			aux[i].Paging.Limit = reportExportPageSize
+		}
+	}
 
-			stp := model.GetStep(d.Source)
-			if stp == nil {
-				return fmt.Errorf("unknown source: %s", d.Source)
+	for {
+		if ff, err = model.Load(ctx, aux...); err != nil {
+			return
+		}
+
+		for _, f := range ff {
+			if rows += len(f.Rows); svc.exportMaxRows > 0 && rows > svc.exportMaxRows {
+				return nil, ReportErrExportTooLarge()
 			}
 
-			// if the current source matches the prev. source, and they both define references,
-			// they fall into the same chunk.
-			if stp.Def().Join != nil && (d.Source == dd[i-1].Source) && (d.Ref != "" && dd[i-1].Ref != "") && (d.Name == dd[i-1].Name) {
-				auxdd = append(auxdd, d)
+			k := fmt.Sprintf("%s/%s/%s/%s", f.Name, f.Ref, f.RelSource, f.RefValue)
+			if m, ok := index[k]; ok {
+				m.Rows = append(m.Rows, f.Rows...)
 				continue
 			}
 
-			// if the current one doesn't fall into the current chunk, process
-			// the chunk and reset it
-			ff, err := model.Load(ctx, auxdd...)
-			if err != nil {
-				return err
-			}
-			out = append(out, ff...)
+			index[k] = f
+			out = append(out, f)
+		}
 
-			auxdd = make([]*rep.FrameDefinition, 0, len(dd))
-			auxdd = append(auxdd, d)
+		// the first frame is the one that defines the paging
+		if len(ff) == 0 || ff[0].Paging == nil || ff[0].Paging.NextPage == nil {
+			break
 		}
+		aux[0].Paging.PageCursor = ff[0].Paging.NextPage
+	}
 
-		if len(auxdd) > 0 {
-			ff, err := model.Load(ctx, auxdd...)
-			if err != nil {
-				return err
+	for _, f := range out {
+		f.Paging = nil
+	}
+
+	return
+}
+
+// reportExportDefinitions defines a frame for each of the steps no other step depends on
+func reportExportDefinitions(ss rep.StepDefinitionSet) (dd rep.FrameDefinitionSet) {
+	var (
+		names = make([]string, 0, len(ss))
+		used  = make(map[string]bool)
+	)
+
+	for _, s := range ss {
+		switch {
+		case s.Load != nil:
+			names = append(names, s.Load.Name)
+		case s.Join != nil:
+			names = append(names, s.Join.Name)
+			used[s.Join.LocalSource] = true
+			used[s.Join.ForeignSource] = true
+		case s.Group != nil:
+			names = append(names, s.Group.Name)
+			used[s.Group.Source] = true
+		case s.Transform != nil:
+			names = append(names, s.Transform.Name)
+			used[s.Transform.Source] = true
+		}
+	}
+
+	for _, n := range names {
+		if !used[n] {
+			dd = append(dd, &rep.FrameDefinition{Name: n, Source: n})
+		}
+	}
+
+	return
+}
+
+// reportTemplateVariables prepares the frames for the template renderer
+func reportTemplateVariables(r *types.Report, ff []*rep.Frame) map[string]interface{} {
+	frames := make([]map[string]interface{}, len(ff))
+	for i, f := range ff {
+		var (
+			cols = make([]map[string]interface{}, len(f.Columns))
+			rows = make([][]string, 0, f.Size())
+		)
+
+		for j, c := range f.Columns {
+			cols[j] = map[string]interface{}{
+				"name":  c.Name,
+				"label": c.Label,
+				"kind":  c.Kind,
 			}
-			out = append(out, ff...)
 		}
 
-		// _ = svc.eventbus.WaitFor(ctx, event.ReportAfterUpdate(upd, report))
-		// return nil
-		return nil
-	}()
+		_ = f.WalkRows(func(_ int, r rep.FrameRow) error {
+			rows = append(rows, rep.ExportRow(r))
+			return nil
+		})
 
-	return out, svc.recordAction(ctx, aaProps, ReportActionRun, err)
+		frames[i] = map[string]interface{}{
+			"name":      f.Name,
+			"title":     f.ExportTitle(),
+			"source":    f.Source,
+			"ref":       f.Ref,
+			"refValue":  f.RefValue,
+			"relColumn": f.RelColumn,
+			"relSource": f.RelSource,
+			"columns":   cols,
+			"rows":      rows,
+		}
+	}
+
+	return map[string]interface{}{
+		"report": r,
+		"frames": frames,
+	}
 }
 
 // toLabeledReports converts to []label.LabeledResource
@@ -383,6 +383,26 @@ func ReportActionRun(props ...*reportActionProps) *reportAction {
 	return a
 }
 
+// ReportActionExport returns "system:report.export" action
+//
+// This function is auto-generated.
+//
+func ReportActionExport(props ...*reportActionProps) *reportAction {
+	a := &reportAction{
+		timestamp: time.Now(),
+		resource:  "system:report",
+		action:    "export",
+		log:       "exported {{report}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -779,6 +799,108 @@ func ReportErrNotAllowedToRun(mm ...*reportActionProps) *errors.Error {
 	return e
 }
 
+// ReportErrInvalidExportFormat returns "system:report.invalidExportFormat" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ReportErrInvalidExportFormat(mm ...*reportActionProps) *errors.Error {
+	var p = &reportActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid export format", nil),
+
+		errors.Meta("type", "invalidExportFormat"),
+		errors.Meta("resource", "system:report"),
+
+		errors.Meta(reportPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "report.errors.invalidExportFormat"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ReportErrExportTemplateRequired returns "system:report.exportTemplateRequired" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ReportErrExportTemplateRequired(mm ...*reportActionProps) *errors.Error {
+	var p = &reportActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("template is required to export the report in this format", nil),
+
+		errors.Meta("type", "exportTemplateRequired"),
+		errors.Meta("resource", "system:report"),
+
+		errors.Meta(reportPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "report.errors.exportTemplateRequired"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ReportErrExportTooLarge returns "system:report.exportTooLarge" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ReportErrExportTooLarge(mm ...*reportActionProps) *errors.Error {
+	var p = &reportActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("report is too large to be exported", nil),
+
+		errors.Meta("type", "exportTooLarge"),
+		errors.Meta("resource", "system:report"),
+
+		errors.Meta(reportPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "report.errors.exportTooLarge"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
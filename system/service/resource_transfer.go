@@ -0,0 +1,466 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/directory"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/yaml"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/rbac"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	resourceTransfer struct {
+		actionlog actionlog.Recorder
+		store     store.Storer
+		ac        resourceTransferAccessController
+		envoy     ResourceTransferStore
+
+		rbac interface {
+			Reload(context.Context)
+		}
+	}
+
+	resourceTransferAccessController interface {
+		CanExportResources(context.Context) bool
+		CanImportResources(context.Context) bool
+	}
+
+	// ResourceTransferStore decodes resources from and encodes them into the store
+	//
+	// Implemented in pkg/envoy/store that covers resources of all components;
+	// set when services are initialized
+	ResourceTransferStore interface {
+		// Decode decodes resources, selected by resource strings
+		// (see envoy store DecodeFilter.FromResource)
+		Decode(ctx context.Context, s store.Storer, rr ...string) (resource.InterfaceSet, error)
+
+		// Changes checks if the resource already exists in the store and
+		// returns fields where the existing resource differs from the imported one
+		Changes(ctx context.Context, s store.Storer, r resource.Interface) (bool, []string, error)
+
+		// CanExport checks if the current user can export the resource
+		CanExport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error)
+
+		// CanImport checks if the current user can import the resource
+		CanImport(ctx context.Context, s store.Storer, r resource.Interface) (bool, error)
+
+		// Encoder returns store encoder for the imported resources
+		Encoder(s store.Storer, onExisting resource.MergeAlg) envoy.PrepareEncoder
+	}
+
+	resourceTransferAccessCheck func(ctx context.Context, s store.Storer, r resource.Interface) (bool, error)
+)
+
+const (
+	// ResourceTransferMaxArchiveSize limits size of the uploaded archive
+	ResourceTransferMaxArchiveSize = 32 << 20
+
+	// limits size and number of the files in the archive;
+	// zip reader fails when file holds more data than declared
+	resourceTransferMaxUncompressedSize = 128 << 20
+	resourceTransferMaxFiles            = 256
+
+	resourceTransferProviderSettingsPrefix = "auth.external.providers."
+)
+
+var (
+	// settings that are never exported
+	resourceTransferSensitiveSettings = map[string]bool{
+		"auth.external.saml.key":  true,
+		"auth.ldap.bind-password": true,
+	}
+
+	// external auth provider settings that are never exported
+	resourceTransferSensitiveProviderSettings = map[string]bool{
+		"secret": true,
+	}
+)
+
+// ResourceTransfer initializes export/import service
+//
+// Resources are exported to and imported from zip archives
+// with one YAML file per resource type
+func ResourceTransfer() *resourceTransfer {
+	svc := &resourceTransfer{
+		ac:        DefaultAccessControl,
+		actionlog: DefaultActionlog,
+		store:     DefaultStore,
+	}
+
+	if ac := rbac.Global(); ac != nil {
+		svc.rbac = ac
+	}
+
+	return svc
+}
+
+// SetTransferStore sets envoy store that resources are decoded from and encoded into
+func (svc *resourceTransfer) SetTransferStore(es ResourceTransferStore) {
+	svc.envoy = es
+}
+
+// Export writes zip archive with selected resources to w
+//
+// Resources are read directly from the store; besides the resources.export
+// operation, each of the resources must be readable by the current user.
+// Sensitive settings (secrets, keys, passwords) are never exported.
+func (svc *resourceTransfer) Export(ctx context.Context, w io.Writer, rr []string) (err error) {
+	var (
+		rtProps = &resourceTransferActionProps{resources: strings.Join(rr, ", ")}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanExportResources(ctx) {
+			return ResourceTransferErrNotAllowedToExport(rtProps)
+		}
+
+		if len(rr) == 0 {
+			return ResourceTransferErrNoResources(rtProps)
+		}
+
+		if svc.envoy == nil {
+			return errors.Internal("resource transfer store not set")
+		}
+
+		nn, err := svc.envoy.Decode(ctx, svc.store, rr...)
+		if err != nil {
+			return
+		}
+
+		if err = resourceTransferCheckAccess(ctx, svc.store, nn, svc.envoy.CanExport, rtProps, ResourceTransferErrNotAllowedToExportResource); err != nil {
+			return
+		}
+
+		if nn = resourceTransferOmitSensitive(nn); len(nn) == 0 {
+			// none of the resource strings matched anything
+			return ResourceTransferErrNoResources(rtProps)
+		}
+
+		ye := yaml.NewYamlEncoder(&yaml.EncoderConfig{
+			MappedOutput: true,
+		})
+
+		g, err := envoy.NewBuilder(ye).Build(ctx, nn...)
+		if err != nil {
+			return
+		}
+
+		if err = envoy.Encode(ctx, g, ye); err != nil {
+			return
+		}
+
+		zw := zip.NewWriter(w)
+		for _, s := range ye.Stream() {
+			var f io.Writer
+			if f, err = zw.Create(resourceTransferFilename(s.Resource)); err != nil {
+				return
+			}
+
+			if _, err = io.Copy(f, s.Source); err != nil {
+				return
+			}
+		}
+
+		return zw.Close()
+	}()
+
+	return svc.recordAction(ctx, rtProps, ResourceTransferActionExport, err)
+}
+
+// Import decodes resources from the zip archive and encodes them into the store
+//
+// Report lists what happens with each of the resources; when preview
+// is requested, report is returned without changing anything.
+//
+// Existing resources are skipped unless onExisting
+// (replace, mergeLeft, mergeRight) says otherwise.
+//
+// Current user must be allowed to create or update each of the resources,
+// grant permissions for imported RBAC rules and manage imported settings.
+func (svc *resourceTransfer) Import(ctx context.Context, name string, archive []byte, onExisting string, preview bool) (r *types.ResourceTransferReport, err error) {
+	var (
+		rtProps = &resourceTransferActionProps{archive: name, onExisting: onExisting}
+		action  = ResourceTransferActionImport
+
+		alg resource.MergeAlg
+		nn  resource.InterfaceSet
+	)
+
+	if preview {
+		action = ResourceTransferActionPreview
+	}
+
+	err = func() (err error) {
+		if !svc.ac.CanImportResources(ctx) {
+			return ResourceTransferErrNotAllowedToImport(rtProps)
+		}
+
+		if alg, err = resourceTransferMergeAlg(onExisting); err != nil {
+			return ResourceTransferErrInvalidOnExisting(rtProps)
+		}
+
+		if svc.envoy == nil {
+			return errors.Internal("resource transfer store not set")
+		}
+
+		if len(archive) > ResourceTransferMaxArchiveSize {
+			return ResourceTransferErrArchiveTooLarge(rtProps)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return ResourceTransferErrInvalidArchive(rtProps).Wrap(err)
+		}
+
+		if !resourceTransferArchiveWithinLimits(zr) {
+			return ResourceTransferErrArchiveTooLarge(rtProps)
+		}
+
+		if nn, err = directory.DecodeFS(ctx, zr, yaml.Decoder()); err != nil {
+			return ResourceTransferErrInvalidArchive(rtProps).Wrap(err)
+		}
+
+		if len(nn) == 0 {
+			return ResourceTransferErrInvalidArchive(rtProps)
+		}
+
+		r = &types.ResourceTransferReport{
+			OnExisting: onExisting,
+			Preview:    preview,
+		}
+
+		rtProps.setReport(r)
+
+		return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+			if err = resourceTransferCheckAccess(ctx, s, nn, svc.envoy.CanImport, rtProps, ResourceTransferErrNotAllowedToImportResource); err != nil {
+				return
+			}
+
+			if r.Items, err = resourceTransferItems(ctx, s, nn, alg, svc.envoy.Changes); err != nil || preview {
+				return
+			}
+
+			se := svc.envoy.Encoder(s, alg)
+
+			g, err := envoy.NewBuilder(se).Build(ctx, nn...)
+			if err != nil {
+				return
+			}
+
+			return envoy.Encode(ctx, g, se)
+		})
+	}()
+
+	if err == nil && !preview && svc.rbac != nil {
+		// imported RBAC rules are stored directly
+		svc.rbac.Reload(ctx)
+	}
+
+	return r, svc.recordAction(ctx, rtProps, action, err)
+}
+
+// resourceTransferArchiveWithinLimits checks number of files and
+// their total uncompressed size before anything is decoded
+func resourceTransferArchiveWithinLimits(zr *zip.Reader) bool {
+	var size uint64
+
+	if len(zr.File) > resourceTransferMaxFiles {
+		return false
+	}
+
+	for _, f := range zr.File {
+		size += f.UncompressedSize64
+		if size > resourceTransferMaxUncompressedSize {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resourceTransferCheckAccess returns the given error for the first resource current user can not access
+func resourceTransferCheckAccess(ctx context.Context, s store.Storer, nn resource.InterfaceSet, can resourceTransferAccessCheck, rtProps *resourceTransferActionProps, errFn func(...*resourceTransferActionProps) *errors.Error) error {
+	for _, n := range nn {
+		ok, err := can(ctx, s, n)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			ii := n.Identifiers().StringSlice()
+			sort.Strings(ii)
+
+			rtProps.resource = strings.TrimSpace(n.ResourceType() + " " + strings.Join(ii, ", "))
+			return errFn(rtProps)
+		}
+	}
+
+	return nil
+}
+
+// resourceTransferOmitSensitive removes settings with secrets, keys and passwords
+func resourceTransferOmitSensitive(nn resource.InterfaceSet) resource.InterfaceSet {
+	out := make(resource.InterfaceSet, 0, len(nn))
+
+	for _, n := range nn {
+		switch r := n.(type) {
+		case *resource.Setting:
+			if resourceTransferSensitiveSetting(r.Res.Name) {
+				continue
+			}
+
+		case *resource.Settings:
+			vv := make(types.SettingValueSet, 0, len(r.Res))
+			for _, v := range r.Res {
+				if !resourceTransferSensitiveSetting(v.Name) {
+					vv = append(vv, v)
+				}
+			}
+
+			if r.Res = vv; len(vv) == 0 {
+				continue
+			}
+		}
+
+		out = append(out, n)
+	}
+
+	return out
+}
+
+// resourceTransferSensitiveSetting checks if setting holds a secret
+//
+// External auth provider settings are checked by the last segment
+// as they are stored under auth.external.providers.<handle>.<key>
+func resourceTransferSensitiveSetting(name string) bool {
+	if strings.HasPrefix(name, resourceTransferProviderSettingsPrefix) {
+		return resourceTransferSensitiveProviderSettings[name[strings.LastIndex(name, ".")+1:]]
+	}
+
+	return resourceTransferSensitiveSettings[name]
+}
+
+// resourceTransferItems determines what import does with each of the resources
+//
+// Records, RBAC rules and settings are reported as sets
+func resourceTransferItems(ctx context.Context, s store.Storer, nn resource.InterfaceSet, alg resource.MergeAlg, lookup func(context.Context, store.Storer, resource.Interface) (bool, []string, error)) (ii types.ResourceTransferItemSet, err error) {
+	var (
+		rules    = &types.ResourceTransferItem{ResourceType: resource.RbacResourceType, Action: types.ResourceTransferApply}
+		settings = &types.ResourceTransferItem{ResourceType: resource.SettingsResourceType, Action: types.ResourceTransferApply}
+
+		exists  bool
+		changes []string
+	)
+
+	for _, n := range nn {
+		i := &types.ResourceTransferItem{
+			ResourceType: n.ResourceType(),
+			Identifiers:  n.Identifiers().StringSlice(),
+		}
+
+		sort.Strings(i.Identifiers)
+
+		switch r := n.(type) {
+		case *resource.RbacRule:
+			rules.Count++
+			continue
+
+		case *resource.Settings:
+			settings.Count += uint(len(r.Res))
+			continue
+
+		case *resource.Setting:
+			settings.Count++
+			continue
+
+		case *resource.ComposeRecord:
+			i.Action = types.ResourceTransferApply
+			err = r.Walker(func(*resource.ComposeRecordRaw) error {
+				i.Count++
+				return nil
+			})
+
+			if err != nil {
+				return
+			}
+
+			ii = append(ii, i)
+			continue
+		}
+
+		if exists, changes, err = lookup(ctx, s, n); err != nil {
+			return
+		}
+
+		i.Action = types.ResourceTransferCreate
+		if exists {
+			i.Action = resourceTransferExistingAction(n, alg)
+		}
+
+		if i.Action == types.ResourceTransferUpdate {
+			i.Changes = changes
+		}
+
+		ii = append(ii, i)
+	}
+
+	for _, i := range []*types.ResourceTransferItem{rules, settings} {
+		if i.Count > 0 {
+			ii = append(ii, i)
+		}
+	}
+
+	return
+}
+
+// resourceTransferExistingAction returns action for the existing resource
+//
+// Merge strategy from the resource's envoy config takes precedence
+func resourceTransferExistingAction(n resource.Interface, alg resource.MergeAlg) types.ResourceTransferAction {
+	if c, ok := n.(interface{ Config() *resource.EnvoyConfig }); ok {
+		if cfg := c.Config(); cfg != nil && cfg.OnExisting != resource.Default {
+			alg = cfg.OnExisting
+		}
+	}
+
+	switch alg {
+	case resource.Replace, resource.MergeLeft, resource.MergeRight:
+		return types.ResourceTransferUpdate
+	}
+
+	return types.ResourceTransferSkip
+}
+
+// resourceTransferMergeAlg converts merge strategy name
+func resourceTransferMergeAlg(s string) (resource.MergeAlg, error) {
+	switch strings.ToLower(s) {
+	case "", "skip":
+		return resource.Skip, nil
+	case "replace":
+		return resource.Replace, nil
+	case "mergeleft":
+		return resource.MergeLeft, nil
+	case "mergeright":
+		return resource.MergeRight, nil
+	}
+
+	return resource.Default, ResourceTransferErrInvalidOnExisting()
+}
+
+// resourceTransferFilename converts resource type to the name of the archived file
+//
+// Same naming as used by the export command
+func resourceTransferFilename(res string) string {
+	return strings.Join(strings.Split(strings.Trim(res, ":"), ":"), "_") + ".yaml"
+}
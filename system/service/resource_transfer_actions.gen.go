@@ -0,0 +1,638 @@
+package service
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// system/service/resource_transfer_actions.yaml
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/locale"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"strings"
+	"time"
+)
+
+type (
+	resourceTransferActionProps struct {
+		resources  string
+		resource   string
+		archive    string
+		onExisting string
+		report     *types.ResourceTransferReport
+	}
+
+	resourceTransferAction struct {
+		timestamp time.Time
+		resource  string
+		action    string
+		log       string
+		severity  actionlog.Severity
+
+		// prefix for error when action fails
+		errorMessage string
+
+		props *resourceTransferActionProps
+	}
+
+	resourceTransferLogMetaKey   struct{}
+	resourceTransferPropsMetaKey struct{}
+)
+
+var (
+	// just a placeholder to cover template cases w/o fmt package use
+	_ = fmt.Println
+)
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Props methods
+// setResources updates resourceTransferActionProps's resources
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *resourceTransferActionProps) setResources(resources string) *resourceTransferActionProps {
+	p.resources = resources
+	return p
+}
+
+// setResource updates resourceTransferActionProps's resource
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *resourceTransferActionProps) setResource(resource string) *resourceTransferActionProps {
+	p.resource = resource
+	return p
+}
+
+// setArchive updates resourceTransferActionProps's archive
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *resourceTransferActionProps) setArchive(archive string) *resourceTransferActionProps {
+	p.archive = archive
+	return p
+}
+
+// setOnExisting updates resourceTransferActionProps's onExisting
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *resourceTransferActionProps) setOnExisting(onExisting string) *resourceTransferActionProps {
+	p.onExisting = onExisting
+	return p
+}
+
+// setReport updates resourceTransferActionProps's report
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *resourceTransferActionProps) setReport(report *types.ResourceTransferReport) *resourceTransferActionProps {
+	p.report = report
+	return p
+}
+
+// Serialize converts resourceTransferActionProps to actionlog.Meta
+//
+// This function is auto-generated.
+//
+func (p resourceTransferActionProps) Serialize() actionlog.Meta {
+	var (
+		m = make(actionlog.Meta)
+	)
+
+	m.Set("resources", p.resources, true)
+	m.Set("resource", p.resource, true)
+	m.Set("archive", p.archive, true)
+	m.Set("onExisting", p.onExisting, true)
+	if p.report != nil {
+		m.Set("report.onExisting", p.report.OnExisting, true)
+		m.Set("report.preview", p.report.Preview, true)
+	}
+
+	return m
+}
+
+// tr translates string and replaces meta value placeholder with values
+//
+// This function is auto-generated.
+//
+func (p resourceTransferActionProps) Format(in string, err error) string {
+	var (
+		pairs = []string{"{{err}}"}
+		// first non-empty string
+		fns = func(ii ...interface{}) string {
+			for _, i := range ii {
+				if s := fmt.Sprintf("%v", i); len(s) > 0 {
+					return s
+				}
+			}
+
+			return ""
+		}
+	)
+
+	if err != nil {
+		pairs = append(pairs, err.Error())
+	} else {
+		pairs = append(pairs, "nil")
+	}
+	pairs = append(pairs, "{{resources}}", fns(p.resources))
+	pairs = append(pairs, "{{resource}}", fns(p.resource))
+	pairs = append(pairs, "{{archive}}", fns(p.archive))
+	pairs = append(pairs, "{{onExisting}}", fns(p.onExisting))
+
+	if p.report != nil {
+		// replacement for "{{report}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{report}}",
+			fns(
+				p.report.OnExisting,
+				p.report.Preview,
+			),
+		)
+		pairs = append(pairs, "{{report.onExisting}}", fns(p.report.OnExisting))
+		pairs = append(pairs, "{{report.preview}}", fns(p.report.Preview))
+	}
+	return strings.NewReplacer(pairs...).Replace(in)
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action methods
+
+// String returns loggable description as string
+//
+// This function is auto-generated.
+//
+func (a *resourceTransferAction) String() string {
+	var props = &resourceTransferActionProps{}
+
+	if a.props != nil {
+		props = a.props
+	}
+
+	return props.Format(a.log, nil)
+}
+
+func (e *resourceTransferAction) ToAction() *actionlog.Action {
+	return &actionlog.Action{
+		Resource:    e.resource,
+		Action:      e.action,
+		Severity:    e.severity,
+		Description: e.String(),
+		Meta:        e.props.Serialize(),
+	}
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action constructors
+
+// ResourceTransferActionExport returns "system:resource-transfer.export" action
+//
+// This function is auto-generated.
+//
+func ResourceTransferActionExport(props ...*resourceTransferActionProps) *resourceTransferAction {
+	a := &resourceTransferAction{
+		timestamp: time.Now(),
+		resource:  "system:resource-transfer",
+		action:    "export",
+		log:       "exported resources ({{resources}})",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ResourceTransferActionPreview returns "system:resource-transfer.preview" action
+//
+// This function is auto-generated.
+//
+func ResourceTransferActionPreview(props ...*resourceTransferActionProps) *resourceTransferAction {
+	a := &resourceTransferAction{
+		timestamp: time.Now(),
+		resource:  "system:resource-transfer",
+		action:    "preview",
+		log:       "previewed import of {{archive}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ResourceTransferActionImport returns "system:resource-transfer.import" action
+//
+// This function is auto-generated.
+//
+func ResourceTransferActionImport(props ...*resourceTransferActionProps) *resourceTransferAction {
+	a := &resourceTransferAction{
+		timestamp: time.Now(),
+		resource:  "system:resource-transfer",
+		action:    "import",
+		log:       "imported resources from {{archive}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Error constructors
+
+// ResourceTransferErrGeneric returns "system:resource-transfer.generic" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrGeneric(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("failed to complete request due to internal error", nil),
+
+		errors.Meta("type", "generic"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "{err}"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.generic"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrNoResources returns "system:resource-transfer.noResources" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrNoResources(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("no resources selected for export", nil),
+
+		errors.Meta("type", "noResources"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.noResources"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrInvalidArchive returns "system:resource-transfer.invalidArchive" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrInvalidArchive(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid resource archive", nil),
+
+		errors.Meta("type", "invalidArchive"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "failed to decode resource archive {{archive}}"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.invalidArchive"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrArchiveTooLarge returns "system:resource-transfer.archiveTooLarge" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrArchiveTooLarge(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("resource archive exceeds size limits", nil),
+
+		errors.Meta("type", "archiveTooLarge"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "resource archive {{archive}} exceeds size limits"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.archiveTooLarge"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrInvalidOnExisting returns "system:resource-transfer.invalidOnExisting" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrInvalidOnExisting(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid merge strategy", nil),
+
+		errors.Meta("type", "invalidOnExisting"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "used invalid merge strategy ({{onExisting}})"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.invalidOnExisting"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrNotAllowedToExport returns "system:resource-transfer.notAllowedToExport" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrNotAllowedToExport(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to export resources", nil),
+
+		errors.Meta("type", "notAllowedToExport"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "failed to export resources; insufficient permissions"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.notAllowedToExport"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrNotAllowedToImport returns "system:resource-transfer.notAllowedToImport" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrNotAllowedToImport(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to import resources", nil),
+
+		errors.Meta("type", "notAllowedToImport"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "failed to import {{archive}}; insufficient permissions"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.notAllowedToImport"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrNotAllowedToExportResource returns "system:resource-transfer.notAllowedToExportResource" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrNotAllowedToExportResource(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to export {{resource}}", nil),
+
+		errors.Meta("type", "notAllowedToExportResource"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "failed to export {{resource}}; insufficient permissions"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.notAllowedToExportResource"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ResourceTransferErrNotAllowedToImportResource returns "system:resource-transfer.notAllowedToImportResource" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ResourceTransferErrNotAllowedToImportResource(mm ...*resourceTransferActionProps) *errors.Error {
+	var p = &resourceTransferActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to import {{resource}}", nil),
+
+		errors.Meta("type", "notAllowedToImportResource"),
+		errors.Meta("resource", "system:resource-transfer"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(resourceTransferLogMetaKey{}, "failed to import {{resource}} from {{archive}}; insufficient permissions"),
+		errors.Meta(resourceTransferPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "resourceTransfer.errors.notAllowedToImportResource"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+
+// recordAction is a service helper function wraps function that can return error
+//
+// It will wrap unrecognized/internal errors with generic errors.
+//
+// This function is auto-generated.
+//
+func (svc resourceTransfer) recordAction(ctx context.Context, props *resourceTransferActionProps, actionFn func(...*resourceTransferActionProps) *resourceTransferAction, err error) error {
+	if svc.actionlog == nil || actionFn == nil {
+		// action log disabled or no action fn passed, return error as-is
+		return err
+	} else if err == nil {
+		// action completed w/o error, record it
+		svc.actionlog.Record(ctx, actionFn(props).ToAction())
+		return nil
+	}
+
+	a := actionFn(props).ToAction()
+
+	// Extracting error information and recording it as action
+	a.Error = err.Error()
+
+	switch c := err.(type) {
+	case *errors.Error:
+		m := c.Meta()
+
+		a.Error = err.Error()
+		a.Severity = actionlog.Severity(m.AsInt("severity"))
+		a.Description = props.Format(m.AsString(resourceTransferLogMetaKey{}), err)
+
+		if p, has := m[resourceTransferPropsMetaKey{}]; has {
+			a.Meta = p.(*resourceTransferActionProps).Serialize()
+		}
+
+		svc.actionlog.Record(ctx, a)
+	default:
+		svc.actionlog.Record(ctx, a)
+	}
+
+	// Original error is passed on
+	return err
+}
@@ -173,6 +173,14 @@ func (svc role) Find(ctx context.Context, filter types.RoleFilter) (rr types.Rol
 			return err
 		}
 
+		if filter.IncTotal {
+			// roles are counted in the store,
+			// check function is not applied
+			if f.Total, err = store.CountRoles(ctx, svc.store, filter); err != nil {
+				return err
+			}
+		}
+
 		if err = label.Load(ctx, svc.store, toLabeledRoles(rr)...); err != nil {
 			return err
 		}
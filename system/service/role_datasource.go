@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+
+	rep "github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/spf13/cast"
+)
+
+type (
+	// roleMember provides role memberships to the report
+	roleMember struct {
+		role *role
+	}
+)
+
+// Datasource provides system roles to the report
+//
+// Definition supports deleted & archived filter states.
+func (svc role) Datasource(ctx context.Context, ld *rep.LoadStepDefinition) (rep.Datasource, error) {
+	var (
+		err error
+		f   = types.RoleFilter{}
+		def = ld.Definition
+	)
+
+	if f.Deleted, err = datasourceFilterState(def, "deleted"); err != nil {
+		return nil, err
+	}
+	if f.Archived, err = datasourceFilterState(def, "archived"); err != nil {
+		return nil, err
+	}
+
+	cols := rep.FrameColumnSet{
+		datasourceIDColumn("ID", "id", "Role ID"),
+		datasourceColumn("String", "handle", "Handle"),
+		datasourceColumn("String", "name", "Name"),
+		datasourceColumn("DateTime", "createdAt", "Created at"),
+		datasourceColumn("DateTime", "updatedAt", "Updated at"),
+		datasourceColumn("DateTime", "archivedAt", "Archived at"),
+		datasourceColumn("DateTime", "deletedAt", "Deleted at"),
+	}
+
+	return rep.MemoryDatasource(ld, cols, func(ctx context.Context) ([]map[string]interface{}, error) {
+		rr, _, err := svc.Find(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]map[string]interface{}, len(rr))
+		for i, r := range rr {
+			out[i] = map[string]interface{}{
+				"id":         r.ID,
+				"handle":     r.Handle,
+				"name":       r.Name,
+				"createdAt":  r.CreatedAt,
+				"updatedAt":  r.UpdatedAt,
+				"archivedAt": r.ArchivedAt,
+				"deletedAt":  r.DeletedAt,
+			}
+		}
+
+		return out, nil
+	})
+}
+
+// RoleMember is a report datasource provider for role memberships
+func RoleMember(r *role) *roleMember {
+	return &roleMember{role: r}
+}
+
+// Datasource provides memberships of the readable roles to the report
+//
+// Definition supports filtering by roleID and userID.
+func (svc roleMember) Datasource(ctx context.Context, ld *rep.LoadStepDefinition) (rep.Datasource, error) {
+	var (
+		err error
+		f   = types.RoleMemberFilter{}
+		def = ld.Definition
+	)
+
+	if v, ok := def["roleID"]; ok {
+		if f.RoleID, err = cast.ToUint64E(v); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := def["userID"]; ok {
+		if f.UserID, err = cast.ToUint64E(v); err != nil {
+			return nil, err
+		}
+	}
+
+	cols := rep.FrameColumnSet{
+		datasourceColumn("ID", "roleID", "Role ID"),
+		datasourceColumn("User", "userID", "User ID"),
+	}
+
+	return rep.MemoryDatasource(ld, cols, func(ctx context.Context) ([]map[string]interface{}, error) {
+		// only memberships of the roles the user can read are provided
+		rr, _, err := svc.role.Find(ctx, types.RoleFilter{})
+		if err != nil {
+			return nil, err
+		}
+
+		readable := make(map[uint64]bool, len(rr))
+		for _, r := range rr {
+			readable[r.ID] = true
+		}
+
+		mm, _, err := store.SearchRoleMembers(ctx, svc.role.store, f)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]map[string]interface{}, 0, len(mm))
+		for _, m := range mm {
+			if !readable[m.RoleID] {
+				continue
+			}
+
+			out = append(out, map[string]interface{}{
+				"roleID": m.RoleID,
+				"userID": m.UserID,
+			})
+		}
+
+		return out, nil
+	})
+}
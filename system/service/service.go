@@ -15,6 +15,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/objstore/minio"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/pwned"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/automation"
@@ -28,11 +29,14 @@ type (
 	}
 
 	Config struct {
-		ActionLog options.ActionLogOpt
-		Storage   options.ObjectStoreOpt
-		Template  options.TemplateOpt
-		Auth      options.AuthOpt
-		RBAC      options.RBACOpt
+		ActionLog   options.ActionLogOpt
+		Storage     options.ObjectStoreOpt
+		Template    options.TemplateOpt
+		Auth        options.AuthOpt
+		RBAC        options.RBACOpt
+		Apigw       options.ApigwOpt
+		Environment options.EnvironmentOpt
+		Report      options.ReportOpt
 	}
 
 	eventDispatcher interface {
@@ -77,10 +81,16 @@ var (
 	DefaultQueue       *queue
 	DefaultApigwRoute  *apigwRoute
 	DefaultApigwFilter *apigwFilter
+	DefaultApigwSecret *apigwSecret
 	DefaultReport      *report
 
+	DefaultResourceTransfer *resourceTransfer
+
 	DefaultStatistics *statistics
 
+	// list of breached passwords, loaded when configured
+	breachedPasswords breachedPasswordsChecker
+
 	// wrapper around time.Now() that will aid service testing
 	now = func() *time.Time {
 		c := time.Now().Round(time.Second)
@@ -160,9 +170,18 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 	hcd.Add(objstore.Healthcheck(DefaultObjectStore), "ObjectStore/System")
 
 	DefaultRenderer = Renderer(c.Template)
-	DefaultReport = Report(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service())
+	DefaultReport = Report(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service(), DefaultRenderer, c.Report)
 	DefaultAuthNotification = AuthNotification(CurrentSettings, DefaultRenderer, c.Auth)
+	if c.Auth.BreachedPasswordsFile != "" {
+		if breachedPasswords, err = pwned.Load(c.Auth.BreachedPasswordsFile); err != nil {
+			return err
+		}
+
+		log.Info("breached passwords list loaded", zap.String("path", c.Auth.BreachedPasswordsFile))
+	}
+
 	DefaultAuth = Auth()
+	DefaultAuth.ldapAllowInsecure = c.Environment.IsDevelopment()
 	DefaultAuthClient = AuthClient(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service(), c.Auth)
 	DefaultUser = User()
 	DefaultRole = Role()
@@ -174,6 +193,13 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 	DefaultQueue = Queue()
 	DefaultApigwRoute = Route()
 	DefaultApigwFilter = Filter()
+	DefaultApigwSecret = ApigwSecret(c.Apigw)
+	DefaultResourceTransfer = ResourceTransfer()
+
+	DefaultReport.RegisterReporter("systemUsers", DefaultUser)
+	DefaultReport.RegisterReporter("systemRoles", DefaultRole)
+	DefaultReport.RegisterReporter("systemRoleMembers", RoleMember(DefaultRole))
+	DefaultReport.RegisterReporter("actionlog", ActionlogDatasource(DefaultAccessControl, DefaultActionlog))
 
 	if err = initRoles(ctx, log.Named("rbac.roles"), c.RBAC, eventbus.Service(), rbac.Global()); err != nil {
 		return err
@@ -201,6 +227,12 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 		DefaultRenderer,
 	)
 
+	automation.ReportsHandler(
+		automationService.Registry(),
+		DefaultReport,
+		DefaultRenderer,
+	)
+
 	automation.RolesHandler(
 		automationService.Registry(),
 		DefaultRole,
@@ -229,6 +261,7 @@ func Activate(ctx context.Context) (err error) {
 
 func Watchers(ctx context.Context) {
 	DefaultReminder.Watch(ctx)
+	DefaultAuth.WatchLDAPSync(ctx)
 	return
 }
 
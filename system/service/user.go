@@ -48,6 +48,7 @@ type (
 		CheckPasswordStrength(string) bool
 		SetPasswordCredentials(context.Context, uint64, string) error
 		RemovePasswordCredentials(context.Context, uint64) error
+		ResetFailedLogins(context.Context, uint64) error
 	}
 
 	userAccessController interface {
@@ -80,6 +81,7 @@ type (
 		Delete(ctx context.Context, id uint64) error
 		Suspend(ctx context.Context, id uint64) error
 		Unsuspend(ctx context.Context, id uint64) error
+		Unlock(ctx context.Context, id uint64) error
 		Undelete(ctx context.Context, id uint64) error
 
 		SetPassword(ctx context.Context, userID uint64, password string) error
@@ -327,6 +329,14 @@ func (svc user) Find(ctx context.Context, filter types.UserFilter) (uu types.Use
 			return err
 		}
 
+		if filter.IncTotal {
+			// users are counted in the store,
+			// check function is not applied
+			if f.Total, err = store.CountUsers(ctx, svc.store, filter); err != nil {
+				return err
+			}
+		}
+
 		if err = label.Load(ctx, svc.store, toLabeledUsers(uu)...); err != nil {
 			return err
 		}
@@ -673,6 +683,40 @@ func (svc user) Unsuspend(ctx context.Context, userID uint64) (err error) {
 	return svc.recordAction(ctx, uaProps, UserActionUnsuspend, err)
 }
 
+// Unlock removes lock (and failed login attempts) from the user's account
+//
+// Expecting unlocker to have permissions to update users
+func (svc user) Unlock(ctx context.Context, userID uint64) (err error) {
+	var (
+		u       *types.User
+		uaProps = &userActionProps{user: &types.User{ID: userID}}
+	)
+
+	err = func() (err error) {
+		if userID == 0 {
+			return UserErrInvalidID()
+		}
+
+		if u, err = store.LookupUserByID(ctx, svc.store, userID); err != nil {
+			return
+		}
+
+		uaProps.setUser(u)
+
+		if u.Kind == types.SystemUser {
+			return UserErrNotAllowedToUpdateSystem()
+		}
+
+		if !svc.ac.CanUpdateUser(ctx, u) {
+			return UserErrNotAllowedToUpdate()
+		}
+
+		return svc.auth.ResetFailedLogins(ctx, userID)
+	}()
+
+	return svc.recordAction(ctx, uaProps, UserActionUnlock, err)
+}
+
 // SetPassword sets new password for a user
 //
 // Expecting setter to have permissions to update users
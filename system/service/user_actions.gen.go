@@ -487,6 +487,26 @@ func UserActionUnsuspend(props ...*userActionProps) *userAction {
 	return a
 }
 
+// UserActionUnlock returns "system:user.unlock" action
+//
+// This function is auto-generated.
+//
+func UserActionUnlock(props ...*userActionProps) *userAction {
+	a := &userAction{
+		timestamp: time.Now(),
+		resource:  "system:user",
+		action:    "unlock",
+		log:       "unlocked {{user}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // UserActionSetPassword returns "system:user.setPassword" action
 //
 // This function is auto-generated.
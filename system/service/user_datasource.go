@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	rep "github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/spf13/cast"
+)
+
+// Datasource provides system users to the report
+//
+// Definition supports deleted & suspended filter states and user kind.
+func (svc user) Datasource(ctx context.Context, ld *rep.LoadStepDefinition) (rep.Datasource, error) {
+	var (
+		err error
+		f   = types.UserFilter{}
+		def = ld.Definition
+	)
+
+	if f.Deleted, err = datasourceFilterState(def, "deleted"); err != nil {
+		return nil, err
+	}
+	if f.Suspended, err = datasourceFilterState(def, "suspended"); err != nil {
+		return nil, err
+	}
+	if k, ok := def["kind"]; ok {
+		f.Kind = types.UserKind(cast.ToString(k))
+	}
+
+	cols := rep.FrameColumnSet{
+		datasourceIDColumn("User", "id", "User ID"),
+		datasourceColumn("String", "handle", "Handle"),
+		datasourceColumn("String", "username", "Username"),
+		datasourceColumn("String", "email", "Email"),
+		datasourceColumn("String", "name", "Name"),
+		datasourceColumn("String", "kind", "Kind"),
+		datasourceColumn("Checkbox", "emailConfirmed", "Email confirmed"),
+		datasourceColumn("DateTime", "createdAt", "Created at"),
+		datasourceColumn("DateTime", "updatedAt", "Updated at"),
+		datasourceColumn("DateTime", "suspendedAt", "Suspended at"),
+		datasourceColumn("DateTime", "deletedAt", "Deleted at"),
+	}
+
+	return rep.MemoryDatasource(ld, cols, func(ctx context.Context) ([]map[string]interface{}, error) {
+		uu, _, err := svc.Find(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		rr := make([]map[string]interface{}, len(uu))
+		for i, u := range uu {
+			rr[i] = map[string]interface{}{
+				"id":             u.ID,
+				"handle":         u.Handle,
+				"username":       u.Username,
+				"email":          u.Email,
+				"name":           u.Name,
+				"kind":           string(u.Kind),
+				"emailConfirmed": u.EmailConfirmed,
+				"createdAt":      u.CreatedAt,
+				"updatedAt":      u.UpdatedAt,
+				"suspendedAt":    u.SuspendedAt,
+				"deletedAt":      u.DeletedAt,
+			}
+		}
+
+		return rr, nil
+	})
+}
+
+// datasourceFilterState returns the filter state from the datasource definition
+func datasourceFilterState(def map[string]interface{}, key string) (filter.State, error) {
+	v, ok := def[key]
+	if !ok {
+		return filter.StateExcluded, nil
+	}
+
+	s, err := cast.ToUintE(v)
+	return filter.State(s), err
+}
+
+func datasourceColumn(kind, name, label string) *rep.FrameColumn {
+	c := rep.MakeColumnOfKind(kind)
+	c.Name = name
+	c.Label = label
+	return c
+}
+
+func datasourceIDColumn(kind, name, label string) *rep.FrameColumn {
+	c := datasourceColumn(kind, name, label)
+	c.System = true
+	c.Primary = true
+	c.Unique = true
+	return c
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// ApigwSecret holds credentials used by API Gateway filters
+	//
+	// Filters reference secrets by handle so the value can be
+	// rotated without changing the routes that use it.
+	ApigwSecret struct {
+		ID     uint64 `json:"secretID,string"`
+		Handle string `json:"handle"`
+
+		// Value is encrypted with the API Gateway secret key
+		// and never leaves the server
+		Value string `json:"-"`
+
+		CreatedAt time.Time  `json:"createdAt,omitempty"`
+		CreatedBy uint64     `json:"createdBy,string" `
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+		UpdatedBy uint64     `json:"updatedBy,string,omitempty" `
+		DeletedAt *time.Time `json:"deletedAt,omitempty"`
+		DeletedBy uint64     `json:"deletedBy,string,omitempty" `
+	}
+
+	ApigwSecretFilter struct {
+		SecretID []uint64 `json:"secretID"`
+		Handle   string   `json:"handle"`
+
+		Deleted filter.State `json:"deleted"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*ApigwSecret) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+)
@@ -64,6 +64,42 @@ type (
 				// Otherwise we offer the user to choose among the enabled external providers
 				// If only one ext. provider is enabled, user is automatically redirected there
 				SplitCredentialsCheck bool `kv:"split-credentials-check"`
+
+				// Password constraints enforced when password is set or changed
+				PasswordConstraints struct {
+					// Minimal password length, never less than 5
+					MinLength uint `kv:"min-length"`
+
+					// Minimal number of characters of each class
+					MinUpperCase uint `kv:"min-upper-case"`
+					MinLowerCase uint `kv:"min-lower-case"`
+					MinNumCount  uint `kv:"min-num-count"`
+					MinSpecial   uint `kv:"min-special"`
+
+					// Number of days after password expires and needs to be changed
+					// on the next login, 0 disables expiration
+					MaxAge uint `kv:"max-age"`
+
+					// Number of previous passwords that can not be reused
+					History uint `kv:"history"`
+				} `kv:"password-constraints"`
+
+				// Protection against brute force attacks on password login
+				//
+				// Every failed attempt adds a progressive delay before the next one is allowed;
+				// after too many failed attempts account (or IP address) is temporarily locked
+				Lockout struct {
+					Enabled bool `kv:"enabled"`
+
+					// Failed attempts on an account before it is locked, defaults to 5
+					MaxAttempts uint `kv:"max-attempts"`
+
+					// Failed attempts from an IP address before it is locked, defaults to 20
+					MaxAttemptsPerIP uint `kv:"max-attempts-per-ip"`
+
+					// Lockout duration in minutes, defaults to 15
+					Duration uint `kv:"duration"`
+				} `kv:"lockout"`
 			}
 
 			External struct {
@@ -89,13 +125,98 @@ type (
 						IdentName       string `kv:"ident-name"`
 						IdentHandle     string `kv:"ident-handle"`
 						IdentIdentifier string `kv:"ident-identifier"`
+
+						// Assertion attribute with values that are mapped to roles
+						RoleAttribute string `kv:"role-attribute"`
+
+						// Memberships of mapped roles are updated on every login
+						RoleMapping []SamlRoleMapping `kv:"role-mapping"`
 					} `kv:"idp"`
+
+					// Additional identity providers
+					IDPs []SamlIDP `kv:"idps"`
 				}
 
 				// all external providers we know
 				Providers ExternalAuthProviderSet
 			}
 
+			// LDAP (Active Directory) authentication and directory sync
+			LDAP struct {
+				// Can users login with LDAP credentials
+				Enabled bool
+
+				// Server URL, ldap://host:389 or ldaps://host:636
+				URL string `kv:"url"`
+
+				// Upgrade ldap:// connection with StartTLS
+				StartTLS bool `kv:"start-tls"`
+
+				// Skip verification of server's certificate
+				// Honored only in development environment (ENVIRONMENT=dev...)
+				InsecureSkipVerify bool `kv:"insecure-skip-verify"`
+
+				// Service account used for user lookups and directory sync
+				BindDN       string `kv:"bind-dn"`
+				BindPassword string `kv:"bind-password"`
+
+				// Base DN for user searches
+				BaseDN string `kv:"base-dn"`
+
+				// Filter for user lookup on login; {username} is replaced with the (escaped) username
+				// defaults to (&(objectClass=person)(|(uid={username})(sAMAccountName={username})(mail={username})))
+				UserFilter string `kv:"user-filter"`
+
+				// Link LDAP entries to existing users (matched by email) that already
+				// have other credentials (password, external providers)
+				//
+				// Existing users without any credentials are always linked
+				LinkExistingUsers bool `kv:"link-existing-users"`
+
+				// LDAP attributes mapped to user's properties
+				Attributes struct {
+					// Attribute with unique & stable user identifier, defaults to DN
+					ID string `kv:"id"`
+
+					// defaults to mail
+					Email string `kv:"email"`
+
+					// defaults to displayName
+					Name string `kv:"name"`
+
+					// defaults to uid
+					Handle string `kv:"handle"`
+				}
+
+				Groups struct {
+					// Base DN for group searches, defaults to base DN
+					BaseDN string `kv:"base-dn"`
+
+					// Filter for groups of the user; {dn} and {username} are replaced with user's DN and username
+					// defaults to (|(member={dn})(uniqueMember={dn})(memberUid={username}))
+					Filter string
+
+					// LDAP groups (DN or CN) mapped to role handles
+					//
+					// Only memberships of mapped roles are managed
+					Mapping []LDAPGroupMapping
+				}
+
+				// Periodic synchronization of users and role memberships
+				Sync struct {
+					Enabled bool
+
+					// Interval in minutes, defaults to 60
+					Interval uint
+
+					// Filter for users that are synchronized, defaults to (objectClass=person)
+					Filter string
+
+					// Suspend users that are no longer in the directory
+					SuspendMissing bool `kv:"suspend-missing"`
+				}
+			} `kv:"ldap"`
+
 			MultiFactor struct {
 				EmailOTP struct {
 					// Can users use email for MFA
@@ -123,8 +244,34 @@ type (
 					// TOTP issuer, defaults to "Corteza"
 					Issuer string
 				} `kv:"totp"`
+
+				WebAuthn struct {
+					// Can users use security keys (WebAuthn) for MFA
+					Enabled bool
+
+					// Is MFA with security keys enforced?
+					Enforced bool
+
+					// Can users login with security key without password
+					Passwordless bool
+
+					// Relying party ID, defaults to the host of the auth base URL
+					RelyingPartyID string `kv:"rp-id"`
+
+					// Relying party name, defaults to "Corteza"
+					RelyingPartyName string `kv:"rp-name"`
+				} `kv:"webauthn"`
 			} `kv:"multi-factor"`
 
+			// Long-lived API tokens that users can create for their scripts
+			PersonalAccessTokens struct {
+				// Can users create personal access tokens
+				Enabled bool
+
+				// Max number of days until token expires, 0 allows tokens without expiration
+				MaxLifetime uint `kv:"max-lifetime"`
+			} `kv:"personal-access-tokens"`
+
 			Mail struct {
 				FromAddress string `kv:"from-address"`
 				FromName    string `kv:"from-name"`
@@ -0,0 +1,12 @@
+package types
+
+type (
+	// LDAPGroupMapping maps LDAP group to role
+	LDAPGroupMapping struct {
+		// Group DN or CN
+		Group string `json:"group"`
+
+		// Role handle
+		Role string `json:"role"`
+	}
+)
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// PersonalAccessToken is user's long-lived API token
+	//
+	// Tokens are stored (hashed) as user's credentials,
+	// this is just a convenient representation of them
+	PersonalAccessToken struct {
+		ID         uint64     `json:"tokenID,string"`
+		OwnerID    uint64     `json:"ownerID,string"`
+		Name       string     `json:"name"`
+		Scope      []string   `json:"scope"`
+		LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+		ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+		CreatedAt  time.Time  `json:"createdAt"`
+	}
+)
+
+// Expired returns true when token expiration date is in the past
+func (t PersonalAccessToken) Expired() bool {
+	return t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now())
+}
@@ -0,0 +1,52 @@
+package types
+
+type (
+	// ResourceTransferReport describes what import of the resource archive
+	// does (or would do in case of a preview) with each of the resources
+	ResourceTransferReport struct {
+		OnExisting string `json:"onExisting"`
+		Preview    bool   `json:"preview"`
+
+		Items ResourceTransferItemSet `json:"items"`
+	}
+
+	ResourceTransferItem struct {
+		ResourceType string                 `json:"resourceType"`
+		Identifiers  []string               `json:"identifiers"`
+		Action       ResourceTransferAction `json:"action"`
+
+		// Number of resources when item represents a set
+		// (records of a module, RBAC rules, settings)
+		Count uint `json:"count,omitempty"`
+
+		// Fields of the existing resource that are changed by the update
+		Changes []string `json:"changes,omitempty"`
+	}
+
+	ResourceTransferItemSet []*ResourceTransferItem
+
+	ResourceTransferAction string
+)
+
+const (
+	// ResourceTransferCreate resource does not exist yet and will be created
+	ResourceTransferCreate ResourceTransferAction = "create"
+	// ResourceTransferUpdate existing resource will be replaced or merged
+	ResourceTransferUpdate ResourceTransferAction = "update"
+	// ResourceTransferSkip existing resource will be left as it is
+	ResourceTransferSkip ResourceTransferAction = "skip"
+	// ResourceTransferApply resources are applied without comparing them
+	// with the existing ones (records, RBAC rules, settings)
+	ResourceTransferApply ResourceTransferAction = "apply"
+)
+
+// Count returns number of items with the given action
+func (set ResourceTransferItemSet) Count(a ResourceTransferAction) (c uint) {
+	for _, i := range set {
+		if i.Action == a {
+			c++
+		}
+	}
+
+	return
+}
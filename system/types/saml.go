@@ -0,0 +1,35 @@
+package types
+
+type (
+	// SamlIDP holds configuration of the additional SAML identity provider
+	SamlIDP struct {
+		// Unique handle, used in the service provider URLs
+		// (/auth/external/saml/{handle}/...)
+		Handle string `json:"handle"`
+
+		// Label on the login form
+		Name string `json:"name"`
+
+		// Identity provider's metadata URL
+		URL string `json:"url"`
+
+		// identifier payload from idp
+		IdentName       string `json:"ident-name"`
+		IdentHandle     string `json:"ident-handle"`
+		IdentIdentifier string `json:"ident-identifier"`
+
+		// Assertion attribute with values that are mapped to roles
+		RoleAttribute string `json:"role-attribute"`
+
+		RoleMapping []SamlRoleMapping `json:"role-mapping"`
+	}
+
+	// SamlRoleMapping maps value of the assertion attribute to role
+	SamlRoleMapping struct {
+		// Attribute value (group name)
+		Value string `json:"value"`
+
+		// Role handle
+		Role string `json:"role"`
+	}
+)
@@ -20,6 +20,11 @@ type (
 	// This type is auto-generated.
 	ApigwRouteSet []*ApigwRoute
 
+	// ApigwSecretSet slice of ApigwSecret
+	//
+	// This type is auto-generated.
+	ApigwSecretSet []*ApigwSecret
+
 	// ApplicationSet slice of Application
 	//
 	// This type is auto-generated.
@@ -203,6 +208,62 @@ func (set ApigwRouteSet) IDs() (IDs []uint64) {
 	return
 }
 
+// Walk iterates through every slice item and calls w(ApigwSecret) err
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) Walk(w func(*ApigwSecret) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(ApigwSecret) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) Filter(f func(*ApigwSecret) (bool, error)) (out ApigwSecretSet, err error) {
+	var ok bool
+	out = ApigwSecretSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) FindByID(ID uint64) *ApigwSecret {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(Application) err
 //
 // This function is auto-generated.
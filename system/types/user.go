@@ -58,7 +58,18 @@ type (
 
 				// Require OTP to be entered every time client is authorized
 				//StrictTOTP bool `json:"strictTOTP"`
+
+				// Are security keys (WebAuthn) required on login?
+				//
+				// Set when user registers the first key or by administrator;
+				// user without registered keys is asked to register one
+				EnforcedWebAuthn bool `json:"enforcedWebAuthn"`
 			} `json:"mfa"`
+
+			// Require password change on the next login
+			//
+			// Cleared when user changes the password
+			PasswordChangeRequired bool `json:"passwordChangeRequired"`
 		} `json:"securityPolicy"`
 	}
 
@@ -111,6 +122,10 @@ type (
 const (
 	NormalUser UserKind = ""
 	SystemUser UserKind = "sys"
+
+	// BotUser is a dedicated user for service-to-service access
+	// (OAuth2 client credentials grant); it can not log in
+	BotUser UserKind = "bot"
 )
 
 func (u User) String() string {
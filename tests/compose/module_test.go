@@ -192,6 +192,30 @@ func TestModuleCreate(t *testing.T) {
 		End()
 }
 
+func TestModuleCreate_invalidRecordPartition(t *testing.T) {
+	h := newHelper(t)
+	h.clearModules()
+
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read", "modules.search")
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "module.create")
+
+	ns := h.makeNamespace("some-namespace")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/", ns.ID)).
+		Header("Accept", "application/json").
+		JSON(`{"name":"some-module","config":{"recordPartition":{"enabled":true,"table":"compose_module"}}}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError(`record partition table name "compose_module" is reserved`)).
+		End()
+
+	// module is removed when record table can not be created
+	mm, _, err := store.SearchComposeModules(context.Background(), service.DefaultStore, types.ModuleFilter{NamespaceID: ns.ID})
+	h.noError(err)
+	h.a.Empty(mm)
+}
+
 func TestModuleUpdateForbidden(t *testing.T) {
 	h := newHelper(t)
 	h.clearModules()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/pkg/rand"
+	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/store"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
 	jsonpath "github.com/steinfletcher/apitest-jsonpath"
 	"github.com/stretchr/testify/require"
@@ -290,3 +293,175 @@ func TestNamespaceLabels(t *testing.T) {
 		req.NotNil(set.FindByID(ID).Labels)
 	})
 }
+
+func TestNamespaceCloneForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearNamespaces()
+
+	ns := h.makeNamespace("some-namespace")
+	helpers.AllowMe(h, types.ComponentRbacResource(), "namespace.create")
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/clone", ns.ID)).
+		Header("Accept", "application/json").
+		FormData("slug", "cloned-namespace").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("namespace.errors.notAllowedToClone")).
+		End()
+
+	helpers.DenyMe(h, types.ComponentRbacResource(), "namespace.create")
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "manage")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/clone", ns.ID)).
+		Header("Accept", "application/json").
+		FormData("slug", "cloned-namespace").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("namespace.errors.notAllowedToCreate")).
+		End()
+}
+
+func TestNamespaceCloneHandleNotUnique(t *testing.T) {
+	h := newHelper(t)
+	h.clearNamespaces()
+
+	ns := h.makeNamespace("some-namespace")
+	h.makeNamespace("cloned-namespace")
+	helpers.AllowMe(h, types.ComponentRbacResource(), "namespace.create")
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read", "manage")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/clone", ns.ID)).
+		Header("Accept", "application/json").
+		FormData("slug", "cloned-namespace").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("namespace.errors.handleNotUnique")).
+		End()
+}
+
+func TestNamespaceClone(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.clearPages()
+	h.clearCharts()
+
+	var (
+		ctx = context.Background()
+		s   = service.DefaultStore
+
+		ns = h.makeNamespace("clone-source")
+
+		parent = h.createModule(&types.Module{
+			Name:        "parent",
+			Handle:      "parent",
+			NamespaceID: ns.ID,
+			Fields:      types.ModuleFieldSet{{Name: "name", Kind: "String"}},
+		})
+
+		child = h.createModule(&types.Module{
+			Name:        "child",
+			Handle:      "child",
+			NamespaceID: ns.ID,
+			Fields: types.ModuleFieldSet{{
+				Name:    "parent",
+				Kind:    "Record",
+				Options: types.ModuleFieldOptions{"moduleID": strconv.FormatUint(parent.ID, 10)},
+			}},
+		})
+
+		p1 = h.makeRecord(parent, &types.RecordValue{Name: "name", Value: "p1"})
+		_  = h.makeRecord(child, &types.RecordValue{Name: "parent", Value: strconv.FormatUint(p1.ID, 10), Ref: p1.ID})
+
+		chart = h.makeChart(ns, "chart")
+
+		role = &sysTypes.Role{ID: id.Next(), Handle: "clone_" + rs(), CreatedAt: time.Now()}
+	)
+
+	h.noError(store.CreateComposePage(ctx, s, &types.Page{
+		ID:          id.Next(),
+		CreatedAt:   time.Now(),
+		Title:       "page",
+		Handle:      "page",
+		NamespaceID: ns.ID,
+		ModuleID:    child.ID,
+		Blocks: types.PageBlocks{{
+			Kind:    "Chart",
+			Options: map[string]interface{}{"chartID": strconv.FormatUint(chart.ID, 10)},
+		}},
+	}))
+
+	h.noError(store.CreateRole(ctx, s, role))
+	helpers.Grant(rbac.AllowRule(role.ID, types.ModuleRbacResource(ns.ID, child.ID), "read"))
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "namespace.create", "grant")
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read", "manage")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "read", "records.search")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/clone", ns.ID)).
+		Header("Accept", "application/json").
+		FormData("slug", "clone-target").
+		FormData("name", "Clone target").
+		FormData("handlePrefix", "copy_").
+		FormData("records", "true").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.slug`, "clone-target")).
+		Assert(jsonpath.Equal(`$.response.name`, "Clone target")).
+		Assert(jsonpath.Equal(`$.response.progress.modules`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.progress.pages`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.progress.charts`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.progress.rules`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.progress.records`, float64(2))).
+		End()
+
+	dup, err := store.LookupComposeNamespaceBySlug(ctx, s, "clone-target")
+	h.noError(err)
+	h.a.NotEqual(ns.ID, dup.ID)
+
+	dupParent, err := store.LookupComposeModuleByNamespaceIDHandle(ctx, s, dup.ID, "copy_parent")
+	h.noError(err)
+	h.a.NotEqual(parent.ID, dupParent.ID)
+
+	dupChild, err := store.LookupComposeModuleByNamespaceIDHandle(ctx, s, dup.ID, "copy_child")
+	h.noError(err)
+	dupChild.Fields, _, err = store.SearchComposeModuleFields(ctx, s, types.ModuleFieldFilter{ModuleID: []uint64{dupChild.ID}})
+	h.noError(err)
+	h.a.Equal(strconv.FormatUint(dupParent.ID, 10), dupChild.Fields.FindByName("parent").Options.String("moduleID"))
+
+	dupChart, err := store.LookupComposeChartByNamespaceIDHandle(ctx, s, dup.ID, "copy_chart")
+	h.noError(err)
+
+	dupPage, err := store.LookupComposePageByNamespaceIDHandle(ctx, s, dup.ID, "copy_page")
+	h.noError(err)
+	h.a.Equal(dupChild.ID, dupPage.ModuleID)
+	h.a.Equal(strconv.FormatUint(dupChart.ID, 10), dupPage.Blocks[0].Options["chartID"])
+
+	pp, _, err := store.SearchComposeRecords(ctx, s, dupParent, types.RecordFilter{ModuleID: dupParent.ID, NamespaceID: dup.ID})
+	h.noError(err)
+	h.a.Len(pp, 1)
+	h.a.NotEqual(p1.ID, pp[0].ID)
+
+	cc, _, err := store.SearchComposeRecords(ctx, s, dupChild, types.RecordFilter{ModuleID: dupChild.ID, NamespaceID: dup.ID})
+	h.noError(err)
+	h.a.Len(cc, 1)
+	h.a.Equal(strconv.FormatUint(pp[0].ID, 10), cc[0].Values.Get("parent", 0).Value)
+
+	var granted bool
+	for _, r := range rbac.Global().FindRulesByRoleID(role.ID) {
+		granted = granted || r.Resource == types.ModuleRbacResource(dup.ID, dupChild.ID)
+	}
+	h.a.True(granted, "access rules must be cloned")
+
+	// source namespace is left as it was
+	src, err := store.LookupComposeModuleByNamespaceIDHandle(ctx, s, ns.ID, "parent")
+	h.noError(err)
+	h.a.Equal(parent.ID, src.ID)
+}
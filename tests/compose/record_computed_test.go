@@ -0,0 +1,263 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+// makeComputedModules creates parent module with rollup fields and
+// child module with a record field that references the parent
+func (h helper) makeComputedModules() (parent, child *types.Module) {
+	ns := h.makeNamespace("computed values testing namespace")
+
+	child = h.makeRecordModuleWithFieldsOnNs("line", ns,
+		&types.ModuleField{Name: "parent", Kind: "Record", Options: types.ModuleFieldOptions{}},
+		&types.ModuleField{Name: "amount", Kind: "Number"},
+		&types.ModuleField{Name: "label", Kind: "String"},
+	)
+
+	parent = h.makeRecordModuleWithFieldsOnNs("order", ns,
+		&types.ModuleField{Name: "name", Kind: "String"},
+		&types.ModuleField{Name: "total", Kind: "Number", Expressions: types.ModuleFieldExpr{
+			Rollup: &types.ModuleFieldRollup{ModuleID: child.ID, RefField: "parent", Field: "amount", Func: "sum"},
+		}},
+		&types.ModuleField{Name: "lines", Kind: "Number", Expressions: types.ModuleFieldExpr{
+			Rollup: &types.ModuleFieldRollup{ModuleID: child.ID, RefField: "parent", Func: "count"},
+		}},
+		&types.ModuleField{Name: "largest", Kind: "Number", Expressions: types.ModuleFieldExpr{
+			Rollup: &types.ModuleFieldRollup{ModuleID: child.ID, RefField: "parent", Field: "amount", Func: "max"},
+		}},
+		&types.ModuleField{Name: "withTax", Kind: "Number", Expressions: types.ModuleFieldExpr{
+			ValueExpr: "total * 2",
+		}},
+	)
+
+	// now that parent module exists, point record field to it
+	ref := child.Fields.FindByName("parent")
+	ref.Options["moduleID"] = strconv.FormatUint(parent.ID, 10)
+	h.noError(store.UpdateComposeModuleField(context.Background(), service.DefaultStore, ref))
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update", "delete")
+
+	return
+}
+
+func (h helper) apiCreateComputedRecord(m *types.Module, values string) uint64 {
+	var (
+		rsp = struct {
+			Response struct {
+				RecordID uint64 `json:"recordID,string"`
+			} `json:"response"`
+		}{}
+	)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(fmt.Sprintf(`{"values": [%s]}`, values)).
+		Header("Accept", "application/json").
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End().
+		JSON(&rsp)
+
+	return rsp.Response.RecordID
+}
+
+func (h helper) computedValue(m *types.Module, recordID uint64, name string) string {
+	if v := h.lookupRecordByID(m, recordID).Values.Get(name, 0); v != nil {
+		return v.Value
+	}
+
+	return ""
+}
+
+func TestRecordComputedRollup(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		parent, child = h.makeComputedModules()
+
+		order = h.makeRecord(parent, &types.RecordValue{Name: "name", Value: "order"})
+		ref   = fmt.Sprintf(`{"name": "parent", "value": "%d"}`, order.ID)
+	)
+
+	first := h.apiCreateComputedRecord(child, ref+`, {"name": "amount", "value": "10"}`)
+	h.apiCreateComputedRecord(child, ref+`, {"name": "amount", "value": "32"}`)
+
+	h.a.Equal("42", h.computedValue(parent, order.ID, "total"))
+	h.a.Equal("2", h.computedValue(parent, order.ID, "lines"))
+	h.a.Equal("32", h.computedValue(parent, order.ID, "largest"))
+	h.a.Equal("84", h.computedValue(parent, order.ID, "withTax"))
+
+	// update of the child record
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", child.NamespaceID, child.ID, first)).
+		JSON(fmt.Sprintf(`{"values": [%s, {"name": "amount", "value": "50"}]}`, ref)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("82", h.computedValue(parent, order.ID, "total"))
+	h.a.Equal("50", h.computedValue(parent, order.ID, "largest"))
+
+	// removal of the child record
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", child.NamespaceID, child.ID, first)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("32", h.computedValue(parent, order.ID, "total"))
+	h.a.Equal("1", h.computedValue(parent, order.ID, "lines"))
+}
+
+func TestRecordComputedRollupReparent(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		parent, child = h.makeComputedModules()
+
+		o1 = h.makeRecord(parent, &types.RecordValue{Name: "name", Value: "first"})
+		o2 = h.makeRecord(parent, &types.RecordValue{Name: "name", Value: "second"})
+	)
+
+	line := h.apiCreateComputedRecord(child, fmt.Sprintf(`{"name": "parent", "value": "%d"}, {"name": "amount", "value": "5"}`, o1.ID))
+	h.a.Equal("5", h.computedValue(parent, o1.ID, "total"))
+
+	// moving child record to another parent recalculates both
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", child.NamespaceID, child.ID, line)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "parent", "value": "%d"}, {"name": "amount", "value": "5"}]}`, o2.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("0", h.computedValue(parent, o1.ID, "total"))
+	h.a.Equal("5", h.computedValue(parent, o2.ID, "total"))
+}
+
+func TestRecordComputedRollupOnCreate(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	parent, _ := h.makeComputedModules()
+
+	// values sent for computed fields are ignored
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", parent.NamespaceID, parent.ID)).
+		JSON(`{"values": [{"name": "name", "value": "order"}, {"name": "total", "value": "100"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Contains(`$.response.values[? @.name=="total"].value`, "0")).
+		Assert(jsonpath.Contains(`$.response.values[? @.name=="lines"].value`, "0")).
+		End()
+}
+
+func TestRecordComputedLookup(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		ns = h.makeNamespace("computed values testing namespace")
+
+		account = h.makeRecordModuleWithFieldsOnNs("account", ns,
+			&types.ModuleField{Name: "name", Kind: "String"},
+		)
+
+		contact = h.makeRecordModuleWithFieldsOnNs("contact", ns,
+			&types.ModuleField{Name: "account", Kind: "Record", Options: types.ModuleFieldOptions{"moduleID": strconv.FormatUint(account.ID, 10)}},
+			&types.ModuleField{Name: "company", Kind: "String", Expressions: types.ModuleFieldExpr{
+				Lookup: &types.ModuleFieldLookup{RefField: "account", Field: "name"},
+			}},
+		)
+
+		acme = h.makeRecord(account, &types.RecordValue{Name: "name", Value: "ACME"})
+	)
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update", "delete")
+
+	c := h.apiCreateComputedRecord(contact, fmt.Sprintf(`{"name": "account", "value": "%d"}`, acme.ID))
+	h.a.Equal("ACME", h.computedValue(contact, c, "company"))
+
+	// change on the referenced record is propagated
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", account.NamespaceID, account.ID, acme.ID)).
+		JSON(`{"values": [{"name": "name", "value": "ACME Inc."}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("ACME Inc.", h.computedValue(contact, c, "company"))
+
+	// and so is the removal
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", account.NamespaceID, account.ID, acme.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("", h.computedValue(contact, c, "company"))
+}
+
+func TestRecordComputedRecalculate(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		ctx = context.Background()
+
+		parent, child = h.makeComputedModules()
+
+		order = h.makeRecord(parent, &types.RecordValue{Name: "name", Value: "order"})
+	)
+
+	// records stored directly, without recalculation
+	h.makeRecord(child,
+		&types.RecordValue{Name: "parent", Value: strconv.FormatUint(order.ID, 10), Ref: order.ID},
+		&types.RecordValue{Name: "amount", Value: "7"},
+	)
+
+	h.a.Equal("", h.computedValue(parent, order.ID, "total"))
+
+	parent = h.lookupModuleByID(parent.ID)
+	n, err := service.RecordComputed(service.DefaultStore, service.DefaultLogger).
+		Recalculate(ctx, parent, h.lookupRecordByID(parent, order.ID))
+	h.noError(err)
+	h.a.Equal(1, n)
+
+	h.a.Equal("7", h.computedValue(parent, order.ID, "total"))
+	h.a.Equal("14", h.computedValue(parent, order.ID, "withTax"))
+	h.a.NotNil(h.lookupRecordByID(parent, order.ID).UpdatedAt)
+
+	// change of computed values is recorded as a revision
+	rr, _, err := store.SearchComposeRecordRevisions(ctx, service.DefaultStore, types.RecordRevisionFilter{RecordID: order.ID})
+	h.noError(err)
+	h.a.Len(rr, 1)
+	h.a.Equal(types.RecordRevisionUpdate, rr[0].Operation)
+}
@@ -0,0 +1,141 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) clearRecordRevisions() {
+	h.noError(store.TruncateComposeRecordRevisions(context.Background(), service.DefaultStore))
+}
+
+func (h helper) apiUpdateRecordName(module *types.Module, recordID uint64, name string) {
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, recordID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "name", "value": %q}]}`, name)).
+		Header("Accept", "application/json").
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func TestRecordRevisions(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.clearRecordRevisions()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "v1"})
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	h.apiUpdateRecordName(module, record.ID, "v2")
+	h.apiUpdateRecordName(module, record.ID, "v3")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions", module.NamespaceID, module.ID, record.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 2)).
+		Assert(jsonpath.Equal(`$.response.set[0].operation`, "update")).
+		Assert(jsonpath.Equal(`$.response.set[0].changes[0].name`, "name")).
+		Assert(jsonpath.Equal(`$.response.set[0].changes[0].old[0]`, "v1")).
+		Assert(jsonpath.Equal(`$.response.set[0].changes[0].new[0]`, "v2")).
+		End()
+}
+
+func TestRecordRevisions_paging(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.clearRecordRevisions()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "v1"})
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	h.apiUpdateRecordName(module, record.ID, "v2")
+	h.apiUpdateRecordName(module, record.ID, "v3")
+	h.apiUpdateRecordName(module, record.ID, "v4")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions", module.NamespaceID, module.ID, record.ID)).
+		Query("limit", "2").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 2)).
+		Assert(jsonpath.Equal(`$.response.set[0].changes[0].new[0]`, "v2")).
+		Assert(jsonpath.Equal(`$.response.set[1].changes[0].new[0]`, "v3")).
+		Assert(jsonpath.Present(`$.response.filter.nextPage`)).
+		End()
+}
+
+func TestRecordRestoreRevision(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.clearRecordRevisions()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "v1"})
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	h.apiUpdateRecordName(module, record.ID, "v2")
+	h.apiUpdateRecordName(module, record.ID, "v3")
+
+	rr, _, err := store.SearchComposeRecordRevisions(context.Background(), service.DefaultStore, types.RecordRevisionFilter{RecordID: record.ID})
+	h.noError(err)
+	h.a.Len(rr, 2)
+
+	first := rr[0]
+	if rr[1].ID < first.ID {
+		first = rr[1]
+	}
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/%d/restore", module.NamespaceID, module.ID, record.ID, first.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.values[0].value`, "v2")).
+		End()
+
+	r := h.lookupRecordByID(module, record.ID)
+	h.a.Equal("v2", r.Values.Get("name", 0).Value)
+
+	rr, _, err = store.SearchComposeRecordRevisions(context.Background(), service.DefaultStore, types.RecordRevisionFilter{RecordID: record.ID})
+	h.noError(err)
+	h.a.Len(rr, 3)
+}
+
+func TestRecordRestoreRevision_notFound(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.clearRecordRevisions()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "v1"})
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/%d/restore", module.NamespaceID, module.ID, record.ID, 42)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.revisionNotFound")).
+		End()
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/cortezaproject/corteza-server/compose/service"
 	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/store"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
 	"github.com/steinfletcher/apitest"
 	jsonpath "github.com/steinfletcher/apitest-jsonpath"
@@ -774,6 +776,29 @@ func (h helper) apiInitRecordImport(api *apitest.APITest, url, f string, file []
 		Status(http.StatusOK)
 }
 
+// processes queued import sessions right away instead of waiting for the worker
+//
+// Worker loads the owner of the session (current user) and their role memberships from the store
+func (h helper) processRecordImports() {
+	var (
+		ctx    = context.Background()
+		role   = &sysTypes.Role{ID: h.roleID, Handle: fmt.Sprintf("import_%d", h.roleID), CreatedAt: time.Now()}
+		member = &sysTypes.RoleMember{UserID: h.cUser.ID, RoleID: h.roleID}
+	)
+
+	h.noError(store.UpsertUser(ctx, service.DefaultStore, &sysTypes.User{
+		ID:        h.cUser.ID,
+		Email:     fmt.Sprintf("import_%d@test.cortezaproject.org", h.cUser.ID),
+		Handle:    fmt.Sprintf("import_%d", h.cUser.ID),
+		CreatedAt: time.Now(),
+	}))
+	h.noError(store.UpsertRole(ctx, service.DefaultStore, role))
+	h.noError(store.DeleteRoleMember(ctx, service.DefaultStore, member))
+	h.noError(store.CreateRoleMember(ctx, service.DefaultStore, member))
+
+	h.noError(service.DefaultImportSession.ProcessQueued(ctx))
+}
+
 func (h helper) apiRunRecordImport(api *apitest.APITest, url, b string) *apitest.Response {
 	return api.
 		Patch(url).
@@ -973,6 +998,8 @@ func TestRecordImportRunFieldError_missing(t *testing.T) {
 			h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"femail":"email"},"onError":"skip"}`).
 				End()
 
+			h.processRecordImports()
+
 			api.Get(fmt.Sprintf("%s/%s", url, rsp.Response.SessionID)).
 				Expect(h.t).
 				Status(http.StatusOK).
@@ -983,6 +1010,226 @@ func TestRecordImportRunFieldError_missing(t *testing.T) {
 	}
 }
 
+func TestRecordImportRun_completed(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	module := h.repoMakeRecordModuleWithFields("record import run module")
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	rsp := &rImportSession{}
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\nv3,v4\n")).End().JSON(rsp)
+
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.processRecordImports()
+
+	h.apiInit().Get(fmt.Sprintf("%s/%s", url, rsp.Response.SessionID)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, "completed")).
+		Assert(jsonpath.Equal(`$.response.progress.completed`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.progress.processed`, float64(2))).
+		End()
+
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 2)
+
+	// session can not be started again
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
+		Assert(helpers.AssertError("record.errors.importSessionAlreadActive")).
+		End()
+}
+
+func TestRecordImportRun_ownerRolesReloaded(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		ctx  = context.Background()
+		role = &sysTypes.Role{ID: id.Next(), Handle: fmt.Sprintf("import_%d", id.Next()), CreatedAt: time.Now()}
+	)
+
+	h.noError(store.CreateRole(ctx, service.DefaultStore, role))
+	helpers.UpdateRBAC(h.roleID, role.ID)
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.Grant(rbac.AllowRule(role.ID, types.ModuleRbacResource(0, 0), "record.create"))
+
+	var (
+		module = h.repoMakeRecordModuleWithFields("record import run module")
+		url    = fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+		rsp    = &rImportSession{}
+	)
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\n")).End().JSON(rsp)
+
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// after the import was started, owner can create records
+	// only as a member of the new role
+	helpers.Grant(rbac.InheritRule(h.roleID, types.ModuleRbacResource(0, 0), "record.create"))
+	h.noError(store.CreateRoleMember(ctx, service.DefaultStore, &sysTypes.RoleMember{UserID: h.cUser.ID, RoleID: role.ID}))
+
+	h.processRecordImports()
+
+	sessionID, _ := strconv.ParseUint(rsp.Response.SessionID, 10, 64)
+	ses, err := store.LookupComposeRecordImportSessionByID(ctx, service.DefaultStore, sessionID)
+	h.noError(err)
+	h.a.Equal(types.RecordImportCompleted, ses.Status)
+
+	rr, _, err := store.SearchComposeRecords(ctx, service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 1)
+}
+
+func TestRecordImportRun_resume(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	var (
+		ctx    = context.Background()
+		module = h.repoMakeRecordModuleWithFields("record import run module")
+		url    = fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+		rsp    = &rImportSession{}
+	)
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\nv3,v4\nv5,v6\n")).End().JSON(rsp)
+
+	sessionID, _ := strconv.ParseUint(rsp.Response.SessionID, 10, 64)
+	ses, err := store.LookupComposeRecordImportSessionByID(ctx, service.DefaultStore, sessionID)
+	h.noError(err)
+
+	// simulate import that is running (on another instance) after the first row
+	fresh := time.Now()
+	ses.Fields = types.RecordImportFields{"fname": "name", "femail": "email"}
+	ses.Status = types.RecordImportRunning
+	ses.Progress.Processed = 1
+	ses.Progress.Completed = 1
+	ses.UpdatedAt = &fresh
+	h.noError(store.UpdateComposeRecordImportSession(ctx, service.DefaultStore, ses))
+
+	h.processRecordImports()
+
+	ses, err = store.LookupComposeRecordImportSessionByID(ctx, service.DefaultStore, sessionID)
+	h.noError(err)
+	h.a.Equal(types.RecordImportRunning, ses.Status)
+
+	// simulate import that was interrupted after the first row
+	stale := time.Now().Add(-time.Hour)
+	ses.UpdatedAt = &stale
+	h.noError(store.UpdateComposeRecordImportSession(ctx, service.DefaultStore, ses))
+
+	h.processRecordImports()
+
+	ses, err = store.LookupComposeRecordImportSessionByID(ctx, service.DefaultStore, sessionID)
+	h.noError(err)
+	h.a.Equal(types.RecordImportCompleted, ses.Status)
+	h.a.Equal(uint64(3), ses.Progress.Completed)
+
+	rr, _, err := store.SearchComposeRecords(ctx, service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 2)
+}
+
+func TestRecordImportRun_upsert(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update")
+
+	var (
+		ctx    = context.Background()
+		module = h.repoMakeRecordModuleWithFields("record import upsert module")
+		url    = fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+		rsp    = &rImportSession{}
+
+		existing = h.makeRecord(module,
+			&types.RecordValue{Name: "name", Value: "old name"},
+			&types.RecordValue{Name: "email", Value: "v2"},
+			&types.RecordValue{Name: "description", Value: "kept"},
+		)
+	)
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nnew name,v2\nv3,v4\n")).End().JSON(rsp)
+
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail","mode":"upsert","key":"femail"}`).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.mode`, "upsert")).
+		End()
+
+	h.processRecordImports()
+
+	rr, _, err := store.SearchComposeRecords(ctx, service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 2)
+
+	updated, err := store.LookupComposeRecordByID(ctx, service.DefaultStore, module, existing.ID)
+	h.noError(err)
+	h.a.Equal("new name", updated.Values.Get("name", 0).Value)
+	h.a.Equal("kept", updated.Values.Get("description", 0).Value)
+}
+
+func TestRecordImportRun_upsertInvalidKey(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record import upsert module")
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	rsp := &rImportSession{}
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\n")).End().JSON(rsp)
+	t.Logf("%s", h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\n")).End().Response.Body)
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail","mode":"upsert","key":"missing"}`).
+		Assert(helpers.AssertError("record.errors.importInvalidKey")).
+		End()
+}
+
+func TestRecordImportRun_dryRun(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	var (
+		module = h.repoMakeRecordModuleWithFieldsRequired("record import dry-run module")
+		url    = fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+		rsp    = &rImportSession{}
+	)
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,v2\n,v4\n")).End().JSON(rsp)
+
+	h.apiRunRecordImport(h.apiInit(), fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail","dryRun":true}`).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, "pending")).
+		Assert(jsonpath.Equal(`$.response.progress.completed`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.progress.failed`, float64(1))).
+		Assert(jsonpath.Len(`$.response.rejected`, 1)).
+		Assert(jsonpath.Equal(`$.response.rejected[0].index`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.rejected[0].errors.set[0].kind`, "empty")).
+		End()
+
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 0)
+
+	// rejected rows can be downloaded
+	r := h.apiInit().Get(fmt.Sprintf("%s/%s/errors.csv", url, rsp.Response.SessionID)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		End()
+
+	b, err := ioutil.ReadAll(r.Response.Body)
+	h.noError(err)
+	h.a.Equal("row,femail,fname,errors\n2,v4,,empty field name\n", string(b))
+}
+
 func TestRecordImportImportProgress(t *testing.T) {
 	h := newHelper(t)
 	h.clearRecords()
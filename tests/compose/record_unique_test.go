@@ -0,0 +1,253 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+// makeUniqueModule creates module with the given unique constraints
+func (h helper) makeUniqueModule(cc ...types.ModuleConfigUniqueConstraint) *types.Module {
+	var (
+		ns = h.makeNamespace("unique constraints testing namespace")
+		m  = h.makeRecordModuleWithFieldsOnNs("contact", ns,
+			&types.ModuleField{Name: "email", Kind: "String"},
+			&types.ModuleField{Name: "company", Kind: "String"},
+			&types.ModuleField{Name: "status", Kind: "String"},
+		)
+	)
+
+	m.Config.UniqueConstraints = cc
+	h.noError(store.UpdateComposeModule(context.Background(), service.DefaultStore, m))
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update")
+
+	return m
+}
+
+func TestRecordUniqueConstraintCreate(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueModule(types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}})
+
+	h.makeRecord(m,
+		&types.RecordValue{Name: "email", Value: "john@example.tld"},
+		&types.RecordValue{Name: "company", Value: "ACME"},
+	)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "company", "value": "ACME"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertErrorP("1 issue(s) found")).
+		End()
+
+	// same email, different company
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "company", "value": "Initech"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// constraint does not apply when any of the values is missing
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func TestRecordUniqueConstraintUpdate(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		m = h.makeUniqueModule(types.ModuleConfigUniqueConstraint{Fields: []string{"email", "company"}})
+
+		r1 = h.makeRecord(m,
+			&types.RecordValue{Name: "email", Value: "john@example.tld"},
+			&types.RecordValue{Name: "company", Value: "ACME"},
+		)
+
+		r2 = h.makeRecord(m,
+			&types.RecordValue{Name: "email", Value: "jane@example.tld"},
+			&types.RecordValue{Name: "company", Value: "ACME"},
+		)
+	)
+
+	// record does not conflict with itself
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", m.NamespaceID, m.ID, r1.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "company", "value": "ACME"}, {"name": "status", "value": "active"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", m.NamespaceID, m.ID, r2.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "company", "value": "ACME"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertErrorP("1 issue(s) found")).
+		End()
+}
+
+func TestRecordUniqueConstraintCaseInsensitive(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueModule(types.ModuleConfigUniqueConstraint{Name: "email per company", Fields: []string{"email", "company"}, CaseInsensitive: true})
+
+	h.makeRecord(m,
+		&types.RecordValue{Name: "email", Value: "john@example.tld"},
+		&types.RecordValue{Name: "company", Value: "ACME"},
+	)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "John@Example.tld"}, {"name": "company", "value": "acme"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertErrorP("1 issue(s) found")).
+		End()
+
+	// wildcards in values are not matched as such
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "company", "value": "AC%"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func TestRecordUniqueConstraintFilter(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueModule(types.ModuleConfigUniqueConstraint{Fields: []string{"email"}, Filter: `status == "active"`})
+
+	h.makeRecord(m,
+		&types.RecordValue{Name: "email", Value: "john@example.tld"},
+		&types.RecordValue{Name: "status", Value: "active"},
+	)
+
+	h.makeRecord(m,
+		&types.RecordValue{Name: "email", Value: "jane@example.tld"},
+		&types.RecordValue{Name: "status", Value: "archived"},
+	)
+
+	// new record is out of constraint's scope
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "status", "value": "archived"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// existing record is out of constraint's scope
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "jane@example.tld"}, {"name": "status", "value": "active"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(`{"values": [{"name": "email", "value": "john@example.tld"}, {"name": "status", "value": "active"}]}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertErrorP("1 issue(s) found")).
+		End()
+}
+
+func TestRecordUniqueConstraintBulk(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		m = h.makeUniqueModule(types.ModuleConfigUniqueConstraint{Fields: []string{"email"}})
+
+		parent = h.makeRecordModuleWithFieldsOnNs("account", h.lookupNamespaceByID(m.NamespaceID),
+			&types.ModuleField{Name: "name", Kind: "String"},
+		)
+	)
+
+	// contacts in the same batch
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", parent.NamespaceID, parent.ID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "name", "value": "ACME"}], "records": [{"set": [
+			{"moduleID": "%d", "values": [{"name": "email", "value": "john@example.tld"}]},
+			{"moduleID": "%d", "values": [{"name": "email", "value": "john@example.tld"}]}
+		]}]}`, m.ID, m.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertErrorP("1 issue(s) found")).
+		End()
+
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, m, types.RecordFilter{})
+	h.noError(err)
+
+	// duplicate is rejected, first record of the batch is stored
+	h.a.Len(rr, 1)
+}
+
+func TestRecordUniqueConstraintModuleUpdate(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueModule()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d", m.NamespaceID, m.ID)).
+		JSON(`{"name": "contact", "fields": [{"name": "email", "kind": "String"}], "config": {"uniqueConstraints": [{"fields": ["missing"]}]}}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("module.errors.invalidUniqueConstraint")).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d", m.NamespaceID, m.ID)).
+		JSON(`{"name": "contact", "fields": [{"name": "email", "kind": "String"}], "config": {"uniqueConstraints": [{"fields": ["email"], "caseInsensitive": true}]}}`).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.config.uniqueConstraints[0].fields[0]`, "email")).
+		End()
+
+	m = h.lookupModuleByID(m.ID)
+	h.a.Len(m.Config.UniqueConstraints, 1)
+	h.a.True(m.Config.UniqueConstraints[0].CaseInsensitive)
+}
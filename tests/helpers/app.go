@@ -31,6 +31,9 @@ func NewIntegrationTestApp(ctx context.Context, initTestServices func(*app.Corte
 	a.Opt.Auth.Expiry = time.Minute
 	a.Opt.Auth.DefaultClient = ""
 
+	// API Gateway secret key is not derived from the environment
+	a.Opt.Apigw.SecretKey = string(rand.Bytes(32))
+
 	a.Log = logger.Default()
 
 	cli.HandleError(a.InitStore(ctx))
@@ -0,0 +1,55 @@
+package reporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_export_csv(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenarioWithName(ctx, s, t, h, "load_basic")
+		ff        = loadNoErr(ctx, h, m, dd...)
+		buf       = &bytes.Buffer{}
+	)
+
+	h.a.NoError(report.ExportCSV(buf, ff...))
+
+	rr, err := csv.NewReader(buf).ReadAll()
+	h.a.NoError(err)
+
+	// header + rows
+	h.a.Len(rr, 13)
+	h.a.Equal([]string{"Record ID", "first_name label", "last_name label", "number_of_numbers label"}, rr[0])
+}
+
+func Test_export_xlsx(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenarioWithName(ctx, s, t, h, "load_basic")
+		ff        = loadNoErr(ctx, h, m, dd...)
+		buf       = &bytes.Buffer{}
+	)
+
+	h.a.NoError(report.ExportXLSX(buf, ff...))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	h.a.NoError(err)
+
+	files := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		files = append(files, f.Name)
+	}
+
+	h.a.ElementsMatch([]string{
+		"xl/worksheets/sheet1.xml",
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+	}, files)
+}
@@ -0,0 +1,50 @@
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_join_attachment_csv(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		raw       = []byte("key,value\naa_01,10\naa_01,20\naa_03,30\nzz_01,40\n")
+	)
+
+	att, err := service.DefaultAttachment.CreateNamespaceAttachment(ctx, "data.csv", int64(len(raw)), bytes.NewReader(raw))
+	h.noError(err)
+
+	var (
+		m, _, dd       = loadScenario(ctx, s, t, h, withDefinition("csv", "attachmentID", att.ID))
+		ff             = loadNoErr(ctx, h, m, dd...)
+		local, foreign *report.Frame
+	)
+
+	h.a.Len(ff, 3)
+
+	local = ff[0]
+	ix := indexJoinedResult(ff)
+
+	// local
+	h.a.Equal("aa", local.Ref)
+
+	// aa_01
+	foreign = ix["csv/aa/aa_01"]
+	h.a.NotNil(foreign)
+	h.a.Equal("key<String>, value<Number>", foreign.Columns.String())
+	h.a.Equal("joined", foreign.Source)
+	h.a.Equal("csv", foreign.Ref)
+	h.a.Equal("pk", foreign.RelColumn)
+	checkRows(h, foreign,
+		"aa_01, 10",
+		"aa_01, 20")
+
+	// aa_03
+	foreign = ix["csv/aa/aa_03"]
+	h.a.NotNil(foreign)
+	checkRows(h, foreign,
+		"aa_03, 30")
+}
@@ -6,7 +6,7 @@ import (
 
 func Test_join_nested_complex(t *testing.T) {
 
-	t.Skip("@todo temporarily disabled")
+	t.Skip("@todo joining joined foreign sources is not yet supported")
 
 	// var (
 	// 	ctx, h, s      = setup(t)
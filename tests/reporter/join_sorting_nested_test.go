@@ -0,0 +1,78 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_join_sorting_nested(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenario(ctx, s, t, h)
+		ff        []*report.Frame
+		def       = dd[0]
+		local     *report.Frame
+	)
+
+	// // // PAGE 1
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 5)
+	local = ff[0]
+	ix := indexJoinedResult(ff)
+
+	// local
+	h.a.Equal(2, local.Size())
+	h.a.NotNil(local.Paging)
+	h.a.NotNil(local.Paging.NextPage)
+	checkRows(h, local,
+		", aa_05, aa :: 05",
+		", aa_04, aa :: 04")
+
+	checkRows(h, ix["cc/aa/aa_05"],
+		", cc_08, aa_05, cc :: 08")
+	checkRows(h, ix["cc/aa/aa_04"],
+		", cc_07, aa_04, cc :: 07")
+	checkRows(h, ix["bb/aa/aa_05"],
+		", bb_08, aa_05, bb :: 08",
+		", bb_09, aa_05, bb :: 09",
+		", bb_10, aa_05, bb :: 10")
+	checkRows(h, ix["bb/aa/aa_04"],
+		", bb_07, aa_04, bb :: 07")
+
+	// // // PAGE 2
+	def.Paging.PageCursor = local.Paging.NextPage
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 5)
+	local = ff[0]
+	ix = indexJoinedResult(ff)
+
+	// local
+	h.a.Equal(2, local.Size())
+	h.a.NotNil(local.Paging)
+	h.a.NotNil(local.Paging.NextPage)
+	checkRows(h, local,
+		", aa_03, aa :: 03",
+		", aa_02, aa :: 02")
+
+	h.a.NotNil(ix["cc/aa/aa_03"])
+	h.a.NotNil(ix["cc/aa/aa_02"])
+	h.a.NotNil(ix["bb/aa/aa_03"])
+	h.a.NotNil(ix["bb/aa/aa_02"])
+
+	// // // PAGE 3
+	def.Paging.PageCursor = local.Paging.NextPage
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 3)
+	local = ff[0]
+	ix = indexJoinedResult(ff)
+
+	// local
+	h.a.Equal(1, local.Size())
+	h.a.Nil(local.Paging)
+	checkRows(h, local,
+		", aa_01, aa :: 01")
+
+	h.a.NotNil(ix["cc/aa/aa_01"])
+	h.a.NotNil(ix["bb/aa/aa_01"])
+}
@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func Test_join_system_users(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+	)
+
+	h.noError(s.TruncateUsers(ctx))
+	h.noError(store.CreateUser(ctx, s,
+		&types.User{ID: id.Next(), Handle: "Maria_Königsmann", Email: "mk@test.tld", Name: "Maria K", Kind: types.NormalUser},
+		&types.User{ID: id.Next(), Handle: "Maria_Krüger", Email: "mkr@test.tld", Name: "Maria Kr", Kind: types.NormalUser},
+		&types.User{ID: id.Next(), Handle: "Ulli_Haupt", Email: "uh@test.tld", Name: "Ulli H", Kind: types.NormalUser},
+	))
+
+	var (
+		m, _, dd       = loadScenario(ctx, s, t, h)
+		ff             = loadNoErr(ctx, h, m, dd...)
+		local, foreign *report.Frame
+	)
+
+	h.a.Len(ff, 3)
+
+	local = ff[0]
+	ix := indexJoinedResult(ff)
+
+	// local
+	// Maria_Spannagel has no system user so it is omitted
+	h.a.Equal("users", local.Ref)
+	checkRows(h, local,
+		", Maria_Krüger, Maria",
+		", Maria_Königsmann, Maria")
+
+	// Maria_Königsmann
+	foreign = ix["sysUsers/users/Maria_Königsmann"]
+	h.a.NotNil(foreign)
+	h.a.Equal("handle<String>, email<String>, name<String>", foreign.Columns.String())
+	h.a.Equal("joined", foreign.Source)
+	h.a.Equal("join_key", foreign.RelColumn)
+	checkRows(h, foreign,
+		"Maria_Königsmann, mk@test.tld, Maria K")
+
+	// Maria_Krüger
+	foreign = ix["sysUsers/users/Maria_Krüger"]
+	h.a.NotNil(foreign)
+	checkRows(h, foreign,
+		"Maria_Krüger, mkr@test.tld, Maria Kr")
+}
@@ -0,0 +1,127 @@
+package reporter
+
+import (
+	"bytes"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/report"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+func Test_load_attachment_json(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		raw       = []byte(`[
+			{"name": "a", "kind": "keep"},
+			{"name": "b", "kind": "skip"},
+			{"name": "c", "kind": "keep", "meta": {"x": 1}},
+			{"name": "d", "kind": "keep"},
+			{"name": "e"}
+		]`)
+	)
+
+	att, err := service.DefaultAttachment.CreateNamespaceAttachment(ctx, "data.json", int64(len(raw)), bytes.NewReader(raw))
+	h.noError(err)
+
+	var (
+		m, _, dd = loadScenario(ctx, s, t, h, withDefinition("json", "attachmentID", att.ID))
+		ff       []*report.Frame
+		f        *report.Frame
+		def      = dd[0]
+	)
+
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	f = ff[0]
+	h.a.Equal("name<String>, kind<String>, meta<String>", f.Columns.String())
+	h.a.NotNil(f.Paging)
+	h.a.NotNil(f.Paging.NextPage)
+	h.a.Nil(f.Paging.PrevPage)
+	checkRows(h, f,
+		"d, keep<N/A>",
+		`c, keep, {"x":1}`)
+
+	def.Paging.PageCursor = f.Paging.NextPage
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	f = ff[0]
+	h.a.NotNil(f.Paging)
+	h.a.Nil(f.Paging.NextPage)
+	h.a.NotNil(f.Paging.PrevPage)
+	checkRows(h, f,
+		"a, keep<N/A>")
+}
+
+func Test_load_attachment_json_unreferenced_record_attachment(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		providers = map[string]report.DatasourceProvider{
+			"composeAttachments": service.DefaultAttachment,
+		}
+	)
+
+	cleanup(ctx, h, s)
+	parseEnvoy(ctx, s, h, "testdata/data_model")
+
+	ns, err := store.LookupComposeNamespaceBySlug(ctx, s, "ns")
+	h.noError(err)
+
+	// record attachment that is not referenced by any record
+	att := &types.Attachment{
+		ID:          id.Next(),
+		Kind:        types.RecordAttachment,
+		NamespaceID: ns.ID,
+		Name:        "data.json",
+		CreatedAt:   time.Now(),
+	}
+	h.noError(store.CreateComposeAttachment(ctx, s, att))
+
+	rr := parseReport(h, path.Join("testdata", "load_attachment_json", "report.json"))
+	withDefinition("json", "attachmentID", att.ID)(rr)
+
+	m, err := report.Model(ctx, providers, rr.Sources.ModelSteps()...)
+	h.noError(err)
+	h.noError(m.Run(ctx))
+
+	loadErr(ctx, h, m, rr.Frames[0], "not allowed to read this record")
+}
+
+func Test_load_attachment_json_referenced_record_attachment(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		raw       = []byte(`[{"name": "a", "kind": "keep"}]`)
+		providers = map[string]report.DatasourceProvider{
+			"composeAttachments": service.DefaultAttachment,
+		}
+	)
+
+	cleanup(ctx, h, s)
+	parseEnvoy(ctx, s, h, "testdata/data_model")
+
+	ns, err := store.LookupComposeNamespaceBySlug(ctx, s, "ns")
+	h.noError(err)
+
+	mod := &types.Module{ID: id.Next(), NamespaceID: ns.ID, Handle: "files", Name: "files", CreatedAt: time.Now()}
+	h.noError(store.CreateComposeModule(ctx, s, mod))
+	h.noError(store.CreateComposeModuleField(ctx, s, &types.ModuleField{ID: id.Next(), ModuleID: mod.ID, Name: "file", Kind: "File", CreatedAt: time.Now()}))
+
+	att, err := service.DefaultAttachment.With(ctx).CreateRecordAttachment(ns.ID, "data.json", int64(len(raw)), bytes.NewReader(raw), mod.ID, 0, "file")
+	h.noError(err)
+
+	rec := &types.Record{ID: id.Next(), NamespaceID: ns.ID, ModuleID: mod.ID, CreatedAt: time.Now()}
+	rec.Values = types.RecordValueSet{{RecordID: rec.ID, Name: "file", Value: strconv.FormatUint(att.ID, 10), Ref: att.ID}}
+	h.noError(store.CreateComposeRecord(ctx, s, mod, rec))
+
+	rr := parseReport(h, path.Join("testdata", "load_attachment_json", "report.json"))
+	withDefinition("json", "attachmentID", att.ID)(rr)
+
+	ff := loadNoErr(ctx, h, modelReport(ctx, h, providers, rr), rr.Frames[0])
+	h.a.Len(ff, 1)
+	checkRows(h, ff[0], "a, keep")
+}
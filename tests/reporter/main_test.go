@@ -30,6 +30,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/pkg/report"
 	"github.com/cortezaproject/corteza-server/store"
+	sysService "github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	sysTypes "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
@@ -248,25 +249,47 @@ func describeNoErr(ctx context.Context, h helper, m report.M, dd ...string) (ff
 	return
 }
 
-func loadScenario(ctx context.Context, s store.Storer, t *testing.T, h helper) (report.M, *auxReport, report.FrameDefinitionSet) {
-	return loadScenarioWithName(ctx, s, t, h, t.Name()[5:])
+func loadScenario(ctx context.Context, s store.Storer, t *testing.T, h helper, pp ...func(*auxReport)) (report.M, *auxReport, report.FrameDefinitionSet) {
+	return loadScenarioWithName(ctx, s, t, h, t.Name()[5:], pp...)
 }
 
-func loadScenarioWithName(ctx context.Context, s store.Storer, t *testing.T, h helper, scenario string) (report.M, *auxReport, report.FrameDefinitionSet) {
+// loadScenarioWithName loads the scenario; pp can adjust the report before it is modeled
+func loadScenarioWithName(ctx context.Context, s store.Storer, t *testing.T, h helper, scenario string, pp ...func(*auxReport)) (report.M, *auxReport, report.FrameDefinitionSet) {
 	var (
 		providers = map[string]report.DatasourceProvider{
-			"composeRecords": service.DefaultRecord,
+			"composeRecords":     service.DefaultRecord,
+			"composeAttachments": service.DefaultAttachment,
+			"systemUsers":        sysService.DefaultUser,
 		}
 	)
 
 	cleanup(ctx, h, s)
 	parseEnvoy(ctx, s, h, "testdata/data_model")
 	rr := parseReport(h, path.Join("testdata", scenario, "report.json"))
+	for _, p := range pp {
+		p(rr)
+	}
 	m := modelReport(ctx, h, providers, rr)
 
 	return m, rr, rr.Frames
 }
 
+// withDefinition sets the load step's definition parameter
+func withDefinition(step, k string, v interface{}) func(*auxReport) {
+	return func(rr *auxReport) {
+		for _, src := range rr.Sources {
+			if src.Step.Load == nil || src.Step.Load.Name != step {
+				continue
+			}
+
+			if src.Step.Load.Definition == nil {
+				src.Step.Load.Definition = make(map[string]interface{})
+			}
+			src.Step.Load.Definition[k] = v
+		}
+	}
+}
+
 func loadScenarioOwnDM(ctx context.Context, s store.Storer, t *testing.T, h helper) (report.M, *auxReport, report.FrameDefinitionSet) {
 	return loadScenarioOwnDMWithName(ctx, s, t, h, t.Name()[5:])
 }
@@ -274,7 +297,9 @@ func loadScenarioOwnDM(ctx context.Context, s store.Storer, t *testing.T, h help
 func loadScenarioOwnDMWithName(ctx context.Context, s store.Storer, t *testing.T, h helper, scenario string) (report.M, *auxReport, report.FrameDefinitionSet) {
 	var (
 		providers = map[string]report.DatasourceProvider{
-			"composeRecords": service.DefaultRecord,
+			"composeRecords":     service.DefaultRecord,
+			"composeAttachments": service.DefaultAttachment,
+			"systemUsers":        sysService.DefaultUser,
 		}
 	)
 
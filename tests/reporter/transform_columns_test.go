@@ -0,0 +1,28 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_transform_columns(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenario(ctx, s, t, h)
+		ff        []*report.Frame
+		def       = dd[0]
+	)
+
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	r := ff[0]
+	h.a.Equal(3, r.Size())
+
+	h.a.Equal("by_name<String>, count<Number>, total<Number>, double<Number>", r.Columns.String())
+
+	checkRows(h, ff[0],
+		"Engel, 3, 179, 358",
+		"Maria, 3, 183, 366",
+		"Ulli, 3, 122, 244")
+}
@@ -0,0 +1,59 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_transform_pivot(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenario(ctx, s, t, h)
+		ff        []*report.Frame
+		def       = dd[0]
+	)
+
+	// // // PAGE 1
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	r := ff[0]
+	h.a.Equal(4, r.Size())
+	h.a.NotNil(r.Paging)
+	h.a.NotNil(r.Paging.NextPage)
+
+	h.a.Equal("by_usr<String>, a<Number>, b<Number>, c<Number>, d<Number>", r.Columns.String())
+
+	checkRows(h, r,
+		"Engel_Kiefer, 182, 139, 136, 106",
+		"Engel_Loritz, 29<N/A>, <N/A>, <N/A>",
+		"Manu_Specht<N/A>, , 113, 83, 45",
+		"Maria_Königsmann, 14, 54, 3, 11")
+
+	// // // PAGE 2
+	def.Paging.PageCursor = r.Paging.NextPage
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	r = ff[0]
+	h.a.Equal(2, r.Size())
+	h.a.NotNil(r.Paging)
+	h.a.Nil(r.Paging.NextPage)
+	h.a.NotNil(r.Paging.PrevPage)
+
+	checkRows(h, r,
+		"Sigi_Goldschmidt, 10, 10<N/A>, , 10",
+		"Ulli_Böhler, 1<N/A>, <N/A>, <N/A>")
+
+	// // // PAGE 1 (back)
+	def.Paging.PageCursor = r.Paging.PrevPage
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	r = ff[0]
+	h.a.Equal(4, r.Size())
+
+	checkRows(h, r,
+		"Engel_Kiefer, 182, 139, 136, 106",
+		"Engel_Loritz, 29<N/A>, <N/A>, <N/A>",
+		"Manu_Specht<N/A>, , 113, 83, 45",
+		"Maria_Königsmann, 14, 54, 3, 11")
+}
@@ -0,0 +1,29 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/report"
+)
+
+func Test_transform_unpivot(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+		m, _, dd  = loadScenario(ctx, s, t, h)
+		ff        []*report.Frame
+		def       = dd[0]
+	)
+
+	ff = loadNoErr(ctx, h, m, def)
+	h.a.Len(ff, 1)
+	r := ff[0]
+	h.a.Equal(4, r.Size())
+
+	h.a.Equal("name<String>, metric<String>, amount<Number>", r.Columns.String())
+
+	checkRows(h, r,
+		"u3 j1, total, 11",
+		"u3 j3, cost, 19",
+		"u3 j3, time_spent, 99",
+		"u3 j3, total, 118")
+}
@@ -0,0 +1,163 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/secure"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) repoMakeApigwSecret(handle, value string, c *secure.Cipher) *types.ApigwSecret {
+	enc, err := c.Encrypt(value)
+	h.noError(err)
+
+	var res = &types.ApigwSecret{
+		ID:        id.Next(),
+		Handle:    handle,
+		Value:     enc,
+		CreatedAt: time.Now(),
+	}
+
+	h.noError(store.CreateApigwSecret(context.Background(), service.DefaultStore, res))
+	return res
+}
+
+func (h helper) lookupApigwSecretValue(handle string) string {
+	res, err := store.LookupApigwSecretByHandle(context.Background(), service.DefaultStore, handle)
+	h.noError(err)
+
+	c, err := secure.NewCipher(testApp.Opt.Apigw.SecretKey)
+	h.noError(err)
+
+	value, err := c.Decrypt(res.Value)
+	h.noError(err)
+
+	return value
+}
+
+func TestApigwSecretCreateForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	h.apiInit().
+		Post("/apigw/secret/").
+		FormData("handle", "token").
+		FormData("value", "s3cr3t").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("apigwSecret.errors.notAllowedToManage")).
+		End()
+}
+
+func TestApigwSecretCreate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	h.apiInit().
+		Post("/apigw/secret/").
+		FormData("handle", "token").
+		FormData("value", "s3cr3t").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.handle`, "token")).
+		Assert(jsonpath.Equal(`$.response.reference`, "secret://token")).
+		Assert(jsonpath.NotPresent(`$.response.value`)).
+		End()
+
+	s, err := store.LookupApigwSecretByHandle(context.Background(), service.DefaultStore, "token")
+	h.noError(err)
+	h.a.NotEqual("s3cr3t", s.Value)
+	h.a.Equal("s3cr3t", h.lookupApigwSecretValue("token"))
+}
+
+func TestApigwSecretCreateDuplicate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	c, err := secure.NewCipher(testApp.Opt.Apigw.SecretKey)
+	h.noError(err)
+	h.repoMakeApigwSecret("token", "s3cr3t", c)
+
+	h.apiInit().
+		Post("/apigw/secret/").
+		FormData("handle", "token").
+		FormData("value", "other").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("apigwSecret.errors.handleNotUnique")).
+		End()
+}
+
+func TestApigwSecretUpdate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	c, err := secure.NewCipher(testApp.Opt.Apigw.SecretKey)
+	h.noError(err)
+	s := h.repoMakeApigwSecret("token", "s3cr3t", c)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/apigw/secret/%d", s.ID)).
+		FormData("handle", "token").
+		FormData("value", "rotated").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("rotated", h.lookupApigwSecretValue("token"))
+
+	// value is kept when not set
+	h.apiInit().
+		Post(fmt.Sprintf("/apigw/secret/%d", s.ID)).
+		FormData("handle", "token").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal("rotated", h.lookupApigwSecretValue("token"))
+}
+
+func TestApigwSecretRotate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	c, err := secure.NewCipher(testApp.Opt.Apigw.SecretKey)
+	h.noError(err)
+	h.repoMakeApigwSecret("current", "s3cr3t", c)
+
+	h.apiInit().
+		Post("/apigw/secret/rotate").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.rotated`, float64(0))).
+		End()
+
+	h.a.Equal("s3cr3t", h.lookupApigwSecretValue("current"))
+}
@@ -168,3 +168,42 @@ func TestAuthClientUnDelete(t *testing.T) {
 	h.a.NotNil(res)
 	h.a.Nil(res.DeletedAt)
 }
+
+func TestAuthClientCreateClientCredentials(t *testing.T) {
+	h := newHelper(t)
+	h.clearAuthClients()
+
+	var (
+		user = h.createUserWithEmail(h.randEmail())
+		bot  = h.createUser(&types.User{Email: h.randEmail(), Kind: types.BotUser})
+
+		payload = func(userID uint64) string {
+			return helpers.JSON(&types.AuthClient{
+				Handle:     rs(),
+				ValidGrant: "client_credentials",
+				Scope:      "api:compose",
+				Security:   &types.AuthClientSecurity{ImpersonateUser: userID},
+			})
+		}
+	)
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "auth-client.create")
+
+	h.apiInit().
+		Post("/auth/clients/").
+		Header("Accept", "application/json").
+		JSON(payload(user.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("authClient.errors.invalidImpersonatedUser")).
+		End()
+
+	h.apiInit().
+		Post("/auth/clients/").
+		Header("Accept", "application/json").
+		JSON(payload(bot.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
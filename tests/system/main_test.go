@@ -98,7 +98,7 @@ func InitTestApp() {
 	sp, _ := loadSAMLService(context.Background())
 
 	hh = &handlers.AuthHandlers{
-		SamlSPService:  *sp,
+		SamlSPServices: map[string]*saml.SamlSPService{"": sp},
 		Log:            zap.NewNop(),
 		AuthService:    service.DefaultAuth,
 		SessionManager: sm,
@@ -198,6 +198,10 @@ func (h helper) clearTemplates() {
 	h.noError(store.TruncateTemplates(context.Background(), service.DefaultStore))
 }
 
+func (h helper) clearApigwSecrets() {
+	h.noError(store.TruncateApigwSecrets(context.Background(), service.DefaultStore))
+}
+
 func readStaticFile(f string) []byte {
 	c, _ := mockData.ReadFile(f)
 	return c
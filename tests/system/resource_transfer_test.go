@@ -0,0 +1,411 @@
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	composeTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	sqlTypes "github.com/jmoiron/sqlx/types"
+	"github.com/steinfletcher/apitest"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) makeResourceArchive(ff map[string]string) []byte {
+	var (
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+
+	for name, content := range ff {
+		f, err := zw.Create(name)
+		h.noError(err)
+		_, err = f.Write([]byte(content))
+		h.noError(err)
+	}
+
+	h.noError(zw.Close())
+	return buf.Bytes()
+}
+
+func (h helper) apiResourceImport(archive []byte, ff map[string]string) *apitest.Response {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("upload", "resources.zip")
+	h.noError(err)
+
+	_, err = part.Write(archive)
+	h.noError(err)
+
+	for k, v := range ff {
+		h.noError(writer.WriteField(k, v))
+	}
+
+	h.noError(writer.Close())
+
+	return h.apiInit().
+		Post("/resources/import").
+		Header("Accept", "application/json").
+		Body(body.String()).
+		ContentType(writer.FormDataContentType()).
+		Expect(h.t).
+		Status(http.StatusOK)
+}
+
+// resourceTransferIdentifiers returns identifiers of a single report item as matched by jsonpath filter
+func (h helper) resourceTransferIdentifiers(ii ...string) []interface{} {
+	out := make([]interface{}, len(ii))
+	for i := range ii {
+		out[i] = ii[i]
+	}
+
+	return []interface{}{out}
+}
+
+func (h helper) lookupRoleByHandle(handle string) *types.Role {
+	res, err := store.LookupRoleByHandle(context.Background(), service.DefaultStore, handle)
+	if err == store.ErrNotFound {
+		return nil
+	}
+
+	h.noError(err)
+	return res
+}
+
+func TestResourceTransferExportForbidden(t *testing.T) {
+	h := newHelper(t)
+
+	h.apiInit().
+		Get("/resources/export").
+		Query("resource", "system:role").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("resourceTransfer.errors.notAllowedToExport")).
+		End()
+}
+
+func TestResourceTransferExport(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.export")
+	helpers.AllowMe(h, types.RoleRbacResource(0), "read")
+
+	role := h.repoMakeRole("Exported role", "exported_role_"+rs())
+
+	rsp := h.apiInit().
+		Get("/resources/export").
+		Query("resource", "system:role:"+role.Handle).
+		Query("filename", "roles").
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/zip").
+		Header("Content-Disposition", "attachment; filename=roles.zip").
+		End()
+
+	archive, err := ioutil.ReadAll(rsp.Response.Body)
+	h.noError(err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	h.noError(err)
+	h.a.Len(zr.File, 1)
+
+	f, err := zr.File[0].Open()
+	h.noError(err)
+	defer f.Close()
+
+	content, err := ioutil.ReadAll(f)
+	h.noError(err)
+	h.a.Contains(string(content), role.Handle)
+	h.a.Contains(string(content), "Exported role")
+}
+
+func TestResourceTransferExportResourceForbidden(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.export")
+
+	role := h.repoMakeRole("Exported role", "exported_role_"+rs())
+
+	h.apiInit().
+		Get("/resources/export").
+		Query("resource", "system:role:"+role.Handle).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("resourceTransfer.errors.notAllowedToExportResource")).
+		End()
+}
+
+func TestResourceTransferExportSettings(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.export", "settings.read")
+
+	ss := types.SettingValueSet{
+		&types.SettingValue{Name: "t_transfer.public", Value: sqlTypes.JSONText(`"public value"`)},
+		&types.SettingValue{Name: "t_transfer.client-secret", Value: sqlTypes.JSONText(`"visible value"`)},
+		&types.SettingValue{Name: "auth.external.providers.t_transfer.secret", Value: sqlTypes.JSONText(`"secret value"`)},
+		&types.SettingValue{Name: "auth.external.saml.key", Value: sqlTypes.JSONText(`"key value"`)},
+		&types.SettingValue{Name: "auth.ldap.bind-password", Value: sqlTypes.JSONText(`"password value"`)},
+	}
+
+	h.noError(store.UpsertSetting(context.Background(), service.DefaultStore, ss...))
+	defer store.DeleteSetting(context.Background(), service.DefaultStore, ss...)
+
+	rsp := h.apiInit().
+		Get("/resources/export").
+		Query("resource", "system:setting").
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/zip").
+		End()
+
+	archive, err := ioutil.ReadAll(rsp.Response.Body)
+	h.noError(err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	h.noError(err)
+
+	var content []byte
+	for _, zf := range zr.File {
+		f, err := zf.Open()
+		h.noError(err)
+
+		buf, err := ioutil.ReadAll(f)
+		h.noError(err)
+		h.noError(f.Close())
+		content = append(content, buf...)
+	}
+
+	h.a.Contains(string(content), "public value")
+	h.a.Contains(string(content), "visible value")
+	h.a.NotContains(string(content), "secret value")
+	h.a.NotContains(string(content), "key value")
+	h.a.NotContains(string(content), "password value")
+}
+
+func TestResourceTransferExportNoResources(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.export")
+
+	h.apiInit().
+		Get("/resources/export").
+		Query("resource", "system:role:non_existing_role_"+rs()).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("resourceTransfer.errors.noResources")).
+		End()
+}
+
+func TestResourceTransferImportForbidden(t *testing.T) {
+	h := newHelper(t)
+
+	archive := h.makeResourceArchive(map[string]string{
+		"roles.yaml": "roles:\n  forbidden_role:\n    name: Forbidden\n",
+	})
+
+	h.apiResourceImport(archive, nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.notAllowedToImport")).
+		End()
+
+	h.a.Nil(h.lookupRoleByHandle("forbidden_role"))
+}
+
+func TestResourceTransferImportInvalidArchive(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import")
+
+	h.apiResourceImport([]byte("not a zip archive"), nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.invalidArchive")).
+		End()
+}
+
+func TestResourceTransferImportArchiveTooLarge(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import")
+
+	ff := make(map[string]string)
+	for i := 0; i <= 256; i++ {
+		ff[fmt.Sprintf("roles_%d.yaml", i)] = "roles:\n  some_role:\n    name: Some role\n"
+	}
+
+	h.apiResourceImport(h.makeResourceArchive(ff), nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.archiveTooLarge")).
+		End()
+
+	// highly compressible content that is too large when decompressed
+	ff = map[string]string{"roles.yaml": strings.Repeat(" ", 129<<20)}
+
+	h.apiResourceImport(h.makeResourceArchive(ff), nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.archiveTooLarge")).
+		End()
+}
+
+func TestResourceTransferImportInvalidOnExisting(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import")
+
+	archive := h.makeResourceArchive(map[string]string{
+		"roles.yaml": "roles:\n  some_role:\n    name: Some role\n",
+	})
+
+	h.apiResourceImport(archive, map[string]string{"onExisting": "overwrite"}).
+		Assert(helpers.AssertError("resourceTransfer.errors.invalidOnExisting")).
+		End()
+}
+
+func TestResourceTransferImportResourceForbidden(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import")
+
+	archive := h.makeResourceArchive(map[string]string{
+		"roles.yaml": "roles:\n  forbidden_role:\n    name: Forbidden\n",
+	})
+
+	h.apiResourceImport(archive, nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.notAllowedToImportResource")).
+		End()
+
+	h.a.Nil(h.lookupRoleByHandle("forbidden_role"))
+}
+
+func TestResourceTransferImportRbacRules(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import", "role.create")
+
+	var (
+		handle = "escalated_role_" + rs()
+
+		archive = h.makeResourceArchive(map[string]string{
+			"roles.yaml": "roles:\n  " + handle + ":\n    name: Escalated role\n",
+			"rbac.yaml":  "allow:\n  " + handle + ":\n    corteza::system/:\n      - grant\n",
+		})
+	)
+
+	// RBAC rules can only be imported by those that can grant them
+	h.apiResourceImport(archive, nil).
+		Assert(helpers.AssertError("resourceTransfer.errors.notAllowedToImportResource")).
+		End()
+
+	h.a.Nil(h.lookupRoleByHandle(handle))
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "grant")
+
+	h.apiResourceImport(archive, nil).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.NotNil(h.lookupRoleByHandle(handle))
+}
+
+func TestResourceTransferImportPreview(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import", "role.create")
+	helpers.AllowMe(h, types.RoleRbacResource(0), "update")
+
+	var (
+		existing = h.repoMakeRole("Existing role", "existing_role_"+rs())
+		created  = "created_role_" + rs()
+
+		archive = h.makeResourceArchive(map[string]string{
+			"system/roles.yaml": "roles:\n" +
+				"  " + existing.Handle + ":\n    name: Renamed role\n" +
+				"  " + created + ":\n    name: Created role\n",
+		})
+	)
+
+	h.apiResourceImport(archive, map[string]string{"onExisting": "replace", "preview": "true"}).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.preview`, true)).
+		Assert(jsonpath.Equal(`$.response.onExisting`, "replace")).
+		Assert(jsonpath.Len(`$.response.items`, 2)).
+		Assert(jsonpath.Equal(`$.response.items[?(@.action == "update")].identifiers`, h.resourceTransferIdentifiers("Renamed role", existing.Handle))).
+		Assert(jsonpath.Equal(`$.response.items[?(@.action == "update")].changes`, []interface{}{[]interface{}{"name"}})).
+		Assert(jsonpath.Equal(`$.response.items[?(@.action == "create")].identifiers`, h.resourceTransferIdentifiers("Created role", created))).
+		Assert(jsonpath.NotPresent(`$.response.items[?(@.action == "create")].changes`)).
+		End()
+
+	// nothing is changed on preview
+	h.a.Nil(h.lookupRoleByHandle(created))
+	h.a.Equal("Existing role", h.lookupRoleByHandle(existing.Handle).Name)
+}
+
+func TestResourceTransferImport(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import", "role.create")
+	helpers.AllowMe(h, types.RoleRbacResource(0), "update")
+
+	var (
+		existing = h.repoMakeRole("Existing role", "existing_role_"+rs())
+		created  = "created_role_" + rs()
+
+		archive = h.makeResourceArchive(map[string]string{
+			"roles.yaml": "roles:\n" +
+				"  " + existing.Handle + ":\n    name: Renamed role\n" +
+				"  " + created + ":\n    name: Created role\n",
+		})
+	)
+
+	// existing resources are skipped by default
+	h.apiResourceImport(archive, nil).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.preview`, false)).
+		Assert(jsonpath.Equal(`$.response.items[?(@.action == "skip")].identifiers`, h.resourceTransferIdentifiers("Renamed role", existing.Handle))).
+		Assert(jsonpath.Equal(`$.response.items[?(@.action == "create")].identifiers`, h.resourceTransferIdentifiers("Created role", created))).
+		End()
+
+	h.a.NotNil(h.lookupRoleByHandle(created))
+	h.a.Equal("Created role", h.lookupRoleByHandle(created).Name)
+	h.a.Equal("Existing role", h.lookupRoleByHandle(existing.Handle).Name)
+
+	// both roles exist now
+	h.apiResourceImport(archive, map[string]string{"onExisting": "replace"}).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.items[?(@.action == "update")]`, 2)).
+		End()
+
+	h.a.Equal("Renamed role", h.lookupRoleByHandle(existing.Handle).Name)
+}
+
+func TestResourceTransferImportPreviewCompose(t *testing.T) {
+	h := newHelper(t)
+	helpers.AllowMe(h, types.ComponentRbacResource(), "resources.import")
+	helpers.AllowMe(h, composeTypes.NamespaceRbacResource(0), "update", "module.create")
+
+	var (
+		ns = &composeTypes.Namespace{
+			ID:        id.Next(),
+			Slug:      "transfer_ns_" + rs(),
+			Name:      "Transfer",
+			Enabled:   true,
+			CreatedAt: time.Now(),
+		}
+	)
+
+	h.noError(store.CreateComposeNamespace(context.Background(), service.DefaultStore, ns))
+
+	archive := h.makeResourceArchive(map[string]string{
+		"namespace.yaml": "namespaces:\n  " + ns.Slug + ":\n    name: Transfer\n",
+		"modules.yaml": "namespace: " + ns.Slug + "\n" +
+			"modules:\n  transfer_module:\n    name: Transfer module\n" +
+			"    fields:\n      f1:\n        kind: String\n",
+	})
+
+	h.apiResourceImport(archive, map[string]string{"preview": "true"}).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.items`, 2)).
+		Assert(jsonpath.Equal(`$.response.items[?(@.resourceType == "corteza::compose:namespace")].action`, []interface{}{"skip"})).
+		Assert(jsonpath.Equal(`$.response.items[?(@.resourceType == "corteza::compose:module")].action`, []interface{}{"create"})).
+		End()
+}
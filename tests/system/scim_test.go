@@ -383,3 +383,143 @@ func scimSetWithExternalId(c *scim.Config) {
 func scimSetWithUUIDValidator(c *scim.Config) {
 	c.ExternalIdValidator = regexp.MustCompile(`^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$`)
 }
+
+func TestScimUserList(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	h.createUserWithEmail("foo@scim.test")
+	h.createUserWithEmail("bar@scim.test")
+	h.createUserWithEmail("baz@scim.test")
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `emails.value ew "@scim.test"`).
+		Query("startIndex", "2").
+		Query("count", "1").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Contains(`$.schemas`, "urn:ietf:params:scim:api:messages:2.0:ListResponse")).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(3))).
+		Assert(jsonpath.Equal(`$.startIndex`, float64(2))).
+		Assert(jsonpath.Len(`$.Resources`, 1)).
+		End()
+
+	// without filter, users are paged and counted in the store
+	h.scimApiInit().
+		Get("/Users").
+		Query("startIndex", "1").
+		Query("count", "1").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(3))).
+		Assert(jsonpath.Len(`$.Resources`, 1)).
+		End()
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `emails eq "bar@scim.test"`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].emails[0].value`, "bar@scim.test")).
+		End()
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `emails eq`).
+		Expect(t).
+		Status(http.StatusBadRequest).
+		Assert(jsonpath.Equal(`$.scimType`, "invalidFilter")).
+		End()
+}
+
+func TestScimUserPatch(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	u := h.createUserWithEmail(h.randEmail())
+
+	h.scimApiInit().
+		Patch(fmt.Sprintf("/Users/%d", u.ID)).
+		JSON(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:PatchOp"],"Operations":[
+			{"op":"Replace","path":"active","value":false},
+			{"op":"replace","value":{"userName":"patched","name":{"formatted":"Patched User"}}},
+			{"op":"replace","path":"emails[type eq \"work\"].value","value":"patched@scim.test"}
+		]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.active`, false)).
+		Assert(jsonpath.Equal(`$.userName`, "patched")).
+		End()
+
+	u, err := store.LookupUserByID(context.Background(), service.DefaultStore, u.ID)
+	h.a.NoError(err)
+	h.a.Equal("patched", u.Username)
+	h.a.Equal("Patched User", u.Name)
+	h.a.Equal("patched@scim.test", u.Email)
+	h.a.NotNil(u.SuspendedAt)
+
+	h.scimApiInit().
+		Patch(fmt.Sprintf("/Users/%d", u.ID)).
+		JSON(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:PatchOp"],"Operations":[{"op":"replace","path":"active","value":true}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.active`, true)).
+		End()
+
+	u, err = store.LookupUserByID(context.Background(), service.DefaultStore, u.ID)
+	h.a.NoError(err)
+	h.a.Nil(u.SuspendedAt)
+}
+
+func TestScimDiscovery(t *testing.T) {
+	h := newHelper(t)
+
+	h.scimApiInit().
+		Get("/ServiceProviderConfig").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.patch.supported`, true)).
+		Assert(jsonpath.Equal(`$.bulk.supported`, true)).
+		Assert(jsonpath.Equal(`$.filter.supported`, true)).
+		End()
+
+	h.scimApiInit().
+		Get("/ResourceTypes").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(2))).
+		End()
+
+	h.scimApiInit().
+		Get("/Schemas/urn:ietf:params:scim:schemas:core:2.0:User").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.name`, "User")).
+		End()
+}
+
+func TestScimBulk(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	h.scimApiInit().
+		Post("/Bulk").
+		JSON(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:BulkRequest"],"Operations":[
+			{"method":"POST","path":"/Users","bulkId":"u1","data":{"userName":"bulk","emails":[{"value":"bulk@scim.test"}]}},
+			{"method":"PATCH","path":"/Users/bulkId:u1","data":{"Operations":[{"op":"replace","path":"nickName","value":"bulky"}]}},
+			{"method":"DELETE","path":"/Users/bulkId:unknown"}
+		]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Contains(`$.schemas`, "urn:ietf:params:scim:api:messages:2.0:BulkResponse")).
+		Assert(jsonpath.Equal(`$.Operations[0].status`, "201")).
+		Assert(jsonpath.Equal(`$.Operations[1].status`, "200")).
+		Assert(jsonpath.Equal(`$.Operations[2].status`, "409")).
+		End()
+
+	u, err := store.LookupUserByEmail(context.Background(), service.DefaultStore, "bulk@scim.test")
+	h.a.NoError(err)
+	h.a.Equal("bulky", u.Handle)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service"
@@ -627,3 +628,66 @@ func TestUserMemberRemove(t *testing.T) {
 		Assert(helpers.AssertNoErrors).
 		End()
 }
+
+func TestUserPersonalAccessTokens(t *testing.T) {
+	h := newHelper(t)
+
+	defer func(s types.AppSettings) { *service.CurrentSettings = s }(*service.CurrentSettings)
+	service.CurrentSettings.Auth.PersonalAccessTokens.Enabled = true
+
+	var (
+		u   = h.createUserWithEmail(h.randEmail())
+		ctx = auth.SetIdentityToContext(context.Background(), u)
+
+		tokensPath = fmt.Sprintf("/users/%d/personal-access-tokens", u.ID)
+	)
+
+	token, pat, err := service.DefaultAuth.CreatePersonalAccessToken(ctx, "test", []string{"api:system"}, nil)
+	h.noError(err)
+
+	composeToken, _, err := service.DefaultAuth.CreatePersonalAccessToken(ctx, "compose", []string{"api:compose"}, nil)
+	h.noError(err)
+
+	// owner can list own tokens using the personal access token
+	h.apiInit().
+		Intercept(helpers.ReqHeaderRawAuthBearer(token)).
+		Get(tokensPath).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response`, 2)).
+		End()
+
+	// token can not be used outside of its scope
+	h.apiInit().
+		Intercept(helpers.ReqHeaderRawAuthBearer(composeToken)).
+		Get(tokensPath).
+		Expect(t).
+		Status(http.StatusUnauthorized).
+		End()
+
+	h.apiInit().
+		Get(tokensPath).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("auth.errors.notAllowedToManagePersonalAccessTokens")).
+		End()
+
+	helpers.AllowMe(h, types.UserRbacResource(0), "update")
+
+	h.apiInit().
+		Delete(fmt.Sprintf("%s/%d", tokensPath, pat.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// revoked token is no longer accepted
+	h.apiInit().
+		Intercept(helpers.ReqHeaderRawAuthBearer(token)).
+		Get(tokensPath).
+		Expect(t).
+		Status(http.StatusUnauthorized).
+		End()
+}